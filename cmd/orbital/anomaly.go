@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// newCostMonitor builds the cost anomaly monitor from [cost_anomaly].
+func newCostMonitor(fileConfig *config.FileConfig) (*loop.CostMonitor, error) {
+	var c config.CostAnomalyConfig
+	if fileConfig != nil && fileConfig.CostAnomaly != nil {
+		c = *fileConfig.CostAnomaly
+	}
+	monitor, err := loop.NewCostMonitor(c.Factor, c.Window, c.Repeat, c.Action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cost_anomaly config: %w", err)
+	}
+	return monitor, nil
+}
+
+// reportCostAnomaly warns about an anomalous iteration in a TUI banner or on
+// stdout and sends a notification. When the anomaly pauses the run it asks
+// whether to continue and returns loop.ErrCostAnomaly if the answer is no.
+func reportCostAnomaly(ctx context.Context, a loop.CostAnomaly, notifier *notify.Notifier, tuiProgram *tui.Program, keys *keyControls) error {
+	msg := "Cost anomaly: " + a.String()
+	if tuiProgram != nil {
+		tuiProgram.SendBanner(msg)
+		tuiProgram.SendOutput("⚠ " + msg)
+	} else {
+		fmt.Printf("\n⚠ %s\n", msg)
+	}
+	if err := notifier.Notify(notify.EventCostAnomaly, "Orbital cost anomaly", a.String()); err != nil {
+		reportNotifyError(err, tuiProgram)
+	}
+
+	if !a.Pause || confirmCostAnomaly(ctx, a, tuiProgram, keys) {
+		return nil
+	}
+	return loop.ErrCostAnomaly
+}
+
+// confirmCostAnomaly asks whether to continue after repeated anomalies: in
+// the TUI, or on the terminal otherwise. With --yes it continues without
+// asking; without a terminal nobody can confirm, so it stops.
+func confirmCostAnomaly(ctx context.Context, a loop.CostAnomaly, tuiProgram *tui.Program, keys *keyControls) bool {
+	question := fmt.Sprintf("%d costly iterations in a row. Continue?", a.Repeats)
+	switch {
+	case assumeYes:
+		return true
+	case tuiProgram != nil:
+		answer, err := tuiProgram.Confirm(ctx, question)
+		return err == nil && answer
+	case keys != nil:
+		return keys.Confirm(ctx, question)
+	case !stdinInteractive():
+		fmt.Fprintln(os.Stderr, "Stopping after repeated cost anomalies: no terminal to confirm (pass --yes to continue)")
+		return false
+	default:
+		return confirmPrompt(os.Stdin, os.Stderr, question)
+	}
+}
+
+// confirmPrompt asks question on out and reports whether the answer read
+// from in is yes.
+func confirmPrompt(in io.Reader, out io.Writer, question string) bool {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+)
+
+func TestNewCostMonitor_FromConfig(t *testing.T) {
+	if _, err := newCostMonitor(nil); err != nil {
+		t.Errorf("newCostMonitor(nil) error = %v", err)
+	}
+
+	fileConfig := &config.FileConfig{CostAnomaly: &config.CostAnomalyConfig{Factor: 2, Window: 2, Action: loop.CostAnomalyPause, Repeat: 1}}
+	monitor, err := newCostMonitor(fileConfig)
+	if err != nil {
+		t.Fatalf("newCostMonitor() error = %v", err)
+	}
+	monitor.Observe(1, 1)
+	monitor.Observe(2, 1)
+	if a := monitor.Observe(3, 2.5); a == nil || !a.Pause {
+		t.Errorf("Observe() = %+v, want a pause with factor 2 and repeat 1", a)
+	}
+
+	fileConfig.CostAnomaly.Action = "bogus"
+	if _, err := newCostMonitor(fileConfig); err == nil || !strings.Contains(err.Error(), "invalid cost_anomaly config") {
+		t.Errorf("newCostMonitor(bogus) error = %v, want invalid cost_anomaly config", err)
+	}
+}
+
+func TestConfirmPrompt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirmPrompt(strings.NewReader(tt.input), &out, "Continue?"); got != tt.want {
+			t.Errorf("confirmPrompt(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if out.String() != "Continue? [y/N]: " {
+			t.Errorf("prompt = %q", out.String())
+		}
+	}
+}
+
+func TestReportCostAnomaly(t *testing.T) {
+	oldYes, oldNonInteractive := assumeYes, nonInteractive
+	defer func() { assumeYes, nonInteractive = oldYes, oldNonInteractive }()
+	nonInteractive = true
+
+	warn := loop.CostAnomaly{Iteration: 3, Cost: 9, Baseline: 1, Repeats: 1}
+	if err := reportCostAnomaly(context.Background(), warn, nil, nil, nil); err != nil {
+		t.Errorf("warning anomaly error = %v, want nil", err)
+	}
+
+	pause := warn
+	pause.Pause = true
+	if err := reportCostAnomaly(context.Background(), pause, nil, nil, nil); !errors.Is(err, loop.ErrCostAnomaly) {
+		t.Errorf("pause without a terminal error = %v, want ErrCostAnomaly", err)
+	}
+
+	assumeYes = true
+	if err := reportCostAnomaly(context.Background(), pause, nil, nil, nil); err != nil {
+		t.Errorf("pause with --yes error = %v, want nil", err)
+	}
+}
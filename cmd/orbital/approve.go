@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/approval"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var approveCmd = newApproveCmd()
+
+func newApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve <session> <step>",
+		Short: "Approve or reject a human gate a session is waiting at",
+		Long: `A workflow step with gate_type = "human" pauses the session until
+someone decides on it. Approving passes the gate; rejecting fails it, so the
+workflow returns to the gate's on_fail step.
+
+Without arguments, lists the gates sessions are waiting at.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 && len(args) != 2 {
+				return errors.New("requires a session and a step, or no arguments to list waiting gates")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(workingDir)
+			if err != nil {
+				return fmt.Errorf("failed to resolve working directory: %w", err)
+			}
+			if len(args) == 0 {
+				return printWaitingGates(cmd.OutOrStdout(), dir, time.Now())
+			}
+			reject, _ := cmd.Flags().GetBool("reject")
+			comment, _ := cmd.Flags().GetString("comment")
+			return decideGate(cmd.OutOrStdout(), dir, args[0], args[1], approval.Decision{
+				Approved: !reject,
+				By:       os.Getenv("USER"),
+				Comment:  comment,
+			})
+		},
+	}
+	cmd.Flags().Bool("reject", false, "Reject the gate instead of approving it")
+	cmd.Flags().StringP("comment", "m", "", "Reason for the decision, shown in the session")
+	return cmd
+}
+
+// decideGate records d for the gate session is waiting at and confirms it.
+func decideGate(out io.Writer, dir, session, step string, d approval.Decision) error {
+	if err := approval.Decide(dir, session, step, d); err != nil {
+		if errors.Is(err, approval.ErrNotWaiting) {
+			return fmt.Errorf("%w; run 'orbital approve' to list waiting gates", err)
+		}
+		return err
+	}
+	verb := "Approved"
+	if !d.Approved {
+		verb = "Rejected"
+	}
+	_, _ = fmt.Fprintf(out, "%s %q for session %s\n", verb, step, session)
+	return nil
+}
+
+// printWaitingGates lists the gates sessions are waiting at, with how long.
+func printWaitingGates(out io.Writer, dir string, now time.Time) error {
+	gates, err := approval.Waiting(dir)
+	if err != nil {
+		return err
+	}
+	if len(gates) == 0 {
+		_, _ = fmt.Fprintln(out, "No gates waiting for approval")
+		return nil
+	}
+	for _, g := range gates {
+		_, _ = fmt.Fprintf(out, "%s  %s  waiting %s\n", g.Session, g.Step, formatDuration(now.Sub(g.Since)))
+	}
+	return nil
+}
+
+// newApprover returns the approver human gates of session wait on. It
+// announces the gate and notifies, then waits for `orbital approve` or, in
+// the TUI, a y/n answer.
+func newApprover(dir, session string, notifier *notify.Notifier, tuiProgram *tui.Program) workflow.ApproveFunc {
+	return func(ctx context.Context, step workflow.Step) (workflow.Approval, error) {
+		if err := approval.Request(dir, session, step.Name); err != nil {
+			return workflow.Approval{}, err
+		}
+		msg := fmt.Sprintf("Gate %q is waiting for approval: run 'orbital approve %s %s' (--reject to reject)", step.Name, session, step.Name)
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("✋ " + msg)
+		} else {
+			fmt.Printf("\n✋ %s\n", msg)
+		}
+		if step.Prompt != "" {
+			showApprovalPrompt(step.Prompt, tuiProgram)
+		}
+		if err := notifier.Notify(notify.EventApproval, "Orbital approval needed", msg); err != nil {
+			reportNotifyError(err, tuiProgram)
+		}
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		if tuiProgram != nil {
+			// A key press is recorded like any other decision
+			go func() {
+				answer, err := tuiProgram.Ask(waitCtx, tui.ConfirmMsg{
+					Question: fmt.Sprintf("Approve %q?", step.Name),
+					Yes:      "approve",
+					No:       "reject",
+				})
+				if err == nil {
+					_ = approval.Decide(dir, session, step.Name, approval.Decision{Approved: answer, By: "tui"})
+				}
+			}()
+		}
+
+		d, err := approval.Wait(waitCtx, dir, session, step.Name)
+		if err != nil {
+			return workflow.Approval{}, err
+		}
+		reportDecision(step.Name, d, tuiProgram)
+		return workflow.Approval{Approved: d.Approved, By: d.By, Comment: d.Comment}, nil
+	}
+}
+
+// showApprovalPrompt shows a human gate's prompt, which tells the approver
+// what to check.
+func showApprovalPrompt(prompt string, tuiProgram *tui.Program) {
+	if tuiProgram != nil {
+		tuiProgram.SendOutput(prompt)
+	} else {
+		fmt.Println(prompt)
+	}
+}
+
+// reportDecision shows who decided on a human gate and why.
+func reportDecision(step string, d *approval.Decision, tuiProgram *tui.Program) {
+	msg := fmt.Sprintf("Gate %q approved", step)
+	if !d.Approved {
+		msg = fmt.Sprintf("Gate %q rejected", step)
+	}
+	if d.By != "" {
+		msg += " by " + d.By
+	}
+	if d.Comment != "" {
+		msg += ": " + d.Comment
+	}
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("✋ " + msg)
+	} else {
+		fmt.Printf("✋ %s\n", msg)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/approval"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+func TestNewApprover_WaitsForDecision(t *testing.T) {
+	dir := t.TempDir()
+	approve := newApprover(dir, "abc", nil, nil)
+
+	// Reject the gate once the session is waiting at it
+	decided := make(chan error, 1)
+	go func() {
+		for {
+			gates, err := approval.Waiting(dir)
+			if err != nil {
+				decided <- err
+				return
+			}
+			if len(gates) == 1 {
+				var out strings.Builder
+				decided <- decideGate(&out, dir, "abc", "sign-off", approval.Decision{By: "sam", Comment: "Not on a Friday"})
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	got, err := approve(ctx, workflow.Step{Name: "sign-off", Gate: true, GateType: workflow.GateTypeHuman})
+	if err != nil {
+		t.Fatalf("approve() error = %v", err)
+	}
+	if err := <-decided; err != nil {
+		t.Fatalf("decideGate() error = %v", err)
+	}
+	want := workflow.Approval{Approved: false, By: "sam", Comment: "Not on a Friday"}
+	if got != want {
+		t.Errorf("approve() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecideGate_NotWaiting(t *testing.T) {
+	var out strings.Builder
+	err := decideGate(&out, t.TempDir(), "abc", "sign-off", approval.Decision{Approved: true})
+	if !errors.Is(err, approval.ErrNotWaiting) || !strings.Contains(err.Error(), "orbital approve") {
+		t.Errorf("decideGate() error = %v, want ErrNotWaiting with a hint", err)
+	}
+}
+
+func TestPrintWaitingGates(t *testing.T) {
+	dir := t.TempDir()
+	var out strings.Builder
+	if err := printWaitingGates(&out, dir, time.Now()); err != nil || out.String() != "No gates waiting for approval\n" {
+		t.Errorf("printWaitingGates() = %q, %v", out.String(), err)
+	}
+
+	if err := approval.Request(dir, "abc", "sign-off"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := printWaitingGates(&out, dir, time.Now().Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "abc  sign-off  waiting 5m") {
+		t.Errorf("printWaitingGates() = %q", out.String())
+	}
+}
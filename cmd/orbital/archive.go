@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// resolveArchive applies the [archive] config section and then the
+// --archive-keep flag, if given, to cfg.
+func resolveArchive(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.Archive != nil && fileConfig.Archive.Keep != nil {
+		cfg.ArchiveKeep = *fileConfig.Archive.Keep
+	}
+	if cmd.Flags().Changed("archive-keep") {
+		cfg.ArchiveKeep = archiveKeep
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
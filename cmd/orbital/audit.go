@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/audit"
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = newAuditCmd()
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit [session-id]",
+		Short: "List the shell commands the agent ran in a session",
+		Long: `List every shell command the agent ran through its Bash tool in a
+session, with when it started, how long it took and how it exited. Without a
+session ID the most recent session is shown; a unique prefix of the ID is
+accepted.
+
+Commands whose result never arrived, for example because the session was
+stopped while they ran, are listed with status unknown. Trails written with
+[encryption] enabled are only printed with --decrypt.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRunFileIDs(".audit.jsonl"),
+		RunE:              runAudit,
+	}
+	cmd.Flags().Bool("decrypt", false, "Decrypt an encrypted audit trail")
+	cmd.Flags().Bool("json", false, "Print the commands as JSON")
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	decrypt, _ := cmd.Flags().GetBool("decrypt")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	if decrypt {
+		fileConfig, err := loadFileConfig(workingDir)
+		if err != nil {
+			return err
+		}
+		if err := setupEncryption(fileConfig); err != nil {
+			return err
+		}
+	}
+
+	ref := ""
+	if len(args) > 0 {
+		ref = args[0]
+	}
+	path, err := findRunFile(workingDir, ref, ".audit.jsonl", "audit trail")
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	if encrypt.IsEncrypted(data) && !decrypt {
+		return fmt.Errorf("%s is encrypted; rerun with --decrypt", path)
+	}
+
+	commands, err := audit.Read(path)
+	if err != nil && !errors.Is(err, encrypt.ErrTruncated) {
+		return err
+	}
+	warnTruncated(cmd.ErrOrStderr(), path, err)
+	out := cmd.OutOrStdout()
+	if asJSON {
+		if commands == nil {
+			commands = []audit.Command{}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(commands)
+	}
+	return printAudit(out, commands)
+}
+
+// printAudit writes one line per command: start time, status, exit code,
+// duration and the command itself. A header names the directory whenever it
+// changes.
+func printAudit(w io.Writer, commands []audit.Command) error {
+	if len(commands) == 0 {
+		_, err := fmt.Fprintln(w, "No shell commands recorded")
+		return err
+	}
+
+	cwd := ""
+	for i, c := range commands {
+		if c.Cwd != cwd || i == 0 {
+			cwd = c.Cwd
+			if cwd != "" {
+				if _, err := fmt.Fprintf(w, "── %s ──\n", cwd); err != nil {
+					return err
+				}
+			}
+		}
+
+		exit, took := "-", "-"
+		if c.ExitCode != nil {
+			exit = fmt.Sprintf("%d", *c.ExitCode)
+		}
+		if c.Status != audit.StatusUnknown {
+			took = formatDuration(c.Duration)
+		}
+		command := strings.ReplaceAll(strings.TrimSpace(c.Command), "\n", "\n"+strings.Repeat(" ", 43))
+		if c.Background {
+			command += "  (background)"
+		}
+		if _, err := fmt.Fprintf(w, "%s  %-7s  %4s  %5s  %s\n",
+			c.StartedAt.Local().Format("2006-01-02 15:04:05"), c.Status, exit, took, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/audit"
+	"github.com/flashingpumpkin/orbital/internal/runs"
+)
+
+// writeAuditTrail records a passing and a failing command for session id.
+func writeAuditTrail(t *testing.T, id string) {
+	t.Helper()
+	r, err := audit.Open(runs.AuditLogPath(".", id), "/work")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	stream := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make test"}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go vet ./..."}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"Exit code 1","is_error":true}]}}
+`
+	if _, err := r.Write([]byte(stream)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runAuditCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newAuditCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestAuditCmd_ListsCommands(t *testing.T) {
+	chdirTemp(t)
+	writeAuditTrail(t, "abc123")
+
+	out, err := runAuditCmd(t, "abc")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != "── /work ──" {
+		t.Fatalf("output =\n%s", out)
+	}
+	if !strings.Contains(lines[1], "ok          0     0s  make test") {
+		t.Errorf("line 1 = %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "failed      1     0s  go vet ./...") {
+		t.Errorf("line 2 = %q", lines[2])
+	}
+}
+
+func TestAuditCmd_JSON(t *testing.T) {
+	chdirTemp(t)
+	writeAuditTrail(t, "abc123")
+
+	out, err := runAuditCmd(t, "--json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var commands []audit.Command
+	if err := json.Unmarshal([]byte(out), &commands); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if len(commands) != 2 || commands[1].Status != audit.StatusFailed {
+		t.Errorf("commands = %+v", commands)
+	}
+}
+
+func TestAuditCmd_NoTrails(t *testing.T) {
+	chdirTemp(t)
+	if _, err := runAuditCmd(t); err == nil || !strings.Contains(err.Error(), "no audit trails recorded") {
+		t.Errorf("Execute() error = %v, want no audit trails", err)
+	}
+}
+
+func TestPrintAudit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printAudit(&buf, nil); err != nil || buf.String() != "No shell commands recorded\n" {
+		t.Errorf("printAudit(nil) = %q, %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	err := printAudit(&buf, []audit.Command{
+		{Command: "npm run dev", Background: true, StartedAt: started, Status: audit.StatusUnknown},
+		{Command: "cat <<EOF\nhi\nEOF", StartedAt: started, Status: audit.StatusFailed, Duration: 90 * time.Second},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2026-03-01 12:00:00  unknown     -      -  npm run dev  (background)\n" +
+		"2026-03-01 12:00:00  failed      -     1m  cat <<EOF\n" +
+		strings.Repeat(" ", 43) + "hi\n" +
+		strings.Repeat(" ", 43) + "EOF\n"
+	if buf.String() != want {
+		t.Errorf("printAudit() =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var blameCmd = newBlameCmd()
+
+func newBlameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "blame <commit>",
+		Short: "Show which run produced a commit",
+		Long: `Look up the orbital run that produced a commit.
+
+Every run records the git HEAD at its start and after each iteration in
+.orbital/runs/. A commit made during a run is traced back to the run's
+session, spec files, workflow, model, orbital version and the iteration it
+was made in. Commits made outside orbital, or by runs recorded elsewhere,
+are not found.`,
+		Example: `  orbital blame HEAD
+  orbital blame 3f2a9c1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadDisplay(workingDir); err != nil {
+				return err
+			}
+			return blameCommit(cmd.Context(), workingDir, args[0], cmd.OutOrStdout())
+		},
+	}
+}
+
+// blameCommit prints the run that produced the commit rev names.
+func blameCommit(ctx context.Context, dir, rev string, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !git.IsRepo(ctx, dir) {
+		return errors.New("orbital blame needs a git repository")
+	}
+	commit, err := git.ResolveCommit(ctx, dir, rev)
+	if err != nil {
+		return err
+	}
+	records, err := runs.List(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+	found, err := runs.Blame(records, commit, func(ancestor, commit string) (bool, error) {
+		return git.IsAncestor(ctx, dir, ancestor, commit)
+	})
+	if err != nil {
+		return err
+	}
+	if found == nil {
+		return fmt.Errorf("no recorded run produced commit %.12s", commit)
+	}
+
+	rec := found.Record
+	_, _ = fmt.Fprintf(out, "Commit:     %s\n", commit)
+	_, _ = fmt.Fprintf(out, "Session:    %s\n", rec.SessionID)
+	if len(rec.SpecFiles) > 0 {
+		_, _ = fmt.Fprintf(out, "Spec:       %s\n", strings.Join(rec.SpecFiles, ", "))
+	}
+	_, _ = fmt.Fprintf(out, "Iteration:  %d of %d\n", found.Iteration, rec.Iterations)
+	_, _ = fmt.Fprintf(out, "Workflow:   %s\n", rec.Workflow)
+	_, _ = fmt.Fprintf(out, "Model:      %s\n", rec.Model)
+	_, _ = fmt.Fprintf(out, "Started:    %s\n", rec.StartedAt.Format("2006-01-02 15:04:05"))
+	_, _ = fmt.Fprintf(out, "Cost:       %s %s (whole run)\n", util.FormatCost(rec.TotalCost, 2), util.CurrencyCode())
+	_, _ = fmt.Fprintf(out, "Outcome:    %s\n", rec.Outcome)
+	if rec.Version != "" {
+		_, _ = fmt.Fprintf(out, "Orbital:    %s\n", rec.Version)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+	"github.com/flashingpumpkin/orbital/internal/runs"
+)
+
+func TestBlameCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := t.TempDir()
+	commitFile := func(name, message string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(message+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", name}, {"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", message}} {
+			if _, err := git.Run(ctx, repo, args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+		head, err := git.HeadCommit(ctx, repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return head
+	}
+	if _, err := git.Run(ctx, repo, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := commitFile("a.txt", "Before the run")
+	parser := commitFile("parser.go", "Add the parser")
+	tests := commitFile("parser_test.go", "Test the parser")
+
+	rec := runs.NewRecord("3f2a9c1d", []string{"docs/plans/parser.md"}, "spec-driven", "opus")
+	rec.Version = "1.2.3"
+	rec.StartCommit = before
+	rec.Iterations = 2
+	rec.AddHead(1, parser)
+	rec.AddHead(2, tests)
+	if err := rec.Save(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := blameCommit(ctx, repo, parser[:8], &out); err != nil {
+		t.Fatalf("blameCommit() error = %v", err)
+	}
+	for _, want := range []string{"Commit:     " + parser, "Session:    3f2a9c1d", "Spec:       docs/plans/parser.md", "Iteration:  1 of 2", "Orbital:    1.2.3"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := blameCommit(ctx, repo, "HEAD", &out); err != nil || !strings.Contains(out.String(), "Iteration:  2 of 2") {
+		t.Errorf("blameCommit(HEAD) = %v:\n%s", err, out.String())
+	}
+
+	if err := blameCommit(ctx, repo, before, &out); err == nil || !strings.Contains(err.Error(), "no recorded run") {
+		t.Errorf("blameCommit(before the run) error = %v, want no recorded run", err)
+	}
+	if err := blameCommit(ctx, repo, "no-such-rev", &out); err == nil {
+		t.Error("blameCommit(no-such-rev) expected error")
+	}
+	if err := blameCommit(ctx, t.TempDir(), "HEAD", &out); err == nil {
+		t.Error("blameCommit() outside git expected error")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// defaultBootstrapTimeout bounds each bootstrap command when [bootstrap]
+// sets no timeout.
+const defaultBootstrapTimeout = 10 * time.Minute
+
+// bootstrap runs the [bootstrap] commands before the first iteration, so the
+// agent does not spend tokens discovering and running dependency installs.
+type bootstrap struct {
+	commands []string
+	dir      string
+	timeout  time.Duration
+}
+
+// newBootstrap returns the bootstrap for cfg's working directory from the
+// [bootstrap] config section, or nil when it lists no commands or in a dry
+// run.
+func newBootstrap(fileConfig *config.FileConfig, cfg *config.Config) *bootstrap {
+	if fileConfig == nil || fileConfig.Bootstrap == nil || cfg.DryRun {
+		return nil
+	}
+	var commands []string
+	for _, command := range fileConfig.Bootstrap.Commands {
+		if strings.TrimSpace(command) != "" {
+			commands = append(commands, command)
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	timeout := fileConfig.Bootstrap.Timeout.Duration()
+	if timeout <= 0 {
+		timeout = defaultBootstrapTimeout
+	}
+	return &bootstrap{commands: commands, dir: cfg.WorkingDir, timeout: timeout}
+}
+
+// Run runs the commands in order, showing each one's output, and stops at
+// the first that fails. A nil bootstrap runs nothing.
+func (b *bootstrap) Run(ctx context.Context, tuiProgram *tui.Program) error {
+	if b == nil {
+		return nil
+	}
+	show := func(line string) {
+		if tuiProgram != nil {
+			tuiProgram.SendOutput(line)
+		} else if !quiet {
+			fmt.Println(line)
+		}
+	}
+	for _, command := range b.commands {
+		show("⚙ Bootstrap: " + command)
+		start := time.Now()
+		last, err := b.run(ctx, command, func(line string) { show("  " + line) })
+		elapsed := time.Since(start).Round(time.Second)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			msg := fmt.Sprintf("bootstrap command `%s` failed after %s: %v", command, elapsed, err)
+			if last != "" {
+				msg += " (" + last + ")"
+			}
+			return errors.New(msg)
+		}
+		show(fmt.Sprintf("✓ Bootstrap: %s (%s)", command, elapsed))
+	}
+	return nil
+}
+
+// run runs command via sh -c, passing each line it writes to show, and
+// returns the last line.
+func (b *bootstrap) run(ctx context.Context, command string, show func(string)) (string, error) {
+	runCtx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	lines := &bootstrapOutput{show: show}
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = b.dir
+	cmd.Stdout = lines
+	cmd.Stderr = lines
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	lines.flush()
+
+	if err != nil && runCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		err = fmt.Errorf("timed out after %s", b.timeout)
+	}
+	return lines.last, err
+}
+
+// bootstrapOutput passes the lines written to it to show, without control
+// sequences. Of a line redrawn with carriage returns, such as a progress
+// bar, only the final state is shown.
+type bootstrapOutput struct {
+	show    func(string)
+	partial []byte
+	last    string
+}
+
+// Write implements io.Writer.
+func (w *bootstrapOutput) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.send(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush shows a final line without a newline.
+func (w *bootstrapOutput) flush() {
+	w.send(string(w.partial))
+	w.partial = nil
+}
+
+// send shows one line, skipping blank ones.
+func (w *bootstrapOutput) send(line string) {
+	line = strings.TrimRight(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.TrimRight(output.Sanitize(line, output.SanitizePlain), " \t")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.last = strings.TrimSpace(line)
+	w.show(line)
+}
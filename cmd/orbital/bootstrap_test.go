@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+func TestNewBootstrap(t *testing.T) {
+	cfg := &config.Config{WorkingDir: "/project"}
+	if b := newBootstrap(nil, cfg); b != nil {
+		t.Errorf("newBootstrap() without config = %+v, want nil", b)
+	}
+	blank := &config.FileConfig{Bootstrap: &config.BootstrapConfig{Commands: []string{" "}}}
+	if b := newBootstrap(blank, cfg); b != nil {
+		t.Errorf("newBootstrap() with blank commands = %+v, want nil", b)
+	}
+
+	fc := &config.FileConfig{Bootstrap: &config.BootstrapConfig{Commands: []string{"npm ci", "", "go mod download"}}}
+	b := newBootstrap(fc, cfg)
+	if b == nil || !reflect.DeepEqual(b.commands, []string{"npm ci", "go mod download"}) || b.dir != "/project" || b.timeout != defaultBootstrapTimeout {
+		t.Errorf("newBootstrap() = %+v, want both commands in /project with the default timeout", b)
+	}
+
+	fc.Bootstrap.Timeout = workflow.Duration(time.Minute)
+	if b := newBootstrap(fc, cfg); b.timeout != time.Minute {
+		t.Errorf("timeout = %s, want 1m", b.timeout)
+	}
+	if b := newBootstrap(fc, &config.Config{DryRun: true}); b != nil {
+		t.Errorf("newBootstrap() in a dry run = %+v, want nil", b)
+	}
+}
+
+func TestBootstrap_RunShowsOutput(t *testing.T) {
+	dir := t.TempDir()
+	b := &bootstrap{dir: dir, timeout: time.Minute}
+
+	var lines []string
+	last, err := b.run(context.Background(), `printf 'installing\n\n'; printf '10%%\r50%%\r100%%\n' >&2; printf '\033[32mdone\033[0m'; touch installed`, func(line string) {
+		lines = append(lines, line)
+	})
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if want := []string{"installing", "100%", "done"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if last != "done" {
+		t.Errorf("last = %q, want done", last)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "installed")); err != nil {
+		t.Errorf("command did not run in the working directory: %v", err)
+	}
+}
+
+func TestBootstrap_RunStopsAtFailure(t *testing.T) {
+	origQuiet := quiet
+	quiet = true
+	defer func() { quiet = origQuiet }()
+
+	dir := t.TempDir()
+	b := &bootstrap{
+		commands: []string{"echo 'npm ERR! missing lockfile'; exit 1", "touch second"},
+		dir:      dir,
+		timeout:  time.Minute,
+	}
+	err := b.Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") || !strings.Contains(err.Error(), "(npm ERR! missing lockfile)") {
+		t.Errorf("Run() error = %v, want the exit status and the last output line", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "second")); err == nil {
+		t.Error("Run() carried on after a failed command")
+	}
+
+	b = &bootstrap{commands: []string{"sleep 5"}, dir: dir, timeout: 50 * time.Millisecond}
+	if err := b.Run(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Errorf("Run() error = %v, want a timeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Run(ctx, nil); err != context.Canceled {
+		t.Errorf("Run() on an interrupted run = %v, want context.Canceled", err)
+	}
+}
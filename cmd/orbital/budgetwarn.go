@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// resolveBudgetWarnings returns the soft budget warning thresholds from
+// --budget-warn, or budget_warn in the config file when the flag is unset.
+func resolveBudgetWarnings(fileConfig *config.FileConfig) ([]float64, error) {
+	if budgetWarn != "" {
+		fractions, err := config.ParseBudgetWarnings(budgetWarn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --budget-warn: %w", err)
+		}
+		return fractions, nil
+	}
+	if fileConfig == nil {
+		return nil, nil
+	}
+	if err := config.ValidateBudgetWarnings(fileConfig.BudgetWarn); err != nil {
+		return nil, fmt.Errorf("invalid budget_warn config: %w", err)
+	}
+	return fileConfig.BudgetWarn, nil
+}
+
+// reportBudgetWarning shows a soft budget warning in a TUI banner or on
+// stdout, sends a budget notification and records it in the notes file.
+func reportBudgetWarning(w loop.BudgetWarning, iteration int, notes *spec.Notes, notifier *notify.Notifier, tuiProgram *tui.Program) {
+	msg := "Budget warning: " + w.String()
+	if tuiProgram != nil {
+		tuiProgram.SendBanner(msg)
+		tuiProgram.SendOutput("⚠ " + msg)
+	} else {
+		fmt.Printf("\n⚠ %s\n", msg)
+	}
+	if err := notifier.Notify(notify.EventBudgetThreshold, "Orbital budget warning", w.String()); err != nil {
+		reportNotifyError(err, tuiProgram)
+	}
+	if notes != nil {
+		body := fmt.Sprintf("This session has %s. The run stops when the budget is spent: favour the remaining spec items over new work.\n", w.String())
+		if err := notes.AddSection(iteration, "budget warning", body, time.Now()); err != nil {
+			reportNotesError(err, tuiProgram)
+		}
+	}
+}
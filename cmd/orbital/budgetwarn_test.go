@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+)
+
+func TestResolveBudgetWarnings(t *testing.T) {
+	old := budgetWarn
+	defer func() { budgetWarn = old }()
+	fileConfig := &config.FileConfig{BudgetWarn: []float64{0.9}}
+
+	budgetWarn = ""
+	if got, err := resolveBudgetWarnings(fileConfig); err != nil || fmt.Sprint(got) != "[0.9]" {
+		t.Errorf("resolveBudgetWarnings(config) = %v, %v; want [0.9]", got, err)
+	}
+	if got, err := resolveBudgetWarnings(nil); err != nil || got != nil {
+		t.Errorf("resolveBudgetWarnings(nil) = %v, %v; want none", got, err)
+	}
+
+	budgetWarn = "0.5,0.8"
+	if got, err := resolveBudgetWarnings(fileConfig); err != nil || fmt.Sprint(got) != "[0.5 0.8]" {
+		t.Errorf("resolveBudgetWarnings(flag) = %v, %v; want the flag's [0.5 0.8]", got, err)
+	}
+
+	budgetWarn = "1.5"
+	if _, err := resolveBudgetWarnings(nil); err == nil || !strings.Contains(err.Error(), "invalid --budget-warn") {
+		t.Errorf("resolveBudgetWarnings(1.5) error = %v, want invalid --budget-warn", err)
+	}
+	budgetWarn = ""
+	if _, err := resolveBudgetWarnings(&config.FileConfig{BudgetWarn: []float64{2}}); err == nil || !strings.Contains(err.Error(), "invalid budget_warn config") {
+		t.Errorf("resolveBudgetWarnings(budget_warn = [2]) error = %v, want invalid budget_warn config", err)
+	}
+}
+
+func TestReportBudgetWarning(t *testing.T) {
+	notesPath := filepath.Join(t.TempDir(), "notes.md")
+	var sent [][]string
+	notifier := notify.New(notify.Config{Desktop: true})
+	notifier.SetCommandRunner(func(name string, args ...string) error {
+		sent = append(sent, args)
+		return nil
+	})
+
+	w := loop.BudgetWarning{Fraction: 0.5, Spent: 5.2, Budget: 10}
+	reportBudgetWarning(w, 3, spec.NewNotes(notesPath), notifier, nil)
+
+	if len(sent) != 1 || !strings.Contains(strings.Join(sent[0], " "), "spent $5.20 of the $10.00 budget (passed 50%)") {
+		t.Errorf("notifications = %v, want one budget warning", sent)
+	}
+	notes, err := os.ReadFile(notesPath)
+	if err != nil {
+		t.Fatalf("notes not written: %v", err)
+	}
+	if !strings.Contains(string(notes), "budget warning") || !strings.Contains(string(notes), "passed 50%") {
+		t.Errorf("notes = %q, want a budget warning section", notes)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// applyBurst switches the executors into burst mode before the first of the
+// --burst iterations and back to the main model after the last, recording
+// each switch in the state. Other iterations are left alone.
+func applyBurst(iteration int, cfg *config.Config, exec *executor.Executor, stepExec *claudeStepExecutor, st *state.State, tuiProgram *tui.Program) {
+	var msg string
+	switch {
+	case cfg.BurstIterations == 0:
+		return
+	case iteration == 1:
+		stepExec.model = cfg.BurstModel
+		exec.SetMaxTurns(cfg.BurstMaxTurns)
+		st.RecordModeSwitch(iteration, state.ModeBurst, cfg.BurstModel)
+		msg = fmt.Sprintf("Burst: running %d quick iteration(s) on %s with at most %d turns each", cfg.BurstIterations, cfg.BurstModel, cfg.BurstMaxTurns)
+	case iteration == cfg.BurstIterations+1:
+		stepExec.model = ""
+		exec.SetMaxTurns(0)
+		st.RecordModeSwitch(iteration, state.ModeMain, exec.Model())
+		msg = fmt.Sprintf("Burst complete after %d iteration(s); switching to %s", cfg.BurstIterations, exec.Model())
+	default:
+		return
+	}
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("⚡ " + msg)
+	} else {
+		fmt.Printf("\n%s\n", msg)
+	}
+}
+
+// iterationModel returns the model an iteration's steps run on.
+func iterationModel(exec *executor.Executor, stepExec *claudeStepExecutor) string {
+	if stepExec.model != "" {
+		return stepExec.model
+	}
+	return exec.Model()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/state"
+)
+
+func TestApplyBurst(t *testing.T) {
+	cfg := &config.Config{Model: "opus", MaxBudget: 1, MaxTurns: 40, BurstIterations: 2, BurstModel: "haiku", BurstMaxTurns: 5}
+	exec := executor.New(cfg)
+	stepExec := &claudeStepExecutor{exec: exec}
+	st := state.NewState("sess", t.TempDir(), nil, "", nil)
+
+	tests := []struct {
+		iteration    int
+		wantModel    string
+		wantMaxTurns int
+		wantSwitches int
+	}{
+		{iteration: 1, wantModel: "haiku", wantMaxTurns: 5, wantSwitches: 1},
+		{iteration: 2, wantModel: "haiku", wantMaxTurns: 5, wantSwitches: 1},
+		{iteration: 3, wantModel: "opus", wantMaxTurns: 40, wantSwitches: 2},
+		{iteration: 4, wantModel: "opus", wantMaxTurns: 40, wantSwitches: 2},
+	}
+	for _, tt := range tests {
+		applyBurst(tt.iteration, cfg, exec, stepExec, st, nil)
+		if got := iterationModel(exec, stepExec); got != tt.wantModel {
+			t.Errorf("iteration %d: model = %q, want %q", tt.iteration, got, tt.wantModel)
+		}
+		if got := exec.MaxTurns(); got != tt.wantMaxTurns {
+			t.Errorf("iteration %d: max turns = %d, want %d", tt.iteration, got, tt.wantMaxTurns)
+		}
+		if got := len(st.ModeSwitches); got != tt.wantSwitches {
+			t.Errorf("iteration %d: %d mode switches, want %d", tt.iteration, got, tt.wantSwitches)
+		}
+	}
+
+	want := []state.ModeSwitch{
+		{Iteration: 1, Mode: state.ModeBurst, Model: "haiku"},
+		{Iteration: 3, Mode: state.ModeMain, Model: "opus"},
+	}
+	for i, w := range want {
+		got := st.ModeSwitches[i]
+		if got.Iteration != w.Iteration || got.Mode != w.Mode || got.Model != w.Model {
+			t.Errorf("ModeSwitches[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestApplyBurst_Disabled(t *testing.T) {
+	cfg := &config.Config{Model: "opus", MaxBudget: 1}
+	exec := executor.New(cfg)
+	stepExec := &claudeStepExecutor{exec: exec}
+	st := state.NewState("sess", t.TempDir(), nil, "", nil)
+
+	applyBurst(1, cfg, exec, stepExec, st, nil)
+	if got := iterationModel(exec, stepExec); got != "opus" {
+		t.Errorf("model = %q, want opus", got)
+	}
+	if len(st.ModeSwitches) != 0 {
+		t.Errorf("ModeSwitches = %+v, want none", st.ModeSwitches)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// resolveCache sets up the step cache: [cache] first, then --no-cache.
+func resolveCache(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.Cache != nil && fileConfig.Cache.TTL != 0 {
+		cfg.CacheTTL = fileConfig.Cache.TTL.Duration()
+	}
+	if cmd.Flags().Changed("no-cache") {
+		cfg.NoCache = noCache
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// stepCache adapts executor.Cache to workflow.StepCache, keying results on
+// the model the step runs on.
+type stepCache struct {
+	cache    *executor.Cache
+	stepExec *claudeStepExecutor
+
+	// noCache skips lookups while still caching results (--no-cache).
+	noCache bool
+
+	tuiProgram *tui.Program
+	now        func() time.Time
+}
+
+// newStepCache returns the cache for the cacheable steps run by stepExec.
+func newStepCache(cfg *config.Config, stepExec *claudeStepExecutor, tuiProgram *tui.Program) *stepCache {
+	return &stepCache{
+		cache:      executor.NewCache(executor.CacheDir(cfg.WorkingDir), cfg.CacheTTL),
+		stepExec:   stepExec,
+		noCache:    cfg.NoCache,
+		tuiProgram: tuiProgram,
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached result of prompt and reports the reuse.
+func (c *stepCache) Get(stepName, model, prompt string) (*workflow.ExecutionResult, bool) {
+	if c.noCache {
+		return nil, false
+	}
+	entry, ok := c.cache.Get(c.stepExec.stepModel(model), prompt)
+	if !ok {
+		return nil, false
+	}
+	c.report("♻ ", fmt.Sprintf("Step %s: reused the result cached %s ago (saved %s)",
+		stepName, formatDuration(c.now().Sub(entry.CreatedAt)), util.FormatCost(entry.CostUSD, 2)))
+	return &workflow.ExecutionResult{Output: entry.Output}, true
+}
+
+// Put caches a step result. A failure to write it is reported but does not
+// stop the run.
+func (c *stepCache) Put(stepName, model, prompt string, result *workflow.ExecutionResult) {
+	err := c.cache.Put(c.stepExec.stepModel(model), prompt, executor.CacheEntry{
+		Output:    result.Output,
+		CostUSD:   result.CostUSD,
+		TokensIn:  result.TokensIn,
+		TokensOut: result.TokensOut,
+	})
+	if err != nil {
+		c.report("⚠ ", fmt.Sprintf("Step %s: %v", stepName, err))
+	}
+}
+
+// report shows msg in the TUI, or prints it.
+func (c *stepCache) report(icon, msg string) {
+	if c.tuiProgram != nil {
+		c.tuiProgram.SendOutput(icon + msg)
+	} else {
+		fmt.Printf("\n%s\n", msg)
+	}
+}
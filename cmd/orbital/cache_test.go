@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveCache(t *testing.T) {
+	oldNoCache := noCache
+	defer func() { noCache = oldNoCache }()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().BoolVar(&noCache, "no-cache", false, "")
+		return cmd
+	}
+
+	newCfg := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.SpecPath = "spec.md"
+		return cfg
+	}
+
+	cfg := newCfg()
+	if err := resolveCache(newCmd(), cfg, nil); err != nil {
+		t.Fatalf("resolveCache() error = %v", err)
+	}
+	if cfg.CacheTTL != config.DefaultCacheTTL || cfg.NoCache {
+		t.Errorf("cache = %v, no-cache %v; want the default TTL", cfg.CacheTTL, cfg.NoCache)
+	}
+
+	fileConfig := &config.FileConfig{Cache: &config.CacheConfig{TTL: workflow.Duration(2 * time.Hour)}}
+	cmd := newCmd()
+	_ = cmd.Flags().Set("no-cache", "true")
+	cfg = newCfg()
+	if err := resolveCache(cmd, cfg, fileConfig); err != nil {
+		t.Fatalf("resolveCache() error = %v", err)
+	}
+	if cfg.CacheTTL != 2*time.Hour || !cfg.NoCache {
+		t.Errorf("cache = %v, no-cache %v; want 2h and no-cache", cfg.CacheTTL, cfg.NoCache)
+	}
+}
+
+func TestStepCache(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.WorkingDir = t.TempDir()
+	exec := executor.New(cfg)
+	stepExec := &claudeStepExecutor{exec: exec}
+	cache := newStepCache(cfg, stepExec, nil)
+
+	cache.Put("plan", "", "plan it", &workflow.ExecutionResult{Output: "the plan", CostUSD: 0.42})
+
+	got, ok := cache.Get("plan", "", "plan it")
+	if !ok || got.Output != "the plan" {
+		t.Fatalf("Get() = %+v, %v; want the cached plan", got, ok)
+	}
+	if _, ok := cache.Get("plan", "sonnet", "plan it"); ok {
+		t.Error("Get() on an escalation model hit the executor model's entry")
+	}
+
+	// Burst iterations run on their own model
+	stepExec.model = cfg.BurstModel
+	if _, ok := cache.Get("plan", "", "plan it"); ok {
+		t.Error("Get() during a burst hit the main model's entry")
+	}
+	stepExec.model = ""
+
+	cache.noCache = true
+	if _, ok := cache.Get("plan", "", "plan it"); ok {
+		t.Error("Get() with --no-cache hit")
+	}
+}
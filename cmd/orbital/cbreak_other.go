@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package main
+
+import "errors"
+
+// cbreak always fails: single-key input is only supported on Linux and
+// macOS.
+func cbreak(fd int) (func(), error) {
+	return nil, errors.New("single-key input is not supported on this platform")
+}
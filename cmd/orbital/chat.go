@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// chatSpend collects the cost of chat turns, which run alongside the loop,
+// until the loop adds it to the session's totals. It is safe for concurrent
+// use; a nil chatSpend records nothing.
+type chatSpend struct {
+	mu   sync.Mutex
+	cost float64
+}
+
+// Add records the cost of a chat turn.
+func (c *chatSpend) Add(cost float64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cost += cost
+}
+
+// Take returns the cost recorded since the last call.
+func (c *chatSpend) Take() float64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cost := c.cost
+	c.cost = 0
+	return cost
+}
+
+// newChatFunc returns a chat handler for the TUI that answers questions about
+// the run using the checker model. It uses its own executor so questions never
+// share a process or stream with the main loop. The cost of each turn is
+// recorded in spend.
+func newChatFunc(cfg *config.Config, redactor *redact.Redactor, specFiles []string, notesFile string, spend *chatSpend) tui.ChatFunc {
+	return func(ctx context.Context, question string, recentOutput []string) (string, error) {
+		chatConfig := &config.Config{
+			Model:      cfg.CheckerModel,
+			MaxBudget:  cfg.MaxBudget,
+			WorkingDir: cfg.WorkingDir,
+		}
+
+		chatExec := executor.New(chatConfig)
+		chatExec.SetRedactor(redactor)
+		prompt := spec.BuildChatPrompt(specFiles, notesFile, recentOutput, question)
+
+		result, err := chatExec.Execute(ctx, prompt)
+		if result != nil {
+			spend.Add(result.CostUSD)
+		}
+		if err != nil {
+			return "", fmt.Errorf("chat execution failed: %w", err)
+		}
+
+		return output.ExtractResult(result.Output), nil
+	}
+}
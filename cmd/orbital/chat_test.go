@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+func TestChatSpend(t *testing.T) {
+	var nilSpend *chatSpend
+	nilSpend.Add(1)
+	if got := nilSpend.Take(); got != 0 {
+		t.Errorf("nil Take() = %v, want 0", got)
+	}
+
+	spend := &chatSpend{}
+	spend.Add(0.25)
+	spend.Add(0.5)
+	if got := spend.Take(); got != 0.75 {
+		t.Errorf("Take() = %v, want 0.75", got)
+	}
+	if got := spend.Take(); got != 0 {
+		t.Errorf("second Take() = %v, want 0", got)
+	}
+}
+
+func TestNewChatFunc_RecordsCostAndRunsInProject(t *testing.T) {
+	// A stand-in claude that answers with the directory it was started in
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"{\\\"type\\\":\\\"result\\\",\\\"result\\\":\\\"$(pwd)\\\",\\\"total_cost_usd\\\":0.25}\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	project, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend := &chatSpend{}
+	chat := newChatFunc(&config.Config{CheckerModel: "haiku", MaxBudget: 5, WorkingDir: project}, nil, nil, "", spend)
+
+	answer, err := chat(context.Background(), "how is it going?", nil)
+	if err != nil {
+		t.Fatalf("chat() error = %v", err)
+	}
+	if answer != project {
+		t.Errorf("chat ran in %q, want the project %q", answer, project)
+	}
+	if got := spend.Take(); got != 0.25 {
+		t.Errorf("recorded chat cost = %v, want 0.25", got)
+	}
+}
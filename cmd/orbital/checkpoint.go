@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// checkpointQueue collects checkpoint names declared during an iteration,
+// by Claude in its output or by the user in the TUI, until the iteration's
+// snapshot is taken. It is safe for concurrent use.
+type checkpointQueue struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// Add queues a checkpoint name for the current iteration.
+func (q *checkpointQueue) Add(name string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, n := range q.names {
+		if n == name {
+			return
+		}
+	}
+	q.names = append(q.names, name)
+}
+
+// Take returns the queued names and empties the queue.
+func (q *checkpointQueue) Take() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	names := q.names
+	q.names = nil
+	return names
+}
+
+// recordCheckpoints records the named checkpoints at the given iteration,
+// whose snapshot must already be recorded, and announces each one.
+func recordCheckpoints(st *state.State, iteration int, names []string, tuiProgram *tui.Program) error {
+	if len(names) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		st.RecordCheckpoint(name, iteration)
+		if tuiProgram != nil {
+			tuiProgram.SendCheckpoint(name, iteration)
+		} else {
+			fmt.Printf("📍 Checkpoint %q at iteration %d\n", name, iteration)
+		}
+	}
+	if err := st.Save(); err != nil {
+		return fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+	return nil
+}
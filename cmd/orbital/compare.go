@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+var compareFormat string
+
+var compareCmd = newCompareCmd()
+
+func newCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <session-a> <session-b>",
+		Short: "Compare two recorded runs",
+		Long: `Compare two recorded orbital runs side by side.
+
+Runs are recorded in .orbital/runs/ when a session ends. Each run is
+referenced by its session ID (a unique prefix is enough) or by the path
+to its record file.
+
+The comparison covers iterations, cost, tokens, duration, steps taken,
+gate failures, verification outcomes, and the final git diff of each run.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRunIDs,
+		RunE:              runCompare,
+	}
+	cmd.Flags().StringVar(&compareFormat, "format", string(runs.FormatTable), "Output format: table or markdown")
+	return cmd
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if err := loadDisplay(workingDir); err != nil {
+		return err
+	}
+	a, err := runs.Load(workingDir, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load run A: %w", err)
+	}
+	b, err := runs.Load(workingDir, args[1])
+	if err != nil {
+		return fmt.Errorf("failed to load run B: %w", err)
+	}
+
+	return runs.Compare(a, b).Render(cmd.OutOrStdout(), runs.Format(compareFormat))
+}
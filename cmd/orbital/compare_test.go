@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/runs"
+)
+
+func setupRecordedRuns(t *testing.T) {
+	t.Helper()
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(originalWd); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	})
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	a := runs.NewRecord("aaaa1111", nil, "fast", "opus")
+	a.Iterations = 3
+	b := runs.NewRecord("bbbb2222", nil, "reviewed", "sonnet")
+	b.Iterations = 5
+	for _, rec := range []*runs.Record{a, b} {
+		if err := rec.Save("."); err != nil {
+			t.Fatalf("failed to save run record: %v", err)
+		}
+	}
+}
+
+func TestCompareCmd_RendersTable(t *testing.T) {
+	setupRecordedRuns(t)
+
+	cmd := newCompareCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"aaaa", "bbbb"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"METRIC", "Iterations", "reviewed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestCompareCmd_RendersMarkdown(t *testing.T) {
+	setupRecordedRuns(t)
+
+	cmd := newCompareCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"aaaa", "bbbb", "--format", "markdown"})
+	defer func() { compareFormat = string(runs.FormatTable) }()
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "| Iterations | 3 | 5 | +2 |") {
+		t.Errorf("markdown output missing iterations row:\n%s", buf.String())
+	}
+}
+
+func TestCompareCmd_UnknownRun(t *testing.T) {
+	setupRecordedRuns(t)
+
+	cmd := newCompareCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"aaaa", "zzzz"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("Execute() error = nil, want error for unknown run")
+	}
+	if !strings.Contains(err.Error(), "run B") {
+		t.Errorf("error = %q, want to mention run B", err.Error())
+	}
+}
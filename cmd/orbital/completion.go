@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/flashingpumpkin/orbital/internal/session"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// specPlansDir is where spec file completion looks for specs, relative to
+// the working directory.
+var specPlansDir = filepath.Join("docs", "plans")
+
+// completeSpecFiles completes the spec file argument with the Markdown files
+// under docs/plans/, falling back to the shell's file completion when none
+// match.
+func completeSpecFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	paths, _ := filepath.Glob(filepath.Join(workingDir, specPlansDir, "*.md"))
+	var specs []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(workingDir, p)
+		if err != nil {
+			continue
+		}
+		if strings.HasPrefix(rel, toComplete) {
+			specs = append(specs, rel)
+		}
+	}
+	return specs, cobra.ShellCompDirectiveDefault
+}
+
+// completeWorkflows completes --workflow with the preset names and their
+// descriptions.
+func completeWorkflows(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	descriptions := workflow.PresetDescriptions()
+	var names []string
+	for _, p := range workflow.ValidPresets() {
+		if strings.HasPrefix(string(p), toComplete) {
+			names = append(names, string(p)+"\t"+descriptions[p])
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSessionIDs completes continue --id with the IDs of the resumable
+// sessions in the current directory and its worktrees.
+func completeSessionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sessions, err := session.NewCollector(wd).Collect()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, s := range sessions {
+		if s.ID != "" && s.Valid && strings.HasPrefix(s.ID, toComplete) {
+			ids = append(ids, s.ID+"\t"+s.DisplayName())
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRunIDs completes compare's arguments with the session IDs of the
+// recorded runs, newest first, leaving out runs already given.
+func completeRunIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) >= 2 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	records, err := runs.List(workingDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if !strings.HasPrefix(r.SessionID, toComplete) || (len(args) > 0 && args[0] == r.SessionID) {
+			continue
+		}
+		ids = append(ids, r.SessionID+"\t"+runDescription(r))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEventLogIDs completes logs' argument with the session IDs of the
+// runs that have an event log.
+func completeEventLogIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeRunFileIDs(".events.jsonl")(cmd, args, toComplete)
+}
+
+// completeRunFileIDs returns a completion of a command's argument with the
+// session IDs of the runs that have a file with suffix in the runs directory.
+func completeRunFileIDs(suffix string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		paths, _ := filepath.Glob(filepath.Join(runs.Dir(workingDir), "*"+suffix))
+		var ids []string
+		for _, p := range paths {
+			if id := strings.TrimSuffix(filepath.Base(p), suffix); strings.HasPrefix(id, toComplete) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// runDescription describes a recorded run for completion, e.g.
+// "2026-01-24 auth.md".
+func runDescription(r *runs.Record) string {
+	names := make([]string, len(r.SpecFiles))
+	for i, f := range r.SpecFiles {
+		names[i] = filepath.Base(f)
+	}
+	return r.StartedAt.Format("2006-01-02") + " " + strings.Join(names, ", ")
+}
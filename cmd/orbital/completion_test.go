@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteWorkflows(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{name: "empty prefix lists all presets", toComplete: "", want: []string{"fast", "spec-driven", "reviewed", "tdd", "autonomous", "panel"}},
+		{name: "prefix filters presets", toComplete: "re", want: []string{"reviewed"}},
+		{name: "unknown prefix", toComplete: "zzz", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := completeWorkflows(nil, nil, tt.toComplete)
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %v, want NoFileComp", directive)
+			}
+			var names []string
+			for _, c := range got {
+				name, desc, ok := strings.Cut(c, "\t")
+				if !ok || desc == "" {
+					t.Errorf("completion %q has no description", c)
+				}
+				names = append(names, name)
+			}
+			for _, want := range tt.want {
+				found := false
+				for _, n := range names {
+					found = found || n == want
+				}
+				if !found {
+					t.Errorf("completions %v missing %q", names, want)
+				}
+			}
+			if tt.want == nil && len(names) != 0 {
+				t.Errorf("completions = %v, want none", names)
+			}
+		})
+	}
+}
+
+func TestCompleteSpecFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(originalWd) })
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	plans := filepath.Join("docs", "plans")
+	if err := os.MkdirAll(plans, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"auth.md", "api.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(plans, name), []byte("# spec"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		toComplete    string
+		want          []string
+		wantDirective cobra.ShellCompDirective
+	}{
+		{name: "lists markdown specs", toComplete: "", want: []string{filepath.Join(plans, "api.md"), filepath.Join(plans, "auth.md")}, wantDirective: cobra.ShellCompDirectiveDefault},
+		{name: "filters by prefix", toComplete: filepath.Join(plans, "au"), want: []string{filepath.Join(plans, "auth.md")}, wantDirective: cobra.ShellCompDirectiveDefault},
+		{name: "falls back to files when nothing matches", toComplete: "src/", want: nil, wantDirective: cobra.ShellCompDirectiveDefault},
+		{name: "no completion after the spec", args: []string{"docs/plans/auth.md"}, want: nil, wantDirective: cobra.ShellCompDirectiveNoFileComp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := completeSpecFiles(nil, tt.args, tt.toComplete)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completions = %v, want %v", got, tt.want)
+			}
+			if directive != tt.wantDirective {
+				t.Errorf("directive = %v, want %v", directive, tt.wantDirective)
+			}
+		})
+	}
+}
+
+func TestCompleteRunIDs(t *testing.T) {
+	setupRecordedRuns(t)
+
+	got, _ := completeRunIDs(nil, nil, "")
+	if len(got) != 2 {
+		t.Fatalf("completions = %v, want 2", got)
+	}
+
+	got, _ = completeRunIDs(nil, []string{"aaaa1111"}, "")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "bbbb2222\t") {
+		t.Errorf("completions = %v, want only bbbb2222", got)
+	}
+
+	got, _ = completeRunIDs(nil, []string{"aaaa1111", "bbbb2222"}, "")
+	if len(got) != 0 {
+		t.Errorf("completions = %v, want none once both runs are given", got)
+	}
+}
+
+func TestCompleteEventLogIDs(t *testing.T) {
+	setupRecordedRuns(t)
+	for _, id := range []string{"cccc3333", "dddd4444"} {
+		if err := os.WriteFile(runs.EventLogPath(".", id), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, _ := completeEventLogIDs(nil, nil, "c")
+	if want := []string{"cccc3333"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completions = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = newConfigCmd()
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect orbital configuration",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newConfigShowCmd())
+	return cmd
+}
+
+func newConfigShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the configuration files in effect",
+		Long: `Show the configuration files orbital reads, lowest precedence first:
+
+    user      ~/.config/orbital/config.toml ($XDG_CONFIG_HOME is honoured)
+    project   .orbital/config.toml, or the file given with --config
+    flags     command-line flags such as --dangerous and --workflow
+
+Tables are merged key by key; any other value (including arrays such as
+[[workflow.steps]]) from a higher layer replaces the lower one.
+
+With --effective, print the merged result and the source of each value.`,
+		Args: cobra.NoArgs,
+		RunE: runConfigShow,
+	}
+	cmd.Flags().Bool("effective", false, "Print the merged configuration and the source of each value")
+	return cmd
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	sources := configSources(workingDir)
+	layered, err := config.LoadLayered(sources)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	effective, _ := cmd.Flags().GetBool("effective")
+	if !effective {
+		return printConfigLayers(out, sources)
+	}
+
+	// Flags take precedence over both files
+	if cmd.Flags().Changed("dangerous") {
+		if err := layered.Set("dangerous", dangerous, config.LayerFlag+" --dangerous"); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("workflow") {
+		if err := layered.Set("workflow.preset", workflowFlag, config.LayerFlag+" --workflow"); err != nil {
+			return err
+		}
+	}
+
+	settings := layered.Settings()
+	if len(settings) == 0 {
+		_, _ = fmt.Fprintln(out, "No configuration set; using defaults")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, s := range settings {
+		_, _ = fmt.Fprintf(tw, "%s = %s\t# %s\n", s.Key, formatConfigValue(s.Value), s.Source)
+	}
+	return tw.Flush()
+}
+
+// printConfigLayers prints each config file and its contents, lowest
+// precedence first.
+func printConfigLayers(out io.Writer, sources []config.Source) error {
+	for i, src := range sources {
+		if i > 0 {
+			_, _ = fmt.Fprintln(out)
+		}
+		if src.Path == "" {
+			_, _ = fmt.Fprintf(out, "# %s: (unavailable)\n", src.Layer)
+			continue
+		}
+		data, err := os.ReadFile(src.Path)
+		if os.IsNotExist(err) {
+			_, _ = fmt.Fprintf(out, "# %s: %s (not found)\n", src.Layer, src.Path)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", src.Path, err)
+		}
+		_, _ = fmt.Fprintf(out, "# %s: %s\n", src.Layer, src.Path)
+		_, _ = out.Write(data)
+	}
+	return nil
+}
+
+// formatConfigValue renders a config value in TOML-like syntax.
+func formatConfigValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []any:
+		s := "["
+		for i, item := range v {
+			if i > 0 {
+				s += ", "
+			}
+			s += formatConfigValue(item)
+		}
+		return s + "]"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupConfigLayers(t *testing.T) {
+	t.Helper()
+	chdirTemp(t)
+
+	userHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", userHome)
+	if err := os.MkdirAll(filepath.Join(userHome, "orbital"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(userHome, "orbital", "config.toml"), []byte("thinking = \"show\"\ndangerous = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(".orbital", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(".orbital", "config.toml"), []byte("thinking = \"log\"\n\n[workflow]\npreset = \"fast\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func executeConfigShow(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := newConfigCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs(append([]string{"show"}, args...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestConfigShow_Effective(t *testing.T) {
+	setupConfigLayers(t)
+
+	out := executeConfigShow(t, "--effective")
+
+	for _, want := range []string{
+		`dangerous = true`,
+		`thinking = "log"`,
+		`workflow.preset = "fast"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "dangerous"):
+			if !strings.HasSuffix(line, "# user") {
+				t.Errorf("dangerous line = %q, want user source", line)
+			}
+		case strings.HasPrefix(line, "thinking"):
+			if !strings.HasSuffix(line, "# project") {
+				t.Errorf("thinking line = %q, want project source", line)
+			}
+		}
+	}
+}
+
+func TestConfigShow_Layers(t *testing.T) {
+	setupConfigLayers(t)
+
+	out := executeConfigShow(t)
+
+	if !strings.Contains(out, "# user: ") || !strings.Contains(out, `thinking = "show"`) {
+		t.Errorf("output missing user layer:\n%s", out)
+	}
+	if !strings.Contains(out, "# project: ") || !strings.Contains(out, `preset = "fast"`) {
+		t.Errorf("output missing project layer:\n%s", out)
+	}
+}
+
+func TestConfigShow_EffectiveNoConfig(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	out := executeConfigShow(t, "--effective")
+	if !strings.Contains(out, "using defaults") {
+		t.Errorf("output = %q, want defaults message", out)
+	}
+}
+
+func TestLoadFileConfig_LayersUserConfig(t *testing.T) {
+	setupConfigLayers(t)
+
+	cfg, err := loadFileConfig(".")
+	if err != nil {
+		t.Fatalf("loadFileConfig() error = %v", err)
+	}
+	if cfg == nil || !cfg.Dangerous || cfg.Thinking != "log" {
+		t.Errorf("loadFileConfig() = %+v, want user dangerous and project thinking", cfg)
+	}
+}
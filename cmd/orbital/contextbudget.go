@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// resolveContextBudget sets up the step prompt budget: [context_budget]
+// first, then --context-budget.
+func resolveContextBudget(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.ContextBudget != nil {
+		cfg.ContextBudget = fileConfig.ContextBudget.Fraction
+		cfg.ContextTrimOrder = fileConfig.ContextBudget.TrimOrder
+	}
+	if cmd.Flags().Changed("context-budget") {
+		cfg.ContextBudget = contextBudget
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// newContextBudget returns the budget step prompts are trimmed to, a share
+// of the model's context window, or nil when there is none.
+func newContextBudget(cfg *config.Config) *workflow.ContextBudget {
+	if cfg.ContextBudget == 0 {
+		return nil
+	}
+	return &workflow.ContextBudget{
+		Limit:     int(cfg.ContextBudget * float64(config.GetContextWindow(cfg.Model))),
+		TrimOrder: cfg.ContextTrimOrder,
+	}
+}
+
+// contextBudgetReporter returns a callback reporting what was trimmed from
+// a step prompt to fit the budget.
+func contextBudgetReporter(tuiProgram *tui.Program) workflow.BudgetCallback {
+	return func(report workflow.BudgetReport) {
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("✂ " + report.String())
+		} else {
+			fmt.Printf("\n%s\n", report)
+		}
+	}
+}
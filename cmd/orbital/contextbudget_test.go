@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveContextBudget(t *testing.T) {
+	oldBudget := contextBudget
+	defer func() { contextBudget = oldBudget }()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Float64Var(&contextBudget, "context-budget", 0, "")
+		return cmd
+	}
+	newCfg := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.SpecPath = "spec.md"
+		cfg.Model = "opus"
+		return cfg
+	}
+
+	cfg := newCfg()
+	if err := resolveContextBudget(newCmd(), cfg, nil); err != nil {
+		t.Fatalf("resolveContextBudget() error = %v", err)
+	}
+	if newContextBudget(cfg) != nil {
+		t.Error("context budget enabled by default")
+	}
+
+	fileConfig := &config.FileConfig{ContextBudget: &config.ContextBudgetConfig{Fraction: 0.5, TrimOrder: []string{"notes"}}}
+	cfg = newCfg()
+	if err := resolveContextBudget(newCmd(), cfg, fileConfig); err != nil {
+		t.Fatalf("resolveContextBudget() error = %v", err)
+	}
+	budget := newContextBudget(cfg)
+	if budget == nil || budget.Limit != config.GetContextWindow("opus")/2 || strings.Join(budget.TrimOrder, ",") != "notes" {
+		t.Errorf("budget = %+v, want half of opus's window trimming notes", budget)
+	}
+
+	cmd := newCmd()
+	_ = cmd.Flags().Set("context-budget", "0.25")
+	cfg = newCfg()
+	if err := resolveContextBudget(cmd, cfg, fileConfig); err != nil {
+		t.Fatalf("resolveContextBudget() error = %v", err)
+	}
+	if cfg.ContextBudget != 0.25 {
+		t.Errorf("ContextBudget = %v, want the flag's 0.25", cfg.ContextBudget)
+	}
+
+	cmd = newCmd()
+	_ = cmd.Flags().Set("context-budget", "2")
+	if err := resolveContextBudget(cmd, newCfg(), nil); err == nil {
+		t.Error("resolveContextBudget(2) expected error")
+	}
+}
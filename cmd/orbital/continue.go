@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,37 +12,40 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/flashingpumpkin/orbital/internal/completion"
+	"github.com/flashingpumpkin/orbital/internal/audit"
 	"github.com/flashingpumpkin/orbital/internal/config"
 	"github.com/flashingpumpkin/orbital/internal/executor"
 	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/memory"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/runs"
 	"github.com/flashingpumpkin/orbital/internal/output"
 	"github.com/flashingpumpkin/orbital/internal/session"
 	"github.com/flashingpumpkin/orbital/internal/spec"
 	"github.com/flashingpumpkin/orbital/internal/state"
 	"github.com/flashingpumpkin/orbital/internal/tui"
 	"github.com/flashingpumpkin/orbital/internal/tui/selector"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
-var continueCmd = &cobra.Command{
-	Use:   "continue",
-	Short: "Resume a terminated session",
-	Long: `Resume a previously terminated orbital session.
+// continueAt is the named checkpoint to roll back to before resuming.
+var continueAt string
 
-This command loads the session state from .orbital/state/ and resumes
-execution with the same session ID. Use this after:
-- Ctrl+C interruption
-- Unexpected termination
-- System restart
+// continueID picks the session to resume without the selector; continueList
+// lists the resumable sessions instead of resuming one.
+var (
+	continueID   string
+	continueList bool
+)
 
-If a orbital instance is already running, an error is returned.`,
-	Args: cobra.NoArgs,
-	RunE: runContinue,
-}
+var continueCmd = newContinueCmd()
 
 func newContinueCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "continue",
-		Short: "Resume a terminated session",
+	cmd := &cobra.Command{
+		Use:     "continue",
+		Aliases: []string{"resume"},
+		Short:   "Resume a terminated session",
 		Long: `Resume a previously terminated orbital session.
 
 This command loads the session state from .orbital/state/ and resumes
@@ -50,19 +54,52 @@ execution with the same session ID. Use this after:
 - Unexpected termination
 - System restart
 
+Sessions left in this directory and in the repository's other git
+worktrees are offered in a selector showing each one's specs, age,
+iteration, cost and status. Use --list to print them instead, and
+--id <session> (or a unique prefix of it) to resume one without prompting.
+
+Use --at <checkpoint> to roll the working tree back to a named checkpoint
+first and resume from the iteration it was declared in.
+
 If a orbital instance is already running, an error is returned.`,
 		Args: cobra.NoArgs,
 		RunE: runContinue,
 	}
+	cmd.Flags().StringVar(&continueAt, "at", "", "Roll back to this named checkpoint before resuming")
+	cmd.Flags().StringVar(&continueID, "id", "", "Resume the session with this ID (or a unique prefix of it)")
+	_ = cmd.RegisterFlagCompletionFunc("id", completeSessionIDs)
+	cmd.Flags().BoolVar(&continueList, "list", false, "List resumable sessions and exit")
+	return cmd
 }
 
 func runContinue(cmd *cobra.Command, args []string) error {
+	if err := validateProgressMode(progressMode); err != nil {
+		return err
+	}
+
 	// Get current working directory
 	wd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
+	// Load optional config files; encrypted sessions need the key before
+	// their state is read
+	fileConfig, err := loadFileConfig(wd)
+	if err != nil {
+		return err
+	}
+	if err := setupEncryption(fileConfig); err != nil {
+		return err
+	}
+	if err := setupDisplay(fileConfig); err != nil {
+		return err
+	}
+	if err := setupExecutor(wd); err != nil {
+		return err
+	}
+
 	// Collect all sessions (valid and invalid)
 	collector := session.NewCollector(wd)
 	sessions, err := collector.Collect()
@@ -74,8 +111,19 @@ func runContinue(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no session to continue in this directory")
 	}
 
+	if continueList {
+		_, _ = fmt.Fprint(cmd.OutOrStdout(), formatSessionList(sessions))
+		return nil
+	}
+
 	// Select session based on flags or interactive TUI
-	selected, _, selectErr := selectSession(sessions, collector, themeFlag)
+	var selected *session.Session
+	var selectErr error
+	if continueID != "" {
+		selected, selectErr = findSession(sessions, continueID)
+	} else {
+		selected, _, selectErr = selectSession(sessions, collector, themeFlag)
+	}
 
 	if selectErr != nil {
 		return selectErr
@@ -92,8 +140,14 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	files = selected.SpecFiles
 	sessID = selected.ID
 
+	// A session in another worktree resumes there
+	if selected.Type == session.SessionTypeWorktree {
+		effectiveWorkingDir = selected.Path()
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Resuming in worktree %s\n", effectiveWorkingDir)
+	}
+
 	// Also check for queued files to merge
-	stateDir := state.StateDir(wd)
+	stateDir := state.StateDir(effectiveWorkingDir)
 	queue, err := state.LoadQueue(stateDir)
 	if err == nil && !queue.IsEmpty() {
 		queuedFiles, popErr := queue.Pop()
@@ -116,7 +170,7 @@ func runContinue(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to generate session ID: %w", err)
 		}
-		st = state.NewState(sessID, wd, files, "", nil)
+		st = state.NewState(sessID, effectiveWorkingDir, files, "", nil)
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Starting new session %s with %d file(s)...\n", sessID, len(files))
 	} else {
 		// Update state with merged files
@@ -125,6 +179,11 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	}
 	_, _ = fmt.Fprintln(cmd.OutOrStdout())
 
+	// Defaults from the spec's front matter, below explicit flags
+	if err := applySpecDefaults(cmd, files[0], cmd.OutOrStdout()); err != nil {
+		return err
+	}
+
 	// Verbose is default, quiet suppresses it
 	verbose := !quiet
 
@@ -137,8 +196,10 @@ func runContinue(cmd *cobra.Command, args []string) error {
 		MaxIterations:              iterations,
 		CompletionPromise:          promise,
 		Model:                      model,
+		FallbackModels:             fallbackModels,
 		CheckerModel:               checkerModel,
 		MaxBudget:                  budget,
+		BudgetReserve:              budgetReserve,
 		WorkingDir:                 effectiveWorkingDir,
 		Verbose:                    verbose,
 		Debug:                      debug,
@@ -157,22 +218,6 @@ func runContinue(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration error: %w", err)
 	}
 
-	// Load optional config file
-	var fileConfig *config.FileConfig
-	if configFile != "" {
-		fileConfig, err = config.LoadFileConfigFrom(configFile)
-		if err != nil {
-			return fmt.Errorf("failed to load config file %s: %w", configFile, err)
-		}
-		if fileConfig == nil {
-			return fmt.Errorf("config file not found: %s", configFile)
-		}
-	} else {
-		fileConfig, err = config.LoadFileConfig(wd)
-		if err != nil {
-			return fmt.Errorf("failed to load config file: %w", err)
-		}
-	}
 	if fileConfig != nil && fileConfig.Prompt != "" {
 		spec.PromptTemplate = fileConfig.Prompt
 	}
@@ -183,11 +228,63 @@ func runContinue(cmd *cobra.Command, args []string) error {
 		cfg.DangerouslySkipPermissions = true
 	}
 
-	// Warn if dangerous mode is enabled
+	// Dangerous mode must be confirmed interactively or with --yes
 	if cfg.DangerouslySkipPermissions {
-		fmt.Fprintln(os.Stderr, "WARNING: Running with --dangerous flag. Claude can execute commands without permission prompts.")
+		if err := confirmDangerous(os.Stdin, os.Stderr, stdinInteractive(), assumeYes, effectiveWorkingDir); err != nil {
+			return err
+		}
+	}
+
+	// Extra Claude CLI flags: config file first, then --claude-arg
+	cfg.ClaudeArgs, err = resolveClaudeArgs(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Secrets are masked before any output is displayed or persisted
+	redactor, err := resolveRedactor(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Stall detection: [stall] first, then --stall-timeout and --stall-action
+	if err := resolveStall(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Max turns auto-tuning: [max_turns] first, then --max-turns-ceiling
+	if err := resolveTurnTuning(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Offline pause: [network] first, then --offline-timeout
+	if err := resolveOffline(cmd, cfg, fileConfig); err != nil {
+		return err
 	}
 
+	// What happens to prompts too large for the context window: [preflight]
+	if err := resolvePreflight(cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// State archive retention: [archive] first, then --archive-keep
+	if err := resolveArchive(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Operator instructions dropped here reach the next iteration
+	cfg.InjectFile = resolveInjectFile(effectiveWorkingDir)
+	cfg.InterjectFile = filepath.Join(effectiveWorkingDir, loop.DefaultInterjectFile)
+	cfg.LeanPrompts = leanPrompts
+	cfg.OptimisticVerify = optimisticVerify
+	if cfg.BudgetWarnings, err = resolveBudgetWarnings(fileConfig); err != nil {
+		return err
+	}
+	if doubleCheck {
+		cfg.DoubleCheckModel = doubleCheckModel
+	}
+	cfg.TickSpec = tickSpec
+
 	// Set completion promise for prompt template
 	spec.CompletionPromise = cfg.CompletionPromise
 
@@ -242,6 +339,13 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	} else {
 		cfg.SystemPrompt = spec.BuildSystemPrompt()
 	}
+	facts := loadMemory(fileConfig, effectiveWorkingDir)
+	cfg.SystemPrompt += memory.FormatSystemPrompt(facts)
+
+	// Refresh the copies of context files from outside the working directory
+	if _, err := stageExternalContext(effectiveWorkingDir, st.ContextFiles); err != nil {
+		return err
+	}
 
 	// Validate spec files exist
 	sp, err := spec.Validate(files)
@@ -254,27 +358,114 @@ func runContinue(cmd *cobra.Command, args []string) error {
 
 	// Create executor with resume flag
 	exec := executor.New(cfg)
+	exec.SetStallCallback(stallReporter(nil))
+	exec.SetRedactor(redactor)
+
+	// Kill claude processes orphaned by a crashed run, then track our own
+	sweepOrphanedProcesses(effectiveWorkingDir)
+	exec.SetPIDDir(executor.PIDDir(effectiveWorkingDir))
+	exec.SetQuarantine(newQuarantine(effectiveWorkingDir, nil))
+	exec.SetPreflight(preflightSummariser(cfg, redactor), preflightReporter(nil))
+
+	// Version control backs snapshots and progress detection
+	repo, err := resolveVCS(fileConfig, effectiveWorkingDir)
+	if err != nil {
+		return err
+	}
+	warnNoVCS(os.Stderr, repo, effectiveWorkingDir)
+	warnEnvironmentChanged(os.Stderr, st.Environment, captureEnvironment(context.Background(), exec, repo, fileConfig))
+
+	// Rewind to the requested checkpoint before anything runs
+	if continueAt != "" {
+		cp, err := findCheckpoint(st, continueAt)
+		if err != nil {
+			return err
+		}
+		if err := rollbackSession(context.Background(), repo, st, cp.Iteration); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Rolled back to checkpoint %q (iteration %d)\n", cp.Name, cp.Iteration)
+	}
+
+	// A panic in the loop writes a diagnostic bundle with the recent output
+	report := newCrashReport(effectiveWorkingDir)
+	report.cfg = cfg
+	report.state = st
 
 	// Enable streaming output
 	if cfg.Debug {
-		exec.SetStreamWriter(os.Stdout)
+		exec.SetStreamWriter(io.MultiWriter(os.Stdout, report.output))
 	} else if cfg.Verbose || cfg.ShowUnhandled || todosOnly {
-		streamProcessor := output.NewStreamProcessor(os.Stdout)
+		streamProcessor := output.NewStreamProcessor(io.MultiWriter(os.Stdout, report.output))
 		if cfg.ShowUnhandled {
 			streamProcessor.SetShowUnhandled(true)
 		}
 		if todosOnly {
 			streamProcessor.SetTodosOnly(true)
 		}
+		thinkingMode, thinkingLog, err := openThinkingLog(fileConfig, effectiveWorkingDir)
+		if err != nil {
+			return err
+		}
+		if thinkingLog != nil {
+			defer func() { _ = thinkingLog.Close() }()
+		}
+		streamProcessor.SetThinking(thinkingMode, thinkingLog)
+		sanitizeMode, err := resolveSanitize(fileConfig)
+		if err != nil {
+			return err
+		}
+		streamProcessor.SetSanitize(sanitizeMode)
 		exec.SetStreamWriter(streamProcessor)
 	}
 
+	// The agent's task list is mirrored to a GitHub issue after each iteration
+	mirror := newIssueMirror(fileConfig, st, effectiveWorkingDir, st.ActiveFiles)
+	if mirror != nil {
+		exec.AddStreamWriter(mirror)
+	}
+
+	// Shell commands the agent runs are kept for review with orbital audit
+	auditLog, err := audit.Open(runs.AuditLogPath(effectiveWorkingDir, sessID), effectiveWorkingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		exec.AddStreamWriter(auditLog)
+	}
+
+	// Single keys show the status, pause, and stop
+	keys := newKeyControls(nil, time.Now())
+	defer keys.Close()
+
 	// Create loop controller
 	controller := loop.New(cfg, exec, detector)
+	verifier, err := newVerifier(fileConfig, cfg, effectiveWorkingDir)
+	if err != nil {
+		return err
+	}
+	controller.SetVerifier(verifier)
+	guard, err := newProgressGuard(fileConfig)
+	if err != nil {
+		return err
+	}
+	controller.SetProgressGuard(guard, treeStateFunc(repo))
+	costs, err := newCostMonitor(fileConfig)
+	if err != nil {
+		return err
+	}
+	controller.SetCostMonitor(costs, func(a loop.CostAnomaly) bool {
+		return confirmCostAnomaly(context.Background(), a, nil, keys)
+	})
+	controller.SetTurnTuner(newTurnTuner(cfg))
+	controller.SetOfflineWaiter(newOfflineWaiter(cfg, st, nil))
+	controller.SetInterjectionCallback(interjectionRecorder(runs.EventLogPath(effectiveWorkingDir, st.SessionID)))
 
 	// Update state with new PID
 	st.PID = os.Getpid()
 	st.StartedAt = time.Now()
+	if cfg.DangerouslySkipPermissions {
+		st.Unconstrained = true
+	}
 	if err := st.Save(); err != nil {
 		return fmt.Errorf("failed to update state: %w", err)
 	}
@@ -286,9 +477,39 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	}
 	controller.SetStateManager(sm)
 
+	// Warn as spend passes each --budget-warn threshold
+	budgetWarner := loop.NewBudgetWarner(cfg.BudgetWarnings)
+	notifier := newNotifier(fileConfig)
+	notes := spec.NewNotes(spec.NotesFile)
+
+	// Quiet and CI runs still show where the session is
+	progress := newProgressLine(progressMode, nil, time.Now())
+	var spent float64
+	controller.SetIterationStartCallback(func(iteration, maxIterations int) {
+		status := output.ProgressStatus{Iteration: iteration, MaxIterations: maxIterations, Cost: spent, Budget: cfg.MaxBudget}
+		progress.Update(status)
+		keys.Update(status)
+		// Hold while the operator has paused the session from the keyboard
+		keys.Wait()
+	})
+
 	// Set iteration callback to update state after each iteration
 	controller.SetIterationCallback(func(iteration int, totalCost float64, totalTokensIn, totalTokensOut int) error {
-		return updateState(st, iteration, totalCost)
+		spent = totalCost
+		status := output.ProgressStatus{Iteration: iteration, MaxIterations: cfg.MaxIterations, Cost: totalCost, Budget: cfg.MaxBudget}
+		progress.Update(status)
+		keys.Update(status)
+		syncIssueMirror(context.Background(), mirror, st, iteration, totalCost, nil)
+		if err := updateState(st, iteration, totalCost); err != nil {
+			return err
+		}
+		if err := snapshotIteration(repo, st, iteration); err != nil {
+			reportSnapshotError(err, nil)
+		}
+		if w := budgetWarner.Observe(totalCost, cfg.MaxBudget); w != nil {
+			reportBudgetWarning(*w, iteration, notes, notifier, nil)
+		}
+		return nil
 	})
 
 	// Resolve workflow from flag or config
@@ -301,7 +522,7 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	formatter := output.NewFormatter(cfg.Verbose, quiet, os.Stdout)
 
 	// Print banner with config summary (use context files from state if available)
-	printBanner(formatter, cfg, sp, st.ContextFiles, wf)
+	printBanner(formatter, cfg, sp, st.ContextFiles, nil, wf)
 
 	// Build the prompt
 	prompt := sp.BuildPrompt()
@@ -316,12 +537,45 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	// Create context with signal handling for graceful shutdown
 	ctx, cancel := setupSignalHandler()
 	defer cancel()
+	report.cancel = cancel
+	defer report.handlePanic()
+	ctx = keys.Attach(ctx)
 
 	// Run the loop
+	progress.Start()
 	loopState, err := controller.Run(ctx, prompt)
+	progress.Stop()
+	keys.Close()
+	notifyRunEnd(notifier, loopState, err)
 
 	// Print summary
-	printSummary(formatter, loopState, sessID)
+	if loopState != nil {
+		loopState.Redactions = redactor.Summary()
+	}
+	printSummary(formatter, loopState, sessID, loadRunRecord(effectiveWorkingDir, sessID))
+
+	// Record commands still running when the session stopped
+	if closeErr := auditLog.Close(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close audit trail: %v\n", closeErr)
+	}
+
+	// Add what the session learned about the project to the shared memory
+	if memoryEnabled(fileConfig) {
+		memCost, memErr := updateMemory(os.Stdout, cfg, redactor, effectiveWorkingDir, files, spec.NotesFile, facts, err)
+		if memErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update memory: %v\n", memErr)
+		}
+		if loopState != nil {
+			loopState.TotalCost += memCost
+		}
+	}
+
+	// Turn a run that ran out of iterations or budget into a next run
+	if followUpEnabled(fileConfig) {
+		if _, fuErr := writeFollowUp(os.Stdout, effectiveWorkingDir, files[0], spec.NotesFile, loopState, "", err, time.Now()); fuErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write follow-up spec: %v\n", fuErr)
+		}
+	}
 
 	// Handle state cleanup or preservation
 	if err != nil {
@@ -333,6 +587,8 @@ func runContinue(cmd *cobra.Command, args []string) error {
 			os.Exit(2)
 		case errors.Is(err, context.DeadlineExceeded):
 			os.Exit(3)
+		case errors.Is(err, loop.ErrNoProgress):
+			os.Exit(5)
 		case errors.Is(err, context.Canceled):
 			// Summary already printed above with resume instructions
 			os.Exit(130)
@@ -342,7 +598,7 @@ func runContinue(cmd *cobra.Command, args []string) error {
 	}
 
 	// On successful completion, clean up state
-	if err := cleanupState(st); err != nil {
+	if err := cleanupState(repo, st, cfg.ArchiveKeep); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to cleanup state: %v\n", err)
 	}
 
@@ -413,15 +669,87 @@ func selectSession(sessions []session.Session, validator sessionValidator, theme
 	return result.Session, result.CleanupPaths, nil
 }
 
-// formatSessionList formats sessions for display in error messages.
+// findSession returns the resumable session whose ID is id or starts with it.
+func findSession(sessions []session.Session, id string) (*session.Session, error) {
+	var matches []session.Session
+	for _, s := range sessions {
+		if s.ID == id {
+			matches = []session.Session{s}
+			break
+		}
+		if s.ID != "" && strings.HasPrefix(s.ID, id) {
+			matches = append(matches, s)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("no session with ID %q; sessions:\n%s", id, formatSessionList(sessions))
+	case len(matches) > 1:
+		return nil, fmt.Errorf("session ID %q is ambiguous; sessions:\n%s", id, formatSessionList(matches))
+	case !matches[0].Valid:
+		return nil, fmt.Errorf("session %s cannot be resumed: %s", matches[0].ID, matches[0].InvalidReason)
+	}
+	return &matches[0], nil
+}
+
+// formatSessionList formats sessions for display in error messages and
+// --list: one line per session with its status, then its ID, specs, age,
+// iteration and cost.
 func formatSessionList(sessions []session.Session) string {
 	var result string
 	for i, s := range sessions {
-		status := "valid"
+		status := s.Status()
 		if !s.Valid {
 			status = "invalid: " + s.InvalidReason
 		}
 		result += fmt.Sprintf("  [%d] %s - %s\n", i+1, s.DisplayName(), status)
+		if s.ID != "" {
+			result += fmt.Sprintf("      ID:        %s\n", s.ID)
+		}
+		if s.Type == session.SessionTypeWorktree {
+			where := s.Path()
+			if s.Branch() != "" {
+				where += " (" + s.Branch() + ")"
+			}
+			result += fmt.Sprintf("      Worktree:  %s\n", where)
+		}
+		if len(s.SpecFiles) > 0 {
+			result += fmt.Sprintf("      Specs:     %s\n", strings.Join(s.SpecFiles, ", "))
+		}
+		if s.RegularState != nil {
+			result += fmt.Sprintf("      Progress:  iteration %d, %s %s, last active %s ago (started %s ago)\n",
+				s.Iteration(), util.FormatCost(s.Cost(), 2), util.CurrencyCode(),
+				formatDuration(time.Since(s.LastActive())), formatDuration(time.Since(s.CreatedAt)))
+		}
 	}
 	return result
 }
+
+// notifyRunEnd sends the completion or gate-failed notification for the
+// end of a resumed run, as a fresh run sends them from its loop.
+func notifyRunEnd(notifier *notify.Notifier, loopState *loop.LoopState, err error) {
+	switch {
+	case loopState != nil && loopState.Completed:
+		notifyCompleted(notifier, loopState, nil)
+	case errors.Is(err, workflow.ErrMaxGateRetriesExceeded):
+		if nerr := notifier.Notify(notify.EventGateFailed, "Orbital gate failed", err.Error()); nerr != nil {
+			reportNotifyError(nerr, nil)
+		}
+	}
+}
+
+// loadRunRecord returns the run record of the session being resumed, which
+// the summary diagnoses an unfinished run from, or nil when the session has
+// none.
+func loadRunRecord(workingDir, sessionID string) *runs.Record {
+	path := filepath.Join(runs.Dir(workingDir), sessionID+".json")
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	rec, err := runs.Load(workingDir, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load run record for diagnosis: %v\n", err)
+		return nil
+	}
+	return rec
+}
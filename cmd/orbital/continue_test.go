@@ -2,12 +2,18 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/runs"
 	"github.com/flashingpumpkin/orbital/internal/session"
 	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
 func TestRunContinue_NoState(t *testing.T) {
@@ -335,3 +341,162 @@ type mockCollector struct {
 func (m *mockCollector) ValidSessions(sessions []session.Session) []session.Session {
 	return m.validSessions
 }
+
+func TestFindSession(t *testing.T) {
+	sessions := []session.Session{
+		{ID: "abc123", Name: "Main session", Valid: true, RegularState: &state.State{}},
+		{ID: "abd456", Type: session.SessionTypeWorktree, Name: "Worktree feature", Valid: true, RegularState: &state.State{}},
+		{ID: "xyz789", Type: session.SessionTypeWorktree, Name: "Worktree busy", Valid: false, InvalidReason: "Session is currently running"},
+	}
+
+	tests := []struct {
+		id      string
+		want    string
+		wantErr string
+	}{
+		{id: "abc123", want: "abc123"},
+		{id: "abd", want: "abd456"},
+		{id: "ab", wantErr: "ambiguous"},
+		{id: "nope", wantErr: "no session with ID"},
+		{id: "xyz", wantErr: "currently running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := findSession(sessions, tt.id)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("findSession(%q) error = %v, want %q", tt.id, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findSession(%q) error = %v", tt.id, err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("findSession(%q) = %s, want %s", tt.id, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSessionList_Details(t *testing.T) {
+	sessions := []session.Session{
+		{
+			ID:             "abc123",
+			Type:           session.SessionTypeWorktree,
+			Name:           "Worktree feature",
+			SpecFiles:      []string{"docs/plans/auth.md"},
+			CreatedAt:      time.Now().Add(-3 * time.Hour),
+			Valid:          true,
+			RegularState:   &state.State{WorkingDir: "/repo-feature", Iteration: 6, TotalCost: 1.5},
+			WorktreeBranch: "feature",
+		},
+	}
+
+	result := formatSessionList(sessions)
+	for _, want := range []string{"[1] Worktree feature - interrupted", "ID:        abc123", "/repo-feature (feature)", "docs/plans/auth.md", "iteration 6", "$1.50", "(started 3h ago)"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("formatSessionList() lacks %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestRunContinue_List(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalWd); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	st := state.NewState("list-session", tempDir, []string{"/path/spec.md"}, "", nil)
+	st.PID = 99999999 // not running
+	st.Iteration = 3
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	cmd := newContinueCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--list"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("continue --list error = %v", err)
+	}
+	for _, want := range []string{"Main session - interrupted", "list-session", "/path/spec.md", "iteration 3"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("continue --list output lacks %q:\n%s", want, out.String())
+		}
+	}
+	if !state.Exists(tempDir) {
+		t.Error("continue --list removed the session state")
+	}
+}
+
+func TestNotifyRunEnd(t *testing.T) {
+	gateErr := fmt.Errorf("%w: step %q failed 3 times", workflow.ErrMaxGateRetriesExceeded, "review")
+	tests := []struct {
+		name  string
+		state *loop.LoopState
+		err   error
+		want  string
+	}{
+		{"completed", &loop.LoopState{Completed: true, Iteration: 4, TotalCost: 1.5}, nil, "Session complete after 4 iteration(s), $1.50 spent"},
+		{"gate failed", &loop.LoopState{}, gateErr, `step "review" failed 3 times`},
+		{"stopped", &loop.LoopState{}, loop.ErrMaxIterationsReached, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			notifier := notify.New(notify.Config{Desktop: true})
+			notifier.SetCommandRunner(func(name string, args ...string) error {
+				sent = append(sent, strings.Join(args, " "))
+				return nil
+			})
+
+			notifyRunEnd(notifier, tt.state, tt.err)
+
+			if tt.want == "" {
+				if len(sent) != 0 {
+					t.Errorf("notifications = %q, want none", sent)
+				}
+			} else if len(sent) != 1 || !strings.Contains(sent[0], tt.want) {
+				t.Errorf("notifications = %q, want one containing %q", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRunRecord(t *testing.T) {
+	dir := t.TempDir()
+	if rec := loadRunRecord(dir, "abc123"); rec != nil {
+		t.Fatalf("loadRunRecord() = %+v without a record, want nil", rec)
+	}
+
+	rec := runs.NewRecord("abc123", nil, "spec-driven", "opus")
+	rec.AddUnchanged(2)
+	rec.AddUnchanged(3)
+	if err := rec.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	// A session whose ID starts with the resumed one's is not mistaken for it
+	if err := runs.NewRecord("abc1234", nil, "spec-driven", "opus").Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadRunRecord(dir, "abc123")
+	if got == nil {
+		t.Fatal("loadRunRecord() = nil, want the saved record")
+	}
+	if findings := runs.Diagnose(got); len(findings) == 0 || !strings.Contains(findings[0], "2 iterations made no repository changes") {
+		t.Errorf("Diagnose() = %q, want the unchanged iterations", findings)
+	}
+}
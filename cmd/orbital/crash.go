@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	runtimedebug "runtime/debug"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/crash"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// crashExitCode is the exit code after a crash, matching other errors.
+const crashExitCode = 4
+
+// tuiRestoreTimeout bounds how long a crash waits for the TUI to restore the
+// terminal before writing the bundle anyway.
+const tuiRestoreTimeout = 2 * time.Second
+
+// crashReport holds what a diagnostic bundle is built from. Fields are filled
+// in as the session starts; any may be nil.
+type crashReport struct {
+	workingDir string
+	cfg        *config.Config
+	state      *state.State
+	output     *crash.Recorder
+	tui        *tui.Program
+	cancel     func()
+	stderr     io.Writer
+}
+
+// newCrashReport creates a report for a session in workingDir that records
+// recent output.
+func newCrashReport(workingDir string) *crashReport {
+	return &crashReport{
+		workingDir: workingDir,
+		output:     crash.NewRecorder(crash.OutputLines),
+		stderr:     os.Stderr,
+	}
+}
+
+// handlePanic recovers a panic in the main loop, restores the terminal,
+// writes a diagnostic bundle and exits. It must be called directly by defer.
+func (c *crashReport) handlePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := runtimedebug.Stack()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.restoreTerminal()
+	c.report(r, stack)
+	os.Exit(crashExitCode)
+}
+
+// restoreTerminal stops the TUI so the terminal leaves the alternate screen
+// and raw mode before anything is printed.
+func (c *crashReport) restoreTerminal() {
+	if c.tui == nil {
+		return
+	}
+	c.tui.Kill()
+	done := make(chan struct{})
+	go func() {
+		c.tui.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(tuiRestoreTimeout):
+	}
+}
+
+// report writes the diagnostic bundle and prints where it is. When the
+// bundle cannot be written the stack trace is printed instead.
+func (c *crashReport) report(value any, stack []byte) {
+	b := crash.Bundle{Panic: value, Stack: stack, Output: c.output.Lines()}
+	if c.cfg != nil {
+		b.Config = c.cfg
+	}
+	if c.state != nil {
+		b.State = c.state
+	}
+
+	_, _ = fmt.Fprintf(c.stderr, "\norbital crashed: %v\n", value)
+	path, err := crash.Write(crash.Dir(c.workingDir), b)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Failed to write diagnostic bundle: %v\n%s", err, stack)
+		return
+	}
+	_, _ = fmt.Fprintf(c.stderr, "Diagnostic bundle written to %s\n", path)
+	if c.state != nil {
+		_, _ = fmt.Fprintln(c.stderr, "Run 'orbital continue' to resume the session.")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/crash"
+	"github.com/flashingpumpkin/orbital/internal/state"
+)
+
+func TestCrashReport_WritesBundle(t *testing.T) {
+	dir := t.TempDir()
+	var stderr bytes.Buffer
+	report := newCrashReport(dir)
+	report.stderr = &stderr
+	report.cfg = &config.Config{Model: "opus", ClaudeArgs: []string{"--api-key=sk-secret"}}
+	report.state = state.NewState("abc123", dir, []string{"spec.md"}, "notes.md", nil)
+	report.output.Record("last words")
+
+	report.report("nil map write", []byte("goroutine 1 [running]:\n"))
+
+	out := stderr.String()
+	if !strings.Contains(out, "orbital crashed: nil map write") || !strings.Contains(out, "orbital continue") {
+		t.Errorf("stderr = %q", out)
+	}
+	bundles, err := os.ReadDir(crash.Dir(dir))
+	if err != nil || len(bundles) != 1 {
+		t.Fatalf("crash dir entries = %v, err = %v", bundles, err)
+	}
+	path := filepath.Join(crash.Dir(dir), bundles[0].Name())
+	if !strings.Contains(out, path) {
+		t.Errorf("stderr does not name the bundle %s: %q", path, out)
+	}
+
+	config, err := os.ReadFile(filepath.Join(path, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(config), "sk-secret") || !strings.Contains(string(config), `"opus"`) {
+		t.Errorf("config.json = %s", config)
+	}
+	for name, want := range map[string]string{"output.log": "last words", "state.json": "abc123"} {
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil || !strings.Contains(string(data), want) {
+			t.Errorf("%s = %q (err %v), want it to contain %q", name, data, err, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// errDangerousNotConfirmed is returned when the user declines dangerous mode.
+var errDangerousNotConfirmed = errors.New("dangerous mode not confirmed")
+
+// dangerousWarning explains what dangerous mode allows. The placeholder is the
+// working directory.
+const dangerousWarning = `WARNING: dangerous mode is enabled (--dangerous or dangerous = true in config).
+
+Claude will run with --dangerously-skip-permissions and will NOT ask before it:
+  - runs any shell command, including ones that delete files or push to remotes
+  - reads, writes, or deletes any file your user can access, not just in %s
+  - makes network requests and installs packages
+
+Only continue in a sandbox, container, or disposable checkout.
+`
+
+// confirmDangerous asks the user to confirm dangerous mode before the run
+// starts. With skipConfirm (--yes) it only prints a one-line warning. Without
+// an interactive terminal it fails, since nobody can confirm.
+func confirmDangerous(in io.Reader, out io.Writer, interactive, skipConfirm bool, dir string) error {
+	if skipConfirm {
+		_, _ = fmt.Fprintln(out, "WARNING: Running with --dangerous flag. Claude can execute commands without permission prompts.")
+		return nil
+	}
+	if !interactive {
+		return fmt.Errorf("%w: pass --yes to run dangerous mode without a terminal", errDangerousNotConfirmed)
+	}
+
+	_, _ = fmt.Fprintf(out, dangerousWarning, dir)
+	_, _ = fmt.Fprint(out, "\nContinue without permission prompts? [y/N]: ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return fmt.Errorf("%w: %v", errDangerousNotConfirmed, err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errDangerousNotConfirmed
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfirmDangerous(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		skipConfirm bool
+		wantErr     bool
+		wantOutput  string
+	}{
+		{name: "yes flag skips prompt", skipConfirm: true, wantOutput: "WARNING: Running with --dangerous"},
+		{name: "non-interactive without yes fails", wantErr: true},
+		{name: "confirmed", input: "y\n", interactive: true, wantOutput: "--dangerously-skip-permissions"},
+		{name: "confirmed in full", input: " YES \n", interactive: true},
+		{name: "declined", input: "n\n", interactive: true, wantErr: true},
+		{name: "empty answer declines", input: "\n", interactive: true, wantErr: true},
+		{name: "closed stdin declines", input: "", interactive: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := confirmDangerous(strings.NewReader(tt.input), &out, tt.interactive, tt.skipConfirm, "/work")
+
+			if tt.wantErr {
+				if !errors.Is(err, errDangerousNotConfirmed) {
+					t.Errorf("confirmDangerous() error = %v, want errDangerousNotConfirmed", err)
+				}
+			} else if err != nil {
+				t.Errorf("confirmDangerous() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestConfirmDangerous_NonInteractiveMentionsYes(t *testing.T) {
+	err := confirmDangerous(strings.NewReader(""), &bytes.Buffer{}, false, false, "/work")
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("confirmDangerous() error = %v, want hint about --yes", err)
+	}
+}
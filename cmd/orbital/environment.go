@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/environment"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
+)
+
+// captureEnvironment snapshots the environment a session runs in. Parts
+// that cannot be determined, such as the Claude CLI version when claude
+// cannot be run, are left empty.
+func captureEnvironment(ctx context.Context, exec *executor.Executor, repo vcs.Backend, fileConfig *config.FileConfig) *environment.Snapshot {
+	claude, _ := exec.ClaudeVersion(ctx)
+	commit, _ := repo.Head(ctx)
+	return environment.Capture(version, claude, commit, environment.HashConfig(fileConfig))
+}
+
+// warnEnvironmentChanged tells the user which parts of the environment
+// differ from when the session started, since a change of orbital, claude or
+// config mid-session can explain a change in behaviour.
+func warnEnvironmentChanged(w io.Writer, started, now *environment.Snapshot) {
+	if diffs := environment.Diff(started, now); len(diffs) > 0 {
+		_, _ = fmt.Fprintf(w, "Warning: the environment changed since the session started (%s)\n", strings.Join(diffs, ", "))
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/environment"
+)
+
+func TestWarnEnvironmentChanged(t *testing.T) {
+	started := &environment.Snapshot{Orbital: "0.1.0", Claude: "2.0.14", OS: "linux/amd64"}
+
+	var buf bytes.Buffer
+	warnEnvironmentChanged(&buf, started, &environment.Snapshot{Orbital: "0.2.0", Claude: "2.0.14", OS: "linux/amd64"})
+	if got, want := buf.String(), "Warning: the environment changed since the session started (orbital: 0.1.0 -> 0.2.0)\n"; got != want {
+		t.Errorf("warning = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	warnEnvironmentChanged(&buf, started, started)
+	warnEnvironmentChanged(&buf, nil, started)
+	if buf.Len() != 0 {
+		t.Errorf("warned %q without a change to compare", buf.String())
+	}
+}
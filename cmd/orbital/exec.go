@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var execJSON bool
+
+var execCmd = newExecCmd()
+
+func newExecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec [prompt]",
+		Short: "Run a single prompt through the executor",
+		Long: `Send one prompt to Claude with the same executor configuration a run
+uses (model, budget, working directory, agents, dangerous mode, Claude
+arguments and stall detection) and print the result.
+
+There is no loop: no spec, state, notes file, workflow or verification.
+The result text goes to stdout and the stats to stderr, so the output can be
+piped. Progress is streamed to stderr unless --quiet is set; --debug streams
+the raw JSON instead.
+
+With no prompt argument, or "-", the prompt is read from stdin.`,
+		Example: `  orbital exec "Summarise the open TODOs in internal/"
+  git diff | orbital exec --model haiku --budget 0.50
+  orbital exec --json "List the exported functions in main.go" | jq -r .result`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runExec,
+	}
+	cmd.Flags().BoolVar(&execJSON, "json", false, "Print the result and stats as JSON on stdout")
+	return cmd
+}
+
+// execOutput is the JSON printed by exec --json.
+type execOutput struct {
+	Result     string  `json:"result"`
+	Model      string  `json:"model"`
+	ExitCode   int     `json:"exit_code"`
+	DurationMS int64   `json:"duration_ms"`
+	TokensIn   int     `json:"tokens_in"`
+	TokensOut  int     `json:"tokens_out"`
+	CostUSD    float64 `json:"cost_usd"`
+	Redactions string  `json:"redactions,omitempty"`
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	prompt, err := readExecPrompt(args, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	cfg := &config.Config{
+		Model:                      model,
+		MaxBudget:                  budget,
+		WorkingDir:                 workingDir,
+		Verbose:                    !quiet,
+		Debug:                      debug,
+		SessionID:                  sessionID,
+		IterationTimeout:           timeout,
+		MaxTurns:                   maxTurns,
+		DangerouslySkipPermissions: dangerous,
+		MaxOutputSize:              maxOutputSize,
+		SystemPrompt:               systemPrompt,
+	}
+
+	fileConfig, err := loadFileConfig(workingDir)
+	if err != nil {
+		return err
+	}
+	if err := setupDisplay(fileConfig); err != nil {
+		return err
+	}
+	if err := setupExecutor(workingDir); err != nil {
+		return err
+	}
+	if !dangerous && fileConfig != nil && fileConfig.Dangerous {
+		cfg.DangerouslySkipPermissions = true
+	}
+	if cfg.DangerouslySkipPermissions {
+		if err := confirmDangerous(os.Stdin, os.Stderr, stdinInteractive(), assumeYes, workingDir); err != nil {
+			return err
+		}
+	}
+	if cfg.ClaudeArgs, err = resolveClaudeArgs(fileConfig); err != nil {
+		return err
+	}
+	if err := resolveStall(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+	if err := resolvePreflight(cfg, fileConfig); err != nil {
+		return err
+	}
+	if cfg.Agents, err = resolveAgents(fileConfig); err != nil {
+		return err
+	}
+	redactor, err := resolveRedactor(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	exec := executor.New(cfg)
+	exec.SetRedactor(redactor)
+	exec.SetPreflight(preflightSummariser(cfg, redactor), preflightReporter(nil))
+	switch {
+	case cfg.Debug:
+		exec.SetStreamWriter(os.Stderr)
+	case cfg.Verbose:
+		exec.SetStreamWriter(output.NewStreamProcessor(os.Stderr))
+	}
+
+	ctx, cancel := setupSignalHandler()
+	defer cancel()
+	if cfg.IterationTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, cfg.IterationTimeout)
+		defer cancelTimeout()
+	}
+
+	result, err := exec.Execute(ctx, prompt)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	text := output.ExtractResult(result.Output)
+	if execJSON {
+		data, err := json.MarshalIndent(execOutput{
+			Result:     text,
+			Model:      exec.Model(),
+			ExitCode:   result.ExitCode,
+			DurationMS: result.Duration.Milliseconds(),
+			TokensIn:   result.TokensIn,
+			TokensOut:  result.TokensOut,
+			CostUSD:    result.CostUSD,
+			Redactions: redactor.Summary(),
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal result: %w", err)
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	} else {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(text, "\n"))
+		if !quiet {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), formatExecStats(exec.Model(), result))
+			if redactions := redactor.Summary(); redactions != "" {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Redacted: "+redactions)
+			}
+		}
+	}
+
+	if result.ExitCode != 0 {
+		return fmt.Errorf("claude exited with code %d", result.ExitCode)
+	}
+	return nil
+}
+
+// readExecPrompt returns the prompt from args, or from in when there is no
+// argument or the argument is "-".
+func readExecPrompt(args []string, in io.Reader) (string, error) {
+	if len(args) == 1 && args[0] != "-" {
+		if strings.TrimSpace(args[0]) == "" {
+			return "", fmt.Errorf("prompt is empty")
+		}
+		return args[0], nil
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt from stdin: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("prompt from stdin is empty")
+	}
+	return string(data), nil
+}
+
+// formatExecStats renders the stats line printed after an exec result.
+func formatExecStats(model string, result *executor.ExecutionResult) string {
+	parts := []string{
+		"Model: " + model,
+		"Duration: " + result.Duration.Round(100*time.Millisecond).String(),
+		fmt.Sprintf("Tokens: %d in / %d out", result.TokensIn, result.TokensOut),
+		fmt.Sprintf("Cost: %s %s", util.FormatCost(result.CostUSD, 4), util.CurrencyCode()),
+	}
+	if resources := output.FormatResources(result.CPUTime, result.PeakRSS); resources != "" {
+		parts = append(parts, "Resources: "+resources)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/executor"
+)
+
+func TestReadExecPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "argument", args: []string{"fix the build"}, stdin: "ignored", want: "fix the build"},
+		{name: "stdin without argument", stdin: "from stdin\n", want: "from stdin\n"},
+		{name: "dash reads stdin", args: []string{"-"}, stdin: "piped", want: "piped"},
+		{name: "empty argument", args: []string{"  "}, wantErr: "prompt is empty"},
+		{name: "empty stdin", stdin: "\n", wantErr: "prompt from stdin is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readExecPrompt(tt.args, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readExecPrompt() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readExecPrompt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readExecPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatExecStats(t *testing.T) {
+	got := formatExecStats("opus", &executor.ExecutionResult{
+		Duration:  12340 * time.Millisecond,
+		TokensIn:  1200,
+		TokensOut: 340,
+		CostUSD:   0.1234,
+	})
+	for _, want := range []string{"Model: opus", "Duration: 12.3s", "Tokens: 1200 in / 340 out", "Cost: $0.1234"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatExecStats() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "Resources") {
+		t.Errorf("formatExecStats() = %q, want no resources without usage", got)
+	}
+}
+
+func TestExecCmd_RejectsExtraArgs(t *testing.T) {
+	cmd := newExecCmd()
+	if err := cmd.Args(cmd, []string{"one", "two"}); err == nil {
+		t.Error("Args() accepted two prompts, want an error")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// externalContextDir is where context files from outside the working
+// directory are copied, relative to it. Claude's tools only reach files in
+// the working directory, and working on copies leaves the originals alone.
+const externalContextDir = ".orbital/context"
+
+// stageExternalContext copies the context files outside dir into
+// dir/.orbital/context and returns files with each of them replaced by the
+// absolute path of its copy. Copies are named after the original's path, so
+// staging the same files again refreshes them. Files that do not exist are
+// left for spec validation to report.
+func stageExternalContext(dir string, files []string) ([]string, error) {
+	staged := make([]string, len(files))
+	for i, file := range files {
+		src, external := externalFile(dir, file)
+		if !external {
+			staged[i] = file
+			continue
+		}
+		data, err := os.ReadFile(src)
+		if errors.Is(err, fs.ErrNotExist) {
+			staged[i] = file
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read context file %s: %w", file, err)
+		}
+		dst, err := filepath.Abs(filepath.Join(dir, externalContextDir, externalCopyName(src)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve copy of context file %s: %w", file, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", externalContextDir, err)
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to copy context file %s: %w", file, err)
+		}
+		staged[i] = dst
+	}
+	return staged, nil
+}
+
+// externalFile returns the absolute path of file and whether it lies outside
+// dir. A symlink in dir pointing outside it counts as outside, since edits
+// through it would change the file it points to.
+func externalFile(dir, file string) (string, bool) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return file, false
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return abs, false
+	}
+	resolved := abs
+	if r, err := filepath.EvalSymlinks(abs); err == nil {
+		resolved = r
+	}
+	if r, err := filepath.EvalSymlinks(root); err == nil {
+		root = r
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return abs, err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// externalCopyName names the copy of the file at abs: its base name behind a
+// hash of its path, so files of the same name from different places do not
+// overwrite each other.
+func externalCopyName(abs string) string {
+	sum := sha256.Sum256([]byte(abs))
+	return fmt.Sprintf("%x-%s", sum[:6], filepath.Base(abs))
+}
+
+// contextDisplayPaths returns files as they are shown to the user: files
+// outside dir by their absolute path, which names the project they come from,
+// and the others as given.
+func contextDisplayPaths(dir string, files []string) []string {
+	display := make([]string, len(files))
+	for i, file := range files {
+		if abs, external := externalFile(dir, file); external {
+			display[i] = abs
+		} else {
+			display[i] = file
+		}
+	}
+	return display
+}
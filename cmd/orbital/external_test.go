@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStageExternalContext(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	shared := filepath.Join(root, "shared-lib")
+	for _, dir := range []string{filepath.Join(project, "docs"), filepath.Join(shared, "docs")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inside := filepath.Join(project, "docs", "api.md")
+	outside := filepath.Join(shared, "README.md")
+	sameName := filepath.Join(shared, "docs", "README.md")
+	for _, f := range []string{inside, outside, sameName} {
+		if err := os.WriteFile(f, []byte("contents of "+f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A symlink in the project to a file outside it is outside too
+	link := filepath.Join(project, "docs", "linked.md")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(shared, "missing.md")
+
+	files := []string{inside, outside, sameName, link, missing}
+	staged, err := stageExternalContext(project, files)
+	if err != nil {
+		t.Fatalf("stageExternalContext() error = %v", err)
+	}
+
+	if staged[0] != inside || staged[4] != missing {
+		t.Errorf("staged = %v, want files inside the project and missing files unchanged", staged)
+	}
+	copyDir := filepath.Join(project, externalContextDir)
+	for i, src := range []string{outside, sameName, outside} {
+		dst := staged[i+1]
+		if filepath.Dir(dst) != copyDir || !strings.HasSuffix(dst, "-README.md") && !strings.HasSuffix(dst, "-linked.md") {
+			t.Errorf("copy of %s = %s, want a file in %s", files[i+1], dst, copyDir)
+		}
+		data, err := os.ReadFile(dst)
+		if err != nil || string(data) != "contents of "+src {
+			t.Errorf("copy of %s holds %q, %v; want the original's contents", files[i+1], data, err)
+		}
+	}
+	if staged[1] == staged[2] {
+		t.Error("files of the same name from different directories share a copy")
+	}
+
+	// Staging again refreshes the copies
+	if err := os.WriteFile(outside, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := stageExternalContext(project, files)
+	if err != nil {
+		t.Fatalf("stageExternalContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(again, staged) {
+		t.Errorf("restaged = %v, want the same copies %v", again, staged)
+	}
+	if data, _ := os.ReadFile(again[1]); string(data) != "edited" {
+		t.Errorf("refreshed copy holds %q, want the edited original", data)
+	}
+}
+
+func TestContextDisplayPaths(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+
+	got := contextDisplayPaths(project, []string{"docs/api.md", "../shared-lib/README.md"})
+	want := []string{"docs/api.md", filepath.Join(filepath.Dir(project), "shared-lib", "README.md")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contextDisplayPaths() = %v, want %v", got, want)
+	}
+}
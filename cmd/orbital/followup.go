@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// followUpEnabled reports whether --follow-up or follow_up in the config file
+// asks for follow-up specs.
+func followUpEnabled(fileConfig *config.FileConfig) bool {
+	return followUp || (fileConfig != nil && fileConfig.FollowUp)
+}
+
+// followUpReason describes a run error that leaves work for a follow-up
+// spec, or returns "" for any other outcome.
+func followUpReason(err error) string {
+	switch {
+	case errors.Is(err, loop.ErrMaxIterationsReached):
+		return "max iterations reached"
+	case errors.Is(err, loop.ErrBudgetExceeded):
+		return "budget exceeded"
+	default:
+		return ""
+	}
+}
+
+// writeFollowUp writes a follow-up spec to the spec directory under dir when
+// the run stopped on its iteration or budget limit with unchecked items left
+// in specFile, and prints the command that runs it. It returns the path
+// written, or "" when no follow-up was needed.
+func writeFollowUp(out io.Writer, dir, specFile, notesFile string, loopState *loop.LoopState, diffStat string, runErr error, now time.Time) (string, error) {
+	reason := followUpReason(runErr)
+	if reason == "" || loopState == nil {
+		return "", nil
+	}
+	remaining, err := spec.UncheckedItems([]string{specFile})
+	if err != nil || len(remaining) == 0 {
+		return "", err
+	}
+	done, err := spec.CheckedItems([]string{specFile})
+	if err != nil {
+		return "", err
+	}
+
+	f := spec.FollowUp{
+		Spec:       relativeTo(dir, specFile),
+		Reason:     reason,
+		Iterations: loopState.Iteration,
+		Cost:       util.FormatCost(loopState.TotalCost, 2) + " " + util.CurrencyCode(),
+		DiffStat:   diffStat,
+		NotesFile:  relativeTo(dir, notesFile),
+	}
+	path := spec.FollowUpPath(filepath.Join(dir, spec.DefaultSpecDir), specFile, now)
+	if err := spec.WriteNewSpec(path, spec.FollowUpContent(f, done, remaining, now)); err != nil {
+		return "", err
+	}
+
+	command := "orbital " + relativeTo(dir, path)
+	if f.NotesFile != "" {
+		command += " --notes " + f.NotesFile
+	}
+	_, _ = fmt.Fprintf(out, "\nFollow-up spec with the %d remaining item(s): %s\nRun it with:\n  %s\n", len(remaining), relativeTo(dir, path), command)
+	return path, nil
+}
+
+// relativeTo returns path relative to dir when it lies inside it.
+func relativeTo(dir, path string) string {
+	if path == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
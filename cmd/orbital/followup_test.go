@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/loop"
+)
+
+func TestWriteFollowUp(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	writeSpec := func(t *testing.T, dir string) string {
+		t.Helper()
+		path := filepath.Join(dir, "2026-03-01-auth.md")
+		content := "# Auth\n\n- [x] Add login\n- [ ] Add logout\n- [ ] Add sessions\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	state := &loop.LoopState{Iteration: 5, TotalCost: 3.5}
+
+	t.Run("writes remaining items when iterations run out", func(t *testing.T) {
+		dir := t.TempDir()
+		specFile := writeSpec(t, dir)
+		var out bytes.Buffer
+
+		path, err := writeFollowUp(&out, dir, specFile, filepath.Join(dir, "notes.md"), state, " a.go | 2 +-", loop.ErrMaxIterationsReached, now)
+		if err != nil {
+			t.Fatalf("writeFollowUp() error = %v", err)
+		}
+		want := filepath.Join(dir, "docs", "plans", "2026-03-04-continuation-of-auth.md")
+		if path != want {
+			t.Errorf("path = %q, want %q", path, want)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		for _, s := range []string{"- [ ] Add logout", "- [ ] Add sessions", "- Add login", "max iterations reached", "a.go | 2 +-"} {
+			if !strings.Contains(content, s) {
+				t.Errorf("follow-up spec lacks %q:\n%s", s, content)
+			}
+		}
+		if strings.Contains(content, "[x]") {
+			t.Errorf("follow-up spec contains checked items:\n%s", content)
+		}
+		if !strings.Contains(out.String(), "orbital docs/plans/2026-03-04-continuation-of-auth.md --notes notes.md") {
+			t.Errorf("output lacks the command to run the follow-up:\n%s", out.String())
+		}
+	})
+
+	t.Run("wraps budget errors", func(t *testing.T) {
+		dir := t.TempDir()
+		specFile := writeSpec(t, dir)
+		err := fmt.Errorf("iteration 3: %w", loop.ErrBudgetExceeded)
+		path, fErr := writeFollowUp(&bytes.Buffer{}, dir, specFile, "", state, "", err, now)
+		if fErr != nil || path == "" {
+			t.Fatalf("writeFollowUp() = %q, %v; want a follow-up spec", path, fErr)
+		}
+	})
+
+	for name, runErr := range map[string]error{
+		"success":     nil,
+		"interrupted": errors.New("context canceled"),
+	} {
+		t.Run("skips "+name, func(t *testing.T) {
+			dir := t.TempDir()
+			specFile := writeSpec(t, dir)
+			path, err := writeFollowUp(&bytes.Buffer{}, dir, specFile, "", state, "", runErr, now)
+			if err != nil || path != "" {
+				t.Errorf("writeFollowUp() = %q, %v; want no follow-up", path, err)
+			}
+		})
+	}
+
+	t.Run("skips when every item is done", func(t *testing.T) {
+		dir := t.TempDir()
+		specFile := filepath.Join(dir, "done.md")
+		if err := os.WriteFile(specFile, []byte("- [x] Everything\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		path, err := writeFollowUp(&bytes.Buffer{}, dir, specFile, "", state, "", loop.ErrMaxIterationsReached, now)
+		if err != nil || path != "" {
+			t.Errorf("writeFollowUp() = %q, %v; want no follow-up", path, err)
+		}
+	})
+}
+
+func TestRelativeTo(t *testing.T) {
+	tests := []struct {
+		dir, path, want string
+	}{
+		{"/repo", "/repo/docs/plans/a.md", filepath.Join("docs", "plans", "a.md")},
+		{"/repo", "/elsewhere/a.md", "/elsewhere/a.md"},
+		{"/repo", "", ""},
+	}
+	for _, tt := range tests {
+		if got := relativeTo(tt.dir, tt.path); got != tt.want {
+			t.Errorf("relativeTo(%q, %q) = %q, want %q", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+)
+
+// maxSeedCommits and maxSeedStatLines bound how much of the branch's history
+// and diff stat the first prompt carries.
+const (
+	maxSeedCommits   = 20
+	maxSeedStatLines = 40
+)
+
+// branchSeedPrompt is appended to the first iteration's prompts of a session
+// started with --from-branch.
+const branchSeedPrompt = `
+
+---
+EXISTING WORK: This session continues branch %s, where work has already
+started. Its commits since it left %s:
+%s
+
+Files changed on the branch:
+%s
+
+Review this work before changing it, and build on it rather than starting
+over.
+---
+`
+
+// applyFromBranch moves a new session into a worktree with --from-branch
+// checked out and returns the summary of the branch's work for the first
+// iteration's prompt. The process changes into the worktree, and the spec,
+// context and notes files given on the command line are taken from the
+// worktree where the branch has them. A project config missing from the
+// branch is still read from the original checkout. Without --from-branch it
+// does nothing.
+func applyFromBranch(ctx context.Context, args []string, out io.Writer) (string, error) {
+	if fromBranch == "" {
+		return "", nil
+	}
+	original, err := filepath.Abs(workingDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory: %w", err)
+	}
+	top, err := git.TopLevel(ctx, original)
+	if err != nil {
+		return "", fmt.Errorf("--from-branch needs a git repository: %w", err)
+	}
+	path, seed, err := startFromBranch(ctx, original, fromBranch, worktreeDir)
+	if err != nil {
+		return "", err
+	}
+
+	// Files inside the repository are used from the worktree
+	if len(args) > 0 && args[0] != "-" {
+		args[0] = worktreeFile(args[0], top, path, true)
+	}
+	for i, f := range contextFiles {
+		contextFiles[i] = worktreeFile(f, top, path, true)
+	}
+	if notesFile != "" {
+		notesFile = worktreeFile(notesFile, top, path, false)
+	}
+
+	if configFile == "" {
+		projectConfig := filepath.Join(original, ".orbital", "config.toml")
+		if _, err := os.Stat(filepath.Join(path, ".orbital", "config.toml")); os.IsNotExist(err) {
+			if _, err := os.Stat(projectConfig); err == nil {
+				configFile = projectConfig
+			}
+		}
+	}
+
+	if err := os.Chdir(path); err != nil {
+		return "", fmt.Errorf("failed to change into worktree: %w", err)
+	}
+	workingDir = path
+	fmt.Fprintf(out, "Continuing branch %s in new worktree %s\n", fromBranch, path)
+	return seed, nil
+}
+
+// worktreeFile returns file's counterpart in the worktree at tree when file
+// lies inside the repository at top (and, with mustExist, the worktree has
+// it), and file as an absolute path otherwise.
+func worktreeFile(file, top, tree string, mustExist bool) string {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return file
+	}
+	resolved := abs
+	if r, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		resolved = filepath.Join(r, filepath.Base(abs))
+	}
+	if realTop, err := filepath.EvalSymlinks(top); err == nil {
+		top = realTop
+	}
+	rel, err := filepath.Rel(top, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return abs
+	}
+	candidate := filepath.Join(tree, rel)
+	if _, err := os.Stat(candidate); err != nil && mustExist {
+		return abs
+	}
+	return candidate
+}
+
+// startFromBranch creates a worktree for branch and returns its path and the
+// prompt summarising the branch's work. The worktree goes to path, or when
+// empty next to the repository as <repo>-<branch>.
+func startFromBranch(ctx context.Context, dir, branch, path string) (string, string, error) {
+	if !git.IsRepo(ctx, dir) {
+		return "", "", fmt.Errorf("--from-branch needs a git repository: %s is not in one", dir)
+	}
+	work, err := git.SummariseBranch(ctx, dir, branch)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to summarise branch %s: %w", branch, err)
+	}
+
+	if path == "" {
+		top, err := git.TopLevel(ctx, dir)
+		if err != nil {
+			return "", "", err
+		}
+		path = filepath.Join(filepath.Dir(top), filepath.Base(top)+"-"+branchDirName(branch))
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", "", fmt.Errorf("worktree path %s already exists; choose another with --worktree-dir", path)
+	}
+	if err := git.AddWorktree(ctx, dir, path, branch); err != nil {
+		return "", "", fmt.Errorf("failed to create worktree for %s: %w", branch, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	return abs, formatBranchSeed(work), nil
+}
+
+// formatBranchSeed renders branchSeedPrompt for the branch's work, keeping
+// the latest commits and the head of the diff stat with its totals line.
+func formatBranchSeed(work *git.BranchWork) string {
+	commits := work.Commits
+	var lines []string
+	if len(commits) > maxSeedCommits {
+		lines = append(lines, fmt.Sprintf("- (%d earlier commits)", len(commits)-maxSeedCommits))
+		commits = commits[len(commits)-maxSeedCommits:]
+	}
+	for _, c := range commits {
+		lines = append(lines, "- "+c)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "- (none yet)")
+	}
+
+	stat := strings.Split(work.DiffStat, "\n")
+	if len(stat) > maxSeedStatLines {
+		omitted := len(stat) - maxSeedStatLines
+		stat = append(append(stat[:maxSeedStatLines-1:maxSeedStatLines-1], fmt.Sprintf(" ... %d more files", omitted)), stat[len(stat)-1])
+	}
+	diffStat := strings.Join(stat, "\n")
+	if work.DiffStat == "" {
+		diffStat = "(none)"
+	}
+
+	return fmt.Sprintf(branchSeedPrompt, work.Branch, work.Base, strings.Join(lines, "\n"), diffStat)
+}
+
+// branchDirName turns a branch name into a directory name suffix, e.g.
+// "feature/x" into "feature-x".
+func branchDirName(branch string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, branch)
+}
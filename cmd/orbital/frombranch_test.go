@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+)
+
+func TestApplyFromBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "spec.md"), []byte("# Spec\n\n- [ ] Build it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit := []string{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m"}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		append(commit, "init"),
+		{"checkout", "-q", "-b", "feature/x"},
+	} {
+		if _, err := git.Run(ctx, repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "parser.go"), []byte("package parser\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", "."}, append(commit, "Start the parser"), {"checkout", "-q", "-"}} {
+		if _, err := git.Run(ctx, repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An untracked project config stays in the original checkout
+	if err := os.MkdirAll(filepath.Join(repo, ".orbital"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, ".orbital", "config.toml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldBranch, oldDir, oldWorkingDir, oldConfig := fromBranch, worktreeDir, workingDir, configFile
+	defer func() { fromBranch, worktreeDir, workingDir, configFile = oldBranch, oldDir, oldWorkingDir, oldConfig }()
+	t.Chdir(repo)
+	fromBranch, worktreeDir, workingDir, configFile = "feature/x", "", ".", ""
+
+	args := []string{"spec.md"}
+	var out strings.Builder
+	seed, err := applyFromBranch(ctx, args, &out)
+	if err != nil {
+		t.Fatalf("applyFromBranch() error = %v", err)
+	}
+
+	tree := filepath.Join(filepath.Dir(repo), "repo-feature-x")
+	if got, want := mustResolve(t, workingDir), mustResolve(t, tree); got != want {
+		t.Errorf("workingDir = %s, want the new worktree %s", got, want)
+	}
+	cwd, _ := os.Getwd()
+	if mustResolve(t, cwd) != mustResolve(t, tree) {
+		t.Errorf("cwd = %s, want the new worktree", cwd)
+	}
+	if mustResolve(t, args[0]) != mustResolve(t, filepath.Join(tree, "spec.md")) {
+		t.Errorf("spec = %s, want the worktree's copy", args[0])
+	}
+	if mustResolve(t, configFile) != mustResolve(t, filepath.Join(repo, ".orbital", "config.toml")) {
+		t.Errorf("configFile = %q, want the original checkout's config", configFile)
+	}
+	if _, err := os.Stat(filepath.Join(tree, "parser.go")); err != nil {
+		t.Errorf("worktree does not have the branch checked out: %v", err)
+	}
+	for _, want := range []string{"EXISTING WORK: This session continues branch feature/x", "Start the parser", "parser.go"} {
+		if !strings.Contains(seed, want) {
+			t.Errorf("seed missing %q:\n%s", want, seed)
+		}
+	}
+	if !strings.Contains(out.String(), "Continuing branch feature/x in new worktree") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	// The worktree now exists, so a second session needs another place
+	t.Chdir(repo)
+	workingDir = "."
+	if _, err := applyFromBranch(ctx, []string{"spec.md"}, &out); err == nil {
+		t.Error("applyFromBranch() into an existing path: expected error")
+	}
+}
+
+func TestApplyFromBranch_Unset(t *testing.T) {
+	old := fromBranch
+	defer func() { fromBranch = old }()
+	fromBranch = ""
+	if seed, err := applyFromBranch(context.Background(), []string{"spec.md"}, os.Stdout); seed != "" || err != nil {
+		t.Errorf("applyFromBranch() = %q, %v; want nothing without --from-branch", seed, err)
+	}
+}
+
+func TestFormatBranchSeed_Truncates(t *testing.T) {
+	work := &git.BranchWork{Branch: "feature/x", Base: "main"}
+	for i := 1; i <= maxSeedCommits+5; i++ {
+		work.Commits = append(work.Commits, fmt.Sprintf("c%d commit %d", i, i))
+	}
+	var stat []string
+	for i := 1; i <= maxSeedStatLines+10; i++ {
+		stat = append(stat, fmt.Sprintf(" file%d.go | 1 +", i))
+	}
+	work.DiffStat = strings.Join(append(stat, " 50 files changed, 50 insertions(+)"), "\n")
+
+	seed := formatBranchSeed(work)
+	for _, want := range []string{"- (5 earlier commits)", "commit 25", " ... 11 more files", "50 files changed"} {
+		if !strings.Contains(seed, want) {
+			t.Errorf("seed missing %q:\n%s", want, seed)
+		}
+	}
+	if strings.Contains(seed, "commit 5\n") || strings.Contains(seed, "file45.go") {
+		t.Errorf("seed not truncated:\n%s", seed)
+	}
+
+	empty := formatBranchSeed(&git.BranchWork{Branch: "b", Base: "main"})
+	if !strings.Contains(empty, "- (none yet)") || !strings.Contains(empty, "(none)") {
+		t.Errorf("seed without work = %q", empty)
+	}
+}
+
+func TestBranchDirName(t *testing.T) {
+	if got := branchDirName("feature/x y"); got != "feature-x-y" {
+		t.Errorf("branchDirName() = %q, want feature-x-y", got)
+	}
+}
+
+func mustResolve(t *testing.T, path string) string {
+	t.Helper()
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
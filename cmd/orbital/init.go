@@ -14,21 +14,224 @@ import (
 const DefaultConfigTemplate = `# Orbital CLI Configuration
 # See: https://github.com/flashingpumpkin/orbital
 
+# Version control backend: "auto" (git inside a git work tree, none
+# elsewhere), "git", or "none". Snapshots, rollback and diff summaries need git.
+# vcs = "auto"
+
+# When a run stops on --iterations or --budget with items unchecked, write a
+# follow-up spec of the remaining items to docs/plans (like --follow-up).
+# follow_up = true
+
+# Warn (banner, notification and notes entry) as spend passes each fraction
+# of the budget, like --budget-warn. The run still stops at the budget.
+# budget_warn = [0.5, 0.8]
+
+# Terminal control sequences in the model's output (screen clears, cursor
+# moves, window titles) are removed before display and logging: "strip"
+# (default) keeps colours, "escape" shows them as text, "plain" also removes
+# colours, "off" passes everything through.
+# sanitize = "strip"
+
 # Workflow configuration
 # Use a preset: spec-driven (default), reviewed, or tdd
 # [workflow]
 # preset = "spec-driven"
+# Retry a gate that keeps failing on a stronger model, until the gate passes:
+# escalate_model = "opus"
+# escalate_after = 2  # gate failures before escalating (default: 2)
+
+# Bound model-judged gates independently of the step timeouts; a gate that
+# times out runs again after the backoff, doubled after each retry:
+# [workflow.gate]
+# timeout = "3m"
+# retries = 1       # default: 1
+# backoff = "10s"   # default: 10s
 
 # Or define custom workflow steps:
 # [[workflow.steps]]
+# name = "plan"
+# prompt = "Write an implementation plan"
+# artifacts = ["docs/plans/{{spec_name}}-plan.md"]  # files the step must write
+#
+# [[workflow.steps]]
 # name = "implement"
-# prompt = "Implement the requirements"
+# prompt = "Implement the requirements following {{artifacts}}"
+#
+# [[workflow.steps]]
+# name = "changed"
+# gate = true
+# gate_type = "diff-empty-fail"        # fail when a step changed no files
+# allow_unchanged = ["plan"]           # steps that may change nothing
+# on_fail = "implement"
 #
 # [[workflow.steps]]
 # name = "review"
 # prompt = "Review the changes"
 # gate = true
 # on_fail = "implement"
+# rubric = "docs/rubrics/review.md"  # criteria injected into the gate prompt
+# context = ["docs/architecture.md"]  # context files listed in this step only
+#
+# [[workflow.steps]]
+# name = "sign-off"
+# prompt = "Check the staging deploy"  # optional: shown to the approver
+# gate = true
+# gate_type = "human"                  # wait for 'orbital approve <session> sign-off'
+# approval_timeout = "1h"              # default: 1h
+# on_approval_timeout = "abort"        # abort (default), fail or pass
+# on_fail = "implement"
+
+# Limit the context files listed to each step of a preset (paths or globs):
+# [workflow.context]
+# implement = ["src/**", "docs/architecture.md"]
+# review = ["docs/style-guide.md"]
+
+# The "panel" preset passes its review gate only when a quorum of reviewer
+# agents approve. Override the agents or quorum (default: a majority):
+# [workflow.panel]
+# agents = ["security-reviewer", "design-reviewer", "logic-reviewer"]
+# quorum = 2
+
+# How completion is verified: "model" (default: the checker model confirms
+# every spec checkbox is ticked), "command", "hybrid" (both), or "none".
+# With per_file, the model checks each spec file on its own and only
+# re-checks the files that changed or failed since. With tick, the checker
+# model first finds the spec items already done and orbital ticks them.
+# [workflow.verification]
+# strategy = "hybrid"
+# command = "go test ./..."
+# timeout = "10m"
+# per_file = true
+# tick = true
+#
+# Replace the checker's prompt ({{files}} lists the files to verify), choose
+# the files with globs, and read its replies with your own patterns:
+# files = ["docs/plans/*.md"]
+# prompt = "Read {{files}}. Reply DONE (N) or TODO N/M."
+# verified = 'DONE \((?P<checked>\d+)\)'
+# incomplete = 'TODO (?P<unchecked>\d+)/(?P<checked>\d+)'
+
+# Guard against iterations that repeat the same output without changing the
+# working tree: "escalate" (default) asks for a change of approach after
+# limit stagnant iterations and aborts after another limit, "abort" stops
+# at once, "off" disables the guard.
+# [no_progress]
+# limit = 3
+# action = "escalate"
+
+# Warn when an iteration costs more than factor times the median of the last
+# window iterations. "pause" also asks for confirmation after repeat
+# anomalies in a row; "off" disables the check.
+# [cost_anomaly]
+# factor = 3.0
+# window = 5
+# action = "warn"
+# repeat = 2
+
+# After each iteration, scan the files changed since the run started for
+# "TODO(orbital): ..." comments, list them in the task panel and add new ones
+# to the spec as unchecked items. Needs git.
+# [todos]
+# scan = true
+
+# Mirror the agent's task list into a GitHub issue as a checklist, updated
+# after each iteration. Needs the gh CLI. Without issue, one is opened on the
+# first iteration and reused by orbital continue.
+# [github]
+# mirror_tasks = true
+# issue = 42
+# repo = "owner/name"
+
+# Secrets in Claude's output are masked before they are displayed or stored.
+# Built-in patterns cover AWS keys, JWTs, private keys and GitHub tokens. Add
+# regular expressions of your own; with a capture group only the group is
+# masked.
+# [redact]
+# patterns = ['INTERNAL-[0-9]{6}', 'password=(\S+)']
+# disable_builtin = false
+
+# Commands run via sh -c before the first iteration, such as dependency
+# installs, so the agent does not spend tokens on them. The run stops if one
+# fails. Each command may take up to timeout.
+# [bootstrap]
+# commands = ["npm ci"]
+# timeout = "10m"
+
+# Kill claude and move on to the next iteration ("retry") or only warn
+# ("warn") when it produces no output for timeout. Disabled by default.
+# [stall]
+# timeout = "10m"
+# action = "retry"
+
+# Tune --max-turns between iterations: raise it (up to ceiling) while
+# iterations keep running out of turns, lower it (down to floor) while they
+# finish in half the limit or less. Disabled by default.
+# [max_turns]
+# ceiling = 100
+# floor = 5
+
+# When claude cannot reach the API (DNS failure, connection refused), the
+# session pauses and resumes once the network is back, without using up
+# iterations. offline_timeout stops it after that long offline instead.
+# [network]
+# offline_timeout = "30m"
+
+# A completed session's state, queue and event log are archived in
+# .orbital/archive/<session-id>/. keep is how many sessions are kept
+# (0 deletes the state on completion instead).
+# [archive]
+# keep = 10
+
+# TUI panel layout: rows of the task panel, the fewest output rows before the
+# task panel collapses, progress bar width, and panels to hide ("tasks",
+# "session").
+# [tui]
+# task_rows = 6
+# min_output_rows = 4
+# bar_width = 20
+# hide = []
+# stream_text = true
+
+# How long the results of workflow steps marked cacheable = true are reused
+# when the step is re-run with an identical prompt. Skip the cache with
+# --no-cache.
+# [cache]
+# ttl = "24h"
+
+# Keep what a step prompt brings into the context window (the prompt, its
+# spec and context files) under a fraction of the window, trimming context
+# files, then notes, then per-iteration additions. Disabled by default.
+# [context_budget]
+# fraction = 0.5
+# trim_order = ["context", "notes", "history"]
+
+# What happens to a prompt too large for the model's context window less a
+# reserve for the response: "refuse" (default) stops before claude starts,
+# "trim" cuts the middle, "summarise" replaces the middle with a summary from
+# the checker model, and "off" sends it anyway.
+# [preflight]
+# action = "refuse"
+# reserve = 20000
+
+# Encrypt the event log, thinking log, run records and session state at
+# rest. The key comes from ORBITAL_ENCRYPTION_KEY ("env") or the OS keychain
+# (service "orbital", account "encryption-key"). Read logs with:
+# orbital logs --decrypt
+# [encryption]
+# enabled = true
+# key_source = "env"
+
+# Show costs in another currency and numbers with a locale's separators.
+# Budgets stay in USD; rate converts costs for display only (EUR = USD × rate).
+# [display]
+# currency = "EUR"
+# locale = "de-DE"
+# rate = 0.92
+
+# Extra flags appended to every Claude CLI command. Flags orbital manages
+# (--model, --resume, --max-turns, ...) are rejected.
+# [claude]
+# extra_args = ["--permission-mode", "acceptEdits"]
 
 # Custom prompt template for Claude. Uncomment and modify to customise.
 # Available placeholders:
@@ -50,6 +253,30 @@ const DefaultConfigTemplate = `# Orbital CLI Configuration
 # prompt = "Detailed instructions for the agent"
 # tools = ["Read", "Write", "Bash"]  # optional: restrict available tools
 # model = "sonnet"                    # optional: override model for this agent
+
+# Notifications when the session completes, a gate fails repeatedly,
+# spend crosses the budget threshold, or an iteration's cost is anomalous.
+#
+# [notifications]
+# bell = true                     # ring the terminal bell
+# desktop = true                  # osascript (macOS) or notify-send (Linux)
+# events = ["complete", "gate_failed", "budget", "cost_anomaly", "approval"]  # optional: default is all
+# budget_threshold = 0.9          # optional: fraction of budget (default 0.9)
+
+# Local usage statistics (runs per week, iterations to completion, budget
+# utilisation) written to .orbital/stats.json. Never sent anywhere.
+# View with: orbital stats
+#
+# [stats]
+# enabled = true
+
+# Shared memory: durable facts about the project (package manager, how to
+# run the tests) in .orbital/memory.md. Each session gets the newest facts
+# in its system prompt and adds what it learned when it ends.
+#
+# [memory]
+# enabled = true
+# max_bytes = 4000                # cap on the facts given to a session
 `
 
 var (
@@ -72,6 +299,7 @@ Available workflow presets:
   spec-driven  Single implement step with completion check (default)
   reviewed     Implement with review gate before completion
   tdd          Red-green-refactor cycle with review gate
+  panel        Implement with a review gate decided by a quorum of agents
 
 If the configuration file already exists, the command will fail unless --force is used.`,
 	Args: cobra.NoArgs,
@@ -235,6 +463,30 @@ name = "`)
 # prompt = "Detailed instructions for the agent"
 # tools = ["Read", "Write", "Bash"]  # optional: restrict available tools
 # model = "sonnet"                    # optional: override model for this agent
+
+# Notifications when the session completes, a gate fails repeatedly,
+# spend crosses the budget threshold, or an iteration's cost is anomalous.
+#
+# [notifications]
+# bell = true                     # ring the terminal bell
+# desktop = true                  # osascript (macOS) or notify-send (Linux)
+# events = ["complete", "gate_failed", "budget", "cost_anomaly", "approval"]  # optional: default is all
+# budget_threshold = 0.9          # optional: fraction of budget (default 0.9)
+
+# Local usage statistics (runs per week, iterations to completion, budget
+# utilisation) written to .orbital/stats.json. Never sent anywhere.
+# View with: orbital stats
+#
+# [stats]
+# enabled = true
+
+# Shared memory: durable facts about the project (package manager, how to
+# run the tests) in .orbital/memory.md. Each session gets the newest facts
+# in its system prompt and adds what it learned when it ends.
+#
+# [memory]
+# enabled = true
+# max_bytes = 4000                # cap on the facts given to a session
 `)
 	return sb.String()
 }
@@ -271,6 +523,30 @@ preset = "`)
 # prompt = "Detailed instructions for the agent"
 # tools = ["Read", "Write", "Bash"]  # optional: restrict available tools
 # model = "sonnet"                    # optional: override model for this agent
+
+# Notifications when the session completes, a gate fails repeatedly,
+# spend crosses the budget threshold, or an iteration's cost is anomalous.
+#
+# [notifications]
+# bell = true                     # ring the terminal bell
+# desktop = true                  # osascript (macOS) or notify-send (Linux)
+# events = ["complete", "gate_failed", "budget", "cost_anomaly", "approval"]  # optional: default is all
+# budget_threshold = 0.9          # optional: fraction of budget (default 0.9)
+
+# Local usage statistics (runs per week, iterations to completion, budget
+# utilisation) written to .orbital/stats.json. Never sent anywhere.
+# View with: orbital stats
+#
+# [stats]
+# enabled = true
+
+# Shared memory: durable facts about the project (package manager, how to
+# run the tests) in .orbital/memory.md. Each session gets the newest facts
+# in its system prompt and adds what it learned when it ends.
+#
+# [memory]
+# enabled = true
+# max_bytes = 4000                # cap on the facts given to a session
 `)
 	return sb.String()
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// takeInterjection claims a pending operator interjection, records it in the
+// session's event log and returns the text to place before this iteration's
+// prompts, or "" if there is none.
+func takeInterjection(interjector *loop.Injector, iteration int, eventLogPath string, tuiProgram *tui.Program) string {
+	instruction, err := interjector.Take()
+	if err != nil {
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("⚠ " + err.Error())
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: "+err.Error())
+		}
+		return ""
+	}
+	if instruction == "" {
+		return ""
+	}
+	if tuiProgram != nil {
+		// The program records it in its event log
+		tuiProgram.SendInterjection(iteration, instruction)
+	} else {
+		fmt.Printf("\nOperator interjection from %s\n", interjector.Path)
+		interjectionRecorder(eventLogPath)(iteration, instruction)
+	}
+	return loop.FormatInterjection(instruction)
+}
+
+// interjectionRecorder returns a callback that appends each interjection to
+// the event log at path, for runs without the TUI (which keeps the log open
+// itself).
+func interjectionRecorder(path string) loop.InterjectionCallback {
+	return func(iteration int, text string) {
+		if err := recordInterjection(path, iteration, text); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: interjection not recorded: %v\n", err)
+		}
+	}
+}
+
+// recordInterjection appends an interjection entry to the event log at path.
+func recordInterjection(path string, iteration int, text string) error {
+	log, err := tui.OpenEventLog(path)
+	if err != nil {
+		return err
+	}
+	log.Observe(tui.InterjectionMsg{Iteration: iteration, Text: text})
+	return log.Close()
+}
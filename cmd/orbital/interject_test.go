@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+func TestTakeInterjection_RecordsInEventLog(t *testing.T) {
+	dir := t.TempDir()
+	interjector := loop.NewInjector(filepath.Join(dir, "interject.md"))
+	eventLog := filepath.Join(dir, "runs", "session.events.jsonl")
+
+	if got := takeInterjection(interjector, 1, eventLog, nil); got != "" {
+		t.Errorf("takeInterjection() without a file = %q, want empty", got)
+	}
+	if _, err := os.Stat(eventLog); !os.IsNotExist(err) {
+		t.Error("event log written without an interjection")
+	}
+
+	if err := os.WriteFile(interjector.Path, []byte("Stop and fix the build\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := takeInterjection(interjector, 2, eventLog, nil), loop.FormatInterjection("Stop and fix the build"); got != want {
+		t.Errorf("takeInterjection() = %q, want %q", got, want)
+	}
+
+	events, err := tui.ReadEventLog(eventLog)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != tui.EventInterjection || e.Iteration != 2 || e.Line != "Stop and fix the build" {
+		t.Errorf("last event = %+v, want the interjection", e)
+	}
+}
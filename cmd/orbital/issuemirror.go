@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/ghissue"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// newIssueMirror returns a mirror of the agent's task list into a GitHub
+// issue when [github] mirror_tasks is on, or nil. The issue in the config
+// wins over the one orbital opened earlier in the session.
+func newIssueMirror(fileConfig *config.FileConfig, st *state.State, dir string, specFiles []string) *ghissue.Mirror {
+	if fileConfig == nil || fileConfig.GitHub == nil || !fileConfig.GitHub.MirrorTasks {
+		return nil
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: [github] mirror_tasks needs the gh CLI on PATH; task mirroring is disabled")
+		return nil
+	}
+	number := fileConfig.GitHub.Issue
+	if number == 0 {
+		number = st.TrackingIssue
+	}
+	return ghissue.New(ghissue.GH(dir), fileConfig.GitHub.Repo, number, issueTitle(specFiles))
+}
+
+// issueTitle names the tracking issue after the spec files.
+func issueTitle(specFiles []string) string {
+	names := make([]string, len(specFiles))
+	for i, f := range specFiles {
+		names[i] = filepath.Base(f)
+	}
+	return "Orbital: " + strings.Join(names, ", ")
+}
+
+// syncIssueMirror writes the task list to the tracking issue after an
+// iteration and records the issue in the state once it is opened. Failures
+// are reported without interrupting the loop.
+func syncIssueMirror(ctx context.Context, m *ghissue.Mirror, st *state.State, iteration int, totalCost float64, tuiProgram *tui.Program) {
+	if m == nil {
+		return
+	}
+	status := fmt.Sprintf("**Orbital session %s**: iteration %d, %s spent.", st.SessionID, iteration, util.FormatCost(totalCost, 2))
+	number, created, err := m.Sync(ctx, status)
+	if err != nil {
+		msg := fmt.Sprintf("GitHub task mirroring failed: %v", err)
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("⚠ " + msg)
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: "+msg)
+		}
+		return
+	}
+	if created {
+		st.TrackingIssue = number
+		msg := fmt.Sprintf("Mirroring tasks to GitHub issue #%d", number)
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("🔗 " + msg)
+		} else {
+			fmt.Println(msg)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/ghissue"
+	"github.com/flashingpumpkin/orbital/internal/state"
+)
+
+func TestNewIssueMirror(t *testing.T) {
+	// A stand-in gh on PATH
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	st := state.NewState("s", t.TempDir(), nil, "", nil)
+	st.TrackingIssue = 12
+
+	if m := newIssueMirror(nil, st, "", nil); m != nil {
+		t.Error("mirror created without config")
+	}
+	if m := newIssueMirror(&config.FileConfig{GitHub: &config.GitHubConfig{}}, st, "", nil); m != nil {
+		t.Error("mirror created with mirror_tasks off")
+	}
+
+	m := newIssueMirror(&config.FileConfig{GitHub: &config.GitHubConfig{MirrorTasks: true}}, st, "", nil)
+	if m == nil || m.Number() != 12 {
+		t.Fatalf("mirror = %v, want the session's issue 12", m)
+	}
+	m = newIssueMirror(&config.FileConfig{GitHub: &config.GitHubConfig{MirrorTasks: true, Issue: 3}}, st, "", nil)
+	if m == nil || m.Number() != 3 {
+		t.Errorf("mirror = %v, want the configured issue 3", m)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if m := newIssueMirror(&config.FileConfig{GitHub: &config.GitHubConfig{MirrorTasks: true}}, st, "", nil); m != nil {
+		t.Error("mirror created without gh on PATH")
+	}
+}
+
+func TestSyncIssueMirror_RecordsOpenedIssue(t *testing.T) {
+	var body string
+	run := func(ctx context.Context, stdin string, args ...string) (string, error) {
+		body = stdin
+		return "https://github.com/owner/name/issues/9", nil
+	}
+	m := ghissue.New(run, "", 0, issueTitle([]string{"/specs/auth.md", "api.md"}))
+	st := state.NewState("session-1", t.TempDir(), nil, "", nil)
+
+	syncIssueMirror(context.Background(), m, st, 2, 1.5, nil)
+	if st.TrackingIssue != 9 {
+		t.Errorf("TrackingIssue = %d, want 9", st.TrackingIssue)
+	}
+	if want := "**Orbital session session-1**: iteration 2, $1.50 spent."; body[:len(want)] != want {
+		t.Errorf("body = %q, want it to start with %q", body, want)
+	}
+
+	// A nil mirror is a no-op
+	syncIssueMirror(context.Background(), nil, st, 3, 2, nil)
+}
+
+func TestIssueTitle(t *testing.T) {
+	if got := issueTitle([]string{"/specs/auth.md", "api.md"}); got != "Orbital: auth.md, api.md" {
+		t.Errorf("issueTitle() = %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// keyHelp lists the keys read in minimal mode.
+const keyHelp = "Keys: s status · p pause/resume · q stop"
+
+// keyControls lets the operator steer a session without the TUI: s prints
+// the status, p pauses at the next iteration boundary and resumes, and q
+// stops the session as ctrl+c does. The terminal is put in cbreak mode, so
+// keys are read one at a time without echo while output is left alone.
+// Messages go to stderr. Its methods do nothing on a nil keyControls.
+type keyControls struct {
+	in      io.Reader
+	out     io.Writer
+	start   time.Time
+	restore func()
+
+	mu      sync.Mutex
+	status  output.ProgressStatus
+	paused  bool
+	resume  chan struct{}
+	answer  chan byte
+	ctx     context.Context
+	stop    func()
+	closed  bool
+	closing sync.Once
+}
+
+// newKeyControls returns the key controls for a session without the TUI, or
+// nil when there is no terminal to read keys from.
+func newKeyControls(tuiProgram *tui.Program, start time.Time) *keyControls {
+	if tuiProgram != nil || !stdinInteractive() {
+		return nil
+	}
+	restore, err := cbreak(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: keyboard controls unavailable: %v\n", err)
+		return nil
+	}
+	return &keyControls{in: os.Stdin, out: os.Stderr, start: start, restore: restore}
+}
+
+// Attach starts reading keys and returns a context that q cancels the way
+// SIGINT does, so claude is interrupted and the session can be continued.
+func (k *keyControls) Attach(ctx context.Context) context.Context {
+	if k == nil {
+		return ctx
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	k.mu.Lock()
+	k.ctx = ctx
+	k.stop = func() { cancel(executor.SignalCause{Signal: os.Interrupt}) }
+	k.mu.Unlock()
+
+	_, _ = fmt.Fprintln(k.out, keyHelp)
+	go k.read()
+	return ctx
+}
+
+// Update records the session's progress for the status key.
+func (k *keyControls) Update(s output.ProgressStatus) {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.status = s
+}
+
+// Wait blocks at an iteration boundary while the session is paused, until
+// it is resumed or the attached context is done.
+func (k *keyControls) Wait() {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	if !k.paused || k.ctx == nil {
+		k.mu.Unlock()
+		return
+	}
+	resume, ctx := k.resume, k.ctx
+	k.mu.Unlock()
+
+	_, _ = fmt.Fprintln(k.out, "\n⏸ Paused. Press p to resume, q to stop.")
+	select {
+	case <-resume:
+	case <-ctx.Done():
+	}
+}
+
+// Confirm asks question and reports whether the next key is y. Keys are
+// taken by the reader, so prompts go through it instead of stdin.
+func (k *keyControls) Confirm(ctx context.Context, question string) bool {
+	answer := make(chan byte, 1)
+	k.mu.Lock()
+	k.answer = answer
+	k.mu.Unlock()
+
+	_, _ = fmt.Fprintf(k.out, "%s [y/N]: ", question)
+	var b byte
+	select {
+	case b = <-answer:
+	case <-ctx.Done():
+	}
+
+	k.mu.Lock()
+	k.answer = nil
+	k.mu.Unlock()
+	if b >= ' ' && b < 0x7f {
+		_, _ = fmt.Fprintf(k.out, "%c\n", b)
+	} else {
+		_, _ = fmt.Fprintln(k.out)
+	}
+	return b == 'y' || b == 'Y'
+}
+
+// Close stops acting on keys and restores the terminal.
+func (k *keyControls) Close() {
+	if k == nil {
+		return
+	}
+	k.closing.Do(func() {
+		k.mu.Lock()
+		k.closed = true
+		k.mu.Unlock()
+		if k.restore != nil {
+			k.restore()
+		}
+	})
+}
+
+// read handles keys until the input ends.
+func (k *keyControls) read() {
+	buf := make([]byte, 1)
+	for {
+		n, err := k.in.Read(buf)
+		if n == 1 {
+			k.handle(buf[0])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// handle acts on one key.
+func (k *keyControls) handle(b byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	switch {
+	case k.closed:
+	case k.answer != nil:
+		select {
+		case k.answer <- b:
+		default:
+		}
+	case b == 's' || b == 'S':
+		line := output.FormatProgress(k.status, time.Since(k.start))
+		if k.paused {
+			line += " | paused"
+		}
+		_, _ = fmt.Fprintf(k.out, "\n%s\n", line)
+	case b == 'p' || b == 'P':
+		if k.paused {
+			k.paused = false
+			close(k.resume)
+			_, _ = fmt.Fprintln(k.out, "\n▶ Resuming")
+			return
+		}
+		k.paused = true
+		k.resume = make(chan struct{})
+		_, _ = fmt.Fprintln(k.out, "\n⏸ Pausing after this iteration (press p again to keep going)")
+	case b == 'q' || b == 'Q':
+		if k.stop != nil {
+			_, _ = fmt.Fprintln(k.out, "\nStopping...")
+			k.stop()
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+)
+
+// lockedBuffer is a bytes.Buffer safe for the key reader and the test to use
+// at once.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestKeys(in io.Reader) (*keyControls, *lockedBuffer) {
+	out := &lockedBuffer{}
+	return &keyControls{in: in, out: out, start: time.Now()}, out
+}
+
+func TestKeyControls_Nil(t *testing.T) {
+	var k *keyControls
+	ctx := context.Background()
+	if got := k.Attach(ctx); got != ctx {
+		t.Error("Attach() on nil keyControls should return ctx unchanged")
+	}
+	k.Update(output.ProgressStatus{Iteration: 1})
+	k.Wait()
+	k.Close()
+}
+
+func TestKeyControls_Status(t *testing.T) {
+	k, out := newTestKeys(nil)
+	k.Update(output.ProgressStatus{Iteration: 3, MaxIterations: 10, Step: "implement", Cost: 1.5, Budget: 10})
+
+	k.handle('s')
+	if got := out.String(); !strings.Contains(got, "iter 3/10 | step implement | $1.50/$10.00") {
+		t.Errorf("status = %q, want the iteration, step and cost", got)
+	}
+
+	k.handle('p')
+	k.handle('s')
+	if got := out.String(); !strings.HasSuffix(got, "| paused\n") {
+		t.Errorf("status while paused = %q, want it marked paused", got)
+	}
+}
+
+func TestKeyControls_PauseAndResume(t *testing.T) {
+	k, out := newTestKeys(nil)
+	k.ctx = context.Background()
+
+	// Not paused: the boundary passes straight through
+	k.Wait()
+
+	k.handle('p')
+	if !strings.Contains(out.String(), "Pausing after this iteration") {
+		t.Errorf("output = %q, want a pausing message", out.String())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		k.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Wait() returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	k.handle('P')
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait() did not return after resuming")
+	}
+	if !strings.Contains(out.String(), "Resuming") {
+		t.Errorf("output = %q, want a resuming message", out.String())
+	}
+}
+
+func TestKeyControls_StopInterrupts(t *testing.T) {
+	in, keysIn := io.Pipe()
+	defer func() { _ = keysIn.Close() }()
+	k, out := newTestKeys(in)
+	ctx := k.Attach(context.Background())
+	if !strings.Contains(out.String(), keyHelp) {
+		t.Errorf("output = %q, want the key help", out.String())
+	}
+
+	// A paused session stops too
+	if _, err := keysIn.Write([]byte("pq")); err != nil {
+		t.Fatal(err)
+	}
+	waited := make(chan struct{})
+	go func() {
+		k.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("q did not cancel the context")
+	}
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Wait() did not return after stopping")
+	}
+	var cause executor.SignalCause
+	if !errors.As(context.Cause(ctx), &cause) {
+		t.Errorf("cause = %v, want a SignalCause like ctrl+c", context.Cause(ctx))
+	}
+}
+
+func TestKeyControls_Confirm(t *testing.T) {
+	tests := []struct {
+		key  byte
+		want bool
+	}{
+		{'y', true},
+		{'Y', true},
+		{'n', false},
+		{'q', false},
+		{'\r', false},
+	}
+
+	for _, tt := range tests {
+		k, out := newTestKeys(nil)
+		got := make(chan bool)
+		go func() { got <- k.Confirm(context.Background(), "Continue?") }()
+
+		// The key is taken as the answer once the prompt is waiting
+		deadline := time.Now().Add(time.Second)
+		for !strings.Contains(out.String(), "Continue? [y/N]: ") && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		k.handle(tt.key)
+		if answer := <-got; answer != tt.want {
+			t.Errorf("Confirm() with %q = %v, want %v", tt.key, answer, tt.want)
+		}
+		if k.stop != nil || k.paused {
+			t.Errorf("answer %q was also handled as a key", tt.key)
+		}
+	}
+
+	k, _ := newTestKeys(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if k.Confirm(ctx, "Continue?") {
+		t.Error("Confirm() with a cancelled context = true, want false")
+	}
+}
+
+func TestKeyControls_ClosedIgnoresKeys(t *testing.T) {
+	restored := 0
+	k, out := newTestKeys(nil)
+	k.restore = func() { restored++ }
+	k.Close()
+	k.Close()
+	if restored != 1 {
+		t.Errorf("terminal restored %d times, want 1", restored)
+	}
+
+	k.handle('s')
+	k.handle('p')
+	if out.String() != "" || k.paused {
+		t.Errorf("keys after Close() were handled: output %q, paused %v", out.String(), k.paused)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = newLogsCmd()
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [session-id]",
+		Short: "Print the output log of a run",
+		Long: `Print the output recorded in a run's event log, grouped by iteration
+and step. Without a session ID the most recent run is shown; a unique prefix
+of the ID is accepted.
+
+Logs written with [encryption] enabled are only printed with --decrypt. The
+key is read from the configured key source, or from ORBITAL_ENCRYPTION_KEY.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeEventLogIDs,
+		RunE:              runLogs,
+	}
+	cmd.Flags().Bool("decrypt", false, "Decrypt encrypted logs")
+	cmd.Flags().Bool("thinking", false, "Print .orbital/thinking.log instead of the event log")
+	cmd.Flags().Bool("parse-errors", false, "Print .orbital/parse-errors.log instead of the event log")
+	cmd.Flags().Bool("raw", false, "Print the event log as stored (JSON lines)")
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	decrypt, _ := cmd.Flags().GetBool("decrypt")
+	thinking, _ := cmd.Flags().GetBool("thinking")
+	parseErrors, _ := cmd.Flags().GetBool("parse-errors")
+	raw, _ := cmd.Flags().GetBool("raw")
+
+	fileConfig, err := loadFileConfig(workingDir)
+	if err != nil {
+		return err
+	}
+	if decrypt {
+		if err := setupEncryption(fileConfig); err != nil {
+			return err
+		}
+	}
+
+	if thinking && parseErrors {
+		return errors.New("--thinking and --parse-errors cannot be used together")
+	}
+
+	var path string
+	if thinking {
+		if len(args) > 0 {
+			return errors.New("--thinking takes no session ID: the thinking log is shared by all runs")
+		}
+		path = output.ThinkingLogPath(workingDir)
+	} else if parseErrors {
+		if len(args) > 0 {
+			return errors.New("--parse-errors takes no session ID: the quarantine is shared by all runs")
+		}
+		path = output.QuarantinePath(workingDir)
+	} else {
+		ref := ""
+		if len(args) > 0 {
+			ref = args[0]
+		}
+		path, err = findEventLog(workingDir, ref)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no log at %s", path)
+		}
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+	if encrypt.IsEncrypted(data) && !decrypt {
+		return fmt.Errorf("%s is encrypted; rerun with --decrypt", path)
+	}
+
+	out := cmd.OutOrStdout()
+	if thinking || parseErrors || raw {
+		plain, err := encrypt.Open(data)
+		if err != nil && !errors.Is(err, encrypt.ErrTruncated) {
+			return fmt.Errorf("failed to decrypt log: %w", err)
+		}
+		warnTruncated(cmd.ErrOrStderr(), path, err)
+		_, err = out.Write(plain)
+		return err
+	}
+
+	events, err := tui.ReadEventLog(path)
+	if err != nil && !errors.Is(err, encrypt.ErrTruncated) {
+		return err
+	}
+	warnTruncated(cmd.ErrOrStderr(), path, err)
+	return printEvents(out, events)
+}
+
+// warnTruncated warns when err reports an encrypted log without its final
+// frame, which its readers still show up to where it ends.
+func warnTruncated(w io.Writer, path string, err error) {
+	if errors.Is(err, encrypt.ErrTruncated) {
+		_, _ = fmt.Fprintf(w, "Warning: %s ends early: it is still being written, its session did not exit cleanly, or it was truncated\n", path)
+	}
+}
+
+// printEvents writes output lines with a header for each iteration/step period.
+func printEvents(w io.Writer, events []tui.Event) error {
+	for _, e := range events {
+		var err error
+		switch e.Kind {
+		case tui.EventPeriod:
+			p := tui.Period{Iteration: e.Iteration, Step: e.Step}
+			_, err = fmt.Fprintf(w, "── %s (%s) ──\n", p.Label(), e.Time.Format("2006-01-02 15:04:05"))
+		case tui.EventOutput:
+			_, err = fmt.Fprintln(w, e.Line)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findEventLog returns the event log for the run matching ref, a session ID
+// or unique prefix of one. An empty ref selects the most recently written log.
+func findEventLog(dir, ref string) (string, error) {
+	return findRunFile(dir, ref, ".events.jsonl", "run log")
+}
+
+// findRunFile returns the file with suffix in the runs directory for the run
+// matching ref, a session ID or unique prefix of one. An empty ref selects
+// the most recently written file. what names the kind of file in errors.
+func findRunFile(dir, ref, suffix, what string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(runs.Dir(dir), "*"+suffix))
+	if err != nil {
+		return "", fmt.Errorf("failed to list %ss: %w", what, err)
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no %ss recorded in this directory", what)
+	}
+
+	if ref == "" {
+		sort.Slice(paths, func(i, j int) bool {
+			return modTime(paths[i]) > modTime(paths[j])
+		})
+		return paths[0], nil
+	}
+
+	var matches []string
+	for _, p := range paths {
+		id := strings.TrimSuffix(filepath.Base(p), suffix)
+		if id == ref {
+			return p, nil
+		}
+		if strings.HasPrefix(id, ref) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s matches %q", what, ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("run reference %q is ambiguous (%d matches)", ref, len(matches))
+	}
+}
+
+// modTime returns the modification time of path in nanoseconds, or 0 if it
+// cannot be read.
+func modTime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/runs"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// writeEventLog records a short run in the event log for session id.
+func writeEventLog(t *testing.T, id string) {
+	t.Helper()
+	log, err := tui.OpenEventLog(runs.EventLogPath(".", id))
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+	log.Observe(tui.ProgressMsg{Iteration: 2, StepName: "implement"})
+	log.Observe(tui.OutputLineMsg("wrote the parser"))
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runLogsCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newLogsCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestLogsCmd_PrintsEventLog(t *testing.T) {
+	chdirTemp(t)
+	writeEventLog(t, "abc123")
+
+	out, err := runLogsCmd(t, "abc")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, want := range []string{"iteration 2, step implement", "wrote the parser"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLogsCmd_NoLogs(t *testing.T) {
+	chdirTemp(t)
+	if _, err := runLogsCmd(t); err == nil {
+		t.Error("Execute() succeeded with no logs, want error")
+	}
+}
+
+func TestLogsCmd_EncryptedNeedsDecrypt(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv(encrypt.KeyEnv, "secret")
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	writeEventLog(t, "abc123")
+	encrypt.SetKey(nil)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	if _, err := runLogsCmd(t); err == nil || !strings.Contains(err.Error(), "--decrypt") {
+		t.Errorf("Execute() error = %v, want a hint to use --decrypt", err)
+	}
+
+	out, err := runLogsCmd(t, "--decrypt")
+	if err != nil {
+		t.Fatalf("Execute(--decrypt) error = %v", err)
+	}
+	if !strings.Contains(out, "wrote the parser") {
+		t.Errorf("decrypted output = %q", out)
+	}
+
+	out, err = runLogsCmd(t, "--decrypt", "--raw")
+	if err != nil {
+		t.Fatalf("Execute(--decrypt --raw) error = %v", err)
+	}
+	if !strings.Contains(out, `"kind":"output"`) {
+		t.Errorf("raw output = %q, want JSON lines", out)
+	}
+}
+
+func TestLogsCmd_ParseErrors(t *testing.T) {
+	chdirTemp(t)
+	t.Setenv(encrypt.KeyEnv, "secret")
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	q := output.NewQuarantine(output.QuarantinePath("."), nil)
+	q.Record(output.MalformedLine{Number: 3, Line: `{"type":"assis`, Err: errors.New("unexpected end of JSON input")})
+	encrypt.SetKey(nil)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	if _, err := runLogsCmd(t, "--parse-errors"); err == nil || !strings.Contains(err.Error(), "--decrypt") {
+		t.Errorf("Execute() error = %v, want a hint to use --decrypt", err)
+	}
+	out, err := runLogsCmd(t, "--parse-errors", "--decrypt")
+	if err != nil {
+		t.Fatalf("Execute(--parse-errors --decrypt) error = %v", err)
+	}
+	if !strings.Contains(out, `line 3: unexpected end of JSON input`) {
+		t.Errorf("output = %q, want the quarantined line", out)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// resolveTurnTuning sets up auto-tuning of --max-turns: [max_turns] first,
+// then --max-turns-ceiling.
+func resolveTurnTuning(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	floor := 0
+	if fileConfig != nil && fileConfig.MaxTurns != nil {
+		cfg.MaxTurnsCeiling = fileConfig.MaxTurns.Ceiling
+		floor = fileConfig.MaxTurns.Floor
+	}
+	if cmd.Flags().Changed("max-turns-ceiling") {
+		cfg.MaxTurnsCeiling = maxTurnsCeiling
+	}
+	if floor == 0 {
+		// A ceiling below the default floor brings the floor down with it
+		floor = min(config.DefaultMaxTurnsFloor, max(cfg.MaxTurnsCeiling, 1))
+	}
+	cfg.MaxTurnsFloor = floor
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// newTurnTuner returns the --max-turns auto-tuner, or nil when tuning is
+// off. The tuner's starting limit replaces cfg.MaxTurns.
+func newTurnTuner(cfg *config.Config) *loop.TurnTuner {
+	if cfg.MaxTurnsCeiling == 0 {
+		return nil
+	}
+	tuner := loop.NewTurnTuner(cfg.MaxTurns, cfg.MaxTurnsFloor, cfg.MaxTurnsCeiling)
+	cfg.MaxTurns = tuner.Limit()
+	return tuner
+}
+
+// applyTurnAdjustment sets the tuned turn limit for the following
+// iterations and reports the change.
+func applyTurnAdjustment(cfg *config.Config, a loop.TurnAdjustment, tuiProgram *tui.Program) {
+	cfg.MaxTurns = a.To
+	msg := fmt.Sprintf("Iteration %d: %s", a.Iteration, a)
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("🔧 " + msg)
+	} else {
+		fmt.Printf("\n%s\n", msg)
+	}
+}
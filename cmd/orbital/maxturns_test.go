@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveTurnTuning(t *testing.T) {
+	oldCeiling := maxTurnsCeiling
+	defer func() { maxTurnsCeiling = oldCeiling }()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().IntVar(&maxTurnsCeiling, "max-turns-ceiling", 0, "")
+		return cmd
+	}
+	newCfg := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.SpecPath = "spec.md"
+		return cfg
+	}
+
+	cfg := newCfg()
+	if err := resolveTurnTuning(newCmd(), cfg, nil); err != nil {
+		t.Fatalf("resolveTurnTuning() error = %v", err)
+	}
+	if cfg.MaxTurnsCeiling != 0 || newTurnTuner(cfg) != nil {
+		t.Errorf("tuning enabled by default (ceiling %d)", cfg.MaxTurnsCeiling)
+	}
+
+	fileConfig := &config.FileConfig{MaxTurns: &config.MaxTurnsConfig{Ceiling: 80, Floor: 10}}
+	cfg = newCfg()
+	if err := resolveTurnTuning(newCmd(), cfg, fileConfig); err != nil {
+		t.Fatalf("resolveTurnTuning() error = %v", err)
+	}
+	if cfg.MaxTurnsCeiling != 80 || cfg.MaxTurnsFloor != 10 {
+		t.Errorf("tuning = %d..%d, want config file values 10..80", cfg.MaxTurnsFloor, cfg.MaxTurnsCeiling)
+	}
+
+	cmd := newCmd()
+	_ = cmd.Flags().Set("max-turns-ceiling", "3")
+	cfg = newCfg()
+	if err := resolveTurnTuning(cmd, cfg, nil); err != nil {
+		t.Fatalf("resolveTurnTuning() error = %v", err)
+	}
+	if cfg.MaxTurnsCeiling != 3 || cfg.MaxTurnsFloor != 3 {
+		t.Errorf("tuning = %d..%d, want the default floor lowered to the ceiling 3", cfg.MaxTurnsFloor, cfg.MaxTurnsCeiling)
+	}
+
+	fileConfig = &config.FileConfig{MaxTurns: &config.MaxTurnsConfig{Ceiling: 20, Floor: 30}}
+	if err := resolveTurnTuning(newCmd(), newCfg(), fileConfig); err == nil || !strings.Contains(err.Error(), "invalid max turns floor") {
+		t.Errorf("resolveTurnTuning(floor above ceiling) error = %v, want invalid max turns floor", err)
+	}
+}
+
+func TestNewTurnTuner_SetsStartingLimit(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxTurnsCeiling = 60
+	if tuner := newTurnTuner(cfg); tuner == nil || cfg.MaxTurns != 60 {
+		t.Errorf("MaxTurns = %d, want unlimited turns to start at the ceiling 60", cfg.MaxTurns)
+	}
+
+	applyTurnAdjustment(cfg, loop.TurnAdjustment{Iteration: 2, From: 60, To: 30, Used: 15}, nil)
+	if cfg.MaxTurns != 30 {
+		t.Errorf("MaxTurns = %d after adjustment, want 30", cfg.MaxTurns)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/memory"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// memorySummaryTimeout bounds the end-of-session summary so a slow model
+// cannot hold up the exit.
+const memorySummaryTimeout = 2 * time.Minute
+
+// memoryEnabled reports whether [memory] in the config file turns on the
+// shared memory file.
+func memoryEnabled(fileConfig *config.FileConfig) bool {
+	return fileConfig != nil && fileConfig.Memory != nil && fileConfig.Memory.Enabled
+}
+
+// loadMemory returns the shared memory facts of the project in dir, cut to
+// the configured size, or "" when memory is off or empty.
+func loadMemory(fileConfig *config.FileConfig, dir string) string {
+	if !memoryEnabled(fileConfig) {
+		return ""
+	}
+	facts, err := memory.Load(dir, fileConfig.Memory.EffectiveMaxBytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+	return facts
+}
+
+// updateMemory asks the checker model for the durable facts the session
+// learned and appends the new ones to the memory file. Interrupted runs and
+// runs that spent their budget are skipped. It returns what the summary
+// cost, for the session's totals, also when it failed.
+func updateMemory(out io.Writer, cfg *config.Config, redactor *redact.Redactor, dir string, specFiles []string, notesFile, facts string, runErr error) (float64, error) {
+	if errors.Is(runErr, context.Canceled) || errors.Is(runErr, loop.ErrBudgetExceeded) {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), memorySummaryTimeout)
+	defer cancel()
+	summaryExec := executor.New(&config.Config{
+		Model:      cfg.CheckerModel,
+		MaxBudget:  cfg.MaxBudget,
+		WorkingDir: dir,
+	})
+	summaryExec.SetRedactor(redactor)
+	result, err := summaryExec.Execute(ctx, memory.BuildSummaryPrompt(specFiles, notesFile, facts))
+	var cost float64
+	if result != nil {
+		cost = result.CostUSD
+	}
+	if err != nil {
+		return cost, fmt.Errorf("memory summary failed: %w", err)
+	}
+	return cost, rememberFacts(out, dir, output.ExtractResult(result.Output), cost)
+}
+
+// rememberFacts appends the facts in the summary model's reply to the memory
+// file and lists those that were new.
+func rememberFacts(out io.Writer, dir, reply string, cost float64) error {
+	added, err := memory.Append(dir, memory.ParseSummary(reply))
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "\nRemembered %d fact(s) in %s (%s):\n", len(added), relativeTo(dir, memory.Path(dir)), util.FormatCost(cost, 2))
+	for _, fact := range added {
+		_, _ = fmt.Fprintf(out, "  - %s\n", fact)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/memory"
+)
+
+func TestRememberFacts(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	if err := rememberFacts(&out, dir, "NONE", 0.01); err != nil || out.Len() != 0 {
+		t.Fatalf("rememberFacts(NONE) = %q, %v, want nothing", out.String(), err)
+	}
+
+	if err := rememberFacts(&out, dir, "- We use pnpm\n- Tests run with `make test`\n", 0.01); err != nil {
+		t.Fatalf("rememberFacts() error = %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Remembered 2 fact(s) in .orbital/memory.md") || !strings.Contains(got, "  - We use pnpm\n") {
+		t.Errorf("rememberFacts() output = %q", got)
+	}
+
+	// Facts already remembered are not reported again
+	out.Reset()
+	if err := rememberFacts(&out, dir, "- we use pnpm\n", 0.01); err != nil || out.Len() != 0 {
+		t.Errorf("rememberFacts() with a known fact = %q, %v, want nothing", out.String(), err)
+	}
+}
+
+func TestLoadMemory(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := memory.Append(dir, []string{"We use pnpm"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loadMemory(nil, dir); got != "" {
+		t.Errorf("loadMemory() without config = %q, want empty", got)
+	}
+	off := &config.FileConfig{Memory: &memory.Config{}}
+	if got := loadMemory(off, dir); got != "" {
+		t.Errorf("loadMemory() while disabled = %q, want empty", got)
+	}
+	on := &config.FileConfig{Memory: &memory.Config{Enabled: true}}
+	if got := loadMemory(on, dir); got != "- We use pnpm\n" {
+		t.Errorf("loadMemory() = %q", got)
+	}
+}
+
+func TestUpdateMemory_SkipsInterruptedRuns(t *testing.T) {
+	for _, runErr := range []error{context.Canceled, loop.ErrBudgetExceeded} {
+		var out bytes.Buffer
+		if cost, err := updateMemory(&out, &config.Config{}, nil, t.TempDir(), nil, "", "", runErr); err != nil || cost != 0 || out.Len() != 0 {
+			t.Errorf("updateMemory(%v) = %q, %v, %v, want skipped", runErr, out.String(), cost, err)
+		}
+	}
+}
+
+func TestUpdateMemory_ReturnsCost(t *testing.T) {
+	// A stand-in claude that remembers one fact
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho '{\"type\":\"result\",\"result\":\"- We use pnpm\",\"total_cost_usd\":0.04}'\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var out bytes.Buffer
+	cost, err := updateMemory(&out, &config.Config{CheckerModel: "haiku", MaxBudget: 5}, nil, t.TempDir(), nil, "", "", nil)
+	if err != nil {
+		t.Fatalf("updateMemory() error = %v", err)
+	}
+	if cost != 0.04 {
+		t.Errorf("updateMemory() cost = %v, want 0.04", cost)
+	}
+	if !strings.Contains(out.String(), "Remembered 1 fact(s)") {
+		t.Errorf("updateMemory() output = %q", out.String())
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newSpecDir         string
+	newSpecDescription string
+	newSpecFill        bool
+)
+
+var newSpecCmd = newNewSpecCmd()
+
+func newNewSpecCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new-spec <title>",
+		Short: "Create a new spec from a template",
+		Long: `Create a new spec file from a template.
+
+The spec is written to docs/plans/<date>-<title>.md with sections for
+context, acceptance criteria (as checkboxes), and out of scope.
+
+Set spec_template in .orbital/config.toml to use your own template.
+Placeholders: {{title}}, {{date}}, {{description}}, {{criteria}}.
+
+With --fill, the model drafts acceptance criteria from the title and
+--description before the file is written.`,
+		Example: `  orbital new-spec "user auth"
+  orbital new-spec "user auth" --description "email and password login" --fill`,
+		Args: cobra.ExactArgs(1),
+		RunE: runNewSpec,
+	}
+	cmd.Flags().StringVar(&newSpecDir, "dir", spec.DefaultSpecDir, "Directory to create the spec in (relative to the working directory)")
+	cmd.Flags().StringVar(&newSpecDescription, "description", "", "One-line description of the work (default: the title)")
+	cmd.Flags().BoolVar(&newSpecFill, "fill", false, "Ask the model to draft acceptance criteria")
+	return cmd
+}
+
+func runNewSpec(cmd *cobra.Command, args []string) error {
+	title := args[0]
+	description := newSpecDescription
+	if description == "" {
+		description = title
+	}
+
+	fileConfig, err := loadFileConfig(workingDir)
+	if err != nil {
+		return err
+	}
+	var template string
+	if fileConfig != nil {
+		template = fileConfig.SpecTemplate
+	}
+
+	var criteria []string
+	if newSpecFill {
+		criteria, err = draftCriteria(cmd.Context(), title, description)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	dir := newSpecDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workingDir, dir)
+	}
+	path := spec.NewSpecPath(dir, title, now)
+	if err := spec.WriteNewSpec(path, spec.NewSpecContent(template, title, description, criteria, now)); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+	return nil
+}
+
+// draftCriteria asks the model for acceptance criteria for the new spec.
+func draftCriteria(ctx context.Context, title, description string) ([]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	exec := executor.New(&config.Config{
+		Model:     model,
+		MaxBudget: budget,
+	})
+	result, err := exec.Execute(ctx, spec.BuildCriteriaPrompt(title, description))
+	if err != nil {
+		return nil, fmt.Errorf("failed to draft acceptance criteria: %w", err)
+	}
+
+	criteria := spec.ParseCriteria(output.ExtractResult(result.Output))
+	if len(criteria) == 0 {
+		return nil, fmt.Errorf("model returned no acceptance criteria")
+	}
+	return criteria, nil
+}
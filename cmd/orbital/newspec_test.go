@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func executeNewSpec(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newNewSpecCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	defer func() {
+		newSpecDir = "docs/plans"
+		newSpecDescription = ""
+		newSpecFill = false
+	}()
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestNewSpecCmd_CreatesSpec(t *testing.T) {
+	chdirTemp(t)
+
+	out, err := executeNewSpec(t, "user auth", "--description", "email login")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	path := filepath.Join("docs", "plans", time.Now().Format("2006-01-02")+"-user-auth.md")
+	if !strings.Contains(out, path) {
+		t.Errorf("output = %q, want path %s", out, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("spec not written: %v", err)
+	}
+	for _, want := range []string{"# user auth", "email login", "- [ ] ", "## Out of Scope"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("spec missing %q:\n%s", want, data)
+		}
+	}
+
+	if _, err := executeNewSpec(t, "user auth"); err == nil {
+		t.Error("Execute() error = nil, want error when spec already exists")
+	}
+}
+
+func TestNewSpecCmd_UsesConfigTemplate(t *testing.T) {
+	chdirTemp(t)
+	if err := os.MkdirAll(".orbital", 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "spec_template = \"TEMPLATE {{title}}\\n{{criteria}}\\n\"\n"
+	if err := os.WriteFile(filepath.Join(".orbital", "config.toml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := executeNewSpec(t, "billing", "--dir", "specs"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join("specs", time.Now().Format("2006-01-02")+"-billing.md"))
+	if err != nil {
+		t.Fatalf("spec not written: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "TEMPLATE billing\n- [ ] ") {
+		t.Errorf("spec = %q, want config template", data)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// resolveOffline applies the [network] config section and then the
+// --offline-timeout flag, if given, to cfg.
+func resolveOffline(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.Network != nil {
+		cfg.OfflineTimeout = fileConfig.Network.OfflineTimeout.Duration()
+	}
+	if cmd.Flags().Changed("offline-timeout") {
+		cfg.OfflineTimeout = offlineTimeout
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// newOfflineWaiter returns the waiter that pauses the session while the API
+// is unreachable, probing the address claude talks to.
+func newOfflineWaiter(cfg *config.Config, st *state.State, tuiProgram *tui.Program) *executor.OfflineWaiter {
+	return executor.NewOfflineWaiter(executor.DialProbe(executor.ProbeAddr()), cfg.OfflineTimeout, offlineReporter(st, tuiProgram))
+}
+
+// offlineReporter reports the session pausing and resuming, and records the
+// pause in st so 'orbital status' shows it.
+func offlineReporter(st *state.State, tuiProgram *tui.Program) executor.OfflineCallback {
+	return func(e executor.OfflineEvent) {
+		icon := "⏸ "
+		if e.Online {
+			icon = "▶ "
+		}
+		if tuiProgram != nil {
+			tuiProgram.SendOutput(icon + e.String())
+		} else {
+			fmt.Printf("\n%s\n", e.String())
+		}
+
+		if st == nil {
+			return
+		}
+		switch {
+		case e.Online:
+			st.OfflineSince = nil
+		case st.OfflineSince == nil:
+			since := time.Now().Add(-e.Offline)
+			st.OfflineSince = &since
+		default:
+			return
+		}
+		if err := st.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save state: %v\n", err)
+		}
+	}
+}
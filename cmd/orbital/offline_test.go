@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveOffline(t *testing.T) {
+	oldTimeout := offlineTimeout
+	defer func() { offlineTimeout = oldTimeout }()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().DurationVar(&offlineTimeout, "offline-timeout", 0, "")
+		return cmd
+	}
+	newCfg := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.SpecPath = "spec.md"
+		return cfg
+	}
+
+	fileConfig := &config.FileConfig{Network: &config.NetworkConfig{OfflineTimeout: workflow.Duration(time.Hour)}}
+	cfg := newCfg()
+	if err := resolveOffline(newCmd(), cfg, fileConfig); err != nil {
+		t.Fatalf("resolveOffline() error = %v", err)
+	}
+	if cfg.OfflineTimeout != time.Hour {
+		t.Errorf("OfflineTimeout = %v, want 1h from [network]", cfg.OfflineTimeout)
+	}
+
+	cmd := newCmd()
+	_ = cmd.Flags().Set("offline-timeout", "10m")
+	cfg = newCfg()
+	if err := resolveOffline(cmd, cfg, fileConfig); err != nil {
+		t.Fatalf("resolveOffline() error = %v", err)
+	}
+	if cfg.OfflineTimeout != 10*time.Minute {
+		t.Errorf("OfflineTimeout = %v, want the flag's 10m", cfg.OfflineTimeout)
+	}
+
+	cmd = newCmd()
+	_ = cmd.Flags().Set("offline-timeout", "-1m")
+	if err := resolveOffline(cmd, newCfg(), nil); err == nil {
+		t.Error("resolveOffline() accepted a negative timeout")
+	}
+}
+
+func TestOfflineReporter_RecordsPauseInState(t *testing.T) {
+	dir := t.TempDir()
+	st := state.NewState("session-1", dir, []string{"spec.md"}, "", nil)
+	report := offlineReporter(st, nil)
+
+	report(executor.OfflineEvent{Retry: 5 * time.Second})
+	if st.OfflineSince == nil {
+		t.Fatal("OfflineSince not set after pausing")
+	}
+	since := *st.OfflineSince
+
+	// Later probes keep the time the session went offline
+	report(executor.OfflineEvent{Attempt: 1, Retry: 10 * time.Second, Offline: 5 * time.Second, Err: errors.New("no route to host")})
+	if st.OfflineSince == nil || !st.OfflineSince.Equal(since) {
+		t.Errorf("OfflineSince = %v, want %v", st.OfflineSince, since)
+	}
+
+	saved, err := state.Load(dir)
+	if err != nil {
+		t.Fatalf("state.Load() error = %v", err)
+	}
+	if saved.OfflineSince == nil {
+		t.Error("saved state has no OfflineSince")
+	}
+
+	report(executor.OfflineEvent{Online: true, Attempt: 2, Offline: 15 * time.Second})
+	if st.OfflineSince != nil {
+		t.Errorf("OfflineSince = %v after resuming, want nil", st.OfflineSince)
+	}
+}
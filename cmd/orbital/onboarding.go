@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// maxSpecCandidates is how many detected specs onboarding lists.
+const maxSpecCandidates = 5
+
+// testScanDepth bounds how deep onboarding looks for test files.
+const testScanDepth = 3
+
+// onboardingPlan is what onboarding proposes to set up in a repository that
+// has not used orbital before.
+type onboardingPlan struct {
+	// Specs are markdown files with unchecked items, newest first, relative
+	// to the working directory.
+	Specs []string
+
+	// Preset is the suggested workflow and PresetReason why it was chosen.
+	Preset       workflow.PresetName
+	PresetReason string
+
+	// Budget is the per-session budget in USD runs start with.
+	Budget float64
+
+	// NotesDir is where notes files are written for each spec.
+	NotesDir string
+}
+
+// needsOnboarding reports whether dir has never been set up for orbital,
+// i.e. it has no .orbital directory.
+func needsOnboarding(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ".orbital"))
+	return err != nil || !info.IsDir()
+}
+
+// planOnboarding inspects dir and proposes a setup for it.
+func planOnboarding(dir string, budget float64) onboardingPlan {
+	plan := onboardingPlan{
+		Specs:        findSpecCandidates(dir),
+		Preset:       workflow.PresetSpecDriven,
+		PresetReason: "no tests found; implement the spec item by item",
+		Budget:       budget,
+		NotesDir:     filepath.Join("docs", "notes"),
+	}
+	if hasTests(dir) {
+		plan.Preset = workflow.PresetTDD
+		plan.PresetReason = "the project has tests; red-green-refactor each item"
+	}
+	return plan
+}
+
+// findSpecCandidates returns the markdown files in dir, docs and docs/plans
+// that have unchecked "- [ ]" items, newest first.
+func findSpecCandidates(dir string) []string {
+	type candidate struct {
+		path    string
+		modTime int64
+	}
+	var found []candidate
+	for _, sub := range []string{".", "docs", spec.DefaultSpecDir} {
+		matches, _ := filepath.Glob(filepath.Join(dir, sub, "*.md"))
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			items, err := spec.UncheckedItems([]string{path})
+			if err != nil || len(items) == 0 {
+				continue
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				rel = path
+			}
+			found = append(found, candidate{path: rel, modTime: info.ModTime().UnixNano()})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].modTime > found[j].modTime })
+	specs := make([]string, 0, min(len(found), maxSpecCandidates))
+	for _, c := range found {
+		if len(specs) == maxSpecCandidates {
+			break
+		}
+		specs = append(specs, c.path)
+	}
+	return specs
+}
+
+// hasTests reports whether dir holds test files or test directories within
+// testScanDepth levels, skipping hidden and dependency directories.
+func hasTests(dir string) bool {
+	var found bool
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+			if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			rel, _ := filepath.Rel(dir, path)
+			if strings.Count(rel, string(filepath.Separator)) >= testScanDepth {
+				return filepath.SkipDir
+			}
+			if name == "test" || name == "tests" || name == "__tests__" {
+				found = true
+				return filepath.SkipAll
+			}
+			return nil
+		}
+		if isTestFile(name) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// isTestFile reports whether name follows a common test file convention.
+func isTestFile(name string) bool {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.HasSuffix(base, "_test") ||
+		strings.HasSuffix(base, ".test") ||
+		strings.HasSuffix(base, ".spec") ||
+		strings.HasPrefix(name, "test_")
+}
+
+// printOnboarding describes the plan.
+func printOnboarding(out io.Writer, plan onboardingPlan) {
+	_, _ = fmt.Fprintln(out, "Welcome to orbital. This directory has not been set up yet (no .orbital/).")
+	_, _ = fmt.Fprintln(out)
+	if len(plan.Specs) == 0 {
+		_, _ = fmt.Fprintf(out, "  Specs:     none found; create one with 'orbital new-spec <title>' (in %s)\n", spec.DefaultSpecDir)
+	} else {
+		_, _ = fmt.Fprintf(out, "  Specs:     %s\n", plan.Specs[0])
+		for _, s := range plan.Specs[1:] {
+			_, _ = fmt.Fprintf(out, "             %s\n", s)
+		}
+	}
+	_, _ = fmt.Fprintf(out, "  Workflow:  %s (%s)\n", plan.Preset, plan.PresetReason)
+	_, _ = fmt.Fprintf(out, "  Budget:    $%.2f per session (change with --budget)\n", plan.Budget)
+	_, _ = fmt.Fprintf(out, "  Notes:     %s/<date>-notes-<spec>.md, one per spec\n", filepath.ToSlash(plan.NotesDir))
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintf(out, "This creates .orbital/config.toml (%s workflow), %s and %s.\n",
+		plan.Preset, spec.DefaultSpecDir, filepath.ToSlash(plan.NotesDir))
+}
+
+// applyOnboarding creates the config file and directories the plan describes.
+func applyOnboarding(dir string, plan onboardingPlan) error {
+	orbitDir := filepath.Join(dir, ".orbital")
+	if err := os.MkdirAll(orbitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", orbitDir, err)
+	}
+	preset := string(plan.Preset)
+	if plan.Preset == workflow.DefaultPreset {
+		preset = ""
+	}
+	configPath := filepath.Join(orbitDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(generateConfigContent(preset)), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	for _, sub := range []string{spec.DefaultSpecDir, plan.NotesDir} {
+		path := filepath.Join(dir, sub)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// runOnboarding shows the onboarding plan for a repository without
+// .orbital and, after a single confirmation, creates everything it needs.
+func runOnboarding(cmd *cobra.Command) error {
+	out := cmd.OutOrStdout()
+	plan := planOnboarding(workingDir, budget)
+	printOnboarding(out, plan)
+
+	if !assumeYes {
+		if !stdinInteractive() {
+			_, _ = fmt.Fprintln(out, "\nRe-run with --yes to set this up, or run 'orbital init' and pass a spec file.")
+			return nil
+		}
+		if !confirmPrompt(cmd.InOrStdin(), out, "\nSet this up?") {
+			_, _ = fmt.Fprintln(out, "Nothing created.")
+			return nil
+		}
+	}
+
+	if err := applyOnboarding(workingDir, plan); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "\nCreated .orbital/config.toml, %s and %s.\n", spec.DefaultSpecDir, filepath.ToSlash(plan.NotesDir))
+	if len(plan.Specs) > 0 {
+		_, _ = fmt.Fprintf(out, "Next: orbital %s\n", plan.Specs[0])
+	} else {
+		_, _ = fmt.Fprintln(out, "Next: orbital new-spec \"<title>\", then orbital <spec-file>")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+func writeOnboardingFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNeedsOnboarding(t *testing.T) {
+	dir := t.TempDir()
+	if !needsOnboarding(dir) {
+		t.Error("needsOnboarding() = false for a directory without .orbital")
+	}
+	if err := os.Mkdir(filepath.Join(dir, ".orbital"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if needsOnboarding(dir) {
+		t.Error("needsOnboarding() = true for a directory with .orbital")
+	}
+}
+
+func TestFindSpecCandidates(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeOnboardingFile(t, filepath.Join(dir, "README.md"), "# Project\n\nNo tasks here.\n", now)
+	writeOnboardingFile(t, filepath.Join(dir, "TODO.md"), "- [ ] old task\n", now.Add(-2*time.Hour))
+	writeOnboardingFile(t, filepath.Join(dir, "docs", "plans", "auth.md"), "- [x] done\n- [ ] login\n", now)
+	writeOnboardingFile(t, filepath.Join(dir, "docs", "done.md"), "- [x] all done\n", now)
+	writeOnboardingFile(t, filepath.Join(dir, "docs", "deep", "nested.md"), "- [ ] too deep\n", now)
+
+	got := findSpecCandidates(dir)
+	want := []string{filepath.Join("docs", "plans", "auth.md"), "TODO.md"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("findSpecCandidates() = %v, want %v", got, want)
+	}
+}
+
+func TestFindSpecCandidates_Limit(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < maxSpecCandidates+2; i++ {
+		writeOnboardingFile(t, filepath.Join(dir, "docs", "plans", string(rune('a'+i))+".md"), "- [ ] task\n", time.Now())
+	}
+	if got := findSpecCandidates(dir); len(got) != maxSpecCandidates {
+		t.Errorf("findSpecCandidates() returned %d specs, want %d", len(got), maxSpecCandidates)
+	}
+}
+
+func TestPlanOnboarding_Preset(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  workflow.PresetName
+	}{
+		{name: "no tests", files: []string{"main.go"}, want: workflow.PresetSpecDriven},
+		{name: "go tests", files: []string{"pkg/foo/foo_test.go"}, want: workflow.PresetTDD},
+		{name: "js tests", files: []string{"src/app.test.ts"}, want: workflow.PresetTDD},
+		{name: "python tests", files: []string{"test_app.py"}, want: workflow.PresetTDD},
+		{name: "tests directory", files: []string{"tests/fixtures.json"}, want: workflow.PresetTDD},
+		{name: "tests in dependencies only", files: []string{"node_modules/lib/lib.test.js"}, want: workflow.PresetSpecDriven},
+		{name: "tests too deep", files: []string{"a/b/c/d/e_test.go"}, want: workflow.PresetSpecDriven},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				writeOnboardingFile(t, filepath.Join(dir, f), "", time.Now())
+			}
+			plan := planOnboarding(dir, 100)
+			if plan.Preset != tt.want {
+				t.Errorf("Preset = %s, want %s", plan.Preset, tt.want)
+			}
+			if plan.PresetReason == "" {
+				t.Error("PresetReason is empty")
+			}
+		})
+	}
+}
+
+func TestPrintOnboarding(t *testing.T) {
+	var buf bytes.Buffer
+	printOnboarding(&buf, onboardingPlan{
+		Specs:        []string{"docs/plans/auth.md", "TODO.md"},
+		Preset:       workflow.PresetTDD,
+		PresetReason: "the project has tests",
+		Budget:       25,
+		NotesDir:     "docs/notes",
+	})
+	out := buf.String()
+	for _, want := range []string{
+		"Specs:     docs/plans/auth.md",
+		"             TODO.md",
+		"Workflow:  tdd (the project has tests)",
+		"Budget:    $25.00 per session",
+		"Notes:     docs/notes/",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunOnboarding(t *testing.T) {
+	oldDir, oldYes, oldNonInteractive := workingDir, assumeYes, nonInteractive
+	defer func() { workingDir, assumeYes, nonInteractive = oldDir, oldYes, oldNonInteractive }()
+
+	run := func(t *testing.T) string {
+		t.Helper()
+		var buf bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&buf)
+		if err := runOnboarding(cmd); err != nil {
+			t.Fatalf("runOnboarding() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("non-interactive without --yes creates nothing", func(t *testing.T) {
+		workingDir = t.TempDir()
+		assumeYes, nonInteractive = false, true
+
+		out := run(t)
+		if !strings.Contains(out, "--yes") {
+			t.Errorf("output does not mention --yes:\n%s", out)
+		}
+		if !needsOnboarding(workingDir) {
+			t.Error(".orbital was created without confirmation")
+		}
+	})
+
+	t.Run("--yes creates config and directories", func(t *testing.T) {
+		workingDir = t.TempDir()
+		assumeYes = true
+		writeOnboardingFile(t, filepath.Join(workingDir, "docs", "plans", "auth.md"), "- [ ] login\n", time.Now())
+		writeOnboardingFile(t, filepath.Join(workingDir, "auth_test.go"), "", time.Now())
+
+		out := run(t)
+		config, err := os.ReadFile(filepath.Join(workingDir, ".orbital", "config.toml"))
+		if err != nil {
+			t.Fatalf("config not written: %v", err)
+		}
+		if !strings.Contains(string(config), `name = "tdd"`) {
+			t.Errorf("config does not use the suggested tdd workflow:\n%s", config)
+		}
+		for _, sub := range []string{"docs/plans", "docs/notes"} {
+			if info, err := os.Stat(filepath.Join(workingDir, sub)); err != nil || !info.IsDir() {
+				t.Errorf("%s was not created", sub)
+			}
+		}
+		if want := "Next: orbital " + filepath.Join("docs", "plans", "auth.md"); !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	})
+}
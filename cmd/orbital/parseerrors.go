@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+)
+
+// newQuarantine returns the quarantine for malformed lines in claude's
+// output, in .orbital/parse-errors.log. The first one is reported; the TUI
+// also records each in the event log with the running count.
+func newQuarantine(workingDir string, tuiProgram *tui.Program) *output.Quarantine {
+	path := output.QuarantinePath(workingDir)
+	return output.NewQuarantine(path, func(count int, line output.MalformedLine) {
+		if tuiProgram != nil {
+			tuiProgram.SendParseError(count, line.Line, path)
+			return
+		}
+		if count == 1 {
+			fmt.Fprintf(os.Stderr, "Warning: skipped a malformed output line; quarantined to %s\n", path)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+// preflightSummaryPrompt asks the checker model to summarise the part of a
+// prompt that did not fit the context window. %s is that part.
+const preflightSummaryPrompt = `The text below was cut from the middle of a prompt that is too large for
+the model's context window. Summarise it so the model can still act on it:
+keep every instruction, requirement, file path, name and open question, and
+drop repetition, logs and examples. Do not modify any files. Reply with the
+summary only.
+
+%s`
+
+// resolvePreflight applies the [preflight] config section to cfg.
+func resolvePreflight(cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.Preflight != nil {
+		cfg.PreflightAction = fileConfig.Preflight.Action
+		cfg.PreflightReserve = fileConfig.Preflight.Reserve
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// preflightSummariser returns the function summarising the middle of an
+// oversized prompt with the checker model. Text too large for the checker
+// is trimmed first.
+func preflightSummariser(cfg *config.Config, redactor *redact.Redactor) executor.SummariseFunc {
+	return func(ctx context.Context, text string) (string, float64, error) {
+		room := config.GetContextWindow(cfg.CheckerModel) - cfg.EffectivePreflightReserve() - workflow.EstimateTokens(preflightSummaryPrompt)
+		summaryExec := executor.New(&config.Config{
+			Model:           cfg.CheckerModel,
+			MaxBudget:       cfg.MaxBudget,
+			WorkingDir:      cfg.WorkingDir,
+			PreflightAction: config.PreflightOff,
+		})
+		summaryExec.SetRedactor(redactor)
+		result, err := summaryExec.Execute(ctx, fmt.Sprintf(preflightSummaryPrompt, executor.TrimMiddle(text, room)))
+		var cost float64
+		if result != nil {
+			cost = result.CostUSD
+		}
+		if err != nil {
+			return "", cost, err
+		}
+		summary := strings.TrimSpace(output.ExtractResult(result.Output))
+		if summary == "" {
+			return "", cost, errors.New("empty summary")
+		}
+		return summary, cost, nil
+	}
+}
+
+// preflightReporter returns a callback logging what the preflight did with
+// an oversized prompt.
+func preflightReporter(tuiProgram *tui.Program) executor.PreflightCallback {
+	return func(d executor.PreflightDecision) {
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("⚠ " + d.String())
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: "+d.String())
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+func TestResolvePreflight(t *testing.T) {
+	newCfg := func() *config.Config {
+		cfg := config.NewConfig()
+		cfg.SpecPath = "spec.md"
+		return cfg
+	}
+
+	cfg := newCfg()
+	if err := resolvePreflight(cfg, nil); err != nil {
+		t.Fatalf("resolvePreflight() error = %v", err)
+	}
+	if cfg.PreflightAction != "" || cfg.EffectivePreflightReserve() != config.DefaultPreflightReserve {
+		t.Errorf("defaults = %q, %d; want refuse with the default reserve", cfg.PreflightAction, cfg.EffectivePreflightReserve())
+	}
+
+	cfg = newCfg()
+	fileConfig := &config.FileConfig{Preflight: &config.PreflightConfig{Action: "summarise", Reserve: 8000}}
+	if err := resolvePreflight(cfg, fileConfig); err != nil {
+		t.Fatalf("resolvePreflight() error = %v", err)
+	}
+	if cfg.PreflightAction != config.PreflightSummarise || cfg.PreflightReserve != 8000 {
+		t.Errorf("cfg = %q, %d; want summarise with an 8000 token reserve", cfg.PreflightAction, cfg.PreflightReserve)
+	}
+
+	fileConfig.Preflight.Action = "truncate"
+	if err := resolvePreflight(newCfg(), fileConfig); err == nil || !strings.Contains(err.Error(), "invalid preflight action") {
+		t.Errorf("resolvePreflight(truncate) error = %v, want invalid preflight action", err)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"golang.org/x/term"
+)
+
+// validateProgressMode checks the --progress value.
+func validateProgressMode(mode string) error {
+	switch mode {
+	case output.ProgressAuto, output.ProgressPlain, output.ProgressNone:
+		return nil
+	default:
+		return fmt.Errorf("unknown progress mode %q: must be %s, %s, or %s", mode, output.ProgressAuto, output.ProgressPlain, output.ProgressNone)
+	}
+}
+
+// newProgressLine returns the progress line for a session without the TUI,
+// or nil when none is shown. With --progress auto it is shown in quiet mode
+// only, rewritten in place on a terminal and as heartbeat lines in CI or
+// when redirected; --progress plain always prints heartbeat lines. It goes
+// to stderr, so the summary on stdout stays clean.
+func newProgressLine(mode string, tuiProgram *tui.Program, start time.Time) *output.ProgressLine {
+	if tuiProgram != nil || debug {
+		return nil
+	}
+	switch mode {
+	case output.ProgressPlain:
+		return output.NewProgressLine(os.Stderr, false, start)
+	case output.ProgressAuto:
+		if !quiet {
+			return nil
+		}
+		live := os.Getenv("CI") == "" && term.IsTerminal(int(os.Stderr.Fd()))
+		return output.NewProgressLine(os.Stderr, live, start)
+	default:
+		return nil
+	}
+}
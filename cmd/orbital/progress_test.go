@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+)
+
+func TestValidateProgressMode(t *testing.T) {
+	for _, mode := range []string{output.ProgressAuto, output.ProgressPlain, output.ProgressNone} {
+		if err := validateProgressMode(mode); err != nil {
+			t.Errorf("validateProgressMode(%q) = %v", mode, err)
+		}
+	}
+	if err := validateProgressMode("fancy"); err == nil || !strings.Contains(err.Error(), `unknown progress mode "fancy"`) {
+		t.Errorf("validateProgressMode(fancy) = %v, want an error", err)
+	}
+}
+
+func TestNewProgressLine(t *testing.T) {
+	oldQuiet, oldDebug := quiet, debug
+	defer func() { quiet, debug = oldQuiet, oldDebug }()
+
+	tests := []struct {
+		name  string
+		mode  string
+		quiet bool
+		debug bool
+		want  bool
+	}{
+		{"auto in quiet mode", output.ProgressAuto, true, false, true},
+		{"auto with verbose output", output.ProgressAuto, false, false, false},
+		{"plain with verbose output", output.ProgressPlain, false, false, true},
+		{"none in quiet mode", output.ProgressNone, true, false, false},
+		{"debug streams raw output", output.ProgressPlain, false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet, debug = tt.quiet, tt.debug
+			if got := newProgressLine(tt.mode, nil, time.Now()) != nil; got != tt.want {
+				t.Errorf("newProgressLine() shown = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackIteration  int
+	rollbackCheckpoint string
+)
+
+var rollbackCmd = newRollbackCmd()
+
+func newRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback (--to-iteration <n> | --to-checkpoint <name>)",
+		Short: "Restore the working tree to an earlier iteration",
+		Long: `Restore the working tree to how it was at the end of an earlier iteration.
+
+Orbital snapshots the working tree after every iteration of a session, and
+once before the first. Rolling back resets HEAD to the commit the snapshot
+was taken on, makes the working tree match the snapshot (removing files
+created since), and trims the session state so 'orbital continue' resumes
+from that iteration. Use --to-iteration 0 to undo the whole session.
+
+Named checkpoints ("schema migrated", "tests green") declared by Claude or
+marked in the TUI can be used instead of iteration numbers with
+--to-checkpoint. 'orbital status' lists them.
+
+The session must be stopped. Total cost is kept, since it was spent.`,
+		Args: cobra.NoArgs,
+		RunE: runRollback,
+	}
+	cmd.Flags().IntVar(&rollbackIteration, "to-iteration", -1, "Iteration to roll back to (0 = before the first iteration)")
+	cmd.Flags().StringVar(&rollbackCheckpoint, "to-checkpoint", "", "Named checkpoint to roll back to")
+	cmd.MarkFlagsOneRequired("to-iteration", "to-checkpoint")
+	cmd.MarkFlagsMutuallyExclusive("to-iteration", "to-checkpoint")
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	if rollbackCheckpoint == "" && rollbackIteration < 0 {
+		return errors.New("--to-iteration must be 0 or greater")
+	}
+
+	if !state.Exists(workingDir) {
+		return errors.New("no orbital session in this directory")
+	}
+	st, err := state.Load(workingDir)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if !st.IsStale() {
+		return fmt.Errorf("session %s is still running (PID %d); stop it before rolling back", st.SessionID, st.PID)
+	}
+
+	fileConfig, err := loadFileConfig(workingDir)
+	if err != nil {
+		return err
+	}
+	repo, err := resolveVCS(fileConfig, workingDir)
+	if err != nil {
+		return err
+	}
+
+	iteration := rollbackIteration
+	if rollbackCheckpoint != "" {
+		cp, err := findCheckpoint(st, rollbackCheckpoint)
+		if err != nil {
+			return err
+		}
+		iteration = cp.Iteration
+	}
+
+	if err := rollbackSession(context.Background(), repo, st, iteration); err != nil {
+		return err
+	}
+
+	if rollbackCheckpoint != "" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Rolled back session %s to checkpoint %q (iteration %d)\n", st.SessionID, rollbackCheckpoint, iteration)
+	} else {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Rolled back session %s to iteration %d\n", st.SessionID, iteration)
+	}
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Resume with: orbital continue")
+	return nil
+}
+
+// findCheckpoint looks up a named checkpoint of the session.
+func findCheckpoint(st *state.State, name string) (state.Checkpoint, error) {
+	cp, ok := st.FindCheckpoint(name)
+	if !ok {
+		return state.Checkpoint{}, fmt.Errorf("no checkpoint named %q in session %s", name, st.SessionID)
+	}
+	return cp, nil
+}
+
+// rollbackSession restores the working tree to the snapshot taken after the
+// given iteration and trims the session state back to it.
+func rollbackSession(ctx context.Context, repo vcs.Backend, st *state.State, iteration int) error {
+	if repo.Name() == vcs.BackendNone {
+		return fmt.Errorf("rollback needs version control: %s is not a git repository (or vcs = \"none\")", st.WorkingDir)
+	}
+
+	snap, ok := st.FindSnapshot(iteration)
+	if !ok {
+		return fmt.Errorf("no snapshot for iteration %d in session %s", iteration, st.SessionID)
+	}
+
+	if err := repo.Restore(ctx, snap.Commit); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	st.RollbackTo(iteration)
+	if err := st.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	if err := repo.DeleteSnapshots(ctx, st.SessionID, iteration); err != nil {
+		return fmt.Errorf("failed to delete later snapshots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+	"github.com/flashingpumpkin/orbital/internal/state"
+)
+
+// chdirTemp changes into a fresh temp directory for the test and returns it.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(originalWd); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	})
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	return tempDir
+}
+
+func executeRollback(t *testing.T, args ...string) error {
+	t.Helper()
+	cmd := newRollbackCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	defer func() { rollbackIteration, rollbackCheckpoint = -1, "" }()
+	return cmd.Execute()
+}
+
+func TestRollbackCmd_NoSession(t *testing.T) {
+	chdirTemp(t)
+
+	err := executeRollback(t, "--to-iteration", "1")
+	if err == nil || !strings.Contains(err.Error(), "no orbital session") {
+		t.Errorf("Execute() error = %v, want no session error", err)
+	}
+}
+
+func TestRollbackCmd_MissingSnapshot(t *testing.T) {
+	dir := chdirTemp(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if _, err := git.Run(context.Background(), dir, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+
+	st := state.NewState("sess1234", dir, []string{"spec.md"}, "", nil)
+	st.PID = 999999999 // not running
+	st.RecordSnapshot(0, "abc123")
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	err := executeRollback(t, "--to-iteration", "3")
+	if err == nil || !strings.Contains(err.Error(), "no snapshot for iteration 3") {
+		t.Errorf("Execute() error = %v, want missing snapshot error", err)
+	}
+}
+
+func TestRollbackCmd_NoVCS(t *testing.T) {
+	dir := chdirTemp(t)
+
+	st := state.NewState("sess1234", dir, []string{"spec.md"}, "", nil)
+	st.PID = 999999999 // not running
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	err := executeRollback(t, "--to-iteration", "0")
+	if err == nil || !strings.Contains(err.Error(), "rollback needs version control") {
+		t.Errorf("Execute() error = %v, want version control error", err)
+	}
+}
+
+func TestRollbackCmd_RequiresIteration(t *testing.T) {
+	chdirTemp(t)
+
+	if err := executeRollback(t); err == nil {
+		t.Error("Execute() error = nil, want error when --to-iteration is missing")
+	}
+}
+
+func TestRollbackCmd_UnknownCheckpoint(t *testing.T) {
+	dir := chdirTemp(t)
+
+	st := state.NewState("sess1234", dir, []string{"spec.md"}, "", nil)
+	st.PID = 999999999 // not running
+	st.RecordSnapshot(1, "abc123")
+	st.RecordCheckpoint("schema migrated", 1)
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	err := executeRollback(t, "--to-checkpoint", "tests green")
+	if err == nil || !strings.Contains(err.Error(), `no checkpoint named "tests green"`) {
+		t.Errorf("Execute() error = %v, want unknown checkpoint error", err)
+	}
+}
+
+func TestRollbackCmd_IterationAndCheckpointExclusive(t *testing.T) {
+	chdirTemp(t)
+
+	if err := executeRollback(t, "--to-iteration", "1", "--to-checkpoint", "tests green"); err == nil {
+		t.Error("Execute() error = nil, want error when both targets are given")
+	}
+}
+
+func TestRollbackCmd_ToCheckpoint(t *testing.T) {
+	dir := chdirTemp(t)
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if _, err := git.Run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st := state.NewState("sess1234", dir, []string{"spec.md"}, "", nil)
+	st.PID = 999999999 // not running
+	if err := os.WriteFile("schema.sql", []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit, err := git.CreateSnapshot(ctx, dir, git.SnapshotRef(st.SessionID, 1), "iteration 1")
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	st.RecordSnapshot(1, commit)
+	st.RecordCheckpoint("schema migrated", 1)
+	st.RecordSnapshot(2, commit)
+	st.RecordCheckpoint("tests green", 2)
+	st.UpdateIteration(2, 1.5)
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	if err := os.WriteFile("schema.sql", []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeRollback(t, "--to-checkpoint", "schema migrated"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	data, err := os.ReadFile("schema.sql")
+	if err != nil || string(data) != "v1" {
+		t.Errorf("schema.sql = %q, %v; want the checkpoint's contents", data, err)
+	}
+	loaded, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Iteration != 1 || len(loaded.Checkpoints) != 1 {
+		t.Errorf("state after rollback: iteration %d, checkpoints %+v", loaded.Iteration, loaded.Checkpoints)
+	}
+}
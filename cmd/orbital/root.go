@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -14,25 +15,43 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/flashingpumpkin/orbital/internal/audit"
 	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/environment"
 	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/ghissue"
 	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/memory"
+	"github.com/flashingpumpkin/orbital/internal/notify"
 	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+	"github.com/flashingpumpkin/orbital/internal/runs"
 	"github.com/flashingpumpkin/orbital/internal/spec"
 	"github.com/flashingpumpkin/orbital/internal/state"
 	"github.com/flashingpumpkin/orbital/internal/tasks"
+	"github.com/flashingpumpkin/orbital/internal/telemetry"
 	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
 	"github.com/flashingpumpkin/orbital/internal/workflow"
 	"golang.org/x/term"
 )
 
+// version is the orbital version. Release builds set it with
+// -ldflags "-X main.version=<version>".
+var version = "0.1.0"
+
 var (
 	// Flag variables
 	iterations          int
 	promise             string
 	model               string
+	fallbackModels      []string
 	checkerModel        string
 	budget              float64
+	budgetReserve       float64
+	budgetWarn          string
 	workingDir          string
 	configFile          string
 	quiet               bool
@@ -43,16 +62,47 @@ var (
 	sessionID           string
 	timeout             time.Duration
 	maxTurns            int
+	maxTurnsCeiling     int
+	burst               int
+	burstModel          string
+	burstMaxTurns       int
+	fromBranch          string
+	worktreeDir         string
+	sandboxMode         string
+	progressMode        string
+	noCache             bool
+	contextBudget       float64
 	systemPrompt        string
 	agents              string
 	notesFile           string
 	contextFiles        []string
 	workflowFlag   string
+	skipSteps      []string
 	minimal        bool
 	nonInteractive bool
 	dangerous      bool
 	maxOutputSize  int
 	themeFlag      string
+	taskFlag       string
+	autoContext    bool
+	claudeArgs     []string
+	assumeYes      bool
+	stallTimeout   time.Duration
+	stallAction    string
+	offlineTimeout time.Duration
+	archiveKeep    int
+	injectFile     string
+	leanPrompts    bool
+	doubleCheck    bool
+	doubleCheckModel string
+	tickSpec         bool
+	followUp         bool
+	optimisticVerify bool
+
+	// Executor backend
+	executorName   string
+	fixturesDir    string
+	recordFixtures bool
 )
 
 var rootCmd = &cobra.Command{
@@ -69,17 +119,21 @@ Named after Ralph Wiggum's optimistic persistence: "I'm learnding!"
 USAGE
 
     orbital <spec-file> [--context <file>]... [--notes <file>] [flags]
+    orbital - < spec.md
+    orbital --task "fix the flaky TestFoo"
+    orbital    (first run in a directory without .orbital: guided setup)
 
-The spec file contains the main task specification. Additional context files
-can be provided with --context (repeatable). A notes file for cross-iteration
-context can be specified with --notes.
+The spec file contains the main task specification. Use - to read the spec
+from stdin, or --task to run a one-off task without a spec file. Additional
+context files can be provided with --context (repeatable). A notes file for
+cross-iteration context can be specified with --notes.
 
 CONFIGURATION FILE
 
 Orbital can be configured via a TOML file. By default, it looks for .orbital/config.toml
 in the working directory. Use --config to specify a different path.`,
-	Args:    cobra.ExactArgs(1),
-	Version: "0.1.0",
+	Args:    validateRootArgs,
+	Version: version,
 	RunE:    runOrbit,
 }
 
@@ -88,13 +142,32 @@ func init() {
 	rootCmd.AddCommand(continueCmd)
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(stateCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(blameCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(newSpecCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+	rootCmd.AddCommand(sandboxCmd)
+	rootCmd.AddCommand(approveCmd)
+	rootCmd.AddCommand(auditCmd)
+
+	// Complete spec files under docs/plans/
+	rootCmd.ValidArgsFunction = completeSpecFiles
 
 	// Register persistent flags (inherited by subcommands like 'continue')
 	rootCmd.PersistentFlags().IntVarP(&iterations, "iterations", "n", 50, "Maximum number of loop iterations")
 	rootCmd.PersistentFlags().StringVarP(&promise, "promise", "p", "<promise>COMPLETE</promise>", "Completion promise string to detect")
 	rootCmd.PersistentFlags().StringVarP(&model, "model", "m", "opus", "Claude model to use for execution")
+	rootCmd.PersistentFlags().StringSliceVar(&fallbackModels, "fallback", nil, "Comma-separated fallback models used when the primary model keeps failing or is rate limited")
 	rootCmd.PersistentFlags().StringVar(&checkerModel, "checker-model", "haiku", "Claude model to use for completion checking")
 	rootCmd.PersistentFlags().Float64VarP(&budget, "budget", "b", 100.00, "Maximum budget in USD")
+	rootCmd.PersistentFlags().Float64Var(&budgetReserve, "budget-reserve", config.DefaultBudgetReserve, "Percentage of --budget reserved for verification")
+	rootCmd.PersistentFlags().StringVar(&budgetWarn, "budget-warn", "", "Comma-separated fractions of --budget at which to warn, e.g. 0.5,0.8")
 	rootCmd.PersistentFlags().StringVarP(&workingDir, "working-dir", "d", ".", "Working directory for execution")
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (default: .orbital/config.toml)")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress verbose output")
@@ -104,30 +177,148 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Run without executing commands")
 	rootCmd.PersistentFlags().StringVarP(&sessionID, "session-id", "s", "", "Session ID for resuming")
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "t", 5*time.Minute, "Timeout per iteration")
+	rootCmd.PersistentFlags().DurationVar(&stallTimeout, "stall-timeout", 0, "Treat claude as stalled after this long without output (0 = disabled)")
+	rootCmd.PersistentFlags().StringVar(&stallAction, "stall-action", config.StallActionRetry, "On stall: retry (kill and move to the next iteration) or warn")
+	rootCmd.PersistentFlags().DurationVar(&offlineTimeout, "offline-timeout", 0, "Stop after the network has been down this long (0 = pause until it is back)")
+	rootCmd.PersistentFlags().IntVar(&archiveKeep, "archive-keep", config.DefaultArchiveKeep, "Completed sessions to keep in .orbital/archive (0 = delete state on completion)")
 	rootCmd.PersistentFlags().IntVar(&maxTurns, "max-turns", 0, "Max agentic turns per iteration (0 = unlimited)")
+	rootCmd.PersistentFlags().IntVar(&maxTurnsCeiling, "max-turns-ceiling", 0, "Auto-tune --max-turns between iterations, raising it up to this many turns (0 = off)")
 	rootCmd.PersistentFlags().StringVar(&systemPrompt, "system-prompt", "", "Custom system prompt (overrides default)")
 	rootCmd.PersistentFlags().StringVar(&agents, "agents", "", "JSON object defining custom agents for Claude CLI")
+	rootCmd.PersistentFlags().StringVar(&injectFile, "inject-file", loop.DefaultInjectFile, "File watched for instructions appended once to the next iteration's prompt")
+	rootCmd.PersistentFlags().BoolVar(&doubleCheck, "double-check", false, "Confirm a passing verification with a second, independent check before completing")
+	rootCmd.PersistentFlags().StringVar(&doubleCheckModel, "double-check-model", loop.DefaultDoubleCheckModel, "Model for the --double-check verification")
+	rootCmd.PersistentFlags().BoolVar(&tickSpec, "tick-spec", false, "Tick the spec items the checker model finds done after each failed verification")
+	rootCmd.PersistentFlags().BoolVar(&followUp, "follow-up", false, "When max iterations or budget stop the run with items unchecked, write a follow-up spec with the remaining items")
+	rootCmd.PersistentFlags().BoolVar(&leanPrompts, "lean-prompts", false, "After a failed verification or gate, prompt with only the unchecked items and the feedback instead of the recent notes")
 	rootCmd.PersistentFlags().StringVar(&notesFile, "notes", "", "Path to notes file (default: auto-generated in docs/notes/)")
+	rootCmd.PersistentFlags().BoolVar(&optimisticVerify, "optimistic-verify", false, "Start the next iteration while a completion promise is verified, cancelling it if verification passes")
+	rootCmd.PersistentFlags().StringVar(&executorName, "executor", executor.BackendClaude, "Executor backend: "+strings.Join(executor.Backends(), ", ")+" (mock replays recorded fixtures)")
+	rootCmd.PersistentFlags().StringVar(&fixturesDir, "fixtures", defaultFixturesDir, "Directory of stream-json fixtures replayed by --executor mock and written by --record-fixtures")
+	rootCmd.PersistentFlags().BoolVar(&recordFixtures, "record-fixtures", false, "Save each Claude run's output as a fixture in --fixtures")
 	rootCmd.PersistentFlags().StringArrayVar(&contextFiles, "context", []string{}, "Additional context file (can be repeated)")
-	rootCmd.PersistentFlags().StringVar(&workflowFlag, "workflow", "", "Workflow preset: fast, spec-driven (default), reviewed, tdd, autonomous")
+	rootCmd.PersistentFlags().StringVar(&workflowFlag, "workflow", "", "Workflow preset: fast, spec-driven (default), reviewed, tdd, autonomous, panel")
+	_ = rootCmd.RegisterFlagCompletionFunc("workflow", completeWorkflows)
+	rootCmd.PersistentFlags().StringArrayVar(&skipSteps, "skip-step", []string{}, "Run a workflow step dry: log its prompt instead of sending it and pass its gate (can be repeated)")
 	rootCmd.PersistentFlags().BoolVar(&minimal, "minimal", false, "Use minimal output mode (no TUI)")
+	rootCmd.PersistentFlags().StringVar(&progressMode, "progress", output.ProgressAuto, "Progress line without the TUI: auto (in quiet mode), plain (heartbeat lines), or none")
 	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Error if interactive selection would be needed")
 	rootCmd.PersistentFlags().BoolVar(&dangerous, "dangerous", false, "Enable --dangerously-skip-permissions for Claude CLI (allows execution without permission prompts)")
 	rootCmd.PersistentFlags().IntVar(&maxOutputSize, "max-output-size", config.DefaultMaxOutputSize, "Maximum output size in bytes to retain (0 = unlimited)")
 	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "auto", "Colour theme: auto (detect), dark, light")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Skip confirmation prompts (required for --dangerous without a terminal)")
+	rootCmd.PersistentFlags().StringArrayVar(&claudeArgs, "claude-arg", []string{}, "Extra flag passed to the Claude CLI (can be repeated)")
+	rootCmd.Flags().StringVar(&taskFlag, "task", "", "Run an inline task without a spec file")
+	rootCmd.Flags().BoolVar(&autoContext, "auto-context", false, "Add existing files referenced in the spec as context files")
+	rootCmd.Flags().IntVar(&burst, "burst", 0, "Run the first N iterations on --burst-model with --burst-max-turns before switching to --model")
+	rootCmd.Flags().StringVar(&burstModel, "burst-model", config.DefaultBurstModel, "Cheap model for --burst iterations")
+	rootCmd.Flags().IntVar(&burstMaxTurns, "burst-max-turns", config.DefaultBurstMaxTurns, "Max agentic turns per --burst iteration")
+	rootCmd.Flags().StringVar(&fromBranch, "from-branch", "", "Continue the work on this branch in a new worktree, with a summary of it in the first prompt")
+	rootCmd.Flags().StringVar(&worktreeDir, "worktree-dir", "", "Where --from-branch creates the worktree (default: <repo>-<branch> next to the repository)")
+	rootCmd.Flags().StringVar(&sandboxMode, "sandbox", "", "Run the session in a copy of the project: copy (review and apply with 'orbital sandbox')")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Run cacheable workflow steps without reusing cached results")
+	rootCmd.Flags().Float64Var(&contextBudget, "context-budget", 0, "Trim step prompts to bring in at most this fraction of the context window, e.g. 0.5 (0 = no budget)")
+}
+
+// validateRootArgs requires exactly one spec file argument, or none when --task
+// is set or the working directory has not been set up yet (onboarding).
+func validateRootArgs(cmd *cobra.Command, args []string) error {
+	if taskFlag != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("cannot use --task together with a spec file")
+		}
+		return nil
+	}
+	if len(args) == 0 && fromBranch == "" && needsOnboarding(workingDir) {
+		return nil
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// resolveSpecPath returns the spec file to run. For "-" the spec is read from
+// stdin and for --task a single-checkbox spec is synthesised; both are written
+// to the state directory so they are removed when the session completes.
+func resolveSpecPath(args []string, task string, stdin io.Reader) (string, error) {
+	specDir := filepath.Join(state.StateDir(workingDir), "specs")
+
+	if task != "" {
+		return spec.WriteGenerated(specDir, task, spec.TaskContent(task))
+	}
+
+	if args[0] == spec.StdinPath {
+		content, err := spec.ReadStdin(stdin)
+		if err != nil {
+			return "", err
+		}
+		return spec.WriteGenerated(specDir, "stdin", content)
+	}
+
+	return args[0], nil
 }
 
 func runOrbit(cmd *cobra.Command, args []string) error {
-	specPath := args[0]
+	// First run in this directory: offer to set it up
+	if len(args) == 0 && taskFlag == "" {
+		return runOnboarding(cmd)
+	}
+	if err := validateProgressMode(progressMode); err != nil {
+		return err
+	}
+
+	// --from-branch: continue the branch's work in a new worktree
+	branchSeed, err := applyFromBranch(context.Background(), args, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	// --sandbox copy: run the session in a copy of the project
+	if err := enterSandbox(args, os.Stdout); err != nil {
+		return err
+	}
+
+	specPath, err := resolveSpecPath(args, taskFlag, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	// Defaults from the spec's front matter, below explicit flags
+	if err := applySpecDefaults(cmd, specPath, os.Stderr); err != nil {
+		return err
+	}
+
+	// Files referenced by the spec are added as context with --auto-context,
+	// otherwise they are only pointed out
+	autoFiles := discoverContextFiles(specPath, contextFiles)
+	ctxFiles := contextFiles
+	if autoContext {
+		ctxFiles = append(append([]string{}, contextFiles...), autoFiles...)
+	} else {
+		if len(autoFiles) > 0 && !quiet {
+			fmt.Fprintf(os.Stderr, "Spec references %d existing file(s) not passed with --context; use --auto-context to add them\n", len(autoFiles))
+		}
+		autoFiles = nil
+	}
+
+	// Context files outside the working directory are copied into it for
+	// Claude, while the user is shown the originals
+	stagedCtxFiles, err := stageExternalContext(workingDir, ctxFiles)
+	if err != nil {
+		return err
+	}
+	shownCtxFiles := contextDisplayPaths(workingDir, ctxFiles)
 
 	// Build list of all files: spec file + context files
-	allFiles := append([]string{specPath}, contextFiles...)
+	allFiles := append([]string{specPath}, stagedCtxFiles...)
 
 	// Get absolute paths for all files
 	absFilePaths, err := getAbsolutePaths(allFiles)
 	if err != nil {
 		return err
 	}
+	shownFilePaths, err := getAbsolutePaths(append([]string{specPath}, ctxFiles...))
+	if err != nil {
+		return err
+	}
 
 	// Verbose is default, quiet suppresses it
 	verbose := !quiet
@@ -140,8 +331,10 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 		MaxIterations:              iterations,
 		CompletionPromise:          promise,
 		Model:                      model,
+		FallbackModels:             fallbackModels,
 		CheckerModel:               checkerModel,
 		MaxBudget:                  budget,
+		BudgetReserve:              budgetReserve,
 		WorkingDir:                 workingDir,
 		Verbose:                    verbose,
 		Debug:                      debug,
@@ -153,6 +346,9 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 		DangerouslySkipPermissions: dangerous,
 		MaxOutputSize:              maxOutputSize,
 		Theme:                      themeFlag,
+		BurstIterations:            burst,
+		BurstModel:                 burstModel,
+		BurstMaxTurns:              burstMaxTurns,
 	}
 
 	// Validate configuration
@@ -161,22 +357,18 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load optional config file
-	var fileConfig *config.FileConfig
-	if configFile != "" {
-		// Use explicit config file path
-		fileConfig, err = config.LoadFileConfigFrom(configFile)
-		if err != nil {
-			return fmt.Errorf("failed to load config file %s: %w", configFile, err)
-		}
-		if fileConfig == nil {
-			return fmt.Errorf("config file not found: %s", configFile)
-		}
-	} else {
-		// Try default .orbital/config.toml
-		fileConfig, err = config.LoadFileConfig(workingDir)
-		if err != nil {
-			return fmt.Errorf("failed to load config file: %w", err)
-		}
+	fileConfig, err := loadFileConfig(workingDir)
+	if err != nil {
+		return err
+	}
+	if err := setupEncryption(fileConfig); err != nil {
+		return err
+	}
+	if err := setupDisplay(fileConfig); err != nil {
+		return err
+	}
+	if err := setupExecutor(workingDir); err != nil {
+		return err
 	}
 	if fileConfig != nil && fileConfig.Prompt != "" {
 		spec.PromptTemplate = fileConfig.Prompt
@@ -188,33 +380,87 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 		cfg.DangerouslySkipPermissions = true
 	}
 
-	// Warn if dangerous mode is enabled
+	// Dangerous mode must be confirmed interactively or with --yes
 	if cfg.DangerouslySkipPermissions {
-		fmt.Fprintln(os.Stderr, "WARNING: Running with --dangerous flag. Claude can execute commands without permission prompts.")
+		if err := confirmDangerous(os.Stdin, os.Stderr, stdinInteractive(), assumeYes, workingDir); err != nil {
+			return err
+		}
+	}
+
+	// Extra Claude CLI flags: config file first, then --claude-arg
+	cfg.ClaudeArgs, err = resolveClaudeArgs(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Secrets are masked before any output is displayed or persisted
+	redactor, err := resolveRedactor(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Stall detection: [stall] first, then --stall-timeout and --stall-action
+	if err := resolveStall(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Max turns auto-tuning: [max_turns] first, then --max-turns-ceiling
+	if err := resolveTurnTuning(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Offline pause: [network] first, then --offline-timeout
+	if err := resolveOffline(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// State archive retention: [archive] first, then --archive-keep
+	if err := resolveArchive(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Step cache: [cache] first, then --no-cache
+	if err := resolveCache(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Prompt budget: [context_budget] first, then --context-budget
+	if err := resolveContextBudget(cmd, cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// What happens to prompts too large for the context window: [preflight]
+	if err := resolvePreflight(cfg, fileConfig); err != nil {
+		return err
+	}
+
+	// Panel sizes and visibility from [tui]
+	tuiLayout, err := resolveTUILayout(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Operator instructions dropped here reach the next iteration
+	cfg.InjectFile = resolveInjectFile(workingDir)
+	cfg.InterjectFile = filepath.Join(workingDir, loop.DefaultInterjectFile)
+	cfg.SeedPrompt = branchSeed
+	cfg.LeanPrompts = leanPrompts
+	cfg.OptimisticVerify = optimisticVerify
+	if cfg.BudgetWarnings, err = resolveBudgetWarnings(fileConfig); err != nil {
+		return err
 	}
+	if doubleCheck {
+		cfg.DoubleCheckModel = doubleCheckModel
+	}
+	cfg.TickSpec = tickSpec
+
+	// Set up notifications from config file (disabled if not configured)
+	notifier := newNotifier(fileConfig)
 
 	// Handle agents: CLI flag takes precedence over config file, defaults always included
-	if agents != "" {
-		// CLI flag provided - merge with defaults via GetEffectiveAgents
-		agentsJSON, err := config.GetEffectiveAgents(agents)
-		if err != nil {
-			return fmt.Errorf("invalid --agents flag: %w", err)
-		}
-		cfg.Agents = agentsJSON
-	} else if fileConfig != nil && len(fileConfig.Agents) > 0 {
-		// Config file agents - AgentsToJSON already merges with defaults
-		agentsJSON, err := config.AgentsToJSON(fileConfig.Agents)
-		if err != nil {
-			return fmt.Errorf("failed to convert agents config: %w", err)
-		}
-		cfg.Agents = agentsJSON
-	} else {
-		// No user agents - use defaults only
-		agentsJSON, err := config.AgentsToJSON(nil)
-		if err != nil {
-			return fmt.Errorf("failed to get default agents: %w", err)
-		}
-		cfg.Agents = agentsJSON
+	cfg.Agents, err = resolveAgents(fileConfig)
+	if err != nil {
+		return err
 	}
 
 	// Set completion promise for prompt template
@@ -275,6 +521,8 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 	} else {
 		cfg.SystemPrompt = spec.BuildSystemPrompt()
 	}
+	facts := loadMemory(fileConfig, workingDir)
+	cfg.SystemPrompt += memory.FormatSystemPrompt(facts)
 
 	// Validate spec and context files exist
 	sp, err := spec.Validate(allFiles)
@@ -284,41 +532,118 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 
 	// Create executor
 	exec := executor.New(cfg)
+	exec.SetRedactor(redactor)
+
+	// Kill claude processes orphaned by a crashed run, then track our own
+	sweepOrphanedProcesses(workingDir)
+	exec.SetPIDDir(executor.PIDDir(workingDir))
+
+	// Version control backs snapshots, diff summaries and progress detection
+	repo, err := resolveVCS(fileConfig, workingDir)
+	if err != nil {
+		return err
+	}
+	warnNoVCS(os.Stderr, repo, workingDir)
 
 	// Resolve workflow from flag or config (early, for TUI progress info)
 	wf, err := resolveWorkflow(workflowFlag, fileConfig)
 	if err != nil {
 		return fmt.Errorf("failed to resolve workflow: %w", err)
 	}
+	if err := config.CheckAgentsDefined(cfg.Agents, wf.PanelAgents()); err != nil {
+		return fmt.Errorf("invalid review panel: %w", err)
+	}
+
+	// Steps named with --skip-step only log their prompts
+	if err := wf.SetDrySteps(skipSteps); err != nil {
+		return fmt.Errorf("invalid --skip-step: %w", err)
+	}
 
 	// If --timeout flag was explicitly provided, override all step timeouts
 	if cmd.Flags().Changed("timeout") {
 		wf.SetAllStepTimeouts(timeout)
 	}
 
+	// Resolve how completion is verified
+	verifier, err := newVerifier(fileConfig, cfg, workingDir)
+	if err != nil {
+		return err
+	}
+
+	// Guard against iterations that repeat themselves without changing anything
+	guard, err := newProgressGuard(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Flag iterations that cost far more than usual
+	costs, err := newCostMonitor(fileConfig)
+	if err != nil {
+		return err
+	}
+
 	// Determine if we should use TUI mode
 	useTUI := shouldUseTUI()
 
+	// The TUI shows assistant text word by word as it is generated
+	cfg.StreamPartial = useTUI && !cfg.Debug && streamTextEnabled(fileConfig)
+
 	// TUI program (only set if useTUI is true)
 	var tuiProgram *tui.Program
 
+	// Event log backing the TUI timeline scrubber (only set in TUI mode)
+	var eventLog *tui.EventLog
+
 	// Create shared task tracker for persistence across iterations
 	taskTracker := tasks.NewTracker()
 
 	// Stream processor for non-TUI mode (may be nil)
 	var streamProcessor *output.StreamProcessor
 
+	// Resolve how the model's thinking is shown, logged, or discarded
+	thinkingMode, thinkingLog, err := openThinkingLog(fileConfig, workingDir)
+	if err != nil {
+		return err
+	}
+	if thinkingLog != nil {
+		defer func() { _ = thinkingLog.Close() }()
+	}
+
+	// Control sequences in the model's output are neutralised before display
+	sanitizeMode, err := resolveSanitize(fileConfig)
+	if err != nil {
+		return err
+	}
+
+	// Generate a state ID for orbit's internal tracking (separate from Claude session ID)
+	stateID, err := generateSessionID()
+	if err != nil {
+		return fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	// Checkpoints declared by Claude or marked in the TUI are recorded at the
+	// end of the iteration they were declared in
+	checkpoints := &checkpointQueue{}
+
+	// Chat turns in the TUI are paid for from the session's budget
+	chat := &chatSpend{}
+
+	// A panic in the loop writes a diagnostic bundle with the recent output
+	report := newCrashReport(workingDir)
+	report.cfg = cfg
+
 	// Enable streaming output
 	if cfg.Debug {
 		// Debug mode: stream raw JSON (no TUI)
-		exec.SetStreamWriter(os.Stdout)
+		exec.SetStreamWriter(io.MultiWriter(os.Stdout, report.output))
 	} else if useTUI {
 		// TUI mode: create program and bridge
 		session := tui.SessionInfo{
-			SpecFiles:   absFilePaths,
+			SpecFiles:   shownFilePaths,
 			NotesFile:   spec.NotesFile,
 			StateFile:   state.StateDir(workingDir) + "/state.json",
-			ContextFile: strings.Join(contextFiles, ", "),
+			ContextFile: strings.Join(shownCtxFiles, ", "),
+			WorkingDir:  workingDir,
 		}
 		progress := tui.ProgressInfo{
 			Iteration:     1,
@@ -327,11 +652,29 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 			ContextWindow: config.GetContextWindow(cfg.Model),
 			WorkflowName:  wf.Name,
 		}
-		tuiProgram = tui.New(session, progress, cfg.Theme)
+		tuiOpts := []tui.Option{
+			tui.WithChat(newChatFunc(cfg, redactor, absFilePaths, spec.NotesFile, chat)),
+			tui.WithCheckpoint(checkpoints.Add),
+			tui.WithTaskEditing(),
+			tui.WithLayout(tuiLayout),
+		}
+		// The event log backs the timeline scrubber; the TUI works without it
+		if eventLog, err = tui.OpenEventLog(runs.EventLogPath(workingDir, stateID)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: timeline disabled: %v\n", err)
+		} else {
+			tuiOpts = append(tuiOpts, tui.WithEventLog(eventLog))
+		}
+		tuiProgram = tui.New(session, progress, cfg.Theme, tuiOpts...)
+		tuiProgram.Bridge().SetRedactor(redactor)
+		tuiProgram.Bridge().SetThinking(thinkingMode, thinkingLog)
+		tuiProgram.Bridge().SetSanitize(sanitizeMode)
+		tuiProgram.SetOutputObserver(report.output.Record)
+		report.tui = tuiProgram
 		exec.SetStreamWriter(tuiProgram.Bridge())
+		exec.SetStderrWriter(tuiProgram.Bridge().Stderr())
 	} else if cfg.Verbose || cfg.ShowUnhandled || todosOnly {
 		// Minimal/verbose mode: formatted output
-		streamProcessor = output.NewStreamProcessor(os.Stdout)
+		streamProcessor = output.NewStreamProcessor(io.MultiWriter(os.Stdout, report.output))
 		streamProcessor.SetTracker(taskTracker) // Use shared tracker
 		if cfg.ShowUnhandled {
 			streamProcessor.SetShowUnhandled(true)
@@ -339,20 +682,47 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 		if todosOnly {
 			streamProcessor.SetTodosOnly(true)
 		}
+		streamProcessor.SetThinking(thinkingMode, thinkingLog)
+		streamProcessor.SetSanitize(sanitizeMode)
 		exec.SetStreamWriter(streamProcessor)
 	}
+	exec.SetQuarantine(newQuarantine(workingDir, tuiProgram))
+	exec.SetPreflight(preflightSummariser(cfg, redactor), preflightReporter(tuiProgram))
 
-	// Generate a state ID for orbit's internal tracking (separate from Claude session ID)
-	stateID, err := generateSessionID()
-	if err != nil {
-		return fmt.Errorf("failed to generate session ID: %w", err)
-	}
+	// Record what the session runs with, for comparing runs across upgrades
+	env := captureEnvironment(context.Background(), exec, repo, fileConfig)
 
 	// Initialize session state
-	st, err := initState(stateID, workingDir, absFilePaths, spec.NotesFile, contextFiles)
+	st, err := initState(stateID, workingDir, absFilePaths, spec.NotesFile, shownCtxFiles, cfg.DangerouslySkipPermissions, env)
 	if err != nil {
 		return fmt.Errorf("failed to initialize state: %w", err)
 	}
+	report.state = st
+
+	// The agent's task list is mirrored to a GitHub issue after each iteration
+	mirror := newIssueMirror(fileConfig, st, workingDir, absFilePaths)
+	if mirror != nil {
+		exec.AddStreamWriter(mirror)
+	}
+
+	// Shell commands the agent runs are kept for review with orbital audit
+	auditLog, err := audit.Open(runs.AuditLogPath(workingDir, stateID), workingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		exec.AddStreamWriter(auditLog)
+	}
+
+	// Start a persistent run record so this run can be compared later
+	rec := newRunRecord(repo, stateID, absFilePaths, wf.Name, cfg.Model)
+	rec.Unconstrained = cfg.DangerouslySkipPermissions
+	rec.Environment = env
+
+	// Work deferred in TODO(orbital) comments is picked up after each iteration
+	todos := newCodeTodoScanner(fileConfig, repo, workingDir, rec.StartCommit, absFilePaths, spec.NotesFile)
+
+	// Commands such as dependency installs run before the first iteration
+	boot := newBootstrap(fileConfig, cfg)
 
 	// Set up state manager for queue checking after completion
 	sm, err := newStateManagerAdapter(st, sp)
@@ -444,7 +814,7 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 
 	// Print banner for non-TUI mode
 	if formatter != nil {
-		printBanner(formatter, cfg, sp, contextFiles, wf)
+		printBanner(formatter, cfg, sp, shownCtxFiles, autoFiles, wf)
 
 		// Print the command that will be executed
 		if cfg.Verbose {
@@ -457,23 +827,48 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 	// Create context with signal handling for graceful shutdown
 	ctx, cancel := setupSignalHandler()
 	defer cancel()
+	report.cancel = cancel
+	defer report.handlePanic()
+
+	// Without the TUI, single keys show the status, pause, and stop
+	keys := newKeyControls(tuiProgram, time.Now())
+	defer keys.Close()
+	ctx = keys.Attach(ctx)
+
+	deps := workflowLoopDeps{
+		repo:        repo,
+		notifier:    notifier,
+		rec:         rec,
+		verifier:    verifier,
+		guard:       guard,
+		costs:       costs,
+		checkpoints: checkpoints,
+		chat:        chat,
+		todos:       todos,
+		mirror:      mirror,
+		keys:        keys,
+		boot:        boot,
+	}
 
 	// Run the loop (with TUI in background if enabled)
 	var loopState *loop.LoopState
 	if tuiProgram != nil {
-		// Run TUI in a goroutine
+		// Run TUI in a goroutine; if it panics, stop the loop it was showing
 		tuiDone := make(chan error, 1)
 		go func() {
-			tuiDone <- tuiProgram.Run()
+			tuiErr := tuiProgram.Run()
+			var panicErr *tui.PanicError
+			if errors.As(tuiErr, &panicErr) {
+				cancel()
+			}
+			tuiDone <- tuiErr
 		}()
 
-		// Brief delay to allow TUI event loop to start and process WindowSizeMsg.
-		// This ensures the TUI is ready to receive messages before the loop begins.
-		// The delay is minimal (50ms) and occurs only once at startup.
-		time.Sleep(50 * time.Millisecond)
+		// No need to wait for the event loop: messages sent before it starts are
+		// buffered by the Program and delivered in order once it is running.
 
 		// Run the workflow loop (step timeouts are handled by the workflow runner)
-		loopState, err = runWorkflowLoop(ctx, cfg, exec, wf, absFilePaths, spec.NotesFile, sm, st, tuiProgram)
+		loopState, err = runWorkflowLoop(ctx, cfg, exec, wf, absFilePaths, spec.NotesFile, sm, st, tuiProgram, deps)
 
 		// Quit the TUI - use Kill() for immediate exit on interrupt
 		if errors.Is(err, context.Canceled) {
@@ -482,22 +877,64 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 			tuiProgram.Quit()
 		}
 		// Wait for TUI goroutine to finish before cleanup
-		<-tuiDone
+		var panicErr *tui.PanicError
+		if errors.As(<-tuiDone, &panicErr) {
+			report.report(panicErr.Value, panicErr.Stack)
+			err = panicErr
+		}
 		// Clean up the Bridge's message pump goroutine
 		tuiProgram.Close()
+		if closeErr := eventLog.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close event log: %v\n", closeErr)
+		}
 	} else {
 		// Run the workflow loop (step timeouts are handled by the workflow runner)
-		loopState, err = runWorkflowLoop(ctx, cfg, exec, wf, absFilePaths, spec.NotesFile, sm, st, nil)
+		loopState, err = runWorkflowLoop(ctx, cfg, exec, wf, absFilePaths, spec.NotesFile, sm, st, nil, deps)
 	}
 
+	keys.Close()
+
 	// Print summary
 	if loopState != nil {
+		loopState.Redactions = redactor.Summary()
 		summaryFormatter := output.NewFormatter(cfg.Verbose, quiet, os.Stdout)
 		// For non-TUI mode, print task summary if we have tasks
 		if !useTUI && streamProcessor != nil {
 			streamProcessor.PrintTaskSummary()
 		}
-		printSummary(summaryFormatter, loopState, st.SessionID)
+		printSummary(summaryFormatter, loopState, st.SessionID, rec)
+	}
+
+	// Record commands still running when the session stopped
+	if closeErr := auditLog.Close(); closeErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close audit trail: %v\n", closeErr)
+	}
+
+	// Add what the session learned about the project to the shared memory.
+	// The summary is part of the session's cost, so it runs before the
+	// record is written.
+	if memoryEnabled(fileConfig) {
+		memCost, memErr := updateMemory(os.Stdout, cfg, redactor, workingDir, absFilePaths, spec.NotesFile, facts, err)
+		if memErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update memory: %v\n", memErr)
+		}
+		if loopState != nil {
+			loopState.TotalCost += memCost
+		}
+	}
+
+	// Persist the run record (survives state cleanup)
+	finishRunRecord(repo, rec, loopState, err)
+	if saveErr := rec.Save(workingDir); saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save run record: %v\n", saveErr)
+	}
+	recordStats(fileConfig, rec, cfg.MaxBudget)
+
+	// Turn a run that ran out of iterations or budget into a next run
+	if followUpEnabled(fileConfig) {
+		if _, fuErr := writeFollowUp(os.Stdout, workingDir, absFilePaths[0], spec.NotesFile, loopState, rec.DiffStat, err, time.Now()); fuErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write follow-up spec: %v\n", fuErr)
+		}
 	}
 
 	// Handle state cleanup or preservation
@@ -512,6 +949,8 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 			os.Exit(2)
 		case errors.Is(err, context.DeadlineExceeded):
 			os.Exit(3)
+		case errors.Is(err, loop.ErrNoProgress):
+			os.Exit(5)
 		case errors.Is(err, context.Canceled):
 			// Summary already printed above with resume instructions
 			os.Exit(130)
@@ -521,7 +960,7 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 	}
 
 	// On successful completion, clean up state
-	if err := cleanupState(st); err != nil {
+	if err := cleanupState(repo, st, cfg.ArchiveKeep); err != nil {
 		// Log but don't fail - the work is done
 		fmt.Fprintf(os.Stderr, "Warning: failed to cleanup state: %v\n", err)
 	}
@@ -529,28 +968,31 @@ func runOrbit(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func printBanner(formatter *output.Formatter, cfg *config.Config, sp *spec.Spec, ctxFiles []string, wf *workflow.Workflow) {
+func printBanner(formatter *output.Formatter, cfg *config.Config, sp *spec.Spec, ctxFiles, autoFiles []string, wf *workflow.Workflow) {
 	bannerCfg := output.BannerConfig{
-		SpecFile:      sp.FilePaths[0],
-		ContextFiles:  ctxFiles,
-		WorkflowName:  wf.Name,
-		WorkflowSteps: len(wf.Steps),
-		HasGates:      wf.HasGates(),
-		Model:         cfg.Model,
-		CheckerModel:  cfg.CheckerModel,
-		MaxIterations: cfg.MaxIterations,
-		Budget:        cfg.MaxBudget,
-		Timeout:       cfg.IterationTimeout,
-		WorkingDir:    cfg.WorkingDir,
-		NotesFile:     spec.NotesFile,
-		SessionID:     cfg.SessionID,
-		DryRun:        cfg.DryRun,
-		Debug:         cfg.Debug,
+		SpecFile:         sp.FilePaths[0],
+		ContextFiles:     ctxFiles,
+		AutoContextFiles: autoFiles,
+		WorkflowName:     wf.Name,
+		WorkflowSteps:    len(wf.Steps),
+		HasGates:         wf.HasGates(),
+		Model:            cfg.Model,
+		CheckerModel:     cfg.CheckerModel,
+		MaxIterations:    cfg.MaxIterations,
+		Budget:           cfg.MaxBudget,
+		Timeout:          cfg.IterationTimeout,
+		WorkingDir:       cfg.WorkingDir,
+		NotesFile:        spec.NotesFile,
+		SessionID:        cfg.SessionID,
+		DryRun:           cfg.DryRun,
+		Debug:            cfg.Debug,
 	}
 	formatter.PrintRichBanner(bannerCfg)
 }
 
-func printSummary(formatter *output.Formatter, loopState *loop.LoopState, sessionID string) {
+// printSummary prints the summary of a finished loop. A run that did not
+// complete is diagnosed from rec, which may be nil.
+func printSummary(formatter *output.Formatter, loopState *loop.LoopState, sessionID string, rec *runs.Record) {
 	summary := output.LoopSummary{
 		Iterations:  loopState.Iteration,
 		TotalCost:   loopState.TotalCost,
@@ -561,6 +1003,18 @@ func printSummary(formatter *output.Formatter, loopState *loop.LoopState, sessio
 		Completed:   loopState.Completed,
 		Error:       loopState.Error,
 		SessionID:   sessionID,
+
+		IterationModels: loopState.IterationModels,
+		Escalations:     loopState.Escalations,
+		Redactions:      loopState.Redactions,
+		CPUTime:         loopState.CPUTime,
+		PeakRSS:         loopState.PeakRSS,
+
+		CacheReadTokens:     loopState.TotalCacheReadTokens,
+		CacheCreationTokens: loopState.TotalCacheCreationTokens,
+	}
+	if !loopState.Completed {
+		summary.Diagnosis = runs.Diagnose(rec)
 	}
 	formatter.PrintLoopSummary(summary)
 }
@@ -576,8 +1030,10 @@ func generateSessionID() (string, error) {
 }
 
 // initState creates and saves a new session state.
-func initState(sessionID, workingDir string, files []string, notesFile string, contextFiles []string) (*state.State, error) {
+func initState(sessionID, workingDir string, files []string, notesFile string, contextFiles []string, unconstrained bool, env *environment.Snapshot) (*state.State, error) {
 	st := state.NewState(sessionID, workingDir, files, notesFile, contextFiles)
+	st.Unconstrained = unconstrained
+	st.Environment = env
 	if err := st.Save(); err != nil {
 		return nil, fmt.Errorf("failed to save state: %w", err)
 	}
@@ -590,11 +1046,154 @@ func updateState(st *state.State, iteration int, totalCost float64) error {
 	return st.Save()
 }
 
-// cleanupState removes the state directory.
-func cleanupState(st *state.State) error {
+// cleanupState removes the session's snapshots and archives the state
+// directory, with the session's event log, so the finished session can still
+// be looked into. With keep 0, or if archiving fails, the state is deleted.
+func cleanupState(repo vcs.Backend, st *state.State, keep int) error {
+	if len(st.Snapshots) > 0 {
+		// Best effort: stale snapshots only cost disk space
+		_ = repo.DeleteSnapshots(context.Background(), st.SessionID, -1)
+	}
+	if keep > 0 {
+		if _, err := st.Archive(keep, runs.EventLogPath(st.WorkingDir, st.SessionID)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to archive state: %v\n", err)
+		}
+	}
 	return st.Cleanup()
 }
 
+// snapshotIteration records a snapshot of the working tree after the given
+// iteration so the session can be rolled back to it. Iteration 0 is the tree
+// before the first iteration. Does nothing without version control.
+func snapshotIteration(repo vcs.Backend, st *state.State, iteration int) error {
+	// Not tied to the run context: the snapshot after an interrupted
+	// iteration is the one most worth having.
+	commit, err := repo.Snapshot(context.Background(), st.SessionID, iteration)
+	if errors.Is(err, vcs.ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to snapshot iteration %d: %w", iteration, err)
+	}
+
+	st.RecordSnapshot(iteration, commit)
+	return st.Save()
+}
+
+// reportSnapshotError surfaces a snapshot failure without interrupting the loop.
+func reportSnapshotError(err error, tuiProgram *tui.Program) {
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("⚠ " + err.Error())
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: "+err.Error())
+	}
+}
+
+// prepareNotes opens the notes section for a step and hands the recent
+// sections to the runner. Failures are reported but never stop the run.
+func prepareNotes(notes *spec.Notes, runner *workflow.Runner, iteration int, step string, tuiProgram *tui.Program) {
+	if err := notes.BeginSection(iteration, step, time.Now()); err != nil {
+		reportNotesError(err, tuiProgram)
+	}
+	recent, err := notes.Recent()
+	if err != nil {
+		reportNotesError(err, tuiProgram)
+	}
+	runner.SetRecentNotes(recent)
+}
+
+// takeInjection claims pending operator instructions and returns the text to
+// append to this iteration's prompts, or "" if there are none.
+func takeInjection(injector *loop.Injector, tuiProgram *tui.Program) string {
+	instructions, err := injector.Take()
+	if err != nil {
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("⚠ " + err.Error())
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: "+err.Error())
+		}
+		return ""
+	}
+	if instructions == "" {
+		return ""
+	}
+	msg := "Injecting operator instructions from " + injector.Path
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("📥 " + msg)
+	} else {
+		fmt.Printf("\n%s\n", msg)
+	}
+	return loop.FormatInjection(instructions)
+}
+
+// leanPrompt returns the lean context after a failed check: the unchecked
+// items, read from the spec files when the check did not report them, and
+// the check's feedback.
+func leanPrompt(specFiles, items []string, feedback string) string {
+	if items == nil {
+		items, _ = spec.UncheckedItems(specFiles)
+	}
+	return spec.BuildLeanPrompt(items, feedback)
+}
+
+// noteSpecChange flags spec files edited since the last iteration in the
+// output and the notes file, and returns the text to append to this
+// iteration's prompts.
+func noteSpecChange(changed []string, notes *spec.Notes, iteration int, tuiProgram *tui.Program) string {
+	msg := "Spec changed: " + strings.Join(changed, ", ")
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("📝 " + msg)
+	} else {
+		fmt.Printf("\n%s\n", msg)
+	}
+	if notes != nil {
+		var body strings.Builder
+		body.WriteString("Edited while the loop was running; re-read before continuing:\n")
+		for _, path := range changed {
+			body.WriteString("- " + path + "\n")
+		}
+		if err := notes.AddSection(iteration, "spec changed", body.String(), time.Now()); err != nil {
+			reportNotesError(err, tuiProgram)
+		}
+	}
+	return loop.FormatSpecChange(changed)
+}
+
+// reportNotesError surfaces a notes file failure without interrupting the loop.
+func reportNotesError(err error, tuiProgram *tui.Program) {
+	msg := fmt.Sprintf("Notes update failed: %v", err)
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("⚠ " + msg)
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: "+msg)
+	}
+}
+
+// discoverContextFiles returns existing files referenced by the spec that are
+// not already context files. Discovery is best effort: an unreadable spec is
+// reported later by spec validation.
+func discoverContextFiles(specPath string, existing []string) []string {
+	refs, err := spec.DiscoverReferences(specPath, workingDir)
+	if err != nil {
+		return nil
+	}
+
+	known := make(map[string]bool, len(existing))
+	for _, path := range existing {
+		if abs, err := filepath.Abs(path); err == nil {
+			known[abs] = true
+		}
+	}
+
+	var found []string
+	for _, ref := range refs {
+		if abs, err := filepath.Abs(ref); err == nil && !known[abs] {
+			found = append(found, ref)
+		}
+	}
+	return found
+}
+
 // getAbsolutePaths converts relative paths to absolute paths.
 func getAbsolutePaths(paths []string) ([]string, error) {
 	result := make([]string, len(paths))
@@ -767,6 +1366,11 @@ func shouldUseTUI() bool {
 	return true
 }
 
+// stdinInteractive reports whether the user can answer prompts on stdin.
+func stdinInteractive() bool {
+	return !nonInteractive && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
 // resolveWorkflow determines the workflow to use based on CLI flag and config file.
 // CLI flag takes precedence over config file. If neither specified, uses spec-driven default.
 func resolveWorkflow(flagValue string, fileConfig *config.FileConfig) (*workflow.Workflow, error) {
@@ -780,7 +1384,22 @@ func resolveWorkflow(flagValue string, fileConfig *config.FileConfig) (*workflow
 			}
 			return nil, fmt.Errorf("invalid workflow preset %q, valid options: %s", flagValue, strings.Join(names, ", "))
 		}
-		return workflow.GetPreset(workflow.PresetName(flagValue))
+		wf, err := workflow.GetPreset(workflow.PresetName(flagValue))
+		if err != nil {
+			return nil, err
+		}
+		if fileConfig != nil && fileConfig.Workflow != nil {
+			wf.ApplyPanel(fileConfig.Workflow.Panel)
+			wf.EscalateModel = fileConfig.Workflow.EscalateModel
+			wf.EscalateAfter = fileConfig.Workflow.EscalateAfter
+			if err := wf.ApplyStepContext(fileConfig.Workflow.Context); err != nil {
+				return nil, err
+			}
+			if err := wf.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return wf, nil
 	}
 
 	// Check config file
@@ -792,27 +1411,175 @@ func resolveWorkflow(flagValue string, fileConfig *config.FileConfig) (*workflow
 	return workflow.GetPreset(workflow.PresetSpecDriven)
 }
 
+// panelVerdicts returns the verdicts reported by a review panel, or nil when
+// the step had no panel.
+func panelVerdicts(p *workflow.PanelResult) map[string]bool {
+	if p == nil || len(p.Verdicts) == 0 {
+		return nil
+	}
+	return p.Verdicts
+}
+
+// maxGateFeedback is the most of a failed gate's reply kept in the run
+// record, in runes.
+const maxGateFeedback = 200
+
+// gateExhausted returns how many times a gate step has failed in the
+// iteration when this failure uses up its retries, and 0 otherwise.
+func gateExhausted(info workflow.StepInfo, gateResult workflow.GateResult) int {
+	if !info.IsGate || gateResult == workflow.GatePassed || info.GateRetries+1 < info.MaxRetries {
+		return 0
+	}
+	return info.GateRetries + 1
+}
+
+// gateFeedback returns the end of a failed gate judge's reply, where it
+// sums up what is wrong, on one line and without the gate and scores tags.
+// Returns "" unless the gate failed.
+func gateFeedback(streamOutput string, gateResult workflow.GateResult) string {
+	if gateResult != workflow.GateFailed {
+		return ""
+	}
+	text := output.ExtractResult(streamOutput)
+	if start := strings.Index(text, workflow.ScoresOpenTag); start >= 0 {
+		if end := strings.Index(text[start:], workflow.ScoresCloseTag); end >= 0 {
+			text = text[:start] + text[start+end+len(workflow.ScoresCloseTag):]
+		}
+	}
+	text = strings.NewReplacer(workflow.GateFailTag, " ", workflow.GatePassTag, " ").Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxGateFeedback {
+		text = "…" + string(runes[len(runes)-maxGateFeedback:])
+	}
+	return text
+}
+
 // claudeStepExecutor adapts the executor.Executor to the workflow.StepExecutor interface.
 type claudeStepExecutor struct {
 	exec *executor.Executor
+
+	// chain is the model fallback chain (nil when no fallbacks are configured).
+	chain *executor.ModelChain
+
+	// onFallback is called when the chain switches to the next model.
+	onFallback func(from, to string, rateLimited bool)
+
+	// model, when set, runs every step on this model without consulting the
+	// fallback chain (burst iterations).
+	model string
 }
 
 // ExecuteStep executes a single workflow step by invoking Claude with the step prompt.
+// When a fallback chain is configured and the current model fails repeatedly or
+// is rate limited, the step is retried on the next model in the chain.
 func (e *claudeStepExecutor) ExecuteStep(ctx context.Context, stepName string, prompt string) (*workflow.ExecutionResult, error) {
+	if e.model != "" {
+		return e.ExecuteStepOnModel(ctx, stepName, prompt, e.model)
+	}
+	stepResult := &workflow.ExecutionResult{StepName: stepName}
+
+	for {
+		if e.chain != nil {
+			e.exec.SetModel(e.chain.Current())
+		}
+
+		result, err := e.exec.Execute(ctx, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("step %q execution failed: %w", stepName, err)
+		}
+
+		// Failed attempts still cost money, so accumulate across retries
+		stepResult.Output = result.Output
+		stepResult.CostUSD += result.CostUSD
+		stepResult.TokensIn += result.TokensIn
+		stepResult.TokensOut += result.TokensOut
+		stepResult.CacheReadTokens += result.CacheReadTokens
+		stepResult.CacheCreationTokens += result.CacheCreationTokens
+		stepResult.CPUTime += result.CPUTime
+		stepResult.PeakRSS = max(stepResult.PeakRSS, result.PeakRSS)
+
+		// An unreachable API is not the model's fault, so it does not count
+		// towards a fallback; the runner waits for the network instead
+		stepResult.Offline = executor.IsNetworkFailure(result)
+		if e.chain == nil || stepResult.Offline {
+			return stepResult, nil
+		}
+
+		failed, rateLimited := executor.IsFailure(result)
+		if !failed {
+			e.chain.RecordSuccess()
+			return stepResult, nil
+		}
+
+		from := e.chain.Current()
+		if !e.chain.RecordFailure(rateLimited) {
+			return stepResult, nil
+		}
+		if e.onFallback != nil {
+			e.onFallback(from, e.chain.Current(), rateLimited)
+		}
+	}
+}
+
+// stepModel returns the model a step runs on: model when it is escalated,
+// otherwise the burst model or the executor's current model.
+func (e *claudeStepExecutor) stepModel(model string) string {
+	if model != "" {
+		return model
+	}
+	if e.model != "" {
+		return e.model
+	}
+	if e.chain != nil {
+		return e.chain.Current()
+	}
+	return e.exec.Model()
+}
+
+// ExecuteStepOnModel executes a workflow step on model, for gate escalation.
+// The fallback chain is not consulted and the executor's model is restored
+// afterwards.
+func (e *claudeStepExecutor) ExecuteStepOnModel(ctx context.Context, stepName, prompt, model string) (*workflow.ExecutionResult, error) {
+	previous := e.exec.Model()
+	e.exec.SetModel(model)
+	defer e.exec.SetModel(previous)
+
 	result, err := e.exec.Execute(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("step %q execution failed: %w", stepName, err)
 	}
-
 	return &workflow.ExecutionResult{
-		StepName:  stepName,
-		Output:    result.Output,
-		CostUSD:   result.CostUSD,
-		TokensIn:  result.TokensIn,
-		TokensOut: result.TokensOut,
+		StepName:            stepName,
+		Output:              result.Output,
+		CostUSD:             result.CostUSD,
+		TokensIn:            result.TokensIn,
+		TokensOut:           result.TokensOut,
+		CacheReadTokens:     result.CacheReadTokens,
+		CacheCreationTokens: result.CacheCreationTokens,
+		CPUTime:             result.CPUTime,
+		PeakRSS:             result.PeakRSS,
+		Offline:             executor.IsNetworkFailure(result),
 	}, nil
 }
 
+// workflowLoopDeps are the collaborators runWorkflowLoop consults and
+// reports to over a session. runOrbit builds them once; add new ones here
+// rather than as parameters.
+type workflowLoopDeps struct {
+	repo        vcs.Backend
+	notifier    *notify.Notifier // nil when notifications are off
+	rec         *runs.Record
+	verifier    loop.Verifier
+	guard       *loop.ProgressGuard
+	costs       *loop.CostMonitor
+	checkpoints *checkpointQueue
+	chat        *chatSpend
+	todos       *codeTodoScanner // nil unless TODO scanning is on
+	mirror      *ghissue.Mirror  // nil unless tasks are mirrored
+	keys        *keyControls
+	boot        *bootstrap // nil without bootstrap commands
+}
+
 // runWorkflowLoop executes a multi-step workflow with gates.
 // It runs the workflow steps in sequence, handling gate pass/fail logic,
 // and iterates until verification passes or limits are reached.
@@ -826,13 +1593,54 @@ func runWorkflowLoop(
 	sm *stateManagerAdapter,
 	st *state.State,
 	tuiProgram *tui.Program,
+	deps workflowLoopDeps,
 ) (*loop.LoopState, error) {
 	loopState := &loop.LoopState{
 		StartTime: time.Now(),
 	}
+	// Chat turns since the last budget check still count towards the totals
+	defer func() { loopState.TotalCost += deps.chat.Take() }()
+	exec.SetStallCallback(stallReporter(tuiProgram))
+
+	// Quiet and CI runs still show where the session is
+	progress := newProgressLine(progressMode, tuiProgram, loopState.StartTime)
+	progress.Start()
+	defer progress.Stop()
+	reportProgress := func(step string) {
+		status := output.ProgressStatus{
+			Iteration:     loopState.Iteration,
+			MaxIterations: cfg.MaxIterations,
+			Step:          step,
+			Cost:          loopState.TotalCost,
+			Budget:        cfg.MaxBudget,
+		}
+		progress.Update(status)
+		deps.keys.Update(status)
+	}
+
+	// The turn limit is tuned on the most turns any step of an iteration
+	// took and whether one ran out of them
+	turnTuner := newTurnTuner(cfg)
+	var iterationTurns int
+	var iterationOutOfTurns bool
 
 	// Create step executor adapter
 	stepExec := &claudeStepExecutor{exec: exec}
+	if len(cfg.FallbackModels) > 0 {
+		stepExec.chain = executor.NewModelChain(cfg.Model, cfg.FallbackModels)
+		stepExec.onFallback = func(from, to string, rateLimited bool) {
+			reason := "repeated failures"
+			if rateLimited {
+				reason = "rate limit"
+			}
+			msg := fmt.Sprintf("Model %s unavailable (%s), falling back to %s", from, reason, to)
+			if tuiProgram != nil {
+				tuiProgram.SendOutput("⚠ " + msg)
+			} else {
+				fmt.Printf("\n%s\n", msg)
+			}
+		}
+	}
 
 	// Create workflow runner
 	runner := workflow.NewRunner(wf, stepExec)
@@ -849,6 +1657,36 @@ func runWorkflowLoop(
 		}
 	}
 	runner.SetNotesFile(notesFile)
+	runner.SetWorkingDir(cfg.WorkingDir)
+	if wf.HasCacheableSteps() {
+		runner.SetCache(newStepCache(cfg, stepExec, tuiProgram))
+	}
+	if budget := newContextBudget(cfg); budget != nil {
+		runner.SetContextBudget(budget, contextBudgetReporter(tuiProgram))
+	}
+	runner.SetOfflineWait(newOfflineWaiter(cfg, st, tuiProgram).Wait)
+	runner.SetApprover(newApprover(cfg.WorkingDir, st.SessionID, deps.notifier, tuiProgram))
+	runner.SetTreeState(workflow.TreeStateFunc(treeStateFunc(deps.repo)))
+
+	// Switch a repeatedly failing gate's retry to the escalation model
+	runner.SetEscalationCallback(func(e workflow.Escalation) {
+		loopState.Escalations = append(loopState.Escalations, fmt.Sprintf("iteration %d: %s", loopState.Iteration, e))
+		if tuiProgram != nil {
+			tuiProgram.SendEscalation(e.Gate, e.Failures, e.Model)
+		} else {
+			fmt.Printf("\nGate %s failed %d times, escalating to %s\n", e.Gate, e.Failures, e.Model)
+		}
+	})
+
+	// Run a gate that outlasted its [workflow.gate] timeout again after a
+	// backoff
+	runner.SetGateTimeoutCallback(func(t workflow.GateTimeout) {
+		if tuiProgram != nil {
+			tuiProgram.SendGateTimeout(t.Gate, t.Timeout, t.String())
+		} else {
+			fmt.Printf("\n⏱ %s\n", t)
+		}
+	})
 
 	// Create formatter for non-TUI output
 	formatter := output.NewFormatter(cfg.Verbose, false, os.Stdout)
@@ -859,9 +1697,36 @@ func runWorkflowLoop(
 	// Track step summaries for final summary
 	var stepSummaries []output.StepSummary
 
+	// Orbital writes the notes scaffolding itself and passes recent sections
+	// into each prompt
+	var notes *spec.Notes
+	if notesFile != "" {
+		notes = spec.NewNotes(notesFile)
+	}
+
+	// checkBudget warns as spend passes each --budget-warn threshold, or
+	// without thresholds notifies once at the notification threshold
+	budgetWarner := loop.NewBudgetWarner(cfg.BudgetWarnings)
+	checkBudget := func(iteration int) {
+		loopState.TotalCost += deps.chat.Take()
+		if len(cfg.BudgetWarnings) == 0 {
+			if err := deps.notifier.CheckBudget(loopState.TotalCost, cfg.MaxBudget); err != nil {
+				reportNotifyError(err, tuiProgram)
+			}
+			return
+		}
+		if w := budgetWarner.Observe(loopState.TotalCost, cfg.MaxBudget); w != nil {
+			reportBudgetWarning(*w, iteration, notes, deps.notifier, tuiProgram)
+		}
+	}
+
 	// Set start callback to print step start
 	runner.SetStartCallback(func(info workflow.StepInfo) {
 		stepStartTime = time.Now()
+		reportProgress(info.Name)
+		if notes != nil {
+			prepareNotes(notes, runner, loopState.Iteration, info.Name, tuiProgram)
+		}
 		if tuiProgram == nil {
 			// Non-TUI mode: print to formatter
 			formatter.PrintStepStart(info.Name, info.Position, info.Total)
@@ -873,24 +1738,31 @@ func runWorkflowLoop(
 			// TUI mode: send progress update immediately when step starts
 			// Include accumulated cost/tokens to prevent display reset
 			tuiProgram.SendProgress(tui.ProgressInfo{
-				Iteration:        loopState.Iteration,
-				MaxIteration:     cfg.MaxIterations,
-				StepName:         info.Name,
-				StepPosition:     info.Position,
-				StepTotal:        info.Total,
-				TokensIn:         loopState.TotalTokensIn,
-				TokensOut:        loopState.TotalTokensOut,
-				Cost:             loopState.TotalCost,
-				Budget:           cfg.MaxBudget,
-				ContextWindow:    config.GetContextWindow(cfg.Model),
-				IterationTimeout: info.Timeout,
-				IterationStart:   stepStartTime,
-				IsGateStep:       info.IsGate,
-				WorkflowName:     wf.Name,
+				Iteration:          loopState.Iteration,
+				MaxIteration:       cfg.MaxIterations,
+				StepName:           info.Name,
+				StepPosition:       info.Position,
+				StepTotal:          info.Total,
+				TokensIn:           loopState.TotalTokensIn,
+				TokensOut:          loopState.TotalTokensOut,
+				CacheReadTokens:    loopState.TotalCacheReadTokens,
+				Cost:               loopState.TotalCost,
+				Budget:             cfg.MaxBudget,
+				ContextWindow:      config.GetContextWindow(cfg.Model),
+				IterationTimeout:   info.Timeout,
+				IterationStart:     stepStartTime,
+				IsGateStep:         info.IsGate,
+				WorkflowName:       wf.Name,
+				IterationBegan:     loopState.IterationStarted,
+				IterationDurations: loopState.IterationDurations,
 			})
 		}
 	})
 
+	// gateLean is the lean context from the last failed gate, carried into
+	// the next iteration when the gate fails too often
+	var gateLean string
+
 	// Set callback to track step execution
 	runner.SetCallback(func(info workflow.StepInfo, result *workflow.ExecutionResult, gateResult workflow.GateResult) error {
 		stepDuration := time.Since(stepStartTime)
@@ -900,13 +1772,35 @@ func runWorkflowLoop(
 		loopState.TotalTokensIn += result.TokensIn
 		loopState.TotalTokensOut += result.TokensOut
 		loopState.TotalTokens = loopState.TotalTokensIn + loopState.TotalTokensOut
+		loopState.AddCacheTokens(result.CacheReadTokens, result.CacheCreationTokens)
 		loopState.LastOutput = result.Output
+		loopState.AddResources(result.CPUTime, result.PeakRSS)
+		reportProgress(info.Name)
+		for _, name := range workflow.ParseCheckpoints(result.Output) {
+			deps.checkpoints.Add(name)
+		}
+		turns, limitReached := executor.ResultTurns(result.Output)
+		iterationTurns = max(iterationTurns, turns)
+		iterationOutOfTurns = iterationOutOfTurns || limitReached
+
+		// A failed gate's review is the feedback for the steps that follow
+		if cfg.LeanPrompts && info.IsGate {
+			switch gateResult {
+			case workflow.GateFailed:
+				gateLean = leanPrompt(specFiles, nil, result.Output)
+				runner.SetLeanContext(gateLean)
+			case workflow.GatePassed:
+				gateLean = ""
+				runner.SetLeanContext("")
+			}
+		}
 
 		// Track step summary
 		summary := output.StepSummary{
 			Name:   info.Name,
 			Cost:   result.CostUSD,
 			Tokens: result.TokensIn + result.TokensOut,
+			Scores: info.Scores,
 		}
 		switch gateResult {
 		case workflow.GatePassed:
@@ -918,47 +1812,231 @@ func runWorkflowLoop(
 		default:
 			summary.Status = "completed"
 		}
+		if info.Dry {
+			summary.Status = "dry"
+		}
+		if info.Model != "" {
+			summary.Model = info.Model
+			summary.Escalated = true
+		} else if served := iterationModel(exec, stepExec); served != cfg.Model {
+			summary.Model = served
+		}
 		stepSummaries = append(stepSummaries, summary)
+		deps.rec.AddStep(runs.StepRecord{
+			Iteration:  loopState.Iteration,
+			Name:       info.Name,
+			Status:     summary.Status,
+			GateResult: summary.GateResult,
+			Model:      summary.Model,
+			Escalated:  summary.Escalated,
+			Cost:       result.CostUSD,
+			Tokens:     result.TokensIn + result.TokensOut,
+			Duration:   stepDuration,
+			Scores:     info.Scores,
+			Artifacts:  info.Artifacts,
+			Verdicts:   panelVerdicts(info.Panel),
+			CPUTime:    result.CPUTime,
+			PeakRSS:    result.PeakRSS,
+			Dry:        info.Dry,
+			Exhausted:  gateExhausted(info, gateResult),
+			Feedback:   gateFeedback(result.Output, gateResult),
+		})
+
+		checkBudget(loopState.Iteration)
 
 		// Send progress update to TUI if active
 		if tuiProgram != nil {
+			if info.Dry {
+				tuiProgram.SendDryStep(info.Name, info.Prompt)
+			}
+			if len(info.Scores) > 0 {
+				tuiProgram.SendGateScores(info.Name, info.Scores)
+			}
+			if summary.GateResult != "" && !info.Dry {
+				tuiProgram.SendGateResult(info.Name, summary.GateResult, stepDuration)
+			}
+			if len(info.Artifacts) > 0 {
+				tuiProgram.SendArtifacts(info.Artifacts)
+			}
+			if info.Panel != nil {
+				tuiProgram.SendOutput("🗳 Panel: " + info.Panel.String())
+			}
 			tuiProgram.SendProgress(tui.ProgressInfo{
-				Iteration:        loopState.Iteration,
-				MaxIteration:     cfg.MaxIterations,
-				StepName:         info.Name,
-				StepPosition:     info.Position,
-				StepTotal:        info.Total,
-				GateRetries:      info.GateRetries,
-				MaxRetries:       info.MaxRetries,
-				TokensIn:         loopState.TotalTokensIn,
-				TokensOut:        loopState.TotalTokensOut,
-				Cost:             loopState.TotalCost,
-				Budget:           cfg.MaxBudget,
-				ContextWindow:    config.GetContextWindow(cfg.Model),
-				IterationTimeout: info.Timeout,
-				IterationStart:   stepStartTime,
-				IsGateStep:       info.IsGate,
-				WorkflowName:     wf.Name,
+				Iteration:          loopState.Iteration,
+				MaxIteration:       cfg.MaxIterations,
+				StepName:           info.Name,
+				StepPosition:       info.Position,
+				StepTotal:          info.Total,
+				GateRetries:        info.GateRetries,
+				MaxRetries:         info.MaxRetries,
+				TokensIn:           loopState.TotalTokensIn,
+				TokensOut:          loopState.TotalTokensOut,
+				CacheReadTokens:    loopState.TotalCacheReadTokens,
+				Cost:               loopState.TotalCost,
+				Budget:             cfg.MaxBudget,
+				ContextWindow:      config.GetContextWindow(cfg.Model),
+				IterationTimeout:   info.Timeout,
+				IterationStart:     stepStartTime,
+				IsGateStep:         info.IsGate,
+				WorkflowName:       wf.Name,
+				IterationBegan:     loopState.IterationStarted,
+				IterationDurations: loopState.IterationDurations,
 			})
 		}
 
 		// Log step completion (non-TUI mode)
 		if tuiProgram == nil {
-			formatter.PrintStepComplete(info.Name, stepDuration, result.CostUSD, result.TokensIn+result.TokensOut)
+			if info.Dry {
+				formatter.PrintDryStep(info.Name, info.Prompt)
+			} else {
+				formatter.PrintStepComplete(info.Name, stepDuration, result.CostUSD, result.TokensIn+result.TokensOut)
+			}
 			switch gateResult {
 			case workflow.GatePassed:
 				formatter.PrintGateResult(true, info.GateRetries, info.MaxRetries)
 			case workflow.GateFailed:
 				formatter.PrintGateResult(false, info.GateRetries, info.MaxRetries)
 			}
+			formatter.PrintGateScores(info.Scores)
+			for _, path := range info.Artifacts {
+				fmt.Printf("  Artifact: %s\n", path)
+			}
+			if info.Panel != nil {
+				fmt.Printf("  Panel: %s\n", info.Panel.String())
+			}
 		}
 
 		return nil
 	})
 
+	// Install dependencies before any budget is spent
+	if err := deps.boot.Run(ctx, tuiProgram); err != nil {
+		loopState.Error = err
+		return loopState, err
+	}
+
+	// Snapshot the starting tree so the whole run can be rolled back
+	if err := snapshotIteration(deps.repo, st, 0); err != nil {
+		reportSnapshotError(err, tuiProgram)
+	}
+
+	// Operator instructions are delivered once, to the next iteration
+	var injector, interjector *loop.Injector
+	if cfg.InjectFile != "" {
+		injector = loop.NewInjector(cfg.InjectFile)
+	}
+	if cfg.InterjectFile != "" {
+		interjector = loop.NewInjector(cfg.InterjectFile)
+	}
+
+	// Edits to the spec files mid-session are flagged to the next iteration
+	specWatcher := loop.NewSpecWatcher(specFiles)
+
+	// With --lean-prompts, lean narrows the next iteration's prompts to the
+	// work left after a failed check
+	lean := ""
+
+	// countVerification adds a verification's cost to the totals and records it
+	countVerification := func(iteration int, verifyResult *loop.VerificationResult) {
+		if verifyResult == nil {
+			return
+		}
+		loopState.TotalCost += verifyResult.Cost
+		loopState.TotalTokens += verifyResult.Tokens
+		loopState.FileVerifications = verifyResult.Files
+		if msg := loop.DescribeTicked(verifyResult.Ticked); msg != "" {
+			if tuiProgram != nil {
+				tuiProgram.SendOutput("☑ " + msg)
+			} else {
+				fmt.Println(msg)
+			}
+		}
+		deps.rec.AddVerification(runs.VerificationRecord{
+			Iteration: iteration,
+			Verified:  verifyResult.Verified,
+			Unchecked: verifyResult.Unchecked,
+			Checked:   verifyResult.Checked,
+			Confirmed: verifyResult.Confirmed,
+		})
+		checkBudget(iteration)
+	}
+
+	// completeRun finishes after verification passed. Queued files are added
+	// to the run, returning false; otherwise the session is complete.
+	completeRun := func() (bool, error) {
+		// Check queue for new files
+		if sm != nil {
+			queuedFiles, err := sm.PopQueue()
+			if err != nil {
+				loopState.Error = err
+				return true, err
+			}
+
+			if len(queuedFiles) > 0 {
+				if tuiProgram == nil {
+					fmt.Printf("Found %d queued file(s), continuing...\n", len(queuedFiles))
+					for _, f := range queuedFiles {
+						fmt.Printf("  + %s\n", f)
+					}
+				}
+
+				if err := sm.MergeFiles(queuedFiles); err != nil {
+					loopState.Error = err
+					return true, err
+				}
+
+				// Update runner's file paths
+				runner.SetFilePaths(append(specFiles, queuedFiles...))
+				return false, nil
+			}
+		}
+
+		// Done - print workflow summary
+		if tuiProgram == nil {
+			fmt.Println("No queued files. Work complete.")
+			formatter.PrintWorkflowSummary(stepSummaries, loopState.TotalCost, loopState.TotalTokens)
+		}
+		loopState.Completed = true
+		notifyCompleted(deps.notifier, loopState, tuiProgram)
+		return true, nil
+	}
+
+	// With --optimistic-verify, pending is the verification running
+	// alongside the current iteration. A return while it runs still waits
+	// for it, so its cost is counted.
+	var pending *loop.PendingVerification
+	defer func() {
+		if pending != nil {
+			verifyResult, _ := pending.Wait()
+			countVerification(pending.Iteration, verifyResult)
+		}
+	}()
+
 	// Outer loop: iterate until verification passes or limits reached
+	stagnation := loop.ProgressOK
+	treeState := treeStateFunc(deps.repo)
+	lastTree := treeState(ctx)
 	for iteration := 1; iteration <= cfg.MaxIterations; iteration++ {
+		// Stop starting iterations once only the verification reserve is left
+		loopState.TotalCost += deps.chat.Take()
+		if loopState.TotalCost >= cfg.LoopBudget() {
+			if loopState.TotalCost < cfg.MaxBudget {
+				msg := loop.ReserveMessage(loopState.TotalCost, cfg)
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("⚠ " + msg)
+				} else {
+					fmt.Printf("\n%s\n", msg)
+				}
+			}
+			loopState.Error = loop.ErrBudgetExceeded
+			return loopState, loop.ErrBudgetExceeded
+		}
+
 		loopState.Iteration = iteration
+		iterationTurns, iterationOutOfTurns = 0, false
+
+		// Hold while the operator has paused the session from the keyboard
+		deps.keys.Wait()
 
 		// Check context cancellation
 		if ctx.Err() != nil {
@@ -966,20 +2044,167 @@ func runWorkflowLoop(
 			return loopState, ctx.Err()
 		}
 
+		// The first --burst iterations run on the cheap model with few turns
+		applyBurst(iteration, cfg, exec, stepExec, st, tuiProgram)
+
+		// Act on the previous iteration's progress
+		suffix := ""
+		switch stagnation {
+		case loop.ProgressAbort:
+			err := fmt.Errorf("%w: %d consecutive iterations without changes", loop.ErrNoProgress, deps.guard.Stagnant())
+			loopState.Error = err
+			return loopState, err
+		case loop.ProgressEscalate:
+			msg := fmt.Sprintf("No progress in %d iterations. Asking for a change of approach...", deps.guard.Stagnant())
+			if tuiProgram != nil {
+				tuiProgram.SendOutput("⚠ " + msg)
+			} else {
+				fmt.Printf("\n%s\n", msg)
+			}
+			suffix = deps.guard.Escalation()
+		}
+
+		// Pick up instructions dropped into the inject file since the last iteration
+		if injector != nil {
+			suffix += takeInjection(injector, tuiProgram)
+		}
+
+		// An interjection overrides the plan, so it goes before the prompts
+		prefix := ""
+		if interjector != nil {
+			prefix = takeInterjection(interjector, iteration, runs.EventLogPath(workingDir, st.SessionID), tuiProgram)
+		}
+
+		// Tell the agent about task statuses the operator changed in the TUI
+		if tuiProgram != nil {
+			prefix += tasks.FormatCorrections(tuiProgram.TakeTaskCorrections())
+		}
+
+		// Work from the current spec if it was edited since the last iteration
+		if changed := specWatcher.Changed(); len(changed) > 0 {
+			suffix += noteSpecChange(changed, notes, iteration, tuiProgram)
+		}
+
+		// The first iteration learns what the session starts from
+		if iteration == 1 {
+			suffix += cfg.SeedPrompt
+		}
+		runner.SetPromptSuffix(suffix)
+		runner.SetPromptPrefix(prefix)
+		runner.SetLeanContext(lean)
+		lean = ""
+
 		if tuiProgram == nil {
 			fmt.Printf("\n══════════════════════════════════════════════════════════════\n")
-			fmt.Printf("  Iteration %d - Workflow: %s\n", iteration, wf.Name)
+			if cfg.InBurst(iteration) {
+				fmt.Printf("  Iteration %d - Workflow: %s (burst: %s)\n", iteration, wf.Name, cfg.BurstModel)
+			} else {
+				fmt.Printf("  Iteration %d - Workflow: %s\n", iteration, wf.Name)
+			}
 			fmt.Printf("══════════════════════════════════════════════════════════════\n\n")
 		}
 
-		// Run the workflow (step timeouts are handled by the workflow runner)
-		runResult, err := runner.Run(ctx)
+		// Run the workflow (step timeouts are handled by the workflow runner).
+		// An optimistic iteration stops once the previous one is verified.
+		runCtx := ctx
+		var passCancel context.CancelFunc
+		if pending != nil {
+			runCtx, passCancel = pending.Context(ctx)
+		}
+		costBefore := loopState.TotalCost
+		loopState.StartIteration(time.Now())
+		runResult, err := runner.Run(runCtx)
+		loopState.EndIteration(time.Now())
+		// The steps' own cost, before the verification that ran alongside
+		// is counted
+		stepsCost := loopState.TotalCost - costBefore
+		if passCancel != nil {
+			passCancel()
+		}
+
+		// Settle the verification that ran alongside this iteration
+		if pending != nil {
+			verified := pending.Iteration
+			verifyResult, verifyErr := pending.Wait()
+			pending = nil
+			countVerification(verified, verifyResult)
+			var msg string
+			switch {
+			case verifyErr != nil:
+				msg = fmt.Sprintf("Verification of iteration %d: error: %v. Continuing.", verified, verifyErr)
+			case !verifyResult.Verified:
+				msg = fmt.Sprintf("Verification of iteration %d: %s. Continuing.", verified, verifyResult.Describe())
+				if cfg.LeanPrompts {
+					lean = leanPrompt(specFiles, verifyResult.Items, verifyResult.Describe())
+				}
+			}
+			if msg != "" {
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("⚠ " + msg)
+				} else {
+					fmt.Println(msg)
+				}
+			} else {
+				// The work was already complete: this iteration is discarded,
+				// though what it spent is still counted
+				msg = fmt.Sprintf("Verification of iteration %d: %s. Cancelled iteration %d.", verified, verifyResult.Describe(), iteration)
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("✓ " + msg)
+				} else {
+					fmt.Println(msg)
+				}
+				loopState.Iteration = verified
+				if done, err := completeRun(); done || err != nil {
+					return loopState, err
+				}
+				// Queued files: run this iteration again on them
+				iteration--
+				continue
+			}
+		}
+
+		// Record which model served this iteration when fallbacks or burst
+		// mode are in play
+		if stepExec.chain != nil || cfg.BurstIterations > 0 {
+			loopState.IterationModels = append(loopState.IterationModels, iterationModel(exec, stepExec))
+			st.RecordModel(iteration, iterationModel(exec, stepExec))
+		}
 
 		// Update iteration callback
+		syncIssueMirror(ctx, deps.mirror, st, iteration, loopState.TotalCost, tuiProgram)
+		st.RecordResources(loopState.CPUTime, loopState.PeakRSS)
 		if err := updateState(st, iteration, loopState.TotalCost); err != nil {
 			loopState.Error = err
 			return loopState, err
 		}
+		if err := snapshotIteration(deps.repo, st, iteration); err != nil {
+			reportSnapshotError(err, tuiProgram)
+		}
+		recordHead(deps.repo, deps.rec, iteration)
+		tree := treeState(ctx)
+		if tree != "" && tree == lastTree {
+			deps.rec.AddUnchanged(iteration)
+		}
+		lastTree = tree
+		if err := recordCheckpoints(st, iteration, deps.checkpoints.Take(), tuiProgram); err != nil {
+			reportSnapshotError(err, tuiProgram)
+		}
+		deps.todos.scan(ctx, tuiProgram)
+
+		// Flag an iteration that cost far more than usual
+		if a := deps.costs.Observe(iteration, stepsCost); a != nil {
+			if err := reportCostAnomaly(ctx, *a, deps.notifier, tuiProgram, deps.keys); err != nil {
+				loopState.Error = err
+				return loopState, err
+			}
+		}
+
+		// Raise or lower the turn limit for the next iterations
+		if !cfg.InBurst(iteration) {
+			if a := turnTuner.Observe(iteration, iterationTurns, iterationOutOfTurns); a != nil {
+				applyTurnAdjustment(cfg, *a, tuiProgram)
+			}
+		}
 
 		if err != nil {
 			// Check for step timeout (after retry) - continue to next iteration
@@ -992,11 +2217,35 @@ func runWorkflowLoop(
 				}
 				continue
 			}
+			// A stalled claude was killed - retry in the next iteration
+			if errors.Is(err, executor.ErrStalled) {
+				msg := fmt.Sprintf("Iteration %d: claude stalled. Continuing to next iteration...", iteration)
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("⏱ " + msg)
+				} else {
+					fmt.Printf("\n%s\n", msg)
+				}
+				continue
+			}
+			// A step did not write its artifacts after a retry - try again next iteration
+			if errors.Is(err, workflow.ErrArtifactMissing) {
+				msg := fmt.Sprintf("Iteration %d: %v. Continuing to next iteration...", iteration, err)
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("⚠ " + msg)
+				} else {
+					fmt.Printf("\n%s\n", msg)
+				}
+				continue
+			}
 			// Check for max gate retries exceeded
 			if errors.Is(err, workflow.ErrMaxGateRetriesExceeded) {
 				if tuiProgram == nil {
 					fmt.Printf("\nWorkflow gate failed too many times: %v\n", err)
 				}
+				if nerr := deps.notifier.Notify(notify.EventGateFailed, "Orbital gate failed", err.Error()); nerr != nil {
+					reportNotifyError(nerr, tuiProgram)
+				}
+				lean = gateLean
 				// Continue to next iteration rather than failing completely
 				continue
 			}
@@ -1004,41 +2253,45 @@ func runWorkflowLoop(
 			return loopState, err
 		}
 
-		// Check budget
+		// Compare with the previous iteration to detect no-progress loops
+		stagnation = deps.guard.Observe(runResult.Output(), tree)
+
+		// Check budget. An iteration that ends in the reserve may still be
+		// verified; the next one is not started.
 		if loopState.TotalCost >= cfg.MaxBudget {
 			loopState.Error = loop.ErrBudgetExceeded
 			return loopState, loop.ErrBudgetExceeded
 		}
 
 		// Check if completion promise was detected in any step output
-		promiseDetected := false
-		for _, stepResult := range runResult.Steps {
-			if stepResult == nil {
-				continue
-			}
-			if strings.Contains(stepResult.Output, cfg.CompletionPromise) {
-				promiseDetected = true
-				if tuiProgram == nil {
-					fmt.Printf("\nCompletion promise detected in step %q. Running verification...\n", stepResult.StepName)
-				}
-				break
-			}
+		promiseStep := runResult.PromiseStep(cfg.CompletionPromise)
+		if promiseStep != nil && tuiProgram == nil {
+			fmt.Printf("\nCompletion promise detected in step %q. Running verification...\n", promiseStep.StepName)
 		}
 
 		// Workflow completed all steps (including gates passing) OR promise detected
-		if runResult.CompletedAllSteps || promiseDetected {
-			if tuiProgram == nil && !promiseDetected {
+		if runResult.CompletedAllSteps || promiseStep != nil {
+			if tuiProgram == nil && promiseStep == nil {
 				fmt.Println("\nWorkflow completed. Running verification...")
 			}
 
+			// Verify while the next iteration starts, rather than before
+			if loop.VerifyOptimistically(cfg, iteration, loopState.TotalCost) {
+				msg := fmt.Sprintf("Verifying iteration %d while iteration %d starts...", iteration, iteration+1)
+				if tuiProgram != nil {
+					tuiProgram.SendOutput("⏳ " + msg)
+				} else {
+					fmt.Println(msg)
+				}
+				pending = loop.StartVerification(ctx, deps.verifier, specFiles, iteration)
+				continue
+			}
+
 			// Run verification
-			verifyResult, verifyErr := runVerification(ctx, cfg, specFiles)
+			verifyResult, verifyErr := deps.verifier.Verify(ctx, specFiles)
 
 			// Add verification cost
-			if verifyResult != nil {
-				loopState.TotalCost += verifyResult.Cost
-				loopState.TotalTokens += verifyResult.Tokens
-			}
+			countVerification(iteration, verifyResult)
 
 			if verifyErr != nil {
 				msg := fmt.Sprintf("Verification error: %v. Continuing.", verifyErr)
@@ -1051,62 +2304,29 @@ func runWorkflowLoop(
 			}
 
 			if !verifyResult.Verified {
-				var msg string
-				if verifyResult.Unchecked >= 0 {
-					msg = fmt.Sprintf("Verification: %d unchecked item(s) remain. Continuing.", verifyResult.Unchecked)
-				} else {
-					msg = "Verification: could not parse response. Continuing."
-				}
+				msg := fmt.Sprintf("Verification: %s. Continuing.", verifyResult.Describe())
 				if tuiProgram != nil {
 					tuiProgram.SendOutput("⚠ " + msg)
 				} else {
 					fmt.Println(msg)
 				}
+				if cfg.LeanPrompts {
+					lean = leanPrompt(specFiles, verifyResult.Items, verifyResult.Describe())
+				}
 				continue
 			}
 
 			// Verification passed
-			msg := fmt.Sprintf("Verification: all items complete (%d checked).", verifyResult.Checked)
+			msg := fmt.Sprintf("Verification: %s.", verifyResult.Describe())
 			if tuiProgram != nil {
 				tuiProgram.SendOutput("✓ " + msg)
 			} else {
 				fmt.Println(msg)
 			}
 
-			// Check queue for new files
-			if sm != nil {
-				queuedFiles, err := sm.PopQueue()
-				if err != nil {
-					loopState.Error = err
-					return loopState, err
-				}
-
-				if len(queuedFiles) > 0 {
-					if tuiProgram == nil {
-						fmt.Printf("Found %d queued file(s), continuing...\n", len(queuedFiles))
-						for _, f := range queuedFiles {
-							fmt.Printf("  + %s\n", f)
-						}
-					}
-
-					if err := sm.MergeFiles(queuedFiles); err != nil {
-						loopState.Error = err
-						return loopState, err
-					}
-
-					// Update runner's file paths
-					runner.SetFilePaths(append(specFiles, queuedFiles...))
-					continue
-				}
-			}
-
-			// Done - print workflow summary
-			if tuiProgram == nil {
-				fmt.Println("No queued files. Work complete.")
-				formatter.PrintWorkflowSummary(stepSummaries, loopState.TotalCost, loopState.TotalTokens)
+			if done, err := completeRun(); done || err != nil {
+				return loopState, err
 			}
-			loopState.Completed = true
-			return loopState, nil
 		}
 	}
 
@@ -1118,28 +2338,473 @@ func runWorkflowLoop(
 	return loopState, loop.ErrMaxIterationsReached
 }
 
-// runVerification executes verification using the checker model.
-func runVerification(ctx context.Context, cfg *config.Config, specFiles []string) (*loop.VerificationResult, error) {
-	verifyConfig := &config.Config{
-		Model:     cfg.CheckerModel,
-		MaxBudget: cfg.MaxBudget,
+// newRunRecord creates a run record, capturing the current revision under
+// version control so the final diff can be recorded.
+func newRunRecord(repo vcs.Backend, sessionID string, specFiles []string, workflowName, model string) *runs.Record {
+	rec := runs.NewRecord(sessionID, specFiles, workflowName, model)
+	rec.Version = version
+	if head, err := repo.Head(context.Background()); err == nil {
+		rec.StartCommit = head
+	}
+	return rec
+}
+
+// recordHead adds the revision at the end of an iteration to the run record,
+// so `orbital blame` can trace the commits it made back to the run.
+func recordHead(repo vcs.Backend, rec *runs.Record, iteration int) {
+	if head, err := repo.Head(context.Background()); err == nil {
+		rec.AddHead(iteration, head)
+	}
+}
+
+// finishRunRecord fills in the final totals and outcome of a run.
+func finishRunRecord(repo vcs.Backend, rec *runs.Record, loopState *loop.LoopState, runErr error) {
+	rec.FinishedAt = time.Now()
+	if loopState != nil {
+		rec.Iterations = loopState.Iteration
+		rec.TotalCost = loopState.TotalCost
+		rec.TokensIn = loopState.TotalTokensIn
+		rec.TokensOut = loopState.TotalTokensOut
+		rec.Completed = loopState.Completed
+		rec.Redactions = loopState.Redactions
+	}
+	rec.Outcome = runOutcome(runErr)
+	recordHead(repo, rec, rec.Iterations)
+
+	if rec.StartCommit != "" {
+		if stat, err := repo.DiffStat(context.Background(), rec.StartCommit); err == nil {
+			rec.DiffStat = stat
+		}
+	}
+}
+
+// recordStats adds the finished run to the local usage statistics when the
+// config file opts in. Failures are reported but never fail the run.
+func recordStats(fileConfig *config.FileConfig, rec *runs.Record, budget float64) {
+	if fileConfig == nil || fileConfig.Stats == nil || !fileConfig.Stats.Enabled {
+		return
+	}
+	sample := telemetry.Sample{
+		FinishedAt: rec.FinishedAt,
+		Workflow:   rec.Workflow,
+		Model:      rec.Model,
+		Iterations: rec.Iterations,
+		Completed:  rec.Completed,
+		Cost:       rec.TotalCost,
+		Budget:     budget,
+	}
+	if err := telemetry.Record(workingDir, sample); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record usage statistics: %v\n", err)
 	}
+}
 
-	verifyExec := executor.New(verifyConfig)
-	prompt := spec.BuildVerificationPrompt(specFiles)
+// runOutcome describes how a run ended based on the loop error.
+func runOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "completed"
+	case errors.Is(err, loop.ErrMaxIterationsReached):
+		return "max iterations reached"
+	case errors.Is(err, loop.ErrBudgetExceeded):
+		return "budget exceeded"
+	case errors.Is(err, loop.ErrNoProgress):
+		return "no progress"
+	case errors.Is(err, loop.ErrCostAnomaly):
+		return "stopped after cost anomalies"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "interrupted"
+	case errors.As(err, new(*tui.PanicError)):
+		return "crashed"
+	default:
+		return "failed"
+	}
+}
 
-	result, err := verifyExec.Execute(ctx, prompt)
+// loadFileConfig loads the user config (~/.config/orbital/config.toml) with
+// the project config layered on top. The project config is the --config file
+// if given, otherwise the optional .orbital/config.toml in dir. Returns nil if
+// neither file exists.
+func loadFileConfig(dir string) (*config.FileConfig, error) {
+	layered, err := config.LoadLayered(configSources(dir))
 	if err != nil {
-		return nil, fmt.Errorf("verification execution failed: %w", err)
+		return nil, err
 	}
+	return layered.Config, nil
+}
 
-	verified, unchecked, checked := loop.ParseVerificationResponse(result.Output)
+// configSources returns the config layers for dir, lowest precedence first.
+func configSources(dir string) []config.Source {
+	project := config.Source{Layer: config.LayerProject, Path: filepath.Join(dir, ".orbital", "config.toml")}
+	if configFile != "" {
+		project = config.Source{Layer: config.LayerProject, Path: configFile, Required: true}
+	}
+	return []config.Source{
+		{Layer: config.LayerUser, Path: config.UserConfigPath()},
+		project,
+	}
+}
 
-	return &loop.VerificationResult{
-		Verified:  verified,
-		Unchecked: unchecked,
-		Checked:   checked,
-		Cost:      result.CostUSD,
-		Tokens:    result.TokensIn + result.TokensOut,
-	}, nil
+// openThinkingLog resolves the thinking mode from the config file. In log mode it
+// also opens .orbital/thinking.log for appending, encrypted when encryption at
+// rest is enabled; the caller must close it.
+func openThinkingLog(fileConfig *config.FileConfig, workingDir string) (output.ThinkingMode, io.WriteCloser, error) {
+	var configured string
+	if fileConfig != nil {
+		configured = fileConfig.Thinking
+	}
+	mode, err := output.ParseThinkingMode(configured)
+	if err != nil {
+		return "", nil, err
+	}
+	if mode != output.ThinkingLog {
+		return mode, nil, nil
+	}
+
+	f, err := encrypt.OpenAppend(output.ThinkingLogPath(workingDir))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open thinking log: %w", err)
+	}
+	return mode, f, nil
+}
+
+// resolveSanitize returns how control sequences in the model's output are
+// handled, from sanitize in the config file (default strip).
+func resolveSanitize(fileConfig *config.FileConfig) (output.SanitizeMode, error) {
+	var configured string
+	if fileConfig != nil {
+		configured = fileConfig.Sanitize
+	}
+	return output.ParseSanitizeMode(configured)
+}
+
+// newNotifier creates a notifier from the config file's notifications section.
+// Returns nil (a no-op notifier) if notifications are not configured.
+func newNotifier(fileConfig *config.FileConfig) *notify.Notifier {
+	if fileConfig == nil || fileConfig.Notifications == nil {
+		return nil
+	}
+	return notify.New(*fileConfig.Notifications)
+}
+
+// sweepOrphanedProcesses kills claude processes left running by an orbital
+// run that crashed in dir. Failures are reported but never stop the run.
+func sweepOrphanedProcesses(dir string) {
+	killed, err := executor.SweepOrphans(executor.PIDDir(dir))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: orphaned process cleanup failed: %v\n", err)
+	}
+	if len(killed) > 0 {
+		fmt.Fprintf(os.Stderr, "Killed %d orphaned claude process(es) left by a previous run\n", len(killed))
+	}
+}
+
+// resolveStall applies the [stall] config section and then the
+// --stall-timeout and --stall-action flags, if given, to cfg.
+func resolveStall(cmd *cobra.Command, cfg *config.Config, fileConfig *config.FileConfig) error {
+	if fileConfig != nil && fileConfig.Stall != nil {
+		cfg.StallTimeout = time.Duration(fileConfig.Stall.Timeout)
+		cfg.StallAction = fileConfig.Stall.Action
+	}
+	if cmd.Flags().Changed("stall-timeout") {
+		cfg.StallTimeout = stallTimeout
+	}
+	if cmd.Flags().Changed("stall-action") {
+		cfg.StallAction = stallAction
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	return nil
+}
+
+// resolveInjectFile returns the --inject-file path, relative to dir unless
+// absolute. An empty flag disables instruction injection.
+func resolveInjectFile(dir string) string {
+	if injectFile == "" || filepath.IsAbs(injectFile) {
+		return injectFile
+	}
+	return filepath.Join(dir, injectFile)
+}
+
+// stallReporter returns an executor.StallCallback that reports stalls in the
+// TUI or on stderr.
+func stallReporter(tuiProgram *tui.Program) executor.StallCallback {
+	return func(idle time.Duration, killed bool) {
+		msg := fmt.Sprintf("Claude has produced no output for %s", idle.Round(time.Second))
+		if killed {
+			msg += "; killing it and moving on to the next iteration"
+		}
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("⚠ " + msg)
+		} else {
+			fmt.Fprintln(os.Stderr, "Warning: "+msg)
+		}
+	}
+}
+
+// notifyCompleted sends the notification for a session that completed.
+func notifyCompleted(notifier *notify.Notifier, loopState *loop.LoopState, tuiProgram *tui.Program) {
+	doneMsg := fmt.Sprintf("Session complete after %d iteration(s), %s spent", loopState.Iteration, util.FormatCost(loopState.TotalCost, 2))
+	if err := notifier.Notify(notify.EventCompleted, "Orbital complete", doneMsg); err != nil {
+		reportNotifyError(err, tuiProgram)
+	}
+}
+
+// reportNotifyError surfaces a notification failure without interrupting the loop.
+func reportNotifyError(err error, tuiProgram *tui.Program) {
+	msg := fmt.Sprintf("Notification failed: %v", err)
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("⚠ " + msg)
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: "+msg)
+	}
+}
+
+// resolveTUILayout returns the TUI layout knobs from the [tui] config
+// section, or the default layout without one.
+func resolveTUILayout(fileConfig *config.FileConfig) (tui.LayoutConfig, error) {
+	if fileConfig == nil || fileConfig.TUI == nil {
+		return tui.LayoutConfig{}, nil
+	}
+	layout := tui.LayoutConfig{
+		TaskRows:      fileConfig.TUI.TaskRows,
+		MinOutputRows: fileConfig.TUI.MinOutputRows,
+		BarWidth:      fileConfig.TUI.BarWidth,
+		Hide:          fileConfig.TUI.Hide,
+	}
+	if err := layout.Validate(); err != nil {
+		return tui.LayoutConfig{}, fmt.Errorf("invalid [tui] config: %w", err)
+	}
+	return layout, nil
+}
+
+// streamTextEnabled reports whether the TUI shows assistant text as it is
+// generated, from [tui] stream_text (default: on).
+func streamTextEnabled(fileConfig *config.FileConfig) bool {
+	if fileConfig == nil || fileConfig.TUI == nil || fileConfig.TUI.StreamText == nil {
+		return true
+	}
+	return *fileConfig.TUI.StreamText
+}
+
+// resolveClaudeArgs returns the extra Claude CLI flags from [claude]
+// extra_args followed by --claude-arg, rejecting flags orbital manages.
+func resolveClaudeArgs(fileConfig *config.FileConfig) ([]string, error) {
+	var args []string
+	if fileConfig != nil && fileConfig.Claude != nil {
+		args = append(args, fileConfig.Claude.ExtraArgs...)
+	}
+	args = append(args, claudeArgs...)
+	if err := executor.ValidateExtraArgs(args); err != nil {
+		return nil, fmt.Errorf("invalid claude args: %w", err)
+	}
+	return args, nil
+}
+
+// resolveRedactor builds the secret redactor from the built-in patterns and
+// [redact]. It is nil when [redact] disables the built-in patterns and adds
+// none of its own.
+func resolveRedactor(fileConfig *config.FileConfig) (*redact.Redactor, error) {
+	patterns := redact.Builtin
+	if fileConfig != nil && fileConfig.Redact != nil {
+		if fileConfig.Redact.DisableBuiltin {
+			patterns = nil
+		}
+		custom, err := redact.Compile(fileConfig.Redact.Patterns)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact config: %w", err)
+		}
+		patterns = append(append([]redact.Pattern(nil), patterns...), custom...)
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return redact.New(patterns), nil
+}
+
+// newProgressGuard builds the no-progress guard from [no_progress].
+func newProgressGuard(fileConfig *config.FileConfig) (*loop.ProgressGuard, error) {
+	var limit int
+	var action string
+	if fileConfig != nil && fileConfig.NoProgress != nil {
+		limit = fileConfig.NoProgress.Limit
+		action = fileConfig.NoProgress.Action
+	}
+	guard, err := loop.NewProgressGuard(limit, action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid no_progress config: %w", err)
+	}
+	return guard, nil
+}
+
+// treeStateFunc returns a loop.TreeStateFunc for repo. Without version
+// control it always returns "", so only outputs are compared.
+func treeStateFunc(repo vcs.Backend) loop.TreeStateFunc {
+	return func(ctx context.Context) string {
+		tree, err := repo.TreeState(ctx)
+		if err != nil {
+			return ""
+		}
+		return tree
+	}
+}
+
+// resolveVCS returns the version control backend for dir selected by the
+// vcs config key (default: auto).
+func resolveVCS(fileConfig *config.FileConfig, dir string) (vcs.Backend, error) {
+	name := ""
+	if fileConfig != nil {
+		name = fileConfig.VCS
+	}
+	repo, err := vcs.New(context.Background(), name, dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vcs config: %w", err)
+	}
+	return repo, nil
+}
+
+// setupEncryption enables encryption at rest for the event log, thinking log,
+// run records and state when the [encryption] section turns it on.
+func setupEncryption(fileConfig *config.FileConfig) error {
+	if fileConfig == nil || fileConfig.Encryption == nil || !fileConfig.Encryption.Enabled {
+		encrypt.SetKey(nil)
+		return nil
+	}
+	key, err := encrypt.LoadKey(fileConfig.Encryption.KeySource)
+	if err != nil {
+		return fmt.Errorf("invalid encryption config: %w", err)
+	}
+	encrypt.SetKey(key)
+	return nil
+}
+
+// setupDisplay applies the [display] currency and locale to cost and number
+// formatting.
+func setupDisplay(fileConfig *config.FileConfig) error {
+	var d config.DisplayConfig
+	if fileConfig != nil && fileConfig.Display != nil {
+		d = *fileConfig.Display
+	}
+	if err := util.SetDisplay(d.Currency, d.Locale, d.Rate); err != nil {
+		return fmt.Errorf("invalid display config: %w", err)
+	}
+	return nil
+}
+
+// defaultFixturesDir is where --executor mock and --record-fixtures look for
+// fixtures, relative to the working directory.
+const defaultFixturesDir = ".orbital/fixtures"
+
+// setupExecutor selects the executor backend (--executor) and turns on
+// fixture recording (--record-fixtures). A relative --fixtures is resolved
+// against dir.
+func setupExecutor(dir string) error {
+	fixtures := fixturesDir
+	if !filepath.IsAbs(fixtures) {
+		fixtures = filepath.Join(dir, fixtures)
+	}
+	if recordFixtures && executorName != executor.BackendClaude {
+		return fmt.Errorf("--record-fixtures records Claude runs and cannot be used with --executor %s", executorName)
+	}
+	if err := executor.UseBackend(executorName, fixtures); err != nil {
+		return fmt.Errorf("invalid --executor: %w", err)
+	}
+	record := ""
+	if recordFixtures {
+		record = fixtures
+	}
+	return executor.RecordTo(record)
+}
+
+// loadDisplay reads the config files in dir and applies their [display]
+// settings, for subcommands that only print costs.
+func loadDisplay(dir string) error {
+	fileConfig, err := loadFileConfig(dir)
+	if err != nil {
+		return err
+	}
+	return setupDisplay(fileConfig)
+}
+
+// warnNoVCS tells the user which features are off without version control.
+func warnNoVCS(w io.Writer, repo vcs.Backend, dir string) {
+	if repo.Name() != vcs.BackendNone {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Note: %s is not under version control; iteration snapshots, rollback, diff summaries, and tree-based progress detection are disabled\n", dir)
+}
+
+// newVerifier builds the verification strategy from the workflow's
+// [workflow.verification] section, defaulting to the checker model.
+func newVerifier(fileConfig *config.FileConfig, cfg *config.Config, workingDir string) (loop.Verifier, error) {
+	opts := loop.VerifierOptions{
+		WorkingDir:   workingDir,
+		CheckerModel: cfg.CheckerModel,
+		MaxBudget:    cfg.MaxBudget,
+	}
+	tick := cfg.TickSpec
+	if fileConfig != nil && fileConfig.Workflow != nil && fileConfig.Workflow.Verification != nil {
+		v := fileConfig.Workflow.Verification
+		opts.Strategy = v.Strategy
+		opts.Command = v.Command
+		opts.Timeout = time.Duration(v.Timeout)
+		opts.PerFile = v.PerFile
+		opts.Prompt = v.Prompt
+		opts.VerifiedPattern = v.Verified
+		opts.IncompletePattern = v.Incomplete
+		opts.Files = v.Files
+		tick = tick || v.Tick
+	}
+
+	verifier, err := loop.NewVerifier(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification config: %w", err)
+	}
+	if cfg.DoubleCheckModel != "" {
+		// The second check reads the same files with the same prompt
+		check := opts
+		check.Strategy = loop.StrategyModel
+		check.CheckerModel = cfg.DoubleCheckModel
+		check.PerFile = false
+		second, err := loop.NewVerifier(check)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification config: %w", err)
+		}
+		verifier = loop.NewDoubleCheckVerifier(verifier, second)
+	}
+	if tick {
+		// Outermost, so it ticks only once every check has had its say
+		verifier = loop.NewTickingVerifier(verifier, cfg.CheckerModel, cfg.MaxBudget, workingDir)
+	}
+	return verifier, nil
+}
+
+// resolveAgents returns the agents JSON for Claude CLI. The --agents flag
+// takes precedence over the config file; the default agents are always
+// included.
+func resolveAgents(fileConfig *config.FileConfig) (string, error) {
+	if agents != "" {
+		// CLI flag provided - merge with defaults via GetEffectiveAgents
+		agentsJSON, err := config.GetEffectiveAgents(agents)
+		if err != nil {
+			return "", fmt.Errorf("invalid --agents flag: %w", err)
+		}
+		return agentsJSON, nil
+	}
+	if fileConfig != nil && len(fileConfig.Agents) > 0 {
+		// Config file agents - AgentsToJSON already merges with defaults
+		agentsJSON, err := config.AgentsToJSON(fileConfig.Agents)
+		if err != nil {
+			return "", fmt.Errorf("failed to convert agents config: %w", err)
+		}
+		return agentsJSON, nil
+	}
+	// No user agents - use defaults only
+	agentsJSON, err := config.AgentsToJSON(nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get default agents: %w", err)
+	}
+	return agentsJSON, nil
 }
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/sandbox"
+	"github.com/spf13/cobra"
+)
+
+var sandboxCmd = newSandboxCmd()
+
+func newSandboxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sandbox",
+		Short: "Review and apply the changes of sessions run with --sandbox copy",
+		Long: `Sessions started with --sandbox copy run in a copy of the project in
+.orbital/sandbox/<id>/, leaving the project itself untouched. These
+commands show what a sandboxed session changed and carry the changes back.
+
+The sandbox id is optional; the most recent sandbox is used without it.`,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(newSandboxListCmd(), newSandboxDiffCmd(), newSandboxApplyCmd(), newSandboxDiscardCmd())
+	return cmd
+}
+
+func newSandboxListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the project's sandboxes and how many files each changed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := filepath.Abs(workingDir)
+			if err != nil {
+				return fmt.Errorf("failed to resolve working directory: %w", err)
+			}
+			sandboxes, err := sandbox.List(dir)
+			if err != nil {
+				return err
+			}
+			return printSandboxes(cmd.OutOrStdout(), sandboxes, time.Now())
+		},
+	}
+}
+
+func newSandboxDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff [id]",
+		Short: "Show a sandbox's changes as a unified diff against the project",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, changes, err := openSandbox(args)
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Sandbox %s has no changes\n", s.ID)
+				return nil
+			}
+			return s.Diff(cmd.OutOrStdout(), changes)
+		},
+	}
+}
+
+func newSandboxApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply [id]",
+		Short: "Copy a sandbox's changes into the project and remove the sandbox",
+		Long: `Copy the files a sandboxed session added or modified into the project,
+and delete the files it deleted. Git metadata and orbital's state are not
+carried over.
+
+Nothing is applied when a file the session changed was also changed in the
+project since the sandbox was created, unless --force is given. The
+sandbox is removed afterwards unless --keep is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, changes, err := openSandbox(args)
+			if err != nil {
+				return err
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			keep, _ := cmd.Flags().GetBool("keep")
+			return applySandbox(cmd.OutOrStdout(), s, changes, force, keep)
+		},
+	}
+	cmd.Flags().Bool("force", false, "Apply even files that were also changed in the project")
+	cmd.Flags().Bool("keep", false, "Keep the sandbox after applying its changes")
+	return cmd
+}
+
+func newSandboxDiscardCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "discard [id]",
+		Short: "Remove a sandbox without applying its changes",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, _, err := openSandbox(args)
+			if err != nil {
+				return err
+			}
+			if err := s.Remove(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Discarded sandbox %s\n", s.ID)
+			return nil
+		},
+	}
+}
+
+// enterSandbox moves a new session into a copy of the project with
+// --sandbox copy. The process changes into the copy, and the spec, context
+// and notes files given on the command line are taken from it where they lie
+// inside the project. The project config is still read from the original
+// tree, since .orbital/ is not copied. Without --sandbox it does nothing.
+func enterSandbox(args []string, out io.Writer) error {
+	if sandboxMode == "" {
+		return nil
+	}
+	if sandboxMode != sandbox.ModeCopy {
+		return fmt.Errorf("unknown sandbox mode %q: must be %s", sandboxMode, sandbox.ModeCopy)
+	}
+	if fromBranch != "" {
+		return errors.New("--sandbox cannot be combined with --from-branch")
+	}
+	original, err := filepath.Abs(workingDir)
+	if err != nil {
+		return fmt.Errorf("invalid working directory: %w", err)
+	}
+	id, err := generateSessionID()
+	if err != nil {
+		return err
+	}
+	s, err := sandbox.Create(original, id)
+	if err != nil {
+		return err
+	}
+	path := s.Path()
+
+	if len(args) > 0 && args[0] != "-" {
+		args[0] = worktreeFile(args[0], original, path, true)
+	}
+	for i, f := range contextFiles {
+		contextFiles[i] = worktreeFile(f, original, path, true)
+	}
+	if notesFile != "" {
+		notesFile = worktreeFile(notesFile, original, path, false)
+	}
+	if configFile == "" {
+		projectConfig := filepath.Join(original, ".orbital", "config.toml")
+		if _, err := os.Stat(projectConfig); err == nil {
+			configFile = projectConfig
+		}
+	}
+
+	if err := os.Chdir(path); err != nil {
+		return fmt.Errorf("failed to change into sandbox: %w", err)
+	}
+	workingDir = path
+	fmt.Fprintf(out, "Running in sandbox %s; review with 'orbital sandbox diff %s' and apply with 'orbital sandbox apply %s'\n", path, id, id)
+	return nil
+}
+
+// openSandbox opens the sandbox named by args, or the most recent one, and
+// returns its changes.
+func openSandbox(args []string) (*sandbox.Sandbox, []sandbox.Change, error) {
+	dir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+	var s *sandbox.Sandbox
+	if len(args) > 0 {
+		s, err = sandbox.Open(dir, args[0])
+	} else {
+		s, err = latestSandbox(dir)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := s.Changes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, changes, nil
+}
+
+// latestSandbox returns the most recently created sandbox of the project.
+func latestSandbox(dir string) (*sandbox.Sandbox, error) {
+	sandboxes, err := sandbox.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(sandboxes) == 0 {
+		return nil, fmt.Errorf("no sandboxes in %s; start one with --sandbox copy", dir)
+	}
+	return sandboxes[len(sandboxes)-1], nil
+}
+
+// applySandbox applies changes from s to the project, lists them, and
+// removes s unless keep is set.
+func applySandbox(out io.Writer, s *sandbox.Sandbox, changes []sandbox.Change, force, keep bool) error {
+	if err := s.Apply(changes, force); err != nil {
+		if errors.Is(err, sandbox.ErrConflict) {
+			return fmt.Errorf("%w; review with 'orbital sandbox diff %s' and use --force to overwrite", err, s.ID)
+		}
+		return err
+	}
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(out, "  %-9s %s\n", c.Kind, c.Path)
+	}
+	_, _ = fmt.Fprintf(out, "Applied %d change(s) from sandbox %s\n", len(changes), s.ID)
+	if keep {
+		return nil
+	}
+	return s.Remove()
+}
+
+// printSandboxes lists sandboxes with their age and number of changes.
+func printSandboxes(out io.Writer, sandboxes []*sandbox.Sandbox, now time.Time) error {
+	if len(sandboxes) == 0 {
+		_, _ = fmt.Fprintln(out, "No sandboxes")
+		return nil
+	}
+	for _, s := range sandboxes {
+		changes, err := s.Changes()
+		if err != nil {
+			return err
+		}
+		conflicts := 0
+		for _, c := range changes {
+			if c.Conflict {
+				conflicts++
+			}
+		}
+		line := fmt.Sprintf("%s  created %s ago  %d change(s)", s.ID, formatDuration(now.Sub(s.CreatedAt)), len(changes))
+		if conflicts > 0 {
+			line += fmt.Sprintf(", %d conflicting", conflicts)
+		}
+		_, _ = fmt.Fprintln(out, line)
+	}
+	return nil
+}
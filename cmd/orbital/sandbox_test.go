@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/sandbox"
+)
+
+func TestEnterSandbox(t *testing.T) {
+	repo := t.TempDir()
+	for name, content := range map[string]string{
+		"spec.md":              "- [ ] Build it\n",
+		"CONTEXT.md":           "context\n",
+		".orbital/config.toml": "",
+	} {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldMode, oldWorkingDir, oldConfig, oldContext := sandboxMode, workingDir, configFile, contextFiles
+	defer func() {
+		sandboxMode, workingDir, configFile, contextFiles = oldMode, oldWorkingDir, oldConfig, oldContext
+	}()
+	t.Chdir(repo)
+	sandboxMode, workingDir, configFile, contextFiles = sandbox.ModeCopy, ".", "", []string{"CONTEXT.md"}
+
+	args := []string{"spec.md"}
+	var out strings.Builder
+	if err := enterSandbox(args, &out); err != nil {
+		t.Fatalf("enterSandbox() error = %v", err)
+	}
+
+	sandboxes, err := sandbox.List(mustResolve(t, repo))
+	if err != nil || len(sandboxes) != 1 {
+		t.Fatalf("sandboxes = %v, %v, want one", sandboxes, err)
+	}
+	path := sandboxes[0].Path()
+	cwd, _ := os.Getwd()
+	if mustResolve(t, cwd) != mustResolve(t, path) || mustResolve(t, workingDir) != mustResolve(t, path) {
+		t.Errorf("cwd, workingDir = %s, %s, want the sandbox %s", cwd, workingDir, path)
+	}
+	if mustResolve(t, args[0]) != mustResolve(t, filepath.Join(path, "spec.md")) {
+		t.Errorf("spec = %s, want the sandbox's copy", args[0])
+	}
+	if mustResolve(t, contextFiles[0]) != mustResolve(t, filepath.Join(path, "CONTEXT.md")) {
+		t.Errorf("context file = %s, want the sandbox's copy", contextFiles[0])
+	}
+	if mustResolve(t, configFile) != mustResolve(t, filepath.Join(repo, ".orbital", "config.toml")) {
+		t.Errorf("configFile = %q, want the project's config", configFile)
+	}
+	if !strings.Contains(out.String(), "orbital sandbox apply "+sandboxes[0].ID) {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestEnterSandbox_InvalidModes(t *testing.T) {
+	oldMode, oldBranch := sandboxMode, fromBranch
+	defer func() { sandboxMode, fromBranch = oldMode, oldBranch }()
+
+	sandboxMode, fromBranch = "", ""
+	if err := enterSandbox([]string{"spec.md"}, os.Stdout); err != nil {
+		t.Errorf("enterSandbox() without --sandbox = %v, want nothing done", err)
+	}
+	sandboxMode = "overlay"
+	if err := enterSandbox([]string{"spec.md"}, os.Stdout); err == nil || !strings.Contains(err.Error(), `unknown sandbox mode "overlay"`) {
+		t.Errorf("enterSandbox() error = %v, want unknown mode", err)
+	}
+	sandboxMode, fromBranch = sandbox.ModeCopy, "feature/x"
+	if err := enterSandbox([]string{"spec.md"}, os.Stdout); err == nil || !strings.Contains(err.Error(), "--from-branch") {
+		t.Errorf("enterSandbox() error = %v, want the --from-branch conflict", err)
+	}
+}
+
+func TestApplySandboxCommand(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "spec.md"), []byte("- [ ] Build it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := sandbox.Create(repo, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Path(), "spec.md"), []byte("- [x] Build it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var list strings.Builder
+	if err := printSandboxes(&list, []*sandbox.Sandbox{s}, s.CreatedAt.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(list.String(), "abc  created 2m") || !strings.Contains(list.String(), "1 change(s)") {
+		t.Errorf("list = %q", list.String())
+	}
+
+	changes, err := s.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := applySandbox(&out, s, changes, false, false); err != nil {
+		t.Fatalf("applySandbox() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, "spec.md")); string(data) != "- [x] Build it\n" {
+		t.Errorf("spec = %q, want the sandbox's change", data)
+	}
+	if !strings.Contains(out.String(), "modified  spec.md") || !strings.Contains(out.String(), "Applied 1 change(s)") {
+		t.Errorf("output = %q", out.String())
+	}
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Errorf("sandbox kept after apply: %v", err)
+	}
+}
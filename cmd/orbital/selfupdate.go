@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/flashingpumpkin/orbital/internal/update"
+	"github.com/spf13/cobra"
+)
+
+var (
+	selfUpdateChannel string
+	selfUpdateCheck   bool
+)
+
+var selfUpdateCmd = newSelfUpdateCmd()
+
+func newSelfUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace this binary with the latest release",
+		Long: `Download the latest orbital release for this platform from GitHub and
+replace the running binary with it.
+
+The stable channel follows tagged releases; the edge channel follows the
+prerelease built from every push to main. The binary's SHA-256 is checked
+against the release's checksums.txt before anything is replaced, and the new
+binary is renamed into place so an interrupted update leaves the old one
+working. Nothing is downloaded when the installed binary already matches the
+release.
+
+With --check, only report whether an update is available.`,
+		Example: `  orbital self-update
+  orbital self-update --channel edge
+  orbital self-update --check`,
+		Args: cobra.NoArgs,
+		RunE: runSelfUpdate,
+	}
+	cmd.Flags().StringVar(&selfUpdateChannel, "channel", update.ChannelStable, "Release channel: stable or edge")
+	cmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether an update is available")
+	return cmd
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	if err := update.ValidateChannel(selfUpdateChannel); err != nil {
+		return err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the orbital binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate the orbital binary: %w", err)
+	}
+
+	ctx, cancel := setupSignalHandler()
+	defer cancel()
+	return selfUpdate(ctx, update.NewClient(), exe, selfUpdateChannel, selfUpdateCheck, cmd.OutOrStdout())
+}
+
+// selfUpdate replaces the binary at exe with the newest release on channel,
+// or with check only reports whether that would change anything.
+func selfUpdate(ctx context.Context, client *update.Client, exe, channel string, check bool, out io.Writer) error {
+	u, err := client.Find(ctx, channel, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	current, err := update.FileSHA256(exe)
+	if err != nil {
+		return fmt.Errorf("failed to read the orbital binary: %w", err)
+	}
+	if current == u.SHA256 {
+		_, _ = fmt.Fprintf(out, "orbital %s is up to date with %s (%s channel)\n", version, u.Release.TagName, channel)
+		return nil
+	}
+	if check {
+		_, _ = fmt.Fprintf(out, "Update available: %s (%s channel), installed %s. Run 'orbital self-update --channel %s' to install it.\n", u.Release.TagName, channel, version, channel)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Downloading %s from %s...\n", u.Asset.Name, u.Release.TagName)
+	data, err := client.Download(ctx, u)
+	if err != nil {
+		return err
+	}
+	if err := update.Install(exe, data); err != nil {
+		return fmt.Errorf("%w (is %s writable?)", err, filepath.Dir(exe))
+	}
+	_, _ = fmt.Fprintf(out, "Checksum verified. Updated %s to %s (%s channel).\n", exe, u.Release.TagName, channel)
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/update"
+)
+
+// releaseServer serves a stable release holding binary for this platform.
+func releaseServer(t *testing.T, binary string) *update.Client {
+	t.Helper()
+	name := update.AssetName(runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256([]byte(binary))
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(update.Release{TagName: "v9.9.9", Assets: []update.Asset{
+			{Name: name, URL: srv.URL + "/bin"},
+			{Name: update.ChecksumsAsset, URL: srv.URL + "/sums"},
+		}})
+	})
+	mux.HandleFunc("/bin", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, binary)
+	})
+	mux.HandleFunc("/sums", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &update.Client{HTTP: srv.Client(), APIURL: srv.URL, Repo: "o/r"}
+}
+
+func writeExe(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "orbital")
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readExe(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestSelfUpdate_Installs(t *testing.T) {
+	client := releaseServer(t, "new")
+	exe := writeExe(t, "old")
+
+	var out bytes.Buffer
+	if err := selfUpdate(context.Background(), client, exe, update.ChannelStable, false, &out); err != nil {
+		t.Fatalf("selfUpdate() error = %v", err)
+	}
+	if got := readExe(t, exe); got != "new" {
+		t.Errorf("binary = %q, want new", got)
+	}
+	if !strings.Contains(out.String(), "Updated") || !strings.Contains(out.String(), "v9.9.9") {
+		t.Errorf("output = %q, want update message", out.String())
+	}
+}
+
+func TestSelfUpdate_CheckOnly(t *testing.T) {
+	client := releaseServer(t, "new")
+	exe := writeExe(t, "old")
+
+	var out bytes.Buffer
+	if err := selfUpdate(context.Background(), client, exe, update.ChannelStable, true, &out); err != nil {
+		t.Fatalf("selfUpdate() error = %v", err)
+	}
+	if got := readExe(t, exe); got != "old" {
+		t.Errorf("binary = %q, want it untouched", got)
+	}
+	if !strings.Contains(out.String(), "Update available: v9.9.9") {
+		t.Errorf("output = %q, want update notice", out.String())
+	}
+}
+
+func TestSelfUpdate_UpToDate(t *testing.T) {
+	client := releaseServer(t, "same")
+	exe := writeExe(t, "same")
+
+	var out bytes.Buffer
+	if err := selfUpdate(context.Background(), client, exe, update.ChannelStable, false, &out); err != nil {
+		t.Fatalf("selfUpdate() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "up to date") {
+		t.Errorf("output = %q, want up to date", out.String())
+	}
+}
+
+func TestSelfUpdateCmd_InvalidChannel(t *testing.T) {
+	cmd := newSelfUpdateCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--channel", "nightly"})
+	defer func() { selfUpdateChannel = update.ChannelStable }()
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid channel") {
+		t.Errorf("Execute() error = %v, want invalid channel", err)
+	}
+}
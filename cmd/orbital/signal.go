@@ -5,24 +5,27 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+
+	"github.com/flashingpumpkin/orbital/internal/executor"
 )
 
 // setupSignalHandler creates a context that is cancelled when SIGINT or SIGTERM is received.
-// It returns the context and a cleanup function that should be deferred.
+// The signal is recorded as the context's cause so the executor can forward it to the
+// claude process group. It returns the context and a cleanup function that should be deferred.
 func setupSignalHandler() (context.Context, context.CancelFunc) {
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancelCause(context.Background())
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		select {
-		case <-sigChan:
-			cancel()
+		case sig := <-sigChan:
+			cancel(executor.SignalCause{Signal: sig})
 		case <-ctx.Done():
 		}
 		signal.Stop(sigChan)
 	}()
 
-	return ctx, cancel
+	return ctx, func() { cancel(nil) }
 }
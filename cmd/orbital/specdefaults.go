@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// applySpecDefaults seeds the run flags from the orbital key in the front
+// matter of the spec at path. Flags given on the command line win; a
+// front-matter workflow also beats the config file's. What was applied is
+// reported on out. A missing spec is left for spec validation to report.
+func applySpecDefaults(cmd *cobra.Command, path string, out io.Writer) error {
+	d, err := spec.LoadDefaults(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if d.IsZero() {
+		return nil
+	}
+	if d.Workflow != "" && !workflow.IsValidPreset(d.Workflow) {
+		return fmt.Errorf("invalid front matter in %s: unknown workflow preset %q", path, d.Workflow)
+	}
+
+	var applied []string
+	unset := func(flag string) bool { return !cmd.Flags().Changed(flag) }
+	if d.Workflow != "" && unset("workflow") {
+		workflowFlag = d.Workflow
+		applied = append(applied, "workflow "+d.Workflow)
+	}
+	if d.Model != "" && unset("model") {
+		model = d.Model
+		applied = append(applied, "model "+d.Model)
+	}
+	if d.CheckerModel != "" && unset("checker-model") {
+		checkerModel = d.CheckerModel
+		applied = append(applied, "checker model "+d.CheckerModel)
+	}
+	if d.Budget > 0 && unset("budget") {
+		budget = d.Budget
+		applied = append(applied, fmt.Sprintf("budget %g", d.Budget))
+	}
+	if d.Iterations > 0 && unset("iterations") {
+		iterations = d.Iterations
+		applied = append(applied, fmt.Sprintf("iterations %d", d.Iterations))
+	}
+	if d.MaxTurns > 0 && unset("max-turns") {
+		maxTurns = d.MaxTurns
+		applied = append(applied, fmt.Sprintf("max turns %d", d.MaxTurns))
+	}
+
+	if len(applied) > 0 && !quiet {
+		_, _ = fmt.Fprintf(out, "Spec front matter: %s\n", strings.Join(applied, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplySpecDefaults(t *testing.T) {
+	oldWorkflow, oldModel, oldChecker, oldBudget, oldIterations, oldMaxTurns, oldQuiet := workflowFlag, model, checkerModel, budget, iterations, maxTurns, quiet
+	defer func() {
+		workflowFlag, model, checkerModel, budget, iterations, maxTurns, quiet = oldWorkflow, oldModel, oldChecker, oldBudget, oldIterations, oldMaxTurns, oldQuiet
+	}()
+	quiet = false
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&workflowFlag, "workflow", "", "")
+		cmd.Flags().StringVar(&model, "model", "opus", "")
+		cmd.Flags().StringVar(&checkerModel, "checker-model", "haiku", "")
+		cmd.Flags().Float64Var(&budget, "budget", 100, "")
+		cmd.Flags().IntVar(&iterations, "iterations", 50, "")
+		cmd.Flags().IntVar(&maxTurns, "max-turns", 0, "")
+		return cmd
+	}
+	writeSpec := func(content string) string {
+		path := filepath.Join(t.TempDir(), "spec.md")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := writeSpec("---\norbital: {workflow: tdd, budget: 20, model: sonnet}\n---\n- [ ] task\n")
+	cmd := newCmd()
+	_ = cmd.Flags().Set("model", "opus")
+	var out bytes.Buffer
+	if err := applySpecDefaults(cmd, path, &out); err != nil {
+		t.Fatalf("applySpecDefaults() error = %v", err)
+	}
+	if workflowFlag != "tdd" || budget != 20 {
+		t.Errorf("workflow, budget = %q, %g, want front matter values", workflowFlag, budget)
+	}
+	if model != "opus" {
+		t.Errorf("model = %q, want the explicit flag to win", model)
+	}
+	if iterations != 50 {
+		t.Errorf("iterations = %d, want the default when front matter leaves it unset", iterations)
+	}
+	if got := out.String(); got != "Spec front matter: workflow tdd, budget 20\n" {
+		t.Errorf("output = %q", got)
+	}
+
+	path = writeSpec("---\norbital: {workflow: waterfall}\n---\n")
+	if err := applySpecDefaults(newCmd(), path, &out); err == nil || !strings.Contains(err.Error(), `unknown workflow preset "waterfall"`) {
+		t.Errorf("applySpecDefaults(waterfall) error = %v, want unknown preset", err)
+	}
+
+	if err := applySpecDefaults(newCmd(), filepath.Join(t.TempDir(), "missing.md"), &out); err != nil {
+		t.Errorf("applySpecDefaults(missing) error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = newStateCmd()
+
+func newStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect saved session state",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newStateShowCmd())
+	return cmd
+}
+
+func newStateShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the session state and check it for inconsistencies",
+		Long: `Show everything orbital has saved about the session in the working
+directory: its status, iteration, cost, workflow step, active, context and
+notes files, the queue, and the sessions in other git worktrees.
+
+The state is also checked: files it refers to that no longer exist, files
+both active and queued, a working directory that does not match, and
+workflow steps or checkpoints out of range are listed as issues.
+
+With --json, the full state and the issues are printed as JSON.`,
+		Example: `  orbital state show
+  orbital state show --json | jq .issues`,
+		Args: cobra.NoArgs,
+		RunE: runStateShow,
+	}
+	cmd.Flags().Bool("json", false, "Print the state and the issues found as JSON")
+	return cmd
+}
+
+// stateReport is what state show prints, and its JSON with --json.
+type stateReport struct {
+	Dir string `json:"dir"`
+	// Status is "running", "interrupted", "queued" (files waiting without a
+	// session), "unreadable" or "none".
+	Status      string           `json:"status"`
+	State       *state.State     `json:"state,omitempty"`
+	CurrentStep string           `json:"current_step,omitempty"`
+	Queue       []queuedFile     `json:"queue"`
+	Worktrees   []worktreeReport `json:"worktrees"`
+	Issues      []string         `json:"issues"`
+}
+
+// queuedFile is a file waiting in the queue.
+type queuedFile struct {
+	Path    string     `json:"path"`
+	AddedAt *time.Time `json:"added_at,omitempty"`
+}
+
+// worktreeReport is another git worktree of the repository and the session
+// saved in it, if any.
+type worktreeReport struct {
+	Path      string  `json:"path"`
+	Branch    string  `json:"branch,omitempty"`
+	Status    string  `json:"status"`
+	SessionID string  `json:"session_id,omitempty"`
+	Iteration int     `json:"iteration,omitempty"`
+	Cost      float64 `json:"cost,omitempty"`
+}
+
+func runStateShow(cmd *cobra.Command, args []string) error {
+	dir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	// Encrypted state needs the key before it is read
+	fileConfig, err := loadFileConfig(dir)
+	if err != nil {
+		return err
+	}
+	if err := setupEncryption(fileConfig); err != nil {
+		return err
+	}
+	if err := setupDisplay(fileConfig); err != nil {
+		return err
+	}
+
+	report := inspectState(dir)
+	out := cmd.OutOrStdout()
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal state: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+		return nil
+	}
+	printStateReport(out, report, time.Now())
+	return nil
+}
+
+// inspectState loads the state and queue saved in dir and the sessions of
+// the other worktrees, and checks them. Files that cannot be read are
+// reported as issues rather than errors.
+func inspectState(dir string) *stateReport {
+	r := &stateReport{Dir: dir, Status: "none", Queue: []queuedFile{}, Worktrees: []worktreeReport{}, Issues: []string{}}
+	issue := func(format string, a ...any) {
+		r.Issues = append(r.Issues, fmt.Sprintf(format, a...))
+	}
+
+	if state.Exists(dir) {
+		st, err := state.Load(dir)
+		if err != nil {
+			r.Status = "unreadable"
+			issue("%v", err)
+		} else {
+			r.State = st
+			r.Status = stateStatus(st)
+		}
+	}
+
+	queue, err := state.LoadQueue(state.StateDir(dir))
+	if err != nil {
+		issue("%v", err)
+	} else {
+		for _, f := range queue.QueuedFiles {
+			q := queuedFile{Path: f}
+			if at, ok := queue.AddedAt[f]; ok {
+				q.AddedAt = &at
+			}
+			r.Queue = append(r.Queue, q)
+			if !pathExists(dir, f) {
+				issue("queued file %s does not exist", f)
+			}
+		}
+		if r.Status == "none" && len(r.Queue) > 0 {
+			r.Status = "queued"
+		}
+	}
+
+	if st := r.State; st != nil {
+		if st.WorkingDir != "" && !sameDir(st.WorkingDir, dir) {
+			issue("state records working directory %s but is saved in %s", st.WorkingDir, dir)
+		}
+		for _, f := range st.ActiveFiles {
+			if !pathExists(dir, f) {
+				issue("active file %s does not exist", f)
+			}
+			for _, q := range r.Queue {
+				if q.Path == f {
+					issue("%s is both active and queued", f)
+				}
+			}
+		}
+		for _, f := range st.ContextFiles {
+			if !pathExists(dir, f) {
+				issue("context file %s does not exist", f)
+			}
+		}
+		if st.NotesFile != "" && !pathExists(dir, st.NotesFile) {
+			issue("notes file %s does not exist", st.NotesFile)
+		}
+		if w := st.Workflow; w != nil {
+			if w.CurrentStepIndex < 0 || w.CurrentStepIndex >= len(w.Steps) {
+				issue("workflow step index %d is out of range (%d steps)", w.CurrentStepIndex, len(w.Steps))
+			} else {
+				r.CurrentStep = w.Steps[w.CurrentStepIndex].Name
+			}
+		}
+		for _, cp := range st.Checkpoints {
+			if cp.Iteration > st.Iteration {
+				issue("checkpoint %q is at iteration %d, after the current iteration %d", cp.Name, cp.Iteration, st.Iteration)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	trees, _ := git.Worktrees(ctx, dir)
+	for _, wt := range trees {
+		if sameDir(wt.Path, dir) {
+			continue
+		}
+		w := worktreeReport{Path: wt.Path, Branch: wt.Branch, Status: "none"}
+		if state.Exists(wt.Path) {
+			st, err := state.Load(wt.Path)
+			if err != nil {
+				w.Status = "unreadable"
+				issue("worktree %s: %v", wt.Path, err)
+			} else {
+				w.Status = stateStatus(st)
+				w.SessionID = st.SessionID
+				w.Iteration = st.Iteration
+				w.Cost = st.TotalCost
+			}
+		}
+		r.Worktrees = append(r.Worktrees, w)
+	}
+	return r
+}
+
+// stateStatus is "running" while the session's process is alive and
+// "interrupted" once it is gone.
+func stateStatus(st *state.State) string {
+	if st.IsStale() {
+		return "interrupted"
+	}
+	return "running"
+}
+
+// pathExists reports whether path, relative to dir unless absolute, exists.
+func pathExists(dir, path string) bool {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// sameDir reports whether a and b name the same directory.
+func sameDir(a, b string) bool {
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// printStateReport prints r for reading, marking missing files.
+func printStateReport(out io.Writer, r *stateReport, now time.Time) {
+	_, _ = fmt.Fprintln(out, "Orbital State")
+	_, _ = fmt.Fprintln(out, "=============")
+	_, _ = fmt.Fprintf(out, "Directory:  %s\n", r.Dir)
+	status := r.Status
+	if status == "interrupted" {
+		status += " (run 'orbital continue' to resume)"
+	}
+	_, _ = fmt.Fprintf(out, "Status:     %s\n", status)
+
+	if st := r.State; st != nil {
+		_, _ = fmt.Fprintf(out, "Session:    %s\n", st.SessionID)
+		_, _ = fmt.Fprintf(out, "PID:        %d\n", st.PID)
+		_, _ = fmt.Fprintf(out, "Iteration:  %d\n", st.Iteration)
+		_, _ = fmt.Fprintf(out, "Cost:       %s %s\n", util.FormatCost(st.TotalCost, 2), util.CurrencyCode())
+		_, _ = fmt.Fprintf(out, "Started:    %s\n", st.StartedAt.Format("2006-01-02 15:04:05"))
+		if w := st.Workflow; w != nil {
+			name := w.PresetName
+			if name == "" {
+				name = "custom"
+			}
+			step := fmt.Sprintf("step %d of %d", w.CurrentStepIndex+1, len(w.Steps))
+			if r.CurrentStep != "" {
+				step += " (" + r.CurrentStep + ")"
+			}
+			_, _ = fmt.Fprintf(out, "Workflow:   %s, %s\n", name, step)
+		}
+		if st.NotesFile != "" {
+			_, _ = fmt.Fprintf(out, "Notes:      %s%s\n", st.NotesFile, missingMark(r.Dir, st.NotesFile))
+		}
+		printStateFiles(out, r.Dir, "Active Files", st.ActiveFiles)
+		printStateFiles(out, r.Dir, "Context Files", st.ContextFiles)
+	}
+
+	_, _ = fmt.Fprintln(out)
+	if len(r.Queue) == 0 {
+		_, _ = fmt.Fprintln(out, "Queue: (empty)")
+	} else {
+		_, _ = fmt.Fprintln(out, "Queue:")
+		for _, q := range r.Queue {
+			added := ""
+			if q.AddedAt != nil {
+				added = fmt.Sprintf(" (added %s ago)", formatDuration(now.Sub(*q.AddedAt)))
+			}
+			_, _ = fmt.Fprintf(out, "  - %s%s%s\n", q.Path, added, missingMark(r.Dir, q.Path))
+		}
+	}
+
+	if len(r.Worktrees) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "Worktrees:")
+		for _, w := range r.Worktrees {
+			name := w.Path
+			if w.Branch != "" {
+				name += " [" + w.Branch + "]"
+			}
+			if w.SessionID == "" {
+				_, _ = fmt.Fprintf(out, "  - %s: %s\n", name, w.Status)
+				continue
+			}
+			_, _ = fmt.Fprintf(out, "  - %s: %s, session %s, iteration %d, %s\n",
+				name, w.Status, w.SessionID, w.Iteration, util.FormatCost(w.Cost, 2))
+		}
+	}
+
+	_, _ = fmt.Fprintln(out)
+	if len(r.Issues) == 0 {
+		_, _ = fmt.Fprintln(out, "No issues found")
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Issues (%d):\n", len(r.Issues))
+	for _, issue := range r.Issues {
+		_, _ = fmt.Fprintf(out, "  ✗ %s\n", issue)
+	}
+}
+
+// printStateFiles prints a titled list of files, marking missing ones.
+func printStateFiles(out io.Writer, dir, title string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintf(out, "%s:\n", title)
+	for _, f := range files {
+		_, _ = fmt.Fprintf(out, "  - %s%s\n", f, missingMark(dir, f))
+	}
+}
+
+// missingMark returns " (missing)" when path does not exist.
+func missingMark(dir, path string) string {
+	if pathExists(dir, path) {
+		return ""
+	}
+	return " (missing)"
+}
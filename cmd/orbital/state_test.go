@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+func TestInspectState_NoSession(t *testing.T) {
+	r := inspectState(t.TempDir())
+	if r.Status != "none" || r.State != nil || len(r.Queue) != 0 || len(r.Issues) != 0 {
+		t.Errorf("inspectState() = %+v, want an empty report", r)
+	}
+}
+
+func TestInspectState_FlagsInconsistencies(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.md")
+	if err := os.WriteFile(spec, []byte("- [ ] item\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	st := state.NewState("session-123", dir, []string{spec, filepath.Join(dir, "gone.md")}, "docs/notes.md", []string{"CONTEXT.md"})
+	st.PID = 99999999 // not running
+	st.Iteration = 3
+	st.Checkpoints = []state.Checkpoint{{Name: "future", Iteration: 5}}
+	st.Workflow = &state.WorkflowState{PresetName: "tdd", Steps: []workflow.Step{{Name: "red"}}, CurrentStepIndex: 2}
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+	queue, err := state.LoadQueue(state.StateDir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Add(spec); err != nil {
+		t.Fatal(err)
+	}
+
+	r := inspectState(dir)
+	if r.Status != "interrupted" || r.State == nil || r.State.Iteration != 3 {
+		t.Fatalf("inspectState() status = %q, state = %+v", r.Status, r.State)
+	}
+	if len(r.Queue) != 1 || r.Queue[0].Path != spec || r.Queue[0].AddedAt == nil {
+		t.Errorf("Queue = %+v, want %s with its added time", r.Queue, spec)
+	}
+
+	want := []string{
+		spec + " is both active and queued",
+		"active file " + filepath.Join(dir, "gone.md") + " does not exist",
+		"context file CONTEXT.md does not exist",
+		"notes file docs/notes.md does not exist",
+		"workflow step index 2 is out of range (1 steps)",
+		`checkpoint "future" is at iteration 5, after the current iteration 3`,
+	}
+	if strings.Join(r.Issues, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Issues =\n%s\nwant\n%s", strings.Join(r.Issues, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestInspectState_UnreadableState(t *testing.T) {
+	dir := t.TempDir()
+	stateDir := state.StateDir(dir)
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir, "state.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := inspectState(dir)
+	if r.Status != "unreadable" || len(r.Issues) != 1 || !strings.Contains(r.Issues[0], "failed to unmarshal state") {
+		t.Errorf("inspectState() status = %q, issues = %v", r.Status, r.Issues)
+	}
+}
+
+func TestPrintStateReport(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	added := now.Add(-5 * time.Minute)
+	dir := t.TempDir()
+	r := &stateReport{
+		Dir:    dir,
+		Status: "interrupted",
+		State: &state.State{
+			SessionID:   "abc",
+			Iteration:   4,
+			TotalCost:   1.5,
+			ActiveFiles: []string{"missing.md"},
+			Workflow:    &state.WorkflowState{PresetName: "tdd", Steps: []workflow.Step{{Name: "red"}, {Name: "green"}}, CurrentStepIndex: 1},
+		},
+		CurrentStep: "green",
+		Queue:       []queuedFile{{Path: "next.md", AddedAt: &added}},
+		Worktrees: []worktreeReport{
+			{Path: "/src/wt", Branch: "feature", Status: "running", SessionID: "def", Iteration: 2, Cost: 0.25},
+			{Path: "/src/other", Status: "none"},
+		},
+		Issues: []string{"active file missing.md does not exist"},
+	}
+
+	var buf bytes.Buffer
+	printStateReport(&buf, r, now)
+	out := buf.String()
+	for _, want := range []string{
+		"Status:     interrupted (run 'orbital continue' to resume)",
+		"Iteration:  4",
+		"Workflow:   tdd, step 2 of 2 (green)",
+		"Active Files:\n  - missing.md (missing)",
+		"  - next.md (added 5m ago) (missing)",
+		"  - /src/wt [feature]: running, session def, iteration 2, $0.25",
+		"  - /src/other: none",
+		"Issues (1):\n  ✗ active file missing.md does not exist",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStateShowCmd_JSON(t *testing.T) {
+	dir := t.TempDir()
+	oldDir := workingDir
+	workingDir = dir
+	defer func() { workingDir = oldDir }()
+
+	st := state.NewState("session-json", dir, nil, "", nil)
+	st.PID = 99999999
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newStateShowCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var got struct {
+		Status string `json:"status"`
+		State  struct {
+			SessionID string `json:"session_id"`
+		} `json:"state"`
+		Queue  []queuedFile `json:"queue"`
+		Issues []string     `json:"issues"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if got.Status != "interrupted" || got.State.SessionID != "session-json" || got.Queue == nil || got.Issues == nil {
+		t.Errorf("JSON = %+v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = newStatsCmd()
+
+func newStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show local usage statistics",
+		Long: `Show usage statistics aggregated from past orbital runs.
+
+Statistics are opt-in and strictly local. Enable them in .orbital/config.toml:
+
+    [stats]
+    enabled = true
+
+Each finished run then adds an anonymous sample (workflow, model, iterations,
+cost, budget) to .orbital/stats.json. Nothing is sent anywhere.
+
+Reported: runs per week, average iterations to completion, average budget
+utilisation, and a per-workflow breakdown.`,
+		Args: cobra.NoArgs,
+		RunE: runStats,
+	}
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if err := loadDisplay(workingDir); err != nil {
+		return err
+	}
+	stats, err := telemetry.Load(workingDir)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(stats.Samples) == 0 {
+		_, _ = fmt.Fprintln(out, "No usage statistics recorded")
+		_, _ = fmt.Fprintln(out, "")
+		_, _ = fmt.Fprintln(out, "Enable with [stats] enabled = true in .orbital/config.toml")
+		return nil
+	}
+
+	return stats.Summarise(time.Now()).Render(out)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/telemetry"
+)
+
+func TestStatsCmd_NoStats(t *testing.T) {
+	chdirTemp(t)
+
+	cmd := newStatsCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No usage statistics recorded") {
+		t.Errorf("output = %q, want empty-state message", buf.String())
+	}
+}
+
+func TestStatsCmd_RendersSummary(t *testing.T) {
+	chdirTemp(t)
+	sample := telemetry.Sample{FinishedAt: time.Now(), Workflow: "tdd", Iterations: 4, Completed: true, Cost: 2, Budget: 10}
+	if err := telemetry.Record(".", sample); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	cmd := newStatsCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, want := range []string{"Runs per week:", "Avg iterations to completion:", "tdd"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -3,10 +3,14 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/flashingpumpkin/orbital/internal/output"
 	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
 var statusCmd = &cobra.Command{
@@ -50,6 +54,10 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
+	if err := loadDisplay(workingDir); err != nil {
+		return err
+	}
+
 	out := cmd.OutOrStdout()
 	stateDir := state.StateDir(workingDir)
 
@@ -74,6 +82,13 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if !hasState && !hasQueue {
 		_, _ = fmt.Fprintln(out, "No orbital session in this directory")
 		_, _ = fmt.Fprintln(out, "")
+		if last, err := state.LatestArchive(workingDir); err == nil && last != nil {
+			_, _ = fmt.Fprintf(out, "Last completed: %s (%d iterations, %s %s, started %s)\n",
+				last.SessionID, last.Iteration, util.FormatCost(last.TotalCost, 2), util.CurrencyCode(),
+				last.StartedAt.Format("2006-01-02 15:04"))
+			_, _ = fmt.Fprintf(out, "Archived in:    %s\n", filepath.Join(state.ArchiveDir(workingDir), last.SessionID))
+			_, _ = fmt.Fprintln(out, "")
+		}
 		_, _ = fmt.Fprintln(out, "Start with: orbital <spec-file>")
 		return nil
 	}
@@ -83,7 +98,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	_, _ = fmt.Fprintln(out, "=============")
 
 	// Print status indicator
-	if isRunning {
+	if isRunning && st.OfflineSince != nil {
+		_, _ = fmt.Fprintf(out, "Status:     PAUSED: offline since %s, resumes when the network is back\n", st.OfflineSince.Format("15:04:05"))
+	} else if isRunning {
 		_, _ = fmt.Fprintln(out, "Status:     RUNNING")
 	} else if hasState {
 		_, _ = fmt.Fprintln(out, "Status:     STOPPED (run 'orbital continue' to resume)")
@@ -96,8 +113,20 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		_, _ = fmt.Fprintf(out, "PID:        %d\n", st.PID)
 		_, _ = fmt.Fprintf(out, "Session:    %s\n", st.SessionID)
 		_, _ = fmt.Fprintf(out, "Iteration:  %d\n", st.Iteration)
-		_, _ = fmt.Fprintf(out, "Cost:       $%.2f USD\n", st.TotalCost)
+		_, _ = fmt.Fprintf(out, "Cost:       %s %s\n", util.FormatCost(st.TotalCost, 2), util.CurrencyCode())
+		if resources := output.FormatResources(st.CPUTime, st.PeakRSS); resources != "" {
+			_, _ = fmt.Fprintf(out, "Resources:  %s\n", resources)
+		}
 		_, _ = fmt.Fprintf(out, "Started:    %s\n", st.StartedAt.Format("2006-01-02 15:04:05"))
+		if st.Environment != nil {
+			_, _ = fmt.Fprintf(out, "Env:        %s\n", st.Environment)
+		}
+		if len(st.ModeSwitches) > 0 {
+			_, _ = fmt.Fprintf(out, "Phases:     %s\n", formatModeSwitches(st.ModeSwitches))
+		}
+		if st.Unconstrained {
+			_, _ = fmt.Fprintln(out, "Mode:       UNCONSTRAINED (--dangerous, no permission prompts)")
+		}
 	}
 	_, _ = fmt.Fprintln(out)
 
@@ -110,6 +139,15 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		_, _ = fmt.Fprintln(out)
 	}
 
+	// Print checkpoints, which rollback --to-checkpoint and continue --at accept
+	if hasState && len(st.Checkpoints) > 0 {
+		_, _ = fmt.Fprintln(out, "Checkpoints:")
+		for _, cp := range st.Checkpoints {
+			_, _ = fmt.Fprintf(out, "  - %s (iteration %d)\n", cp.Name, cp.Iteration)
+		}
+		_, _ = fmt.Fprintln(out)
+	}
+
 	// Print queued files
 	if hasQueue {
 		_, _ = fmt.Fprintln(out, "Queued Files:")
@@ -142,3 +180,13 @@ func formatDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%dd", int(d.Hours()/24))
 }
+
+// formatModeSwitches describes the session's phases, e.g. "burst on haiku
+// from iteration 1, main on opus from iteration 4".
+func formatModeSwitches(switches []state.ModeSwitch) string {
+	parts := make([]string, len(switches))
+	for i, m := range switches {
+		parts[i] = fmt.Sprintf("%s on %s from iteration %d", m.Mode, m.Model, m.Iteration)
+	}
+	return strings.Join(parts, ", ")
+}
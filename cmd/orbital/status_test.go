@@ -3,11 +3,13 @@ package main
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/flashingpumpkin/orbital/internal/state"
+	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
 func TestStatusCmd_ShowsNoSessionWhenNoState(t *testing.T) {
@@ -41,6 +43,49 @@ func TestStatusCmd_ShowsNoSessionWhenNoState(t *testing.T) {
 	}
 }
 
+func TestStatusCmd_ShowsLastArchivedSession(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalWd); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	st := state.NewState("done-session", tempDir, []string{"spec.md"}, "", nil)
+	st.UpdateIteration(7, 3.5)
+	if err := st.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := st.Archive(10); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	cmd := newStatusCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"No orbital session in this directory",
+		"Last completed: done-session (7 iterations, " + util.FormatCost(3.5, 2),
+		filepath.Join(".orbital", "archive", "done-session"),
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output = %q; want to contain %q", output, want)
+		}
+	}
+}
+
 func TestStatusCmd_ShowsStoppedStateMessage(t *testing.T) {
 	tempDir := t.TempDir()
 	originalWd, err := os.Getwd()
@@ -98,6 +143,10 @@ func TestStatusCmd_ShowsRunningInstanceStatus(t *testing.T) {
 	st := state.NewState("session-abc123", tempDir, []string{"/path/spec1.md", "/path/spec2.md"}, "", nil)
 	st.Iteration = 5
 	st.TotalCost = 1.23
+	st.RecordResources(90*time.Second, 512<<20)
+	st.RecordCheckpoint("tests green", 4)
+	st.RecordModeSwitch(1, state.ModeBurst, "haiku")
+	st.RecordModeSwitch(3, state.ModeMain, "opus")
 	st.StartedAt = time.Date(2026, 1, 18, 10, 0, 0, 0, time.UTC)
 	if err := st.Save(); err != nil {
 		t.Fatalf("failed to save state: %v", err)
@@ -120,9 +169,12 @@ func TestStatusCmd_ShowsRunningInstanceStatus(t *testing.T) {
 		"session-abc123",
 		"5", // Iteration
 		"$1.23", // Cost
+		"Resources:  CPU 1m30s, peak RSS 512.0 MB",
 		"/path/spec1.md",
 		"/path/spec2.md",
 		"Active Files:",
+		"Checkpoints:\n  - tests green (iteration 4)",
+		"Phases:     burst on haiku from iteration 1, main on opus from iteration 3",
 	}
 
 	for _, check := range checks {
@@ -132,6 +184,41 @@ func TestStatusCmd_ShowsRunningInstanceStatus(t *testing.T) {
 	}
 }
 
+func TestStatusCmd_ShowsOfflinePause(t *testing.T) {
+	tempDir := t.TempDir()
+	originalWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	defer func() {
+		if err := os.Chdir(originalWd); err != nil {
+			t.Errorf("failed to restore working directory: %v", err)
+		}
+	}()
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	st := state.NewState("session-abc123", tempDir, []string{"/path/spec.md"}, "", nil)
+	since := time.Date(2026, 1, 18, 10, 30, 0, 0, time.Local)
+	st.OfflineSince = &since
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "Status:     PAUSED: offline since 10:30:00, resumes when the network is back"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q\nfull output: %s", want, buf.String())
+	}
+}
+
 func TestStatusCmd_ShowsQueuedFiles(t *testing.T) {
 	tempDir := t.TempDir()
 	originalWd, err := os.Getwd()
@@ -226,3 +313,31 @@ func TestStatusCmd_ShowsNoQueuedFilesMessage(t *testing.T) {
 		t.Errorf("output = %q; want to contain %q", output, expected)
 	}
 }
+
+func TestStatusCmd_DisplayCurrency(t *testing.T) {
+	dir := chdirTemp(t)
+	if err := os.MkdirAll(filepath.Join(dir, ".orbital"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "[display]\ncurrency = \"EUR\"\nlocale = \"de-DE\"\nrate = 0.5\n"
+	if err := os.WriteFile(filepath.Join(dir, ".orbital", "config.toml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = util.SetDisplay("", "", 0) })
+
+	st := state.NewState("session-abc123", dir, []string{"/path/spec1.md"}, "", nil)
+	st.TotalCost = 2469
+	if err := st.Save(); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	cmd := newStatusCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "Cost:       1.234,50 € EUR"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q\nfull output: %s", want, buf.String())
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/tasks"
+	"github.com/flashingpumpkin/orbital/internal/tui"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
+)
+
+// codeTodoScanner finds TODO(orbital) comments in the files changed since
+// the run started, so work the agent defers in code shows up in the task
+// panel and the spec instead of being silently lost.
+type codeTodoScanner struct {
+	repo     vcs.Backend
+	dir      string
+	from     string
+	specFile string
+	// skip holds the spec and notes files, relative to dir, which may
+	// mention the marker without deferring anything.
+	skip map[string]bool
+}
+
+// newCodeTodoScanner returns a scanner for the files in dir changed since
+// revision from when [todos] scan is on, or nil. Scanning needs version
+// control to know which files changed.
+func newCodeTodoScanner(fileConfig *config.FileConfig, repo vcs.Backend, dir, from string, specFiles []string, notesFile string) *codeTodoScanner {
+	if fileConfig == nil || fileConfig.Todos == nil || !fileConfig.Todos.Scan || len(specFiles) == 0 {
+		return nil
+	}
+	if from == "" {
+		fmt.Fprintln(os.Stderr, "Warning: [todos] scan needs a version-controlled working directory with at least one commit; TODO(orbital) scanning is disabled")
+		return nil
+	}
+	s := &codeTodoScanner{repo: repo, dir: dir, from: from, specFile: specFiles[0], skip: make(map[string]bool)}
+	for _, path := range append([]string{notesFile}, specFiles...) {
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				continue
+			}
+			path = rel
+		}
+		s.skip[filepath.ToSlash(path)] = true
+	}
+	return s
+}
+
+// files returns the changed files worth scanning.
+func (s *codeTodoScanner) files(ctx context.Context) ([]string, error) {
+	changed, err := s.repo.ChangedFiles(ctx, s.from)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range changed {
+		if s.skip[file] || strings.HasPrefix(file, ".orbital/") {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// scan lists the TODO(orbital) comments in the changed files in the task
+// panel and adds the ones not yet in the spec as unchecked items. Failures
+// are reported without interrupting the loop.
+func (s *codeTodoScanner) scan(ctx context.Context, tuiProgram *tui.Program) {
+	if s == nil {
+		return
+	}
+	files, err := s.files(ctx)
+	if err != nil {
+		reportTodoScanError(err, tuiProgram)
+		return
+	}
+	todos, err := tasks.ScanCodeTodos(s.dir, files)
+	if err != nil {
+		reportTodoScanError(err, tuiProgram)
+		return
+	}
+	if tuiProgram != nil {
+		tuiProgram.SendCodeTodos(todos)
+	}
+
+	items := make([]string, len(todos))
+	for i, todo := range todos {
+		items[i] = fmt.Sprintf("%s (%s)", todo.Text, todo.File)
+	}
+	added, err := spec.AppendDeferred(s.specFile, items)
+	if err != nil {
+		reportTodoScanError(err, tuiProgram)
+		return
+	}
+	for _, item := range added {
+		msg := "Deferred in code, added to the spec: " + item
+		if tuiProgram != nil {
+			tuiProgram.SendOutput("📌 " + msg)
+		} else {
+			fmt.Printf("%s\n", msg)
+		}
+	}
+}
+
+// reportTodoScanError surfaces a TODO scan failure without interrupting the
+// loop.
+func reportTodoScanError(err error, tuiProgram *tui.Program) {
+	msg := fmt.Sprintf("TODO(orbital) scan failed: %v", err)
+	if tuiProgram != nil {
+		tuiProgram.SendOutput("⚠ " + msg)
+	} else {
+		fmt.Fprintln(os.Stderr, "Warning: "+msg)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/git"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
+)
+
+func TestNewCodeTodoScanner_Disabled(t *testing.T) {
+	repo := vcs.None()
+	specs := []string{"/work/spec.md"}
+	tests := []struct {
+		name       string
+		fileConfig *config.FileConfig
+		from       string
+	}{
+		{name: "no config", from: "abc"},
+		{name: "scan off", fileConfig: &config.FileConfig{Todos: &config.TodosConfig{}}, from: "abc"},
+		{name: "no start revision", fileConfig: &config.FileConfig{Todos: &config.TodosConfig{Scan: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s := newCodeTodoScanner(tt.fileConfig, repo, "/work", tt.from, specs, ".orbital/notes.md"); s != nil {
+				t.Errorf("newCodeTodoScanner() = %+v, want nil", s)
+			}
+		})
+	}
+}
+
+func TestCodeTodoScanner_Scan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.md")
+	if err := os.WriteFile(specPath, []byte("# Spec\n\n- [ ] Build it\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "init"},
+	} {
+		if _, err := git.Run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := vcs.Git(dir)
+	head, err := repo.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileConfig := &config.FileConfig{Todos: &config.TodosConfig{Scan: true}}
+	s := newCodeTodoScanner(fileConfig, repo, dir, head, []string{specPath}, ".orbital/notes.md")
+	if s == nil {
+		t.Fatal("newCodeTodoScanner() = nil")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\n// TODO(orbital): handle errors\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The spec mentions the marker too, but is not scanned
+	if err := os.WriteFile(specPath, []byte("# Spec\n\n- [ ] Build it\n\nDefer with TODO(orbital): comments.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.scan(ctx, nil)
+	s.scan(ctx, nil)
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "- [ ] "); got != 2 {
+		t.Errorf("spec has %d unchecked items, want 2:\n%s", got, data)
+	}
+	if !strings.Contains(string(data), "- [ ] handle errors (main.go)") {
+		t.Errorf("spec is missing the deferred item:\n%s", data)
+	}
+}
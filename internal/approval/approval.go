@@ -0,0 +1,157 @@
+// Package approval passes decisions on human gates to a running session. The
+// session marks the gate it waits at in .orbital/approvals/<session>/, and a
+// decision written next to the mark (by `orbital approve` or the TUI) lets it
+// continue.
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotWaiting is returned by Decide when the session is not waiting at the
+// gate.
+var ErrNotWaiting = errors.New("not waiting for approval")
+
+// pollInterval is how often Wait checks for a decision.
+var pollInterval = time.Second
+
+// Decision is a person's verdict on a gate.
+type Decision struct {
+	// Approved passes the gate; otherwise it fails.
+	Approved bool `json:"approved"`
+
+	// By names who decided, if known.
+	By string `json:"by,omitempty"`
+
+	// Comment is the reason given, if any.
+	Comment string `json:"comment,omitempty"`
+
+	// At is when the decision was made.
+	At time.Time `json:"at"`
+}
+
+// Gate is a gate a session is waiting at.
+type Gate struct {
+	Session string    `json:"session"`
+	Step    string    `json:"step"`
+	Since   time.Time `json:"since"`
+}
+
+// Dir returns the directory the approvals of the project in workingDir are
+// kept in.
+func Dir(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "approvals")
+}
+
+// paths returns the files marking that session waits at step and holding the
+// decision. Step names are escaped to keep them to one path element.
+func paths(workingDir, session, step string) (waiting, decision string) {
+	base := filepath.Join(Dir(workingDir), session, url.PathEscape(step))
+	return base + ".waiting", base + ".json"
+}
+
+// Request marks session as waiting at step, clearing any earlier decision.
+func Request(workingDir, session, step string) error {
+	waiting, decision := paths(workingDir, session, step)
+	if err := os.MkdirAll(filepath.Dir(waiting), 0755); err != nil {
+		return fmt.Errorf("failed to create approvals directory: %w", err)
+	}
+	_ = os.Remove(decision)
+	data, err := json.Marshal(Gate{Session: session, Step: step, Since: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal approval request: %w", err)
+	}
+	if err := os.WriteFile(waiting, data, 0644); err != nil {
+		return fmt.Errorf("failed to request approval: %w", err)
+	}
+	return nil
+}
+
+// Decide records d for the gate session is waiting at. It returns
+// ErrNotWaiting when the session is not waiting there.
+func Decide(workingDir, session, step string, d Decision) error {
+	waiting, decision := paths(workingDir, session, step)
+	if _, err := os.Stat(waiting); err != nil {
+		return fmt.Errorf("session %s is %w at step %q", session, ErrNotWaiting, step)
+	}
+	if d.At.IsZero() {
+		d.At = time.Now()
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision: %w", err)
+	}
+	// Write then rename so Wait never reads half a decision
+	tmp := decision + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to record decision: %w", err)
+	}
+	if err := os.Rename(tmp, decision); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to record decision: %w", err)
+	}
+	return nil
+}
+
+// Wait blocks until a decision is recorded for the gate session is waiting
+// at, or ctx ends. Either way the gate is no longer marked as waiting.
+func Wait(ctx context.Context, workingDir, session, step string) (*Decision, error) {
+	waiting, decision := paths(workingDir, session, step)
+	defer func() {
+		_ = os.Remove(waiting)
+		_ = os.Remove(decision)
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		data, err := os.ReadFile(decision)
+		if err == nil {
+			var d Decision
+			if err := json.Unmarshal(data, &d); err != nil {
+				return nil, fmt.Errorf("failed to parse decision: %w", err)
+			}
+			return &d, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read decision: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Waiting returns the gates sessions of the project in workingDir are
+// waiting at, longest waiting first.
+func Waiting(workingDir string) ([]Gate, error) {
+	matches, err := filepath.Glob(filepath.Join(Dir(workingDir), "*", "*.waiting"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	var gates []Gate
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var g Gate
+		if err := json.Unmarshal(data, &g); err != nil || strings.TrimSpace(g.Step) == "" {
+			continue
+		}
+		gates = append(gates, g)
+	}
+	sort.SliceStable(gates, func(i, j int) bool { return gates[i].Since.Before(gates[j].Since) })
+	return gates, nil
+}
@@ -0,0 +1,77 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDecideAndWait(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	dir := t.TempDir()
+
+	if err := Decide(dir, "abc", "sign-off", Decision{Approved: true}); !errors.Is(err, ErrNotWaiting) {
+		t.Fatalf("Decide() before Request() error = %v, want ErrNotWaiting", err)
+	}
+	if err := Request(dir, "abc", "sign-off/prod"); err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	gates, err := Waiting(dir)
+	if err != nil || len(gates) != 1 || gates[0].Session != "abc" || gates[0].Step != "sign-off/prod" {
+		t.Fatalf("Waiting() = %+v, %v, want the requested gate", gates, err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = Decide(dir, "abc", "sign-off/prod", Decision{Approved: false, By: "sam", Comment: "Not on a Friday"})
+	}()
+	d, err := Wait(context.Background(), dir, "abc", "sign-off/prod")
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if d.Approved || d.By != "sam" || d.Comment != "Not on a Friday" || d.At.IsZero() {
+		t.Errorf("Wait() = %+v, want the rejection", d)
+	}
+	if gates, _ := Waiting(dir); len(gates) != 0 {
+		t.Errorf("Waiting() after the decision = %+v, want none", gates)
+	}
+}
+
+func TestWait_ContextEnds(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	dir := t.TempDir()
+	if err := Request(dir, "abc", "sign-off"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := Wait(ctx, dir, "abc", "sign-off"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want DeadlineExceeded", err)
+	}
+	waiting, _ := paths(dir, "abc", "sign-off")
+	if _, err := os.Stat(waiting); !os.IsNotExist(err) {
+		t.Errorf("gate still marked waiting: %v", err)
+	}
+}
+
+func TestRequest_ClearsEarlierDecision(t *testing.T) {
+	pollInterval = 5 * time.Millisecond
+	dir := t.TempDir()
+	if err := Request(dir, "abc", "sign-off"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Decide(dir, "abc", "sign-off", Decision{Approved: true}); err != nil {
+		t.Fatal(err)
+	}
+	// A stale decision does not answer a new request
+	if err := Request(dir, "abc", "sign-off"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if d, err := Wait(ctx, dir, "abc", "sign-off"); err == nil {
+		t.Errorf("Wait() = %+v, want no decision", d)
+	}
+}
@@ -0,0 +1,236 @@
+// Package audit keeps a trail of the shell commands the agent runs. Commands
+// are taken from the Bash tool uses in claude's stream-json output and
+// appended, once their result arrives, to a per-session JSON lines file.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/output"
+)
+
+// Command statuses.
+const (
+	// StatusOK is a command the tool reported as succeeding.
+	StatusOK = "ok"
+	// StatusFailed is a command the tool reported as failing.
+	StatusFailed = "failed"
+	// StatusUnknown is a command whose result never arrived, for example
+	// because claude was stopped while it ran.
+	StatusUnknown = "unknown"
+)
+
+// exitCodePattern finds the exit status in a failed Bash tool result.
+var exitCodePattern = regexp.MustCompile(`(?i)exit code (\d+)`)
+
+// Command is one shell command the agent ran.
+type Command struct {
+	// Command is the command line, as redacted in claude's output.
+	Command string `json:"command"`
+
+	// Description is the agent's own description of the command, if given.
+	Description string `json:"description,omitempty"`
+
+	// Cwd is the directory claude was started in. Commands that change
+	// directory themselves are recorded as written.
+	Cwd string `json:"cwd,omitempty"`
+
+	// Background is set for commands the agent started in the background.
+	Background bool `json:"background,omitempty"`
+
+	// StartedAt is when the tool use was seen in the output.
+	StartedAt time.Time `json:"started_at"`
+
+	// Duration is the time from the tool use to its result.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Status is StatusOK, StatusFailed or StatusUnknown.
+	Status string `json:"status"`
+
+	// ExitCode is the command's exit status when it is known: 0 for
+	// commands that succeeded, and the code in the error for those that
+	// failed with one.
+	ExitCode *int `json:"exit_code,omitempty"`
+}
+
+// bashInput is the input of a Bash tool use.
+type bashInput struct {
+	Command         string `json:"command"`
+	Description     string `json:"description"`
+	RunInBackground bool   `json:"run_in_background"`
+}
+
+// Recorder is an io.Writer taking claude's stream-json output and appending
+// the Bash commands in it to the audit trail.
+type Recorder struct {
+	mu      sync.Mutex
+	w       io.WriteCloser
+	parser  *output.Parser
+	cwd     string
+	partial string
+	pending map[string]*Command
+	order   []string
+	seen    map[string]bool
+	now     func() time.Time
+}
+
+// Open opens the audit trail at path for appending, encrypted when
+// encryption is enabled. cwd is recorded as the commands' directory.
+func Open(path, cwd string) (*Recorder, error) {
+	w, err := encrypt.OpenAppend(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit trail: %w", err)
+	}
+	return &Recorder{
+		w:       w,
+		parser:  output.NewParser(),
+		cwd:     cwd,
+		pending: make(map[string]*Command),
+		seen:    make(map[string]bool),
+		now:     time.Now,
+	}, nil
+}
+
+// Write records the Bash tool uses in stream-json output. Partial lines are
+// kept until their newline arrives.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	text := r.partial + string(p)
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		r.observe(text[:i])
+		text = text[i+1:]
+	}
+	r.partial = text
+	return len(p), nil
+}
+
+// observe starts a command on a Bash tool use and finishes it on its result.
+// Must be called with lock held.
+func (r *Recorder) observe(line string) {
+	event, err := r.parser.ParseLine([]byte(line))
+	if err != nil || event == nil || event.ToolID == "" {
+		return
+	}
+
+	switch {
+	case event.Type == "user":
+		if c, ok := r.pending[event.ToolID]; ok {
+			r.finish(event.ToolID, c, event)
+		}
+	case event.ToolName == "Bash" && !r.seen[event.ToolID]:
+		var in bashInput
+		if json.Unmarshal([]byte(event.ToolInput), &in) != nil || strings.TrimSpace(in.Command) == "" {
+			// Streamed tool uses start without their input; the full
+			// message follows
+			return
+		}
+		r.seen[event.ToolID] = true
+		r.pending[event.ToolID] = &Command{
+			Command:     in.Command,
+			Description: in.Description,
+			Cwd:         r.cwd,
+			Background:  in.RunInBackground,
+			StartedAt:   r.now(),
+			Status:      StatusUnknown,
+		}
+		r.order = append(r.order, event.ToolID)
+	}
+}
+
+// finish completes c with its result and appends it to the trail.
+// Must be called with lock held.
+func (r *Recorder) finish(id string, c *Command, result *output.StreamEvent) {
+	c.Duration = r.now().Sub(c.StartedAt)
+	if result.ToolError {
+		c.Status = StatusFailed
+		if m := exitCodePattern.FindStringSubmatch(result.Content); m != nil {
+			if code, err := strconv.Atoi(m[1]); err == nil {
+				c.ExitCode = &code
+			}
+		}
+	} else {
+		c.Status = StatusOK
+		code := 0
+		c.ExitCode = &code
+	}
+	r.append(c)
+	delete(r.pending, id)
+}
+
+// append writes c to the trail. Must be called with lock held.
+func (r *Recorder) append(c *Command) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	_, _ = r.w.Write(append(data, '\n'))
+}
+
+// Close appends the commands still waiting for a result, with status
+// unknown, and closes the trail.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.order {
+		if c, ok := r.pending[id]; ok {
+			r.append(c)
+		}
+	}
+	r.pending = make(map[string]*Command)
+	r.order = nil
+	return r.w.Close()
+}
+
+// Read returns the commands in the audit trail at path in the order they
+// started. Commands are written as they finish, so the two can differ. An
+// encrypted trail that does not end with its final frame returns the
+// commands read along with an error wrapping encrypt.ErrTruncated.
+func Read(path string) ([]Command, error) {
+	f, err := encrypt.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var commands []Command
+	for scanner.Scan() {
+		var c Command
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			continue
+		}
+		commands = append(commands, c)
+	}
+	err = scanner.Err()
+	if err != nil && !errors.Is(err, encrypt.ErrTruncated) {
+		return nil, fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	sort.SliceStable(commands, func(i, j int) bool { return commands[i].StartedAt.Before(commands[j].StartedAt) })
+	if err != nil {
+		return commands, fmt.Errorf("audit trail %s: %w", path, err)
+	}
+	return commands, nil
+}
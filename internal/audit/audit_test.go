@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func bashUse(id, input string) string {
+	return `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"` + id + `","name":"Bash","input":` + input + `}]}}` + "\n"
+}
+
+func bashResult(id, content string, isError bool) string {
+	errField := ""
+	if isError {
+		errField = `,"is_error":true`
+	}
+	return `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"` + id + `","content":"` + content + `"` + errField + `}]}}` + "\n"
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs", "abc.audit.jsonl")
+	r, err := Open(path, "/work")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return clock }
+
+	stream := []string{
+		// Streamed tool uses start without their input
+		`{"type":"content_block_start","content_block":{"type":"tool_use","id":"t1","name":"Bash","input":{}}}` + "\n",
+		bashUse("t1", `{"command":"make test","description":"Run the tests"}`),
+		bashUse("t1", `{"command":"make test","description":"Run the tests"}`),
+		bashUse("t2", `{"command":"npm run dev","run_in_background":true}`),
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t3","name":"Read","input":{"file_path":"go.mod"}}]}}` + "\n",
+		bashUse("t4", `{"command":"go vet ./..."}`),
+	}
+	for _, line := range stream {
+		clock = clock.Add(time.Second)
+		// Split writes mid-line to check lines are reassembled
+		half := len(line) / 2
+		if _, err := r.Write([]byte(line[:half])); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Write([]byte(line[half:])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock = clock.Add(3 * time.Second)
+	for _, line := range []string{
+		bashResult("t4", "Error: Exit code 2\\nvet: undefined: foo", true),
+		bashResult("t1", "ok", false),
+		bashResult("t3", "module x", false),
+	} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	commands, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(commands) != 3 {
+		t.Fatalf("Read() = %+v, want 3 commands", commands)
+	}
+
+	test := commands[0]
+	if test.Command != "make test" || test.Description != "Run the tests" || test.Cwd != "/work" ||
+		test.Status != StatusOK || test.ExitCode == nil || *test.ExitCode != 0 || test.Duration != 7*time.Second {
+		t.Errorf("commands[0] = %+v", test)
+	}
+	dev := commands[1]
+	if dev.Command != "npm run dev" || !dev.Background || dev.Status != StatusUnknown || dev.ExitCode != nil {
+		t.Errorf("commands[1] = %+v", dev)
+	}
+	vet := commands[2]
+	if vet.Command != "go vet ./..." || vet.Status != StatusFailed || vet.ExitCode == nil || *vet.ExitCode != 2 {
+		t.Errorf("commands[2] = %+v", vet)
+	}
+}
+
+func TestRecorder_FailureWithoutExitCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.audit.jsonl")
+	r, err := Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = r.Write([]byte(bashUse("t1", `{"command":"sleep 999"}`) + bashResult("t1", "Command timed out", true)))
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := Read(path)
+	if err != nil || len(commands) != 1 {
+		t.Fatalf("Read() = %+v, %v", commands, err)
+	}
+	if commands[0].Status != StatusFailed || commands[0].ExitCode != nil {
+		t.Errorf("command = %+v, want failed without an exit code", commands[0])
+	}
+}
+
+func TestRead_Missing(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "none.audit.jsonl"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read audit trail") {
+		t.Errorf("Read() error = %v", err)
+	}
+}
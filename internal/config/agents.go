@@ -4,6 +4,7 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // Agent represents a custom agent definition for TOML config files.
@@ -341,3 +342,27 @@ func GetEffectiveAgents(userAgentsJSON string) (string, error) {
 
 	return AgentsToJSON(userAgents)
 }
+
+// CheckAgentsDefined returns an error naming every agent in names that is
+// not defined in agentsJSON.
+func CheckAgentsDefined(agentsJSON string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	var agents map[string]AgentDefinition
+	if err := json.Unmarshal([]byte(agentsJSON), &agents); err != nil {
+		return fmt.Errorf("invalid JSON syntax: %w", err)
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := agents[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("undefined agent(s): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -321,3 +321,18 @@ func TestGetEffectiveAgents(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckAgentsDefined(t *testing.T) {
+	agentsJSON, err := AgentsToJSON(map[string]Agent{"custom": {Description: "d", Prompt: "p"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckAgentsDefined(agentsJSON, []string{"custom", "security-reviewer"}); err != nil {
+		t.Errorf("CheckAgentsDefined() error = %v", err)
+	}
+	err = CheckAgentsDefined(agentsJSON, []string{"custom", "ghost", "phantom"})
+	if err == nil || !strings.Contains(err.Error(), "ghost, phantom") {
+		t.Errorf("CheckAgentsDefined() error = %v, want ghost and phantom named", err)
+	}
+}
@@ -3,7 +3,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
 // Config holds the configuration for an orbit execution session.
@@ -20,12 +25,26 @@ type Config struct {
 	// Model specifies which Claude model to use for execution (default: "opus").
 	Model string
 
+	// FallbackModels is an ordered list of models to fall back to when the
+	// primary model hits repeated failures or rate limits.
+	FallbackModels []string
+
 	// CheckerModel specifies which Claude model to use for completion checking (default: "haiku").
 	CheckerModel string
 
 	// MaxBudget is the maximum allowed spend in dollars (default: 100.00).
 	MaxBudget float64
 
+	// BudgetReserve is the percentage of MaxBudget held back for
+	// verification, so the loop cannot spend the whole budget and leave
+	// nothing to confirm completion with (default: 5). See LoopBudget.
+	BudgetReserve float64
+
+	// BudgetWarnings are fractions of MaxBudget (each above 0 and below 1)
+	// at which a soft warning fires as spend passes them, before the hard
+	// stop at MaxBudget.
+	BudgetWarnings []float64
+
 	// WorkingDir is the directory where orbit executes (default: ".").
 	WorkingDir string
 
@@ -54,6 +73,16 @@ type Config struct {
 	// MaxTurns limits the number of agentic turns per iteration (default: 0 = unlimited).
 	MaxTurns int
 
+	// MaxTurnsCeiling enables auto-tuning of MaxTurns between iterations:
+	// the limit is raised, up to this ceiling, while iterations keep running
+	// out of turns and lowered, down to MaxTurnsFloor, while they finish well
+	// under it (default: 0 = no tuning).
+	MaxTurnsCeiling int
+
+	// MaxTurnsFloor is the lowest limit auto-tuning lowers MaxTurns to
+	// (default: DefaultMaxTurnsFloor).
+	MaxTurnsFloor int
+
 	// Agents is a JSON string defining custom agents for Claude CLI --agents flag.
 	Agents string
 
@@ -71,8 +100,154 @@ type Config struct {
 	// "auto" detects the terminal background colour automatically.
 	// Default: "auto".
 	Theme string
+
+	// StallTimeout is how long claude may go without producing output before
+	// it is considered stalled. Zero disables stall detection.
+	StallTimeout time.Duration
+
+	// StallAction is what happens when claude stalls: StallActionRetry (the
+	// default) kills it and moves on to the next iteration, StallActionWarn
+	// only reports it.
+	StallAction string
+
+	// InjectFile is watched for operator instructions, which are appended to
+	// the next iteration's prompts once (see loop.Injector). Empty disables it.
+	InjectFile string
+
+	// InterjectFile is watched for operator interjections, which are
+	// prepended to the next iteration's prompts once and override the plan
+	// (see loop.FormatInterjection). Empty disables it.
+	InterjectFile string
+
+	// SeedPrompt is appended to the first iteration's prompts, e.g. the
+	// summary of the branch a --from-branch session continues.
+	SeedPrompt string
+
+	// DoubleCheckModel, when set, confirms a passing verification with a
+	// second, independent check by this model (--double-check).
+	DoubleCheckModel string
+
+	// TickSpec has orbital tick the spec items the checker model finds done
+	// after each failed verification, so the spec on disk shows progress
+	// (--tick-spec).
+	TickSpec bool
+
+	// LeanPrompts narrows the prompts after a failed verification or gate to
+	// the unchecked spec items and the failed check's feedback, instead of the
+	// recent notes (see spec.BuildLeanPrompt).
+	LeanPrompts bool
+
+	// OptimisticVerify verifies a completion promise in the background while
+	// the next iteration starts, cancelling that iteration if verification
+	// passes (see loop.PendingVerification).
+	OptimisticVerify bool
+
+	// BurstIterations is the number of quick exploratory iterations run on
+	// BurstModel with at most BurstMaxTurns turns before the loop switches
+	// to Model for the remainder (--burst). Zero disables burst mode.
+	BurstIterations int
+
+	// BurstModel is the cheap model burst iterations run on (default: "haiku").
+	BurstModel string
+
+	// BurstMaxTurns limits the agentic turns of each burst iteration
+	// (default: 10).
+	BurstMaxTurns int
+
+	// CacheTTL is how long the results of cacheable workflow steps are
+	// reused (default: DefaultCacheTTL).
+	CacheTTL time.Duration
+
+	// NoCache runs cacheable workflow steps without consulting the cache
+	// (--no-cache). Their results are still cached for later runs.
+	NoCache bool
+
+	// OfflineTimeout is how long a session stays paused while the network
+	// is down before it stops (default: 0 = wait indefinitely).
+	OfflineTimeout time.Duration
+
+	// ArchiveKeep is how many completed sessions' state is kept in
+	// .orbital/archive (default: DefaultArchiveKeep; 0 deletes the state on
+	// completion instead of archiving it).
+	ArchiveKeep int
+
+	// ContextBudget is the fraction of the model's context window a workflow
+	// step prompt may bring in, counting the spec and context files it
+	// points at. Over it, components are trimmed in ContextTrimOrder
+	// (default: 0 = no budget).
+	ContextBudget float64
+
+	// ContextTrimOrder is the order prompt components are trimmed in to fit
+	// ContextBudget (default: workflow.DefaultTrimOrder).
+	ContextTrimOrder []string
+
+	// PreflightAction is what happens to a prompt too large for the model's
+	// context window less PreflightReserve: PreflightRefuse (the default)
+	// fails the execution before claude is started, PreflightTrim cuts the
+	// middle of the prompt, PreflightSummarise replaces the middle with a
+	// summary, and PreflightOff sends it anyway.
+	PreflightAction string
+
+	// PreflightReserve is the number of tokens of the context window kept
+	// free for the response (default: DefaultPreflightReserve).
+	PreflightReserve int
+
+	// ClaudeArgs are extra flags appended verbatim to the Claude CLI command,
+	// from [claude] extra_args and --claude-arg. Flags orbital manages itself
+	// are rejected by executor.ValidateExtraArgs.
+	ClaudeArgs []string
+
+	// StreamPartial requests partial message events from the Claude CLI, so
+	// assistant text can be shown word by word as it is generated.
+	StreamPartial bool
 }
 
+// Stall actions for StallAction.
+const (
+	StallActionRetry = "retry"
+	StallActionWarn  = "warn"
+)
+
+// Preflight actions for PreflightAction.
+const (
+	PreflightRefuse    = "refuse"
+	PreflightTrim      = "trim"
+	PreflightSummarise = "summarise"
+	PreflightOff       = "off"
+)
+
+// DefaultPreflightReserve is the default number of tokens of the context
+// window kept free for the response.
+const DefaultPreflightReserve = 20000
+
+// EffectivePreflightReserve returns the configured or default preflight
+// reserve.
+func (c *Config) EffectivePreflightReserve() int {
+	if c.PreflightReserve > 0 {
+		return c.PreflightReserve
+	}
+	return DefaultPreflightReserve
+}
+
+// DefaultBudgetReserve is the default percentage of the budget reserved for
+// verification.
+const DefaultBudgetReserve = 5.0
+
+// DefaultBurstModel is the model burst iterations run on by default.
+const DefaultBurstModel = "haiku"
+
+// DefaultBurstMaxTurns is the default turn limit of a burst iteration.
+const DefaultBurstMaxTurns = 10
+
+// DefaultMaxTurnsFloor is the default lowest limit of max turns auto-tuning.
+const DefaultMaxTurnsFloor = 5
+
+// DefaultCacheTTL is how long a cacheable step's result is reused by default.
+const DefaultCacheTTL = 24 * time.Hour
+
+// DefaultArchiveKeep is how many completed sessions are archived by default.
+const DefaultArchiveKeep = 10
+
 // DefaultMaxOutputSize is the default maximum output size in bytes (10MB).
 const DefaultMaxOutputSize = 10 * 1024 * 1024
 
@@ -103,10 +278,16 @@ func NewConfig() *Config {
 		Model:             "opus",
 		CheckerModel:      "haiku",
 		MaxBudget:         100.00,
+		BudgetReserve:     DefaultBudgetReserve,
 		WorkingDir:        ".",
 		IterationTimeout:  5 * time.Minute,
 		MaxOutputSize:     DefaultMaxOutputSize,
 		Theme:             "auto",
+		BurstModel:        DefaultBurstModel,
+		BurstMaxTurns:     DefaultBurstMaxTurns,
+		MaxTurnsFloor:     DefaultMaxTurnsFloor,
+		CacheTTL:          DefaultCacheTTL,
+		ArchiveKeep:       DefaultArchiveKeep,
 	}
 }
 
@@ -125,8 +306,109 @@ func (c *Config) Validate() error {
 	if c.MaxBudget <= 0 {
 		return errors.New("max budget must be positive")
 	}
+	if c.BudgetReserve < 0 || c.BudgetReserve >= 100 {
+		return fmt.Errorf("invalid budget reserve %g%%: must be at least 0 and below 100", c.BudgetReserve)
+	}
+	if err := ValidateBudgetWarnings(c.BudgetWarnings); err != nil {
+		return err
+	}
 	if c.IterationTimeout <= 0 {
 		return errors.New("iteration timeout must be positive")
 	}
+	if c.StallTimeout < 0 {
+		return errors.New("stall timeout cannot be negative")
+	}
+	switch c.StallAction {
+	case "", StallActionRetry, StallActionWarn:
+	default:
+		return fmt.Errorf("invalid stall action %q: must be %s or %s", c.StallAction, StallActionRetry, StallActionWarn)
+	}
+	if c.MaxTurnsCeiling < 0 {
+		return errors.New("max turns ceiling cannot be negative")
+	}
+	if c.MaxTurnsCeiling > 0 && (c.MaxTurnsFloor < 1 || c.MaxTurnsFloor > c.MaxTurnsCeiling) {
+		return fmt.Errorf("invalid max turns floor %d: must be between 1 and the ceiling (%d)", c.MaxTurnsFloor, c.MaxTurnsCeiling)
+	}
+	if c.CacheTTL < 0 {
+		return errors.New("cache TTL cannot be negative")
+	}
+	if c.OfflineTimeout < 0 {
+		return errors.New("offline timeout cannot be negative")
+	}
+	if c.ArchiveKeep < 0 {
+		return errors.New("archive keep cannot be negative")
+	}
+	if c.ContextBudget < 0 || c.ContextBudget > 1 {
+		return fmt.Errorf("invalid context budget %g: must be a fraction between 0 and 1", c.ContextBudget)
+	}
+	if err := workflow.ValidateTrimOrder(c.ContextTrimOrder); err != nil {
+		return fmt.Errorf("invalid context trim order: %w", err)
+	}
+	switch c.PreflightAction {
+	case "", PreflightRefuse, PreflightTrim, PreflightSummarise, PreflightOff:
+	default:
+		return fmt.Errorf("invalid preflight action %q: must be %s, %s, %s or %s", c.PreflightAction, PreflightRefuse, PreflightTrim, PreflightSummarise, PreflightOff)
+	}
+	if c.PreflightReserve < 0 {
+		return errors.New("preflight reserve cannot be negative")
+	}
+	if c.BurstIterations < 0 {
+		return errors.New("burst iterations cannot be negative")
+	}
+	if c.BurstIterations > 0 {
+		if c.BurstIterations >= c.MaxIterations {
+			return fmt.Errorf("burst iterations (%d) must be below max iterations (%d)", c.BurstIterations, c.MaxIterations)
+		}
+		if c.BurstModel == "" {
+			return errors.New("burst model cannot be empty")
+		}
+		if c.BurstMaxTurns <= 0 {
+			return errors.New("burst max turns must be positive")
+		}
+	}
+	return nil
+}
+
+// InBurst reports whether iteration is one of the burst iterations that run
+// on BurstModel.
+func (c *Config) InBurst(iteration int) bool {
+	return iteration <= c.BurstIterations
+}
+
+// LoopBudget returns the spend at which the loop stops starting iterations:
+// MaxBudget less the BudgetReserve. Verification may spend up to MaxBudget.
+func (c *Config) LoopBudget() float64 {
+	return c.MaxBudget * (1 - c.BudgetReserve/100)
+}
+
+// ParseBudgetWarnings parses a comma-separated list of budget fractions
+// such as "0.5,0.8". An empty string means no warnings.
+func ParseBudgetWarnings(s string) ([]float64, error) {
+	var fractions []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget warning %q: not a number", part)
+		}
+		fractions = append(fractions, f)
+	}
+	if err := ValidateBudgetWarnings(fractions); err != nil {
+		return nil, err
+	}
+	return fractions, nil
+}
+
+// ValidateBudgetWarnings checks that each budget warning fraction is above
+// 0 and below 1.
+func ValidateBudgetWarnings(fractions []float64) error {
+	for _, f := range fractions {
+		if f <= 0 || f >= 1 {
+			return fmt.Errorf("invalid budget warning %g: must be a fraction of the budget above 0 and below 1", f)
+		}
+	}
 	return nil
 }
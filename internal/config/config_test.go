@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"testing"
 	"time"
 )
@@ -29,6 +30,13 @@ func TestNewConfig_ReturnsConfigWithDefaults(t *testing.T) {
 		t.Errorf("MaxBudget = %f; want 100.00", cfg.MaxBudget)
 	}
 
+	if cfg.BudgetReserve != DefaultBudgetReserve {
+		t.Errorf("BudgetReserve = %f; want %f", cfg.BudgetReserve, DefaultBudgetReserve)
+	}
+	if cfg.BurstModel != DefaultBurstModel || cfg.BurstMaxTurns != DefaultBurstMaxTurns {
+		t.Errorf("BurstModel, BurstMaxTurns = %q, %d; want %q, %d", cfg.BurstModel, cfg.BurstMaxTurns, DefaultBurstModel, DefaultBurstMaxTurns)
+	}
+
 	if cfg.WorkingDir != "." {
 		t.Errorf("WorkingDir = %q; want %q", cfg.WorkingDir, ".")
 	}
@@ -93,6 +101,261 @@ func TestConfig_Validate_AcceptsRelativeSpecPath(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate_ContextBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		fraction  float64
+		trimOrder []string
+		wantErr   bool
+	}{
+		{name: "disabled", fraction: 0},
+		{name: "half the window", fraction: 0.5, trimOrder: []string{"notes", "context"}},
+		{name: "negative", fraction: -0.1, wantErr: true},
+		{name: "above the window", fraction: 1.5, wantErr: true},
+		{name: "unknown component", fraction: 0.5, trimOrder: []string{"spec"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SpecPath = "spec.md"
+			cfg.ContextBudget = tt.fraction
+			cfg.ContextTrimOrder = tt.trimOrder
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Preflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		reserve int
+		wantErr bool
+	}{
+		{name: "default", action: ""},
+		{name: "trim", action: PreflightTrim, reserve: 8000},
+		{name: "summarise", action: PreflightSummarise},
+		{name: "off", action: PreflightOff},
+		{name: "unknown action", action: "truncate", wantErr: true},
+		{name: "negative reserve", action: PreflightRefuse, reserve: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SpecPath = "spec.md"
+			cfg.PreflightAction = tt.action
+			cfg.PreflightReserve = tt.reserve
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	cfg := NewConfig()
+	if got := cfg.EffectivePreflightReserve(); got != DefaultPreflightReserve {
+		t.Errorf("EffectivePreflightReserve() = %d, want %d", got, DefaultPreflightReserve)
+	}
+	cfg.PreflightReserve = 5000
+	if got := cfg.EffectivePreflightReserve(); got != 5000 {
+		t.Errorf("EffectivePreflightReserve() = %d, want 5000", got)
+	}
+}
+
+func TestConfig_Validate_Stall(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		action  string
+		wantErr bool
+	}{
+		{name: "disabled", timeout: 0, action: "", wantErr: false},
+		{name: "retry", timeout: 10 * time.Minute, action: StallActionRetry, wantErr: false},
+		{name: "warn", timeout: 10 * time.Minute, action: StallActionWarn, wantErr: false},
+		{name: "negative timeout", timeout: -time.Minute, action: "", wantErr: true},
+		{name: "unknown action", timeout: time.Minute, action: "nudge", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SpecPath = "spec.md"
+			cfg.StallTimeout = tt.timeout
+			cfg.StallAction = tt.action
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_OfflineTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		timeout time.Duration
+		wantErr bool
+	}{
+		{timeout: 0, wantErr: false},
+		{timeout: 30 * time.Minute, wantErr: false},
+		{timeout: -time.Minute, wantErr: true},
+	} {
+		cfg := NewConfig()
+		cfg.SpecPath = "spec.md"
+		cfg.OfflineTimeout = tt.timeout
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with offline timeout %s: error = %v, wantErr %v", tt.timeout, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_ArchiveKeep(t *testing.T) {
+	for _, tt := range []struct {
+		keep    int
+		wantErr bool
+	}{
+		{keep: 0, wantErr: false},
+		{keep: DefaultArchiveKeep, wantErr: false},
+		{keep: -1, wantErr: true},
+	} {
+		cfg := NewConfig()
+		cfg.SpecPath = "spec.md"
+		cfg.ArchiveKeep = tt.keep
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate() with archive keep %d: error = %v, wantErr %v", tt.keep, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_Validate_BudgetReserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		reserve float64
+		wantErr bool
+	}{
+		{name: "none", reserve: 0, wantErr: false},
+		{name: "default", reserve: DefaultBudgetReserve, wantErr: false},
+		{name: "negative", reserve: -1, wantErr: true},
+		{name: "whole budget", reserve: 100, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SpecPath = "spec.md"
+			cfg.BudgetReserve = tt.reserve
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_BudgetWarnings(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SpecPath = "spec.md"
+	cfg.BudgetWarnings = []float64{0.5, 0.8}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	cfg.BudgetWarnings = []float64{0.5, 1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted a warning at the whole budget")
+	}
+}
+
+func TestParseBudgetWarnings(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []float64
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "0.5,0.8", want: []float64{0.5, 0.8}},
+		{input: " 0.9 , 0.25,", want: []float64{0.9, 0.25}},
+		{input: "50%", wantErr: true},
+		{input: "0.5,80", wantErr: true},
+		{input: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBudgetWarnings(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBudgetWarnings(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ParseBudgetWarnings(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Burst(t *testing.T) {
+	tests := []struct {
+		name     string
+		burst    int
+		model    string
+		maxTurns int
+		wantErr  bool
+	}{
+		{name: "off", burst: 0, wantErr: false},
+		{name: "defaults", burst: 3, model: DefaultBurstModel, maxTurns: DefaultBurstMaxTurns, wantErr: false},
+		{name: "negative", burst: -1, wantErr: true},
+		{name: "every iteration", burst: 50, model: DefaultBurstModel, maxTurns: DefaultBurstMaxTurns, wantErr: true},
+		{name: "no model", burst: 3, model: "", maxTurns: DefaultBurstMaxTurns, wantErr: true},
+		{name: "no turns", burst: 3, model: DefaultBurstModel, maxTurns: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.SpecPath = "spec.md"
+			cfg.BurstIterations = tt.burst
+			cfg.BurstModel = tt.model
+			cfg.BurstMaxTurns = tt.maxTurns
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_InBurst(t *testing.T) {
+	cfg := &Config{BurstIterations: 2}
+	for iteration, want := range map[int]bool{1: true, 2: true, 3: false} {
+		if got := cfg.InBurst(iteration); got != want {
+			t.Errorf("InBurst(%d) = %v, want %v", iteration, got, want)
+		}
+	}
+	if (&Config{}).InBurst(1) {
+		t.Error("InBurst(1) = true without burst iterations")
+	}
+}
+
+func TestConfig_LoopBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  float64
+		reserve float64
+		want    float64
+	}{
+		{name: "no reserve", budget: 10, reserve: 0, want: 10},
+		{name: "five percent", budget: 10, reserve: 5, want: 9.5},
+		{name: "quarter", budget: 20, reserve: 25, want: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{MaxBudget: tt.budget, BudgetReserve: tt.reserve}
+			if got := cfg.LoopBudget(); got != tt.want {
+				t.Errorf("LoopBudget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_FieldsAreSettable(t *testing.T) {
 	cfg := NewConfig()
 
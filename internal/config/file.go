@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/flashingpumpkin/orbital/internal/memory"
+	"github.com/flashingpumpkin/orbital/internal/notify"
+	"github.com/flashingpumpkin/orbital/internal/telemetry"
 	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
@@ -15,6 +18,10 @@ type FileConfig struct {
 	// Prompt is the custom prompt template. Use {{files}} as placeholder for spec file paths.
 	Prompt string `toml:"prompt"`
 
+	// SpecTemplate is the template used by `orbital new-spec`. Placeholders:
+	// {{title}}, {{date}}, {{description}}, and {{criteria}}.
+	SpecTemplate string `toml:"spec_template"`
+
 	// Agents defines custom sub-agents that Claude can delegate to via the Task tool.
 	Agents map[string]Agent `toml:"agents"`
 
@@ -25,6 +32,289 @@ type FileConfig struct {
 	// When true, Claude can execute commands without prompting for permission.
 	// Default is false for safety.
 	Dangerous bool `toml:"dangerous"`
+
+	// FollowUp writes a follow-up spec with the remaining items when a run
+	// stops on its iteration or budget limit, like --follow-up.
+	FollowUp bool `toml:"follow_up"`
+
+	// BudgetWarn lists fractions of the budget at which soft warnings fire,
+	// like --budget-warn.
+	BudgetWarn []float64 `toml:"budget_warn"`
+
+	// Notifications configures the terminal bell and desktop notifications
+	// emitted on completion, repeated gate failure, and budget threshold.
+	Notifications *notify.Config `toml:"notifications"`
+
+	// Thinking controls the model's thinking (extended reasoning): "show" displays
+	// it in the TUI, "log" appends it to .orbital/thinking.log, and "discard"
+	// (the default) drops it.
+	Thinking string `toml:"thinking"`
+
+	// Sanitize controls terminal control sequences in the model's output:
+	// "strip" (the default) removes them but keeps colours, "escape" shows
+	// them as visible text, "plain" removes colours too, and "off" passes
+	// them through.
+	Sanitize string `toml:"sanitize"`
+
+	// Stats opts in to local-only usage statistics in .orbital/stats.json.
+	Stats *telemetry.Config `toml:"stats"`
+
+	// Memory opts in to the shared memory file .orbital/memory.md, which
+	// sessions read at start and add durable facts to when they end.
+	Memory *memory.Config `toml:"memory"`
+
+	// NoProgress configures the guard against iterations that repeat the same
+	// output without changing the working tree.
+	NoProgress *NoProgressConfig `toml:"no_progress"`
+
+	// CostAnomaly configures warnings for iterations that cost far more than
+	// the recent baseline.
+	CostAnomaly *CostAnomalyConfig `toml:"cost_anomaly"`
+
+	// Todos configures the scan for TODO(orbital) comments in changed files.
+	Todos *TodosConfig `toml:"todos"`
+
+	// Redact configures masking of secrets in Claude's output.
+	Redact *RedactConfig `toml:"redact"`
+
+	// Claude configures the Claude CLI invocation.
+	Claude *ClaudeConfig `toml:"claude"`
+
+	// VCS selects the version control backend: "auto" (default: git inside a
+	// git work tree, none elsewhere), "git", or "none".
+	VCS string `toml:"vcs"`
+
+	// Stall configures detection of a claude process that stops producing output.
+	Stall *StallConfig `toml:"stall"`
+
+	// MaxTurns configures auto-tuning of the agentic turn limit, like
+	// --max-turns-ceiling.
+	MaxTurns *MaxTurnsConfig `toml:"max_turns"`
+
+	// Cache configures reuse of the results of cacheable workflow steps.
+	Cache *CacheConfig `toml:"cache"`
+
+	// ContextBudget caps how much of the context window a step prompt may
+	// bring in, like --context-budget.
+	ContextBudget *ContextBudgetConfig `toml:"context_budget"`
+
+	// Preflight configures what happens to prompts too large for the
+	// model's context window.
+	Preflight *PreflightConfig `toml:"preflight"`
+
+	// Network configures pausing the session while the network is down.
+	Network *NetworkConfig `toml:"network"`
+
+	// Archive configures how many completed sessions' state is kept.
+	Archive *ArchiveConfig `toml:"archive"`
+
+	// TUI configures the size and visibility of the TUI's panels.
+	TUI *TUIConfig `toml:"tui"`
+
+	// Encryption turns on encryption at rest for logs, run records and state.
+	Encryption *EncryptionConfig `toml:"encryption"`
+
+	// Display configures the currency and locale costs and numbers are shown in.
+	Display *DisplayConfig `toml:"display"`
+
+	// GitHub configures mirroring the session's task list into a GitHub issue.
+	GitHub *GitHubConfig `toml:"github"`
+
+	// Bootstrap configures the commands run before the first iteration,
+	// such as dependency installs.
+	Bootstrap *BootstrapConfig `toml:"bootstrap"`
+}
+
+// BootstrapConfig represents the [bootstrap] section.
+type BootstrapConfig struct {
+	// Commands are run in order via sh -c in the working directory before
+	// the first iteration, e.g. ["npm ci", "go mod download"]. The run stops
+	// if one fails.
+	Commands []string `toml:"commands"`
+
+	// Timeout bounds each command (e.g. "5m", default "10m").
+	Timeout workflow.Duration `toml:"timeout"`
+}
+
+// GitHubConfig represents the [github] section.
+type GitHubConfig struct {
+	// MirrorTasks keeps a GitHub issue's body in step with the agent's task
+	// list, as a checklist updated after each iteration. Needs the gh CLI.
+	MirrorTasks bool `toml:"mirror_tasks"`
+
+	// Issue is the number of the issue to update. When unset, an issue is
+	// opened on the first iteration and reused by orbital continue.
+	Issue int `toml:"issue"`
+
+	// Repo is the repository as "owner/name" (default: the one gh resolves
+	// from the working directory).
+	Repo string `toml:"repo"`
+}
+
+// DisplayConfig represents the [display] section.
+type DisplayConfig struct {
+	// Currency is the ISO code costs are shown in, e.g. "EUR" (default "USD").
+	Currency string `toml:"currency"`
+
+	// Locale selects number separators and symbol placement, e.g. "de-DE"
+	// (default "en-US").
+	Locale string `toml:"locale"`
+
+	// Rate converts USD costs to Currency for display (display = USD × rate).
+	// Budgets and limits are always in USD. Default 1.
+	Rate float64 `toml:"rate"`
+}
+
+// EncryptionConfig represents the [encryption] section.
+type EncryptionConfig struct {
+	// Enabled encrypts the event log, thinking log, run records and session
+	// state with AES-256-GCM.
+	Enabled bool `toml:"enabled"`
+
+	// KeySource is "env" (default: read ORBITAL_ENCRYPTION_KEY) or "keychain"
+	// (macOS Keychain or the Linux secret service).
+	KeySource string `toml:"key_source"`
+}
+
+// StallConfig represents the [stall] section.
+type StallConfig struct {
+	// Timeout is how long claude may go without output (e.g. "10m"). Zero
+	// disables stall detection.
+	Timeout workflow.Duration `toml:"timeout"`
+
+	// Action is "retry" (default: kill claude and move on to the next
+	// iteration) or "warn" (report the stall and keep waiting).
+	Action string `toml:"action"`
+}
+
+// MaxTurnsConfig represents the [max_turns] section.
+type MaxTurnsConfig struct {
+	// Ceiling is the highest turn limit auto-tuning raises --max-turns to.
+	// Zero disables auto-tuning.
+	Ceiling int `toml:"ceiling"`
+
+	// Floor is the lowest turn limit auto-tuning lowers --max-turns to
+	// (default: 5).
+	Floor int `toml:"floor"`
+}
+
+// CacheConfig represents the [cache] section.
+type CacheConfig struct {
+	// TTL is how long a cacheable step's result is reused (e.g. "12h",
+	// default "24h").
+	TTL workflow.Duration `toml:"ttl"`
+}
+
+// ArchiveConfig represents the [archive] section.
+type ArchiveConfig struct {
+	// Keep is how many completed sessions are kept in .orbital/archive
+	// (default: 10). Zero turns archiving off.
+	Keep *int `toml:"keep"`
+}
+
+// TUIConfig represents the [tui] section.
+type TUIConfig struct {
+	// TaskRows is the most tasks the task panel shows before scrolling
+	// (default: 6).
+	TaskRows int `toml:"task_rows"`
+
+	// MinOutputRows is the fewest rows the output area keeps; the task panel
+	// is collapsed to make room below it (default: 4).
+	MinOutputRows int `toml:"min_output_rows"`
+
+	// BarWidth is the width of the progress bars (default: 20).
+	BarWidth int `toml:"bar_width"`
+
+	// Hide lists panels that are never shown: "tasks" and "session".
+	Hide []string `toml:"hide"`
+
+	// StreamText shows assistant text word by word as it is generated
+	// rather than a line at a time (default: true).
+	StreamText *bool `toml:"stream_text"`
+}
+
+// NetworkConfig represents the [network] section.
+type NetworkConfig struct {
+	// OfflineTimeout is how long the session waits for the network to come
+	// back before stopping (e.g. "30m", default "0": wait indefinitely).
+	OfflineTimeout workflow.Duration `toml:"offline_timeout"`
+}
+
+// ContextBudgetConfig represents the [context_budget] section.
+type ContextBudgetConfig struct {
+	// Fraction is the share of the model's context window a step prompt may
+	// bring in, e.g. 0.5. Zero disables the budget.
+	Fraction float64 `toml:"fraction"`
+
+	// TrimOrder is the order components are trimmed in when a prompt is
+	// over budget: "context", "notes" and "history" (default: that order).
+	TrimOrder []string `toml:"trim_order"`
+}
+
+// PreflightConfig represents the [preflight] section.
+type PreflightConfig struct {
+	// Action is what happens to a prompt too large for the model's context
+	// window: "refuse" (default), "trim", "summarise" or "off".
+	Action string `toml:"action"`
+
+	// Reserve is the number of tokens of the context window kept free for
+	// the response (default: 20000).
+	Reserve int `toml:"reserve"`
+}
+
+// ClaudeConfig represents the [claude] section.
+type ClaudeConfig struct {
+	// ExtraArgs are appended to every Claude CLI command, e.g.
+	// ["--permission-mode", "acceptEdits"].
+	ExtraArgs []string `toml:"extra_args"`
+}
+
+// NoProgressConfig represents the [no_progress] section.
+type NoProgressConfig struct {
+	// Limit is the number of consecutive stagnant iterations tolerated (default: 3).
+	Limit int `toml:"limit"`
+
+	// Action is "escalate" (default: inject a change-of-approach prompt, abort
+	// after another Limit stagnant iterations), "abort", or "off".
+	Action string `toml:"action"`
+}
+
+// TodosConfig represents the [todos] section.
+type TodosConfig struct {
+	// Scan looks for TODO(orbital) comments in the files changed since the
+	// run started after each iteration, lists them in the task panel and adds
+	// them to the spec as unchecked items.
+	Scan bool `toml:"scan"`
+}
+
+// RedactConfig represents the [redact] section.
+type RedactConfig struct {
+	// Patterns are extra regular expressions to mask. When an expression has
+	// a capture group only the group is masked.
+	Patterns []string `toml:"patterns"`
+
+	// DisableBuiltin turns off the built-in patterns for AWS keys, JWTs,
+	// private keys and GitHub tokens.
+	DisableBuiltin bool `toml:"disable_builtin"`
+}
+
+// CostAnomalyConfig represents the [cost_anomaly] section.
+type CostAnomalyConfig struct {
+	// Factor is the multiple of the baseline cost that counts as an anomaly
+	// (default: 3).
+	Factor float64 `toml:"factor"`
+
+	// Window is the number of recent iterations the baseline is the median
+	// of (default: 5).
+	Window int `toml:"window"`
+
+	// Action is "warn" (default), "pause" (ask for confirmation once
+	// anomalies repeat), or "off".
+	Action string `toml:"action"`
+
+	// Repeat is the number of consecutive anomalies before "pause" asks
+	// (default: 2).
+	Repeat int `toml:"repeat"`
 }
 
 // WorkflowConfig represents the workflow section in config.toml.
@@ -40,6 +330,63 @@ type WorkflowConfig struct {
 
 	// MaxGateRetries is the maximum number of times a gate can fail before aborting.
 	MaxGateRetries int `toml:"max_gate_retries"`
+
+	// EscalateModel is the model a failing gate's retry runs on once the gate
+	// has failed EscalateAfter times.
+	EscalateModel string `toml:"escalate_model"`
+
+	// EscalateAfter is the number of gate failures before escalating.
+	EscalateAfter int `toml:"escalate_after"`
+
+	// Gate bounds model-judged gates with their own timeout and retries.
+	Gate *workflow.GateConfig `toml:"gate"`
+
+	// Verification selects how completion is verified for this workflow.
+	Verification *VerificationConfig `toml:"verification"`
+
+	// Panel overrides the agents and quorum of panel review gates, such as
+	// the review step of the "panel" preset.
+	Panel *workflow.Panel `toml:"panel"`
+
+	// Context selects the context files listed in each named step's prompt,
+	// e.g. implement = ["src/**"]. It overrides the steps' own context.
+	Context map[string][]string `toml:"context"`
+}
+
+// VerificationConfig represents the [workflow.verification] section.
+type VerificationConfig struct {
+	// Strategy is "model" (default: checker model counts spec checkboxes),
+	// "command" (run Command), "hybrid" (both must pass), or "none".
+	Strategy string `toml:"strategy"`
+
+	// Command is the shell command for the command and hybrid strategies.
+	Command string `toml:"command"`
+
+	// Timeout bounds the command's run time (e.g. "10m"). Zero means no limit.
+	Timeout workflow.Duration `toml:"timeout"`
+
+	// PerFile has the checker model verify each spec file on its own and
+	// re-check only the files that changed or failed since.
+	PerFile bool `toml:"per_file"`
+
+	// Tick has the checker model find the spec items already done before
+	// each verification and orbital tick them in the spec files.
+	Tick bool `toml:"tick"`
+
+	// Prompt replaces the checker model's verification prompt. Its {{files}}
+	// placeholder lists the files to verify.
+	Prompt string `toml:"prompt"`
+
+	// Verified and Incomplete are regular expressions reading the replies to
+	// a custom prompt: Verified captures (?P<checked>\d+), Incomplete
+	// (?P<unchecked>\d+) and (?P<checked>\d+). Without them the prompt must
+	// ask for the default VERIFIED/INCOMPLETE replies.
+	Verified   string `toml:"verified"`
+	Incomplete string `toml:"incomplete"`
+
+	// Files are glob patterns, relative to the working directory, selecting
+	// the files verified instead of the session's spec files.
+	Files []string `toml:"files"`
 }
 
 // DefaultPromptTemplate is the default prompt when no config file exists.
@@ -105,6 +452,9 @@ func (wc *WorkflowConfig) ToWorkflow() (*workflow.Workflow, error) {
 		Preset:         wc.Preset,
 		Steps:          wc.Steps,
 		MaxGateRetries: wc.MaxGateRetries,
+		EscalateModel:  wc.EscalateModel,
+		EscalateAfter:  wc.EscalateAfter,
+		Gate:           wc.Gate,
 	}
 
 	// If preset is specified and no custom steps, load preset
@@ -118,6 +468,10 @@ func (wc *WorkflowConfig) ToWorkflow() (*workflow.Workflow, error) {
 			w.Name = preset.Name
 		}
 	}
+	w.ApplyPanel(wc.Panel)
+	if err := w.ApplyStepContext(wc.Context); err != nil {
+		return nil, err
+	}
 
 	// Validate the resulting workflow
 	if err := w.Validate(); err != nil {
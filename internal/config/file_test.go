@@ -329,6 +329,192 @@ func TestLoadFileConfig_WithoutDangerous(t *testing.T) {
 	}
 }
 
+func TestLoadFileConfig_WithFollowUp(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `follow_up = true`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg == nil {
+		t.Fatal("LoadFileConfig() = nil, want config")
+	}
+	if !cfg.FollowUp {
+		t.Error("FollowUp = false, want true")
+	}
+}
+
+func TestLoadFileConfig_WithBudgetWarn(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `budget_warn = [0.5, 0.8]`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg == nil || len(cfg.BudgetWarn) != 2 || cfg.BudgetWarn[0] != 0.5 || cfg.BudgetWarn[1] != 0.8 {
+		t.Errorf("BudgetWarn = %v, want [0.5 0.8]", cfg)
+	}
+}
+
+func TestLoadFileConfig_WithGitHub(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `
+[github]
+mirror_tasks = true
+issue = 42
+repo = "owner/name"
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg == nil || cfg.GitHub == nil {
+		t.Fatal("GitHub section not loaded")
+	}
+	if !cfg.GitHub.MirrorTasks || cfg.GitHub.Issue != 42 || cfg.GitHub.Repo != "owner/name" {
+		t.Errorf("GitHub = %+v", cfg.GitHub)
+	}
+}
+
+func TestLoadFileConfig_WithNotifications(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `
+[notifications]
+bell = true
+desktop = true
+events = ["complete", "budget"]
+budget_threshold = 0.75
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Notifications == nil {
+		t.Fatal("Notifications = nil, want config")
+	}
+	n := cfg.Notifications
+	if !n.Bell || !n.Desktop {
+		t.Errorf("Bell = %v, Desktop = %v, want both true", n.Bell, n.Desktop)
+	}
+	if len(n.Events) != 2 || n.Events[0] != "complete" || n.Events[1] != "budget" {
+		t.Errorf("Events = %v, want [complete budget]", n.Events)
+	}
+	if n.BudgetThreshold != 0.75 {
+		t.Errorf("BudgetThreshold = %v, want 0.75", n.BudgetThreshold)
+	}
+}
+
+func TestLoadFileConfig_WithStats(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `
+[stats]
+enabled = true
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Stats == nil || !cfg.Stats.Enabled {
+		t.Errorf("Stats = %+v, want enabled", cfg.Stats)
+	}
+}
+
+func TestLoadFileConfig_WithMemory(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `
+[memory]
+enabled = true
+max_bytes = 2000
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Memory == nil || !cfg.Memory.Enabled || cfg.Memory.EffectiveMaxBytes() != 2000 {
+		t.Errorf("Memory = %+v, want enabled with max_bytes 2000", cfg.Memory)
+	}
+}
+
+func TestLoadFileConfig_WithSpecTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `spec_template = """
+# {{title}}
+
+{{criteria}}
+"""
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.SpecTemplate != "# {{title}}\n\n{{criteria}}\n" {
+		t.Errorf("SpecTemplate = %q", cfg.SpecTemplate)
+	}
+}
+
 func TestWorkflowConfig_ToWorkflow(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -527,3 +713,344 @@ prompt = "Do the thing"
 		t.Errorf("Steps[0].EffectiveTimeout() = %v, want default %v", step.EffectiveTimeout(), workflow.DefaultStepTimeout)
 	}
 }
+
+func TestLoadFileConfig_WithThinking(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(`thinking = "log"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Thinking != "log" {
+		t.Errorf("Thinking = %q, want %q", cfg.Thinking, "log")
+	}
+}
+
+func TestLoadFileConfig_WithVerification(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configContent := `[workflow]
+preset = "spec-driven"
+
+[workflow.verification]
+strategy = "hybrid"
+command = "go test ./..."
+timeout = "10m"
+tick = true
+prompt = "Check {{files}}"
+verified = 'DONE \((?P<checked>\d+)\)'
+incomplete = 'TODO (?P<unchecked>\d+)/(?P<checked>\d+)'
+files = ["docs/plans/*.md"]
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	v := cfg.Workflow.Verification
+	if v == nil {
+		t.Fatal("Workflow.Verification is nil")
+	}
+	if v.Strategy != "hybrid" || v.Command != "go test ./..." || time.Duration(v.Timeout) != 10*time.Minute || !v.Tick {
+		t.Errorf("Verification = %+v", v)
+	}
+	if v.Prompt != "Check {{files}}" || v.Verified != `DONE \((?P<checked>\d+)\)` || v.Incomplete != `TODO (?P<unchecked>\d+)/(?P<checked>\d+)` {
+		t.Errorf("Verification prompt and grammar = %q, %q, %q", v.Prompt, v.Verified, v.Incomplete)
+	}
+	if len(v.Files) != 1 || v.Files[0] != "docs/plans/*.md" {
+		t.Errorf("Verification.Files = %v", v.Files)
+	}
+}
+
+func TestWorkflowConfig_ToWorkflow_Panel(t *testing.T) {
+	wc := &WorkflowConfig{
+		Preset: "panel",
+		Panel:  &workflow.Panel{Agents: []string{"security-reviewer", "data-reviewer"}},
+	}
+	w, err := wc.ToWorkflow()
+	if err != nil {
+		t.Fatalf("ToWorkflow() error = %v", err)
+	}
+	if got := w.PanelAgents(); len(got) != 2 || got[1] != "data-reviewer" {
+		t.Errorf("PanelAgents() = %v", got)
+	}
+
+	wc.Panel.Quorum = 3
+	if _, err := wc.ToWorkflow(); err == nil {
+		t.Error("ToWorkflow() accepted a quorum larger than the panel")
+	}
+}
+
+func TestWorkflowConfig_ToWorkflow_Context(t *testing.T) {
+	wc := &WorkflowConfig{
+		Preset:  "spec-driven",
+		Context: map[string][]string{"implement": {"src/**"}},
+	}
+	w, err := wc.ToWorkflow()
+	if err != nil {
+		t.Fatalf("ToWorkflow() error = %v", err)
+	}
+	if got := w.Steps[w.GetStepIndex("implement")].Context; len(got) != 1 || got[0] != "src/**" {
+		t.Errorf("implement context = %v, want [src/**]", got)
+	}
+
+	wc.Context = map[string][]string{"deploy": {"ops/**"}}
+	if _, err := wc.ToWorkflow(); err == nil {
+		t.Error("ToWorkflow() accepted context for an unknown step")
+	}
+}
+
+func TestWorkflowConfig_ToWorkflow_Escalation(t *testing.T) {
+	wc := &WorkflowConfig{Preset: "reviewed", EscalateModel: "opus", EscalateAfter: 1}
+	w, err := wc.ToWorkflow()
+	if err != nil {
+		t.Fatalf("ToWorkflow() error = %v", err)
+	}
+	if w.EscalateModel != "opus" || w.EscalateAfter != 1 {
+		t.Errorf("escalation = %q after %d, want opus after 1", w.EscalateModel, w.EscalateAfter)
+	}
+
+	wc.EscalateAfter = 5
+	if _, err := wc.ToWorkflow(); err == nil {
+		t.Error("ToWorkflow() accepted escalate_after at the gate retry limit")
+	}
+}
+
+func TestLoadFileConfig_WithWorkflowGate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[workflow]\npreset = \"reviewed\"\n\n[workflow.gate]\ntimeout = \"3m\"\nretries = 2\nbackoff = \"30s\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	w, err := cfg.Workflow.ToWorkflow()
+	if err != nil {
+		t.Fatalf("ToWorkflow() error = %v", err)
+	}
+	if w.Gate == nil || w.Gate.Timeout.Duration() != 3*time.Minute || w.Gate.EffectiveRetries() != 2 || w.Gate.Backoff.Duration() != 30*time.Second {
+		t.Errorf("Gate = %+v, want a 3m timeout, 2 retries and a 30s backoff", w.Gate)
+	}
+	if got := w.StepTimeout(&w.Steps[w.GetStepIndex("review")]); got != 3*time.Minute {
+		t.Errorf("review timeout = %s, want 3m", got)
+	}
+}
+
+func TestLoadFileConfig_WithNoProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[no_progress]\nlimit = 5\naction = \"abort\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.NoProgress == nil || cfg.NoProgress.Limit != 5 || cfg.NoProgress.Action != "abort" {
+		t.Errorf("NoProgress = %+v, want limit 5 and action abort", cfg.NoProgress)
+	}
+}
+
+func TestLoadFileConfig_WithTodos(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[todos]\nscan = true\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Todos == nil || !cfg.Todos.Scan {
+		t.Errorf("Todos = %+v, want scan enabled", cfg.Todos)
+	}
+}
+
+func TestLoadFileConfig_WithMaxTurns(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[max_turns]\nceiling = 80\nfloor = 10\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.MaxTurns == nil || cfg.MaxTurns.Ceiling != 80 || cfg.MaxTurns.Floor != 10 {
+		t.Errorf("MaxTurns = %+v, want ceiling 80 and floor 10", cfg.MaxTurns)
+	}
+}
+
+func TestLoadFileConfig_WithCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[cache]\nttl = \"12h\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Cache == nil || cfg.Cache.TTL.Duration() != 12*time.Hour {
+		t.Errorf("Cache = %+v, want a 12h TTL", cfg.Cache)
+	}
+}
+
+func TestLoadFileConfig_WithNetwork(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[network]\noffline_timeout = \"30m\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Network == nil || cfg.Network.OfflineTimeout.Duration() != 30*time.Minute {
+		t.Errorf("Network = %+v, want a 30m offline timeout", cfg.Network)
+	}
+}
+
+func TestLoadFileConfig_WithPreflight(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[preflight]\naction = \"trim\"\nreserve = 8000\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Preflight == nil || cfg.Preflight.Action != "trim" || cfg.Preflight.Reserve != 8000 {
+		t.Errorf("Preflight = %+v, want trim with an 8000 token reserve", cfg.Preflight)
+	}
+}
+
+func TestLoadFileConfig_WithArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[archive]\nkeep = 0\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Archive == nil || cfg.Archive.Keep == nil || *cfg.Archive.Keep != 0 {
+		t.Errorf("Archive = %+v, want keep set to 0", cfg.Archive)
+	}
+}
+
+func TestLoadFileConfig_WithTUI(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[tui]\ntask_rows = 3\nmin_output_rows = 10\nbar_width = 12\nhide = [\"session\"]\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.TUI == nil || cfg.TUI.TaskRows != 3 || cfg.TUI.MinOutputRows != 10 || cfg.TUI.BarWidth != 12 ||
+		len(cfg.TUI.Hide) != 1 || cfg.TUI.Hide[0] != "session" {
+		t.Errorf("TUI = %+v, want 3 task rows, 10 output rows, 12-wide bars and the session panel hidden", cfg.TUI)
+	}
+}
+
+func TestLoadFileConfig_WithRedact(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".orbital")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "[redact]\npatterns = ['INTERNAL-[0-9]+', 'password=(\\S+)']\ndisable_builtin = true\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFileConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadFileConfig() error = %v", err)
+	}
+	if cfg.Redact == nil {
+		t.Fatal("Redact is nil")
+	}
+	if len(cfg.Redact.Patterns) != 2 || cfg.Redact.Patterns[1] != `password=(\S+)` {
+		t.Errorf("Patterns = %q", cfg.Redact.Patterns)
+	}
+	if !cfg.Redact.DisableBuiltin {
+		t.Error("DisableBuiltin = false, want true")
+	}
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Layer names, from lowest to highest precedence.
+const (
+	// LayerUser is the user-level config in ~/.config/orbital/config.toml.
+	LayerUser = "user"
+	// LayerProject is the project config in .orbital/config.toml (or --config).
+	LayerProject = "project"
+	// LayerFlag marks values set by command-line flags.
+	LayerFlag = "flag"
+)
+
+// Source is a config file that contributes one layer.
+type Source struct {
+	// Layer is the layer name (LayerUser or LayerProject).
+	Layer string
+	// Path is the TOML file to read.
+	Path string
+	// Required makes a missing file an error instead of skipping the layer.
+	Required bool
+}
+
+// LayeredConfig is the result of merging config layers. Tables are merged key
+// by key; any other value (including arrays such as workflow.steps) from a
+// higher layer replaces the lower one wholesale.
+type LayeredConfig struct {
+	// Config is the merged configuration, or nil if no layer was loaded.
+	Config *FileConfig
+	// Loaded lists the sources that were read, lowest precedence first.
+	Loaded []Source
+
+	values  map[string]any
+	sources map[string]string
+}
+
+// Setting is one effective configuration value and where it came from.
+type Setting struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+// UserConfigPath returns the user-level config path, honouring
+// XDG_CONFIG_HOME. Returns "" if the home directory cannot be determined.
+func UserConfigPath() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "orbital", "config.toml")
+}
+
+// LoadLayered reads sources in order, lowest precedence first, and merges
+// them. Missing optional files are skipped.
+func LoadLayered(sources []Source) (*LayeredConfig, error) {
+	lc := &LayeredConfig{
+		values:  map[string]any{},
+		sources: map[string]string{},
+	}
+
+	for _, src := range sources {
+		if src.Path == "" {
+			continue
+		}
+		data, err := os.ReadFile(src.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				if src.Required {
+					return nil, fmt.Errorf("config file not found: %s", src.Path)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("failed to load config file %s: %w", src.Path, err)
+		}
+
+		var layer map[string]any
+		if _, err := toml.Decode(string(data), &layer); err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", src.Path, err)
+		}
+		lc.merge(lc.values, layer, "", src.Layer)
+		lc.Loaded = append(lc.Loaded, src)
+	}
+
+	if len(lc.Loaded) == 0 {
+		return lc, nil
+	}
+	if err := lc.decode(); err != nil {
+		return nil, err
+	}
+	return lc, nil
+}
+
+// Set overrides a single value (e.g. from a command-line flag) and records its
+// source. key is a dotted path such as "workflow.preset".
+func (lc *LayeredConfig) Set(key string, value any, source string) error {
+	parts := strings.Split(key, ".")
+	node := lc.values
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node[part].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[part] = child
+		}
+		node = child
+	}
+	lc.assign(node, parts[len(parts)-1], key, value, source)
+	return lc.decode()
+}
+
+// Settings returns every effective value with its source, sorted by key.
+// Arrays of tables are expanded so each field is listed, e.g.
+// workflow.steps[0].name.
+func (lc *LayeredConfig) Settings() []Setting {
+	var settings []Setting
+	lc.walk(lc.values, "", "", &settings)
+	return settings
+}
+
+// merge copies src into dst, recording source for every key it sets.
+func (lc *LayeredConfig) merge(dst, src map[string]any, prefix, source string) {
+	for key, value := range src {
+		path := joinKey(prefix, key)
+		srcTable, srcIsTable := value.(map[string]any)
+		dstTable, dstIsTable := dst[key].(map[string]any)
+		if srcIsTable && dstIsTable {
+			lc.merge(dstTable, srcTable, path, source)
+			continue
+		}
+		lc.assign(dst, key, path, value, source)
+	}
+}
+
+// assign sets node[key] and makes source the owner of path and everything
+// below it.
+func (lc *LayeredConfig) assign(node map[string]any, key, path string, value any, source string) {
+	for owned := range lc.sources {
+		if owned == path || hasKeyPrefix(owned, path) {
+			delete(lc.sources, owned)
+		}
+	}
+	node[key] = value
+	lc.sources[path] = source
+}
+
+// decode rebuilds Config from the merged values by round-tripping through TOML,
+// so the merged result is parsed exactly like a single file would be.
+func (lc *LayeredConfig) decode() error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(lc.values); err != nil {
+		return fmt.Errorf("failed to merge config layers: %w", err)
+	}
+	var cfg FileConfig
+	if err := toml.Unmarshal(buf.Bytes(), &cfg); err != nil {
+		return fmt.Errorf("failed to merge config layers: %w", err)
+	}
+	lc.Config = &cfg
+	return nil
+}
+
+// walk flattens node into settings. inherited is the source of the nearest
+// enclosing key that was set as a whole.
+func (lc *LayeredConfig) walk(node map[string]any, prefix, inherited string, settings *[]Setting) {
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := joinKey(prefix, key)
+		source := inherited
+		if s, ok := lc.sources[path]; ok {
+			source = s
+		}
+
+		switch value := node[key].(type) {
+		case map[string]any:
+			lc.walk(value, path, source, settings)
+		case []map[string]any:
+			for i, table := range value {
+				lc.walk(table, path+"["+strconv.Itoa(i)+"]", source, settings)
+			}
+		default:
+			*settings = append(*settings, Setting{Key: path, Value: value, Source: source})
+		}
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func hasKeyPrefix(key, prefix string) bool {
+	return strings.HasPrefix(key, prefix+".") || strings.HasPrefix(key, prefix+"[")
+}
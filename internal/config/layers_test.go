@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLayer(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func settingsByKey(lc *LayeredConfig) map[string]Setting {
+	byKey := map[string]Setting{}
+	for _, s := range lc.Settings() {
+		byKey[s.Key] = s
+	}
+	return byKey
+}
+
+func TestUserConfigPath_HonoursXDG(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got, want := UserConfigPath(), filepath.Join("/xdg", "orbital", "config.toml"); got != want {
+		t.Errorf("UserConfigPath() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadLayered_ProjectOverridesUser(t *testing.T) {
+	userPath := writeLayer(t, t.TempDir(), `
+thinking = "show"
+dangerous = true
+
+[notifications]
+bell = true
+desktop = true
+`)
+	projectPath := writeLayer(t, t.TempDir(), `
+thinking = "log"
+
+[notifications]
+desktop = false
+
+[workflow]
+preset = "tdd"
+`)
+
+	lc, err := LoadLayered([]Source{
+		{Layer: LayerUser, Path: userPath},
+		{Layer: LayerProject, Path: projectPath},
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	cfg := lc.Config
+	if cfg.Thinking != "log" {
+		t.Errorf("Thinking = %q, want project value %q", cfg.Thinking, "log")
+	}
+	if !cfg.Dangerous {
+		t.Error("Dangerous = false, want user value true")
+	}
+	if !cfg.Notifications.Bell || cfg.Notifications.Desktop {
+		t.Errorf("Notifications = %+v, want bell from user and desktop from project", cfg.Notifications)
+	}
+	if cfg.Workflow == nil || cfg.Workflow.Preset != "tdd" {
+		t.Errorf("Workflow = %+v, want preset tdd", cfg.Workflow)
+	}
+
+	settings := settingsByKey(lc)
+	for key, want := range map[string]string{
+		"thinking":              LayerProject,
+		"dangerous":             LayerUser,
+		"notifications.bell":    LayerUser,
+		"notifications.desktop": LayerProject,
+		"workflow.preset":       LayerProject,
+	} {
+		if got := settings[key].Source; got != want {
+			t.Errorf("source of %s = %q, want %q", key, got, want)
+		}
+	}
+	if len(lc.Loaded) != 2 {
+		t.Errorf("Loaded = %v, want both layers", lc.Loaded)
+	}
+}
+
+func TestLoadLayered_ArraysReplaceWholesale(t *testing.T) {
+	userPath := writeLayer(t, t.TempDir(), `
+[workflow]
+[[workflow.steps]]
+name = "a"
+prompt = "a"
+[[workflow.steps]]
+name = "b"
+prompt = "b"
+`)
+	projectPath := writeLayer(t, t.TempDir(), `
+[workflow]
+[[workflow.steps]]
+name = "only"
+prompt = "only"
+`)
+
+	lc, err := LoadLayered([]Source{
+		{Layer: LayerUser, Path: userPath},
+		{Layer: LayerProject, Path: projectPath},
+	})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if steps := lc.Config.Workflow.Steps; len(steps) != 1 || steps[0].Name != "only" {
+		t.Errorf("Steps = %+v, want only the project step", steps)
+	}
+	if s := settingsByKey(lc)["workflow.steps[0].name"]; s.Source != LayerProject || s.Value != "only" {
+		t.Errorf("workflow.steps[0].name = %+v, want project value", s)
+	}
+}
+
+func TestLoadLayered_MissingFiles(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+
+	lc, err := LoadLayered([]Source{{Layer: LayerUser, Path: missing}, {Layer: LayerProject, Path: ""}})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	if lc.Config != nil {
+		t.Errorf("Config = %+v, want nil when no layer exists", lc.Config)
+	}
+
+	_, err = LoadLayered([]Source{{Layer: LayerProject, Path: missing, Required: true}})
+	if err == nil || !strings.Contains(err.Error(), "config file not found") {
+		t.Errorf("LoadLayered() error = %v, want not found error", err)
+	}
+}
+
+func TestLoadLayered_InvalidTOML(t *testing.T) {
+	path := writeLayer(t, t.TempDir(), "invalid toml {{{")
+	if _, err := LoadLayered([]Source{{Layer: LayerProject, Path: path}}); err == nil {
+		t.Error("LoadLayered() error = nil, want error for invalid TOML")
+	}
+}
+
+func TestLayeredConfig_Set(t *testing.T) {
+	path := writeLayer(t, t.TempDir(), `
+[workflow]
+preset = "fast"
+max_gate_retries = 2
+`)
+	lc, err := LoadLayered([]Source{{Layer: LayerProject, Path: path}})
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if err := lc.Set("workflow.preset", "tdd", "flag --workflow"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := lc.Set("dangerous", true, "flag --dangerous"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if lc.Config.Workflow.Preset != "tdd" || !lc.Config.Dangerous {
+		t.Errorf("Config = %+v, want flag values applied", lc.Config)
+	}
+	settings := settingsByKey(lc)
+	if s := settings["workflow.preset"]; s.Source != "flag --workflow" {
+		t.Errorf("workflow.preset source = %q, want flag", s.Source)
+	}
+	if s := settings["workflow.max_gate_retries"]; s.Source != LayerProject {
+		t.Errorf("workflow.max_gate_retries source = %q, want project", s.Source)
+	}
+}
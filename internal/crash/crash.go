@@ -0,0 +1,244 @@
+// Package crash writes diagnostic bundles when orbital panics, so a crash
+// leaves behind the stack trace and the context needed to reproduce it.
+//
+// A bundle is a directory under .orbital/crash/ holding:
+//
+//	panic.txt   the panic value, Go version, platform and stack trace
+//	config.json the run configuration with secrets redacted
+//	output.log  the last OutputLines lines of output
+//	state.json  the session state at the time of the crash
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+// OutputLines is the number of recent output lines kept for a bundle.
+const OutputLines = 200
+
+// maxLineLength bounds a recorded output line so a single huge tool result
+// cannot crowd out the rest of the bundle.
+const maxLineLength = 2000
+
+// Redacted replaces secret values in a bundle's config.
+const Redacted = "[redacted]"
+
+// Dir returns the directory crash bundles are written to.
+func Dir(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "crash")
+}
+
+// Bundle is what is known about a crash.
+type Bundle struct {
+	// Time is when the crash happened.
+	Time time.Time
+
+	// Panic is the recovered panic value.
+	Panic any
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+
+	// Config is the run configuration. Secrets are redacted when written.
+	Config any
+
+	// Output is the most recent output, oldest first.
+	Output []string
+
+	// State is the session state.
+	State any
+}
+
+// Write writes the bundle to a new timestamped directory under dir and
+// returns its path. Files are encrypted when encryption is enabled.
+func Write(dir string, b Bundle) (string, error) {
+	if b.Time.IsZero() {
+		b.Time = time.Now()
+	}
+	path := filepath.Join(dir, b.Time.Format("20060102-150405.000"))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+
+	files := map[string][]byte{
+		"panic.txt":  []byte(formatPanic(b)),
+		"output.log": []byte(strings.Join(b.Output, "\n") + "\n"),
+	}
+	if b.Config != nil {
+		config, err := Redact(b.Config)
+		if err != nil {
+			return "", err
+		}
+		if files["config.json"], err = json.MarshalIndent(config, "", "  "); err != nil {
+			return "", fmt.Errorf("failed to marshal config: %w", err)
+		}
+	}
+	if b.State != nil {
+		data, err := json.MarshalIndent(b.State, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal state: %w", err)
+		}
+		files["state.json"] = data
+	}
+
+	for name, data := range files {
+		if err := encrypt.WriteFile(filepath.Join(path, name), data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return path, nil
+}
+
+// formatPanic renders the panic value, build information and stack trace.
+func formatPanic(b Bundle) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "panic: %v\n\n", b.Panic)
+	fmt.Fprintf(&s, "time: %s\n", b.Time.Format(time.RFC3339))
+	fmt.Fprintf(&s, "go: %s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if len(b.Stack) == 0 {
+		s.WriteString("(stack trace unavailable)\n")
+	} else {
+		s.Write(b.Stack)
+	}
+	return s.String()
+}
+
+// secretKey matches config field names whose values are secrets.
+var secretKey = regexp.MustCompile(`(?i)(key|token|secret|password|credential)`)
+
+// inlineSecret matches secrets embedded in string values, such as
+// "--api-key=..." in pass-through arguments.
+var inlineSecret = regexp.MustCompile(`(?i)((?:api[_-]?key|token|secret|password)[=:])\S+`)
+
+// Redact returns v as generic JSON with the values of secret-looking fields
+// and inline secrets replaced by Redacted.
+func Redact(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return redact(generic), nil
+}
+
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if secretKey.MatchString(k) && !isEmpty(val) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redact(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = redact(val)
+		}
+		return v
+	case string:
+		return inlineSecret.ReplaceAllString(v, "${1}"+Redacted)
+	default:
+		return v
+	}
+}
+
+// isEmpty reports whether a JSON value carries nothing worth redacting.
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+// Recorder keeps the most recent output lines for a bundle. It is safe for
+// concurrent use and implements io.Writer, splitting written text into
+// lines with ANSI sequences removed.
+type Recorder struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial string
+}
+
+// NewRecorder creates a recorder keeping the last n lines.
+func NewRecorder(n int) *Recorder {
+	if n <= 0 {
+		n = OutputLines
+	}
+	return &Recorder{lines: make([]string, n)}
+}
+
+// Record adds a line of output. Embedded newlines start new lines.
+func (r *Recorder) Record(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range strings.Split(line, "\n") {
+		r.push(l)
+	}
+}
+
+// Write records the complete lines in p. A trailing partial line is held
+// until the rest of it is written.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	text := r.partial + string(p)
+	lines := strings.Split(text, "\n")
+	r.partial = lines[len(lines)-1]
+	for _, l := range lines[:len(lines)-1] {
+		r.push(l)
+	}
+	return len(p), nil
+}
+
+// push adds a line, overwriting the oldest once full. Callers hold mu.
+func (r *Recorder) push(line string) {
+	line = strings.TrimRight(ansi.Strip(line), "\r")
+	if ansi.StringWidth(line) > maxLineLength {
+		line = ansi.Truncate(line, maxLineLength, "…")
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Lines returns the recorded lines, oldest first, including any partial line.
+func (r *Recorder) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lines []string
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+	if r.partial != "" {
+		lines = append(lines, ansi.Strip(r.partial))
+	}
+	return lines
+}
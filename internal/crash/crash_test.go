@@ -0,0 +1,125 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder_KeepsLastLines(t *testing.T) {
+	r := NewRecorder(3)
+	for _, line := range []string{"one", "two", "three", "four"} {
+		r.Record(line)
+	}
+	if got, want := r.Lines(), []string{"two", "three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestRecorder_Write(t *testing.T) {
+	r := NewRecorder(10)
+	_, _ = r.Write([]byte("\x1b[32mfirst\x1b[0m\r\nsec"))
+	_, _ = r.Write([]byte("ond\nthi"))
+	if got, want := r.Lines(), []string{"first", "second", "thi"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestRecorder_TruncatesLongLines(t *testing.T) {
+	r := NewRecorder(1)
+	r.Record(strings.Repeat("x", maxLineLength*2))
+	if got := r.Lines()[0]; len([]rune(got)) != maxLineLength {
+		t.Errorf("recorded line has %d runes, want %d", len([]rune(got)), maxLineLength)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	config := struct {
+		Model      string
+		APIKey     string
+		Token      string
+		ClaudeArgs []string
+		Nested     map[string]string
+	}{
+		Model:      "opus",
+		APIKey:     "sk-123",
+		ClaudeArgs: []string{"--api-key=sk-456", "--verbose"},
+		Nested:     map[string]string{"password": "hunter2", "user": "me"},
+	}
+
+	got, err := Redact(config)
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	want := map[string]any{
+		"Model":      "opus",
+		"APIKey":     Redacted,
+		"Token":      "",
+		"ClaudeArgs": []any{"--api-key=" + Redacted, "--verbose"},
+		"Nested":     map[string]any{"password": Redacted, "user": "me"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %#v, want %#v", got, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crash")
+	path, err := Write(dir, Bundle{
+		Time:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Panic:  "index out of range",
+		Stack:  []byte("goroutine 1 [running]:\nmain.main()\n"),
+		Config: map[string]string{"Model": "opus", "Secret": "s3cret"},
+		Output: []string{"line one", "line two"},
+		State:  map[string]int{"iteration": 3},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("bundle path = %s, want it under %s", path, dir)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			t.Fatalf("bundle missing %s: %v", name, err)
+		}
+		return string(data)
+	}
+	for name, want := range map[string]string{
+		"panic.txt":   "panic: index out of range",
+		"config.json": `"Secret": "[redacted]"`,
+		"output.log":  "line one\nline two\n",
+		"state.json":  `"iteration": 3`,
+	} {
+		if got := read(name); !strings.Contains(got, want) {
+			t.Errorf("%s = %q, want it to contain %q", name, got, want)
+		}
+	}
+	if got := read("panic.txt"); !strings.Contains(got, "main.main()") {
+		t.Errorf("panic.txt missing stack trace: %q", got)
+	}
+}
+
+func TestWrite_WithoutStack(t *testing.T) {
+	path, err := Write(t.TempDir(), Bundle{Panic: "boom"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(path, "panic.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "stack trace unavailable") {
+		t.Errorf("panic.txt = %q", data)
+	}
+	for _, name := range []string{"config.json", "state.json"} {
+		if _, err := os.Stat(filepath.Join(path, name)); !os.IsNotExist(err) {
+			t.Errorf("%s written without content", name)
+		}
+	}
+}
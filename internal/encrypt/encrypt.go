@@ -0,0 +1,527 @@
+// Package encrypt provides optional encryption at rest for the files orbital
+// persists: the event log, the thinking log, run records and session state.
+//
+// Encrypted files start with a magic header and a random salt, followed by
+// frames, each holding one AES-256-GCM sealed chunk prefixed by its length
+// and flags. Every write becomes its own frame, so append-only logs stay
+// appendable without rewriting earlier data. Each frame's index and whether
+// it is the last are authenticated with it, so frames dropped, reordered or
+// cut off the end are detected. Files without the header are read as
+// plaintext, so turning encryption on does not break existing sessions.
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Magic starts every encrypted file.
+const Magic = "ORBITAL-ENC2\n"
+
+// KeyEnv is the environment variable holding the encryption key.
+const KeyEnv = "ORBITAL_ENCRYPTION_KEY"
+
+// maxFrame bounds the size of a single frame so a corrupt length prefix
+// cannot trigger a huge allocation.
+const maxFrame = 64 << 20
+
+// saltSize is the length of the salt stored after the header.
+const saltSize = 16
+
+// kdfIterations is the PBKDF2-HMAC-SHA256 work factor for passphrases.
+const kdfIterations = 600_000
+
+// flagFinal marks the last frame of a file.
+const flagFinal = 1
+
+// ErrNoKey is returned when reading an encrypted file without a key.
+var ErrNoKey = errors.New("file is encrypted but no encryption key is available (set " + KeyEnv + ")")
+
+// ErrTruncated is returned, after everything before it has been read, when an
+// encrypted file does not end with its final frame: it was cut short, or is a
+// log still being written or left behind by a process that did not exit
+// cleanly.
+var ErrTruncated = errors.New("encrypted file does not end with its final frame (truncated, still being written or not closed cleanly)")
+
+// Key is the secret files are encrypted with: an AES-256 key, or a passphrase
+// each file's key is derived from with the file's salt.
+type Key struct {
+	raw        [32]byte
+	passphrase string
+}
+
+// ParseKey parses a key given as 64 hex characters or base64 of 32 bytes.
+// Any other non-empty value is treated as a passphrase.
+func ParseKey(s string) (Key, error) {
+	var k Key
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return k, errors.New("encryption key is empty")
+	}
+	if b, err := hex.DecodeString(s); err == nil && len(b) == len(k.raw) {
+		copy(k.raw[:], b)
+		return k, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && len(b) == len(k.raw) {
+		copy(k.raw[:], b)
+		return k, nil
+	}
+	k.passphrase = s
+	return k, nil
+}
+
+// derivation identifies a passphrase-derived key in the cache.
+type derivation struct {
+	passphrase string
+	salt       string
+}
+
+var (
+	derivedMu sync.Mutex
+	derived   = make(map[derivation][32]byte)
+)
+
+// fileKey returns the AES key for a file with the given salt. Passphrases are
+// stretched with PBKDF2, once per salt.
+func (k *Key) fileKey(salt []byte) ([32]byte, error) {
+	if k.passphrase == "" {
+		return k.raw, nil
+	}
+	d := derivation{passphrase: k.passphrase, salt: string(salt)}
+	derivedMu.Lock()
+	defer derivedMu.Unlock()
+	if key, ok := derived[d]; ok {
+		return key, nil
+	}
+	b, err := pbkdf2.Key(sha256.New, k.passphrase, salt, kdfIterations, 32)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to derive key: %w", err)
+	}
+	var key [32]byte
+	copy(key[:], b)
+	derived[d] = key
+	return key, nil
+}
+
+var (
+	mu     sync.RWMutex
+	active *Key
+	// salt is used for the files written with the active key, so a
+	// passphrase is stretched once per process rather than once per file.
+	salt []byte
+)
+
+// SetKey sets the key used to encrypt files written by orbital. A nil key
+// disables encryption for new writes; existing encrypted files can still be
+// read if the key is available from the environment.
+func SetKey(k *Key) {
+	mu.Lock()
+	defer mu.Unlock()
+	active = k
+	salt = nil
+	if k != nil {
+		salt = make([]byte, saltSize)
+		_, _ = rand.Read(salt)
+	}
+}
+
+// Enabled reports whether new writes are encrypted.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active != nil
+}
+
+// activeKey returns the key and salt for new writes, or a nil key when
+// encryption is disabled.
+func activeKey() (*Key, []byte) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active, salt
+}
+
+// readKey returns the key for decrypting: the active key, or the key from
+// KeyEnv so commands that do not load the config can still read files.
+func readKey() (*Key, error) {
+	if k, _ := activeKey(); k != nil {
+		return k, nil
+	}
+	if v := os.Getenv(KeyEnv); v != "" {
+		key, err := ParseKey(v)
+		if err != nil {
+			return nil, err
+		}
+		return &key, nil
+	}
+	return nil, ErrNoKey
+}
+
+// IsEncrypted reports whether data starts with an encrypted file header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Magic))
+}
+
+// newAEAD creates the AES-GCM cipher for a key.
+func newAEAD(k [32]byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// frameAD is the additional data authenticated with a frame: the file's salt,
+// the frame's index and its flags.
+func frameAD(salt []byte, index uint64, flags byte) []byte {
+	ad := make([]byte, 0, len(salt)+9)
+	ad = append(ad, salt...)
+	ad = binary.BigEndian.AppendUint64(ad, index)
+	return append(ad, flags)
+}
+
+// writer seals every Write into a frame.
+type writer struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	salt  []byte
+	index uint64
+}
+
+// newWriter returns a writer that encrypts frames with k for a file with the
+// given salt, starting at frame index. The header is not written.
+func newWriter(w io.Writer, k *Key, salt []byte, index uint64) (*writer, error) {
+	key, err := k.fileKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &writer{w: w, aead: aead, salt: salt, index: index}, nil
+}
+
+// Write implements io.Writer.
+func (e *writer) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := e.writeFrame(p, 0); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// finish writes p as the final frame, which marks the end of the file.
+func (e *writer) finish(p []byte) error {
+	return e.writeFrame(p, flagFinal)
+}
+
+// writeFrame seals p into the next frame.
+func (e *writer) writeFrame(p []byte, flags byte) error {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(p)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, p, frameAD(e.salt, e.index, flags))
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 5+len(sealed)), uint32(len(sealed)))
+	frame = append(frame, flags)
+	frame = append(frame, sealed...)
+	if _, err := e.w.Write(frame); err != nil {
+		return err
+	}
+	e.index++
+	return nil
+}
+
+// reader decrypts frames as they are read.
+type reader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	buf   []byte
+	salt  []byte
+	index uint64
+	// final is set once the last frame read was the final one
+	final bool
+}
+
+// NewReader returns a reader over the plaintext of r. If r does not start
+// with an encrypted file header it is passed through unchanged; otherwise
+// the key is taken from SetKey or KeyEnv and ErrNoKey is returned without one.
+// Reading an encrypted file that does not end with its final frame returns
+// ErrTruncated once everything before has been read.
+func NewReader(r io.Reader) (io.Reader, error) {
+	header := make([]byte, len(Magic))
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n < len(Magic) || string(header) != Magic {
+		return io.MultiReader(bytes.NewReader(header[:n]), r), nil
+	}
+
+	k, err := readKey()
+	if err != nil {
+		return nil, err
+	}
+	fileSalt := make([]byte, saltSize)
+	if _, err := io.ReadFull(r, fileSalt); err != nil {
+		return nil, fmt.Errorf("truncated encrypted header: %w", err)
+	}
+	key, err := k.fileKey(fileSalt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &reader{r: r, aead: aead, salt: fileSalt}, nil
+}
+
+// Read implements io.Reader.
+func (d *reader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(d.r, size[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				if !d.final {
+					return 0, ErrTruncated
+				}
+				return 0, io.EOF
+			}
+			return 0, fmt.Errorf("truncated encrypted frame: %w", err)
+		}
+		var flags [1]byte
+		if _, err := io.ReadFull(d.r, flags[:]); err != nil {
+			return 0, fmt.Errorf("truncated encrypted frame: %w", err)
+		}
+		if flags[0]&^flagFinal != 0 {
+			return 0, fmt.Errorf("invalid encrypted frame flags %#x", flags[0])
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n > maxFrame || int(n) < d.aead.NonceSize() {
+			return 0, fmt.Errorf("invalid encrypted frame length %d", n)
+		}
+		sealed := make([]byte, n)
+		if _, err := io.ReadFull(d.r, sealed); err != nil {
+			return 0, fmt.Errorf("truncated encrypted frame: %w", err)
+		}
+		nonce, ciphertext := sealed[:d.aead.NonceSize()], sealed[d.aead.NonceSize():]
+		plain, err := d.aead.Open(nil, nonce, ciphertext, frameAD(d.salt, d.index, flags[0]))
+		if err != nil {
+			return 0, errors.New("failed to decrypt: wrong key, or a corrupt, reordered or spliced frame")
+		}
+		d.index++
+		d.final = flags[0]&flagFinal != 0
+		d.buf = plain
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// Seal returns data as a complete encrypted file, or data unchanged when
+// encryption is disabled.
+func Seal(data []byte) ([]byte, error) {
+	k, fileSalt := activeKey()
+	if k == nil {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(Magic)
+	buf.Write(fileSalt)
+	w, err := newWriter(&buf, k, fileSalt, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.finish(data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Open returns the plaintext of a file's contents. Plaintext input is
+// returned unchanged.
+func Open(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	r, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// ReadFile reads a file written by WriteFile or OpenAppend and returns its
+// plaintext.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data)
+}
+
+// WriteFile writes data to path, encrypted when a key is set.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	sealed, err := Seal(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, sealed, perm)
+}
+
+// OpenFile opens a file for reading its plaintext. The caller must close it.
+func OpenFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, f}, nil
+}
+
+// appendFile is a log opened by OpenAppend.
+type appendFile struct {
+	io.Writer
+	f *os.File
+	// enc is the encrypting writer, nil for a plaintext log
+	enc *writer
+}
+
+// Close writes an encrypted log's final frame and closes the underlying file.
+func (a *appendFile) Close() error {
+	var err error
+	if a.enc != nil {
+		err = a.enc.finish(nil)
+	}
+	if closeErr := a.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// OpenAppend opens path for appending, creating it and its directory if
+// needed. When a key is set, writes are encrypted, a new file gets the
+// header, and Close marks the end of the log with a final frame. Appending
+// encrypted data to an existing plaintext file (or the other way round) is
+// refused rather than producing a file that cannot be read back.
+func OpenAppend(path string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, len(Magic))
+	n, _ := io.ReadFull(f, header)
+	existing := n > 0
+	encrypted := IsEncrypted(header[:n])
+
+	k, fileSalt := activeKey()
+	if k == nil {
+		if encrypted {
+			_ = f.Close()
+			return nil, fmt.Errorf("%s is encrypted but encryption is not enabled", path)
+		}
+		return &appendFile{Writer: f, f: f}, nil
+	}
+	if existing && !encrypted {
+		_ = f.Close()
+		return nil, fmt.Errorf("%s is not encrypted; move it aside to start an encrypted log", path)
+	}
+
+	var w *writer
+	switch {
+	case !existing:
+		if _, err = f.Write(append([]byte(Magic), fileSalt...)); err == nil {
+			w, err = newWriter(f, k, fileSalt, 0)
+		}
+	default:
+		// Carry on from the existing frames, under the file's own salt
+		var frames uint64
+		fileSalt = make([]byte, saltSize)
+		if _, err = io.ReadFull(f, fileSalt); err == nil {
+			frames, err = countFrames(f)
+		}
+		if err == nil {
+			w, err = newWriter(f, k, fileSalt, frames)
+		} else {
+			err = fmt.Errorf("%s is corrupt: %w", path, err)
+		}
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &appendFile{Writer: w, f: f, enc: w}, nil
+}
+
+// countFrames skips over the frames from r's current position to its end
+// and returns how many there are.
+func countFrames(r io.ReadSeeker) (uint64, error) {
+	pos, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(pos, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	var frames uint64
+	for {
+		var prefix [5]byte
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return frames, nil
+			}
+			return 0, fmt.Errorf("truncated encrypted frame: %w", err)
+		}
+		n := binary.BigEndian.Uint32(prefix[:4])
+		if n > maxFrame {
+			return 0, fmt.Errorf("invalid encrypted frame length %d", n)
+		}
+		if pos, err = r.Seek(int64(n), io.SeekCurrent); err != nil {
+			return 0, err
+		}
+		if pos > end {
+			return 0, errors.New("truncated encrypted frame")
+		}
+		frames++
+	}
+}
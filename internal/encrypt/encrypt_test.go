@@ -0,0 +1,304 @@
+package encrypt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withKey sets the active key for the duration of a test.
+func withKey(t *testing.T, k *Key) {
+	t.Helper()
+	SetKey(k)
+	t.Cleanup(func() { SetKey(nil) })
+}
+
+func testKey(t *testing.T, s string) *Key {
+	t.Helper()
+	k, err := ParseKey(s)
+	if err != nil {
+		t.Fatalf("ParseKey() error = %v", err)
+	}
+	return &k
+}
+
+func TestParseKey(t *testing.T) {
+	hexKey := strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		in      string
+		want    byte
+		wantErr bool
+	}{
+		{name: "hex", in: hexKey, want: 0xab},
+		{name: "base64", in: "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=", want: 0x01},
+		{name: "passphrase", in: "correct horse battery staple"},
+		{name: "empty", in: "  ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKey(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != 0 && (k.raw[0] != tt.want || k.raw[31] != tt.want) {
+				t.Errorf("ParseKey() = %x, want every byte %x", k, tt.want)
+			}
+		})
+	}
+}
+
+func TestSealOpen_RoundTrip(t *testing.T) {
+	withKey(t, testKey(t, "secret"))
+
+	sealed, err := Seal([]byte(`{"state":1}`))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if !IsEncrypted(sealed) || bytes.Contains(sealed, []byte("state")) {
+		t.Fatalf("Seal() = %q, want encrypted output", sealed)
+	}
+	plain, err := Open(sealed)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if string(plain) != `{"state":1}` {
+		t.Errorf("Open() = %q", plain)
+	}
+}
+
+func TestSeal_DisabledPassesThrough(t *testing.T) {
+	SetKey(nil)
+	sealed, err := Seal([]byte("plain"))
+	if err != nil || string(sealed) != "plain" {
+		t.Errorf("Seal() = %q, %v, want plaintext unchanged", sealed, err)
+	}
+	plain, err := Open([]byte("plain"))
+	if err != nil || string(plain) != "plain" {
+		t.Errorf("Open() = %q, %v, want plaintext unchanged", plain, err)
+	}
+}
+
+func TestOpen_WrongKey(t *testing.T) {
+	withKey(t, testKey(t, "one"))
+	sealed, err := Seal([]byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKey(testKey(t, "two"))
+	if _, err := Open(sealed); err == nil {
+		t.Error("Open() with the wrong key succeeded, want error")
+	}
+}
+
+func TestOpen_KeyFromEnv(t *testing.T) {
+	withKey(t, testKey(t, "from-env"))
+	sealed, err := Seal([]byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKey(nil)
+
+	t.Setenv(KeyEnv, "")
+	if _, err := Open(sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Open() without a key error = %v, want ErrNoKey", err)
+	}
+
+	t.Setenv(KeyEnv, "from-env")
+	plain, err := Open(sealed)
+	if err != nil || string(plain) != "data" {
+		t.Errorf("Open() = %q, %v, want key read from %s", plain, err, KeyEnv)
+	}
+}
+
+func TestOpenAppend_AcrossReopens(t *testing.T) {
+	withKey(t, testKey(t, "secret"))
+	path := filepath.Join(t.TempDir(), "logs", "events.jsonl")
+
+	for _, line := range []string{"first\n", "second\n"} {
+		w, err := OpenAppend(path)
+		if err != nil {
+			t.Fatalf("OpenAppend() error = %v", err)
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Count(raw, []byte(Magic)) != 1 || bytes.Contains(raw, []byte("first")) {
+		t.Fatalf("file = %q, want one header and no plaintext", raw)
+	}
+
+	r, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(got) != "first\nsecond\n" {
+		t.Errorf("plaintext = %q", got)
+	}
+}
+
+// frames splits an encrypted file after its header and salt into its frames.
+func frames(t *testing.T, data []byte) (header []byte, out [][]byte) {
+	t.Helper()
+	header, rest := data[:len(Magic)+saltSize], data[len(Magic)+saltSize:]
+	for len(rest) > 0 {
+		n := 5 + int(binary.BigEndian.Uint32(rest[:4]))
+		out = append(out, rest[:n])
+		rest = rest[n:]
+	}
+	return header, out
+}
+
+func TestParseKey_PassphraseIsSalted(t *testing.T) {
+	k := testKey(t, "correct horse battery staple")
+	salt1, salt2 := bytes.Repeat([]byte{1}, saltSize), bytes.Repeat([]byte{2}, saltSize)
+	key1, err := k.fileKey(salt1)
+	if err != nil {
+		t.Fatalf("fileKey() error = %v", err)
+	}
+	key2, _ := k.fileKey(salt2)
+	if key1 == key2 || key1 == sha256.Sum256([]byte("correct horse battery staple")) {
+		t.Error("fileKey() did not derive the key from the passphrase and salt")
+	}
+
+	withKey(t, k)
+	first, _ := Seal([]byte("data"))
+	SetKey(k)
+	second, _ := Seal([]byte("data"))
+	if bytes.Equal(first[:len(Magic)+saltSize], second[:len(Magic)+saltSize]) {
+		t.Error("Seal() reused the salt across keys, want a random salt in each header")
+	}
+	for _, sealed := range [][]byte{first, second} {
+		if plain, err := Open(sealed); err != nil || string(plain) != "data" {
+			t.Errorf("Open() = %q, %v", plain, err)
+		}
+	}
+}
+
+func TestReader_DetectsTruncationAndReordering(t *testing.T) {
+	withKey(t, testKey(t, "secret"))
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for _, lines := range [][]string{{"first\n", "second\n"}, {"third\n"}} {
+		w, err := OpenAppend(path)
+		if err != nil {
+			t.Fatalf("OpenAppend() error = %v", err)
+		}
+		for _, line := range lines {
+			if _, err := io.WriteString(w, line); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := Open(data); err != nil || string(plain) != "first\nsecond\nthird\n" {
+		t.Fatalf("Open() = %q, %v", plain, err)
+	}
+
+	header, fs := frames(t, data)
+	if len(fs) != 5 {
+		t.Fatalf("got %d frames, want 2 writes and a final frame per session", len(fs))
+	}
+
+	// Dropping the final frame leaves the rest readable but reports it
+	truncated := bytes.Join(append([][]byte{header}, fs[:4]...), nil)
+	plain, err := Open(truncated)
+	if !errors.Is(err, ErrTruncated) || string(plain) != "first\nsecond\nthird\n" {
+		t.Errorf("Open() of a truncated log = %q, %v, want the lines and ErrTruncated", plain, err)
+	}
+	if _, err := Open(data[:len(Magic)+saltSize]); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Open() of a header alone error = %v, want ErrTruncated", err)
+	}
+
+	reordered := bytes.Join([][]byte{header, fs[1], fs[0], fs[2], fs[3], fs[4]}, nil)
+	if _, err := Open(reordered); err == nil || errors.Is(err, ErrTruncated) {
+		t.Errorf("Open() of reordered frames error = %v, want a decryption error", err)
+	}
+}
+
+func TestOpenAppend_RefusesMixedFiles(t *testing.T) {
+	dir := t.TempDir()
+	plainPath := filepath.Join(dir, "plain.log")
+	if err := os.WriteFile(plainPath, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	withKey(t, testKey(t, "secret"))
+	if _, err := OpenAppend(plainPath); err == nil {
+		t.Error("OpenAppend() on a plaintext file with encryption on succeeded, want error")
+	}
+
+	encPath := filepath.Join(dir, "enc.log")
+	w, err := OpenAppend(encPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	SetKey(nil)
+	if _, err := OpenAppend(encPath); err == nil {
+		t.Error("OpenAppend() on an encrypted file with encryption off succeeded, want error")
+	}
+}
+
+func TestReader_PlaintextPassthrough(t *testing.T) {
+	for _, in := range []string{"", "ab", "a longer plaintext file\n"} {
+		r, err := NewReader(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("NewReader(%q) error = %v", in, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || string(got) != in {
+			t.Errorf("NewReader(%q) read %q, %v", in, got, err)
+		}
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	if _, err := LoadKey(SourceEnv); err == nil {
+		t.Error("LoadKey(env) without the variable set succeeded, want error")
+	}
+	t.Setenv(KeyEnv, "secret")
+	if _, err := LoadKey(""); err != nil {
+		t.Errorf("LoadKey(\"\") error = %v", err)
+	}
+	if _, err := LoadKey("vault"); err == nil {
+		t.Error("LoadKey(vault) succeeded, want error for unknown source")
+	}
+
+	orig := keychainCommand
+	t.Cleanup(func() { keychainCommand = orig })
+	keychainCommand = func() ([]string, error) { return []string{"echo", "from-keychain"}, nil }
+	k, err := LoadKey(SourceKeychain)
+	if err != nil {
+		t.Fatalf("LoadKey(keychain) error = %v", err)
+	}
+	if want, _ := ParseKey("from-keychain"); *k != want {
+		t.Error("LoadKey(keychain) did not use the keychain output")
+	}
+}
@@ -0,0 +1,70 @@
+package encrypt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Key sources for the [encryption] key_source setting.
+const (
+	// SourceEnv reads the key from KeyEnv.
+	SourceEnv = "env"
+	// SourceKeychain reads the key from the OS keychain.
+	SourceKeychain = "keychain"
+)
+
+// KeychainService is the keychain service name the key is stored under.
+const KeychainService = "orbital"
+
+// KeychainAccount is the keychain account name the key is stored under.
+const KeychainAccount = "encryption-key"
+
+// keychainCommand returns the command that prints the stored key, or an
+// error if the platform has no supported keychain.
+var keychainCommand = func() ([]string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"security", "find-generic-password", "-s", KeychainService, "-a", KeychainAccount, "-w"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"secret-tool", "lookup", "service", KeychainService, "account", KeychainAccount}, nil
+	default:
+		return nil, fmt.Errorf("no supported keychain on %s; use key_source = %q", runtime.GOOS, SourceEnv)
+	}
+}
+
+// LoadKey loads the key from the given source ("env" when empty).
+func LoadKey(source string) (*Key, error) {
+	var raw string
+	switch source {
+	case "", SourceEnv:
+		raw = os.Getenv(KeyEnv)
+		if raw == "" {
+			return nil, fmt.Errorf("encryption is enabled but %s is not set", KeyEnv)
+		}
+	case SourceKeychain:
+		args, err := keychainCommand()
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key from keychain (service %q, account %q): %w", KeychainService, KeychainAccount, err)
+		}
+		raw = strings.TrimSpace(string(out))
+	default:
+		return nil, fmt.Errorf("invalid encryption key source %q (want %q or %q)", source, SourceEnv, SourceKeychain)
+	}
+
+	k, err := ParseKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
@@ -0,0 +1,128 @@
+// Package environment captures the environment a session started in: the
+// orbital and Claude CLI versions, the git commit, the platform and a hash of
+// the configuration. It is kept in the session state and the run record so
+// historical runs can be compared and regressions after an upgrade traced.
+package environment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Snapshot is the environment a session started in.
+type Snapshot struct {
+	// Orbital is the version of orbital that started the session.
+	Orbital string `json:"orbital"`
+
+	// Claude is the output of `claude --version`, or "" if it could not be
+	// run.
+	Claude string `json:"claude,omitempty"`
+
+	// Commit is the git HEAD when the session started (empty outside git).
+	Commit string `json:"commit,omitempty"`
+
+	// OS is the platform as GOOS/GOARCH, e.g. "linux/amd64".
+	OS string `json:"os"`
+
+	// Go is the Go version orbital was built with.
+	Go string `json:"go"`
+
+	// Config is a hash of the effective config file settings (see
+	// HashConfig), or "" without a config file.
+	Config string `json:"config,omitempty"`
+
+	// CapturedAt is when the snapshot was taken.
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// Capture returns a snapshot of the current platform with the given
+// versions, commit and config hash.
+func Capture(orbital, claude, commit, config string) *Snapshot {
+	return &Snapshot{
+		Orbital:    orbital,
+		Claude:     claude,
+		Commit:     commit,
+		OS:         runtime.GOOS + "/" + runtime.GOARCH,
+		Go:         runtime.Version(),
+		Config:     config,
+		CapturedAt: time.Now(),
+	}
+}
+
+// HashConfig returns the first 12 hex digits of the SHA-256 of v's JSON
+// encoding, so two sessions run with the same settings share a hash. Returns
+// "" for nil or a value that cannot be encoded.
+func HashConfig(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// String describes the snapshot on one line, e.g. "orbital 0.1.0, claude
+// 2.0.14 (Claude Code), linux/amd64, commit 3f2a9c1, config 9b1e04c2aa71".
+// Unknown parts are left out.
+func (s *Snapshot) String() string {
+	if s == nil {
+		return ""
+	}
+	parts := []string{"orbital " + s.Orbital}
+	if s.Claude != "" {
+		parts = append(parts, "claude "+s.Claude)
+	}
+	parts = append(parts, s.OS)
+	if s.Commit != "" {
+		parts = append(parts, "commit "+ShortCommit(s.Commit))
+	}
+	if s.Config != "" {
+		parts = append(parts, "config "+s.Config)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Diff lists the parts of the environment that differ between a and b, such
+// as `claude: 2.0.14 -> 2.1.0`. Missing snapshots have nothing to compare.
+func Diff(a, b *Snapshot) []string {
+	if a == nil || b == nil {
+		return nil
+	}
+	var diffs []string
+	for _, f := range []struct{ name, a, b string }{
+		{"orbital", a.Orbital, b.Orbital},
+		{"claude", a.Claude, b.Claude},
+		{"os", a.OS, b.OS},
+		{"go", a.Go, b.Go},
+		{"config", a.Config, b.Config},
+	} {
+		if f.a != f.b {
+			diffs = append(diffs, fmt.Sprintf("%s: %s -> %s", f.name, orUnknown(f.a), orUnknown(f.b)))
+		}
+	}
+	return diffs
+}
+
+// ShortCommit abbreviates a commit hash to 7 characters.
+func ShortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// orUnknown returns s, or "unknown" if it is empty.
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package environment
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	s := Capture("0.1.0", "2.0.14 (Claude Code)", "3f2a9c1d8e7b", "9b1e04c2aa71")
+	if s.OS != runtime.GOOS+"/"+runtime.GOARCH || s.Go != runtime.Version() || s.CapturedAt.IsZero() {
+		t.Errorf("Capture() = %+v, want the current platform and time", s)
+	}
+	want := "orbital 0.1.0, claude 2.0.14 (Claude Code), " + s.OS + ", commit 3f2a9c1, config 9b1e04c2aa71"
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// Unknown parts are left out
+	bare := &Snapshot{Orbital: "0.1.0", OS: "darwin/arm64"}
+	if got := bare.String(); got != "orbital 0.1.0, darwin/arm64" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestHashConfig(t *testing.T) {
+	type config struct{ Thinking string }
+	a := HashConfig(config{Thinking: "show"})
+	if len(a) != 12 || a != HashConfig(config{Thinking: "show"}) {
+		t.Errorf("HashConfig() = %q, want 12 hex digits stable across calls", a)
+	}
+	if a == HashConfig(config{Thinking: "log"}) {
+		t.Error("HashConfig() is the same for different settings")
+	}
+	var none *config
+	if got := HashConfig(none); got != "" {
+		t.Errorf("HashConfig(nil pointer) = %q, want empty", got)
+	}
+	if got := HashConfig(nil); got != "" {
+		t.Errorf("HashConfig(nil) = %q, want empty", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &Snapshot{Orbital: "0.1.0", Claude: "2.0.14", OS: "linux/amd64", Go: "go1.24.0", Commit: "aaa", Config: "111"}
+	b := &Snapshot{Orbital: "0.1.0", Claude: "", OS: "linux/amd64", Go: "go1.24.0", Commit: "bbb", Config: "222"}
+
+	// Commits are expected to move, so they are not reported
+	want := []string{"claude: 2.0.14 -> unknown", "config: 111 -> 222"}
+	if got := Diff(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+	if got := Diff(a, a); got != nil {
+		t.Errorf("Diff() of identical snapshots = %q, want none", got)
+	}
+	if got := Diff(nil, b); got != nil {
+		t.Errorf("Diff() without a snapshot = %q, want none", got)
+	}
+}
@@ -8,3 +8,10 @@ var ErrBudgetExceeded = errors.New("budget exceeded")
 
 // ErrMaxIterationsReached is returned when the maximum number of iterations is reached without completion.
 var ErrMaxIterationsReached = errors.New("max iterations reached")
+
+// ErrNoProgress is returned when consecutive iterations stop making progress.
+var ErrNoProgress = errors.New("no progress")
+
+// ErrCostAnomaly is returned when the user stops a run after repeated
+// cost anomalies.
+var ErrCostAnomaly = errors.New("stopped after cost anomalies")
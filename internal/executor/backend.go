@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+)
+
+// Backend names selectable with --executor.
+const (
+	// BackendClaude runs the Claude CLI (the default).
+	BackendClaude = "claude"
+	// BackendMock replays recorded stream-json fixtures instead of calling Claude.
+	BackendMock = "mock"
+)
+
+// Backend produces the stream-json output of an execution in place of the
+// Claude CLI. The executor handles the lines it writes like claude's stdout:
+// they are redacted, parsed for stats, buffered and streamed.
+type Backend interface {
+	// Stream writes the stream-json output for prompt to w.
+	Stream(ctx context.Context, prompt string, w io.Writer) error
+}
+
+// BackendFactory builds a Backend. dir is the fixture directory from
+// --fixtures; backends that need none ignore it.
+type BackendFactory func(dir string) (Backend, error)
+
+// backendRegistry maps backend names to their factories. BackendClaude is
+// built in and not listed.
+var backendRegistry = map[string]BackendFactory{
+	BackendMock: func(dir string) (Backend, error) {
+		return NewMockBackend(dir)
+	},
+}
+
+// RegisterBackend registers a backend under name, replacing any existing
+// backend with that name.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendRegistry[name] = factory
+}
+
+// Backends returns the selectable backend names in sorted order.
+func Backends() []string {
+	names := []string{BackendClaude}
+	for name := range backendRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// active holds the backend and fixture recorder that executors created by
+// New use, set once at startup by UseBackend and RecordTo.
+var active struct {
+	sync.RWMutex
+	backend  Backend
+	recorder *Recorder
+}
+
+// UseBackend makes executors created afterwards run on the named backend,
+// built with the fixture directory dir. An empty name or BackendClaude
+// restores the Claude CLI.
+func UseBackend(name, dir string) error {
+	var backend Backend
+	if name != "" && name != BackendClaude {
+		factory, ok := backendRegistry[name]
+		if !ok {
+			return fmt.Errorf("unknown executor %q (available: %s)", name, strings.Join(Backends(), ", "))
+		}
+		var err error
+		if backend, err = factory(dir); err != nil {
+			return fmt.Errorf("executor %q: %w", name, err)
+		}
+	}
+	active.Lock()
+	defer active.Unlock()
+	active.backend = backend
+	return nil
+}
+
+// RecordTo makes executors created afterwards save the output of each Claude
+// run as a fixture in dir, for replay with the mock backend. An empty dir
+// stops recording.
+func RecordTo(dir string) error {
+	var recorder *Recorder
+	if dir != "" {
+		var err error
+		if recorder, err = NewRecorder(dir); err != nil {
+			return err
+		}
+	}
+	active.Lock()
+	defer active.Unlock()
+	active.recorder = recorder
+	return nil
+}
+
+// activeBackend returns the backend and recorder set by UseBackend and RecordTo.
+func activeBackend() (Backend, *Recorder) {
+	active.RLock()
+	defer active.RUnlock()
+	return active.backend, active.recorder
+}
+
+// runBackend runs prompt on e.backend, handling its output as execute
+// handles claude's. A backend error is returned, unlike a failed claude
+// run, since it means the backend itself is misconfigured.
+func (e *Executor) runBackend(ctx context.Context, prompt string) (*ExecutionResult, error) {
+	startTime := time.Now()
+	sink := &lineSink{e: e, parser: output.NewParser()}
+	sink.parser.SetQuarantine(e.quarantine)
+	streamErr := e.backend.Stream(ctx, prompt, sink)
+	sink.flush()
+
+	stats := sink.parser.GetStats()
+	outputBytes, _ := truncateOutput(sink.out.Bytes(), e.config.MaxOutputSize)
+	result := &ExecutionResult{
+		Output:              string(outputBytes),
+		Duration:            time.Since(startTime),
+		TokensIn:            stats.TokensIn,
+		TokensOut:           stats.TokensOut,
+		CacheReadTokens:     stats.CacheReadTokens,
+		CacheCreationTokens: stats.CacheCreationTokens,
+		CostUSD:             stats.CostUSD,
+	}
+
+	if ctx.Err() != nil {
+		result.Error = ctx.Err()
+		return result, ctx.Err()
+	}
+	if streamErr != nil {
+		result.ExitCode = 1
+		result.Error = streamErr
+		return result, streamErr
+	}
+	result.Completed = true
+	return result, nil
+}
+
+// lineSink receives a backend's output and handles each complete line.
+type lineSink struct {
+	e       *Executor
+	parser  *output.Parser
+	out     bytes.Buffer
+	pending string
+}
+
+// Write implements io.Writer.
+func (s *lineSink) Write(p []byte) (int, error) {
+	text := s.pending + string(p)
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		s.line(text[:i])
+		text = text[i+1:]
+	}
+	s.pending = text
+	return len(p), nil
+}
+
+// flush handles a final line without a newline.
+func (s *lineSink) flush() {
+	if s.pending != "" {
+		s.line(s.pending)
+		s.pending = ""
+	}
+}
+
+// line redacts, buffers, parses and streams one line of output.
+func (s *lineSink) line(line string) {
+	line = s.e.redactor.Redact(line)
+	s.out.WriteString(line)
+	s.out.WriteString("\n")
+	_, _ = s.parser.ParseLine([]byte(line))
+	if s.e.streamWriter != nil {
+		_, _ = fmt.Fprintln(s.e.streamWriter, line)
+	}
+}
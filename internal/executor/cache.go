@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+// CacheEntry is a cached Claude result.
+type CacheEntry struct {
+	// Model is the model that produced the result.
+	Model string `json:"model"`
+
+	// Output is the result text.
+	Output string `json:"output"`
+
+	// CostUSD, TokensIn and TokensOut are what producing the result cost,
+	// and what reusing it saves.
+	CostUSD   float64 `json:"cost_usd"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+
+	// CreatedAt is when the result was cached.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Cache stores Claude results on disk keyed by model and prompt, so an
+// identical request can be answered without running Claude again.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// CacheDir returns the directory the result cache is kept in for the
+// working directory.
+func CacheDir(workingDir string) string {
+	workingDir = strings.TrimSuffix(workingDir, "/")
+	return filepath.Join(workingDir, ".orbital", "cache")
+}
+
+// NewCache returns a cache in dir whose entries expire after ttl. A ttl of
+// zero or less never expires entries.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// CacheKey returns the key a result is cached under: the SHA-256 of the
+// model and prompt.
+func CacheKey(model, prompt string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached result for prompt on model. Expired and unreadable
+// entries, including encrypted ones without the key, are misses; expired
+// ones are removed.
+func (c *Cache) Get(model, prompt string) (*CacheEntry, bool) {
+	path := c.path(model, prompt)
+	data, err := encrypt.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Model != model {
+		return nil, false
+	}
+	if c.ttl > 0 && c.now().Sub(entry.CreatedAt) > c.ttl {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put caches entry as the result for prompt on model, replacing any earlier
+// entry. The entry is encrypted when encryption at rest is enabled.
+func (c *Cache) Put(model, prompt string, entry CacheEntry) error {
+	entry.Model = model
+	entry.CreatedAt = c.now()
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write and rename so a concurrent Get never reads a partial entry
+	path := c.path(model, prompt)
+	tmp := path + ".tmp"
+	if err := encrypt.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// path returns the file the entry for prompt on model is stored in.
+func (c *Cache) path(model, prompt string) string {
+	return filepath.Join(c.dir, CacheKey(model, prompt)+".json")
+}
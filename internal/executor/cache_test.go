@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+func TestCache_PutGet(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+
+	if _, ok := cache.Get("opus", "plan it"); ok {
+		t.Fatal("Get() hit on an empty cache")
+	}
+	if err := cache.Put("opus", "plan it", CacheEntry{Output: "the plan", CostUSD: 0.42, TokensIn: 100, TokensOut: 50}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	entry, ok := cache.Get("opus", "plan it")
+	if !ok {
+		t.Fatal("Get() missed a cached entry")
+	}
+	if entry.Output != "the plan" || entry.CostUSD != 0.42 || entry.TokensIn != 100 || entry.TokensOut != 50 {
+		t.Errorf("Get() = %+v", entry)
+	}
+
+	tests := []struct {
+		name   string
+		model  string
+		prompt string
+	}{
+		{name: "other model", model: "sonnet", prompt: "plan it"},
+		{name: "other prompt", model: "opus", prompt: "plan it again"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := cache.Get(tt.model, tt.prompt); ok {
+				t.Errorf("Get(%q, %q) hit", tt.model, tt.prompt)
+			}
+		})
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCache(dir, time.Hour)
+	now := time.Date(2026, 1, 24, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Put("opus", "plan it", CacheEntry{Output: "the plan"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	now = now.Add(59 * time.Minute)
+	if _, ok := cache.Get("opus", "plan it"); !ok {
+		t.Fatal("Get() missed an entry within its TTL")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get("opus", "plan it"); ok {
+		t.Fatal("Get() hit an expired entry")
+	}
+	if _, err := os.Stat(cache.path("opus", "plan it")); !os.IsNotExist(err) {
+		t.Errorf("expired entry not removed: %v", err)
+	}
+}
+
+func TestCache_NoTTL(t *testing.T) {
+	cache := NewCache(t.TempDir(), 0)
+	now := time.Date(2026, 1, 24, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Put("opus", "plan it", CacheEntry{Output: "the plan"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	now = now.Add(365 * 24 * time.Hour)
+	if _, ok := cache.Get("opus", "plan it"); !ok {
+		t.Error("Get() missed an entry with no TTL")
+	}
+}
+
+func TestCache_CorruptEntry(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	if err := cache.Put("opus", "plan it", CacheEntry{Output: "the plan"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := os.WriteFile(cache.path("opus", "plan it"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("opus", "plan it"); ok {
+		t.Error("Get() hit a corrupt entry")
+	}
+}
+
+func TestCache_Encrypted(t *testing.T) {
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	dir := t.TempDir()
+	cache := NewCache(dir, time.Hour)
+	if err := cache.Put("opus", "plan it", CacheEntry{Output: "the confidential plan"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(cache.path("opus", "plan it"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypt.IsEncrypted(raw) || strings.Contains(string(raw), "confidential") {
+		t.Fatalf("cache entry = %q, want it encrypted", raw)
+	}
+	if entry, ok := cache.Get("opus", "plan it"); !ok || entry.Output != "the confidential plan" {
+		t.Errorf("Get() = %+v, %v, want the decrypted entry", entry, ok)
+	}
+
+	encrypt.SetKey(nil)
+	t.Setenv(encrypt.KeyEnv, "")
+	if _, ok := cache.Get("opus", "plan it"); ok {
+		t.Error("Get() without the key hit, want a miss")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/flashingpumpkin/orbital/internal/config"
 	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
 )
 
 const (
@@ -48,6 +50,12 @@ type ExecutionResult struct {
 	// TokensOut is the number of output tokens used during execution.
 	TokensOut int
 
+	// CacheReadTokens is the part of TokensIn read from the prompt cache.
+	CacheReadTokens int
+
+	// CacheCreationTokens is the part of TokensIn written to the prompt cache.
+	CacheCreationTokens int
+
 	// CostUSD is the estimated cost in USD for the execution.
 	CostUSD float64
 
@@ -56,22 +64,44 @@ type ExecutionResult struct {
 
 	// Error contains any error that occurred during execution.
 	Error error
+
+	// CPUTime is the user and system CPU time the Claude process used.
+	CPUTime time.Duration
+
+	// PeakRSS is the Claude process's peak resident memory in bytes, or 0
+	// where the OS does not report it.
+	PeakRSS int64
 }
 
 // Executor manages the execution of Claude CLI commands.
 type Executor struct {
 	config       *config.Config
+	model        string
+	maxTurns     int
 	claudeCmd    string
 	streamWriter io.Writer
+	stderrWriter io.Writer
 	verbose      bool
+	pidDir       string
+	onStall      StallCallback
+	redactor     *redact.Redactor
+	backend      Backend
+	recorder     *Recorder
+	quarantine   *output.Quarantine
+	summarise    SummariseFunc
+	onPreflight  PreflightCallback
 }
 
-// New creates a new Executor with the given configuration.
+// New creates a new Executor with the given configuration. It runs on the
+// backend selected by UseBackend, by default the Claude CLI.
 func New(cfg *config.Config) *Executor {
+	backend, recorder := activeBackend()
 	return &Executor{
 		config:    cfg,
 		claudeCmd: "claude",
 		verbose:   cfg.Verbose,
+		backend:   backend,
+		recorder:  recorder,
 	}
 }
 
@@ -80,6 +110,92 @@ func (e *Executor) SetStreamWriter(w io.Writer) {
 	e.streamWriter = w
 }
 
+// AddStreamWriter streams the output to w as well as to the writer set with
+// SetStreamWriter.
+func (e *Executor) AddStreamWriter(w io.Writer) {
+	if e.streamWriter == nil {
+		e.streamWriter = w
+		return
+	}
+	e.streamWriter = io.MultiWriter(e.streamWriter, w)
+}
+
+// SetStderrWriter sends claude's stderr to w, redacted like its output.
+// Without it stderr is discarded.
+func (e *Executor) SetStderrWriter(w io.Writer) {
+	e.stderrWriter = w
+}
+
+// SetRedactor masks secrets in claude's output before it is buffered,
+// parsed or streamed, so nothing downstream sees them.
+func (e *Executor) SetRedactor(r *redact.Redactor) {
+	e.redactor = r
+}
+
+// SetPIDDir enables PID files for claude processes in dir (see PIDDir), so
+// SweepOrphans can clean them up if orbital crashes.
+func (e *Executor) SetPIDDir(dir string) {
+	e.pidDir = dir
+}
+
+// SetQuarantine records the malformed lines in claude's output in q instead
+// of silently skipping them.
+func (e *Executor) SetQuarantine(q *output.Quarantine) {
+	e.quarantine = q
+}
+
+// track records cmd's PID file after it has started and returns a function,
+// to be called once cmd.Wait returns, that kills anything left in the process
+// group and removes the PID file.
+func (e *Executor) track(cmd *exec.Cmd) func() {
+	pid := cmd.Process.Pid
+	var pidFile string
+	if e.pidDir != "" {
+		path, err := writePIDFile(e.pidDir, pid)
+		if err != nil {
+			if e.verbose {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+		} else {
+			pidFile = path
+		}
+	}
+	return func() {
+		killProcessGroup(pid)
+		if pidFile != "" {
+			_ = os.Remove(pidFile)
+		}
+	}
+}
+
+// SetModel overrides the model from config for subsequent executions.
+// Used by the model fallback chain.
+func (e *Executor) SetModel(model string) {
+	e.model = model
+}
+
+// Model returns the model used for executions.
+func (e *Executor) Model() string {
+	if e.model != "" {
+		return e.model
+	}
+	return e.config.Model
+}
+
+// SetMaxTurns overrides the max turns from config for subsequent
+// executions. Zero restores the configured limit. Used by burst mode.
+func (e *Executor) SetMaxTurns(n int) {
+	e.maxTurns = n
+}
+
+// MaxTurns returns the agentic turn limit used for executions (0 = unlimited).
+func (e *Executor) MaxTurns() int {
+	if e.maxTurns > 0 {
+		return e.maxTurns
+	}
+	return e.config.MaxTurns
+}
+
 // GetCommand returns the full command string that would be executed.
 func (e *Executor) GetCommand(prompt string) string {
 	args := e.BuildArgs(prompt)
@@ -95,13 +211,58 @@ func (e *Executor) GetCommand(prompt string) string {
 	return e.claudeCmd + " " + strings.Join(quotedArgs, " ")
 }
 
+// versionTimeout bounds `claude --version`.
+const versionTimeout = 10 * time.Second
+
+// ClaudeVersion returns the first line of `claude --version`.
+func (e *Executor) ClaudeVersion(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, e.claudeCmd, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get claude version: %w", err)
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(version), nil
+}
+
+// managedFlags maps Claude CLI flags that BuildArgs sets to the orbital
+// option that controls them.
+var managedFlags = map[string]string{
+	"-p":                             "orbital always runs claude in print mode",
+	"--print":                        "orbital always runs claude in print mode",
+	"--output-format":                "orbital parses stream-json output",
+	"--verbose":                      "orbital always streams verbose output",
+	"--model":                        "use --model",
+	"--max-budget-usd":               "use --budget",
+	"--dangerously-skip-permissions": "use --dangerous",
+	"-r":                             "use --session-id or orbital continue",
+	"--resume":                       "use --session-id or orbital continue",
+	"--append-system-prompt":         "use --system-prompt",
+	"--max-turns":                    "use --max-turns",
+	"--agents":                       "use --agents or [agents] in config.toml",
+	"--include-partial-messages":     "use stream_text in [tui]",
+}
+
+// ValidateExtraArgs returns an error if args set any flag that orbital
+// manages itself. Both "--flag value" and "--flag=value" forms are checked.
+func ValidateExtraArgs(args []string) error {
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		if hint, ok := managedFlags[flag]; ok {
+			return fmt.Errorf("claude flag %s is managed by orbital (%s)", flag, hint)
+		}
+	}
+	return nil
+}
+
 // BuildArgs constructs the command-line arguments for the Claude CLI.
 func (e *Executor) BuildArgs(prompt string) []string {
 	args := []string{
 		"-p",
 		"--output-format", "stream-json",
 		"--verbose",
-		"--model", e.config.Model,
+		"--model", e.Model(),
 		"--max-budget-usd", fmt.Sprintf("%.2f", e.config.MaxBudget),
 	}
 
@@ -118,28 +279,45 @@ func (e *Executor) BuildArgs(prompt string) []string {
 		args = append(args, "--append-system-prompt", e.config.SystemPrompt)
 	}
 
-	if e.config.MaxTurns > 0 {
-		args = append(args, "--max-turns", fmt.Sprintf("%d", e.config.MaxTurns))
+	if maxTurns := e.MaxTurns(); maxTurns > 0 {
+		args = append(args, "--max-turns", fmt.Sprintf("%d", maxTurns))
 	}
 
 	if e.config.Agents != "" {
 		args = append(args, "--agents", e.config.Agents)
 	}
 
+	if e.config.StreamPartial {
+		args = append(args, "--include-partial-messages")
+	}
+
+	args = append(args, e.config.ClaudeArgs...)
 	args = append(args, prompt)
 
 	return args
 }
 
-// extractStats parses the raw output and extracts token counts and cost.
-// Returns tokensIn, tokensOut, and costUSD.
-func extractStats(rawOutput string) (int, int, float64) {
+// isPartialEvent reports whether line is a partial message event, which the
+// Claude CLI wraps in a stream_event envelope.
+func isPartialEvent(line string) bool {
+	if !strings.Contains(line, `"stream_event"`) {
+		return false
+	}
+	var event struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal([]byte(line), &event) == nil && event.Type == "stream_event"
+}
+
+// extractStats parses the raw output and extracts token counts and cost,
+// quarantining malformed lines in q (which may be nil).
+func extractStats(rawOutput string, q *output.Quarantine) *output.OutputStats {
 	parser := output.NewParser()
+	parser.SetQuarantine(q)
 	for _, line := range strings.Split(rawOutput, "\n") {
 		_, _ = parser.ParseLine([]byte(line))
 	}
-	stats := parser.GetStats()
-	return stats.TokensIn, stats.TokensOut, stats.CostUSD
+	return parser.GetStats()
 }
 
 // truncateOutput truncates output to the specified maximum size, preserving
@@ -172,9 +350,46 @@ func truncateOutput(content []byte, maxSize int) ([]byte, bool) {
 
 // Execute runs the Claude CLI with the given prompt.
 // It respects context cancellation and returns an error if Claude is not in PATH.
+// A prompt too large for the model's context window is refused, trimmed or
+// summarised first, as the preflight action says.
 // If a stream writer is set, output is streamed line-by-line as it arrives.
 // When WorkingDir is set in config, Claude CLI runs in that directory.
+// With a backend set by UseBackend, the backend runs instead of claude.
+// The cost of summarising the prompt is included in the result's cost.
 func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult, error) {
+	prompt, summaryCost, err := e.preflight(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	var result *ExecutionResult
+	if e.backend != nil {
+		result, err = e.runBackend(ctx, prompt)
+	} else {
+		var resources resourceMonitor
+		result, err = e.execute(ctx, prompt, &resources)
+		if result != nil {
+			result.CPUTime, result.PeakRSS = resources.usage()
+			e.record(result.Output)
+		}
+	}
+	if result != nil {
+		result.CostUSD += summaryCost
+	}
+	return result, err
+}
+
+// record saves output as a fixture when recording is on (see RecordTo).
+func (e *Executor) record(output string) {
+	if e.recorder == nil || output == "" {
+		return
+	}
+	if _, err := e.recorder.Save(output); err != nil && e.verbose {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
+// execute runs claude, recording its resource usage on resources.
+func (e *Executor) execute(ctx context.Context, prompt string, resources *resourceMonitor) (*ExecutionResult, error) {
 	// Check if the command exists in PATH
 	cmdPath, err := exec.LookPath(e.claudeCmd)
 	if err != nil {
@@ -182,13 +397,23 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 	}
 
 	args := e.BuildArgs(prompt)
-	cmd := exec.CommandContext(ctx, cmdPath, args...)
+
+	// Kill (or warn about) claude if it goes quiet for the stall timeout
+	runCtx, stall := e.watchStall(ctx)
+	defer stall.stop()
+
+	cmd := exec.CommandContext(runCtx, cmdPath, args...)
+	configureProcessGroup(runCtx, cmd)
 
 	// Set working directory if configured (used for worktree mode)
 	if e.config.WorkingDir != "" && e.config.WorkingDir != "." {
 		cmd.Dir = e.config.WorkingDir
 	}
 
+	if e.stderrWriter != nil {
+		cmd.Stderr = redactWriter{w: e.stderrWriter, r: e.redactor}
+	}
+
 	// Use pipe for streaming if writer is set, otherwise buffer
 	var stdout bytes.Buffer
 
@@ -202,9 +427,12 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 		if err := cmd.Start(); err != nil {
 			return nil, fmt.Errorf("failed to start command: %w", err)
 		}
+		release := e.track(cmd)
+		resources.start(cmd.Process.Pid)
 
 		// Parse output during streaming to avoid double-parsing at the end
 		parser := output.NewParser()
+		parser.SetQuarantine(e.quarantine)
 
 		// Read and stream output line by line
 		scanner := bufio.NewScanner(stdoutPipe)
@@ -218,7 +446,8 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 
 		var scanErr error
 		for scanner.Scan() {
-			line := scanner.Text()
+			stall.touch()
+			line := e.redactor.Redact(scanner.Text())
 			lineLen := len(line)
 
 			// Warn about very large lines that approach the buffer limit
@@ -227,11 +456,16 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 					lineLen, scannerMaxBufSize)
 			}
 
-			stdout.WriteString(line)
-			stdout.WriteString("\n")
+			// Partial message events only feed the live display: the
+			// assistant message that follows them repeats their content
+			partial := e.config.StreamPartial && isPartialEvent(line)
+			if !partial {
+				stdout.WriteString(line)
+				stdout.WriteString("\n")
+			}
 
 			// Check if truncation is needed (only if MaxOutputSize > 0)
-			if maxOutputSize > 0 && stdout.Len() > maxOutputSize {
+			if !partial && maxOutputSize > 0 && stdout.Len() > maxOutputSize {
 				// Truncate from the front to preserve recent content
 				// Keep approximately half the max size to avoid frequent truncation
 				keepSize := maxOutputSize / 2
@@ -278,6 +512,8 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 		}
 
 		runErr := cmd.Wait()
+		release()
+		resources.finish(cmd.ProcessState)
 		duration := time.Since(startTime)
 
 		// Get stats from streaming parser (already parsed, no double-parsing)
@@ -286,26 +522,45 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 		// Handle context cancellation
 		if ctx.Err() != nil {
 			return &ExecutionResult{
-				Output:    stdout.String(),
-				Duration:  duration,
-				TokensIn:  stats.TokensIn,
-				TokensOut: stats.TokensOut,
-				CostUSD:   stats.CostUSD,
-				Completed: false,
-				Error:     ctx.Err(),
+				Output:              stdout.String(),
+				Duration:            duration,
+				TokensIn:            stats.TokensIn,
+				TokensOut:           stats.TokensOut,
+				CacheReadTokens:     stats.CacheReadTokens,
+				CacheCreationTokens: stats.CacheCreationTokens,
+				CostUSD:             stats.CostUSD,
+				Completed:           false,
+				Error:               ctx.Err(),
 			}, ctx.Err()
 		}
 
+		// Handle a stall that killed claude
+		if stallErr := stall.err(runCtx); stallErr != nil {
+			return &ExecutionResult{
+				Output:              stdout.String(),
+				Duration:            duration,
+				TokensIn:            stats.TokensIn,
+				TokensOut:           stats.TokensOut,
+				CacheReadTokens:     stats.CacheReadTokens,
+				CacheCreationTokens: stats.CacheCreationTokens,
+				CostUSD:             stats.CostUSD,
+				Completed:           false,
+				Error:               stallErr,
+			}, stallErr
+		}
+
 		// Handle scanner errors (e.g., line too long)
 		if scanErr != nil {
 			return &ExecutionResult{
-				Output:    stdout.String(),
-				Duration:  duration,
-				TokensIn:  stats.TokensIn,
-				TokensOut: stats.TokensOut,
-				CostUSD:   stats.CostUSD,
-				Completed: false,
-				Error:     scanErr,
+				Output:              stdout.String(),
+				Duration:            duration,
+				TokensIn:            stats.TokensIn,
+				TokensOut:           stats.TokensOut,
+				CacheReadTokens:     stats.CacheReadTokens,
+				CacheCreationTokens: stats.CacheCreationTokens,
+				CostUSD:             stats.CostUSD,
+				Completed:           false,
+				Error:               scanErr,
 			}, scanErr
 		}
 
@@ -316,41 +571,58 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 				exitCode = exitErr.ExitCode()
 			}
 			return &ExecutionResult{
-				Output:    stdout.String(),
-				ExitCode:  exitCode,
-				Duration:  duration,
-				TokensIn:  stats.TokensIn,
-				TokensOut: stats.TokensOut,
-				CostUSD:   stats.CostUSD,
-				Completed: false,
-				Error:     runErr,
+				Output:              stdout.String(),
+				ExitCode:            exitCode,
+				Duration:            duration,
+				TokensIn:            stats.TokensIn,
+				TokensOut:           stats.TokensOut,
+				CacheReadTokens:     stats.CacheReadTokens,
+				CacheCreationTokens: stats.CacheCreationTokens,
+				CostUSD:             stats.CostUSD,
+				Completed:           false,
+				Error:               runErr,
 			}, nil
 		}
 
 		return &ExecutionResult{
-			Output:    stdout.String(),
-			ExitCode:  0,
-			Duration:  duration,
-			TokensIn:  stats.TokensIn,
-			TokensOut: stats.TokensOut,
-			CostUSD:   stats.CostUSD,
-			Completed: true,
-			Error:     nil,
+			Output:              stdout.String(),
+			ExitCode:            0,
+			Duration:            duration,
+			TokensIn:            stats.TokensIn,
+			TokensOut:           stats.TokensOut,
+			CacheReadTokens:     stats.CacheReadTokens,
+			CacheCreationTokens: stats.CacheCreationTokens,
+			CostUSD:             stats.CostUSD,
+			Completed:           true,
+			Error:               nil,
 		}, nil
 	}
 
 	// Non-streaming path: parse once at the end
 	cmd.Stdout = &stdout
+	if stall != nil {
+		cmd.Stdout = activityWriter{w: &stdout, watch: stall}
+	}
 
 	startTime := time.Now()
-	runErr := cmd.Run()
+	runErr := cmd.Start()
+	if runErr == nil {
+		release := e.track(cmd)
+		resources.start(cmd.Process.Pid)
+		runErr = cmd.Wait()
+		release()
+		resources.finish(cmd.ProcessState)
+	}
 	duration := time.Since(startTime)
 
+	// Mask secrets first, so malformed lines reach the quarantine redacted
+	redacted := e.redactor.Redact(stdout.String())
+
 	// Parse output once for stats (parse before truncation to get accurate stats)
-	tokensIn, tokensOut, cost := extractStats(stdout.String())
+	stats := extractStats(redacted, e.quarantine)
 
 	// Apply truncation if configured
-	outputBytes := stdout.Bytes()
+	outputBytes := []byte(redacted)
 	if e.config.MaxOutputSize > 0 {
 		truncatedOutput, wasTruncated := truncateOutput(outputBytes, e.config.MaxOutputSize)
 		if wasTruncated {
@@ -366,16 +638,33 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 	// Handle context cancellation - check this first as it takes priority
 	if ctx.Err() != nil {
 		return &ExecutionResult{
-			Output:    outputStr,
-			Duration:  duration,
-			TokensIn:  tokensIn,
-			TokensOut: tokensOut,
-			CostUSD:   cost,
-			Completed: false,
-			Error:     ctx.Err(),
+			Output:              outputStr,
+			Duration:            duration,
+			TokensIn:            stats.TokensIn,
+			TokensOut:           stats.TokensOut,
+			CacheReadTokens:     stats.CacheReadTokens,
+			CacheCreationTokens: stats.CacheCreationTokens,
+			CostUSD:             stats.CostUSD,
+			Completed:           false,
+			Error:               ctx.Err(),
 		}, ctx.Err()
 	}
 
+	// Handle a stall that killed claude
+	if stallErr := stall.err(runCtx); stallErr != nil {
+		return &ExecutionResult{
+			Output:              outputStr,
+			Duration:            duration,
+			TokensIn:            stats.TokensIn,
+			TokensOut:           stats.TokensOut,
+			CacheReadTokens:     stats.CacheReadTokens,
+			CacheCreationTokens: stats.CacheCreationTokens,
+			CostUSD:             stats.CostUSD,
+			Completed:           false,
+			Error:               stallErr,
+		}, stallErr
+	}
+
 	// Handle command execution error
 	if runErr != nil {
 		exitCode := 1
@@ -383,25 +672,43 @@ func (e *Executor) Execute(ctx context.Context, prompt string) (*ExecutionResult
 			exitCode = exitErr.ExitCode()
 		}
 		return &ExecutionResult{
-			Output:    outputStr,
-			ExitCode:  exitCode,
-			Duration:  duration,
-			TokensIn:  tokensIn,
-			TokensOut: tokensOut,
-			CostUSD:   cost,
-			Completed: false,
-			Error:     runErr,
+			Output:              outputStr,
+			ExitCode:            exitCode,
+			Duration:            duration,
+			TokensIn:            stats.TokensIn,
+			TokensOut:           stats.TokensOut,
+			CacheReadTokens:     stats.CacheReadTokens,
+			CacheCreationTokens: stats.CacheCreationTokens,
+			CostUSD:             stats.CostUSD,
+			Completed:           false,
+			Error:               runErr,
 		}, nil
 	}
 
 	return &ExecutionResult{
-		Output:    outputStr,
-		ExitCode:  0,
-		Duration:  duration,
-		TokensIn:  tokensIn,
-		TokensOut: tokensOut,
-		CostUSD:   cost,
-		Completed: true,
-		Error:     nil,
+		Output:              outputStr,
+		ExitCode:            0,
+		Duration:            duration,
+		TokensIn:            stats.TokensIn,
+		TokensOut:           stats.TokensOut,
+		CacheReadTokens:     stats.CacheReadTokens,
+		CacheCreationTokens: stats.CacheCreationTokens,
+		CostUSD:             stats.CostUSD,
+		Completed:           true,
+		Error:               nil,
 	}, nil
 }
+
+// redactWriter masks secrets in what is written to w.
+type redactWriter struct {
+	w io.Writer
+	r *redact.Redactor
+}
+
+// Write implements io.Writer.
+func (rw redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, rw.r.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,109 @@
+package executor
+
+import "strings"
+
+// DefaultMaxModelFailures is the number of consecutive failures on a model
+// before the chain falls back to the next model.
+const DefaultMaxModelFailures = 2
+
+// rateLimitMarkers are substrings in Claude CLI output that indicate the
+// model is rate limited or overloaded. These trigger an immediate fallback.
+var rateLimitMarkers = []string{
+	"rate_limit_error",
+	"rate limit",
+	"overloaded_error",
+	"usage limit",
+}
+
+// ModelChain tracks a primary model and an ordered list of fallbacks.
+// It advances to the next model after repeated failures or a rate limit.
+type ModelChain struct {
+	models      []string
+	index       int
+	failures    int
+	maxFailures int
+}
+
+// NewModelChain creates a chain starting at primary and falling back through
+// fallbacks in order. Empty and duplicate model names are ignored.
+func NewModelChain(primary string, fallbacks []string) *ModelChain {
+	c := &ModelChain{maxFailures: DefaultMaxModelFailures}
+	seen := make(map[string]bool)
+	for _, m := range append([]string{primary}, fallbacks...) {
+		m = strings.TrimSpace(m)
+		if m == "" || seen[m] {
+			continue
+		}
+		seen[m] = true
+		c.models = append(c.models, m)
+	}
+	return c
+}
+
+// SetMaxFailures sets how many consecutive failures trigger a fallback.
+func (c *ModelChain) SetMaxFailures(n int) {
+	if n > 0 {
+		c.maxFailures = n
+	}
+}
+
+// Current returns the model currently in use.
+func (c *ModelChain) Current() string {
+	if len(c.models) == 0 {
+		return ""
+	}
+	return c.models[c.index]
+}
+
+// HasFallback reports whether there is a model after the current one.
+func (c *ModelChain) HasFallback() bool {
+	return c.index < len(c.models)-1
+}
+
+// RecordSuccess resets the consecutive failure count for the current model.
+func (c *ModelChain) RecordSuccess() {
+	c.failures = 0
+}
+
+// RecordFailure records a failed execution on the current model.
+// A rate limit advances the chain immediately; other failures advance it
+// once the consecutive failure count reaches the limit.
+// Returns true if the chain advanced to the next model.
+func (c *ModelChain) RecordFailure(rateLimited bool) bool {
+	c.failures++
+	if !c.HasFallback() {
+		return false
+	}
+	if !rateLimited && c.failures < c.maxFailures {
+		return false
+	}
+	c.index++
+	c.failures = 0
+	return true
+}
+
+// IsRateLimited reports whether Claude CLI output mentions a rate limit
+// or overloaded model.
+func IsRateLimited(output string) bool {
+	lower := strings.ToLower(output)
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFailure reports whether an execution result should count against the
+// current model. Returns the failure flag and whether it was a rate limit.
+// Rate limit markers are only checked on failed executions, since successful
+// output may legitimately mention rate limits.
+func IsFailure(result *ExecutionResult) (failed bool, rateLimited bool) {
+	if result == nil {
+		return true, false
+	}
+	if result.Completed {
+		return false, false
+	}
+	return true, IsRateLimited(result.Output)
+}
@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+func TestNewModelChain_DeduplicatesAndSkipsEmpty(t *testing.T) {
+	c := NewModelChain("opus", []string{"sonnet", " ", "opus", "haiku"})
+
+	var got []string
+	for {
+		got = append(got, c.Current())
+		if !c.RecordFailure(true) {
+			break
+		}
+	}
+
+	want := []string{"opus", "sonnet", "haiku"}
+	if len(got) != len(want) {
+		t.Fatalf("chain = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chain[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModelChain_RecordFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    []bool // rateLimited flag per failure
+		wantAdvance []bool
+		wantModel   string
+	}{
+		{
+			name:        "rate limit advances immediately",
+			failures:    []bool{true},
+			wantAdvance: []bool{true},
+			wantModel:   "sonnet",
+		},
+		{
+			name:        "single failure does not advance",
+			failures:    []bool{false},
+			wantAdvance: []bool{false},
+			wantModel:   "opus",
+		},
+		{
+			name:        "repeated failures advance",
+			failures:    []bool{false, false},
+			wantAdvance: []bool{false, true},
+			wantModel:   "sonnet",
+		},
+		{
+			name:        "exhausted chain stays on last model",
+			failures:    []bool{true, true, true},
+			wantAdvance: []bool{true, false, false},
+			wantModel:   "sonnet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewModelChain("opus", []string{"sonnet"})
+			for i, rateLimited := range tt.failures {
+				if got := c.RecordFailure(rateLimited); got != tt.wantAdvance[i] {
+					t.Errorf("RecordFailure #%d = %v, want %v", i+1, got, tt.wantAdvance[i])
+				}
+			}
+			if c.Current() != tt.wantModel {
+				t.Errorf("Current() = %q, want %q", c.Current(), tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestModelChain_RecordSuccessResetsFailures(t *testing.T) {
+	c := NewModelChain("opus", []string{"sonnet"})
+
+	c.RecordFailure(false)
+	c.RecordSuccess()
+	if c.RecordFailure(false) {
+		t.Error("RecordFailure() advanced after success reset, want no advance")
+	}
+	if c.Current() != "opus" {
+		t.Errorf("Current() = %q, want opus", c.Current())
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		name            string
+		result          *ExecutionResult
+		wantFailed      bool
+		wantRateLimited bool
+	}{
+		{"nil result", nil, true, false},
+		{"completed", &ExecutionResult{Completed: true}, false, false},
+		{"completed mentioning rate limit", &ExecutionResult{Completed: true, Output: "added a rate limit"}, false, false},
+		{"failed", &ExecutionResult{Output: "boom"}, true, false},
+		{"rate limited", &ExecutionResult{Output: `{"type":"error","error":{"type":"rate_limit_error"}}`}, true, true},
+		{"overloaded", &ExecutionResult{Output: `{"error":{"type":"overloaded_error"}}`}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failed, rateLimited := IsFailure(tt.result)
+			if failed != tt.wantFailed || rateLimited != tt.wantRateLimited {
+				t.Errorf("IsFailure() = (%v, %v), want (%v, %v)", failed, rateLimited, tt.wantFailed, tt.wantRateLimited)
+			}
+		})
+	}
+}
+
+func TestExecutor_SetModelOverridesConfig(t *testing.T) {
+	e := New(&config.Config{Model: "opus", MaxBudget: 1})
+
+	if e.Model() != "opus" {
+		t.Errorf("Model() = %q, want opus", e.Model())
+	}
+
+	e.SetModel("sonnet")
+	args := e.BuildArgs("prompt")
+	for i, arg := range args {
+		if arg == "--model" {
+			if args[i+1] != "sonnet" {
+				t.Errorf("--model = %q, want sonnet", args[i+1])
+			}
+			return
+		}
+	}
+	t.Error("BuildArgs() missing --model")
+}
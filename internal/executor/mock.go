@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+// FixtureExt is the extension of recorded stream-json fixtures.
+const FixtureExt = ".jsonl"
+
+// mockLineDelay is the pause before each replayed line, so a replay streams
+// into the TUI like a live run.
+var mockLineDelay = 20 * time.Millisecond
+
+// MockBackend replays recorded stream-json fixtures: each execution streams
+// the next *.jsonl file of its directory in name order, whatever the prompt.
+// Replays are deterministic as long as executions happen in the order they
+// were recorded.
+type MockBackend struct {
+	mu    sync.Mutex
+	dir   string
+	files []string
+	next  int
+}
+
+// NewMockBackend creates a MockBackend replaying the fixtures in dir.
+func NewMockBackend(dir string) (*MockBackend, error) {
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures (*%s) in %s; record some with --record-fixtures", FixtureExt, dir)
+	}
+	return &MockBackend{dir: dir, files: files}, nil
+}
+
+// Stream implements Backend.
+func (m *MockBackend) Stream(ctx context.Context, _ string, w io.Writer) error {
+	m.mu.Lock()
+	if m.next >= len(m.files) {
+		m.mu.Unlock()
+		return fmt.Errorf("mock executor: all %d fixture(s) in %s have been replayed", len(m.files), m.dir)
+	}
+	path := m.files[m.next]
+	m.next++
+	m.mu.Unlock()
+
+	f, err := encrypt.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("mock executor: %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, scannerInitialBufSize), scannerMaxBufSize)
+	for scanner.Scan() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(mockLineDelay):
+		}
+		if _, err := fmt.Fprintln(w, scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("mock executor: failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// Recorder saves the output of executions as numbered fixtures (001.jsonl,
+// 002.jsonl, ...) for MockBackend. Numbering continues after the fixtures
+// already in its directory.
+type Recorder struct {
+	mu   sync.Mutex
+	dir  string
+	next int
+}
+
+// NewRecorder creates a Recorder writing to dir, creating it if needed.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	files, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	next := 1
+	for _, f := range files {
+		if n, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(f), FixtureExt)); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return &Recorder{dir: dir, next: next}, nil
+}
+
+// Save writes output as the next fixture and returns its path. The fixture
+// is encrypted when encryption at rest is enabled.
+func (r *Recorder) Save(output string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := filepath.Join(r.dir, fmt.Sprintf("%03d%s", r.next, FixtureExt))
+	if err := encrypt.WriteFile(path, []byte(output), 0644); err != nil {
+		return "", fmt.Errorf("failed to record fixture: %w", err)
+	}
+	r.next++
+	return path, nil
+}
+
+// fixtureFiles returns the fixtures in dir in name order.
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), FixtureExt) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
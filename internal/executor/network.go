@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultProbeAddr is the address dialled to check connectivity when
+	// ANTHROPIC_BASE_URL does not name another host.
+	DefaultProbeAddr = "api.anthropic.com:443"
+
+	// offlineBackoffStart is the first wait before probing the network again.
+	offlineBackoffStart = 5 * time.Second
+
+	// offlineBackoffMax caps the wait between probes.
+	offlineBackoffMax = 2 * time.Minute
+
+	// probeTimeout bounds a single connectivity probe.
+	probeTimeout = 10 * time.Second
+)
+
+// ErrOffline is returned when the network stays unreachable for longer than
+// the offline timeout.
+var ErrOffline = errors.New("network unreachable")
+
+// networkFailureMarkers are substrings in Claude CLI output that indicate it
+// could not reach the API at all, as opposed to the API rejecting a request.
+var networkFailureMarkers = []string{
+	"enotfound",
+	"getaddrinfo",
+	"eai_again",
+	"econnrefused",
+	"econnreset",
+	"etimedout",
+	"enetunreach",
+	"ehostunreach",
+	"network is unreachable",
+	"connection refused",
+	"connection error",
+	"fetch failed",
+	"unable to connect to api",
+}
+
+// networkTailLines is how many trailing output lines are searched for
+// network failure markers. Claude reports an unreachable API at the end of
+// its output; earlier lines hold tool results that may mention the same
+// errors about the project's own services.
+const networkTailLines = 3
+
+// IsNetworkFailure reports whether an execution failed because claude could
+// not reach the API. Like rate limits, the markers are only checked on failed
+// executions, since successful output may mention them.
+func IsNetworkFailure(result *ExecutionResult) bool {
+	if result == nil || result.Completed {
+		return false
+	}
+	lower := strings.ToLower(outputTail(result.Output, networkTailLines))
+	if result.Error != nil {
+		lower += "\n" + strings.ToLower(result.Error.Error())
+	}
+	for _, marker := range networkFailureMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// outputTail returns the last n non-blank lines of output.
+func outputTail(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	var tail []string
+	for i := len(lines) - 1; i >= 0 && len(tail) < n; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			tail = append(tail, lines[i])
+		}
+	}
+	return strings.Join(tail, "\n")
+}
+
+// ProbeFunc checks whether the API is reachable, returning nil when it is.
+type ProbeFunc func(ctx context.Context) error
+
+// DialProbe returns a probe that opens a TCP connection to addr, which
+// covers both DNS resolution and a refused connection.
+func DialProbe(addr string) ProbeFunc {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+		defer cancel()
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// ProbeAddr returns the address to probe: the host of ANTHROPIC_BASE_URL
+// when set, otherwise DefaultProbeAddr.
+func ProbeAddr() string {
+	u, err := url.Parse(os.Getenv("ANTHROPIC_BASE_URL"))
+	if err != nil || u.Hostname() == "" {
+		return DefaultProbeAddr
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// OfflineEvent describes a session paused because the network is down.
+type OfflineEvent struct {
+	// Online is set once connectivity is back and the work is about to be
+	// retried.
+	Online bool
+
+	// Attempt is the number of probes made so far.
+	Attempt int
+
+	// Retry is how long until the next probe (only while offline).
+	Retry time.Duration
+
+	// Offline is how long the session has been paused.
+	Offline time.Duration
+
+	// Err is why the network is considered down: the last probe's error.
+	Err error
+}
+
+// String describes the event for display.
+func (e OfflineEvent) String() string {
+	if e.Online {
+		return fmt.Sprintf("Back online after %s; resuming", e.Offline.Round(time.Second))
+	}
+	msg := fmt.Sprintf("Paused: offline, retrying in %s", e.Retry.Round(time.Second))
+	if e.Err != nil {
+		msg += fmt.Sprintf(" (%v)", e.Err)
+	}
+	return msg
+}
+
+// OfflineCallback is called when a session pauses, on each failed probe and
+// when connectivity returns.
+type OfflineCallback func(OfflineEvent)
+
+// OfflineWaiter pauses a session whose executions fail with network errors,
+// probing with exponential backoff until the API is reachable again, so that
+// being offline does not consume iterations or retries.
+type OfflineWaiter struct {
+	probe   ProbeFunc
+	timeout time.Duration
+	onEvent OfflineCallback
+
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	// outage, delay and recovered carry an outage across waits: an execution
+	// that fails again soon after the probe succeeded (a flaky link, or the
+	// API host answering while claude still cannot get through) continues
+	// the backoff and the offline timeout instead of starting over.
+	outage    time.Time
+	delay     time.Duration
+	recovered time.Time
+}
+
+// NewOfflineWaiter creates a waiter that checks connectivity with probe and
+// gives up after timeout offline (0 = wait indefinitely). onEvent may be nil.
+func NewOfflineWaiter(probe ProbeFunc, timeout time.Duration, onEvent OfflineCallback) *OfflineWaiter {
+	return &OfflineWaiter{
+		probe:      probe,
+		timeout:    timeout,
+		onEvent:    onEvent,
+		backoff:    offlineBackoffStart,
+		maxBackoff: offlineBackoffMax,
+	}
+}
+
+// SetBackoff sets the first wait before probing and the cap it doubles up to.
+func (w *OfflineWaiter) SetBackoff(start, max time.Duration) {
+	w.backoff = start
+	w.maxBackoff = max
+}
+
+// Wait blocks until the probe succeeds. It returns ctx's error if ctx is
+// done first, or ErrOffline once the offline timeout has passed.
+func (w *OfflineWaiter) Wait(ctx context.Context) error {
+	now := time.Now()
+	if w.outage.IsZero() || now.Sub(w.recovered) > w.maxBackoff {
+		w.outage = now
+		w.delay = w.backoff
+	}
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		offline := time.Since(w.outage)
+		if w.timeout > 0 && offline+w.delay > w.timeout {
+			w.delay = w.timeout - offline
+			if w.delay <= 0 {
+				w.outage = time.Time{}
+				return fmt.Errorf("%w for %s", ErrOffline, w.timeout)
+			}
+		}
+		w.notify(OfflineEvent{Attempt: attempt - 1, Retry: w.delay, Offline: offline, Err: lastErr})
+
+		timer := time.NewTimer(w.delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		w.delay = min(w.delay*2, w.maxBackoff)
+		if lastErr = w.probe(ctx); lastErr == nil {
+			w.recovered = time.Now()
+			w.notify(OfflineEvent{Online: true, Attempt: attempt, Offline: time.Since(w.outage)})
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (w *OfflineWaiter) notify(e OfflineEvent) {
+	if w.onEvent != nil {
+		w.onEvent(e)
+	}
+}
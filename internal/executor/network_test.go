@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIsNetworkFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *ExecutionResult
+		want   bool
+	}{
+		{name: "nil result", result: nil, want: false},
+		{
+			name:   "completed mentioning a refused connection",
+			result: &ExecutionResult{Completed: true, Output: "connect ECONNREFUSED 127.0.0.1:5432"},
+			want:   false,
+		},
+		{
+			name:   "api connection error",
+			result: &ExecutionResult{Output: `{"type":"result","is_error":true,"result":"API Error: Connection error."}`},
+			want:   true,
+		},
+		{
+			name:   "dns failure",
+			result: &ExecutionResult{Output: "getaddrinfo ENOTFOUND api.anthropic.com\n\n"},
+			want:   true,
+		},
+		{
+			name:   "marker in the error",
+			result: &ExecutionResult{Error: errors.New("dial tcp: connect: network is unreachable")},
+			want:   true,
+		},
+		{
+			name: "marker only in earlier tool output",
+			result: &ExecutionResult{Output: "connect ECONNREFUSED 127.0.0.1:5432\nline\nline\n" +
+				`{"type":"result","is_error":true,"result":"exit"}`},
+			want: false,
+		},
+		{
+			name:   "rate limit",
+			result: &ExecutionResult{Output: "rate_limit_error"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNetworkFailure(tt.result); got != tt.want {
+				t.Errorf("IsNetworkFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeAddr(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{baseURL: "", want: DefaultProbeAddr},
+		{baseURL: "https://proxy.example.com", want: "proxy.example.com:443"},
+		{baseURL: "http://localhost", want: "localhost:80"},
+		{baseURL: "http://localhost:8080/v1", want: "localhost:8080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			t.Setenv("ANTHROPIC_BASE_URL", tt.baseURL)
+			if got := ProbeAddr(); got != tt.want {
+				t.Errorf("ProbeAddr() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialProbe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	if err := DialProbe(addr)(context.Background()); err != nil {
+		t.Errorf("probe of a listening address failed: %v", err)
+	}
+	_ = ln.Close()
+	if err := DialProbe(addr)(context.Background()); err == nil {
+		t.Error("probe of a closed address succeeded")
+	}
+}
+
+// newTestWaiter returns a waiter with millisecond backoff whose probe fails
+// failures times before succeeding.
+func newTestWaiter(failures int, timeout time.Duration) (*OfflineWaiter, *[]OfflineEvent) {
+	var events []OfflineEvent
+	probe := func(context.Context) error {
+		if failures > 0 {
+			failures--
+			return errors.New("no route to host")
+		}
+		return nil
+	}
+	w := NewOfflineWaiter(probe, timeout, func(e OfflineEvent) { events = append(events, e) })
+	w.SetBackoff(time.Millisecond, 4*time.Millisecond)
+	return w, &events
+}
+
+func TestOfflineWaiter_WaitsUntilOnline(t *testing.T) {
+	w, events := newTestWaiter(2, 0)
+
+	if err := w.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	got := *events
+	if len(got) != 4 {
+		t.Fatalf("got %d events, want 3 pauses and 1 recovery: %+v", len(got), got)
+	}
+	wantRetry := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+	for i, want := range wantRetry {
+		if got[i].Online || got[i].Retry != want {
+			t.Errorf("event %d = %+v, want a pause retrying in %s", i, got[i], want)
+		}
+	}
+	if got[1].Err == nil {
+		t.Error("pause after a failed probe has no error")
+	}
+	if last := got[3]; !last.Online || last.Attempt != 3 {
+		t.Errorf("last event = %+v, want online after 3 probes", last)
+	}
+}
+
+func TestOfflineWaiter_Timeout(t *testing.T) {
+	w, _ := newTestWaiter(1000, 10*time.Millisecond)
+
+	err := w.Wait(context.Background())
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Wait() error = %v, want ErrOffline", err)
+	}
+}
+
+func TestOfflineWaiter_Cancelled(t *testing.T) {
+	w, _ := newTestWaiter(1000, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := w.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestOfflineWaiter_ContinuesOutageAfterQuickFailure(t *testing.T) {
+	w, events := newTestWaiter(0, 0)
+	w.SetBackoff(time.Millisecond, time.Hour)
+
+	if err := w.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+	if err := w.Wait(context.Background()); err != nil {
+		t.Fatalf("second Wait() error = %v", err)
+	}
+
+	// The execution failed again right after recovering, so the backoff
+	// carries on from where it was
+	got := *events
+	if len(got) != 4 || got[2].Retry != 2*time.Millisecond {
+		t.Errorf("events = %+v, want the second pause to retry in 2ms", got)
+	}
+}
+
+func TestOfflineEvent_String(t *testing.T) {
+	paused := OfflineEvent{Retry: 10 * time.Second, Err: errors.New("dial tcp: no route to host")}
+	if got, want := paused.String(), "Paused: offline, retrying in 10s (dial tcp: no route to host)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	online := OfflineEvent{Online: true, Offline: 95 * time.Second}
+	if got, want := online.String(), "Back online after 1m35s; resuming"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
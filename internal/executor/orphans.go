@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// pidRecord is written to the PID directory while a claude process runs, so a
+// later run can find it if orbital crashes before cleaning up.
+type pidRecord struct {
+	// PID is the claude process, which also leads its process group.
+	PID int `json:"pid"`
+	// OwnerPID is the orbital process that started it.
+	OwnerPID int `json:"owner_pid"`
+	// Started is when the process was started.
+	Started time.Time `json:"started"`
+}
+
+// PIDDir returns the directory where claude PID files are kept for the
+// working directory.
+func PIDDir(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "pids")
+}
+
+// writePIDFile records pid in dir and returns the file path.
+func writePIDFile(dir string, pid int) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create pid directory: %w", err)
+	}
+	data, err := json.Marshal(pidRecord{PID: pid, OwnerPID: os.Getpid(), Started: time.Now()})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pid file: %w", err)
+	}
+	path := filepath.Join(dir, strconv.Itoa(pid)+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write pid file: %w", err)
+	}
+	return path, nil
+}
+
+// SweepOrphans kills claude process groups recorded in dir whose orbital
+// owner is no longer running, and removes stale PID files. Files owned by a
+// live orbital process are left alone. A recorded PID is only signalled if it
+// still looks like a claude process, guarding against PID reuse. Returns the
+// PIDs that were killed.
+func SweepOrphans(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pid directory: %w", err)
+	}
+
+	var killed []int
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var rec pidRecord
+		if err := json.Unmarshal(data, &rec); err != nil || rec.PID <= 0 {
+			_ = os.Remove(path)
+			continue
+		}
+		if rec.OwnerPID > 0 && rec.OwnerPID != os.Getpid() && isProcessRunning(rec.OwnerPID) {
+			continue
+		}
+
+		if isProcessRunning(rec.PID) && strings.Contains(processCommand(rec.PID), "claude") {
+			if err := signalProcessGroup(rec.PID, syscall.SIGKILL); err != nil {
+				return killed, fmt.Errorf("failed to kill orphaned process %d: %w", rec.PID, err)
+			}
+			killed = append(killed, rec.PID)
+		}
+		_ = os.Remove(path)
+	}
+	return killed, nil
+}
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+// ErrPromptTooLarge is returned when a prompt does not fit the model's
+// context window and the preflight action is refuse.
+var ErrPromptTooLarge = errors.New("prompt too large for the model's context window")
+
+// promptCharsPerToken is the ratio of characters to tokens
+// workflow.EstimateTokens assumes, for cutting a prompt to a number of tokens.
+const promptCharsPerToken = 4
+
+// trimmedPromptMarker replaces the middle of a prompt trimmed to fit the
+// context window. %d is the estimated number of tokens removed.
+const trimmedPromptMarker = "\n\n[~%d tokens trimmed from the middle of this prompt to fit the context window]\n\n"
+
+// summarisedPromptMarker introduces the summary replacing the middle of a
+// prompt. %d is the estimated number of tokens summarised.
+const summarisedPromptMarker = "\n\n[~%d tokens from the middle of this prompt were summarised to fit the context window:]\n%s\n[end of summary]\n\n"
+
+// SummariseFunc summarises text taken out of an oversized prompt, returning
+// the summary and what producing it cost in USD, also when it failed.
+type SummariseFunc func(ctx context.Context, text string) (summary string, cost float64, err error)
+
+// PreflightDecision describes what the preflight did with a prompt too large
+// for the model's context window.
+type PreflightDecision struct {
+	// Model is the model the prompt was for.
+	Model string
+
+	// Action is the action taken: config.PreflightRefuse,
+	// config.PreflightTrim or config.PreflightSummarise.
+	Action string
+
+	// Tokens is the estimated size of the prompt and system prompt, Limit
+	// the most that fits, and After the estimated size sent (0 if refused).
+	Tokens int
+	Limit  int
+	After  int
+
+	// Err is why a summary could not be used; the prompt was trimmed
+	// instead.
+	Err error
+}
+
+// String describes the decision on one line.
+func (d PreflightDecision) String() string {
+	size := fmt.Sprintf("~%d tokens exceeds the %d that fit %s", d.Tokens, d.Limit, d.Model)
+	switch {
+	case d.Action == config.PreflightRefuse:
+		return fmt.Sprintf("Prompt refused: %s", size)
+	case d.Err != nil:
+		return fmt.Sprintf("Prompt trimmed to ~%d tokens: %s (summary failed: %v)", d.After, size, d.Err)
+	case d.Action == config.PreflightSummarise:
+		return fmt.Sprintf("Prompt summarised to ~%d tokens: %s", d.After, size)
+	default:
+		return fmt.Sprintf("Prompt trimmed to ~%d tokens: %s", d.After, size)
+	}
+}
+
+// PreflightCallback is called with every decision the preflight takes.
+type PreflightCallback func(d PreflightDecision)
+
+// SetPreflight sets the function that summarises the middle of oversized
+// prompts with the summarise action, and the callback told about every
+// decision. Without a summariser, summarise trims instead.
+func (e *Executor) SetPreflight(summarise SummariseFunc, cb PreflightCallback) {
+	e.summarise = summarise
+	e.onPreflight = cb
+}
+
+// preflight checks the estimated size of prompt and the system prompt
+// against the model's context window less the response reserve, and
+// applies the preflight action to a prompt that does not fit. It returns the
+// prompt to send and the cost of summarising it.
+func (e *Executor) preflight(ctx context.Context, prompt string) (string, float64, error) {
+	action := e.config.PreflightAction
+	if action == config.PreflightOff {
+		return prompt, 0, nil
+	}
+	if action == "" {
+		action = config.PreflightRefuse
+	}
+
+	model := e.Model()
+	system := workflow.EstimateTokens(e.config.SystemPrompt)
+	tokens := system + workflow.EstimateTokens(prompt)
+	limit := config.GetContextWindow(model) - e.config.EffectivePreflightReserve()
+	if tokens <= limit {
+		return prompt, 0, nil
+	}
+
+	d := PreflightDecision{Model: model, Action: action, Tokens: tokens, Limit: limit}
+	room := limit - system
+	if action == config.PreflightRefuse || room <= 0 {
+		d.Action = config.PreflightRefuse
+		e.reportPreflight(d)
+		return "", 0, fmt.Errorf("%w: ~%d tokens, %d fit %s", ErrPromptTooLarge, tokens, limit, model)
+	}
+
+	var cost float64
+	if action == config.PreflightSummarise && e.summarise != nil {
+		summarised, summaryCost, err := e.summarisePrompt(ctx, prompt, room)
+		cost = summaryCost
+		if err != nil {
+			d.Err = err
+		} else {
+			d.After = system + workflow.EstimateTokens(summarised)
+			e.reportPreflight(d)
+			return summarised, cost, nil
+		}
+	}
+	d.Action = config.PreflightTrim
+	if action == config.PreflightSummarise && d.Err == nil {
+		d.Err = errors.New("no summariser")
+	}
+	trimmed := TrimMiddle(prompt, room)
+	d.After = system + workflow.EstimateTokens(trimmed)
+	e.reportPreflight(d)
+	return trimmed, cost, nil
+}
+
+// summarisePrompt replaces the middle of prompt with a summary so it fits
+// within room tokens. Half of the room is kept as written, split between
+// the start and the end, which hold the instructions. The cost of the
+// summary is returned whether or not it could be used.
+func (e *Executor) summarisePrompt(ctx context.Context, prompt string, room int) (string, float64, error) {
+	head, middle, tail := splitMiddle(prompt, room*promptCharsPerToken/4)
+	summary, cost, err := e.summarise(ctx, middle)
+	if err != nil {
+		return "", cost, err
+	}
+	summarised := head + fmt.Sprintf(summarisedPromptMarker, workflow.EstimateTokens(middle), summary) + tail
+	if workflow.EstimateTokens(summarised) > room {
+		return "", cost, fmt.Errorf("summary of ~%d tokens does not fit", workflow.EstimateTokens(summary))
+	}
+	return summarised, cost, nil
+}
+
+// reportPreflight passes d to the preflight callback, if set.
+func (e *Executor) reportPreflight(d PreflightDecision) {
+	if e.onPreflight != nil {
+		e.onPreflight(d)
+	}
+}
+
+// TrimMiddle cuts the middle of text so that its estimated size is at most
+// tokens, keeping the start and the end, and marks the cut. Text that
+// already fits is returned unchanged.
+func TrimMiddle(text string, tokens int) string {
+	if workflow.EstimateTokens(text) <= tokens {
+		return text
+	}
+	marker := fmt.Sprintf(trimmedPromptMarker, workflow.EstimateTokens(text))
+	keep := (tokens*promptCharsPerToken - len(marker)) / 2
+	if keep <= 0 {
+		return ""
+	}
+	head, middle, tail := splitMiddle(text, keep)
+	return head + fmt.Sprintf(trimmedPromptMarker, workflow.EstimateTokens(middle)) + tail
+}
+
+// splitMiddle splits text into its first and last keep bytes, moved back to
+// rune boundaries, and the middle between them.
+func splitMiddle(text string, keep int) (head, middle, tail string) {
+	start, end := keep, len(text)-keep
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return text[:start], text[start:end], text[end:]
+}
@@ -0,0 +1,191 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+// newPreflightExecutor returns an executor on haiku that leaves 1000 tokens
+// for the prompt.
+func newPreflightExecutor(action string) (*Executor, *[]PreflightDecision) {
+	e := New(&config.Config{
+		Model:            "haiku",
+		PreflightAction:  action,
+		PreflightReserve: config.GetContextWindow("haiku") - 1000,
+	})
+	var decisions []PreflightDecision
+	e.SetPreflight(nil, func(d PreflightDecision) { decisions = append(decisions, d) })
+	return e, &decisions
+}
+
+// oversizedPrompt is ~2000 tokens: instructions at both ends of filler.
+var oversizedPrompt = "START instructions\n" + strings.Repeat("filler ", 1140) + "\nEND instructions"
+
+func TestPreflight_FittingPromptUnchanged(t *testing.T) {
+	for _, action := range []string{"", config.PreflightTrim, config.PreflightSummarise} {
+		e, decisions := newPreflightExecutor(action)
+		got, _, err := e.preflight(context.Background(), "short prompt")
+		if err != nil || got != "short prompt" {
+			t.Errorf("preflight(%q) = %q, %v; want the prompt unchanged", action, got, err)
+		}
+		if len(*decisions) != 0 {
+			t.Errorf("preflight(%q) reported %v for a prompt that fits", action, *decisions)
+		}
+	}
+}
+
+func TestPreflight_Refuse(t *testing.T) {
+	e, decisions := newPreflightExecutor("")
+	_, _, err := e.preflight(context.Background(), oversizedPrompt)
+	if !errors.Is(err, ErrPromptTooLarge) {
+		t.Fatalf("preflight() error = %v, want ErrPromptTooLarge", err)
+	}
+	if len(*decisions) != 1 || (*decisions)[0].Action != config.PreflightRefuse || (*decisions)[0].Limit != 1000 {
+		t.Fatalf("decisions = %+v, want one refusal with a 1000 token limit", *decisions)
+	}
+	if got := (*decisions)[0].String(); !strings.HasPrefix(got, "Prompt refused: ~") || !strings.Contains(got, "1000 that fit haiku") {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Execute refuses before claude is started
+	if _, err := e.Execute(context.Background(), oversizedPrompt); !errors.Is(err, ErrPromptTooLarge) {
+		t.Errorf("Execute() error = %v, want ErrPromptTooLarge", err)
+	}
+}
+
+func TestPreflight_Off(t *testing.T) {
+	e, decisions := newPreflightExecutor(config.PreflightOff)
+	got, _, err := e.preflight(context.Background(), oversizedPrompt)
+	if err != nil || got != oversizedPrompt || len(*decisions) != 0 {
+		t.Errorf("preflight() = %d bytes, %v, %v; want the prompt unchanged and unreported", len(got), err, *decisions)
+	}
+}
+
+func TestPreflight_Trim(t *testing.T) {
+	e, decisions := newPreflightExecutor(config.PreflightTrim)
+	got, _, err := e.preflight(context.Background(), oversizedPrompt)
+	if err != nil {
+		t.Fatalf("preflight() error = %v", err)
+	}
+	if n := workflow.EstimateTokens(got); n > 1000 {
+		t.Errorf("trimmed prompt is ~%d tokens, want at most 1000", n)
+	}
+	if !strings.HasPrefix(got, "START instructions") || !strings.HasSuffix(got, "END instructions") {
+		t.Error("trimmed prompt lost its start or end")
+	}
+	if !strings.Contains(got, "tokens trimmed from the middle of this prompt") {
+		t.Error("trimmed prompt does not mark the cut")
+	}
+	if len(*decisions) != 1 || (*decisions)[0].Action != config.PreflightTrim || (*decisions)[0].After == 0 {
+		t.Errorf("decisions = %+v, want one trim", *decisions)
+	}
+}
+
+func TestPreflight_Summarise(t *testing.T) {
+	e, decisions := newPreflightExecutor(config.PreflightSummarise)
+	var summarised string
+	e.summarise = func(ctx context.Context, text string) (string, float64, error) {
+		summarised = text
+		return "- keep the filler", 0.03, nil
+	}
+
+	got, cost, err := e.preflight(context.Background(), oversizedPrompt)
+	if err != nil {
+		t.Fatalf("preflight() error = %v", err)
+	}
+	if cost != 0.03 {
+		t.Errorf("preflight() cost = %v, want the summary's 0.03", cost)
+	}
+	if !strings.Contains(got, "- keep the filler") || !strings.HasPrefix(got, "START") || !strings.HasSuffix(got, "END instructions") {
+		t.Errorf("summarised prompt = %q, want the start, the summary and the end", got)
+	}
+	if !strings.Contains(oversizedPrompt, summarised) || strings.Contains(summarised, "START") {
+		t.Error("the summariser was not given the middle of the prompt")
+	}
+	if len(*decisions) != 1 || (*decisions)[0].Action != config.PreflightSummarise || (*decisions)[0].Err != nil {
+		t.Errorf("decisions = %+v, want one summary", *decisions)
+	}
+}
+
+func TestExecute_CountsSummaryCost(t *testing.T) {
+	e, _ := newPreflightExecutor(config.PreflightSummarise)
+	e.summarise = func(context.Context, string) (string, float64, error) {
+		return "- keep the filler", 0.03, nil
+	}
+	e.claudeCmd = filepath.Join(t.TempDir(), "claude")
+	script := "#!/bin/sh\necho '{\"type\":\"result\",\"total_cost_usd\":0.02}'\n"
+	if err := os.WriteFile(e.claudeCmd, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := e.Execute(context.Background(), oversizedPrompt)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.CostUSD < 0.0499 || result.CostUSD > 0.0501 {
+		t.Errorf("CostUSD = %v, want the step's 0.02 plus the summary's 0.03", result.CostUSD)
+	}
+}
+
+func TestPreflight_SummariseFallsBackToTrim(t *testing.T) {
+	tests := []struct {
+		name      string
+		summarise SummariseFunc
+		wantErr   string
+		wantCost  float64
+	}{
+		{name: "no summariser", wantErr: "no summariser"},
+		{name: "summary failed", summarise: func(context.Context, string) (string, float64, error) {
+			return "", 0.01, errors.New("rate limited")
+		}, wantErr: "rate limited", wantCost: 0.01},
+		{name: "summary too long", summarise: func(_ context.Context, text string) (string, float64, error) {
+			return text, 0.02, nil
+		}, wantErr: "does not fit", wantCost: 0.02},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, decisions := newPreflightExecutor(config.PreflightSummarise)
+			e.summarise = tt.summarise
+			got, cost, err := e.preflight(context.Background(), oversizedPrompt)
+			if err != nil {
+				t.Fatalf("preflight() error = %v", err)
+			}
+			if cost != tt.wantCost {
+				t.Errorf("preflight() cost = %v, want %v for the failed summary", cost, tt.wantCost)
+			}
+			if !strings.Contains(got, "tokens trimmed from the middle") {
+				t.Error("prompt was not trimmed")
+			}
+			d := (*decisions)[0]
+			if d.Action != config.PreflightTrim || d.Err == nil || !strings.Contains(d.String(), tt.wantErr) {
+				t.Errorf("decision = %q, want a trim noting %q", d.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrimMiddle(t *testing.T) {
+	if got := TrimMiddle("short", 10); got != "short" {
+		t.Errorf("TrimMiddle(short) = %q, want it unchanged", got)
+	}
+
+	// Cuts fall on rune boundaries
+	text := strings.Repeat("é", 2000)
+	got := TrimMiddle(text, 200)
+	if !strings.HasPrefix(got, "é") || !strings.HasSuffix(got, "é") || strings.ContainsRune(got, '�') {
+		t.Errorf("TrimMiddle() split a rune: %q", got[:20])
+	}
+	if strings.ToValidUTF8(got, "?") != got {
+		t.Error("TrimMiddle() produced invalid UTF-8")
+	}
+	if n := workflow.EstimateTokens(got); n > 200 {
+		t.Errorf("TrimMiddle() = ~%d tokens, want at most 200", n)
+	}
+}
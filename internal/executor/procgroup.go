@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// processGroupGracePeriod is how long a cancelled claude process group has to
+// exit after being signalled before it is killed.
+const processGroupGracePeriod = 5 * time.Second
+
+// SignalCause is used as a context cancellation cause to record the signal
+// that interrupted orbital, so the same signal is forwarded to the claude
+// process group.
+type SignalCause struct {
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+func (c SignalCause) Error() string {
+	return "received signal " + c.Signal.String()
+}
+
+// cancelSignal returns the signal to forward to the claude process group when
+// ctx is done: the signal recorded by SignalCause, or SIGTERM otherwise
+// (e.g. on timeout).
+func cancelSignal(ctx context.Context) syscall.Signal {
+	var cause SignalCause
+	if errors.As(context.Cause(ctx), &cause) {
+		if sig, ok := cause.Signal.(syscall.Signal); ok {
+			return sig
+		}
+	}
+	return syscall.SIGTERM
+}
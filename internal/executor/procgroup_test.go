@@ -0,0 +1,179 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+// writeFakeClaude writes an executable script named claude into a temp dir.
+func writeFakeClaude(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// waitFor polls cond until it holds or the deadline passes.
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+func TestCancelSignal(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(SignalCause{Signal: syscall.SIGINT})
+	if got := cancelSignal(ctx); got != syscall.SIGINT {
+		t.Errorf("cancelSignal() = %v, want SIGINT", got)
+	}
+
+	// Derived contexts report the parent's cause
+	child, childCancel := context.WithTimeout(ctx, time.Minute)
+	defer childCancel()
+	if got := cancelSignal(child); got != syscall.SIGINT {
+		t.Errorf("cancelSignal(child) = %v, want SIGINT", got)
+	}
+
+	timedOut, timeoutCancel := context.WithTimeout(context.Background(), 0)
+	defer timeoutCancel()
+	<-timedOut.Done()
+	if got := cancelSignal(timedOut); got != syscall.SIGTERM {
+		t.Errorf("cancelSignal(timeout) = %v, want SIGTERM", got)
+	}
+}
+
+func TestExecute_CancelKillsProcessGroup(t *testing.T) {
+	childPIDFile := filepath.Join(t.TempDir(), "child.pid")
+	script := writeFakeClaude(t, "sleep 30 &\necho $! > "+childPIDFile+"\nsleep 30")
+
+	pidDir := filepath.Join(t.TempDir(), "pids")
+	e := New(&config.Config{Model: "test-model", MaxBudget: 1.00})
+	e.claudeCmd = script
+	e.SetPIDDir(pidDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = e.Execute(ctx, "prompt")
+	}()
+
+	var childPID int
+	if !waitFor(t, func() bool {
+		data, err := os.ReadFile(childPIDFile)
+		if err != nil {
+			return false
+		}
+		childPID, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}) {
+		t.Fatal("fake claude did not start its child")
+	}
+	if entries, _ := os.ReadDir(pidDir); len(entries) != 1 {
+		t.Errorf("pid files while running = %d, want 1", len(entries))
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Execute() did not return after cancellation")
+	}
+
+	if !waitFor(t, func() bool { return !isProcessRunning(childPID) || isZombie(childPID) }) {
+		t.Errorf("child process %d still running after cancellation", childPID)
+	}
+	if entries, _ := os.ReadDir(pidDir); len(entries) != 0 {
+		t.Errorf("pid files after exit = %d, want 0", len(entries))
+	}
+}
+
+// isZombie reports whether pid has exited but not yet been reaped.
+func isZombie(pid int) bool {
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "Z")
+}
+
+func TestSweepOrphans(t *testing.T) {
+	dir := t.TempDir()
+
+	// An orphaned claude process whose orbital owner has exited
+	orphan := exec.Command(writeFakeClaude(t, "sleep 30"))
+	orphan.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := orphan.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = orphan.Wait()
+		close(exited)
+	}()
+	deadOwner := exec.Command("true")
+	if err := deadOwner.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeRecord := func(name string, rec pidRecord) {
+		data, _ := json.Marshal(rec)
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRecord("orphan.json", pidRecord{PID: orphan.Process.Pid, OwnerPID: deadOwner.Process.Pid})
+	// Owned by a live orbital process: left alone
+	writeRecord("live.json", pidRecord{PID: orphan.Process.Pid, OwnerPID: os.Getppid()})
+	// Not a claude process: file removed, process untouched
+	writeRecord("other.json", pidRecord{PID: os.Getpid(), OwnerPID: deadOwner.Process.Pid})
+	if err := os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	killed, err := SweepOrphans(dir)
+	if err != nil {
+		t.Fatalf("SweepOrphans() error = %v", err)
+	}
+	if len(killed) != 1 || killed[0] != orphan.Process.Pid {
+		t.Errorf("SweepOrphans() killed = %v, want [%d]", killed, orphan.Process.Pid)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Error("orphaned process was not killed")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "live.json" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("remaining pid files = %v, want [live.json]", names)
+	}
+}
+
+func TestSweepOrphans_MissingDir(t *testing.T) {
+	killed, err := SweepOrphans(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || killed != nil {
+		t.Errorf("SweepOrphans(missing) = %v, %v, want nil, nil", killed, err)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// configureProcessGroup runs cmd in its own process group so that cancelling
+// ctx signals claude and every process it spawned, not just claude itself.
+// Processes that ignore the signal are killed after processGroupGracePeriod.
+func configureProcessGroup(ctx context.Context, cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return signalProcessGroup(cmd.Process.Pid, cancelSignal(ctx))
+	}
+	cmd.WaitDelay = processGroupGracePeriod
+}
+
+// signalProcessGroup sends sig to every process in the group led by pgid.
+// A group that no longer exists is not an error.
+func signalProcessGroup(pgid int, sig syscall.Signal) error {
+	if err := syscall.Kill(-pgid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+// killProcessGroup kills any processes left in the group led by pgid.
+func killProcessGroup(pgid int) {
+	_ = signalProcessGroup(pgid, syscall.SIGKILL)
+}
+
+// isProcessRunning checks if a process with the given PID is running.
+func isProcessRunning(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+// processCommand returns the command line of a running process, or "" if it
+// cannot be determined.
+func processCommand(pid int) string {
+	out, err := exec.Command("ps", "-o", "args=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package executor
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// configureProcessGroup is a no-op on Windows, where cancelling ctx kills
+// only the claude process itself.
+func configureProcessGroup(ctx context.Context, cmd *exec.Cmd) {}
+
+// signalProcessGroup is not supported on Windows.
+func signalProcessGroup(pgid int, sig syscall.Signal) error {
+	return nil
+}
+
+// killProcessGroup is a no-op on Windows.
+func killProcessGroup(pgid int) {}
+
+// isProcessRunning checks if a process with the given PID is running on Windows.
+func isProcessRunning(pid int) bool {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	syscall.CloseHandle(handle)
+	return true
+}
+
+// processCommand is not supported on Windows; orphans are never matched.
+func processCommand(pid int) string {
+	return ""
+}
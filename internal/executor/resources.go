@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// resourceSampleInterval is how often the claude process's memory is sampled
+// while it runs.
+const resourceSampleInterval = time.Second
+
+// resourceMonitor tracks the machine resources used by one claude process:
+// its resident memory is sampled while it runs (where the OS supports it) and
+// CPU time and peak memory are read from the process accounting on exit.
+type resourceMonitor struct {
+	mu      sync.Mutex
+	peakRSS int64
+	cpuTime time.Duration
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// start begins sampling the process with the given pid.
+func (m *resourceMonitor) start(pid int) {
+	if _, ok := sampleRSS(pid); !ok {
+		return
+	}
+	m.done = make(chan struct{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			m.sample(pid)
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sample records the process's current resident memory.
+func (m *resourceMonitor) sample(pid int) {
+	rss, ok := sampleRSS(pid)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	if rss > m.peakRSS {
+		m.peakRSS = rss
+	}
+	m.mu.Unlock()
+}
+
+// finish stops sampling and records the usage of the exited process. ps may
+// be nil if the process never started or could not be waited for.
+func (m *resourceMonitor) finish(ps *os.ProcessState) {
+	if m.done != nil {
+		close(m.done)
+		m.wg.Wait()
+		m.done = nil
+	}
+	if ps == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cpuTime = ps.UserTime() + ps.SystemTime()
+	if rss := maxRSS(ps); rss > m.peakRSS {
+		m.peakRSS = rss
+	}
+}
+
+// usage returns the CPU time and peak resident memory in bytes.
+func (m *resourceMonitor) usage() (time.Duration, int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cpuTime, m.peakRSS
+}
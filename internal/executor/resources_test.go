@@ -0,0 +1,51 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+func TestExecute_RecordsResourceUsage(t *testing.T) {
+	for _, streaming := range []bool{true, false} {
+		name := "buffered"
+		if streaming {
+			name = "streaming"
+		}
+		t.Run(name, func(t *testing.T) {
+			e := New(&config.Config{Model: "test-model", MaxBudget: 1.00})
+			e.claudeCmd = writeFakeClaude(t, "i=0\nwhile [ $i -lt 50000 ]; do i=$((i+1)); done\necho done")
+			if streaming {
+				e.SetStreamWriter(io.Discard)
+			}
+
+			result, err := e.Execute(context.Background(), "prompt")
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.CPUTime <= 0 {
+				t.Errorf("CPUTime = %v, want > 0", result.CPUTime)
+			}
+			if result.PeakRSS <= 0 {
+				t.Errorf("PeakRSS = %d, want > 0", result.PeakRSS)
+			}
+		})
+	}
+}
+
+func TestResourceMonitor_SamplesRunningProcess(t *testing.T) {
+	if _, ok := sampleRSS(os.Getpid()); !ok {
+		t.Skip("memory sampling not supported on this OS")
+	}
+	var m resourceMonitor
+	m.start(os.Getpid())
+	m.finish(nil)
+	if _, rss := m.usage(); rss <= 0 {
+		t.Errorf("peak RSS = %d, want the sampled memory of this process", rss)
+	}
+}
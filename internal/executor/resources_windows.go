@@ -0,0 +1,11 @@
+//go:build windows
+
+package executor
+
+import "os"
+
+// maxRSS returns 0: peak memory of an exited process is not available on
+// Windows without extra APIs, so only CPU time is reported.
+func maxRSS(ps *os.ProcessState) int64 {
+	return 0
+}
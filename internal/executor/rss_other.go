@@ -0,0 +1,9 @@
+//go:build !linux
+
+package executor
+
+// sampleRSS is not supported outside Linux; peak memory comes from the
+// process accounting on exit instead.
+func sampleRSS(pid int) (int64, bool) {
+	return 0, false
+}
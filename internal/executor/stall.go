@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+// ErrStalled is returned when claude produced no output for the stall timeout
+// and the stall action is retry. The process is killed so the loop can move on
+// to the next iteration instead of waiting out the iteration timeout.
+var ErrStalled = errors.New("claude stalled")
+
+// StallCallback is called when claude has produced no output for the stall
+// timeout. killed reports whether the process is being killed (stall action
+// retry) or only warned about.
+type StallCallback func(idle time.Duration, killed bool)
+
+// SetStallCallback sets the function notified when claude stalls.
+func (e *Executor) SetStallCallback(cb StallCallback) {
+	e.onStall = cb
+}
+
+// stallWatch tracks output activity and acts when none arrives for the
+// configured timeout.
+type stallWatch struct {
+	timeout time.Duration
+	kill    bool
+	cancel  context.CancelCauseFunc
+	onStall StallCallback
+
+	mu     sync.Mutex
+	last   time.Time
+	warned bool
+	done   chan struct{}
+}
+
+// watchStall starts watching for output inactivity on ctx. It returns the
+// context to run claude with and the watch, or a nil watch when stall
+// detection is disabled. The caller must call stop once claude has exited.
+func (e *Executor) watchStall(ctx context.Context) (context.Context, *stallWatch) {
+	if e.config.StallTimeout <= 0 {
+		return ctx, nil
+	}
+	stallCtx, cancel := context.WithCancelCause(ctx)
+	w := &stallWatch{
+		timeout: e.config.StallTimeout,
+		kill:    e.config.StallAction != config.StallActionWarn,
+		cancel:  cancel,
+		onStall: e.onStall,
+		last:    time.Now(),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return stallCtx, w
+}
+
+// run checks for inactivity until stopped. The check interval is a fraction
+// of the timeout so a stall is noticed promptly.
+func (w *stallWatch) run() {
+	interval := w.timeout / 10
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			idle := time.Since(w.last)
+			stalled := idle >= w.timeout && !w.warned
+			if stalled {
+				w.warned = true
+			}
+			w.mu.Unlock()
+			if !stalled {
+				continue
+			}
+			if w.onStall != nil {
+				w.onStall(idle, w.kill)
+			}
+			if w.kill {
+				w.cancel(fmt.Errorf("%w: no output for %s", ErrStalled, idle.Round(time.Second)))
+				return
+			}
+		}
+	}
+}
+
+// touch records output activity. A warning is given again if claude stalls
+// after producing more output.
+func (w *stallWatch) touch() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.last = time.Now()
+	w.warned = false
+	w.mu.Unlock()
+}
+
+// stop ends the watch and releases its context.
+func (w *stallWatch) stop() {
+	if w == nil {
+		return
+	}
+	close(w.done)
+	w.cancel(nil)
+}
+
+// err returns the stall error if the watch killed claude, or nil.
+func (w *stallWatch) err(ctx context.Context) error {
+	if w == nil {
+		return nil
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, ErrStalled) {
+		return cause
+	}
+	return nil
+}
+
+// activityWriter records output activity on a stall watch as it is written.
+type activityWriter struct {
+	w     io.Writer
+	watch *stallWatch
+}
+
+// Write implements io.Writer.
+func (a activityWriter) Write(p []byte) (int, error) {
+	a.watch.touch()
+	return a.w.Write(p)
+}
@@ -0,0 +1,104 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+// stallEvents records StallCallback invocations.
+type stallEvents struct {
+	mu     sync.Mutex
+	killed []bool
+}
+
+func (s *stallEvents) record(_ time.Duration, killed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.killed = append(s.killed, killed)
+}
+
+func (s *stallEvents) get() []bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]bool(nil), s.killed...)
+}
+
+func TestExecute_StallRetryKills(t *testing.T) {
+	for _, streaming := range []bool{true, false} {
+		name := "buffered"
+		if streaming {
+			name = "streaming"
+		}
+		t.Run(name, func(t *testing.T) {
+			e := New(&config.Config{Model: "test-model", MaxBudget: 1.00, StallTimeout: 200 * time.Millisecond})
+			e.claudeCmd = writeFakeClaude(t, "echo started\nsleep 30")
+			if streaming {
+				e.SetStreamWriter(io.Discard)
+			}
+			var events stallEvents
+			e.SetStallCallback(events.record)
+
+			start := time.Now()
+			result, err := e.Execute(context.Background(), "prompt")
+			if !errors.Is(err, ErrStalled) {
+				t.Fatalf("Execute() error = %v, want ErrStalled", err)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("Execute() took %s, want the stalled process killed promptly", elapsed)
+			}
+			if result == nil || result.Completed || !errors.Is(result.Error, ErrStalled) {
+				t.Errorf("result = %+v, want incomplete with ErrStalled", result)
+			}
+			if got := events.get(); len(got) != 1 || !got[0] {
+				t.Errorf("stall callbacks = %v, want one with killed = true", got)
+			}
+		})
+	}
+}
+
+func TestExecute_StallWarnKeepsWaiting(t *testing.T) {
+	e := New(&config.Config{
+		Model:        "test-model",
+		MaxBudget:    1.00,
+		StallTimeout: 100 * time.Millisecond,
+		StallAction:  config.StallActionWarn,
+	})
+	e.claudeCmd = writeFakeClaude(t, "echo started\nsleep 0.5\necho done")
+	e.SetStreamWriter(io.Discard)
+	var events stallEvents
+	e.SetStallCallback(events.record)
+
+	result, err := e.Execute(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Completed {
+		t.Errorf("Completed = false, want the process left to finish")
+	}
+	if got := events.get(); len(got) != 1 || got[0] {
+		t.Errorf("stall callbacks = %v, want one warning with killed = false", got)
+	}
+}
+
+func TestExecute_StallDisabled(t *testing.T) {
+	e := New(&config.Config{Model: "test-model", MaxBudget: 1.00})
+	e.claudeCmd = writeFakeClaude(t, "sleep 0.3\necho done")
+	e.SetStreamWriter(io.Discard)
+	var events stallEvents
+	e.SetStallCallback(events.record)
+
+	if _, err := e.Execute(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := events.get(); len(got) != 0 {
+		t.Errorf("stall callbacks = %v, want none when disabled", got)
+	}
+}
@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maxTurnsSubtype is the result event subtype of an execution that ran out
+// of agentic turns.
+const maxTurnsSubtype = "error_max_turns"
+
+// ResultTurns reads the number of agentic turns an execution took from the
+// result event in its stream-json output, and whether it stopped because it
+// reached the turn limit. turns is 0 when the output has no result event.
+func ResultTurns(output string) (turns int, limitReached bool) {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"result"`) {
+			continue
+		}
+		var event struct {
+			Type     string `json:"type"`
+			Subtype  string `json:"subtype"`
+			NumTurns int    `json:"num_turns"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Type != "result" {
+			continue
+		}
+		return event.NumTurns, event.Subtype == maxTurnsSubtype
+	}
+	return 0, false
+}
@@ -0,0 +1,40 @@
+package executor
+
+import "testing"
+
+func TestResultTurns(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		wantTurns int
+		wantLimit bool
+	}{
+		{
+			name:      "success",
+			output:    `{"type":"assistant","message":{"content":[]}}` + "\n" + `{"type":"result","subtype":"success","num_turns":7,"result":"done"}`,
+			wantTurns: 7,
+		},
+		{
+			name:      "ran out of turns",
+			output:    `{"type":"result","subtype":"error_max_turns","num_turns":20}` + "\n",
+			wantTurns: 20,
+			wantLimit: true,
+		},
+		{
+			name:   "no result event",
+			output: `{"type":"assistant","message":{"content":[{"type":"text","text":"the result is"}]}}`,
+		},
+		{
+			name:   "empty",
+			output: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			turns, limit := ResultTurns(tt.output)
+			if turns != tt.wantTurns || limit != tt.wantLimit {
+				t.Errorf("ResultTurns() = %d, %v; want %d, %v", turns, limit, tt.wantTurns, tt.wantLimit)
+			}
+		})
+	}
+}
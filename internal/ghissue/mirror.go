@@ -0,0 +1,190 @@
+// Package ghissue mirrors a session's task list into a GitHub issue through
+// the gh CLI, so autonomous runs can be followed from the team's tracker.
+package ghissue
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tasks"
+)
+
+// Runner runs gh with args, feeding it stdin, and returns its trimmed stdout.
+type Runner func(ctx context.Context, stdin string, args ...string) (string, error)
+
+// GH returns a Runner for the gh CLI in dir. On failure, the returned error
+// includes gh's stderr output.
+func GH(dir string) Runner {
+	return func(ctx context.Context, stdin string, args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "gh", args...)
+		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader(stdin)
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" {
+				return "", fmt.Errorf("gh %s: %w", strings.Join(args, " "), err)
+			}
+			return "", fmt.Errorf("gh %s: %s: %w", strings.Join(args, " "), msg, err)
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}
+}
+
+// Mirror keeps the body of a GitHub issue in step with the task list the
+// agent maintains through TodoWrite and the task tools. It is an io.Writer
+// for the executor's stream-json output, and Sync writes the list to the
+// issue as a checklist.
+type Mirror struct {
+	run   Runner
+	repo  string
+	title string
+
+	mu      sync.Mutex
+	number  int
+	parser  *output.Parser
+	tracker *tasks.Tracker
+	pending string
+	// synced is the body last written to the issue.
+	synced string
+}
+
+// New creates a Mirror that updates issue number in repo ("owner/name", or
+// empty for the repository gh resolves from its directory). With number 0,
+// an issue titled title is opened on the first Sync.
+func New(run Runner, repo string, number int, title string) *Mirror {
+	return &Mirror{
+		run:     run,
+		repo:    repo,
+		title:   title,
+		number:  number,
+		parser:  output.NewParser(),
+		tracker: tasks.NewTracker(),
+	}
+}
+
+// Number returns the number of the mirrored issue, or 0 before it is opened.
+func (m *Mirror) Number() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.number
+}
+
+// Write records the task tool uses in stream-json output. Partial lines are
+// kept until their newline arrives.
+func (m *Mirror) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	text := m.pending + string(p)
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			break
+		}
+		m.observe(text[:i])
+		text = text[i+1:]
+	}
+	m.pending = text
+	return len(p), nil
+}
+
+// observe feeds one line of output to the task tracker.
+// Must be called with lock held.
+func (m *Mirror) observe(line string) {
+	event, err := m.parser.ParseLine([]byte(line))
+	if err != nil || event == nil || event.ToolName == "" || event.ToolInput == "" {
+		return
+	}
+	m.tracker.ProcessToolUse(event.ToolName, event.ToolInput)
+}
+
+// Sync writes the task list, under status, to the issue, opening it first
+// when it has no number yet. It returns the issue number and whether this
+// call opened it. Nothing is sent when the body has not changed.
+func (m *Mirror) Sync(ctx context.Context, status string) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body := Body(status, m.tracker.GetTasks())
+	if m.number == 0 {
+		args := append([]string{"issue", "create", "--title", m.title, "--body-file", "-"}, m.repoArgs()...)
+		out, err := m.run(ctx, body, args...)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to open issue: %w", err)
+		}
+		number, err := issueNumber(out)
+		if err != nil {
+			return 0, false, err
+		}
+		m.number = number
+		m.synced = body
+		return number, true, nil
+	}
+
+	if body == m.synced {
+		return m.number, false, nil
+	}
+	args := append([]string{"issue", "edit", strconv.Itoa(m.number), "--body-file", "-"}, m.repoArgs()...)
+	if _, err := m.run(ctx, body, args...); err != nil {
+		return m.number, false, fmt.Errorf("failed to update issue #%d: %w", m.number, err)
+	}
+	m.synced = body
+	return m.number, false, nil
+}
+
+// repoArgs selects the repository when one is configured.
+func (m *Mirror) repoArgs() []string {
+	if m.repo == "" {
+		return nil
+	}
+	return []string{"--repo", m.repo}
+}
+
+// issueNumber extracts the number from the issue URL gh prints on create,
+// e.g. https://github.com/owner/name/issues/42.
+func issueNumber(out string) (int, error) {
+	url := strings.TrimSpace(out)
+	if i := strings.LastIndexByte(url, '\n'); i >= 0 {
+		url = url[i+1:]
+	}
+	number, err := strconv.Atoi(url[strings.LastIndexByte(url, '/')+1:])
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("unexpected output from gh issue create: %q", out)
+	}
+	return number, nil
+}
+
+// Body renders the issue body: the status line followed by the tasks as a
+// markdown checklist, with the task in progress marked.
+func Body(status string, list []tasks.Task) string {
+	var b strings.Builder
+	b.WriteString(status)
+	b.WriteString("\n\n")
+	if len(list) == 0 {
+		b.WriteString("_No tasks yet._\n")
+	}
+	for _, task := range list {
+		mark := " "
+		if task.Status == "completed" {
+			mark = "x"
+		}
+		content := strings.ReplaceAll(task.Content, "\n", " ")
+		if task.Status == "in_progress" {
+			content = "**" + content + "** (in progress)"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", mark, content)
+	}
+	b.WriteString("\n<sub>Updated by orbital after each iteration.</sub>\n")
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package ghissue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/tasks"
+)
+
+// ghCall is one recorded invocation of the fake gh.
+type ghCall struct {
+	args  string
+	stdin string
+}
+
+// fakeGH records its calls and answers issue create with an issue URL.
+func fakeGH(calls *[]ghCall, err error) Runner {
+	return func(ctx context.Context, stdin string, args ...string) (string, error) {
+		*calls = append(*calls, ghCall{args: strings.Join(args, " "), stdin: stdin})
+		if err != nil {
+			return "", err
+		}
+		if len(args) > 1 && args[1] == "create" {
+			return "Creating issue in owner/name\n\nhttps://github.com/owner/name/issues/42", nil
+		}
+		return "", nil
+	}
+}
+
+const todoWrite = `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"TodoWrite","input":{"todos":[` +
+	`{"content":"Write tests","status":"completed","activeForm":"Writing tests"},` +
+	`{"content":"Implement parser","status":"in_progress","activeForm":"Implementing parser"},` +
+	`{"content":"Update docs","status":"pending","activeForm":"Updating docs"}]}}]}}`
+
+func TestMirror_OpensThenUpdatesIssue(t *testing.T) {
+	var calls []ghCall
+	m := New(fakeGH(&calls, nil), "owner/name", 0, "Orbital: spec.md")
+
+	number, created, err := m.Sync(context.Background(), "Iteration 1")
+	if err != nil || number != 42 || !created {
+		t.Fatalf("Sync() = %d, %v, %v; want issue 42 opened", number, created, err)
+	}
+	if calls[0].args != "issue create --title Orbital: spec.md --body-file - --repo owner/name" {
+		t.Errorf("create args = %q", calls[0].args)
+	}
+	if !strings.Contains(calls[0].stdin, "_No tasks yet._") {
+		t.Errorf("create body = %q, want no tasks", calls[0].stdin)
+	}
+
+	// Tool uses arrive split across writes
+	if _, err := m.Write([]byte(todoWrite[:40])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Write([]byte(todoWrite[40:] + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Sync(context.Background(), "Iteration 2"); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(calls) != 2 || calls[1].args != "issue edit 42 --body-file - --repo owner/name" {
+		t.Fatalf("calls = %+v, want an edit of issue 42", calls)
+	}
+	for _, want := range []string{"Iteration 2\n", "- [x] Write tests\n", "- [ ] **Implement parser** (in progress)\n", "- [ ] Update docs\n"} {
+		if !strings.Contains(calls[1].stdin, want) {
+			t.Errorf("edit body missing %q:\n%s", want, calls[1].stdin)
+		}
+	}
+
+	// An unchanged body is not sent again
+	if _, _, err := m.Sync(context.Background(), "Iteration 2"); err != nil || len(calls) != 2 {
+		t.Errorf("Sync() with no changes: %d calls, %v", len(calls), err)
+	}
+}
+
+func TestMirror_ExistingIssue(t *testing.T) {
+	var calls []ghCall
+	m := New(fakeGH(&calls, nil), "", 7, "unused")
+	number, created, err := m.Sync(context.Background(), "Iteration 1")
+	if err != nil || number != 7 || created {
+		t.Fatalf("Sync() = %d, %v, %v; want issue 7 updated", number, created, err)
+	}
+	if len(calls) != 1 || calls[0].args != "issue edit 7 --body-file -" {
+		t.Errorf("calls = %+v, want an edit of issue 7 in the default repository", calls)
+	}
+}
+
+func TestMirror_FailedUpdateIsRetried(t *testing.T) {
+	var calls []ghCall
+	m := New(fakeGH(&calls, errors.New("HTTP 502")), "", 7, "")
+	if _, _, err := m.Sync(context.Background(), "Iteration 1"); err == nil || !strings.Contains(err.Error(), "failed to update issue #7") {
+		t.Fatalf("Sync() error = %v, want the update failure", err)
+	}
+	_, _, _ = m.Sync(context.Background(), "Iteration 1")
+	if len(calls) != 2 {
+		t.Errorf("gh called %d times, want the unsent body retried", len(calls))
+	}
+}
+
+func TestIssueNumber(t *testing.T) {
+	tests := []struct {
+		out     string
+		want    int
+		wantErr bool
+	}{
+		{out: "https://github.com/owner/name/issues/42\n", want: 42},
+		{out: "Creating issue\n\nhttps://github.com/o/r/issues/7", want: 7},
+		{out: "", wantErr: true},
+		{out: "https://github.com/owner/name/issues/new", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := issueNumber(tt.out)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("issueNumber(%q) = %d, %v; want %d, error %v", tt.out, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestBody_FlattensMultilineTasks(t *testing.T) {
+	body := Body("Done", []tasks.Task{{Content: "first\nsecond", Status: "pending"}})
+	if !strings.Contains(body, "- [ ] first second\n") {
+		t.Errorf("Body() = %q", body)
+	}
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// BranchWork summarises the work on a branch since it left the branch
+// checked out in the repository.
+type BranchWork struct {
+	// Branch is the summarised branch.
+	Branch string
+	// Base is the branch it is compared against, or the abbreviated HEAD
+	// commit when HEAD is detached.
+	Base string
+	// Commits are the one-line subjects of the branch's own commits, oldest
+	// first.
+	Commits []string
+	// DiffStat is `git diff --stat` output from the merge base to the branch.
+	DiffStat string
+}
+
+// SummariseBranch describes the commits and changed files on branch since
+// its merge base with dir's HEAD.
+func SummariseBranch(ctx context.Context, dir, branch string) (*BranchWork, error) {
+	base, err := Run(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if base == "HEAD" {
+		if base, err = Run(ctx, dir, "rev-parse", "--short", "HEAD"); err != nil {
+			return nil, err
+		}
+	}
+	mergeBase, err := Run(ctx, dir, "merge-base", "HEAD", branch)
+	if err != nil {
+		return nil, err
+	}
+	log, err := Run(ctx, dir, "log", "--reverse", "--format=%h %s", mergeBase+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := Run(ctx, dir, "diff", "--stat", mergeBase, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	work := &BranchWork{Branch: branch, Base: base, DiffStat: stat}
+	if log != "" {
+		work.Commits = strings.Split(log, "\n")
+	}
+	return work, nil
+}
+
+// AddWorktree creates a working tree at path with branch checked out.
+func AddWorktree(ctx context.Context, dir, path, branch string) error {
+	_, err := Run(ctx, dir, "worktree", "add", "-q", path, branch)
+	return err
+}
+
+// TopLevel returns the root of the working tree dir belongs to.
+func TopLevel(ctx context.Context, dir string) (string, error) {
+	return Run(ctx, dir, "rev-parse", "--show-toplevel")
+}
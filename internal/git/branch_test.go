@@ -0,0 +1,97 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummariseBranch(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+	base, err := Run(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two commits on feature/x, then one on the base branch
+	if _, err := Run(ctx, dir, "checkout", "-q", "-b", "feature/x"); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"b.txt", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("work\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", []string{"Add b", "Add c"}[i]}} {
+			if _, err := Run(ctx, dir, args...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := Run(ctx, dir, "checkout", "-q", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, dir, "commit", "-q", "-am", "Base work"); err != nil {
+		t.Fatal(err)
+	}
+
+	work, err := SummariseBranch(ctx, dir, "feature/x")
+	if err != nil {
+		t.Fatalf("SummariseBranch() error = %v", err)
+	}
+	if work.Branch != "feature/x" || work.Base != base {
+		t.Errorf("SummariseBranch() = %s against %s, want feature/x against %s", work.Branch, work.Base, base)
+	}
+	if len(work.Commits) != 2 || !strings.HasSuffix(work.Commits[0], " Add b") || !strings.HasSuffix(work.Commits[1], " Add c") {
+		t.Errorf("Commits = %q, want the branch's two commits oldest first", work.Commits)
+	}
+	if !strings.Contains(work.DiffStat, "b.txt") || !strings.Contains(work.DiffStat, "c.txt") || strings.Contains(work.DiffStat, "a.txt") {
+		t.Errorf("DiffStat = %q, want only the branch's changes", work.DiffStat)
+	}
+
+	if _, err := SummariseBranch(ctx, dir, "no-such-branch"); err == nil {
+		t.Error("SummariseBranch() for unknown branch: expected error")
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+	if _, err := Run(ctx, dir, "branch", "feature"); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "feature")
+	if err := AddWorktree(ctx, dir, path, "feature"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	branch, err := Run(ctx, path, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil || branch != "feature" {
+		t.Errorf("worktree branch = %q, %v; want feature", branch, err)
+	}
+	top, err := TopLevel(ctx, path)
+	if err != nil {
+		t.Fatalf("TopLevel() error = %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(top); got != mustEvalSymlinks(t, path) {
+		t.Errorf("TopLevel() = %q, want %q", top, path)
+	}
+
+	if err := AddWorktree(ctx, dir, filepath.Join(t.TempDir(), "again"), "feature"); err == nil {
+		t.Error("AddWorktree() for a branch checked out elsewhere: expected error")
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
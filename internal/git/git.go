@@ -0,0 +1,162 @@
+// Package git provides thin wrappers around the git CLI used by orbital.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Run executes git with the given arguments in dir and returns trimmed stdout.
+// On failure, the returned error includes git's stderr output.
+func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	return runEnv(ctx, dir, nil, args...)
+}
+
+// runEnv is Run with extra environment variables appended to the process environment.
+func runEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+		}
+		return "", fmt.Errorf("git %s: %s: %w", strings.Join(args, " "), msg, err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsRepo returns true if dir is inside a git work tree.
+func IsRepo(ctx context.Context, dir string) bool {
+	out, err := Run(ctx, dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// HeadCommit returns the full hash of HEAD in dir.
+func HeadCommit(ctx context.Context, dir string) (string, error) {
+	return Run(ctx, dir, "rev-parse", "HEAD")
+}
+
+// ResolveCommit returns the full hash of the commit rev names, such as an
+// abbreviated hash or a branch.
+func ResolveCommit(ctx context.Context, dir, rev string) (string, error) {
+	commit, err := Run(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %q", rev)
+	}
+	return commit, nil
+}
+
+// IsAncestor reports whether ancestor is commit or one of its ancestors.
+func IsAncestor(ctx context.Context, dir, ancestor, commit string) (bool, error) {
+	_, err := Run(ctx, dir, "merge-base", "--is-ancestor", ancestor, commit)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DiffStat returns `git diff --stat` output between the given commit and the
+// working tree, including uncommitted changes.
+func DiffStat(ctx context.Context, dir, from string) (string, error) {
+	return Run(ctx, dir, "diff", "--stat", from)
+}
+
+// ChangedFiles returns the files in dir changed since the given commit that
+// still exist, including uncommitted and untracked files, as sorted paths
+// relative to dir. Ignored files are left out.
+func ChangedFiles(ctx context.Context, dir, from string) ([]string, error) {
+	changed, err := Run(ctx, dir, "diff", "--name-only", "--relative", "--diff-filter=d", from)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := Run(ctx, dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, line := range strings.Split(changed+"\n"+untracked, "\n") {
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		files = append(files, line)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// TreeState returns a description of dir's working tree: the HEAD commit, the
+// status of changed and untracked files, and the diff against HEAD. Equal
+// results mean nothing was committed or changed in between.
+func TreeState(ctx context.Context, dir string) (string, error) {
+	head, err := HeadCommit(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	status, err := Run(ctx, dir, "status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	diff, err := Run(ctx, dir, "diff", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return head + "\n" + status + "\n" + diff, nil
+}
+
+// Worktree is one working tree of a repository.
+type Worktree struct {
+	// Path is the absolute path of the working tree.
+	Path string
+	// Branch is the checked-out branch, or "" when HEAD is detached.
+	Branch string
+}
+
+// Worktrees lists the working trees of the repository dir belongs to, the
+// main working tree first. Bare and prunable entries are left out.
+func Worktrees(ctx context.Context, dir string) ([]Worktree, error) {
+	out, err := Run(ctx, dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	var trees []Worktree
+	for _, block := range strings.Split(out, "\n\n") {
+		var wt Worktree
+		skip := false
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			switch key {
+			case "worktree":
+				wt.Path = value
+			case "branch":
+				wt.Branch = strings.TrimPrefix(value, "refs/heads/")
+			case "bare", "prunable":
+				skip = true
+			}
+		}
+		if wt.Path != "" && !skip {
+			trees = append(trees, wt)
+		}
+	}
+	return trees, nil
+}
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with a single commit and returns its path.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if _, err := Run(ctx, dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, dir, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, dir, "commit", "-q", "-m", "initial"); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestIsRepo(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	if !IsRepo(ctx, dir) {
+		t.Error("IsRepo() = false for git repository, want true")
+	}
+	if IsRepo(ctx, t.TempDir()) {
+		t.Error("IsRepo() = true for plain directory, want false")
+	}
+}
+
+func TestHeadCommit(t *testing.T) {
+	dir := initRepo(t)
+
+	head, err := HeadCommit(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("HeadCommit() error = %v", err)
+	}
+	if len(head) != 40 {
+		t.Errorf("HeadCommit() = %q, want 40-character hash", head)
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	head, err := HeadCommit(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err := DiffStat(ctx, dir, head)
+	if err != nil {
+		t.Fatalf("DiffStat() error = %v", err)
+	}
+	if !strings.Contains(stat, "a.txt") || !strings.Contains(stat, "1 insertion") {
+		t.Errorf("DiffStat() = %q, want change to a.txt", stat)
+	}
+}
+
+func TestTreeState(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	before, err := TreeState(ctx, dir)
+	if err != nil {
+		t.Fatalf("TreeState() error = %v", err)
+	}
+	again, _ := TreeState(ctx, dir)
+	if again != before {
+		t.Error("TreeState() changed without any change to the tree")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modified, _ := TreeState(ctx, dir)
+	if modified == before {
+		t.Error("TreeState() unchanged after modifying a tracked file")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	untracked, _ := TreeState(ctx, dir)
+	if untracked == modified {
+		t.Error("TreeState() unchanged after adding an untracked file")
+	}
+}
+
+func TestWorktrees(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+	linked := filepath.Join(t.TempDir(), "feature")
+	if _, err := Run(ctx, dir, "worktree", "add", "-q", "-b", "feature", linked); err != nil {
+		t.Fatal(err)
+	}
+
+	trees, err := Worktrees(ctx, linked)
+	if err != nil {
+		t.Fatalf("Worktrees() error = %v", err)
+	}
+	if len(trees) != 2 {
+		t.Fatalf("Worktrees() = %v, want 2 working trees", trees)
+	}
+	same := func(a, b string) bool {
+		ra, _ := filepath.EvalSymlinks(a)
+		rb, _ := filepath.EvalSymlinks(b)
+		return ra == rb
+	}
+	if !same(trees[0].Path, dir) {
+		t.Errorf("main working tree = %q, want %q", trees[0].Path, dir)
+	}
+	if !same(trees[1].Path, linked) || trees[1].Branch != "feature" {
+		t.Errorf("linked working tree = %+v, want %s on branch feature", trees[1], linked)
+	}
+
+	if _, err := Worktrees(ctx, t.TempDir()); err == nil {
+		t.Error("Worktrees() outside a repository: expected error")
+	}
+}
+
+func TestRun_ErrorIncludesStderr(t *testing.T) {
+	dir := initRepo(t)
+
+	_, err := Run(context.Background(), dir, "rev-parse", "does-not-exist")
+	if err == nil {
+		t.Fatal("Run() error = nil, want error for unknown revision")
+	}
+	if !strings.Contains(err.Error(), "rev-parse") {
+		t.Errorf("error = %q, want command in message", err.Error())
+	}
+}
+
+func TestResolveCommitAndIsAncestor(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+	first, err := HeadCommit(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, dir, "commit", "-q", "-am", "Second"); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ResolveCommit(ctx, dir, "HEAD")
+	if err != nil {
+		t.Fatalf("ResolveCommit(HEAD) error = %v", err)
+	}
+	if short, err := ResolveCommit(ctx, dir, first[:7]); err != nil || short != first {
+		t.Errorf("ResolveCommit(%s) = %q, %v; want %s", first[:7], short, err, first)
+	}
+	if _, err := ResolveCommit(ctx, dir, "no-such-rev"); err == nil {
+		t.Error("ResolveCommit(no-such-rev) expected error")
+	}
+
+	tests := []struct {
+		ancestor, commit string
+		want             bool
+	}{
+		{ancestor: first, commit: second, want: true},
+		{ancestor: second, commit: second, want: true},
+		{ancestor: second, commit: first, want: false},
+	}
+	for _, tt := range tests {
+		got, err := IsAncestor(ctx, dir, tt.ancestor, tt.commit)
+		if err != nil || got != tt.want {
+			t.Errorf("IsAncestor(%.7s, %.7s) = %v, %v; want %v", tt.ancestor, tt.commit, got, err, tt.want)
+		}
+	}
+	if _, err := IsAncestor(ctx, dir, "no-such-rev", second); err == nil {
+		t.Error("IsAncestor(no-such-rev) expected error")
+	}
+}
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SnapshotRefPrefix is the ref namespace snapshots are stored under. Keeping a
+// ref to each snapshot stops git gc from collecting it.
+const SnapshotRefPrefix = "refs/orbital/snapshots"
+
+// snapshotExclude keeps orbital's own state out of snapshots so a rollback
+// never rewrites the state it is rolling back.
+const snapshotExclude = ":(exclude).orbital"
+
+// snapshotIdentity lets commit-tree work in repositories without a configured user.
+var snapshotIdentity = []string{
+	"GIT_AUTHOR_NAME=orbital",
+	"GIT_AUTHOR_EMAIL=orbital@localhost",
+	"GIT_COMMITTER_NAME=orbital",
+	"GIT_COMMITTER_EMAIL=orbital@localhost",
+}
+
+// SnapshotRef returns the ref name for a session's snapshot of an iteration.
+func SnapshotRef(sessionID string, iteration int) string {
+	return fmt.Sprintf("%s/%s/%d", SnapshotRefPrefix, sessionID, iteration)
+}
+
+// CreateSnapshot records the working tree in dir, including uncommitted and
+// untracked (but not ignored) files, as a commit whose parent is HEAD. The
+// index and working tree are left untouched. The commit is stored under ref
+// and its hash returned.
+func CreateSnapshot(ctx context.Context, dir, ref, message string) (string, error) {
+	head, err := HeadCommit(ctx, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	// Stage into a throwaway index so the user's index is not disturbed
+	index, err := os.CreateTemp("", "orbital-snapshot-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	indexPath := index.Name()
+	_ = index.Close()
+	_ = os.Remove(indexPath) // git refuses to read an empty index file
+	defer func() { _ = os.Remove(indexPath) }()
+
+	env := append([]string{"GIT_INDEX_FILE=" + indexPath}, snapshotIdentity...)
+
+	if _, err := runEnv(ctx, dir, env, "read-tree", head); err != nil {
+		return "", err
+	}
+	if _, err := runEnv(ctx, dir, env, "add", "-A", "--", ".", snapshotExclude); err != nil {
+		return "", err
+	}
+	tree, err := runEnv(ctx, dir, env, "write-tree")
+	if err != nil {
+		return "", err
+	}
+	commit, err := runEnv(ctx, dir, env, "commit-tree", tree, "-p", head, "-m", message)
+	if err != nil {
+		return "", err
+	}
+	if _, err := Run(ctx, dir, "update-ref", ref, commit); err != nil {
+		return "", err
+	}
+
+	return commit, nil
+}
+
+// RestoreSnapshot resets HEAD to the commit the snapshot was taken on and
+// makes the working tree match the snapshot exactly. Files created since the
+// snapshot are removed; ignored files and uncommitted orbital state are left
+// alone. Orbital state that was committed after the snapshot is removed with
+// that commit, so callers should save their state again afterwards.
+// The snapshot's changes are left unstaged, as they were when it was taken.
+func RestoreSnapshot(ctx context.Context, dir, commit string) error {
+	parent, err := Run(ctx, dir, "rev-parse", commit+"^")
+	if err != nil {
+		return fmt.Errorf("failed to resolve snapshot parent: %w", err)
+	}
+
+	// Stage everything first so reset --hard also removes files created since the snapshot
+	if _, err := Run(ctx, dir, "add", "-A", "--", ".", snapshotExclude); err != nil {
+		return err
+	}
+	if _, err := Run(ctx, dir, "reset", "-q", "--hard", parent); err != nil {
+		return err
+	}
+	if _, err := Run(ctx, dir, "read-tree", "-u", "--reset", commit); err != nil {
+		return err
+	}
+	if _, err := Run(ctx, dir, "reset", "-q"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSnapshots removes the snapshot refs of a session. Iterations at or
+// below keep are retained; pass a negative keep to delete them all.
+func DeleteSnapshots(ctx context.Context, dir, sessionID string, keep int) error {
+	prefix := SnapshotRefPrefix + "/" + sessionID + "/"
+	out, err := Run(ctx, dir, "for-each-ref", "--format=%(refname)", prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range strings.Fields(out) {
+		var iteration int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(ref, prefix), "%d", &iteration); err == nil && iteration <= keep {
+			continue
+		}
+		if _, err := Run(ctx, dir, "update-ref", "-d", ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
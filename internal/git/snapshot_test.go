@@ -0,0 +1,107 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateAndRestoreSnapshot(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	// Uncommitted edit and untracked file at snapshot time
+	writeFile(t, dir, "a.txt", "two\n")
+	writeFile(t, dir, "b.txt", "new\n")
+	writeFile(t, dir, ".orbital/state/state.json", "{}")
+
+	ref := SnapshotRef("sess", 1)
+	commit, err := CreateSnapshot(ctx, dir, ref, "iteration 1")
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if got, _ := Run(ctx, dir, "rev-parse", ref); got != commit {
+		t.Errorf("ref %s = %q, want %q", ref, got, commit)
+	}
+	if staged, _ := Run(ctx, dir, "diff", "--cached", "--name-only"); staged != "" {
+		t.Errorf("CreateSnapshot() staged files in the user's index: %q", staged)
+	}
+
+	// Later changes: edit, delete, new file, and a commit
+	writeFile(t, dir, "a.txt", "three\n")
+	if err := os.Remove(filepath.Join(dir, "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "c.txt", "later\n")
+	if _, err := Run(ctx, dir, "add", "--", "a.txt", "c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(ctx, dir, "commit", "-q", "-m", "later"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, ".orbital/state/state.json", `{"iteration":3}`)
+
+	if err := RestoreSnapshot(ctx, dir, commit); err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+
+	assertFile(t, dir, "a.txt", "two\n")
+	assertFile(t, dir, "b.txt", "new\n")
+	assertFile(t, dir, ".orbital/state/state.json", `{"iteration":3}`)
+	if _, err := os.Stat(filepath.Join(dir, "c.txt")); !os.IsNotExist(err) {
+		t.Error("c.txt created after the snapshot should have been removed")
+	}
+	if head, _ := Run(ctx, dir, "log", "-1", "--format=%s"); head != "initial" {
+		t.Errorf("HEAD = %q, want commit the snapshot was taken on", head)
+	}
+}
+
+func TestDeleteSnapshots(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	for i := 0; i <= 3; i++ {
+		if _, err := CreateSnapshot(ctx, dir, SnapshotRef("sess", i), "snapshot"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := DeleteSnapshots(ctx, dir, "sess", 1); err != nil {
+		t.Fatalf("DeleteSnapshots() error = %v", err)
+	}
+	refs, _ := Run(ctx, dir, "for-each-ref", "--format=%(refname)", SnapshotRefPrefix)
+	want := SnapshotRef("sess", 0) + "\n" + SnapshotRef("sess", 1)
+	if refs != want {
+		t.Errorf("remaining refs = %q, want %q", refs, want)
+	}
+
+	if err := DeleteSnapshots(ctx, dir, "sess", -1); err != nil {
+		t.Fatalf("DeleteSnapshots() error = %v", err)
+	}
+	if refs, _ := Run(ctx, dir, "for-each-ref", SnapshotRefPrefix); refs != "" {
+		t.Errorf("expected all refs deleted, got %q", refs)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, dir, name, want string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", name, data, want)
+	}
+}
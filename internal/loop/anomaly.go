@@ -0,0 +1,151 @@
+package loop
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// Cost anomaly actions selectable in [cost_anomaly].
+const (
+	// CostAnomalyWarn reports anomalous iterations and keeps going.
+	CostAnomalyWarn = "warn"
+	// CostAnomalyPause reports anomalies and asks for confirmation once they
+	// repeat.
+	CostAnomalyPause = "pause"
+	// CostAnomalyOff disables the monitor.
+	CostAnomalyOff = "off"
+)
+
+// DefaultAnomalyFactor is the multiple of the baseline cost at which an
+// iteration counts as anomalous.
+const DefaultAnomalyFactor = 3.0
+
+// DefaultAnomalyWindow is the number of recent iterations the baseline is
+// computed from.
+const DefaultAnomalyWindow = 5
+
+// DefaultAnomalyRepeat is the number of consecutive anomalous iterations
+// after which the pause action asks for confirmation.
+const DefaultAnomalyRepeat = 2
+
+// anomalyMinSamples is the number of iterations needed before a baseline is
+// trusted.
+const anomalyMinSamples = 2
+
+// CostAnomaly describes an iteration that cost far more than usual.
+type CostAnomaly struct {
+	// Iteration is the anomalous iteration.
+	Iteration int
+
+	// Cost is what the iteration cost in USD.
+	Cost float64
+
+	// Baseline is the median cost of recent ordinary iterations.
+	Baseline float64
+
+	// Repeats is the number of consecutive anomalous iterations, including
+	// this one.
+	Repeats int
+
+	// Pause is true when the run should stop until the user confirms.
+	Pause bool
+}
+
+// Ratio returns the iteration's cost as a multiple of the baseline.
+func (a CostAnomaly) Ratio() float64 {
+	return a.Cost / a.Baseline
+}
+
+// String describes the anomaly, e.g. "iteration 6 cost $4.20, 3.5× the
+// $1.20 baseline".
+func (a CostAnomaly) String() string {
+	return fmt.Sprintf("iteration %d cost %s, %.1f× the %s baseline",
+		a.Iteration, util.FormatCost(a.Cost, 2), a.Ratio(), util.FormatCost(a.Baseline, 2))
+}
+
+// CostMonitor flags iterations whose cost exceeds a multiple of the rolling
+// baseline, often a sign the agent is reading whole files into context.
+// Anomalous iterations are left out of the baseline so a run of them keeps
+// being reported.
+type CostMonitor struct {
+	factor  float64
+	window  int
+	action  string
+	repeat  int
+	costs   []float64
+	repeats int
+}
+
+// NewCostMonitor creates a monitor. Zero values use DefaultAnomalyFactor,
+// DefaultAnomalyWindow, DefaultAnomalyRepeat and CostAnomalyWarn.
+func NewCostMonitor(factor float64, window, repeat int, action string) (*CostMonitor, error) {
+	switch {
+	case factor == 0:
+		factor = DefaultAnomalyFactor
+	case factor <= 1:
+		return nil, fmt.Errorf("invalid cost_anomaly factor %g (must be greater than 1)", factor)
+	}
+	switch {
+	case window == 0:
+		window = DefaultAnomalyWindow
+	case window < anomalyMinSamples:
+		return nil, fmt.Errorf("invalid cost_anomaly window %d (must be at least %d)", window, anomalyMinSamples)
+	}
+	switch {
+	case repeat == 0:
+		repeat = DefaultAnomalyRepeat
+	case repeat < 0:
+		return nil, fmt.Errorf("invalid cost_anomaly repeat %d (must be positive)", repeat)
+	}
+	switch action {
+	case "":
+		action = CostAnomalyWarn
+	case CostAnomalyWarn, CostAnomalyPause, CostAnomalyOff:
+	default:
+		return nil, fmt.Errorf("invalid cost_anomaly action %q (valid: %s, %s, %s)", action, CostAnomalyWarn, CostAnomalyPause, CostAnomalyOff)
+	}
+	return &CostMonitor{factor: factor, window: window, action: action, repeat: repeat}, nil
+}
+
+// Observe records an iteration's cost and returns the anomaly it represents,
+// or nil. Iterations that cost nothing are ignored.
+func (m *CostMonitor) Observe(iteration int, cost float64) *CostAnomaly {
+	if m == nil || m.action == CostAnomalyOff || cost <= 0 {
+		return nil
+	}
+
+	baseline, ok := m.Baseline()
+	if ok && cost > baseline*m.factor {
+		m.repeats++
+		a := &CostAnomaly{Iteration: iteration, Cost: cost, Baseline: baseline, Repeats: m.repeats}
+		if m.action == CostAnomalyPause && m.repeats >= m.repeat {
+			a.Pause = true
+			m.repeats = 0
+		}
+		return a
+	}
+
+	m.repeats = 0
+	m.costs = append(m.costs, cost)
+	if len(m.costs) > m.window {
+		m.costs = m.costs[len(m.costs)-m.window:]
+	}
+	return nil
+}
+
+// Baseline returns the median cost of the recent ordinary iterations. It
+// reports false until enough iterations have been observed.
+func (m *CostMonitor) Baseline() (float64, bool) {
+	if m == nil || len(m.costs) < anomalyMinSamples {
+		return 0, false
+	}
+	sorted := append([]float64(nil), m.costs...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2, true
+	}
+	return sorted[mid], true
+}
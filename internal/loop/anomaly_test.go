@@ -0,0 +1,114 @@
+package loop
+
+import (
+	"testing"
+)
+
+func TestNewCostMonitor_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		factor  float64
+		window  int
+		repeat  int
+		action  string
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "custom", factor: 2.5, window: 10, repeat: 3, action: CostAnomalyPause},
+		{name: "factor too low", factor: 1, wantErr: true},
+		{name: "window too small", window: 1, wantErr: true},
+		{name: "negative repeat", repeat: -1, wantErr: true},
+		{name: "unknown action", action: "explode", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCostMonitor(tt.factor, tt.window, tt.repeat, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCostMonitor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCostMonitor_Observe(t *testing.T) {
+	m, err := NewCostMonitor(0, 0, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No baseline until two iterations have been seen
+	if a := m.Observe(1, 1.00); a != nil {
+		t.Errorf("first iteration flagged: %v", a)
+	}
+	if a := m.Observe(2, 10.00); a != nil {
+		t.Errorf("second iteration flagged before a baseline exists: %v", a)
+	}
+	if a := m.Observe(3, 0); a != nil {
+		t.Errorf("zero-cost iteration flagged: %v", a)
+	}
+
+	// Baseline is the median of 1.00 and 10.00
+	if b, ok := m.Baseline(); !ok || !floatEquals(b, 5.50) {
+		t.Errorf("Baseline() = %v, %v, want 5.50", b, ok)
+	}
+	a := m.Observe(4, 20.00)
+	if a == nil {
+		t.Fatal("20.00 against a 5.50 baseline was not flagged")
+	}
+	if a.Iteration != 4 || a.Repeats != 1 || a.Pause {
+		t.Errorf("anomaly = %+v", a)
+	}
+	if got, want := a.String(), "iteration 4 cost $20.00, 3.6× the $5.50 baseline"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// The anomaly is left out of the baseline
+	if b, _ := m.Baseline(); !floatEquals(b, 5.50) {
+		t.Errorf("Baseline() after anomaly = %v, want 5.50", b)
+	}
+}
+
+func TestCostMonitor_RollingWindow(t *testing.T) {
+	m, _ := NewCostMonitor(3, 3, 0, "")
+	for i, cost := range []float64{9, 9, 1, 1, 1} {
+		m.Observe(i+1, cost)
+	}
+	if b, _ := m.Baseline(); !floatEquals(b, 1) {
+		t.Errorf("Baseline() = %v, want 1 from the last three iterations", b)
+	}
+}
+
+func TestCostMonitor_PausesWhenAnomaliesRepeat(t *testing.T) {
+	m, _ := NewCostMonitor(0, 0, 2, CostAnomalyPause)
+	m.Observe(1, 1)
+	m.Observe(2, 1)
+
+	if a := m.Observe(3, 5); a == nil || a.Pause {
+		t.Fatalf("first anomaly = %+v, want a warning without pause", a)
+	}
+	if a := m.Observe(4, 5); a == nil || !a.Pause || a.Repeats != 2 {
+		t.Fatalf("second anomaly = %+v, want a pause", a)
+	}
+	// The count restarts after a pause
+	if a := m.Observe(5, 5); a == nil || a.Pause {
+		t.Errorf("anomaly after pause = %+v, want a warning", a)
+	}
+	// An ordinary iteration resets the count
+	m.Observe(6, 1)
+	if a := m.Observe(7, 5); a == nil || a.Repeats != 1 {
+		t.Errorf("anomaly after ordinary iteration = %+v, want repeats 1", a)
+	}
+}
+
+func TestCostMonitor_OffAndNil(t *testing.T) {
+	var nilMonitor *CostMonitor
+	if a := nilMonitor.Observe(1, 100); a != nil {
+		t.Errorf("nil monitor flagged %v", a)
+	}
+	m, _ := NewCostMonitor(0, 0, 0, CostAnomalyOff)
+	for i, cost := range []float64{1, 1, 100} {
+		if a := m.Observe(i+1, cost); a != nil {
+			t.Errorf("disabled monitor flagged %v", a)
+		}
+	}
+}
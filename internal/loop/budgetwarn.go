@@ -0,0 +1,75 @@
+package loop
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// BudgetWarning is a soft warning that spend passed a fraction of the budget.
+type BudgetWarning struct {
+	// Fraction is the highest threshold passed, e.g. 0.8.
+	Fraction float64
+
+	// Spent is the spend in USD when the threshold was passed.
+	Spent float64
+
+	// Budget is the hard budget cap in USD.
+	Budget float64
+}
+
+// String describes the warning, e.g. "spent $82.10 of the $100.00 budget
+// (passed 80%)".
+func (w BudgetWarning) String() string {
+	return fmt.Sprintf("spent %s of the %s budget (passed %s)",
+		util.FormatCost(w.Spent, 2), util.FormatCost(w.Budget, 2), formatPercent(w.Fraction))
+}
+
+// BudgetWarner fires soft warnings as spend passes each threshold fraction
+// of the budget, once per threshold. They are separate from the hard stop
+// at the budget itself.
+type BudgetWarner struct {
+	thresholds []float64
+	passed     int
+}
+
+// NewBudgetWarner creates a BudgetWarner for the given fractions of the
+// budget. Duplicates are ignored; a nil warner never warns.
+func NewBudgetWarner(fractions []float64) *BudgetWarner {
+	thresholds := append([]float64(nil), fractions...)
+	sort.Float64s(thresholds)
+	unique := thresholds[:0]
+	for i, f := range thresholds {
+		if i == 0 || f != thresholds[i-1] {
+			unique = append(unique, f)
+		}
+	}
+	return &BudgetWarner{thresholds: unique}
+}
+
+// Observe records the spend so far and returns a warning when it passed a
+// threshold not passed before. Passing several at once warns once, for the
+// highest.
+func (b *BudgetWarner) Observe(spent, budget float64) *BudgetWarning {
+	if b == nil || budget <= 0 {
+		return nil
+	}
+	passed := b.passed
+	for passed < len(b.thresholds) && spent >= budget*b.thresholds[passed] {
+		passed++
+	}
+	if passed == b.passed {
+		return nil
+	}
+	b.passed = passed
+	return &BudgetWarning{Fraction: b.thresholds[passed-1], Spent: spent, Budget: budget}
+}
+
+// formatPercent formats a fraction as a percentage, e.g. 0.8 as "80%" and
+// 0.125 as "12.5%".
+func formatPercent(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/10, 'f', -1, 64) + "%"
+}
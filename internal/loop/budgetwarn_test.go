@@ -0,0 +1,56 @@
+package loop
+
+import "testing"
+
+func TestBudgetWarner_Observe(t *testing.T) {
+	warner := NewBudgetWarner([]float64{0.8, 0.5, 0.8, 0.9})
+	tests := []struct {
+		spent float64
+		want  float64 // 0 means no warning
+	}{
+		{10, 0},
+		{49.99, 0},
+		{50, 0.5},
+		{60, 0},   // 50% already passed
+		{95, 0.9}, // passing 80% and 90% at once warns once
+		{99, 0},
+	}
+	for _, tt := range tests {
+		w := warner.Observe(tt.spent, 100)
+		switch {
+		case tt.want == 0 && w != nil:
+			t.Errorf("Observe(%g) = %+v, want no warning", tt.spent, *w)
+		case tt.want != 0 && (w == nil || w.Fraction != tt.want || w.Spent != tt.spent):
+			t.Errorf("Observe(%g) = %+v, want a warning at %g", tt.spent, w, tt.want)
+		}
+	}
+}
+
+func TestBudgetWarner_NoThresholds(t *testing.T) {
+	var nilWarner *BudgetWarner
+	if w := nilWarner.Observe(100, 100); w != nil {
+		t.Errorf("nil warner warned: %+v", *w)
+	}
+	if w := NewBudgetWarner(nil).Observe(100, 100); w != nil {
+		t.Errorf("warner without thresholds warned: %+v", *w)
+	}
+	if w := NewBudgetWarner([]float64{0.5}).Observe(100, 0); w != nil {
+		t.Errorf("warner without a budget warned: %+v", *w)
+	}
+}
+
+func TestBudgetWarning_String(t *testing.T) {
+	tests := []struct {
+		warning BudgetWarning
+		want    string
+	}{
+		{BudgetWarning{Fraction: 0.8, Spent: 82.1, Budget: 100}, "spent $82.10 of the $100.00 budget (passed 80%)"},
+		{BudgetWarning{Fraction: 0.125, Spent: 1.3, Budget: 10}, "spent $1.30 of the $10.00 budget (passed 12.5%)"},
+		{BudgetWarning{Fraction: 0.57, Spent: 5.7, Budget: 10}, "spent $5.70 of the $10.00 budget (passed 57%)"},
+	}
+	for _, tt := range tests {
+		if got := tt.warning.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -6,13 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/flashingpumpkin/orbital/internal/completion"
 	"github.com/flashingpumpkin/orbital/internal/config"
 	orberrors "github.com/flashingpumpkin/orbital/internal/errors"
 	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
 	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
 // ErrBudgetExceeded is an alias for compatibility with existing code.
@@ -21,6 +24,12 @@ var ErrBudgetExceeded = orberrors.ErrBudgetExceeded
 // ErrMaxIterationsReached is an alias for compatibility with existing code.
 var ErrMaxIterationsReached = orberrors.ErrMaxIterationsReached
 
+// ErrNoProgress is an alias for compatibility with existing code.
+var ErrNoProgress = orberrors.ErrNoProgress
+
+// ErrCostAnomaly is an alias for compatibility with existing code.
+var ErrCostAnomaly = orberrors.ErrCostAnomaly
+
 // LoopState represents the current state of the execution loop.
 type LoopState struct {
 	// Iteration is the current iteration number (1-indexed).
@@ -38,12 +47,45 @@ type LoopState struct {
 	// TotalTokens is the cumulative total tokens (in + out) for backward compatibility.
 	TotalTokens int
 
+	// TotalCacheReadTokens and TotalCacheCreationTokens are the parts of
+	// TotalTokensIn read from and written to the prompt cache.
+	TotalCacheReadTokens     int
+	TotalCacheCreationTokens int
+
 	// StartTime is when the loop execution began.
 	StartTime time.Time
 
 	// LastOutput is the output from the most recent iteration.
 	LastOutput string
 
+	// CPUTime is the cumulative CPU time used by Claude processes.
+	CPUTime time.Duration
+
+	// PeakRSS is the highest resident memory of any Claude process, in bytes.
+	PeakRSS int64
+
+	// IterationModels records the model that served each iteration, in order.
+	// Only populated when a model fallback chain is in use.
+	IterationModels []string
+
+	// Escalations describes each switch to the workflow's escalation model
+	// after repeated gate failures, in order.
+	Escalations []string
+
+	// Redactions describes the secrets masked in Claude's output by pattern,
+	// e.g. "3 (2 aws-access-key, 1 jwt)", or "" when none were.
+	Redactions string
+
+	// FileVerifications holds the per-file outcome of the latest
+	// verification when spec files are verified one by one.
+	FileVerifications []FileVerification
+
+	// IterationStarted is when the current iteration started.
+	IterationStarted time.Time
+
+	// IterationDurations records how long each iteration took, in order.
+	IterationDurations []time.Duration
+
 	// Completed indicates whether the task completed successfully (promise detected).
 	Completed bool
 
@@ -51,6 +93,46 @@ type LoopState struct {
 	Error error
 }
 
+// CacheHitRatio returns the fraction of input tokens served from the prompt
+// cache so far.
+func (s *LoopState) CacheHitRatio() float64 {
+	return output.CacheHitRatio(s.TotalCacheReadTokens, s.TotalTokensIn)
+}
+
+// AddCacheTokens records the prompt cache usage of one Claude process.
+func (s *LoopState) AddCacheTokens(read, creation int) {
+	s.TotalCacheReadTokens += read
+	s.TotalCacheCreationTokens += creation
+}
+
+// AddResources records the machine resources used by one Claude process.
+func (s *LoopState) AddResources(cpu time.Duration, peakRSS int64) {
+	s.CPUTime += cpu
+	s.PeakRSS = max(s.PeakRSS, peakRSS)
+}
+
+// StartIteration marks the start of an iteration at now.
+func (s *LoopState) StartIteration(now time.Time) {
+	s.IterationStarted = now
+}
+
+// EndIteration records how long the iteration started last took, up to now.
+// It does nothing when no iteration is running.
+func (s *LoopState) EndIteration(now time.Time) {
+	if s.IterationStarted.IsZero() {
+		return
+	}
+	s.IterationDurations = append(s.IterationDurations, now.Sub(s.IterationStarted))
+	s.IterationStarted = time.Time{}
+}
+
+// ReserveMessage explains why no further iteration is started when spend
+// has reached the loop budget but not the whole budget.
+func ReserveMessage(spent float64, cfg *config.Config) string {
+	return fmt.Sprintf("Spent %s of the %s budget; the remaining %g%% is reserved for verification. Not starting another iteration.",
+		util.FormatCost(spent, 2), util.FormatCost(cfg.MaxBudget, 2), cfg.BudgetReserve)
+}
+
 // ExecutorInterface defines the interface for executing prompts.
 // This allows for mocking in tests.
 type ExecutorInterface interface {
@@ -66,6 +148,10 @@ type IterationCallback func(iteration int, totalCost float64, totalTokensIn, tot
 // Parameters: iteration, maxIterations
 type IterationStartCallback func(iteration, maxIterations int)
 
+// InterjectionCallback is called with an operator interjection when an
+// iteration picks it up, so it can be recorded.
+type InterjectionCallback func(iteration int, text string)
+
 // StateManager defines an interface for managing queue state.
 // This allows the loop to check for queued files after completion.
 type StateManager interface {
@@ -80,7 +166,7 @@ type StateManager interface {
 }
 
 // Verifier defines the interface for verification execution.
-// This allows for mocking in tests.
+// Strategies are built with NewVerifier; tests can supply their own.
 type Verifier interface {
 	Verify(ctx context.Context, files []string) (*VerificationResult, error)
 }
@@ -95,8 +181,23 @@ type Controller struct {
 	stateManager           StateManager
 	specFiles              []string
 	verifier               Verifier
+	progressGuard          *ProgressGuard
+	treeState              TreeStateFunc
+	costMonitor            *CostMonitor
+	confirmCost            ConfirmFunc
+	turnTuner              *TurnTuner
+	offline                *executor.OfflineWaiter
+	interjectionCallback   InterjectionCallback
 }
 
+// ConfirmFunc asks the user whether to continue after a cost anomaly that
+// pauses the run. It returns false to stop.
+type ConfirmFunc func(a CostAnomaly) bool
+
+// TreeStateFunc describes the working tree for no-progress detection
+// (see git.TreeState). It returns "" when the state is unavailable.
+type TreeStateFunc func(ctx context.Context) string
+
 // New creates a new Controller with the given configuration, executor, and detector.
 func New(cfg *config.Config, exec ExecutorInterface, det *completion.Detector) *Controller {
 	return &Controller{
@@ -116,6 +217,12 @@ func (c *Controller) SetIterationStartCallback(cb IterationStartCallback) {
 	c.iterationStartCallback = cb
 }
 
+// SetInterjectionCallback sets a callback function to be called with each
+// operator interjection picked up from config.InterjectFile.
+func (c *Controller) SetInterjectionCallback(cb InterjectionCallback) {
+	c.interjectionCallback = cb
+}
+
 // SetStateManager sets the state manager for queue checking.
 func (c *Controller) SetStateManager(sm StateManager) {
 	c.stateManager = sm
@@ -126,7 +233,58 @@ func (c *Controller) SetSpecFiles(files []string) {
 	c.specFiles = files
 }
 
-// SetVerifier sets a custom verifier for testing purposes.
+// SetProgressGuard enables no-progress detection. treeState may be nil, in
+// which case only the output is compared.
+func (c *Controller) SetProgressGuard(g *ProgressGuard, treeState TreeStateFunc) {
+	c.progressGuard = g
+	c.treeState = treeState
+}
+
+// SetCostMonitor enables cost anomaly detection. confirm is called when an
+// anomaly pauses the run; if it is nil the run continues.
+func (c *Controller) SetCostMonitor(m *CostMonitor, confirm ConfirmFunc) {
+	c.costMonitor = m
+	c.confirmCost = confirm
+}
+
+// SetTurnTuner enables auto-tuning of the agentic turn limit. Adjustments
+// are applied to the config's MaxTurns, which the executor reads on each
+// execution.
+func (c *Controller) SetTurnTuner(t *TurnTuner) {
+	c.turnTuner = t
+	if t != nil {
+		c.config.MaxTurns = t.Limit()
+	}
+}
+
+// SetOfflineWaiter pauses the loop when an iteration fails because the
+// network is down. The iteration runs again once w finds the API reachable,
+// without counting against the iteration limit.
+func (c *Controller) SetOfflineWaiter(w *executor.OfflineWaiter) {
+	c.offline = w
+}
+
+// execute runs prompt for one iteration, bounded by the iteration timeout
+// and, for an optimistic iteration, by the previous iteration's verification.
+func (c *Controller) execute(ctx context.Context, prompt string, pending *PendingVerification) (*executor.ExecutionResult, error) {
+	// Create iteration context with timeout if configured
+	iterCtx := ctx
+	if c.config.IterationTimeout > 0 {
+		var cancel context.CancelFunc
+		iterCtx, cancel = context.WithTimeout(ctx, c.config.IterationTimeout)
+		defer cancel()
+	}
+	// An optimistic iteration stops once the previous one is verified
+	if pending != nil {
+		var cancel context.CancelFunc
+		iterCtx, cancel = pending.Context(iterCtx)
+		defer cancel()
+	}
+	return c.executor.Execute(iterCtx, prompt)
+}
+
+// SetVerifier sets the verification strategy.
+// If unset, completion is verified by the checker model.
 func (c *Controller) SetVerifier(v Verifier) {
 	c.verifier = v
 }
@@ -138,52 +296,49 @@ type VerificationResult struct {
 	Checked   int
 	Cost      float64
 	Tokens    int
+	// Reason describes the outcome for strategies that don't count checkboxes
+	// (e.g. "`go test ./...` failed: exit status 1").
+	Reason string
+	// Items are the texts of the unchecked spec items, for strategies that
+	// count checkboxes and found some unchecked.
+	Items []string
+	// Confirmed is set when a second, independent check agreed that the work
+	// is complete (see DoubleCheckVerifier).
+	Confirmed bool
+	// Files holds the outcome per spec file when each is verified on its
+	// own (see PerFileVerifier).
+	Files []FileVerification
+	// Ticked are the spec items orbital ticked itself after the verification
+	// failed (see TickingVerifier).
+	Ticked []string
 }
 
-// verifyCompletion runs a verification check using the checker model (haiku).
-// If a custom verifier is set (via SetVerifier), it uses that.
-// Otherwise, it creates a fresh executor instance and runs the verification prompt.
-// Returns a VerificationResult and any error encountered.
-func (c *Controller) verifyCompletion(ctx context.Context) (*VerificationResult, error) {
-	// Use custom verifier if set (for testing)
-	if c.verifier != nil {
-		return c.verifier.Verify(ctx, c.specFiles)
-	}
-
-	if len(c.specFiles) == 0 {
-		return nil, errors.New("no spec files configured for verification")
-	}
-
-	// Create a minimal config for the verification executor
-	verifyConfig := &config.Config{
-		Model:     c.config.CheckerModel,
-		MaxBudget: c.config.MaxBudget,
-		// No session ID - fresh session each time
-		// No system prompt - just the verification prompt
+// Describe returns a short human-readable outcome for status messages.
+func (r *VerificationResult) Describe() string {
+	switch {
+	case r.Reason != "":
+		return r.Reason
+	case r.Verified:
+		return fmt.Sprintf("all items complete (%d checked)", r.Checked) + r.describeFiles()
+	case r.Unchecked >= 0:
+		return fmt.Sprintf("%d unchecked item(s) remain", r.Unchecked) + r.describeFiles()
+	default:
+		return "could not parse response" + r.describeFiles()
 	}
+}
 
-	// Create a new executor for verification
-	verifyExec := executor.New(verifyConfig)
-
-	// Build the verification prompt
-	prompt := spec.BuildVerificationPrompt(c.specFiles)
+// verifyCompletion runs the configured verifier, defaulting to the checker model.
+// Returns a VerificationResult and any error encountered.
+func (c *Controller) verifyCompletion(ctx context.Context) (*VerificationResult, error) {
+	return c.activeVerifier().Verify(ctx, c.specFiles)
+}
 
-	// Execute verification
-	result, err := verifyExec.Execute(ctx, prompt)
-	if err != nil {
-		return nil, fmt.Errorf("verification execution failed: %w", err)
+// activeVerifier returns the configured verifier, defaulting to the checker model.
+func (c *Controller) activeVerifier() Verifier {
+	if c.verifier == nil {
+		return NewModelVerifier(c.config.CheckerModel, c.config.MaxBudget)
 	}
-
-	// Parse the response
-	verified, unchecked, checked := ParseVerificationResponse(result.Output)
-
-	return &VerificationResult{
-		Verified:  verified,
-		Unchecked: unchecked,
-		Checked:   checked,
-		Cost:      result.CostUSD,
-		Tokens:    result.TokensIn + result.TokensOut,
-	}, nil
+	return c.verifier
 }
 
 // ParseVerificationResponse parses the verification output for VERIFIED or INCOMPLETE.
@@ -219,7 +374,47 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 
 	currentPrompt := prompt
 
+	// escalation is appended to the prompt while iterations are stagnant
+	verdict := ProgressOK
+	escalation := ""
+	var injector, interjector *Injector
+	if c.config.InjectFile != "" {
+		injector = NewInjector(c.config.InjectFile)
+	}
+	if c.config.InterjectFile != "" {
+		interjector = NewInjector(c.config.InterjectFile)
+	}
+	// lean narrows the next prompt to the work left after a failed
+	// verification (config.LeanPrompts)
+	lean := ""
+	var specWatcher *SpecWatcher
+	if len(c.specFiles) > 0 {
+		specWatcher = NewSpecWatcher(c.specFiles)
+	}
+
+	// pending is a verification running alongside the current iteration
+	// (config.OptimisticVerify). A return while it runs still waits for it,
+	// so its cost is counted.
+	var pending *PendingVerification
+	defer func() {
+		if pending != nil {
+			if result, _ := pending.Wait(); result != nil {
+				state.TotalCost += result.Cost
+				state.TotalTokens += result.Tokens
+			}
+		}
+	}()
+
 	for i := 1; i <= c.config.MaxIterations; i++ {
+		// Stop starting iterations once only the verification reserve is left
+		if state.TotalCost >= c.config.LoopBudget() {
+			if state.TotalCost < c.config.MaxBudget {
+				fmt.Printf("\n%s\n", ReserveMessage(state.TotalCost, c.config))
+			}
+			state.Error = ErrBudgetExceeded
+			return state, ErrBudgetExceeded
+		}
+
 		state.Iteration = i
 
 		// Check context cancellation before each iteration
@@ -228,24 +423,130 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 			return state, ctx.Err()
 		}
 
+		// Act on the previous iteration's progress
+		switch verdict {
+		case ProgressAbort:
+			err := fmt.Errorf("%w: %d consecutive iterations without changes", ErrNoProgress, c.progressGuard.Stagnant())
+			state.Error = err
+			return state, err
+		case ProgressEscalate:
+			fmt.Printf("No progress in %d iterations. Asking for a change of approach...\n", c.progressGuard.Stagnant())
+			escalation = c.progressGuard.Escalation()
+		default:
+			escalation = ""
+		}
+
+		// Pick up instructions dropped into the inject file since the last iteration
+		injection := ""
+		if injector != nil {
+			instructions, err := injector.Take()
+			if err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			} else if instructions != "" {
+				fmt.Printf("Injecting operator instructions from %s\n", injector.Path)
+				injection = FormatInjection(instructions)
+			}
+		}
+
+		// An interjection overrides the plan, so it goes before the prompt
+		interjection := ""
+		if interjector != nil {
+			instruction, err := interjector.Take()
+			if err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			} else if instruction != "" {
+				fmt.Printf("Operator interjection from %s\n", interjector.Path)
+				interjection = FormatInterjection(instruction)
+				if c.interjectionCallback != nil {
+					c.interjectionCallback(i, instruction)
+				}
+			}
+		}
+
+		// Work from the current spec if it was edited since the last iteration
+		if specWatcher != nil {
+			if changed := specWatcher.Changed(); len(changed) > 0 {
+				fmt.Printf("Spec changed: %s\n", strings.Join(changed, ", "))
+				injection += FormatSpecChange(changed)
+				if c.stateManager != nil {
+					if newPrompt, err := c.stateManager.RebuildPrompt(); err != nil {
+						fmt.Printf("Warning: %v\n", err)
+					} else {
+						currentPrompt = newPrompt
+					}
+				}
+			}
+		}
+
+		remaining := lean
+		lean = ""
+
 		// Call iteration start callback if set
+		state.StartIteration(time.Now())
 		if c.iterationStartCallback != nil {
 			c.iterationStartCallback(i, c.config.MaxIterations)
 		}
 
-		// Create iteration context with timeout if configured
-		iterCtx := ctx
-		var iterCancel context.CancelFunc
-		if c.config.IterationTimeout > 0 {
-			iterCtx, iterCancel = context.WithTimeout(ctx, c.config.IterationTimeout)
+		// Execute the prompt, pausing while offline and sending it again
+		// once the network is back
+		prompt := interjection + currentPrompt + escalation + injection + remaining
+		result, err := c.execute(ctx, prompt, pending)
+		for err == nil && c.offline != nil && executor.IsNetworkFailure(result) {
+			state.TotalCost += result.CostUSD
+			state.TotalTokensIn += result.TokensIn
+			state.TotalTokensOut += result.TokensOut
+			state.TotalTokens = state.TotalTokensIn + state.TotalTokensOut
+			state.AddCacheTokens(result.CacheReadTokens, result.CacheCreationTokens)
+			if err = c.offline.Wait(ctx); err != nil {
+				result = nil
+				break
+			}
+			result, err = c.execute(ctx, prompt, pending)
 		}
+		state.EndIteration(time.Now())
 
-		// Execute the prompt
-		result, err := c.executor.Execute(iterCtx, currentPrompt)
-
-		// Cancel iteration context to release resources
-		if iterCancel != nil {
-			iterCancel()
+		// Settle the verification that ran alongside this iteration
+		if pending != nil {
+			verifyResult, verifyErr := pending.Wait()
+			verified := pending.Iteration
+			pending = nil
+			if verifyResult != nil {
+				state.TotalCost += verifyResult.Cost
+				state.TotalTokens += verifyResult.Tokens
+				state.FileVerifications = verifyResult.Files
+				if msg := DescribeTicked(verifyResult.Ticked); msg != "" {
+					fmt.Println(msg)
+				}
+			}
+			switch {
+			case verifyErr != nil:
+				fmt.Printf("Verification of iteration %d: error: %v. Continuing loop.\n\n", verified, verifyErr)
+			case !verifyResult.Verified:
+				fmt.Printf("Verification of iteration %d: %s. Continuing loop.\n\n", verified, verifyResult.Describe())
+				if c.config.LeanPrompts {
+					lean = spec.BuildLeanPrompt(verifyResult.Items, verifyResult.Describe())
+				}
+			default:
+				// The work was already complete: this iteration is discarded,
+				// though what it spent is still counted
+				if result != nil {
+					state.TotalCost += result.CostUSD
+					state.TotalTokensIn += result.TokensIn
+					state.TotalTokensOut += result.TokensOut
+					state.TotalTokens += result.TokensIn + result.TokensOut
+					state.AddCacheTokens(result.CacheReadTokens, result.CacheCreationTokens)
+					state.AddResources(result.CPUTime, result.PeakRSS)
+				}
+				fmt.Printf("Verification of iteration %d: %s. Cancelled iteration %d.\n", verified, verifyResult.Describe(), i)
+				state.Iteration = verified
+				done, err := c.completeVerified(state, &currentPrompt)
+				if done || err != nil {
+					return state, err
+				}
+				// Queued files: run this iteration again on the new prompt
+				i--
+				continue
+			}
 		}
 
 		// Update cumulative state from result even if there was an error
@@ -255,7 +556,27 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 			state.TotalTokensIn += result.TokensIn
 			state.TotalTokensOut += result.TokensOut
 			state.TotalTokens = state.TotalTokensIn + state.TotalTokensOut
+			state.AddCacheTokens(result.CacheReadTokens, result.CacheCreationTokens)
 			state.LastOutput = result.Output
+			state.AddResources(result.CPUTime, result.PeakRSS)
+
+			// Flag iterations that cost far more than usual
+			if a := c.costMonitor.Observe(i, result.CostUSD); a != nil {
+				fmt.Printf("\nCost anomaly: %s\n", a)
+				if a.Pause && c.confirmCost != nil && !c.confirmCost(*a) {
+					state.Error = ErrCostAnomaly
+					return state, ErrCostAnomaly
+				}
+			}
+
+			// Raise or lower the turn limit for the next iterations
+			if !c.config.InBurst(i) {
+				turns, limitReached := executor.ResultTurns(result.Output)
+				if a := c.turnTuner.Observe(i, turns, limitReached); a != nil {
+					c.config.MaxTurns = a.To
+					fmt.Printf("\nMax turns: %s\n", a)
+				}
+			}
 		}
 
 		if err != nil {
@@ -264,10 +585,24 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 				fmt.Printf("\nIteration %d timed out. Continuing to next iteration...\n", i)
 				continue
 			}
+			// A stalled claude was killed, so try again in the next iteration
+			if errors.Is(err, executor.ErrStalled) {
+				fmt.Printf("\nIteration %d stalled. Continuing to next iteration...\n", i)
+				continue
+			}
 			state.Error = err
 			return state, err
 		}
 
+		// Compare with the previous iteration to detect no-progress loops
+		if c.progressGuard != nil {
+			tree := ""
+			if c.treeState != nil {
+				tree = c.treeState(ctx)
+			}
+			verdict = c.progressGuard.Observe(result.Output, tree)
+		}
+
 		// Call iteration callback if set
 		if c.iterationCallback != nil {
 			if err := c.iterationCallback(state.Iteration, state.TotalCost, state.TotalTokensIn, state.TotalTokensOut); err != nil {
@@ -276,7 +611,8 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 			}
 		}
 
-		// Check for budget exceeded
+		// Check for budget exceeded. An iteration that ends in the reserve
+		// may still be verified; the next one is not started.
 		if state.TotalCost >= c.config.MaxBudget {
 			state.Error = ErrBudgetExceeded
 			return state, ErrBudgetExceeded
@@ -284,6 +620,13 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 
 		// Check for completion
 		if c.detector.Check(result.Output) {
+			// Verify while the next iteration starts, rather than before
+			if VerifyOptimistically(c.config, i, state.TotalCost) {
+				fmt.Printf("\nCompletion promise detected. Verifying while iteration %d starts...\n", i+1)
+				pending = StartVerification(ctx, c.activeVerifier(), c.specFiles, i)
+				continue
+			}
+
 			fmt.Println("\nCompletion promise detected. Verifying...")
 
 			// Run verification step
@@ -294,6 +637,10 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 			if verifyResult != nil {
 				state.TotalCost += verifyResult.Cost
 				state.TotalTokens += verifyResult.Tokens
+				state.FileVerifications = verifyResult.Files
+				if msg := DescribeTicked(verifyResult.Ticked); msg != "" {
+					fmt.Println(msg)
+				}
 			}
 
 			// Handle verification errors - continue loop
@@ -304,59 +651,67 @@ func (c *Controller) Run(ctx context.Context, prompt string) (*LoopState, error)
 
 			// Handle incomplete verification - continue loop
 			if !verifyResult.Verified {
-				if verifyResult.Unchecked >= 0 {
-					fmt.Printf("Verification: %d unchecked item(s) remain. Continuing loop.\n\n", verifyResult.Unchecked)
-				} else {
-					fmt.Println("Verification: could not parse response. Continuing loop.")
+				fmt.Printf("Verification: %s. Continuing loop.\n\n", verifyResult.Describe())
+				if c.config.LeanPrompts {
+					lean = spec.BuildLeanPrompt(verifyResult.Items, verifyResult.Describe())
 				}
 				continue
 			}
 
 			// Verification passed
-			fmt.Printf("Verification: all items complete (%d checked).\n", verifyResult.Checked)
+			fmt.Printf("Verification: %s.\n", verifyResult.Describe())
 
-			// Check queue for new files if StateManager is set
-			if c.stateManager != nil {
-				queuedFiles, err := c.stateManager.PopQueue()
-				if err != nil {
-					state.Error = err
-					return state, err
-				}
+			done, err := c.completeVerified(state, &currentPrompt)
+			if done || err != nil {
+				return state, err
+			}
+			// Continue to next iteration with new prompt
+		}
+	}
 
-				if len(queuedFiles) > 0 {
-					fmt.Printf("Found %d queued file(s), continuing...\n", len(queuedFiles))
-					for _, f := range queuedFiles {
-						fmt.Printf("  + %s\n", f)
-					}
-					fmt.Println()
+	// Max iterations reached without completion
+	state.Error = ErrMaxIterationsReached
+	return state, ErrMaxIterationsReached
+}
 
-					// Merge queued files into active list
-					if err := c.stateManager.MergeFiles(queuedFiles); err != nil {
-						state.Error = err
-						return state, err
-					}
+// completeVerified finishes a passed verification. Queued files are merged
+// into the run and the prompt is rebuilt for them, returning false; without
+// any the work is complete and it returns true.
+func (c *Controller) completeVerified(state *LoopState, prompt *string) (bool, error) {
+	// Check queue for new files if StateManager is set
+	if c.stateManager != nil {
+		queuedFiles, err := c.stateManager.PopQueue()
+		if err != nil {
+			state.Error = err
+			return true, err
+		}
 
-					// Rebuild prompt with new files
-					newPrompt, err := c.stateManager.RebuildPrompt()
-					if err != nil {
-						state.Error = err
-						return state, err
-					}
-					currentPrompt = newPrompt
+		if len(queuedFiles) > 0 {
+			fmt.Printf("Found %d queued file(s), continuing...\n", len(queuedFiles))
+			for _, f := range queuedFiles {
+				fmt.Printf("  + %s\n", f)
+			}
+			fmt.Println()
 
-					// Continue to next iteration with new prompt
-					continue
-				}
+			// Merge queued files into active list
+			if err := c.stateManager.MergeFiles(queuedFiles); err != nil {
+				state.Error = err
+				return true, err
 			}
 
-			// No queued files or no state manager - we're done
-			fmt.Println("No queued files. Work complete.")
-			state.Completed = true
-			return state, nil
+			// Rebuild prompt with new files
+			newPrompt, err := c.stateManager.RebuildPrompt()
+			if err != nil {
+				state.Error = err
+				return true, err
+			}
+			*prompt = newPrompt
+			return false, nil
 		}
 	}
 
-	// Max iterations reached without completion
-	state.Error = ErrMaxIterationsReached
-	return state, ErrMaxIterationsReached
+	// No queued files or no state manager - we're done
+	fmt.Println("No queued files. Work complete.")
+	state.Completed = true
+	return true, nil
 }
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/flashingpumpkin/orbital/internal/completion"
 	"github.com/flashingpumpkin/orbital/internal/config"
 	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/spec"
 )
 
 // floatEquals compares two floats for equality within a small epsilon.
@@ -176,6 +178,52 @@ func TestRun_CompletesOnThirdIteration(t *testing.T) {
 	if exec.calls != 3 {
 		t.Errorf("expected 3 executor calls, got %d", exec.calls)
 	}
+	if len(state.IterationDurations) != 3 || !state.IterationStarted.IsZero() {
+		t.Errorf("expected 3 recorded iteration durations and none running, got %v", state.IterationDurations)
+	}
+}
+
+func TestLoopState_CacheTokens(t *testing.T) {
+	s := LoopState{TotalTokensIn: 2000}
+	if s.CacheHitRatio() != 0 {
+		t.Errorf("CacheHitRatio() = %v before any cache use, want 0", s.CacheHitRatio())
+	}
+
+	s.AddCacheTokens(1000, 300)
+	s.AddCacheTokens(500, 0)
+	if s.TotalCacheReadTokens != 1500 || s.TotalCacheCreationTokens != 300 {
+		t.Errorf("cache read, creation = %d, %d, want 1500, 300", s.TotalCacheReadTokens, s.TotalCacheCreationTokens)
+	}
+	if s.CacheHitRatio() != 0.75 {
+		t.Errorf("CacheHitRatio() = %v, want 0.75", s.CacheHitRatio())
+	}
+}
+
+func TestLoopState_IterationDurations(t *testing.T) {
+	var s LoopState
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Ending without a started iteration records nothing
+	s.EndIteration(start)
+	if len(s.IterationDurations) != 0 {
+		t.Fatalf("IterationDurations = %v, want none", s.IterationDurations)
+	}
+
+	s.StartIteration(start)
+	if !s.IterationStarted.Equal(start) {
+		t.Errorf("IterationStarted = %v, want %v", s.IterationStarted, start)
+	}
+	s.EndIteration(start.Add(90 * time.Second))
+	s.StartIteration(start.Add(2 * time.Minute))
+	s.EndIteration(start.Add(3 * time.Minute))
+
+	want := []time.Duration{90 * time.Second, time.Minute}
+	if len(s.IterationDurations) != 2 || s.IterationDurations[0] != want[0] || s.IterationDurations[1] != want[1] {
+		t.Errorf("IterationDurations = %v, want %v", s.IterationDurations, want)
+	}
+	if !s.IterationStarted.IsZero() {
+		t.Errorf("IterationStarted = %v, want zero after the iteration ended", s.IterationStarted)
+	}
 }
 
 func TestRun_BudgetExceeded(t *testing.T) {
@@ -402,6 +450,61 @@ func TestRun_BudgetExactlyMet(t *testing.T) {
 	}
 }
 
+func TestRun_BudgetReserveStopsLoop(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 10
+	cfg.MaxBudget = 1.0
+	cfg.BudgetReserve = 10
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "Working...", Completed: true, CostUSD: 0.5}, nil)
+	exec.addResult(&executor.ExecutionResult{Output: "Still working...", Completed: true, CostUSD: 0.45}, nil)
+
+	det := completion.New("<promise>COMPLETE</promise>")
+	ctrl := New(cfg, exec, det)
+
+	state, err := ctrl.Run(context.Background(), "test prompt")
+
+	// 0.95 is under the budget but inside the 10% reserve
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got: %v", err)
+	}
+	if exec.calls != 2 {
+		t.Errorf("expected 2 executor calls, got %d", exec.calls)
+	}
+	if state.Iteration != 2 {
+		t.Errorf("expected Iteration to be 2, got %d", state.Iteration)
+	}
+}
+
+func TestRun_BudgetReserveVerifiesFinalIteration(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 10
+	cfg.MaxBudget = 1.0
+	cfg.BudgetReserve = 10
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "Done! <promise>COMPLETE</promise>", Completed: true, CostUSD: 0.95}, nil)
+
+	det := completion.New("<promise>COMPLETE</promise>")
+	ctrl := New(cfg, exec, det)
+	verifier := newMockVerifier()
+	ctrl.SetVerifier(verifier)
+
+	state, err := ctrl.Run(context.Background(), "test prompt")
+
+	// The iteration ended inside the reserve, which pays for verification
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !state.Completed {
+		t.Error("expected Completed to be true")
+	}
+	if verifier.calls != 1 {
+		t.Errorf("expected 1 verifier call, got %d", verifier.calls)
+	}
+}
+
 func TestRun_ZeroIterations(t *testing.T) {
 	cfg := config.NewConfig()
 	cfg.MaxIterations = 0
@@ -1092,3 +1195,197 @@ func (v *errorThenSuccessVerifier) Verify(ctx context.Context, files []string) (
 	}
 	return v.result, nil
 }
+
+func TestRun_NoProgressEscalatesThenAborts(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 10
+	cfg.MaxBudget = 100.0
+
+	// The mock's default output never changes
+	exec := newMockExecutor()
+	det := completion.New("<promise>COMPLETE</promise>")
+	ctrl := New(cfg, exec, det)
+
+	guard, err := NewProgressGuard(1, NoProgressEscalate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctrl.SetProgressGuard(guard, func(context.Context) string { return "unchanged" })
+
+	state, err := ctrl.Run(context.Background(), "test prompt")
+
+	if !errors.Is(err, ErrNoProgress) {
+		t.Fatalf("expected ErrNoProgress, got: %v", err)
+	}
+	if !errors.Is(state.Error, ErrNoProgress) {
+		t.Errorf("expected state.Error to be ErrNoProgress, got: %v", state.Error)
+	}
+	if exec.calls != 3 {
+		t.Errorf("expected 3 executor calls, got %d", exec.calls)
+	}
+	if exec.prompts[1] != "test prompt" {
+		t.Errorf("second prompt = %q, want no escalation yet", exec.prompts[1])
+	}
+	if !strings.Contains(exec.prompts[2], "You are repeating yourself") {
+		t.Errorf("third prompt = %q, want escalation", exec.prompts[2])
+	}
+}
+
+func TestRun_LeanPromptsAfterFailedVerification(t *testing.T) {
+	for _, lean := range []bool{false, true} {
+		cfg := config.NewConfig()
+		cfg.MaxIterations = 2
+		cfg.LeanPrompts = lean
+
+		exec := newMockExecutor()
+		exec.addResult(&executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", Completed: true}, nil)
+		exec.addResult(&executor.ExecutionResult{Output: "working", Completed: true}, nil)
+
+		verifier := &mockVerifier{result: &VerificationResult{Unchecked: 1, Checked: 2, Items: []string{"Write docs"}}}
+		ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+		ctrl.SetVerifier(verifier)
+		if _, err := ctrl.Run(context.Background(), "prompt"); !errors.Is(err, ErrMaxIterationsReached) {
+			t.Fatalf("Run() error = %v, want max iterations", err)
+		}
+
+		want := "prompt"
+		if lean {
+			want += spec.BuildLeanPrompt([]string{"Write docs"}, "1 unchecked item(s) remain")
+		}
+		if len(exec.prompts) != 2 || exec.prompts[1] != want {
+			t.Errorf("lean=%v: second prompt = %q, want %q", lean, exec.prompts[1], want)
+		}
+	}
+}
+
+func TestRun_CostAnomalyPause(t *testing.T) {
+	for _, confirm := range []bool{true, false} {
+		cfg := config.NewConfig()
+		cfg.MaxIterations = 4
+
+		exec := newMockExecutor()
+		for _, cost := range []float64{1, 1, 5, 5} {
+			exec.addResult(&executor.ExecutionResult{Output: "working", CostUSD: cost, Completed: true}, nil)
+		}
+
+		monitor, err := NewCostMonitor(0, 0, 1, CostAnomalyPause)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var asked []CostAnomaly
+		ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+		ctrl.SetCostMonitor(monitor, func(a CostAnomaly) bool {
+			asked = append(asked, a)
+			return confirm
+		})
+
+		state, err := ctrl.Run(context.Background(), "prompt")
+		if confirm {
+			if !errors.Is(err, ErrMaxIterationsReached) || len(asked) != 2 {
+				t.Errorf("confirmed: error = %v after %d confirmations, want max iterations after 2", err, len(asked))
+			}
+			continue
+		}
+		if !errors.Is(err, ErrCostAnomaly) || state.Iteration != 3 || len(asked) != 1 {
+			t.Errorf("declined: error = %v at iteration %d after %d confirmations, want ErrCostAnomaly at 3", err, state.Iteration, len(asked))
+		}
+	}
+}
+
+func TestRun_TurnTunerRaisesLimit(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+	cfg.MaxTurns = 10
+
+	exec := newMockExecutor()
+	for i := 0; i < 3; i++ {
+		exec.addResult(&executor.ExecutionResult{
+			Output:    `{"type":"result","subtype":"error_max_turns","num_turns":10}`,
+			Completed: true,
+		}, nil)
+	}
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetTurnTuner(NewTurnTuner(cfg.MaxTurns, 5, 40))
+
+	if _, err := ctrl.Run(context.Background(), "prompt"); !errors.Is(err, ErrMaxIterationsReached) {
+		t.Fatalf("Run() error = %v, want max iterations", err)
+	}
+	if cfg.MaxTurns != 15 {
+		t.Errorf("MaxTurns = %d, want 15 after two iterations ran out of turns", cfg.MaxTurns)
+	}
+}
+
+func TestRun_OfflinePausesWithoutUsingIterations(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 1
+	cfg.MaxBudget = 100.0
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{
+		Output:  `{"type":"result","is_error":true,"result":"API Error: Connection error."}`,
+		CostUSD: 0.01,
+	}, nil)
+	exec.addResult(&executor.ExecutionResult{
+		Output:    "Task done! <promise>COMPLETE</promise>",
+		Completed: true,
+		CostUSD:   0.05,
+	}, nil)
+
+	probes := 0
+	waiter := executor.NewOfflineWaiter(func(context.Context) error {
+		probes++
+		if probes < 2 {
+			return errors.New("no route to host")
+		}
+		return nil
+	}, 0, nil)
+	waiter.SetBackoff(time.Millisecond, time.Millisecond)
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(newMockVerifier())
+	ctrl.SetOfflineWaiter(waiter)
+
+	state, err := ctrl.Run(context.Background(), "test prompt")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !state.Completed || state.Iteration != 1 {
+		t.Errorf("Completed = %v, Iteration = %d; want completed in iteration 1", state.Completed, state.Iteration)
+	}
+	if exec.calls != 2 || exec.prompts[0] != exec.prompts[1] {
+		t.Errorf("calls = %d, prompts = %q; want the same prompt sent twice", exec.calls, exec.prompts)
+	}
+	if probes != 2 {
+		t.Errorf("probes = %d, want 2", probes)
+	}
+	// The failed attempt's cost is still counted, plus verification
+	if !floatEquals(state.TotalCost, 0.061) {
+		t.Errorf("TotalCost = %f, want 0.061", state.TotalCost)
+	}
+}
+
+func TestRun_OfflineTimeoutStopsLoop(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+	cfg.MaxBudget = 100.0
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "getaddrinfo ENOTFOUND api.anthropic.com"}, nil)
+
+	waiter := executor.NewOfflineWaiter(func(context.Context) error {
+		return errors.New("no route to host")
+	}, 5*time.Millisecond, nil)
+	waiter.SetBackoff(time.Millisecond, time.Millisecond)
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetOfflineWaiter(waiter)
+
+	state, err := ctrl.Run(context.Background(), "test prompt")
+	if !errors.Is(err, executor.ErrOffline) {
+		t.Fatalf("Run() error = %v, want ErrOffline", err)
+	}
+	if state.Iteration != 1 || exec.calls != 1 {
+		t.Errorf("Iteration = %d, calls = %d; want 1 and 1", state.Iteration, exec.calls)
+	}
+}
@@ -0,0 +1,60 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// FileSetVerifier verifies a fixed set of files, selected by glob patterns,
+// instead of the session's spec files: for example only the plan that
+// tracks progress when the spec is spread over several documents.
+type FileSetVerifier struct {
+	inner    Verifier
+	patterns []string
+	dir      string
+}
+
+// NewFileSetVerifier creates a FileSetVerifier that verifies the files
+// matching patterns, relative to dir, with inner.
+func NewFileSetVerifier(inner Verifier, patterns []string, dir string) *FileSetVerifier {
+	return &FileSetVerifier{inner: inner, patterns: patterns, dir: dir}
+}
+
+// Verify implements Verifier. The patterns are matched on every call, so
+// files created during the session are picked up. The given spec files are
+// not used.
+func (v *FileSetVerifier) Verify(ctx context.Context, _ []string) (*VerificationResult, error) {
+	files, err := v.files()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match the verification files %s", strings.Join(v.patterns, ", "))
+	}
+	return v.inner.Verify(ctx, files)
+}
+
+// files returns the files matching the patterns, in pattern order and
+// without duplicates.
+func (v *FileSetVerifier) files() ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	for _, pattern := range v.patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(v.dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification file pattern %q: %w", pattern, err)
+		}
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,38 @@
+package loop
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileSetVerifier(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, filepath.Join(dir, "a.md"), "- [x] One\n")
+	writeSpec(t, filepath.Join(dir, "b.md"), "- [ ] Two\n")
+	writeSpec(t, filepath.Join(dir, "notes.txt"), "- [ ] Ignored\n")
+
+	inner := &checkboxVerifier{}
+	v := NewFileSetVerifier(inner, []string{"a.md", "*.md"}, dir)
+
+	result, err := v.Verify(context.Background(), []string{"spec.md"})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")}
+	if !reflect.DeepEqual(inner.checked, want) {
+		t.Errorf("checked %q, want %q", inner.checked, want)
+	}
+	if result.Verified || result.Unchecked != 1 || result.Checked != 1 {
+		t.Errorf("result = %+v, want 1 unchecked, 1 checked", result)
+	}
+}
+
+func TestFileSetVerifier_NoMatches(t *testing.T) {
+	v := NewFileSetVerifier(&checkboxVerifier{}, []string{"plans/*.md"}, t.TempDir())
+	if _, err := v.Verify(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "no files match") {
+		t.Errorf("Verify() error = %v, want no files match", err)
+	}
+}
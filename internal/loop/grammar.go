@@ -0,0 +1,100 @@
+package loop
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Named groups the patterns of a ResponseGrammar capture.
+const (
+	groupChecked   = "checked"
+	groupUnchecked = "unchecked"
+)
+
+// ResponseGrammar parses a checker model's reply to a custom verification
+// prompt. The verified pattern matches a reply saying everything is done
+// and captures the checked count; the incomplete pattern captures the
+// unchecked and checked counts. Both are matched anywhere in the reply.
+type ResponseGrammar struct {
+	verified   *regexp.Regexp
+	incomplete *regexp.Regexp
+}
+
+// NewResponseGrammar compiles the verified and incomplete patterns and checks
+// that they capture the counts a verification reports: (?P<checked>\d+) in
+// verified, (?P<unchecked>\d+) and (?P<checked>\d+) in incomplete.
+func NewResponseGrammar(verified, incomplete string) (*ResponseGrammar, error) {
+	v, err := compileGrammarPattern("verified", verified, groupChecked)
+	if err != nil {
+		return nil, err
+	}
+	i, err := compileGrammarPattern("incomplete", incomplete, groupUnchecked, groupChecked)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseGrammar{verified: v, incomplete: i}, nil
+}
+
+// compileGrammarPattern compiles one pattern of a grammar and checks it has
+// the named groups and cannot match an empty reply.
+func compileGrammarPattern(name, pattern string, groups ...string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("%s pattern is required", name)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", name, err)
+	}
+	for _, group := range groups {
+		if re.SubexpIndex(group) < 0 {
+			return nil, fmt.Errorf("%s pattern must capture (?P<%s>\\d+)", name, group)
+		}
+	}
+	if re.MatchString("") {
+		return nil, fmt.Errorf("%s pattern matches an empty response", name)
+	}
+	return re, nil
+}
+
+// Parse reads a reply the same way ParseVerificationResponse does: it returns
+// (verified, unchecked, checked), with -1 counts when neither pattern matches.
+func (g *ResponseGrammar) Parse(reply string) (bool, int, int) {
+	if m := g.verified.FindStringSubmatch(reply); m != nil {
+		return true, 0, grammarCount(g.verified, m, groupChecked)
+	}
+	if m := g.incomplete.FindStringSubmatch(reply); m != nil {
+		return false, grammarCount(g.incomplete, m, groupUnchecked), grammarCount(g.incomplete, m, groupChecked)
+	}
+	return false, -1, -1
+}
+
+// grammarCount returns the number captured by group in match, or -1 when it
+// is not a number.
+func grammarCount(re *regexp.Regexp, match []string, group string) int {
+	n, err := strconv.Atoi(match[re.SubexpIndex(group)])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// validateVerificationPrompt checks a custom verification prompt template: it
+// must list the files to verify and, without a custom grammar, ask for the
+// replies ParseVerificationResponse understands.
+func validateVerificationPrompt(prompt string, customGrammar bool) error {
+	if !strings.Contains(prompt, "{{files}}") {
+		return errors.New("verification prompt must contain the {{files}} placeholder")
+	}
+	if customGrammar {
+		return nil
+	}
+	for _, format := range []string{"VERIFIED:", "INCOMPLETE:"} {
+		if !strings.Contains(prompt, format) {
+			return fmt.Errorf("verification prompt must ask for %q replies, or set verified and incomplete patterns for its own format", format)
+		}
+	}
+	return nil
+}
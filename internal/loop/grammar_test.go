@@ -0,0 +1,61 @@
+package loop
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewResponseGrammar(t *testing.T) {
+	tests := []struct {
+		name       string
+		verified   string
+		incomplete string
+		wantErr    string
+	}{
+		{name: "valid", verified: `ALL DONE \((?P<checked>\d+)\)`, incomplete: `LEFT (?P<unchecked>\d+) OF (?P<checked>\d+)`},
+		{name: "missing verified", incomplete: `(?P<unchecked>\d+) (?P<checked>\d+)`, wantErr: "verified pattern is required"},
+		{name: "missing incomplete", verified: `(?P<checked>\d+)`, wantErr: "incomplete pattern is required"},
+		{name: "invalid regexp", verified: `(?P<checked>\d+`, incomplete: `(?P<unchecked>\d+) (?P<checked>\d+)`, wantErr: "invalid verified pattern"},
+		{name: "verified without checked", verified: `ALL DONE`, incomplete: `(?P<unchecked>\d+) (?P<checked>\d+)`, wantErr: "verified pattern must capture (?P<checked>"},
+		{name: "incomplete without unchecked", verified: `(?P<checked>\d+)`, incomplete: `(?P<checked>\d+)`, wantErr: "incomplete pattern must capture (?P<unchecked>"},
+		{name: "matches empty reply", verified: `(?P<checked>\d*)`, incomplete: `(?P<unchecked>\d+) (?P<checked>\d+)`, wantErr: "matches an empty response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewResponseGrammar(tt.verified, tt.incomplete)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewResponseGrammar() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewResponseGrammar() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResponseGrammar_Parse(t *testing.T) {
+	g, err := NewResponseGrammar(`ALL DONE \((?P<checked>\d+)\)`, `LEFT (?P<unchecked>\d+), DONE (?P<checked>\d+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		reply                      string
+		wantVerified               bool
+		wantUnchecked, wantChecked int
+	}{
+		{reply: "Looked at both files.\nALL DONE (7)", wantVerified: true, wantUnchecked: 0, wantChecked: 7},
+		{reply: "LEFT 2, DONE 7", wantVerified: false, wantUnchecked: 2, wantChecked: 7},
+		{reply: "VERIFIED: 0 unchecked, 3 checked", wantVerified: false, wantUnchecked: -1, wantChecked: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			verified, unchecked, checked := g.Parse(tt.reply)
+			if verified != tt.wantVerified || unchecked != tt.wantUnchecked || checked != tt.wantChecked {
+				t.Errorf("Parse() = (%v, %d, %d), want (%v, %d, %d)", verified, unchecked, checked, tt.wantVerified, tt.wantUnchecked, tt.wantChecked)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package loop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultInjectFile is the inject file used when --inject-file is not given,
+// relative to the working directory.
+var DefaultInjectFile = filepath.Join(".orbital", "inject.md")
+
+// DefaultInterjectFile is the interjection file, relative to the working
+// directory. Unlike the inject file, its contents override the plan: they are
+// placed before the prompt rather than after it.
+var DefaultInterjectFile = filepath.Join(".orbital", "interject.md")
+
+// InjectionPrompt is appended to every step prompt of the iteration that picks
+// up injected instructions. The verb is replaced with the instructions.
+const InjectionPrompt = `
+
+---
+OPERATOR INSTRUCTIONS: The operator added the following instructions while the
+loop was running. They take priority over the plan for this iteration.
+
+%s
+---
+`
+
+// InterjectionPrompt is prepended to every step prompt of the iteration that
+// picks up an interjection. The verb is replaced with the instruction.
+const InterjectionPrompt = `---
+OPERATOR INSTRUCTION (interjection): The operator is overriding the plan for
+this iteration. Follow this instruction before anything below, and where it
+conflicts with the spec, the notes or the workflow step, this instruction wins.
+
+%s
+---
+
+`
+
+// Injector picks up instructions that external tools or the operator drop into
+// a file while the loop runs. Each drop is delivered exactly once: the file is
+// claimed by renaming it before it is read, so text written afterwards lands
+// in a new file for a later iteration.
+type Injector struct {
+	// Path is the watched file.
+	Path string
+}
+
+// NewInjector creates an injector watching path.
+func NewInjector(path string) *Injector {
+	return &Injector{Path: path}
+}
+
+// Take claims and returns the pending instructions, trimmed. It returns an
+// empty string when there are none.
+func (in *Injector) Take() (string, error) {
+	claimed := in.Path + ".taken"
+	if err := os.Rename(in.Path, claimed); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to claim inject file: %w", err)
+	}
+	data, err := os.ReadFile(claimed)
+	if err != nil {
+		return "", fmt.Errorf("failed to read inject file: %w", err)
+	}
+	_ = os.Remove(claimed)
+	return strings.TrimSpace(string(data)), nil
+}
+
+// FormatInjection returns the text appended to step prompts for the given
+// instructions, or "" when there are none.
+func FormatInjection(instructions string) string {
+	if instructions == "" {
+		return ""
+	}
+	return fmt.Sprintf(InjectionPrompt, instructions)
+}
+
+// FormatInterjection returns the text prepended to step prompts for the given
+// interjection, or "" when there is none.
+func FormatInterjection(instruction string) string {
+	if instruction == "" {
+		return ""
+	}
+	return fmt.Sprintf(InterjectionPrompt, instruction)
+}
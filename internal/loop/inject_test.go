@@ -0,0 +1,132 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/completion"
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+)
+
+func TestInjector_Take(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inject.md")
+	in := NewInjector(path)
+
+	if got, err := in.Take(); err != nil || got != "" {
+		t.Errorf("Take() without a file = %q, %v, want empty", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("\n  Focus on the parser first.\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := in.Take()
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if got != "Focus on the parser first." {
+		t.Errorf("Take() = %q, want trimmed instructions", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("inject file still exists after Take()")
+	}
+	if _, err := os.Stat(path + ".taken"); !os.IsNotExist(err) {
+		t.Error("claimed inject file left behind after Take()")
+	}
+
+	// Delivered exactly once
+	if got, err := in.Take(); err != nil || got != "" {
+		t.Errorf("second Take() = %q, %v, want empty", got, err)
+	}
+}
+
+func TestFormatInjection(t *testing.T) {
+	if got := FormatInjection(""); got != "" {
+		t.Errorf("FormatInjection(\"\") = %q, want empty", got)
+	}
+	if got := FormatInjection("Use the v2 API"); !strings.Contains(got, "OPERATOR INSTRUCTIONS") || !strings.Contains(got, "Use the v2 API") {
+		t.Errorf("FormatInjection() = %q, want instructions block", got)
+	}
+}
+
+func TestFormatInterjection(t *testing.T) {
+	if got := FormatInterjection(""); got != "" {
+		t.Errorf("FormatInterjection(\"\") = %q, want empty", got)
+	}
+	got := FormatInterjection("Revert the schema change")
+	if !strings.HasPrefix(got, "---\nOPERATOR INSTRUCTION (interjection)") || !strings.Contains(got, "Revert the schema change") {
+		t.Errorf("FormatInterjection() = %q, want a leading interjection block", got)
+	}
+}
+
+func TestRun_InjectsInstructionsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inject.md")
+	if err := os.WriteFile(path, []byte("Skip the docs task"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+	cfg.InjectFile = path
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "working", Completed: true}, nil)
+	exec.addResult(&executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", Completed: true}, nil)
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(newMockVerifier())
+	if _, err := ctrl.Run(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(exec.prompts) != 2 {
+		t.Fatalf("prompts = %d, want 2", len(exec.prompts))
+	}
+	if want := "prompt" + FormatInjection("Skip the docs task"); exec.prompts[0] != want {
+		t.Errorf("first prompt = %q, want %q", exec.prompts[0], want)
+	}
+	if exec.prompts[1] != "prompt" {
+		t.Errorf("second prompt = %q, want the instructions delivered only once", exec.prompts[1])
+	}
+}
+
+func TestRun_PrependsInterjectionOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interject.md")
+	if err := os.WriteFile(path, []byte("Revert the schema change\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+	cfg.InterjectFile = path
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "working", Completed: true}, nil)
+	exec.addResult(&executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", Completed: true}, nil)
+
+	var audited []string
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(newMockVerifier())
+	ctrl.SetInterjectionCallback(func(iteration int, text string) {
+		audited = append(audited, text)
+	})
+	if _, err := ctrl.Run(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(exec.prompts) != 2 {
+		t.Fatalf("prompts = %d, want 2", len(exec.prompts))
+	}
+	if want := FormatInterjection("Revert the schema change") + "prompt"; exec.prompts[0] != want {
+		t.Errorf("first prompt = %q, want %q", exec.prompts[0], want)
+	}
+	if exec.prompts[1] != "prompt" {
+		t.Errorf("second prompt = %q, want the interjection delivered only once", exec.prompts[1])
+	}
+	if len(audited) != 1 || audited[0] != "Revert the schema change" {
+		t.Errorf("audited = %q, want the interjection once", audited)
+	}
+}
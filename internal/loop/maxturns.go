@@ -0,0 +1,107 @@
+package loop
+
+import "fmt"
+
+// turnTuneRepeat is the number of consecutive iterations that must run out
+// of turns, or finish well under the limit, before the limit changes.
+const turnTuneRepeat = 2
+
+// TurnAdjustment describes a change of the agentic turn limit.
+type TurnAdjustment struct {
+	// Iteration is the iteration after which the limit changed.
+	Iteration int
+
+	// From and To are the old and new turn limits.
+	From int
+	To   int
+
+	// Used is the most turns an iteration took in the run of iterations
+	// that lowered the limit. It is 0 when the limit was raised.
+	Used int
+}
+
+// String describes the adjustment, e.g. "raised max turns from 20 to 30
+// after 2 iterations ran out of turns".
+func (a TurnAdjustment) String() string {
+	if a.To > a.From {
+		return fmt.Sprintf("raised max turns from %d to %d after %d iterations ran out of turns", a.From, a.To, turnTuneRepeat)
+	}
+	return fmt.Sprintf("lowered max turns from %d to %d after %d iterations used at most %d turns", a.From, a.To, turnTuneRepeat, a.Used)
+}
+
+// TurnTuner adjusts the agentic turn limit between iterations. The limit
+// goes up by half while iterations keep running out of turns, up to the
+// ceiling, and down to twice the turns actually used while iterations keep
+// finishing in half the limit or less, down to the floor.
+type TurnTuner struct {
+	limit   int
+	floor   int
+	ceiling int
+
+	// hits and under count consecutive iterations that ran out of turns and
+	// that finished in half the limit or less; used is the most turns one of
+	// the latter took.
+	hits  int
+	under int
+	used  int
+}
+
+// NewTurnTuner creates a tuner starting from the limit start, which is
+// brought within floor and ceiling; an unlimited start (0) begins at the
+// ceiling.
+func NewTurnTuner(start, floor, ceiling int) *TurnTuner {
+	if start <= 0 || start > ceiling {
+		start = ceiling
+	}
+	return &TurnTuner{limit: max(start, floor), floor: floor, ceiling: ceiling}
+}
+
+// Limit returns the current turn limit.
+func (t *TurnTuner) Limit() int {
+	return t.limit
+}
+
+// Observe records the turns an iteration took and whether it ran out of
+// them, and returns an adjustment when the limit changes. Iterations whose
+// turn count is unknown (0) are ignored. A nil tuner never adjusts.
+func (t *TurnTuner) Observe(iteration, turns int, limitReached bool) *TurnAdjustment {
+	if t == nil {
+		return nil
+	}
+	if limitReached {
+		t.under, t.used = 0, 0
+		t.hits++
+		if t.hits < turnTuneRepeat || t.limit >= t.ceiling {
+			return nil
+		}
+		t.hits = 0
+		return t.adjust(iteration, min(t.ceiling, t.limit+max(1, t.limit/2)), 0)
+	}
+	if turns <= 0 {
+		return nil
+	}
+	t.hits = 0
+	if turns*2 > t.limit {
+		t.under, t.used = 0, 0
+		return nil
+	}
+	t.under++
+	t.used = max(t.used, turns)
+	if t.under < turnTuneRepeat {
+		return nil
+	}
+	used := t.used
+	t.under, t.used = 0, 0
+	to := max(t.floor, used*2)
+	if to >= t.limit {
+		return nil
+	}
+	return t.adjust(iteration, to, used)
+}
+
+// adjust moves the limit to the new value and describes the change.
+func (t *TurnTuner) adjust(iteration, to, used int) *TurnAdjustment {
+	a := &TurnAdjustment{Iteration: iteration, From: t.limit, To: to, Used: used}
+	t.limit = to
+	return a
+}
@@ -0,0 +1,92 @@
+package loop
+
+import "testing"
+
+func TestNewTurnTuner_StartsWithinBounds(t *testing.T) {
+	tests := []struct {
+		start, floor, ceiling int
+		want                  int
+	}{
+		{start: 20, floor: 5, ceiling: 100, want: 20},
+		{start: 0, floor: 5, ceiling: 100, want: 100},
+		{start: 200, floor: 5, ceiling: 100, want: 100},
+		{start: 2, floor: 5, ceiling: 100, want: 5},
+	}
+	for _, tt := range tests {
+		if got := NewTurnTuner(tt.start, tt.floor, tt.ceiling).Limit(); got != tt.want {
+			t.Errorf("NewTurnTuner(%d, %d, %d).Limit() = %d, want %d", tt.start, tt.floor, tt.ceiling, got, tt.want)
+		}
+	}
+}
+
+func TestTurnTuner_RaisesAfterRepeatedLimitHits(t *testing.T) {
+	tuner := NewTurnTuner(20, 5, 40)
+
+	if a := tuner.Observe(1, 20, true); a != nil {
+		t.Fatalf("Observe() after one hit = %+v, want no adjustment", a)
+	}
+	a := tuner.Observe(2, 20, true)
+	if a == nil || a.From != 20 || a.To != 30 || a.Iteration != 2 {
+		t.Fatalf("Observe() = %+v, want 20 raised to 30 after iteration 2", a)
+	}
+	if got := a.String(); got != "raised max turns from 20 to 30 after 2 iterations ran out of turns" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Capped at the ceiling, then no further adjustments
+	tuner.Observe(3, 30, true)
+	if a := tuner.Observe(4, 30, true); a == nil || a.To != 40 {
+		t.Fatalf("Observe() = %+v, want the limit capped at the ceiling 40", a)
+	}
+	tuner.Observe(5, 40, true)
+	if a := tuner.Observe(6, 40, true); a != nil {
+		t.Errorf("Observe() at the ceiling = %+v, want no adjustment", a)
+	}
+}
+
+func TestTurnTuner_LowersAfterRepeatedShortIterations(t *testing.T) {
+	tuner := NewTurnTuner(50, 5, 100)
+
+	tuner.Observe(1, 8, false)
+	a := tuner.Observe(2, 12, false)
+	if a == nil || a.From != 50 || a.To != 24 || a.Used != 12 {
+		t.Fatalf("Observe() = %+v, want 50 lowered to twice the 12 turns used", a)
+	}
+	if got := a.String(); got != "lowered max turns from 50 to 24 after 2 iterations used at most 12 turns" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Never below the floor
+	tuner.Observe(3, 1, false)
+	if a := tuner.Observe(4, 2, false); a == nil || a.To != 5 {
+		t.Errorf("Observe() = %+v, want the limit lowered to the floor 5", a)
+	}
+}
+
+func TestTurnTuner_StreaksReset(t *testing.T) {
+	tuner := NewTurnTuner(20, 5, 100)
+
+	// A hit between short iterations, and an iteration using most of the
+	// limit between hits, break the streaks
+	tuner.Observe(1, 4, false)
+	tuner.Observe(2, 20, true)
+	if a := tuner.Observe(3, 4, false); a != nil {
+		t.Errorf("Observe() = %+v, want no adjustment after a broken streak", a)
+	}
+	tuner.Observe(4, 20, true)
+	tuner.Observe(5, 15, false)
+	if a := tuner.Observe(6, 20, true); a != nil {
+		t.Errorf("Observe() = %+v, want no adjustment after a broken streak", a)
+	}
+
+	// Unknown turn counts are ignored
+	tuner.Observe(7, 0, false)
+	if a := tuner.Observe(8, 20, true); a == nil {
+		t.Error("Observe() = nil, want an iteration without a turn count to be ignored")
+	}
+
+	var nilTuner *TurnTuner
+	if a := nilTuner.Observe(1, 20, true); a != nil {
+		t.Errorf("nil tuner Observe() = %+v, want nil", a)
+	}
+}
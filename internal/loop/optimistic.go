@@ -0,0 +1,68 @@
+package loop
+
+import (
+	"context"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+)
+
+// PendingVerification is a verification running in the background while the
+// next iteration starts optimistically (config.OptimisticVerify). Most
+// completion promises on long specs are premature, so the next iteration
+// usually goes on; when verification passes instead, the iteration running
+// under Context is cancelled.
+type PendingVerification struct {
+	// Iteration is the iteration whose completion promise is being verified.
+	Iteration int
+
+	done   chan struct{}
+	result *VerificationResult
+	err    error
+}
+
+// StartVerification runs v on files in the background for the completion
+// promise of iteration.
+func StartVerification(ctx context.Context, v Verifier, files []string, iteration int) *PendingVerification {
+	p := &PendingVerification{Iteration: iteration, done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		p.result, p.err = v.Verify(ctx, files)
+	}()
+	return p
+}
+
+// VerifyOptimistically reports whether a completion promise in iteration
+// should be verified alongside the next iteration: the option is on, and
+// limits still allow a next iteration to start.
+func VerifyOptimistically(cfg *config.Config, iteration int, spent float64) bool {
+	return cfg.OptimisticVerify && iteration < cfg.MaxIterations && spent < cfg.LoopBudget()
+}
+
+// Context returns a context for the optimistic iteration, derived from ctx
+// and cancelled as soon as the verification passes. Call cancel when the
+// iteration ends.
+func (p *PendingVerification) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	runCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-p.done:
+			if p.err == nil && p.result != nil && p.result.Verified {
+				cancel()
+			}
+		case <-runCtx.Done():
+		}
+	}()
+	return runCtx, cancel
+}
+
+// Wait blocks until the verification is done and returns its result.
+func (p *PendingVerification) Wait() (*VerificationResult, error) {
+	<-p.done
+	return p.result, p.err
+}
+
+// Passed waits for the verification and reports whether it passed.
+func (p *PendingVerification) Passed() bool {
+	result, err := p.Wait()
+	return err == nil && result != nil && result.Verified
+}
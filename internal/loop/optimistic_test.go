@@ -0,0 +1,205 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/completion"
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+)
+
+// funcExecutor runs each iteration through run, with its 1-based call number.
+type funcExecutor struct {
+	calls atomic.Int32
+	run   func(ctx context.Context, call int) (*executor.ExecutionResult, error)
+}
+
+func (f *funcExecutor) Execute(ctx context.Context, prompt string) (*executor.ExecutionResult, error) {
+	return f.run(ctx, int(f.calls.Add(1)))
+}
+
+// delayedVerifier returns result after delay.
+type delayedVerifier struct {
+	delay  time.Duration
+	result *VerificationResult
+	calls  atomic.Int32
+}
+
+func (v *delayedVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	v.calls.Add(1)
+	select {
+	case <-time.After(v.delay):
+		return v.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestVerifyOptimistically(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 5
+	cfg.MaxBudget = 10
+	cfg.BudgetReserve = 10
+
+	if VerifyOptimistically(cfg, 1, 0) {
+		t.Error("VerifyOptimistically() = true with the option off")
+	}
+	cfg.OptimisticVerify = true
+	tests := []struct {
+		iteration int
+		spent     float64
+		want      bool
+	}{
+		{1, 0, true},
+		{5, 0, false},   // no iteration left to start
+		{2, 9.5, false}, // only the verification reserve is left
+	}
+	for _, tt := range tests {
+		if got := VerifyOptimistically(cfg, tt.iteration, tt.spent); got != tt.want {
+			t.Errorf("VerifyOptimistically(iteration %d, spent %g) = %v, want %v", tt.iteration, tt.spent, got, tt.want)
+		}
+	}
+}
+
+func TestPendingVerification_Context(t *testing.T) {
+	passed := StartVerification(context.Background(), newMockVerifier(), []string{"spec.md"}, 1)
+	ctx, cancel := passed.Context(context.Background())
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled after verification passed")
+	}
+	if !passed.Passed() {
+		t.Error("Passed() = false, want true")
+	}
+
+	failing := &mockVerifier{result: &VerificationResult{Unchecked: 2}}
+	failed := StartVerification(context.Background(), failing, []string{"spec.md"}, 1)
+	ctx, cancel = failed.Context(context.Background())
+	defer cancel()
+	if failed.Passed() {
+		t.Error("Passed() = true for a failed verification")
+	}
+	if ctx.Err() != nil {
+		t.Error("context cancelled after verification failed")
+	}
+
+	broken := &mockVerifier{err: errors.New("checker unavailable")}
+	if StartVerification(context.Background(), broken, nil, 1).Passed() {
+		t.Error("Passed() = true for a verification error")
+	}
+}
+
+func TestRun_OptimisticVerifyCancelsIterationWhenVerified(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 5
+	cfg.MaxBudget = 100
+	cfg.OptimisticVerify = true
+
+	exec := &funcExecutor{run: func(ctx context.Context, call int) (*executor.ExecutionResult, error) {
+		if call == 1 {
+			return &executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", CostUSD: 0.10, TokensIn: 100}, nil
+		}
+		// The optimistic iteration runs until it is cancelled
+		<-ctx.Done()
+		return &executor.ExecutionResult{Output: "partial", CostUSD: 0.02, TokensIn: 20}, ctx.Err()
+	}}
+	verifier := &delayedVerifier{delay: 20 * time.Millisecond, result: &VerificationResult{Verified: true, Checked: 3, Cost: 0.001, Tokens: 5}}
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(verifier)
+	ctrl.SetSpecFiles([]string{"spec.md"})
+
+	state, err := ctrl.Run(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !state.Completed || state.Iteration != 1 {
+		t.Errorf("Completed = %v, Iteration = %d; want completed at iteration 1", state.Completed, state.Iteration)
+	}
+	if exec.calls.Load() != 2 || verifier.calls.Load() != 1 {
+		t.Errorf("executor calls = %d, verifier calls = %d; want 2 and 1", exec.calls.Load(), verifier.calls.Load())
+	}
+	// Both iterations and the verification are paid for
+	if !floatEquals(state.TotalCost, 0.121) {
+		t.Errorf("TotalCost = %f, want 0.121", state.TotalCost)
+	}
+	if state.TotalTokensIn != 120 || state.TotalTokens != 125 {
+		t.Errorf("TotalTokensIn = %d, TotalTokens = %d; want 120 and 125", state.TotalTokensIn, state.TotalTokens)
+	}
+	if state.LastOutput == "partial" {
+		t.Error("LastOutput is from the discarded iteration")
+	}
+}
+
+func TestRun_OptimisticVerifyContinuesWhenIncomplete(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+	cfg.MaxBudget = 100
+	cfg.OptimisticVerify = true
+
+	exec := &funcExecutor{run: func(ctx context.Context, call int) (*executor.ExecutionResult, error) {
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Errorf("iteration %d cancelled although verification failed", call)
+		}
+		output := "working"
+		if call != 2 {
+			output = "<promise>COMPLETE</promise>"
+		}
+		return &executor.ExecutionResult{Output: output, CostUSD: 0.10}, nil
+	}}
+	verifier := &sequenceVerifier{results: []*VerificationResult{
+		{Verified: false, Unchecked: 2, Cost: 0.001},
+		{Verified: true, Checked: 5, Cost: 0.001},
+	}}
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(verifier)
+	ctrl.SetSpecFiles([]string{"spec.md"})
+
+	state, err := ctrl.Run(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// Iteration 3 is the last, so its promise is verified before stopping
+	if !state.Completed || state.Iteration != 3 || exec.calls.Load() != 3 || verifier.calls != 2 {
+		t.Errorf("Completed = %v, Iteration = %d, executor calls = %d, verifier calls = %d; want completed after 3 iterations and 2 verifications",
+			state.Completed, state.Iteration, exec.calls.Load(), verifier.calls)
+	}
+	if !floatEquals(state.TotalCost, 0.302) {
+		t.Errorf("TotalCost = %f, want 0.302", state.TotalCost)
+	}
+}
+
+func TestRun_OptimisticVerifyCountsVerificationOnEarlyReturn(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 5
+	cfg.MaxBudget = 100
+	cfg.OptimisticVerify = true
+
+	boom := errors.New("claude crashed")
+	exec := &funcExecutor{run: func(ctx context.Context, call int) (*executor.ExecutionResult, error) {
+		if call == 1 {
+			return &executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", CostUSD: 0.10}, nil
+		}
+		return nil, boom
+	}}
+	verifier := &delayedVerifier{delay: 10 * time.Millisecond, result: &VerificationResult{Unchecked: 1, Cost: 0.001}}
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(verifier)
+
+	state, err := ctrl.Run(context.Background(), "prompt")
+	if !errors.Is(err, boom) {
+		t.Fatalf("Run() error = %v, want %v", err, boom)
+	}
+	if !floatEquals(state.TotalCost, 0.101) {
+		t.Errorf("TotalCost = %f, want the verification counted (0.101)", state.TotalCost)
+	}
+}
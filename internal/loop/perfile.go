@@ -0,0 +1,143 @@
+package loop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileVerification is the verification outcome of one spec file.
+type FileVerification struct {
+	File      string
+	Verified  bool
+	Unchecked int
+	Checked   int
+	// Rechecked is false when the file had not changed since it last passed
+	// and that result was reused.
+	Rechecked bool
+}
+
+// PerFileVerifier verifies each spec file on its own. A file that passed is
+// not checked again until its content changes, so a session with several
+// spec files only pays for checking the ones still being worked on. The
+// work is verified when every file is.
+type PerFileVerifier struct {
+	inner Verifier
+
+	mu sync.Mutex
+	// passed holds, per file, the content hash it passed with and the result.
+	passed map[string]passedFile
+}
+
+// passedFile is a spec file that passed verification with the given content.
+type passedFile struct {
+	hash   string
+	result FileVerification
+}
+
+// NewPerFileVerifier creates a PerFileVerifier that checks each file with
+// inner, which must check the spec files it is given (like ModelVerifier).
+func NewPerFileVerifier(inner Verifier) *PerFileVerifier {
+	return &PerFileVerifier{inner: inner, passed: make(map[string]passedFile)}
+}
+
+// Verify implements Verifier. With a single file it defers to the inner
+// verifier. Costs, tokens and checkbox counts are summed over the files
+// checked; a file that could not be read is always checked.
+func (v *PerFileVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	if len(files) < 2 {
+		return v.inner.Verify(ctx, files)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	combined := &VerificationResult{Verified: true}
+	for _, file := range files {
+		hash := fileHash(file)
+		if p, ok := v.passed[file]; ok && hash != "" && p.hash == hash {
+			p.result.Rechecked = false
+			combined.add(p.result, nil)
+			continue
+		}
+
+		result, err := v.inner.Verify(ctx, []string{file})
+		if result != nil {
+			combined.Cost += result.Cost
+			combined.Tokens += result.Tokens
+		}
+		if err != nil {
+			return combined, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+
+		fv := FileVerification{
+			File:      file,
+			Verified:  result.Verified,
+			Unchecked: result.Unchecked,
+			Checked:   result.Checked,
+			Rechecked: true,
+		}
+		if fv.Verified && hash != "" {
+			v.passed[file] = passedFile{hash: hash, result: fv}
+		} else {
+			delete(v.passed, file)
+		}
+		combined.add(fv, result.Items)
+	}
+	return combined, nil
+}
+
+// add aggregates one file's outcome into r. A count a check could not
+// determine (-1) makes the total unknown.
+func (r *VerificationResult) add(fv FileVerification, items []string) {
+	r.Files = append(r.Files, fv)
+	r.Verified = r.Verified && fv.Verified
+	r.Items = append(r.Items, items...)
+	if fv.Unchecked < 0 || r.Unchecked < 0 {
+		r.Unchecked = -1
+	} else {
+		r.Unchecked += fv.Unchecked
+	}
+	if fv.Checked > 0 {
+		r.Checked += fv.Checked
+	}
+}
+
+// describeFiles summarises per-file results for Describe, e.g. " in b.md
+// (re-checked 1 of 3 spec files)". It is empty without per-file results.
+func (r *VerificationResult) describeFiles() string {
+	if len(r.Files) == 0 {
+		return ""
+	}
+	var failed []string
+	rechecked := 0
+	for _, f := range r.Files {
+		if !f.Verified {
+			failed = append(failed, filepath.Base(f.File))
+		}
+		if f.Rechecked {
+			rechecked++
+		}
+	}
+	s := ""
+	if len(failed) > 0 {
+		s = " in " + strings.Join(failed, ", ")
+	}
+	return s + fmt.Sprintf(" (re-checked %d of %d spec files)", rechecked, len(r.Files))
+}
+
+// fileHash returns the SHA-256 of path's content, or "" when it cannot be
+// read.
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,142 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkboxVerifier counts the checkboxes of the files it is given, like the
+// checker model, and records which files it checked.
+type checkboxVerifier struct {
+	checked []string
+	err     error
+}
+
+func (v *checkboxVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	v.checked = append(v.checked, files...)
+	if v.err != nil {
+		return nil, v.err
+	}
+	result := &VerificationResult{Cost: 0.01, Tokens: 10}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		result.Unchecked += strings.Count(string(data), "- [ ]")
+		result.Checked += strings.Count(string(data), "- [x]")
+		if strings.Contains(string(data), "- [ ]") {
+			result.Items = append(result.Items, filepath.Base(f)+" item")
+		}
+	}
+	result.Verified = result.Unchecked == 0
+	return result, nil
+}
+
+func writeSpec(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPerFileVerifier_RechecksChangedAndFailedFiles(t *testing.T) {
+	dir := t.TempDir()
+	a, b, c := filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md"), filepath.Join(dir, "c.md")
+	writeSpec(t, a, "- [x] one\n")
+	writeSpec(t, b, "- [x] two\n- [ ] three\n")
+	writeSpec(t, c, "- [x] four\n")
+
+	inner := &checkboxVerifier{}
+	v := NewPerFileVerifier(inner)
+	files := []string{a, b, c}
+
+	result, err := v.Verify(context.Background(), files)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Verified || result.Unchecked != 1 || result.Checked != 3 || result.Cost != 0.03 {
+		t.Errorf("first Verify() = %+v, want 1 unchecked, 3 checked, 3 checks paid for", result)
+	}
+	if len(result.Items) != 1 || result.Items[0] != "b.md item" {
+		t.Errorf("Items = %v, want b.md's item", result.Items)
+	}
+	if got := result.Describe(); got != "1 unchecked item(s) remain in b.md (re-checked 3 of 3 spec files)" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	// Only the failed file is checked again while the others are unchanged
+	inner.checked = nil
+	writeSpec(t, b, "- [x] two\n- [x] three\n")
+	result, err = v.Verify(context.Background(), files)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if strings.Join(inner.checked, ",") != b {
+		t.Errorf("checked %v, want only b.md", inner.checked)
+	}
+	if !result.Verified || result.Checked != 4 || result.Cost != 0.01 {
+		t.Errorf("second Verify() = %+v, want verified with 4 checked at the cost of one check", result)
+	}
+	if got := result.Describe(); got != "all items complete (4 checked) (re-checked 1 of 3 spec files)" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	// A passed file that changes is checked again
+	inner.checked = nil
+	writeSpec(t, a, "- [x] one\n- [ ] new\n")
+	result, _ = v.Verify(context.Background(), files)
+	if strings.Join(inner.checked, ",") != a || result.Verified {
+		t.Errorf("checked %v, verified %v; want a.md re-checked and failing", inner.checked, result.Verified)
+	}
+	if len(result.Files) != 3 || !result.Files[0].Rechecked || result.Files[1].Rechecked || result.Files[0].Verified {
+		t.Errorf("Files = %+v", result.Files)
+	}
+}
+
+func TestPerFileVerifier_SingleFileUsesInner(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.md")
+	writeSpec(t, a, "- [x] one\n")
+
+	inner := &checkboxVerifier{}
+	v := NewPerFileVerifier(inner)
+	for i := 0; i < 2; i++ {
+		result, err := v.Verify(context.Background(), []string{a})
+		if err != nil || !result.Verified || result.Files != nil {
+			t.Fatalf("Verify() = %+v, %v", result, err)
+		}
+	}
+	if len(inner.checked) != 2 {
+		t.Errorf("inner checked %d times, want every call", len(inner.checked))
+	}
+}
+
+func TestPerFileVerifier_Error(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")
+	writeSpec(t, a, "- [x] one\n")
+	writeSpec(t, b, "- [x] two\n")
+
+	v := NewPerFileVerifier(&checkboxVerifier{err: errors.New("checker down")})
+	_, err := v.Verify(context.Background(), []string{a, b})
+	if err == nil || err.Error() != "a.md: checker down" {
+		t.Errorf("Verify() error = %v, want the file's checker error", err)
+	}
+}
+
+func TestVerificationResult_AddUnknownCount(t *testing.T) {
+	r := &VerificationResult{Verified: true}
+	r.add(FileVerification{File: "a.md", Unchecked: -1, Checked: -1}, nil)
+	r.add(FileVerification{File: "b.md", Verified: true, Checked: 2, Rechecked: true}, nil)
+	if r.Verified || r.Unchecked != -1 || r.Checked != 2 {
+		t.Errorf("add() = %+v, want unverified with an unknown unchecked count", r)
+	}
+	if got := r.Describe(); got != "could not parse response in a.md (re-checked 1 of 2 spec files)" {
+		t.Errorf("Describe() = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package loop
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// No-progress actions selectable in [no_progress].
+const (
+	// NoProgressEscalate injects EscalationPrompt once the limit is reached and
+	// aborts if the iterations stay stagnant for another limit.
+	NoProgressEscalate = "escalate"
+	// NoProgressAbort aborts with ErrNoProgress once the limit is reached.
+	NoProgressAbort = "abort"
+	// NoProgressOff disables the guard.
+	NoProgressOff = "off"
+)
+
+// DefaultNoProgressLimit is the number of consecutive stagnant iterations
+// tolerated before the guard acts.
+const DefaultNoProgressLimit = 3
+
+// EscalationPrompt is appended to every step prompt while iterations are stagnant.
+const EscalationPrompt = `
+
+---
+IMPORTANT: The last %d iterations produced the same output and no changes to the
+working tree. You are repeating yourself. Stop and change approach: re-read the
+spec, question your assumptions, and try a different strategy. If you are
+blocked, record why in the notes file.
+---
+`
+
+// ProgressVerdict is the guard's decision after an iteration.
+type ProgressVerdict int
+
+const (
+	// ProgressOK means the iteration made progress or the limit is not reached.
+	ProgressOK ProgressVerdict = iota
+	// ProgressEscalate means the next iteration should get the escalation prompt.
+	ProgressEscalate
+	// ProgressAbort means the run should stop with ErrNoProgress.
+	ProgressAbort
+)
+
+// digitsPattern matches numbers, which vary between otherwise identical
+// outputs (timestamps, costs, durations).
+var digitsPattern = regexp.MustCompile(`[0-9]+`)
+
+// ProgressGuard detects runs of iterations that make no progress. An
+// iteration is stagnant when its normalised output and working tree state hash
+// to the same value as the previous iteration's.
+type ProgressGuard struct {
+	limit    int
+	action   string
+	last     string
+	stagnant int
+}
+
+// NewProgressGuard creates a guard. A limit of 0 or less uses
+// DefaultNoProgressLimit and an empty action uses NoProgressEscalate.
+func NewProgressGuard(limit int, action string) (*ProgressGuard, error) {
+	if limit <= 0 {
+		limit = DefaultNoProgressLimit
+	}
+	switch action {
+	case "":
+		action = NoProgressEscalate
+	case NoProgressEscalate, NoProgressAbort, NoProgressOff:
+	default:
+		return nil, fmt.Errorf("invalid no_progress action %q (valid: %s, %s, %s)", action, NoProgressEscalate, NoProgressAbort, NoProgressOff)
+	}
+	return &ProgressGuard{limit: limit, action: action}, nil
+}
+
+// Observe records an iteration's output and working tree state (as returned
+// by git.TreeState; empty outside a repository) and returns the verdict.
+func (g *ProgressGuard) Observe(output, tree string) ProgressVerdict {
+	if g == nil || g.action == NoProgressOff {
+		return ProgressOK
+	}
+
+	hash := fingerprint(output, tree)
+	if hash == g.last {
+		g.stagnant++
+	} else {
+		g.stagnant = 0
+	}
+	g.last = hash
+
+	switch {
+	case g.stagnant < g.limit:
+		return ProgressOK
+	case g.action == NoProgressAbort || g.stagnant >= 2*g.limit:
+		return ProgressAbort
+	default:
+		return ProgressEscalate
+	}
+}
+
+// Stagnant returns the number of consecutive stagnant iterations.
+func (g *ProgressGuard) Stagnant() int {
+	if g == nil {
+		return 0
+	}
+	return g.stagnant
+}
+
+// Escalation returns the escalation prompt for the current stagnant run.
+func (g *ProgressGuard) Escalation() string {
+	return fmt.Sprintf(EscalationPrompt, g.Stagnant()+1)
+}
+
+// fingerprint hashes the output, ignoring case, whitespace and numbers, together
+// with the working tree state.
+func fingerprint(output, tree string) string {
+	normalised := strings.Join(strings.Fields(strings.ToLower(digitsPattern.ReplaceAllString(output, "#"))), " ")
+	sum := sha256.Sum256([]byte(normalised + "\x00" + tree))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+package loop
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewProgressGuard_InvalidAction(t *testing.T) {
+	if _, err := NewProgressGuard(3, "explode"); err == nil {
+		t.Error("NewProgressGuard() error = nil, want error for unknown action")
+	}
+}
+
+func TestProgressGuard_Observe(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		action  string
+		outputs []string
+		trees   []string
+		want    []ProgressVerdict
+	}{
+		{
+			name:    "progress resets the count",
+			limit:   2,
+			outputs: []string{"a", "a", "b", "b"},
+			trees:   []string{"t", "t", "t", "t"},
+			want:    []ProgressVerdict{ProgressOK, ProgressOK, ProgressOK, ProgressOK},
+		},
+		{
+			name:    "escalate then abort",
+			limit:   2,
+			outputs: []string{"same", "same", "same", "same", "same"},
+			trees:   []string{"t", "t", "t", "t", "t"},
+			want:    []ProgressVerdict{ProgressOK, ProgressOK, ProgressEscalate, ProgressEscalate, ProgressAbort},
+		},
+		{
+			name:    "abort action",
+			limit:   1,
+			action:  NoProgressAbort,
+			outputs: []string{"same", "same"},
+			trees:   []string{"t", "t"},
+			want:    []ProgressVerdict{ProgressOK, ProgressAbort},
+		},
+		{
+			name:    "tree changes count as progress",
+			limit:   1,
+			outputs: []string{"same", "same", "same"},
+			trees:   []string{"t1", "t2", "t3"},
+			want:    []ProgressVerdict{ProgressOK, ProgressOK, ProgressOK},
+		},
+		{
+			name:    "near-identical outputs are stagnant",
+			limit:   1,
+			outputs: []string{"Cost: $1.20 after 12s", "cost:  $1.35 after 14s\n"},
+			trees:   []string{"", ""},
+			want:    []ProgressVerdict{ProgressOK, ProgressEscalate},
+		},
+		{
+			name:    "off",
+			limit:   1,
+			action:  NoProgressOff,
+			outputs: []string{"same", "same", "same"},
+			trees:   []string{"", "", ""},
+			want:    []ProgressVerdict{ProgressOK, ProgressOK, ProgressOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewProgressGuard(tt.limit, tt.action)
+			if err != nil {
+				t.Fatalf("NewProgressGuard() error = %v", err)
+			}
+			for i, output := range tt.outputs {
+				if got := g.Observe(output, tt.trees[i]); got != tt.want[i] {
+					t.Errorf("iteration %d: Observe() = %v, want %v", i+1, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProgressGuard_Escalation(t *testing.T) {
+	g, _ := NewProgressGuard(1, "")
+	g.Observe("x", "")
+	g.Observe("x", "")
+
+	if got := g.Escalation(); !strings.Contains(got, "last 2 iterations") {
+		t.Errorf("Escalation() = %q, want iteration count", got)
+	}
+}
+
+func TestProgressGuard_Nil(t *testing.T) {
+	var g *ProgressGuard
+	if got := g.Observe("x", ""); got != ProgressOK {
+		t.Errorf("nil Observe() = %v, want ProgressOK", got)
+	}
+}
@@ -0,0 +1,101 @@
+package loop
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// SpecChangePrompt is appended to every step prompt of the iteration after
+// the spec files changed. The verb is replaced with the changed paths.
+const SpecChangePrompt = `
+
+---
+SPEC CHANGED: The following spec file(s) were edited while the loop was
+running:
+%s
+
+Re-read them before you continue. Where they disagree with what you
+remember, your notes or your plan, the files as they are now take priority.
+---
+`
+
+// specFileState is what is known about a watched file.
+type specFileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// SpecWatcher detects edits to the spec files while the loop runs, so the
+// next iteration works from the current version rather than the one loaded
+// at startup. Files are compared by modification time and size first and by
+// content only when those differ, so touching a file is not a change.
+type SpecWatcher struct {
+	paths []string
+	known map[string]specFileState
+}
+
+// NewSpecWatcher creates a watcher for paths, taking their current contents
+// as the baseline.
+func NewSpecWatcher(paths []string) *SpecWatcher {
+	w := &SpecWatcher{
+		paths: append([]string(nil), paths...),
+		known: make(map[string]specFileState, len(paths)),
+	}
+	for _, p := range w.paths {
+		w.known[p] = readSpecFileState(p, specFileState{})
+	}
+	return w
+}
+
+// Changed returns the paths whose content changed, or that were removed or
+// created, since the previous call. Each change is reported once.
+func (w *SpecWatcher) Changed() []string {
+	var changed []string
+	for _, p := range w.paths {
+		prev := w.known[p]
+		cur := readSpecFileState(p, prev)
+		w.known[p] = cur
+		if cur.exists != prev.exists || cur.sum != prev.sum {
+			changed = append(changed, p)
+		}
+	}
+	return changed
+}
+
+// readSpecFileState returns the state of path. The content is only hashed
+// when the modification time or size differ from prev.
+func readSpecFileState(path string, prev specFileState) specFileState {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return specFileState{}
+	}
+	cur := specFileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	if prev.exists && cur.modTime.Equal(prev.modTime) && cur.size == prev.size {
+		cur.sum = prev.sum
+		return cur
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return specFileState{}
+	}
+	cur.sum = sha256.Sum256(data)
+	return cur
+}
+
+// FormatSpecChange returns the text appended to step prompts for the changed
+// paths, or "" when there are none.
+func FormatSpecChange(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	lines := make([]string, len(paths))
+	for i, p := range paths {
+		lines[i] = "- " + p
+	}
+	return fmt.Sprintf(SpecChangePrompt, strings.Join(lines, "\n"))
+}
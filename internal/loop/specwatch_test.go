@@ -0,0 +1,108 @@
+package loop
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/completion"
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+)
+
+func TestSpecWatcher_Changed(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.md")
+	ctx := filepath.Join(dir, "context.md")
+	for _, p := range []string{spec, ctx} {
+		if err := os.WriteFile(p, []byte("- [ ] task\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := NewSpecWatcher([]string{spec, ctx})
+	if got := w.Changed(); got != nil {
+		t.Errorf("Changed() before any edit = %v, want nil", got)
+	}
+
+	// Touching a file without changing it is not a change
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(ctx, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Changed(); got != nil {
+		t.Errorf("Changed() after touch = %v, want nil", got)
+	}
+
+	if err := os.WriteFile(spec, []byte("- [ ] task\n- [ ] new task\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Changed(); !reflect.DeepEqual(got, []string{spec}) {
+		t.Errorf("Changed() after edit = %v, want [%s]", got, spec)
+	}
+	if got := w.Changed(); got != nil {
+		t.Errorf("Changed() reported the edit twice: %v", got)
+	}
+
+	if err := os.Remove(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Changed(); !reflect.DeepEqual(got, []string{ctx}) {
+		t.Errorf("Changed() after removal = %v, want [%s]", got, ctx)
+	}
+}
+
+func TestFormatSpecChange(t *testing.T) {
+	if got := FormatSpecChange(nil); got != "" {
+		t.Errorf("FormatSpecChange(nil) = %q, want empty", got)
+	}
+	got := FormatSpecChange([]string{"/work/spec.md"})
+	if !strings.Contains(got, "SPEC CHANGED") || !strings.Contains(got, "- /work/spec.md") {
+		t.Errorf("FormatSpecChange() = %q, want change block", got)
+	}
+}
+
+func TestRun_RefreshesPromptWhenSpecChanges(t *testing.T) {
+	spec := filepath.Join(t.TempDir(), "spec.md")
+	if err := os.WriteFile(spec, []byte("- [ ] task\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.MaxIterations = 3
+
+	exec := newMockExecutor()
+	exec.addResult(&executor.ExecutionResult{Output: "working", Completed: true}, nil)
+	exec.addResult(&executor.ExecutionResult{Output: "working", Completed: true}, nil)
+	exec.addResult(&executor.ExecutionResult{Output: "<promise>COMPLETE</promise>", Completed: true}, nil)
+
+	ctrl := New(cfg, exec, completion.New("<promise>COMPLETE</promise>"))
+	ctrl.SetVerifier(newMockVerifier())
+	ctrl.SetSpecFiles([]string{spec})
+	ctrl.SetIterationCallback(func(iteration int, _ float64, _, _ int) error {
+		if iteration == 1 {
+			return os.WriteFile(spec, []byte("- [ ] task\n- [ ] added mid-run\n"), 0644)
+		}
+		return nil
+	})
+	if _, err := ctrl.Run(context.Background(), "prompt"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(exec.prompts) != 3 {
+		t.Fatalf("prompts = %d, want 3", len(exec.prompts))
+	}
+	if exec.prompts[0] != "prompt" {
+		t.Errorf("first prompt = %q, want no change notice", exec.prompts[0])
+	}
+	if want := "prompt" + FormatSpecChange([]string{spec}); exec.prompts[1] != want {
+		t.Errorf("second prompt = %q, want %q", exec.prompts[1], want)
+	}
+	if exec.prompts[2] != "prompt" {
+		t.Errorf("third prompt = %q, want the change flagged only once", exec.prompts[2])
+	}
+}
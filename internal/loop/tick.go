@@ -0,0 +1,93 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+)
+
+// TickingVerifier keeps the spec files on disk in step with the work: after
+// a verification fails it asks the checker model which unchecked items the
+// working tree already satisfies and ticks them itself, rather than relying
+// on the agent remembering to. The ticks only show progress; the inner
+// verifier judges the spec as the agent left it, so a model wrongly calling
+// an item done never turns into a passed verification.
+type TickingVerifier struct {
+	inner Verifier
+
+	// ask runs a prompt on the checker model in the working directory.
+	ask func(ctx context.Context, prompt string) (*executor.ExecutionResult, error)
+}
+
+// NewTickingVerifier creates a TickingVerifier that asks model, running in
+// workingDir, which items are done after inner finds work left.
+func NewTickingVerifier(inner Verifier, model string, maxBudget float64, workingDir string) *TickingVerifier {
+	return &TickingVerifier{
+		inner: inner,
+		ask: func(ctx context.Context, prompt string) (*executor.ExecutionResult, error) {
+			// Fresh session each time; the model reads the tree itself
+			return executor.New(&config.Config{
+				Model:      model,
+				MaxBudget:  maxBudget,
+				WorkingDir: workingDir,
+			}).Execute(ctx, prompt)
+		},
+	}
+}
+
+// Verify implements Verifier. The inner verification runs first and its
+// verdict stands; only when it finds work left are the done items ticked.
+// Costs and tokens include the tick check, and a tick check that fails is
+// reported as the error.
+func (v *TickingVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	result, err := v.inner.Verify(ctx, files)
+	if err != nil || result == nil || result.Verified {
+		return result, err
+	}
+
+	ticked, cost, tokens, tickErr := v.tick(ctx, files)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	result.Cost += cost
+	result.Tokens += tokens
+	result.Ticked = ticked
+	return result, tickErr
+}
+
+// tick asks the checker model which unchecked items are done and ticks them,
+// returning the items ticked and what the check cost.
+func (v *TickingVerifier) tick(ctx context.Context, files []string) ([]string, float64, int, error) {
+	items, err := spec.UncheckedItems(files)
+	if err != nil || len(items) == 0 {
+		return nil, 0, 0, err
+	}
+
+	result, err := v.ask(ctx, spec.BuildTickPrompt(files, items))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("spec tick check failed: %w", err)
+	}
+	cost, tokens := result.CostUSD, result.TokensIn+result.TokensOut
+
+	done := spec.ParseTickResponse(output.ExtractResult(result.Output))
+	ticked, err := spec.TickItems(files, done)
+	if err != nil {
+		return ticked, cost, tokens, fmt.Errorf("failed to tick spec items: %w", err)
+	}
+	return ticked, cost, tokens, nil
+}
+
+// DescribeTicked returns a status message for the spec items ticked before a
+// verification, e.g. "Ticked 2 spec item(s): Add login; Add logout". It is
+// empty when nothing was ticked.
+func DescribeTicked(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Ticked %d spec item(s): %s", len(items), strings.Join(items, "; "))
+}
@@ -0,0 +1,127 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/executor"
+)
+
+// newTestTicker returns a TickingVerifier over a checkbox-counting verifier
+// whose checker model replies with reply, recording the prompts it was sent.
+func newTestTicker(reply string, err error) (*TickingVerifier, *[]string) {
+	var prompts []string
+	v := &TickingVerifier{
+		inner: &checkboxVerifier{},
+		ask: func(_ context.Context, prompt string) (*executor.ExecutionResult, error) {
+			prompts = append(prompts, prompt)
+			if err != nil {
+				return nil, err
+			}
+			return &executor.ExecutionResult{Output: reply, CostUSD: 0.02, TokensIn: 15, TokensOut: 5}, nil
+		},
+	}
+	return v, &prompts
+}
+
+func TestTickingVerifier_TicksDoneItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	writeSpec(t, path, "- [ ] Add login\n- [ ] Add logout\n")
+	v, prompts := newTestTicker(`{"type":"result","result":"{\"done\": [\"Add login\", \"Not in the spec\"]}"}`, nil)
+
+	result, err := v.Verify(context.Background(), []string{path})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if want := []string{"Add login"}; !reflect.DeepEqual(result.Ticked, want) {
+		t.Errorf("Ticked = %q, want %q", result.Ticked, want)
+	}
+	if result.Verified || result.Unchecked != 2 || result.Checked != 0 {
+		t.Errorf("result = %+v, want the spec verified before ticking: 2 unchecked", result)
+	}
+	if result.Cost != 0.03 || result.Tokens != 30 {
+		t.Errorf("cost, tokens = %v, %d, want both checks summed", result.Cost, result.Tokens)
+	}
+	if len(*prompts) != 1 || !strings.Contains((*prompts)[0], "- [ ] Add logout") {
+		t.Errorf("prompts = %q, want one listing the unchecked items", *prompts)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "- [x] Add login\n- [ ] Add logout\n" {
+		t.Errorf("spec = %q", data)
+	}
+}
+
+func TestTickingVerifier_NothingUnchecked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	writeSpec(t, path, "- [x] Add login\n")
+	v, prompts := newTestTicker("", nil)
+
+	result, err := v.Verify(context.Background(), []string{path})
+	if err != nil || !result.Verified {
+		t.Fatalf("Verify() = %+v, %v, want verified", result, err)
+	}
+	if len(*prompts) != 0 {
+		t.Errorf("checker asked %d times, want no tick check", len(*prompts))
+	}
+}
+
+func TestTickingVerifier_VerifiesBeforeTicking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	writeSpec(t, path, "- [ ] Add login\n")
+	v, _ := newTestTicker(`{"type":"result","result":"{\"done\": [\"Add login\"]}"}`, nil)
+
+	// The model calls the only item done, but the verdict is the spec's own
+	result, err := v.Verify(context.Background(), []string{path})
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Verified || result.Unchecked != 1 {
+		t.Errorf("result = %+v, want unverified with 1 unchecked", result)
+	}
+	if want := []string{"Add login"}; !reflect.DeepEqual(result.Ticked, want) {
+		t.Errorf("Ticked = %q, want %q", result.Ticked, want)
+	}
+}
+
+func TestTickingVerifier_InnerFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	writeSpec(t, path, "- [ ] Add login\n")
+	v, prompts := newTestTicker("", nil)
+	v.inner = &checkboxVerifier{err: errors.New("checker down")}
+
+	if _, err := v.Verify(context.Background(), []string{path}); err == nil {
+		t.Error("Verify() error = nil, want the inner verifier's error")
+	}
+	if len(*prompts) != 0 {
+		t.Errorf("checker asked %d times, want no tick check", len(*prompts))
+	}
+}
+
+func TestTickingVerifier_TickCheckFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	writeSpec(t, path, "- [ ] Add login\n")
+	v, _ := newTestTicker("", errors.New("boom"))
+
+	result, err := v.Verify(context.Background(), []string{path})
+	if err == nil || !strings.Contains(err.Error(), "spec tick check failed") {
+		t.Errorf("Verify() error = %v, want the tick check's error", err)
+	}
+	if result == nil || result.Unchecked != 1 || len(result.Ticked) != 0 {
+		t.Errorf("result = %+v, want the inner verification with nothing ticked", result)
+	}
+}
+
+func TestDescribeTicked(t *testing.T) {
+	if got := DescribeTicked(nil); got != "" {
+		t.Errorf("DescribeTicked(nil) = %q, want empty", got)
+	}
+	if got, want := DescribeTicked([]string{"Add login", "Add logout"}), "Ticked 2 spec item(s): Add login; Add logout"; got != want {
+		t.Errorf("DescribeTicked() = %q, want %q", got, want)
+	}
+}
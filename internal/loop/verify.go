@@ -0,0 +1,407 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+)
+
+// Verification strategy names selectable in [workflow.verification].
+const (
+	// StrategyModel asks the checker model whether every spec checkbox is ticked.
+	StrategyModel = "model"
+	// StrategyCommand runs a shell command; exit status 0 means verified.
+	StrategyCommand = "command"
+	// StrategyHybrid requires both the command and the model check to pass.
+	StrategyHybrid = "hybrid"
+	// StrategyNone skips verification and accepts the completion promise.
+	StrategyNone = "none"
+)
+
+// VerifierOptions configures the verifier built by NewVerifier.
+type VerifierOptions struct {
+	// Strategy is the registered strategy name (default: StrategyModel).
+	Strategy string
+	// Command is the shell command for the command and hybrid strategies.
+	Command string
+	// WorkingDir is the directory the command runs in.
+	WorkingDir string
+	// Timeout bounds the command's run time (0 means no limit beyond the context).
+	Timeout time.Duration
+	// CheckerModel is the model used by the model strategy.
+	CheckerModel string
+	// MaxBudget is passed to the checker model's executor.
+	MaxBudget float64
+	// PerFile checks each spec file on its own and only re-checks the files
+	// that changed or failed (see PerFileVerifier). It applies to the model
+	// check of the model and hybrid strategies.
+	PerFile bool
+	// Prompt replaces spec.VerificationPrompt for the model check. It must
+	// contain {{files}}, which lists the files to verify.
+	Prompt string
+	// VerifiedPattern and IncompletePattern parse the checker model's reply
+	// to a custom Prompt (see ResponseGrammar). Without them the reply must
+	// follow the VERIFIED/INCOMPLETE format ParseVerificationResponse reads.
+	VerifiedPattern   string
+	IncompletePattern string
+	// Files are glob patterns, relative to WorkingDir, selecting the files
+	// verified instead of the session's spec files.
+	Files []string
+}
+
+// grammar returns the response grammar for a custom prompt, or nil to read
+// replies with ParseVerificationResponse.
+func (o VerifierOptions) grammar() (*ResponseGrammar, error) {
+	if o.VerifiedPattern == "" && o.IncompletePattern == "" {
+		return nil, nil
+	}
+	grammar, err := NewResponseGrammar(o.VerifiedPattern, o.IncompletePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification response grammar: %w", err)
+	}
+	return grammar, nil
+}
+
+// validate checks the custom prompt, grammar and file patterns.
+func (o VerifierOptions) validate() error {
+	grammar, err := o.grammar()
+	if err != nil {
+		return err
+	}
+	if o.Prompt != "" {
+		if err := validateVerificationPrompt(o.Prompt, grammar != nil); err != nil {
+			return err
+		}
+	} else if grammar != nil {
+		return errors.New("verification response patterns require a custom prompt")
+	}
+	for _, pattern := range o.Files {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid verification file pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// VerifierFactory builds a Verifier from options.
+type VerifierFactory func(opts VerifierOptions) (Verifier, error)
+
+// verifierRegistry maps strategy names to their factories.
+var verifierRegistry = map[string]VerifierFactory{
+	StrategyModel: func(opts VerifierOptions) (Verifier, error) {
+		return modelCheck(opts), nil
+	},
+	StrategyCommand: func(opts VerifierOptions) (Verifier, error) {
+		if strings.TrimSpace(opts.Command) == "" {
+			return nil, errors.New("verification strategy \"command\" requires a command")
+		}
+		return NewCommandVerifier(opts.Command, opts.WorkingDir, opts.Timeout), nil
+	},
+	StrategyHybrid: func(opts VerifierOptions) (Verifier, error) {
+		if strings.TrimSpace(opts.Command) == "" {
+			return nil, errors.New("verification strategy \"hybrid\" requires a command")
+		}
+		// Run the command first: it is cheap and a failure skips the model call
+		return NewHybridVerifier(
+			NewCommandVerifier(opts.Command, opts.WorkingDir, opts.Timeout),
+			modelCheck(opts),
+		), nil
+	},
+	StrategyNone: func(VerifierOptions) (Verifier, error) {
+		return NoopVerifier{}, nil
+	},
+}
+
+// modelCheck returns the checker model verifier for opts, checking each
+// spec file on its own with opts.PerFile.
+func modelCheck(opts VerifierOptions) Verifier {
+	model := NewModelVerifier(opts.CheckerModel, opts.MaxBudget)
+	// Validated by NewVerifier
+	grammar, _ := opts.grammar()
+	model.SetPrompt(opts.Prompt, grammar)
+
+	var v Verifier = model
+	if opts.PerFile {
+		v = NewPerFileVerifier(v)
+	}
+	return v
+}
+
+// RegisterVerifier registers a verification strategy under name, replacing
+// any existing strategy with that name.
+func RegisterVerifier(name string, factory VerifierFactory) {
+	verifierRegistry[name] = factory
+}
+
+// VerifierStrategies returns the registered strategy names in sorted order.
+func VerifierStrategies() []string {
+	names := make([]string, 0, len(verifierRegistry))
+	for name := range verifierRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewVerifier builds the verifier for opts.Strategy. An empty strategy
+// selects StrategyModel. With opts.Files, the verifier checks the files
+// they match instead of the spec files.
+func NewVerifier(opts VerifierOptions) (Verifier, error) {
+	name := opts.Strategy
+	if name == "" {
+		name = StrategyModel
+	}
+	factory, ok := verifierRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown verification strategy %q (available: %s)", name, strings.Join(VerifierStrategies(), ", "))
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	verifier, err := factory(opts)
+	if err != nil || len(opts.Files) == 0 {
+		return verifier, err
+	}
+	return NewFileSetVerifier(verifier, opts.Files, opts.WorkingDir), nil
+}
+
+// ModelVerifier asks the checker model to count checked and unchecked items
+// in the spec files.
+type ModelVerifier struct {
+	model     string
+	maxBudget float64
+	prompt    string
+	grammar   *ResponseGrammar
+}
+
+// NewModelVerifier creates a ModelVerifier using the given checker model.
+func NewModelVerifier(model string, maxBudget float64) *ModelVerifier {
+	return &ModelVerifier{model: model, maxBudget: maxBudget}
+}
+
+// SetPrompt replaces spec.VerificationPrompt with template, whose {{files}}
+// placeholder lists the files to verify. Replies are parsed with grammar, or
+// with ParseVerificationResponse when it is nil. An empty template restores
+// the default prompt.
+func (v *ModelVerifier) SetPrompt(template string, grammar *ResponseGrammar) {
+	v.prompt = template
+	v.grammar = grammar
+}
+
+// Verify implements Verifier. Each call uses a fresh executor and session.
+func (v *ModelVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no spec files configured for verification")
+	}
+
+	verifyExec := executor.New(&config.Config{
+		Model:     v.model,
+		MaxBudget: v.maxBudget,
+		// No session ID - fresh session each time
+		// No system prompt - just the verification prompt
+	})
+
+	prompt := spec.BuildVerificationPrompt(files)
+	if v.prompt != "" {
+		prompt = spec.BuildCustomVerificationPrompt(v.prompt, files)
+	}
+	result, err := verifyExec.Execute(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("verification execution failed: %w", err)
+	}
+
+	verified, unchecked, checked := ParseVerificationResponse(result.Output)
+	if v.grammar != nil {
+		// Custom patterns are matched against the reply text, not the
+		// JSON-escaped stream
+		verified, unchecked, checked = v.grammar.Parse(output.ExtractResult(result.Output))
+	}
+
+	// The item texts are read locally rather than asked of the checker; a
+	// read failure only loses them, the counts still stand
+	var items []string
+	if !verified {
+		items, _ = spec.UncheckedItems(files)
+	}
+
+	return &VerificationResult{
+		Verified:  verified,
+		Unchecked: unchecked,
+		Checked:   checked,
+		Cost:      result.CostUSD,
+		Tokens:    result.TokensIn + result.TokensOut,
+		Items:     items,
+	}, nil
+}
+
+// commandWaitDelay bounds how long a killed verification command may hold its
+// output open, e.g. when the shell's children outlive it.
+const commandWaitDelay = time.Second
+
+// CommandVerifier runs a shell command; the work is verified when it exits 0.
+type CommandVerifier struct {
+	command string
+	dir     string
+	timeout time.Duration
+}
+
+// NewCommandVerifier creates a CommandVerifier that runs command in dir via sh -c.
+func NewCommandVerifier(command, dir string, timeout time.Duration) *CommandVerifier {
+	return &CommandVerifier{command: command, dir: dir, timeout: timeout}
+}
+
+// Verify implements Verifier. The spec files are not used.
+func (v *CommandVerifier) Verify(ctx context.Context, _ []string) (*VerificationResult, error) {
+	runCtx := ctx
+	if v.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, v.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", v.command)
+	cmd.Dir = v.dir
+	cmd.WaitDelay = commandWaitDelay
+	out, err := cmd.CombinedOutput()
+
+	// Interruption of the whole run is an error, not a failed check
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if err != nil {
+		reason := fmt.Sprintf("`%s` failed: %v", v.command, err)
+		if runCtx.Err() == context.DeadlineExceeded {
+			reason = fmt.Sprintf("`%s` timed out after %s", v.command, v.timeout)
+		}
+		if last := lastLine(string(out)); last != "" {
+			reason += " (" + last + ")"
+		}
+		return &VerificationResult{Verified: false, Unchecked: -1, Checked: -1, Reason: reason}, nil
+	}
+
+	return &VerificationResult{Verified: true, Reason: fmt.Sprintf("`%s` passed", v.command)}, nil
+}
+
+// lastLine returns the last non-empty line of s, trimmed.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// HybridVerifier runs several verifiers in order; all must pass. It stops at
+// the first failure so later (often more expensive) checks are skipped.
+type HybridVerifier struct {
+	verifiers []Verifier
+}
+
+// NewHybridVerifier creates a HybridVerifier from verifiers, run in order.
+func NewHybridVerifier(verifiers ...Verifier) *HybridVerifier {
+	return &HybridVerifier{verifiers: verifiers}
+}
+
+// Verify implements Verifier. Costs and tokens are summed across the checks
+// that ran; checkbox counts come from the checks that report them.
+func (v *HybridVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	combined := &VerificationResult{Verified: true}
+	var reasons []string
+
+	for _, verifier := range v.verifiers {
+		result, err := verifier.Verify(ctx, files)
+		if result != nil {
+			combined.Cost += result.Cost
+			combined.Tokens += result.Tokens
+			if result.Reason == "" {
+				combined.Unchecked = result.Unchecked
+				combined.Checked = result.Checked
+				combined.Files = result.Files
+			}
+		}
+		if err != nil {
+			return combined, err
+		}
+
+		if !result.Verified {
+			combined.Verified = false
+			combined.Unchecked = result.Unchecked
+			combined.Checked = result.Checked
+			combined.Items = result.Items
+			combined.Files = result.Files
+			combined.Reason = result.Describe()
+			return combined, nil
+		}
+		reasons = append(reasons, result.Describe())
+	}
+
+	combined.Reason = strings.Join(reasons, "; ")
+	return combined, nil
+}
+
+// DefaultDoubleCheckModel is the model used for the second check when
+// --double-check-model is not given. It differs from the default checker
+// model so the two checks are independent.
+const DefaultDoubleCheckModel = "sonnet"
+
+// DoubleCheckVerifier confirms a passing verification with a second,
+// independent check and only reports the work as verified when both agree.
+// The second check is skipped when the first one fails.
+type DoubleCheckVerifier struct {
+	first  Verifier
+	second Verifier
+}
+
+// NewDoubleCheckVerifier creates a DoubleCheckVerifier that confirms first
+// with second.
+func NewDoubleCheckVerifier(first, second Verifier) *DoubleCheckVerifier {
+	return &DoubleCheckVerifier{first: first, second: second}
+}
+
+// Verify implements Verifier. Costs and tokens are summed across both checks.
+func (v *DoubleCheckVerifier) Verify(ctx context.Context, files []string) (*VerificationResult, error) {
+	result, err := v.first.Verify(ctx, files)
+	if err != nil || !result.Verified {
+		return result, err
+	}
+
+	check, err := v.second.Verify(ctx, files)
+	combined := *result
+	if check != nil {
+		combined.Cost += check.Cost
+		combined.Tokens += check.Tokens
+	}
+	if err != nil {
+		return &combined, fmt.Errorf("double-check failed: %w", err)
+	}
+
+	if !check.Verified {
+		combined.Verified = false
+		combined.Unchecked = check.Unchecked
+		combined.Checked = check.Checked
+		combined.Items = check.Items
+		combined.Files = check.Files
+		combined.Reason = fmt.Sprintf("%s, but the double-check disagreed: %s", result.Describe(), check.Describe())
+		return &combined, nil
+	}
+
+	combined.Confirmed = true
+	combined.Reason = result.Describe() + "; confirmed by double-check"
+	return &combined, nil
+}
+
+// NoopVerifier accepts every completion without checking.
+type NoopVerifier struct{}
+
+// Verify implements Verifier.
+func (NoopVerifier) Verify(context.Context, []string) (*VerificationResult, error) {
+	return &VerificationResult{Verified: true, Reason: "skipped (strategy none)"}, nil
+}
@@ -0,0 +1,271 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewVerifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    VerifierOptions
+		want    Verifier
+		wantErr string
+	}{
+		{name: "default is model", opts: VerifierOptions{}, want: &ModelVerifier{}},
+		{name: "model", opts: VerifierOptions{Strategy: StrategyModel}, want: &ModelVerifier{}},
+		{name: "model per file", opts: VerifierOptions{Strategy: StrategyModel, PerFile: true}, want: &PerFileVerifier{}},
+		{name: "command", opts: VerifierOptions{Strategy: StrategyCommand, Command: "true"}, want: &CommandVerifier{}},
+		{name: "hybrid", opts: VerifierOptions{Strategy: StrategyHybrid, Command: "true"}, want: &HybridVerifier{}},
+		{name: "none", opts: VerifierOptions{Strategy: StrategyNone}, want: NoopVerifier{}},
+		{name: "command without command", opts: VerifierOptions{Strategy: StrategyCommand}, wantErr: "requires a command"},
+		{name: "hybrid without command", opts: VerifierOptions{Strategy: StrategyHybrid, Command: "  "}, wantErr: "requires a command"},
+		{name: "unknown", opts: VerifierOptions{Strategy: "vibes"}, wantErr: "unknown verification strategy \"vibes\""},
+		{name: "file set", opts: VerifierOptions{Files: []string{"docs/plans/*.md"}}, want: &FileSetVerifier{}},
+		{name: "custom prompt", opts: VerifierOptions{Prompt: "Check {{files}}. Reply VERIFIED: 0 unchecked, N checked or INCOMPLETE: N unchecked, M checked"}, want: &ModelVerifier{}},
+		{
+			name: "custom prompt and grammar",
+			opts: VerifierOptions{
+				Prompt:            "Check {{files}}. Reply DONE (n) or TODO n/m",
+				VerifiedPattern:   `DONE \((?P<checked>\d+)\)`,
+				IncompletePattern: `TODO (?P<unchecked>\d+)/(?P<checked>\d+)`,
+			},
+			want: &ModelVerifier{},
+		},
+		{name: "prompt without files", opts: VerifierOptions{Prompt: "VERIFIED: INCOMPLETE:"}, wantErr: "{{files}}"},
+		{name: "prompt without default grammar", opts: VerifierOptions{Prompt: "Check {{files}}"}, wantErr: `must ask for "VERIFIED:" replies`},
+		{name: "grammar without prompt", opts: VerifierOptions{VerifiedPattern: `(?P<checked>\d+)`, IncompletePattern: `(?P<unchecked>\d+) (?P<checked>\d+)`}, wantErr: "require a custom prompt"},
+		{name: "invalid grammar", opts: VerifierOptions{Prompt: "{{files}}", VerifiedPattern: "DONE"}, wantErr: "invalid verification response grammar"},
+		{name: "invalid file pattern", opts: VerifierOptions{Files: []string{"[a"}}, wantErr: "invalid verification file pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVerifier(tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewVerifier() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewVerifier() error = %v", err)
+			}
+			if gotType, wantType := typeName(got), typeName(tt.want); gotType != wantType {
+				t.Errorf("NewVerifier() = %s, want %s", gotType, wantType)
+			}
+		})
+	}
+}
+
+func typeName(v Verifier) string {
+	switch v.(type) {
+	case *ModelVerifier:
+		return "model"
+	case *CommandVerifier:
+		return "command"
+	case *HybridVerifier:
+		return "hybrid"
+	case *PerFileVerifier:
+		return "per-file"
+	case NoopVerifier:
+		return "none"
+	case *FileSetVerifier:
+		return "file-set"
+	}
+	return "other"
+}
+
+func TestRegisterVerifier(t *testing.T) {
+	defer delete(verifierRegistry, "custom")
+
+	mock := newMockVerifier()
+	RegisterVerifier("custom", func(VerifierOptions) (Verifier, error) { return mock, nil })
+
+	got, err := NewVerifier(VerifierOptions{Strategy: "custom"})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	if got != mock {
+		t.Error("NewVerifier() did not use the registered factory")
+	}
+	if !strings.Contains(strings.Join(VerifierStrategies(), ","), "custom") {
+		t.Errorf("VerifierStrategies() = %v, want custom listed", VerifierStrategies())
+	}
+}
+
+func TestCommandVerifier(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name         string
+		command      string
+		timeout      time.Duration
+		wantVerified bool
+		wantReason   string
+	}{
+		{name: "passes", command: "true", wantVerified: true, wantReason: "`true` passed"},
+		{name: "fails", command: "echo 'FAIL: TestFoo' && exit 1", wantReason: "failed: exit status 1 (FAIL: TestFoo)"},
+		{name: "runs in working dir", command: "test \"$(pwd)\" = '" + dir + "'", wantVerified: true},
+		{name: "times out", command: "sleep 5", timeout: 50 * time.Millisecond, wantReason: "timed out after 50ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewCommandVerifier(tt.command, dir, tt.timeout).Verify(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Verified != tt.wantVerified {
+				t.Errorf("Verified = %v, want %v (%s)", result.Verified, tt.wantVerified, result.Reason)
+			}
+			if !strings.Contains(result.Describe(), tt.wantReason) {
+				t.Errorf("Describe() = %q, want containing %q", result.Describe(), tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCommandVerifier_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewCommandVerifier("true", t.TempDir(), 0).Verify(ctx, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Verify() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestHybridVerifier(t *testing.T) {
+	passCommand := &mockVerifier{result: &VerificationResult{Verified: true, Reason: "`make test` passed"}}
+	failCommand := &mockVerifier{result: &VerificationResult{Verified: false, Unchecked: -1, Checked: -1, Reason: "`make test` failed"}}
+
+	t.Run("all pass", func(t *testing.T) {
+		model := newMockVerifier()
+		result, err := NewHybridVerifier(passCommand, model).Verify(context.Background(), []string{"spec.md"})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if !result.Verified || result.Checked != 5 || result.Cost != 0.001 || result.Tokens != 50 {
+			t.Errorf("result = %+v, want verified with model counts and cost", result)
+		}
+		if result.Describe() != "`make test` passed; all items complete (5 checked)" {
+			t.Errorf("Describe() = %q", result.Describe())
+		}
+	})
+
+	t.Run("stops at first failure", func(t *testing.T) {
+		model := newMockVerifier()
+		result, err := NewHybridVerifier(failCommand, model).Verify(context.Background(), []string{"spec.md"})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if result.Verified || result.Describe() != "`make test` failed" {
+			t.Errorf("result = %+v, want command failure", result)
+		}
+		if model.calls != 0 {
+			t.Errorf("model verifier called %d times after command failed", model.calls)
+		}
+	})
+
+	t.Run("model incomplete", func(t *testing.T) {
+		model := &mockVerifier{result: &VerificationResult{Unchecked: 2, Checked: 3, Cost: 0.01, Items: []string{"a", "b"}}}
+		result, err := NewHybridVerifier(passCommand, model).Verify(context.Background(), []string{"spec.md"})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if result.Verified || result.Describe() != "2 unchecked item(s) remain" || result.Cost != 0.01 {
+			t.Errorf("result = %+v, want model failure with its cost", result)
+		}
+		if len(result.Items) != 2 {
+			t.Errorf("Items = %v, want the model's unchecked items", result.Items)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		model := &mockVerifier{err: errors.New("boom")}
+		if _, err := NewHybridVerifier(passCommand, model).Verify(context.Background(), nil); err == nil {
+			t.Error("Verify() error = nil, want error")
+		}
+	})
+}
+
+func TestVerificationResult_Describe(t *testing.T) {
+	tests := []struct {
+		result VerificationResult
+		want   string
+	}{
+		{VerificationResult{Verified: true, Checked: 4}, "all items complete (4 checked)"},
+		{VerificationResult{Unchecked: 3, Checked: 1}, "3 unchecked item(s) remain"},
+		{VerificationResult{Unchecked: -1, Checked: -1}, "could not parse response"},
+		{VerificationResult{Reason: "skipped (strategy none)", Verified: true}, "skipped (strategy none)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.result.Describe(); got != tt.want {
+			t.Errorf("Describe() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNoopVerifier(t *testing.T) {
+	result, err := NoopVerifier{}.Verify(context.Background(), nil)
+	if err != nil || !result.Verified {
+		t.Errorf("Verify() = %+v, %v; want verified", result, err)
+	}
+}
+
+func TestDoubleCheckVerifier(t *testing.T) {
+	pass := func() *mockVerifier {
+		return &mockVerifier{result: &VerificationResult{Verified: true, Checked: 5, Cost: 0.01, Tokens: 10}}
+	}
+
+	t.Run("agreement confirms", func(t *testing.T) {
+		result, err := NewDoubleCheckVerifier(pass(), pass()).Verify(context.Background(), []string{"spec.md"})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if !result.Verified || !result.Confirmed || result.Cost != 0.02 || result.Tokens != 20 {
+			t.Errorf("result = %+v, want confirmed with both costs", result)
+		}
+		if result.Describe() != "all items complete (5 checked); confirmed by double-check" {
+			t.Errorf("Describe() = %q", result.Describe())
+		}
+	})
+
+	t.Run("disagreement fails", func(t *testing.T) {
+		second := &mockVerifier{result: &VerificationResult{Unchecked: 1, Checked: 4, Items: []string{"Write docs"}}}
+		result, err := NewDoubleCheckVerifier(pass(), second).Verify(context.Background(), []string{"spec.md"})
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if result.Verified || result.Confirmed || result.Unchecked != 1 || len(result.Items) != 1 {
+			t.Errorf("result = %+v, want the second check's failure", result)
+		}
+		if !strings.Contains(result.Describe(), "double-check disagreed: 1 unchecked item(s) remain") {
+			t.Errorf("Describe() = %q", result.Describe())
+		}
+	})
+
+	t.Run("first failure skips the second check", func(t *testing.T) {
+		second := pass()
+		first := &mockVerifier{result: &VerificationResult{Unchecked: 2, Checked: 3}}
+		result, err := NewDoubleCheckVerifier(first, second).Verify(context.Background(), nil)
+		if err != nil || result.Verified {
+			t.Errorf("Verify() = %+v, %v, want unverified", result, err)
+		}
+		if second.calls != 0 {
+			t.Errorf("second check ran %d times", second.calls)
+		}
+	})
+
+	t.Run("second check error", func(t *testing.T) {
+		second := &mockVerifier{err: errors.New("boom")}
+		if _, err := NewDoubleCheckVerifier(pass(), second).Verify(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "double-check") {
+			t.Errorf("Verify() error = %v, want double-check error", err)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// Package memory keeps a project's shared memory: durable facts about the
+// project in .orbital/memory.md that each session starts with and adds to
+// when it ends.
+package memory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxBytes is how much of the memory file goes into the system
+// prompt when max_bytes is unset.
+const DefaultMaxBytes = 4000
+
+// header starts a new memory file.
+const header = `# Project memory
+
+Durable facts about this project, kept by orbital across sessions. Edit freely;
+one fact per bullet. Only the newest facts are given to sessions when the file
+grows past the configured size.
+`
+
+// Config represents the [memory] section.
+type Config struct {
+	// Enabled turns on the shared memory file. Off unless set.
+	Enabled bool `toml:"enabled"`
+
+	// MaxBytes caps how much of the file goes into the system prompt
+	// (default DefaultMaxBytes). The newest facts are kept.
+	MaxBytes int `toml:"max_bytes"`
+}
+
+// EffectiveMaxBytes returns MaxBytes, or DefaultMaxBytes when it is unset.
+func (c *Config) EffectiveMaxBytes() int {
+	if c == nil || c.MaxBytes <= 0 {
+		return DefaultMaxBytes
+	}
+	return c.MaxBytes
+}
+
+// Path returns the memory file of the project in workingDir.
+func Path(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "memory.md")
+}
+
+// Load returns the facts in the memory file, one "- " bullet per line, cut
+// to the newest that fit in maxBytes. It returns "" when there is no file.
+func Load(workingDir string, maxBytes int) (string, error) {
+	data, err := os.ReadFile(Path(workingDir))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read memory file: %w", err)
+	}
+	facts := Facts(string(data))
+
+	// Facts are appended, so the newest are at the end
+	size := 0
+	start := len(facts)
+	for start > 0 {
+		n := len(facts[start-1]) + len("- \n")
+		if size+n > maxBytes {
+			break
+		}
+		size += n
+		start--
+	}
+	var b strings.Builder
+	for _, fact := range facts[start:] {
+		b.WriteString("- ")
+		b.WriteString(fact)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// Facts returns the bullet points in content, without their markers. Other
+// lines, such as headings, are skipped.
+func Facts(content string) []string {
+	var facts []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"- ", "* "} {
+			if fact, ok := strings.CutPrefix(line, marker); ok {
+				if fact = strings.TrimSpace(fact); fact != "" {
+					facts = append(facts, fact)
+				}
+				break
+			}
+		}
+	}
+	return facts
+}
+
+// Append adds the facts not already in the memory file to its end, creating
+// it if needed, and returns those added. Facts are compared ignoring case and
+// surrounding space.
+func Append(workingDir string, facts []string) ([]string, error) {
+	path := Path(workingDir)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read memory file: %w", err)
+	}
+	content := string(data)
+	if content == "" {
+		content = header
+	}
+
+	seen := make(map[string]bool)
+	for _, fact := range Facts(content) {
+		seen[strings.ToLower(fact)] = true
+	}
+	var added []string
+	var b strings.Builder
+	for _, fact := range facts {
+		fact = strings.TrimSpace(fact)
+		key := strings.ToLower(fact)
+		if fact == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		added = append(added, fact)
+		b.WriteString("- ")
+		b.WriteString(fact)
+		b.WriteString("\n")
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if len(data) == 0 {
+		content += "\n"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create memory directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content+b.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write memory file: %w", err)
+	}
+	return added, nil
+}
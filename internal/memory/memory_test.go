@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	facts, err := Load(t.TempDir(), DefaultMaxBytes)
+	if err != nil || facts != "" {
+		t.Errorf("Load() = %q, %v, want nothing", facts, err)
+	}
+}
+
+func TestAppend_CreatesAndDedupes(t *testing.T) {
+	dir := t.TempDir()
+
+	added, err := Append(dir, []string{"We use pnpm", "Tests run with `make test`", "we use PNPM "})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if want := []string{"We use pnpm", "Tests run with `make test`"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("Append() added %q, want %q", added, want)
+	}
+
+	added, err = Append(dir, []string{"Tests run with `make test`", "CI runs on Go 1.24"})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if want := []string{"CI runs on Go 1.24"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("second Append() added %q, want %q", added, want)
+	}
+
+	data, err := os.ReadFile(Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Project memory\n") {
+		t.Errorf("memory file missing header:\n%s", data)
+	}
+	if !strings.HasSuffix(string(data), "\n\n- We use pnpm\n- Tests run with `make test`\n- CI runs on Go 1.24\n") {
+		t.Errorf("memory file =\n%s", data)
+	}
+}
+
+func TestAppend_NothingNew(t *testing.T) {
+	dir := t.TempDir()
+	if added, err := Append(dir, []string{" ", ""}); err != nil || added != nil {
+		t.Errorf("Append() = %q, %v, want nothing", added, err)
+	}
+	if _, err := os.Stat(Path(dir)); !os.IsNotExist(err) {
+		t.Errorf("memory file written with nothing to add: %v", err)
+	}
+}
+
+func TestLoad_KeepsNewestWithinCap(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Append(dir, []string{"oldest fact", "middle fact", "newest fact"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		maxBytes int
+		want     string
+	}{
+		{maxBytes: DefaultMaxBytes, want: "- oldest fact\n- middle fact\n- newest fact\n"},
+		{maxBytes: 30, want: "- middle fact\n- newest fact\n"},
+		{maxBytes: 5, want: ""},
+	}
+	for _, tt := range tests {
+		got, err := Load(dir, tt.maxBytes)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Load(%d) = %q, want %q", tt.maxBytes, got, tt.want)
+		}
+	}
+}
+
+func TestFacts(t *testing.T) {
+	content := "# Project memory\n\nSome intro.\n\n- one\n* two\n  -   three  \n-\n-not a bullet\n"
+	if got, want := Facts(content), []string{"one", "two", "three"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Facts() = %q, want %q", got, want)
+	}
+}
+
+func TestConfig_EffectiveMaxBytes(t *testing.T) {
+	var unset *Config
+	if got := unset.EffectiveMaxBytes(); got != DefaultMaxBytes {
+		t.Errorf("nil EffectiveMaxBytes() = %d", got)
+	}
+	if got := (&Config{MaxBytes: 100}).EffectiveMaxBytes(); got != 100 {
+		t.Errorf("EffectiveMaxBytes() = %d, want 100", got)
+	}
+}
@@ -0,0 +1,80 @@
+package memory
+
+import "strings"
+
+// SystemPromptSection introduces the memory file's facts in the system
+// prompt. {{facts}} is replaced with the facts.
+const SystemPromptSection = `
+
+PROJECT MEMORY
+
+Facts earlier sessions learned about this project. Rely on them unless the
+working tree shows they are out of date:
+
+{{facts}}`
+
+// SummaryPrompt asks the checker model for the durable facts a finished
+// session learned. Placeholders: {{files}}, {{notes}} and {{memory}}.
+const SummaryPrompt = `An orbital session has just finished working through the following spec file(s):
+
+{{files}}
+{{notes}}
+List the durable facts about this project a future session would need and could
+not cheaply rediscover: the package manager, how to build and run the tests,
+conventions, and pitfalls. Leave out anything about this session's task, its
+progress or its files' current contents.
+
+The project memory already holds:
+
+{{memory}}
+
+Do not repeat these facts. Do not modify any files.
+
+Reply with one fact per line, each starting with "- ", and nothing else. Keep each
+fact under 120 characters. Reply with NONE when there is nothing new.`
+
+// FormatSystemPrompt returns the system prompt section carrying facts, or ""
+// when there are none.
+func FormatSystemPrompt(facts string) string {
+	facts = strings.TrimSpace(facts)
+	if facts == "" {
+		return ""
+	}
+	return strings.Replace(SystemPromptSection, "{{facts}}", facts, 1)
+}
+
+// BuildSummaryPrompt generates the prompt for the end-of-session summary.
+// facts is the memory the session started with.
+func BuildSummaryPrompt(files []string, notesFile, facts string) string {
+	var fileList strings.Builder
+	for _, path := range files {
+		fileList.WriteString("- ")
+		fileList.WriteString(path)
+		fileList.WriteString("\n")
+	}
+
+	notes := ""
+	if notesFile != "" {
+		notes = "\nThe session recorded its progress, decisions and blockers in " + notesFile + ".\n"
+	}
+
+	if strings.TrimSpace(facts) == "" {
+		facts = "(nothing yet)"
+	}
+
+	// Single-pass replacement so placeholders inside the memory are left alone
+	r := strings.NewReplacer(
+		"{{files}}", strings.TrimSuffix(fileList.String(), "\n"),
+		"{{notes}}", notes,
+		"{{memory}}", strings.TrimSpace(facts),
+	)
+	return r.Replace(SummaryPrompt)
+}
+
+// ParseSummary returns the facts in the summary model's reply.
+func ParseSummary(reply string) []string {
+	if strings.TrimSpace(reply) == "NONE" {
+		return nil
+	}
+	return Facts(reply)
+}
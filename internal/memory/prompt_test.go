@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatSystemPrompt(t *testing.T) {
+	if got := FormatSystemPrompt("  \n"); got != "" {
+		t.Errorf("FormatSystemPrompt() with no facts = %q, want empty", got)
+	}
+	got := FormatSystemPrompt("- We use pnpm\n")
+	if !strings.Contains(got, "PROJECT MEMORY") || !strings.HasSuffix(got, "\n\n- We use pnpm") {
+		t.Errorf("FormatSystemPrompt() = %q", got)
+	}
+}
+
+func TestBuildSummaryPrompt(t *testing.T) {
+	prompt := BuildSummaryPrompt([]string{"/path/spec.md"}, "/path/notes.md", "- {{files}} are specs\n")
+	for _, want := range []string{"- /path/spec.md", "/path/notes.md", "- {{files}} are specs", "Reply with NONE"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+
+	prompt = BuildSummaryPrompt([]string{"/path/spec.md"}, "", "")
+	if !strings.Contains(prompt, "(nothing yet)") || strings.Contains(prompt, "{{") {
+		t.Errorf("prompt = %q", prompt)
+	}
+}
+
+func TestParseSummary(t *testing.T) {
+	if got := ParseSummary(" NONE\n"); got != nil {
+		t.Errorf("ParseSummary(NONE) = %q, want nil", got)
+	}
+	got := ParseSummary("Here you go:\n- We use pnpm\n- Lint with `make lint`\n")
+	if want := []string{"We use pnpm", "Lint with `make lint`"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSummary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+// Package notify provides terminal bell and desktop notifications for key session events.
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// Event identifies a notable session event.
+type Event string
+
+const (
+	// EventCompleted fires when the session completes and verification passes.
+	EventCompleted Event = "complete"
+
+	// EventGateFailed fires when a gate exceeds its maximum retries.
+	EventGateFailed Event = "gate_failed"
+
+	// EventBudgetThreshold fires once when spend crosses the budget threshold.
+	EventBudgetThreshold Event = "budget"
+
+	// EventCostAnomaly fires when an iteration costs far more than the
+	// recent baseline.
+	EventCostAnomaly Event = "cost_anomaly"
+
+	// EventApproval fires when a human gate is waiting for a decision.
+	EventApproval Event = "approval"
+)
+
+// DefaultBudgetThreshold is the fraction of the budget at which a notification fires (90%).
+const DefaultBudgetThreshold = 0.9
+
+// Config controls which notifications are emitted.
+type Config struct {
+	// Bell writes a terminal bell character when an event fires.
+	Bell bool `toml:"bell"`
+
+	// Desktop sends a desktop notification via osascript (macOS) or notify-send (Linux).
+	Desktop bool `toml:"desktop"`
+
+	// Events restricts notifications to the listed events.
+	// Empty means all events are enabled.
+	Events []string `toml:"events"`
+
+	// BudgetThreshold is the fraction of the budget that triggers a budget event (default: 0.9).
+	BudgetThreshold float64 `toml:"budget_threshold"`
+}
+
+// EffectiveBudgetThreshold returns the configured budget threshold or the default if not set.
+func (c *Config) EffectiveBudgetThreshold() float64 {
+	if c.BudgetThreshold > 0 && c.BudgetThreshold <= 1 {
+		return c.BudgetThreshold
+	}
+	return DefaultBudgetThreshold
+}
+
+// Enabled returns true if the given event should produce a notification.
+func (c *Config) Enabled(event Event) bool {
+	if !c.Bell && !c.Desktop {
+		return false
+	}
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if Event(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandRunner runs an external command. It allows desktop notifications to be mocked in tests.
+type CommandRunner func(name string, args ...string) error
+
+// runCommand is the default CommandRunner that executes the command and waits for it.
+func runCommand(name string, args ...string) error {
+	return exec.Command(name, args...).Run()
+}
+
+// Notifier emits notifications for session events.
+// A nil Notifier is valid and does nothing.
+type Notifier struct {
+	config      Config
+	bellWriter  io.Writer
+	runner      CommandRunner
+	goos        string
+	mu          sync.Mutex
+	budgetFired bool
+}
+
+// New creates a Notifier with the given configuration.
+// The bell is written to stderr so it reaches the terminal even when the TUI owns stdout.
+func New(cfg Config) *Notifier {
+	return &Notifier{
+		config:     cfg,
+		bellWriter: os.Stderr,
+		runner:     runCommand,
+		goos:       runtime.GOOS,
+	}
+}
+
+// SetBellWriter sets the writer that receives the bell character.
+func (n *Notifier) SetBellWriter(w io.Writer) {
+	n.bellWriter = w
+}
+
+// SetCommandRunner sets the function used to run desktop notification commands.
+func (n *Notifier) SetCommandRunner(r CommandRunner) {
+	n.runner = r
+}
+
+// Notify emits a notification for the event if it is enabled.
+// Desktop notification failures are returned but never fatal to the caller.
+func (n *Notifier) Notify(event Event, title, message string) error {
+	if n == nil || !n.config.Enabled(event) {
+		return nil
+	}
+
+	if n.config.Bell && n.bellWriter != nil {
+		_, _ = fmt.Fprint(n.bellWriter, "\a")
+	}
+
+	if n.config.Desktop {
+		name, args := desktopCommand(n.goos, title, message)
+		if name == "" {
+			return fmt.Errorf("desktop notifications not supported on %s", n.goos)
+		}
+		if err := n.runner(name, args...); err != nil {
+			return fmt.Errorf("failed to send desktop notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CheckBudget fires a budget event the first time cost crosses the configured threshold.
+// Subsequent calls are no-ops so the user is only notified once per session.
+func (n *Notifier) CheckBudget(cost, budget float64) error {
+	if n == nil || budget <= 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	if n.budgetFired || cost < budget*n.config.EffectiveBudgetThreshold() {
+		n.mu.Unlock()
+		return nil
+	}
+	n.budgetFired = true
+	n.mu.Unlock()
+
+	percent := int(cost / budget * 100)
+	return n.Notify(EventBudgetThreshold, "Orbital budget warning",
+		fmt.Sprintf("Spent %s of %s budget (%d%%)", util.FormatCost(cost, 2), util.FormatCost(budget, 2), percent))
+}
+
+// desktopCommand returns the command and arguments to show a desktop notification.
+// Returns an empty name if the platform is not supported.
+func desktopCommand(goos, title, message string) (string, []string) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		return "osascript", []string{"-e", script}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{title, message}
+	default:
+		return "", nil
+	}
+}
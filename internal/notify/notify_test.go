@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// recordingRunner captures desktop notification commands.
+type recordingRunner struct {
+	calls []string
+	err   error
+}
+
+func (r *recordingRunner) run(name string, args ...string) error {
+	r.calls = append(r.calls, name+" "+strings.Join(args, " "))
+	return r.err
+}
+
+func newTestNotifier(cfg Config, goos string) (*Notifier, *bytes.Buffer, *recordingRunner) {
+	var bell bytes.Buffer
+	runner := &recordingRunner{}
+	n := New(cfg)
+	n.SetBellWriter(&bell)
+	n.SetCommandRunner(runner.run)
+	n.goos = goos
+	return n, &bell, runner
+}
+
+func TestConfig_Enabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		event Event
+		want  bool
+	}{
+		{"disabled when neither bell nor desktop", Config{}, EventCompleted, false},
+		{"all events when list empty", Config{Bell: true}, EventGateFailed, true},
+		{"listed event enabled", Config{Desktop: true, Events: []string{"complete"}}, EventCompleted, true},
+		{"unlisted event disabled", Config{Desktop: true, Events: []string{"complete"}}, EventBudgetThreshold, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Enabled(tt.event); got != tt.want {
+				t.Errorf("Enabled(%q) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_EffectiveBudgetThreshold(t *testing.T) {
+	tests := []struct {
+		threshold float64
+		want      float64
+	}{
+		{0, DefaultBudgetThreshold},
+		{0.75, 0.75},
+		{1.5, DefaultBudgetThreshold},
+		{-1, DefaultBudgetThreshold},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{BudgetThreshold: tt.threshold}
+		if got := cfg.EffectiveBudgetThreshold(); got != tt.want {
+			t.Errorf("EffectiveBudgetThreshold() with %v = %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestNotifier_Notify_Bell(t *testing.T) {
+	n, bell, runner := newTestNotifier(Config{Bell: true}, "linux")
+
+	if err := n.Notify(EventCompleted, "Orbital", "done"); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if bell.String() != "\a" {
+		t.Errorf("bell output = %q, want %q", bell.String(), "\a")
+	}
+	if len(runner.calls) != 0 {
+		t.Errorf("desktop commands = %v, want none", runner.calls)
+	}
+}
+
+func TestNotifier_Notify_Desktop(t *testing.T) {
+	tests := []struct {
+		goos    string
+		wantCmd string
+	}{
+		{"linux", "notify-send Orbital done"},
+		{"darwin", "osascript -e display notification \"done\" with title \"Orbital\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			n, bell, runner := newTestNotifier(Config{Desktop: true}, tt.goos)
+
+			if err := n.Notify(EventCompleted, "Orbital", "done"); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if bell.Len() != 0 {
+				t.Errorf("bell output = %q, want empty", bell.String())
+			}
+			if len(runner.calls) != 1 || runner.calls[0] != tt.wantCmd {
+				t.Errorf("desktop commands = %v, want [%s]", runner.calls, tt.wantCmd)
+			}
+		})
+	}
+}
+
+func TestNotifier_Notify_UnsupportedPlatform(t *testing.T) {
+	n, _, _ := newTestNotifier(Config{Desktop: true}, "plan9")
+
+	if err := n.Notify(EventCompleted, "Orbital", "done"); err == nil {
+		t.Error("Notify() error = nil, want error for unsupported platform")
+	}
+}
+
+func TestNotifier_Notify_RunnerError(t *testing.T) {
+	n, _, runner := newTestNotifier(Config{Desktop: true}, "linux")
+	runner.err = errors.New("notify-send: not found")
+
+	if err := n.Notify(EventCompleted, "Orbital", "done"); err == nil {
+		t.Error("Notify() error = nil, want error when command fails")
+	}
+}
+
+func TestNotifier_Notify_NilIsNoop(t *testing.T) {
+	var n *Notifier
+	if err := n.Notify(EventCompleted, "Orbital", "done"); err != nil {
+		t.Errorf("Notify() on nil notifier error = %v, want nil", err)
+	}
+	if err := n.CheckBudget(100, 100); err != nil {
+		t.Errorf("CheckBudget() on nil notifier error = %v, want nil", err)
+	}
+}
+
+func TestNotifier_CheckBudget(t *testing.T) {
+	n, bell, _ := newTestNotifier(Config{Bell: true}, "linux")
+
+	if err := n.CheckBudget(80, 100); err != nil {
+		t.Fatalf("CheckBudget() error = %v", err)
+	}
+	if bell.Len() != 0 {
+		t.Fatalf("bell rang below threshold")
+	}
+
+	if err := n.CheckBudget(90, 100); err != nil {
+		t.Fatalf("CheckBudget() error = %v", err)
+	}
+	if bell.String() != "\a" {
+		t.Fatalf("bell output = %q after crossing threshold, want one bell", bell.String())
+	}
+
+	// Only fires once per session
+	if err := n.CheckBudget(95, 100); err != nil {
+		t.Fatalf("CheckBudget() error = %v", err)
+	}
+	if bell.String() != "\a" {
+		t.Errorf("bell output = %q, want a single bell", bell.String())
+	}
+}
+
+func TestNotifier_CheckBudget_ZeroBudget(t *testing.T) {
+	n, bell, _ := newTestNotifier(Config{Bell: true}, "linux")
+
+	if err := n.CheckBudget(10, 0); err != nil {
+		t.Fatalf("CheckBudget() error = %v", err)
+	}
+	if bell.Len() != 0 {
+		t.Error("bell rang with zero budget")
+	}
+}
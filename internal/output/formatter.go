@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	orberrors "github.com/flashingpumpkin/orbital/internal/errors"
+	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
 // Formatter handles formatted output for orbit.
@@ -23,21 +26,24 @@ type Formatter struct {
 
 // BannerConfig contains all configuration for the rich banner display.
 type BannerConfig struct {
-	SpecFile      string
-	ContextFiles  []string
-	WorkflowName  string
-	WorkflowSteps int
-	HasGates      bool
-	Model         string
-	CheckerModel  string
-	MaxIterations int
-	Budget        float64
-	Timeout       time.Duration
-	WorkingDir    string
-	NotesFile     string
-	SessionID     string
-	DryRun        bool
-	Debug         bool
+	SpecFile     string
+	ContextFiles []string
+	// AutoContextFiles lists the context files discovered from spec references
+	// (a subset of ContextFiles); they are marked in the banner.
+	AutoContextFiles []string
+	WorkflowName     string
+	WorkflowSteps    int
+	HasGates         bool
+	Model            string
+	CheckerModel     string
+	MaxIterations    int
+	Budget           float64
+	Timeout          time.Duration
+	WorkingDir       string
+	NotesFile        string
+	SessionID        string
+	DryRun           bool
+	Debug            bool
 }
 
 // LoopSummary contains summary information for loop execution.
@@ -51,6 +57,31 @@ type LoopSummary struct {
 	Completed   bool
 	Error       error
 	SessionID   string // For resume instructions on interrupt
+
+	// IterationModels lists the model that served each iteration, in order.
+	IterationModels []string
+
+	// Escalations describes each switch to the workflow's escalation model
+	// after repeated gate failures, in order.
+	Escalations []string
+
+	// Redactions describes the secrets masked in Claude's output.
+	Redactions string
+
+	// CPUTime is the CPU time used by Claude processes.
+	CPUTime time.Duration
+
+	// PeakRSS is the highest resident memory of a Claude process, in bytes.
+	PeakRSS int64
+
+	// CacheReadTokens and CacheCreationTokens are the parts of TokensIn
+	// read from and written to the prompt cache.
+	CacheReadTokens     int
+	CacheCreationTokens int
+
+	// Diagnosis classifies why a run that did not complete ended, one
+	// finding per entry, most telling first.
+	Diagnosis []string
 }
 
 // NewFormatter creates a new Formatter with the specified options.
@@ -92,9 +123,21 @@ func (f *Formatter) PrintRichBanner(cfg BannerConfig) {
 
 	// Context files
 	if len(cfg.ContextFiles) > 0 {
-		_, _ = white.Fprintf(f.writer, "  Context:     %d file(s)\n", len(cfg.ContextFiles))
+		auto := make(map[string]bool, len(cfg.AutoContextFiles))
+		for _, path := range cfg.AutoContextFiles {
+			auto[path] = true
+		}
+		_, _ = white.Fprintf(f.writer, "  Context:     %d file(s)", len(cfg.ContextFiles))
+		if len(cfg.AutoContextFiles) > 0 {
+			_, _ = dim.Fprintf(f.writer, " (%d auto-added from spec)", len(cfg.AutoContextFiles))
+		}
+		_, _ = fmt.Fprintln(f.writer)
 		for _, path := range cfg.ContextFiles {
-			_, _ = dim.Fprintf(f.writer, "               - %s\n", path)
+			if auto[path] {
+				_, _ = dim.Fprintf(f.writer, "               - %s (auto)\n", path)
+			} else {
+				_, _ = dim.Fprintf(f.writer, "               - %s\n", path)
+			}
 		}
 	}
 
@@ -112,7 +155,7 @@ func (f *Formatter) PrintRichBanner(cfg BannerConfig) {
 
 	// Limits
 	_, _ = white.Fprintf(f.writer, "  Iterations:  max %d\n", cfg.MaxIterations)
-	_, _ = white.Fprintf(f.writer, "  Budget:      $%.2f USD\n", cfg.Budget)
+	_, _ = white.Fprintf(f.writer, "  Budget:      %s %s\n", util.FormatCost(cfg.Budget, 2), util.CurrencyCode())
 	_, _ = white.Fprintf(f.writer, "  Timeout:     %v per iteration\n", cfg.Timeout)
 
 	// Paths
@@ -152,7 +195,7 @@ func (f *Formatter) PrintLoopSummary(summary LoopSummary) {
 	_, _ = cyan.Fprintln(f.writer, "════════════════════════════════════════════════════════════════")
 	_, _ = white.Fprintf(f.writer, "  Iterations:   %d\n", summary.Iterations)
 	_, _ = white.Fprintf(f.writer, "  Duration:     %v\n", formatDuration(summary.Duration))
-	_, _ = white.Fprintf(f.writer, "  Cost:         $%.4f USD\n", summary.TotalCost)
+	_, _ = white.Fprintf(f.writer, "  Cost:         %s %s\n", util.FormatCost(summary.TotalCost, 4), util.CurrencyCode())
 
 	// Show detailed token breakdown if available, otherwise fall back to TotalTokens
 	if summary.TokensIn > 0 || summary.TokensOut > 0 {
@@ -161,6 +204,35 @@ func (f *Formatter) PrintLoopSummary(summary LoopSummary) {
 		_, _ = white.Fprintf(f.writer, "  Tokens:       %d\n", summary.TotalTokens)
 	}
 
+	// How much of the input the prompt cache served
+	if cache := FormatCacheUsage(summary.CacheReadTokens, summary.CacheCreationTokens, summary.TokensIn); cache != "" {
+		_, _ = white.Fprintf(f.writer, "  Cache:        %s\n", cache)
+	}
+
+	// Show which models served the run when a fallback occurred
+	if models := FormatModelUsage(summary.IterationModels); models != "" {
+		_, _ = white.Fprintf(f.writer, "  Models:       %s\n", models)
+	}
+
+	// Gates that failed often enough to switch to a stronger model
+	for i, escalation := range summary.Escalations {
+		label := "  Escalations:  "
+		if i > 0 {
+			label = "                "
+		}
+		_, _ = white.Fprintf(f.writer, "%s%s\n", label, escalation)
+	}
+
+	// Secrets kept out of the display, logs and transcripts
+	if summary.Redactions != "" {
+		_, _ = white.Fprintf(f.writer, "  Redacted:     %s\n", summary.Redactions)
+	}
+
+	// Machine resources, for users running several sessions on one box
+	if resources := FormatResources(summary.CPUTime, summary.PeakRSS); resources != "" {
+		_, _ = white.Fprintf(f.writer, "  Resources:    %s\n", resources)
+	}
+
 	// Status line with appropriate colour
 	if summary.Completed {
 		_, _ = green.Fprintln(f.writer, "  Status:       COMPLETED")
@@ -182,6 +254,15 @@ func (f *Formatter) PrintLoopSummary(summary LoopSummary) {
 		_, _ = red.Fprintln(f.writer, "  Status:       NOT COMPLETED")
 	}
 
+	// Why the run did not complete, to decide what to do next
+	for i, finding := range summary.Diagnosis {
+		label := "  Diagnosis:    "
+		if i > 0 {
+			label = "                "
+		}
+		_, _ = white.Fprintf(f.writer, "%s%s\n", label, finding)
+	}
+
 	// Show resume instructions if session has a session ID and can be resumed
 	// This includes interrupted sessions and other non-completed states
 	if summary.SessionID != "" && !summary.Completed {
@@ -193,6 +274,73 @@ func (f *Formatter) PrintLoopSummary(summary LoopSummary) {
 	_, _ = fmt.Fprintln(f.writer, "")
 }
 
+// FormatModelUsage summarises per-iteration models as contiguous ranges,
+// e.g. "opus (1-3), sonnet (4-5)". Returns "" if only one model was used.
+func FormatModelUsage(models []string) string {
+	if len(models) == 0 {
+		return ""
+	}
+
+	var parts []string
+	distinct := make(map[string]bool)
+	start := 0
+	for i := 1; i <= len(models); i++ {
+		if i < len(models) && models[i] == models[start] {
+			continue
+		}
+		distinct[models[start]] = true
+		if i-start == 1 {
+			parts = append(parts, fmt.Sprintf("%s (%d)", models[start], start+1))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s (%d-%d)", models[start], start+1, i))
+		}
+		start = i
+	}
+
+	if len(distinct) < 2 {
+		return ""
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatResources describes CPU time and peak memory, e.g.
+// "CPU 1m12s, peak RSS 412.5 MB". Unknown values are left out; returns ""
+// when both are unknown.
+func FormatResources(cpu time.Duration, peakRSS int64) string {
+	var parts []string
+	if cpu > 0 {
+		parts = append(parts, "CPU "+formatDuration(cpu.Round(time.Second)))
+	}
+	if peakRSS > 0 {
+		parts = append(parts, "peak RSS "+FormatBytes(peakRSS))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatCacheUsage describes prompt cache usage against the input tokens,
+// e.g. "72% hit (7200 read, 800 written)". Returns "" when the cache was
+// not used.
+func FormatCacheUsage(read, creation, tokensIn int) string {
+	if read == 0 && creation == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% hit (%d read, %d written)", CacheHitRatio(read, tokensIn)*100, read, creation)
+}
+
+// FormatBytes formats a byte count with a binary unit, e.g. "412.5 MB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 // formatDuration formats a duration in a human-readable way.
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -223,7 +371,7 @@ func (f *Formatter) PrintStepComplete(name string, duration time.Duration, cost
 	}
 
 	white := color.New(color.FgWhite)
-	_, _ = white.Fprintf(f.writer, "  Completed in %s | $%.4f | %d tokens\n", formatDuration(duration), cost, tokens)
+	_, _ = white.Fprintf(f.writer, "  Completed in %s | %s | %d tokens\n", formatDuration(duration), util.FormatCost(cost, 4), tokens)
 }
 
 // PrintGateResult prints the result of a gate check.
@@ -241,13 +389,55 @@ func (f *Formatter) PrintGateResult(passed bool, retries, maxRetries int) {
 	}
 }
 
+// PrintDryStep prints the prompt a dry step would have sent.
+func (f *Formatter) PrintDryStep(name, prompt string) {
+	if f.quiet {
+		return
+	}
+
+	yellow := color.New(color.FgYellow)
+	_, _ = yellow.Fprintf(f.writer, "  Dry step %s: prompt not sent\n", name)
+	for _, line := range strings.Split(strings.TrimRight(prompt, "\n"), "\n") {
+		_, _ = fmt.Fprintf(f.writer, "  │ %s\n", line)
+	}
+}
+
+// PrintGateScores prints a gate judge's rubric scores.
+func (f *Formatter) PrintGateScores(scores map[string]float64) {
+	if f.quiet || len(scores) == 0 {
+		return
+	}
+
+	white := color.New(color.FgWhite)
+	_, _ = white.Fprintf(f.writer, "  Scores: %s\n", FormatScores(scores))
+}
+
+// FormatScores renders rubric scores sorted by criterion, e.g.
+// "correctness 4, overall 4, tests 3.5".
+func FormatScores(scores map[string]float64) string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %g", name, scores[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
 // StepSummary contains summary information for a completed step.
 type StepSummary struct {
 	Name       string
-	Status     string // "passed", "failed", "completed"
+	Status     string // "passed", "failed", "completed", "dry"
 	Cost       float64
 	Tokens     int
-	GateResult string // "PASS", "FAIL", "" for non-gate steps
+	GateResult string             // "PASS", "FAIL", "" for non-gate steps
+	Model      string             // Fallback or escalation model that served the step, "" for the primary model
+	Escalated  bool               // Model is the escalation model after repeated gate failures
+	Scores     map[string]float64 // Rubric scores reported by a gate judge
 }
 
 // PrintWorkflowSummary prints a summary of all completed workflow steps.
@@ -264,6 +454,7 @@ func (f *Formatter) PrintWorkflowSummary(steps []StepSummary, totalCost float64,
 	white := color.New(color.FgWhite)
 	green := color.New(color.FgGreen)
 	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
 
 	for i, step := range steps {
 		var statusIcon string
@@ -276,6 +467,9 @@ func (f *Formatter) PrintWorkflowSummary(steps []StepSummary, totalCost float64,
 		case "failed":
 			statusIcon = "✗"
 			statusColor = red
+		case "dry":
+			statusIcon = "○"
+			statusColor = yellow
 		default:
 			statusIcon = "•"
 			statusColor = white
@@ -294,11 +488,26 @@ func (f *Formatter) PrintWorkflowSummary(steps []StepSummary, totalCost float64,
 		}
 		_, _ = fmt.Fprintln(f.writer)
 
+		if step.Status == "dry" {
+			_, _ = white.Fprintln(f.writer, "      dry run, prompt not sent")
+			continue
+		}
+
 		// Print cost and tokens
-		_, _ = white.Fprintf(f.writer, "      $%.4f | %d tokens\n", step.Cost, step.Tokens)
+		_, _ = white.Fprintf(f.writer, "      %s | %d tokens", util.FormatCost(step.Cost, 4), step.Tokens)
+		switch {
+		case step.Escalated:
+			_, _ = white.Fprintf(f.writer, " | escalated to %s", step.Model)
+		case step.Model != "":
+			_, _ = white.Fprintf(f.writer, " | via %s", step.Model)
+		}
+		_, _ = fmt.Fprintln(f.writer)
+		if len(step.Scores) > 0 {
+			_, _ = white.Fprintf(f.writer, "      scores: %s\n", FormatScores(step.Scores))
+		}
 	}
 
 	// Print totals
 	_, _ = fmt.Fprintln(f.writer, "")
-	_, _ = white.Fprintf(f.writer, "  Total: $%.4f | %d tokens\n", totalCost, totalTokens)
+	_, _ = white.Fprintf(f.writer, "  Total: %s | %d tokens\n", util.FormatCost(totalCost, 4), totalTokens)
 }
@@ -144,6 +144,32 @@ func TestPrintRichBanner(t *testing.T) {
 	}
 }
 
+func TestPrintRichBanner_AutoContext(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	cfg := BannerConfig{
+		SpecFile:         "spec.md",
+		ContextFiles:     []string{"manual.md", "internal/foo.go"},
+		AutoContextFiles: []string{"internal/foo.go"},
+		WorkflowName:     "fast",
+		WorkflowSteps:    1,
+	}
+
+	f.PrintRichBanner(cfg)
+	output := buf.String()
+
+	if !strings.Contains(output, "2 file(s) (1 auto-added from spec)") {
+		t.Errorf("expected auto-added count, got:\n%s", output)
+	}
+	if !strings.Contains(output, "internal/foo.go (auto)") {
+		t.Error("expected auto-added file to be marked")
+	}
+	if strings.Contains(output, "manual.md (auto)") {
+		t.Error("expected manual context file not to be marked")
+	}
+}
+
 func TestPrintRichBanner_WithSessionID(t *testing.T) {
 	var buf bytes.Buffer
 	f := NewFormatter(false, false, &buf)
@@ -357,6 +383,198 @@ func TestPrintLoopSummary_TokenBreakdown(t *testing.T) {
 	}
 }
 
+func TestPrintLoopSummary_CacheUsage(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintLoopSummary(LoopSummary{
+		Iterations:          2,
+		TokensIn:            10000,
+		TokensOut:           500,
+		CacheReadTokens:     7200,
+		CacheCreationTokens: 800,
+		Completed:           true,
+	})
+
+	if !strings.Contains(buf.String(), "Cache:        72% hit (7200 read, 800 written)") {
+		t.Errorf("expected cache usage line, got: %s", buf.String())
+	}
+}
+
+func TestPrintLoopSummary_ModelFallback(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintLoopSummary(LoopSummary{
+		Iterations:      3,
+		Completed:       true,
+		IterationModels: []string{"opus", "opus", "sonnet"},
+	})
+
+	if !strings.Contains(buf.String(), "Models:       opus (1-2), sonnet (3)") {
+		t.Errorf("expected model usage line, got: %s", buf.String())
+	}
+}
+
+func TestPrintLoopSummary_Escalations(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintLoopSummary(LoopSummary{
+		Iterations:  2,
+		Completed:   true,
+		Escalations: []string{"iteration 1: gate review failed 2 times, escalating to opus"},
+	})
+
+	if !strings.Contains(buf.String(), "Escalations:  iteration 1: gate review failed 2 times, escalating to opus") {
+		t.Errorf("expected escalation line, got: %s", buf.String())
+	}
+}
+
+func TestPrintLoopSummary_Diagnosis(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintLoopSummary(LoopSummary{
+		Iterations: 8,
+		Error:      orberrors.ErrMaxIterationsReached,
+		Diagnosis: []string{
+			`gate "review" exhausted retries 3× in iteration 4`,
+			"4 iterations made no repository changes (5-8)",
+		},
+	})
+
+	out := buf.String()
+	if !strings.Contains(out, "Diagnosis:    gate \"review\" exhausted retries 3× in iteration 4\n") ||
+		!strings.Contains(out, "\n                4 iterations made no repository changes (5-8)\n") {
+		t.Errorf("expected a diagnosis line per finding, got: %s", out)
+	}
+	if strings.Index(out, "Diagnosis:") < strings.Index(out, "Status:") {
+		t.Errorf("expected the diagnosis below the status, got: %s", out)
+	}
+}
+
+func TestPrintLoopSummary_Redactions(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintLoopSummary(LoopSummary{Iterations: 1, Completed: true})
+	if strings.Contains(buf.String(), "Redacted:") {
+		t.Errorf("unexpected redaction line without redactions, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	f.PrintLoopSummary(LoopSummary{Iterations: 1, Completed: true, Redactions: "3 (2 aws-access-key, 1 jwt)"})
+	if !strings.Contains(buf.String(), "Redacted:     3 (2 aws-access-key, 1 jwt)") {
+		t.Errorf("expected redaction line, got: %s", buf.String())
+	}
+}
+
+func TestFormatModelUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		models []string
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"single model", []string{"opus", "opus"}, ""},
+		{"fallback", []string{"opus", "sonnet", "sonnet"}, "opus (1), sonnet (2-3)"},
+		{"back and forth", []string{"opus", "sonnet", "opus"}, "opus (1), sonnet (2), opus (3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatModelUsage(tt.models); got != tt.want {
+				t.Errorf("FormatModelUsage(%v) = %q, want %q", tt.models, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatScores(t *testing.T) {
+	got := FormatScores(map[string]float64{"tests": 3.5, "correctness": 4, "overall": 4})
+	if want := "correctness 4, overall 4, tests 3.5"; got != want {
+		t.Errorf("FormatScores() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatCacheUsage(t *testing.T) {
+	tests := []struct {
+		name                     string
+		read, creation, tokensIn int
+		want                     string
+	}{
+		{"unused", 0, 0, 1000, ""},
+		{"cold", 0, 900, 1000, "0% hit (0 read, 900 written)"},
+		{"warm", 7200, 800, 10000, "72% hit (7200 read, 800 written)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCacheUsage(tt.read, tt.creation, tt.tokensIn); got != tt.want {
+				t.Errorf("FormatCacheUsage(%d, %d, %d) = %q, want %q", tt.read, tt.creation, tt.tokensIn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatResources(t *testing.T) {
+	tests := []struct {
+		name string
+		cpu  time.Duration
+		rss  int64
+		want string
+	}{
+		{"unknown", 0, 0, ""},
+		{"cpu only", 72 * time.Second, 0, "CPU 1m12s"},
+		{"both", 4500 * time.Millisecond, 412 << 20, "CPU 5s, peak RSS 412.0 MB"},
+		{"small rss", 0, 900, "peak RSS 900 B"},
+		{"gigabytes", 0, 3 << 30, "peak RSS 3.0 GB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatResources(tt.cpu, tt.rss); got != tt.want {
+				t.Errorf("FormatResources(%v, %d) = %q, want %q", tt.cpu, tt.rss, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintWorkflowSummary_Scores(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintWorkflowSummary([]StepSummary{
+		{Name: "implement", Status: "completed"},
+		{Name: "review", Status: "passed", GateResult: "PASS", Scores: map[string]float64{"overall": 4}},
+	}, 0.1, 100)
+
+	out := buf.String()
+	if !strings.Contains(out, "scores: overall 4") {
+		t.Errorf("summary missing scores: %q", out)
+	}
+	if strings.Count(out, "scores:") != 1 {
+		t.Errorf("expected scores only for the gate with a rubric: %q", out)
+	}
+}
+
+func TestPrintWorkflowSummary_Escalated(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintWorkflowSummary([]StepSummary{
+		{Name: "implement", Status: "completed", Model: "sonnet"},
+		{Name: "review", Status: "passed", GateResult: "PASS", Model: "opus", Escalated: true},
+	}, 0.1, 100)
+
+	out := buf.String()
+	if !strings.Contains(out, "via sonnet") {
+		t.Errorf("summary missing fallback model: %q", out)
+	}
+	if !strings.Contains(out, "escalated to opus") {
+		t.Errorf("summary missing escalation: %q", out)
+	}
+}
+
 func TestPrintLoopSummary_Interrupted(t *testing.T) {
 	var buf bytes.Buffer
 	f := NewFormatter(false, false, &buf)
@@ -487,3 +705,36 @@ func TestPrintLoopSummary_WrappedErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintWorkflowSummary_DryStep(t *testing.T) {
+	var buf bytes.Buffer
+	f := NewFormatter(false, false, &buf)
+
+	f.PrintWorkflowSummary([]StepSummary{
+		{Name: "implement", Status: "completed"},
+		{Name: "review", Status: "dry", GateResult: "PASS"},
+	}, 0.1, 100)
+
+	out := buf.String()
+	if !strings.Contains(out, "○ 2. review [PASS]\n      dry run, prompt not sent\n") {
+		t.Errorf("summary missing the dry step: %q", out)
+	}
+	if strings.Count(out, "tokens") != 2 {
+		t.Errorf("expected cost lines only for the executed step and the total: %q", out)
+	}
+}
+
+func TestPrintDryStep(t *testing.T) {
+	var buf bytes.Buffer
+	NewFormatter(false, false, &buf).PrintDryStep("review", "Review the code\nOutput PASS or FAIL\n")
+	want := "  Dry step review: prompt not sent\n  │ Review the code\n  │ Output PASS or FAIL\n"
+	if buf.String() != want {
+		t.Errorf("PrintDryStep() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	NewFormatter(false, true, &buf).PrintDryStep("review", "prompt")
+	if buf.Len() != 0 {
+		t.Errorf("quiet PrintDryStep() = %q", buf.String())
+	}
+}
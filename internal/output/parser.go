@@ -19,6 +19,15 @@ type StreamEvent struct {
 	ToolName  string
 	ToolID    string
 	ToolInput string
+	// ToolError is set on a tool result the tool reported as failed.
+	ToolError bool
+	// Thinking holds extended reasoning from thinking blocks and thinking deltas.
+	// It is kept separate from Content so markers are never matched in reasoning.
+	Thinking string
+	// Streamed is set while partial message streaming is on: on the events
+	// unwrapped from stream_event envelopes, and on assistant messages, whose
+	// text and thinking those events have already delivered.
+	Streamed bool
 }
 
 // OutputStats contains accumulated statistics from parsing Claude CLI output.
@@ -31,6 +40,19 @@ type OutputStats struct {
 	// These are used for context window display (per-invocation usage).
 	CurrentIterTokensIn  int
 	CurrentIterTokensOut int
+	// CacheReadTokens and CacheCreationTokens are the parts of TokensIn read
+	// from and written to the prompt cache.
+	CacheReadTokens     int
+	CacheCreationTokens int
+}
+
+// CacheHitRatio returns the fraction of input tokens read from the prompt
+// cache, or 0 when no input tokens were used.
+func CacheHitRatio(cacheRead, tokensIn int) float64 {
+	if tokensIn <= 0 {
+		return 0
+	}
+	return float64(cacheRead) / float64(tokensIn)
 }
 
 // knownEventTypes lists all event types recognised by this parser version.
@@ -44,6 +66,12 @@ var knownEventTypes = map[string]bool{
 	"content_block_start": true,
 	"content_block_stop":  true,
 	"system":              true,
+	// Partial message streaming (--include-partial-messages) wraps the raw
+	// API events below in a stream_event envelope.
+	"stream_event":  true,
+	"message_start": true,
+	"message_delta": true,
+	"message_stop":  true,
 }
 
 // Parser accumulates statistics while parsing Claude CLI stream-json output.
@@ -57,6 +85,12 @@ type Parser struct {
 	// resultTokensIn/Out accumulate tokens across result events (iterations).
 	resultTokensIn  int
 	resultTokensOut int
+	// Cache token counts follow the same assistant/result accounting as the
+	// input tokens they are part of.
+	assistantCacheRead     int
+	assistantCacheCreation int
+	resultCacheRead        int
+	resultCacheCreation    int
 	// currentIterTokensIn/Out track tokens for the current iteration only.
 	// These are reset when ResetIterationTokens() is called at iteration start.
 	// Used for context window display (per-invocation usage).
@@ -68,6 +102,15 @@ type Parser struct {
 	unknownTypes      map[string]int // Map of unknown type -> count (for warning deduplication)
 	// Warning output (defaults to nil = no warnings)
 	warnWriter io.Writer
+	// Malformed line tracking: lines are numbered from 1 and the previous
+	// line is kept as context for the quarantine
+	lineNumber     int
+	previousLine   string
+	malformedCount int
+	quarantine     *Quarantine
+	// partial is set once a stream_event envelope is seen: the output
+	// streams partial messages
+	partial bool
 }
 
 // NewParser creates a new Parser instance.
@@ -77,6 +120,12 @@ func NewParser() *Parser {
 	}
 }
 
+// SetQuarantine records malformed lines in q. Without one they are only
+// counted (see ParseStats.MalformedCount).
+func (p *Parser) SetQuarantine(q *Quarantine) {
+	p.quarantine = q
+}
+
 // SetWarningWriter sets the writer for format warnings.
 // If set, warnings about unrecognised event types are written to it.
 // Pass nil to disable warnings (the default).
@@ -104,6 +153,8 @@ type contentBlock struct {
 	Input     any    `json:"input,omitempty"`
 	ToolUseID string `json:"tool_use_id,omitempty"`
 	Content   string `json:"content,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
 }
 
 type toolUseResult struct {
@@ -114,12 +165,14 @@ type toolUseResult struct {
 }
 
 type errorContent struct {
+	Type    string `json:"type"`
 	Message string `json:"message"`
 }
 
 type deltaContent struct {
 	Type        string `json:"type"`
 	Text        string `json:"text"`
+	Thinking    string `json:"thinking,omitempty"`
 	PartialJSON string `json:"partial_json,omitempty"`
 }
 
@@ -131,17 +184,29 @@ type toolBlock struct {
 }
 
 // ParseLine parses a single JSON line from Claude CLI stream-json output.
-// Returns nil event (not error) for malformed JSON or empty lines.
-func (p *Parser) ParseLine(line []byte) (*StreamEvent, error) {
+// Returns nil event (not error) for malformed JSON or empty lines. It never
+// fails the stream: malformed lines, and lines the parser chokes on, are
+// counted and quarantined (see SetQuarantine) and parsing carries on.
+func (p *Parser) ParseLine(line []byte) (event *StreamEvent, err error) {
 	// Handle empty or whitespace-only lines
 	trimmed := bytes.TrimSpace(line)
 	if len(trimmed) == 0 {
 		return nil, nil
 	}
 
+	p.lineNumber++
+	defer func() {
+		if r := recover(); r != nil {
+			p.quarantineLine(trimmed, fmt.Errorf("parser panic: %v", r))
+			event, err = nil, nil
+		}
+		p.previousLine = string(trimmed)
+	}()
+
 	// Parse JSON - return nil for malformed JSON (not an error per requirements)
 	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(trimmed, &raw); err != nil {
+		p.quarantineLine(trimmed, err)
 		return nil, nil
 	}
 
@@ -149,15 +214,30 @@ func (p *Parser) ParseLine(line []byte) (*StreamEvent, error) {
 	var eventType string
 	if typeRaw, ok := raw["type"]; ok {
 		if err := json.Unmarshal(typeRaw, &eventType); err != nil {
+			p.quarantineLine(trimmed, fmt.Errorf("invalid event type: %w", err))
 			return nil, nil
 		}
 	}
 
-	event := &StreamEvent{
+	event = &StreamEvent{
 		Type:      eventType,
 		Timestamp: time.Now(),
 	}
 
+	// Unwrap partial message streaming envelopes so the inner event is
+	// handled exactly like its unwrapped equivalent
+	if eventType == "stream_event" {
+		p.partial = true
+		event.Streamed = true
+		inner, innerType, ok := unwrapStreamEvent(raw)
+		if !ok {
+			p.knownEventCount++
+			return event, nil
+		}
+		raw, eventType = inner, innerType
+		event.Type = eventType
+	}
+
 	// Track event types for validation
 	if eventType != "" {
 		if knownEventTypes[eventType] {
@@ -177,6 +257,7 @@ func (p *Parser) ParseLine(line []byte) (*StreamEvent, error) {
 	switch eventType {
 	case "assistant":
 		p.parseAssistantMessage(raw, event)
+		event.Streamed = p.partial
 
 	case "user":
 		p.parseUserMessage(raw, event)
@@ -189,7 +270,7 @@ func (p *Parser) ParseLine(line []byte) (*StreamEvent, error) {
 		event.Content = p.parseErrorContent(raw)
 
 	case "content_block_delta":
-		event.Content = p.parseDeltaContent(raw)
+		p.parseDeltaContent(raw, event)
 
 	case "content_block_start":
 		p.parseContentBlockStart(raw, event)
@@ -204,6 +285,17 @@ func (p *Parser) ParseLine(line []byte) (*StreamEvent, error) {
 	return event, nil
 }
 
+// quarantineLine counts a malformed line and hands it to the quarantine.
+func (p *Parser) quarantineLine(line []byte, err error) {
+	p.malformedCount++
+	p.quarantine.Record(MalformedLine{
+		Number:   p.lineNumber,
+		Line:     string(line),
+		Previous: p.previousLine,
+		Err:      err,
+	})
+}
+
 // parseAssistantMessage extracts content and usage stats from assistant message.
 func (p *Parser) parseAssistantMessage(raw map[string]json.RawMessage, event *StreamEvent) {
 	msgRaw, ok := raw["message"]
@@ -222,6 +314,8 @@ func (p *Parser) parseAssistantMessage(raw map[string]json.RawMessage, event *St
 		switch block.Type {
 		case "text":
 			contentBuilder.WriteString(block.Text)
+		case "thinking":
+			event.Thinking += block.Thinking
 		case "tool_use":
 			event.ToolName = block.Name
 			event.ToolID = block.ID
@@ -240,9 +334,13 @@ func (p *Parser) parseAssistantMessage(raw map[string]json.RawMessage, event *St
 	if msg.Usage != nil {
 		p.assistantTokensIn = msg.Usage.InputTokens + msg.Usage.CacheCreationInputTokens + msg.Usage.CacheReadInputTokens
 		p.assistantTokensOut = msg.Usage.OutputTokens
+		p.assistantCacheRead = msg.Usage.CacheReadInputTokens
+		p.assistantCacheCreation = msg.Usage.CacheCreationInputTokens
 		// Update stats to reflect current state (assistant values + accumulated result values)
 		p.stats.TokensIn = p.resultTokensIn + p.assistantTokensIn
 		p.stats.TokensOut = p.resultTokensOut + p.assistantTokensOut
+		p.stats.CacheReadTokens = p.resultCacheRead + p.assistantCacheRead
+		p.stats.CacheCreationTokens = p.resultCacheCreation + p.assistantCacheCreation
 		// Update current iteration tokens (for context window display)
 		p.currentIterTokensIn = p.assistantTokensIn
 		p.currentIterTokensOut = p.assistantTokensOut
@@ -278,6 +376,7 @@ func (p *Parser) parseUserMessage(raw map[string]json.RawMessage, event *StreamE
 	for _, block := range msg.Content {
 		if block.Type == "tool_result" {
 			event.ToolID = block.ToolUseID
+			event.ToolError = block.IsError
 			if event.Content == "" && block.Content != "" {
 				// Truncate long content
 				content := block.Content
@@ -333,12 +432,18 @@ func (p *Parser) parseResultStats(raw map[string]json.RawMessage) {
 			// Accumulate result tokens across iterations
 			p.resultTokensIn += tokensIn
 			p.resultTokensOut += tokensOut
+			p.resultCacheRead += usage.CacheReadInputTokens
+			p.resultCacheCreation += usage.CacheCreationInputTokens
 			// Reset assistant tokens (result supersedes them for this iteration)
 			p.assistantTokensIn = 0
 			p.assistantTokensOut = 0
+			p.assistantCacheRead = 0
+			p.assistantCacheCreation = 0
 			// Update stats to reflect the accumulated result totals
 			p.stats.TokensIn = p.resultTokensIn
 			p.stats.TokensOut = p.resultTokensOut
+			p.stats.CacheReadTokens = p.resultCacheRead
+			p.stats.CacheCreationTokens = p.resultCacheCreation
 			// Update current iteration tokens (for context window display)
 			// These are the final authoritative counts for this iteration
 			p.currentIterTokensIn = tokensIn
@@ -348,6 +453,8 @@ func (p *Parser) parseResultStats(raw map[string]json.RawMessage) {
 }
 
 // parseErrorContent extracts error message.
+// The error may be an object with a message and an optional API error type
+// (e.g. "overloaded_error"), or a plain string.
 func (p *Parser) parseErrorContent(raw map[string]json.RawMessage) string {
 	errRaw, ok := raw["error"]
 	if !ok {
@@ -356,23 +463,54 @@ func (p *Parser) parseErrorContent(raw map[string]json.RawMessage) string {
 
 	var errContent errorContent
 	if err := json.Unmarshal(errRaw, &errContent); err != nil {
-		return ""
+		var msg string
+		if err := json.Unmarshal(errRaw, &msg); err != nil {
+			return ""
+		}
+		return msg
+	}
+	if errContent.Message == "" {
+		return errContent.Type
 	}
 	return errContent.Message
 }
 
-// parseDeltaContent extracts text from content_block_delta.
-func (p *Parser) parseDeltaContent(raw map[string]json.RawMessage) string {
+// parseDeltaContent extracts text or thinking from content_block_delta.
+func (p *Parser) parseDeltaContent(raw map[string]json.RawMessage, event *StreamEvent) {
 	deltaRaw, ok := raw["delta"]
 	if !ok {
-		return ""
+		return
 	}
 
 	var delta deltaContent
 	if err := json.Unmarshal(deltaRaw, &delta); err != nil {
-		return ""
+		return
+	}
+	event.Content = delta.Text
+	event.Thinking = delta.Thinking
+}
+
+// unwrapStreamEvent extracts the inner API event from a stream_event envelope:
+//
+//	{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"..."}}}
+func unwrapStreamEvent(raw map[string]json.RawMessage) (map[string]json.RawMessage, string, bool) {
+	innerRaw, ok := raw["event"]
+	if !ok {
+		return nil, "", false
+	}
+
+	var inner map[string]json.RawMessage
+	if err := json.Unmarshal(innerRaw, &inner); err != nil {
+		return nil, "", false
 	}
-	return delta.Text
+
+	var innerType string
+	if typeRaw, ok := inner["type"]; ok {
+		if err := json.Unmarshal(typeRaw, &innerType); err != nil {
+			return nil, "", false
+		}
+	}
+	return inner, innerType, true
 }
 
 // parseSystemContent extracts message from system event.
@@ -436,6 +574,8 @@ func (p *Parser) GetStats() *OutputStats {
 		Duration:             p.stats.Duration,
 		CurrentIterTokensIn:  p.currentIterTokensIn,
 		CurrentIterTokensOut: p.currentIterTokensOut,
+		CacheReadTokens:      p.stats.CacheReadTokens,
+		CacheCreationTokens:  p.stats.CacheCreationTokens,
 	}
 }
 
@@ -447,6 +587,8 @@ func (p *Parser) ResetIterationTokens() {
 	p.currentIterTokensOut = 0
 	p.assistantTokensIn = 0
 	p.assistantTokensOut = 0
+	p.assistantCacheRead = 0
+	p.assistantCacheCreation = 0
 }
 
 // ParseStats contains statistics about the parsing process itself.
@@ -454,6 +596,7 @@ type ParseStats struct {
 	KnownEventCount   int            // Number of recognised events parsed
 	UnknownEventCount int            // Number of unrecognised events parsed
 	UnknownTypes      map[string]int // Map of unknown type -> occurrence count
+	MalformedCount    int            // Number of malformed lines skipped
 }
 
 // GetParseStats returns statistics about the parsing process.
@@ -467,6 +610,7 @@ func (p *Parser) GetParseStats() *ParseStats {
 		KnownEventCount:   p.knownEventCount,
 		UnknownEventCount: p.unknownEventCount,
 		UnknownTypes:      unknownCopy,
+		MalformedCount:    p.malformedCount,
 	}
 }
 
@@ -514,3 +658,23 @@ func ExtractText(rawOutput string) string {
 	}
 	return text.String()
 }
+
+// ExtractResult returns the final answer from raw stream-json output: the
+// "result" field of the last result event. It falls back to ExtractText when
+// no result event carries any text.
+func ExtractResult(rawOutput string) string {
+	lines := strings.Split(rawOutput, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		var event struct {
+			Type   string `json:"type"`
+			Result string `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(lines[i])), &event); err != nil {
+			continue
+		}
+		if event.Type == "result" && event.Result != "" {
+			return event.Result
+		}
+	}
+	return ExtractText(rawOutput)
+}
@@ -140,6 +140,159 @@ func TestParseLine_ErrorMessage(t *testing.T) {
 	}
 }
 
+func TestParseLine_ErrorVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "typed API error",
+			line: `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			want: "Overloaded",
+		},
+		{
+			name: "type without message",
+			line: `{"type":"error","error":{"type":"overloaded_error"}}`,
+			want: "overloaded_error",
+		},
+		{
+			name: "plain string",
+			line: `{"type":"error","error":"connection reset"}`,
+			want: "connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewParser().ParseLine([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.Content != tt.want {
+				t.Errorf("Content = %q, want %q", event.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLine_AssistantThinking(t *testing.T) {
+	p := NewParser()
+	line := []byte(`{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"The tests fail because...","signature":"abc"},{"type":"text","text":"Fixing the test"}]}}`)
+
+	event, err := p.ParseLine(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Thinking != "The tests fail because..." {
+		t.Errorf("Thinking = %q", event.Thinking)
+	}
+	if event.Content != "Fixing the test" {
+		t.Errorf("Content = %q, want thinking excluded", event.Content)
+	}
+}
+
+func TestParseLine_ThinkingDelta(t *testing.T) {
+	p := NewParser()
+	line := []byte(`{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"Considering"}}`)
+
+	event, err := p.ParseLine(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Thinking != "Considering" {
+		t.Errorf("Thinking = %q, want %q", event.Thinking, "Considering")
+	}
+	if event.Content != "" {
+		t.Errorf("Content = %q, want empty", event.Content)
+	}
+}
+
+func TestParseLine_StreamEventUnwrapped(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantType     string
+		wantContent  string
+		wantThinking string
+	}{
+		{
+			name:        "text delta",
+			line:        `{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}}`,
+			wantType:    "content_block_delta",
+			wantContent: "Hello",
+		},
+		{
+			name:         "thinking delta",
+			line:         `{"type":"stream_event","event":{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Hmm"}}}`,
+			wantType:     "content_block_delta",
+			wantThinking: "Hmm",
+		},
+		{
+			name:     "message start",
+			line:     `{"type":"stream_event","event":{"type":"message_start","message":{"id":"msg_1"}}}`,
+			wantType: "message_start",
+		},
+		{
+			name:     "missing inner event",
+			line:     `{"type":"stream_event"}`,
+			wantType: "stream_event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewParser()
+			event, err := p.ParseLine([]byte(tt.line))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", event.Type, tt.wantType)
+			}
+			if event.Content != tt.wantContent {
+				t.Errorf("Content = %q, want %q", event.Content, tt.wantContent)
+			}
+			if event.Thinking != tt.wantThinking {
+				t.Errorf("Thinking = %q, want %q", event.Thinking, tt.wantThinking)
+			}
+			if stats := p.GetParseStats(); stats.KnownEventCount != 1 || stats.UnknownEventCount != 0 {
+				t.Errorf("parse stats = %+v, want one known event", stats)
+			}
+		})
+	}
+}
+
+func TestParseLine_StreamedAssistantMessages(t *testing.T) {
+	assistant := []byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"Hello"}]}}`)
+	p := NewParser()
+
+	// Without partial message streaming, assistant messages are the only
+	// source of their text
+	if event, _ := p.ParseLine(assistant); event.Streamed {
+		t.Error("assistant message marked streamed before any partial message")
+	}
+
+	delta, _ := p.ParseLine([]byte(`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hello"}}}`))
+	if !delta.Streamed {
+		t.Error("unwrapped partial event not marked streamed")
+	}
+	if event, _ := p.ParseLine(assistant); !event.Streamed || event.Content != "Hello" {
+		t.Errorf("assistant message = %+v, want it marked streamed with its text kept", event)
+	}
+}
+
+func TestExtractText_ExcludesThinking(t *testing.T) {
+	input := `{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"maybe <promise>COMPLETE</promise>?"}]}}
+{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"<promise>COMPLETE</promise>"}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Still working"}]}}`
+
+	got := ExtractText(input)
+	if got != "Still working\n" {
+		t.Errorf("ExtractText() = %q, want thinking excluded", got)
+	}
+}
+
 func TestParseLine_MalformedJSON(t *testing.T) {
 	p := NewParser()
 	line := []byte(`{invalid json`)
@@ -268,6 +421,27 @@ func TestParseLine_SystemMessage(t *testing.T) {
 	}
 }
 
+func TestParseLine_ToolResult(t *testing.T) {
+	p := NewParser()
+
+	tests := []struct {
+		line      string
+		wantError bool
+	}{
+		{line: `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"ok"}]}}`},
+		{line: `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"Exit code 1","is_error":true}]}}`, wantError: true},
+	}
+	for _, tt := range tests {
+		event, err := p.ParseLine([]byte(tt.line))
+		if err != nil || event == nil {
+			t.Fatalf("ParseLine() = %v, %v", event, err)
+		}
+		if event.ToolID != "toolu_1" || event.ToolError != tt.wantError {
+			t.Errorf("ToolID = %q, ToolError = %v, want toolu_1, %v", event.ToolID, event.ToolError, tt.wantError)
+		}
+	}
+}
+
 func TestParseLine_ResultWithDuration(t *testing.T) {
 	p := NewParser()
 	// Result messages include duration_ms (milliseconds) - use actual Claude Code format
@@ -371,6 +545,41 @@ func TestParseLine_AssistantUsageUpdatesWithLatest(t *testing.T) {
 	}
 }
 
+func TestParseLine_CacheTokens(t *testing.T) {
+	p := NewParser()
+
+	// An assistant message is superseded by its iteration's result, and
+	// results accumulate across iterations
+	lines := []string{
+		`{"type":"assistant","message":{"content":[],"usage":{"input_tokens":1,"cache_creation_input_tokens":100,"cache_read_input_tokens":200,"output_tokens":5}}}`,
+		`{"type":"result","usage":{"input_tokens":2,"cache_creation_input_tokens":150,"cache_read_input_tokens":300,"output_tokens":10}}`,
+		`{"type":"result","usage":{"input_tokens":3,"cache_creation_input_tokens":0,"cache_read_input_tokens":450,"output_tokens":10}}`,
+		`{"type":"assistant","message":{"content":[],"usage":{"input_tokens":1,"cache_creation_input_tokens":20,"cache_read_input_tokens":40,"output_tokens":5}}}`,
+	}
+	for _, line := range lines {
+		if _, err := p.ParseLine([]byte(line)); err != nil {
+			t.Fatalf("ParseLine(%s) error = %v", line, err)
+		}
+	}
+
+	stats := p.GetStats()
+	if stats.CacheReadTokens != 790 || stats.CacheCreationTokens != 170 {
+		t.Errorf("cache read, creation = %d, %d, want 790, 170", stats.CacheReadTokens, stats.CacheCreationTokens)
+	}
+	if stats.TokensIn != 966 {
+		t.Errorf("TokensIn = %d, want 966 including the cache tokens", stats.TokensIn)
+	}
+}
+
+func TestCacheHitRatio(t *testing.T) {
+	if got := CacheHitRatio(750, 1000); got != 0.75 {
+		t.Errorf("CacheHitRatio(750, 1000) = %v, want 0.75", got)
+	}
+	if got := CacheHitRatio(0, 0); got != 0 {
+		t.Errorf("CacheHitRatio(0, 0) = %v, want 0", got)
+	}
+}
+
 func TestParseLine_ResultMessageActualFormat(t *testing.T) {
 	// This is the ACTUAL format from Claude Code CLI stream-json output
 	// as captured from: claude -p --verbose --output-format stream-json "say hello"
@@ -666,6 +875,10 @@ func TestParser_AllKnownEventTypes(t *testing.T) {
 		"content_block_start",
 		"content_block_stop",
 		"system",
+		"stream_event",
+		"message_start",
+		"message_delta",
+		"message_stop",
 	}
 
 	for _, eventType := range knownTypes {
@@ -747,3 +960,37 @@ func TestExtractText(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "uses result field of result event",
+			input: `{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the notes"}]}}
+{"type":"result","subtype":"success","result":"The gate failed because tests were skipped."}`,
+			expected: "The gate failed because tests were skipped.",
+		},
+		{
+			name:     "falls back to text when result is empty",
+			input:    `{"type":"content_block_delta","delta":{"text":"Hello"}}`,
+			expected: "Hello\n",
+		},
+		{
+			name:     "empty input",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractResult(tt.input)
+			if got != tt.expected {
+				t.Errorf("ExtractResult() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
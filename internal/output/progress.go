@@ -0,0 +1,165 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// Progress modes for --progress.
+const (
+	// ProgressAuto shows progress in quiet mode only: a line updated in
+	// place on a terminal, heartbeat lines elsewhere.
+	ProgressAuto = "auto"
+	// ProgressPlain always prints heartbeat lines, even on a terminal.
+	ProgressPlain = "plain"
+	// ProgressNone shows no progress line.
+	ProgressNone = "none"
+)
+
+// Progress line refresh intervals: a live line is redrawn every second so
+// its elapsed time ticks; heartbeat lines are only repeated when nothing
+// changed for a while, to keep CI logs short.
+const (
+	liveProgressInterval      = time.Second
+	heartbeatProgressInterval = 30 * time.Second
+)
+
+// ProgressStatus is what a progress line shows.
+type ProgressStatus struct {
+	Iteration     int
+	MaxIterations int
+	Step          string
+	Cost          float64
+	Budget        float64
+}
+
+// FormatProgress renders status as a single line, e.g.
+// "iter 7/50 | step implement | $4.21/$20.00 | 13m elapsed". The step and
+// budget are left out when unknown.
+func FormatProgress(s ProgressStatus, elapsed time.Duration) string {
+	parts := []string{fmt.Sprintf("iter %d/%d", s.Iteration, s.MaxIterations)}
+	if s.Step != "" {
+		parts = append(parts, "step "+s.Step)
+	}
+	cost := util.FormatCost(s.Cost, 2)
+	if s.Budget > 0 {
+		cost += "/" + util.FormatCost(s.Budget, 2)
+	}
+	parts = append(parts, cost)
+	if elapsed >= time.Minute {
+		elapsed = elapsed.Truncate(time.Minute)
+	}
+	parts = append(parts, formatDuration(elapsed.Round(time.Second))+" elapsed")
+	return strings.Join(parts, " | ")
+}
+
+// ProgressLine reports a session's progress while nothing else is shown, as
+// in quiet mode. A live line is rewritten in place; otherwise a heartbeat
+// line is printed when the iteration or step changes, and repeated while
+// nothing changes. Its methods are safe for concurrent use and do nothing on
+// a nil ProgressLine.
+type ProgressLine struct {
+	mu      sync.Mutex
+	w       io.Writer
+	live    bool
+	status  ProgressStatus
+	start   time.Time
+	printed time.Time
+	now     func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewProgressLine returns a progress line writing to w, rewritten in place
+// when live is set. Elapsed time is counted from start.
+func NewProgressLine(w io.Writer, live bool, start time.Time) *ProgressLine {
+	return &ProgressLine{w: w, live: live, start: start, now: time.Now}
+}
+
+// Start refreshes the line in the background until Stop is called.
+func (p *ProgressLine) Start() {
+	if p == nil {
+		return
+	}
+	interval := heartbeatProgressInterval
+	if p.live {
+		interval = liveProgressInterval
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.tick(interval)
+			}
+		}
+	}()
+}
+
+// Update sets the status shown. A heartbeat line is printed straight away
+// when the iteration or step changed.
+func (p *ProgressLine) Update(s ProgressStatus) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := s.Iteration != p.status.Iteration || s.Step != p.status.Step
+	p.status = s
+	if p.live || changed {
+		p.print()
+	}
+}
+
+// Stop stops refreshing the line and, for a live line, ends it so later
+// output starts on a new line.
+func (p *ProgressLine) Stop() {
+	if p == nil {
+		return
+	}
+	if p.stop != nil {
+		close(p.stop)
+		<-p.done
+		p.stop = nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.live && !p.printed.IsZero() {
+		_, _ = fmt.Fprintln(p.w)
+	}
+}
+
+// tick redraws a live line, and repeats a heartbeat line when none was
+// printed for interval.
+func (p *ProgressLine) tick(interval time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.live || p.now().Sub(p.printed) >= interval {
+		p.print()
+	}
+}
+
+// print writes the line. Must be called with the lock held.
+func (p *ProgressLine) print() {
+	now := p.now()
+	line := FormatProgress(p.status, now.Sub(p.start))
+	if p.live {
+		// Return to the start of the line and clear what was there
+		_, _ = fmt.Fprint(p.w, "\r\033[K"+line)
+	} else {
+		_, _ = fmt.Fprintln(p.w, line)
+	}
+	p.printed = now
+}
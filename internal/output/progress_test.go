@@ -0,0 +1,96 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  ProgressStatus
+		elapsed time.Duration
+		want    string
+	}{
+		{
+			name:    "full",
+			status:  ProgressStatus{Iteration: 7, MaxIterations: 50, Step: "implement", Cost: 4.21, Budget: 20},
+			elapsed: 13*time.Minute + 42*time.Second,
+			want:    "iter 7/50 | step implement | $4.21/$20.00 | 13m elapsed",
+		},
+		{
+			name:    "no step or budget",
+			status:  ProgressStatus{Iteration: 1, MaxIterations: 10},
+			elapsed: 42 * time.Second,
+			want:    "iter 1/10 | $0.00 | 42s elapsed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatProgress(tt.status, tt.elapsed); got != tt.want {
+				t.Errorf("FormatProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestProgressLine returns a progress line on a fake clock started at
+// start, and a function to move the clock on.
+func newTestProgressLine(live bool, start time.Time) (*ProgressLine, *strings.Builder, func(time.Duration)) {
+	var buf strings.Builder
+	p := NewProgressLine(&buf, live, start)
+	now := start
+	p.now = func() time.Time { return now }
+	return p, &buf, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestProgressLine_Heartbeat(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	p, buf, advance := newTestProgressLine(false, start)
+
+	p.Update(ProgressStatus{Iteration: 1, MaxIterations: 5, Step: "implement"})
+	advance(10 * time.Second)
+	// Only the cost changed: no new line
+	p.Update(ProgressStatus{Iteration: 1, MaxIterations: 5, Step: "implement", Cost: 0.5})
+	p.tick(heartbeatProgressInterval)
+	advance(heartbeatProgressInterval)
+	p.tick(heartbeatProgressInterval)
+	p.Update(ProgressStatus{Iteration: 1, MaxIterations: 5, Step: "review", Cost: 0.5})
+
+	want := "iter 1/5 | step implement | $0.00 | 0s elapsed\n" +
+		"iter 1/5 | step implement | $0.50 | 40s elapsed\n" +
+		"iter 1/5 | step review | $0.50 | 40s elapsed\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestProgressLine_Live(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	p, buf, advance := newTestProgressLine(true, start)
+
+	p.Update(ProgressStatus{Iteration: 2, MaxIterations: 5})
+	advance(time.Second)
+	p.tick(liveProgressInterval)
+	p.Stop()
+
+	want := "\r\033[Kiter 2/5 | $0.00 | 0s elapsed" + "\r\033[Kiter 2/5 | $0.00 | 1s elapsed" + "\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestProgressLine_StartStop(t *testing.T) {
+	p, buf, _ := newTestProgressLine(true, time.Now())
+	p.Start()
+	p.Stop()
+	if buf.String() != "" {
+		t.Errorf("output = %q, want nothing before the first update", buf.String())
+	}
+
+	var nilLine *ProgressLine
+	nilLine.Start()
+	nilLine.Update(ProgressStatus{Iteration: 1})
+	nilLine.Stop()
+}
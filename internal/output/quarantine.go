@@ -0,0 +1,114 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+// QuarantineFile is the file in .orbital/ malformed stream lines are
+// quarantined to.
+const QuarantineFile = "parse-errors.log"
+
+// quarantineLineLimit bounds how much of a malformed line, and of the line
+// before it, is kept in the quarantine file.
+const quarantineLineLimit = 2000
+
+// QuarantinePath returns the quarantine file for the working directory.
+func QuarantinePath(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", QuarantineFile)
+}
+
+// MalformedLine is a stream line the parser could not make sense of.
+type MalformedLine struct {
+	// Number is the line's position in the parser's stream, from 1.
+	Number int
+	// Line is the malformed line.
+	Line string
+	// Previous is the line before it, for context (empty for the first line).
+	Previous string
+	// Err is why the line was rejected.
+	Err error
+}
+
+// QuarantineCallback is called after a malformed line is quarantined, with
+// the number quarantined so far.
+type QuarantineCallback func(count int, line MalformedLine)
+
+// Quarantine records malformed stream lines, with their context, to a log
+// file so they can be inspected later while the stream carries on. It is
+// safe for concurrent use and may be shared by several parsers.
+type Quarantine struct {
+	mu       sync.Mutex
+	path     string
+	count    int
+	onRecord QuarantineCallback
+	now      func() time.Time
+}
+
+// NewQuarantine creates a quarantine appending to path. onRecord may be nil.
+func NewQuarantine(path string, onRecord QuarantineCallback) *Quarantine {
+	return &Quarantine{path: path, onRecord: onRecord, now: time.Now}
+}
+
+// Record appends m to the quarantine file and reports it. Failing to write
+// the file only loses the entry; the line is still counted.
+func (q *Quarantine) Record(m MalformedLine) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.count++
+	count := q.count
+	_ = q.append(m)
+	q.mu.Unlock()
+
+	if q.onRecord != nil {
+		q.onRecord(count, m)
+	}
+}
+
+// Count returns the number of lines quarantined so far.
+func (q *Quarantine) Count() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.count
+}
+
+// append writes one entry for m, encrypted when encryption at rest is
+// enabled. Must be called with the lock held.
+func (q *Quarantine) append(m MalformedLine) error {
+	f, err := encrypt.OpenAppend(q.path)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s line %d: %v\n", q.now().UTC().Format(time.RFC3339), m.Number, m.Err)
+	if m.Previous != "" {
+		fmt.Fprintf(&b, "  after: %s\n", truncateLine(m.Previous))
+	}
+	fmt.Fprintf(&b, "  line:  %s\n", truncateLine(m.Line))
+
+	if _, err := io.WriteString(f, b.String()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// truncateLine shortens line to quarantineLineLimit bytes.
+func truncateLine(line string) string {
+	if len(line) <= quarantineLineLimit {
+		return line
+	}
+	return line[:quarantineLineLimit] + fmt.Sprintf("… (%d bytes)", len(line))
+}
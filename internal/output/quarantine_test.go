@@ -0,0 +1,135 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+func TestQuarantinePath(t *testing.T) {
+	if got, want := QuarantinePath("/repo"), filepath.Join("/repo", ".orbital", "parse-errors.log"); got != want {
+		t.Errorf("QuarantinePath() = %q, want %q", got, want)
+	}
+}
+
+func TestQuarantine_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".orbital", QuarantineFile)
+	var counts []int
+	q := NewQuarantine(path, func(count int, _ MalformedLine) { counts = append(counts, count) })
+	q.now = func() time.Time { return time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC) }
+
+	q.Record(MalformedLine{Number: 1, Line: "not json", Err: errors.New("invalid character 'o'")})
+	q.Record(MalformedLine{Number: 3, Line: `{"type":"assis`, Previous: `{"type":"system"}`, Err: errors.New("unexpected end of JSON input")})
+
+	if q.Count() != 2 || len(counts) != 2 || counts[1] != 2 {
+		t.Errorf("Count() = %d, callback counts = %v, want 2 and [1 2]", q.Count(), counts)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("quarantine file: %v", err)
+	}
+	want := "2026-01-02T10:30:00Z line 1: invalid character 'o'\n" +
+		"  line:  not json\n" +
+		"2026-01-02T10:30:00Z line 3: unexpected end of JSON input\n" +
+		"  after: {\"type\":\"system\"}\n" +
+		"  line:  {\"type\":\"assis\n"
+	if string(data) != want {
+		t.Errorf("quarantine file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestQuarantine_TruncatesLongLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), QuarantineFile)
+	q := NewQuarantine(path, nil)
+
+	q.Record(MalformedLine{Number: 1, Line: strings.Repeat("x", quarantineLineLimit+10), Err: errors.New("bad")})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf("… (%d bytes)", quarantineLineLimit+10)) {
+		t.Errorf("long line not truncated:\n%.200s", data)
+	}
+}
+
+func TestQuarantine_Nil(t *testing.T) {
+	var q *Quarantine
+	q.Record(MalformedLine{Line: "ignored"})
+	if q.Count() != 0 {
+		t.Error("nil quarantine counted a line")
+	}
+}
+
+func TestParser_QuarantinesMalformedLines(t *testing.T) {
+	var got []MalformedLine
+	p := NewParser()
+	p.SetQuarantine(NewQuarantine(filepath.Join(t.TempDir(), QuarantineFile), func(_ int, m MalformedLine) {
+		got = append(got, m)
+	}))
+
+	lines := []string{
+		`{"type":"system","subtype":"init"}`,
+		`{"type":"assis`,
+		``,
+		`{"type":42}`,
+		`{"type":"result","total_cost_usd":0.5}`,
+	}
+	for _, line := range lines {
+		if _, err := p.ParseLine([]byte(line)); err != nil {
+			t.Fatalf("ParseLine(%q) error = %v", line, err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("quarantined %d lines, want 2: %+v", len(got), got)
+	}
+	if got[0].Number != 2 || got[0].Previous != lines[0] || got[0].Err == nil {
+		t.Errorf("first quarantined line = %+v", got[0])
+	}
+	if got[1].Number != 3 || got[1].Line != `{"type":42}` || !strings.Contains(got[1].Err.Error(), "invalid event type") {
+		t.Errorf("second quarantined line = %+v", got[1])
+	}
+	if stats := p.GetParseStats(); stats.MalformedCount != 2 {
+		t.Errorf("MalformedCount = %d, want 2", stats.MalformedCount)
+	}
+	// Parsing carried on past the malformed lines
+	if p.GetStats().CostUSD != 0.5 {
+		t.Errorf("CostUSD = %v, want the result after the malformed lines parsed", p.GetStats().CostUSD)
+	}
+}
+
+func TestQuarantine_Encrypted(t *testing.T) {
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	path := filepath.Join(t.TempDir(), ".orbital", QuarantineFile)
+	q := NewQuarantine(path, nil)
+	q.Record(MalformedLine{Number: 1, Line: "confidential", Err: errors.New("bad")})
+	q.Record(MalformedLine{Number: 2, Line: "more", Err: errors.New("bad")})
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypt.IsEncrypted(raw) || strings.Contains(string(raw), "confidential") {
+		t.Fatalf("quarantine file = %q, want it encrypted", raw)
+	}
+	data, err := encrypt.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "  line:  confidential\n") || !strings.Contains(string(data), "  line:  more\n") {
+		t.Errorf("decrypted quarantine file = %q, want both entries", data)
+	}
+}
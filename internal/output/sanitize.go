@@ -0,0 +1,220 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode controls what happens to terminal control sequences in the
+// model's output. Raw escape sequences can break the TUI layout or act on
+// the terminal (clear it, move the cursor, set the clipboard or title).
+type SanitizeMode string
+
+const (
+	// SanitizeStrip removes control sequences but keeps SGR colours and
+	// styles. This is the default.
+	SanitizeStrip SanitizeMode = "strip"
+
+	// SanitizeEscape shows control sequences as visible text, e.g. "␛[2J",
+	// and keeps SGR colours and styles.
+	SanitizeEscape SanitizeMode = "escape"
+
+	// SanitizePlain removes all control sequences, colours included.
+	SanitizePlain SanitizeMode = "plain"
+
+	// SanitizeOff passes output through unchanged.
+	SanitizeOff SanitizeMode = "off"
+)
+
+// ParseSanitizeMode validates a sanitize mode from configuration.
+// An empty string selects SanitizeStrip.
+func ParseSanitizeMode(s string) (SanitizeMode, error) {
+	switch SanitizeMode(s) {
+	case "":
+		return SanitizeStrip, nil
+	case SanitizeStrip, SanitizeEscape, SanitizePlain, SanitizeOff:
+		return SanitizeMode(s), nil
+	}
+	return "", fmt.Errorf("invalid sanitize mode %q: must be strip, escape, plain, or off", s)
+}
+
+// SanitizeEvent sanitizes the model-controlled text of event in place.
+func SanitizeEvent(event *StreamEvent, mode SanitizeMode) {
+	event.Content = Sanitize(event.Content, mode)
+	event.Thinking = Sanitize(event.Thinking, mode)
+	event.ToolName = Sanitize(event.ToolName, mode)
+	event.ToolInput = Sanitize(event.ToolInput, mode)
+}
+
+// Sanitize neutralises the control sequences in s according to mode. Newlines
+// and tabs are kept; other C0 and C1 controls, and escape sequences other than
+// benign SGR, are removed or, in SanitizeEscape mode, made visible. The zero
+// mode is SanitizeStrip.
+func Sanitize(s string, mode SanitizeMode) string {
+	if mode == SanitizeOff || !hasControl(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\x1b':
+			n, sgr := escapeSequence(s[i:])
+			seq := s[i : i+n]
+			switch {
+			case sgr && mode != SanitizePlain:
+				b.WriteString(seq)
+			case mode == SanitizeEscape:
+				b.WriteString(visible(seq))
+			}
+			i += n
+		case c == '\n' || c == '\t':
+			b.WriteByte(c)
+			i++
+		case c < 0x20 || c == 0x7f:
+			if mode == SanitizeEscape {
+				b.WriteString(visible(string(c)))
+			}
+			i++
+		case c < utf8.RuneSelf:
+			b.WriteByte(c)
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r >= 0x80 && r <= 0x9f {
+				// C1 controls, such as the single-character CSI
+				if mode == SanitizeEscape {
+					fmt.Fprintf(&b, "<U+%04X>", r)
+				}
+			} else {
+				b.WriteString(s[i : i+size])
+			}
+			i += size
+		}
+	}
+	return b.String()
+}
+
+// hasControl reports whether s holds anything Sanitize would change.
+func hasControl(s string) bool {
+	for _, r := range s {
+		if (r < 0x20 && r != '\n' && r != '\t') || (r >= 0x7f && r <= 0x9f) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeSequence returns the length of the escape sequence at the start of
+// s, which starts with ESC, and whether it is a benign SGR sequence. An
+// unterminated sequence runs to the end of s.
+func escapeSequence(s string) (n int, sgr bool) {
+	if len(s) < 2 {
+		return len(s), false
+	}
+	switch s[1] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, then a final byte
+		i := 2
+		for i < len(s) && s[i] >= 0x30 && s[i] <= 0x3f {
+			i++
+		}
+		params := s[2:i]
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i >= len(s) || s[i] < 0x40 || s[i] > 0x7e {
+			return i, false
+		}
+		return i + 1, s[i] == 'm' && i == 2+len(params) && benignSGR(params)
+	case ']', 'P', '_', '^', 'X':
+		// OSC, DCS, APC, PM and SOS strings end with ST (ESC \); OSC also
+		// with BEL
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' && s[1] == ']' {
+				return i + 1, false
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2, false
+			}
+		}
+		return len(s), false
+	}
+	// Other escapes: intermediate bytes, then a final byte
+	i := 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7e {
+		i++
+	}
+	return i, false
+}
+
+// benignSGR reports whether the parameters of an SGR sequence only set
+// colours and styles. Blink and conceal are refused, as are parameters this
+// function does not know.
+func benignSGR(params string) bool {
+	if params == "" {
+		return true
+	}
+	if strings.ContainsAny(params, "<=>?") {
+		return false
+	}
+	fields := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	for i := 0; i < len(fields); i++ {
+		p := fields[i]
+		switch p {
+		case "", "0", "1", "2", "3", "4", "7", "9", "21", "22", "23", "24", "27", "29", "39", "49", "59":
+		case "38", "48", "58":
+			// Extended colour: 5;index or 2;r;g;b
+			if i+1 >= len(fields) {
+				return false
+			}
+			switch fields[i+1] {
+			case "5":
+				i += 2
+			case "2":
+				i += 4
+			default:
+				return false
+			}
+		default:
+			if !basicColor(p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// basicColor reports whether p is a 16-colour foreground or background
+// parameter (30-37, 40-47, 90-97, 100-107).
+func basicColor(p string) bool {
+	n, err := strconv.Atoi(p)
+	if err != nil {
+		return false
+	}
+	return (n >= 30 && n <= 37) || (n >= 40 && n <= 47) || (n >= 90 && n <= 97) || (n >= 100 && n <= 107)
+}
+
+// visible renders the control characters of seq as printable symbols: ESC
+// as ␛ and other C0 controls as their control pictures.
+func visible(seq string) string {
+	var b strings.Builder
+	for _, r := range seq {
+		switch {
+		case r == 0x7f:
+			b.WriteRune('␡')
+		case r < 0x20:
+			b.WriteRune(0x2400 + r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSanitizeMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SanitizeMode
+		wantErr bool
+	}{
+		{in: "", want: SanitizeStrip},
+		{in: "strip", want: SanitizeStrip},
+		{in: "escape", want: SanitizeEscape},
+		{in: "plain", want: SanitizePlain},
+		{in: "off", want: SanitizeOff},
+		{in: "raw", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSanitizeMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSanitizeMode(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		mode SanitizeMode
+		want string
+	}{
+		{name: "plain text", in: "hello\tworld\n", want: "hello\tworld\n"},
+		{name: "keeps colours", in: "\x1b[31mred\x1b[0m \x1b[1;38;5;208mbold orange\x1b[m", want: "\x1b[31mred\x1b[0m \x1b[1;38;5;208mbold orange\x1b[m"},
+		{name: "keeps truecolour", in: "\x1b[38;2;255;0;0mred\x1b[39m", want: "\x1b[38;2;255;0;0mred\x1b[39m"},
+		{name: "clear screen", in: "a\x1b[2J\x1b[Hb", want: "ab"},
+		{name: "cursor movement", in: "line\x1b[1A\x1b[2Kover", want: "lineover"},
+		{name: "blink and conceal", in: "\x1b[5mx\x1b[8my", want: "xy"},
+		{name: "private modes", in: "\x1b[?1049hx\x1b[?25l", want: "x"},
+		{name: "window title (BEL)", in: "\x1b]0;pwned\x07ok", want: "ok"},
+		{name: "clipboard (ST)", in: "\x1b]52;c;ZXZpbA==\x1b\\ok", want: "ok"},
+		{name: "hyperlink", in: "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", want: "link"},
+		{name: "DCS", in: "\x1bPq#0;2;0;0;0\x1b\\ok", want: "ok"},
+		{name: "two-byte escapes", in: "\x1bcreset\x1b(Bok\x1b7", want: "resetok"},
+		{name: "unterminated", in: "ok\x1b[12", want: "ok"},
+		{name: "lone escape", in: "ok\x1b", want: "ok"},
+		{name: "C0 controls", in: "a\rb\bc\x07d\x00e\x7f", want: "abcde"},
+		{name: "C1 CSI", in: "a\u009b2Jb", want: "a2Jb"},
+		{name: "unicode kept", in: "✓ héllo 💭", want: "✓ héllo 💭"},
+		{name: "escape mode", in: "\x1b[31mred\x1b[0m\x1b[2J\r\u009b", mode: SanitizeEscape, want: "\x1b[31mred\x1b[0m␛[2J␍<U+009B>"},
+		{name: "plain mode", in: "\x1b[31mred\x1b[0m\x1b[2J", mode: SanitizePlain, want: "red"},
+		{name: "off", in: "\x1b[2J\r", mode: SanitizeOff, want: "\x1b[2J\r"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in, tt.mode); got != tt.want {
+				t.Errorf("Sanitize(%q, %q) = %q, want %q", tt.in, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeEvent(t *testing.T) {
+	event := &StreamEvent{
+		Content:   "text\x1b[2J",
+		Thinking:  "hmm\x1b]0;t\x07",
+		ToolName:  "Bash\x1b[H",
+		ToolInput: `{"command":"echo \u001b[2J"}`,
+	}
+	SanitizeEvent(event, SanitizeStrip)
+	for _, field := range []string{event.Content, event.Thinking, event.ToolName, event.ToolInput} {
+		if strings.ContainsRune(field, '\x1b') {
+			t.Errorf("field still holds an escape: %q", field)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/flashingpumpkin/orbital/internal/tasks"
+	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
 // StreamProcessor processes Claude CLI stream-json output and formats it for display.
@@ -21,14 +22,19 @@ type StreamProcessor struct {
 	showUnhandled bool
 	todosOnly     bool
 	tracker       *tasks.Tracker
+	thinkingMode  ThinkingMode
+	thinkingLog   *ThinkingLogger
+	thinkingShown bool
+	sanitize      SanitizeMode
 }
 
 // NewStreamProcessor creates a new StreamProcessor.
 func NewStreamProcessor(w io.Writer) *StreamProcessor {
 	return &StreamProcessor{
-		writer:  w,
-		parser:  NewParser(),
-		tracker: tasks.NewTracker(),
+		writer:       w,
+		parser:       NewParser(),
+		tracker:      tasks.NewTracker(),
+		thinkingMode: ThinkingDiscard,
 	}
 }
 
@@ -52,12 +58,29 @@ func (sp *StreamProcessor) SetTodosOnly(show bool) {
 	sp.todosOnly = show
 }
 
+// SetThinking sets how thinking is handled. In ThinkingLog mode it is appended
+// to log; in ThinkingShow mode it is printed dimmed with a 🧠 prefix.
+func (sp *StreamProcessor) SetThinking(mode ThinkingMode, log io.Writer) {
+	sp.thinkingMode = mode
+	sp.thinkingLog = nil
+	if mode == ThinkingLog {
+		sp.thinkingLog = NewThinkingLogger(log)
+	}
+}
+
+// SetSanitize sets how control sequences in the model's output are handled
+// before it is printed or logged. The default is SanitizeStrip.
+func (sp *StreamProcessor) SetSanitize(mode SanitizeMode) {
+	sp.sanitize = mode
+}
+
 // ProcessLine processes a single line of stream-json output.
 func (sp *StreamProcessor) ProcessLine(line string) {
 	event, err := sp.parser.ParseLine([]byte(line))
 	if err != nil || event == nil {
 		return
 	}
+	SanitizeEvent(event, sp.sanitize)
 
 	// In todosOnly mode, only process assistant events (which contain tool use)
 	if sp.todosOnly {
@@ -68,6 +91,10 @@ func (sp *StreamProcessor) ProcessLine(line string) {
 		return
 	}
 
+	if event.Thinking != "" || event.Type == "content_block_stop" {
+		sp.handleThinking(event)
+	}
+
 	switch event.Type {
 	case "system":
 		sp.printSystem(event.Content)
@@ -96,13 +123,54 @@ func (sp *StreamProcessor) ProcessLine(line string) {
 	default:
 		// Unhandled event type
 		if sp.showUnhandled && event.Type != "" {
-			sp.printUnhandled(event.Type, line)
+			sp.printUnhandled(event.Type, Sanitize(line, sp.sanitize))
 		}
 	}
 
 	sp.lastType = event.Type
 }
 
+// handleThinking shows, logs, or discards the thinking carried by event.
+func (sp *StreamProcessor) handleThinking(event *StreamEvent) {
+	switch sp.thinkingMode {
+	case ThinkingShow:
+		if event.Thinking != "" && !(event.Type == "assistant" && event.Streamed) {
+			sp.printThinking(event.Thinking)
+			// Thinking from an assistant message is a complete block
+			if event.Type == "assistant" {
+				sp.endThinking()
+			}
+		}
+	case ThinkingLog:
+		sp.thinkingLog.Log(event)
+	}
+}
+
+// printThinking prints thinking content dimmed so it reads as secondary to Claude's text.
+func (sp *StreamProcessor) printThinking(thinking string) {
+	if sp.textShown {
+		_, _ = fmt.Fprintln(sp.writer)
+		sp.textShown = false
+	}
+
+	dim := color.New(color.Faint)
+	if !sp.thinkingShown {
+		_, _ = fmt.Fprintln(sp.writer)
+		_, _ = dim.Fprint(sp.writer, "  🧠 ")
+	}
+
+	sp.thinkingShown = true
+	_, _ = dim.Fprint(sp.writer, thinking)
+}
+
+// endThinking terminates an ongoing thinking block.
+func (sp *StreamProcessor) endThinking() {
+	if sp.thinkingShown {
+		_, _ = fmt.Fprintln(sp.writer)
+		sp.thinkingShown = false
+	}
+}
+
 // printUnhandled outputs raw JSON for unhandled event types.
 func (sp *StreamProcessor) printUnhandled(eventType, rawJSON string) {
 	// End any ongoing text block
@@ -153,6 +221,7 @@ func (sp *StreamProcessor) printContentBlockStop() {
 	if sp.currentTool != "" {
 		sp.currentTool = ""
 	}
+	sp.endThinking()
 	if sp.textShown {
 		_, _ = fmt.Fprintln(sp.writer)
 		sp.textShown = false
@@ -161,7 +230,8 @@ func (sp *StreamProcessor) printContentBlockStop() {
 
 // printAssistant handles assistant messages with text or tool use.
 func (sp *StreamProcessor) printAssistant(event *StreamEvent) {
-	// End any ongoing text block
+	// End any ongoing text or thinking block
+	sp.endThinking()
 	if sp.textShown {
 		_, _ = fmt.Fprintln(sp.writer)
 		sp.textShown = false
@@ -201,7 +271,7 @@ func (sp *StreamProcessor) printAssistant(event *StreamEvent) {
 	}
 
 	// Show text content if present and no streaming has occurred (skip in todosOnly mode)
-	if event.Content != "" && event.ToolName == "" && !sp.todosOnly {
+	if event.Content != "" && event.ToolName == "" && !event.Streamed && !sp.todosOnly {
 		sp.printText(event.Content)
 	}
 }
@@ -398,6 +468,8 @@ func (sp *StreamProcessor) printText(text string) {
 		return
 	}
 
+	sp.endThinking()
+
 	// Add visual distinction for thoughts
 	if !sp.textShown {
 		// Starting a new thought block
@@ -440,14 +512,14 @@ func (sp *StreamProcessor) printResult(subtype string) {
 	// Show different messages based on result subtype
 	switch subtype {
 	case "end_turn":
-		_, _ = dim.Fprintf(sp.writer, "\n─── turn complete | tokens: %d in, %d out | cost: $%.4f ───\n",
-			stats.TokensIn, stats.TokensOut, stats.CostUSD)
+		_, _ = dim.Fprintf(sp.writer, "\n─── turn complete | tokens: %d in, %d out | cost: %s ───\n",
+			stats.TokensIn, stats.TokensOut, util.FormatCost(stats.CostUSD, 4))
 	case "tool_use":
 		// Tool use results are intermediate, just show a brief indicator
 		_, _ = dim.Fprintf(sp.writer, "   ↳ awaiting tool result\n")
 	default:
-		_, _ = dim.Fprintf(sp.writer, "\n─── tokens: %d in, %d out | cost: $%.4f ───\n",
-			stats.TokensIn, stats.TokensOut, stats.CostUSD)
+		_, _ = dim.Fprintf(sp.writer, "\n─── tokens: %d in, %d out | cost: %s ───\n",
+			stats.TokensIn, stats.TokensOut, util.FormatCost(stats.CostUSD, 4))
 	}
 }
 
@@ -89,6 +89,27 @@ func TestStreamProcessor_Write(t *testing.T) {
 	}
 }
 
+func TestStreamProcessor_Sanitize(t *testing.T) {
+	input := `{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hello\u001b[2J\u001b[31m World\u001b[0m"}}
+`
+	tests := []struct {
+		mode SanitizeMode
+		want string
+	}{
+		{mode: "", want: "Hello\x1b[31m World\x1b[0m"},
+		{mode: SanitizePlain, want: "Hello World"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		sp := NewStreamProcessor(&buf)
+		sp.SetSanitize(tt.mode)
+		_, _ = sp.Write([]byte(input))
+		if !strings.Contains(buf.String(), tt.want) || strings.Contains(buf.String(), "\x1b[2J") {
+			t.Errorf("mode %q: output = %q, want to contain %q", tt.mode, buf.String(), tt.want)
+		}
+	}
+}
+
 func TestStreamProcessor_GetStats(t *testing.T) {
 	var buf bytes.Buffer
 	sp := NewStreamProcessor(&buf)
@@ -209,3 +230,47 @@ func TestStreamProcessor_PrintTaskSummary(t *testing.T) {
 		t.Errorf("summary should contain pending indicator '○', got: %q", output)
 	}
 }
+
+func TestStreamProcessor_Thinking(t *testing.T) {
+	lines := []string{
+		`{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"Let me "}}`,
+		`{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"look."}}`,
+		`{"type":"content_block_stop"}`,
+		`{"type":"content_block_delta","delta":{"type":"text_delta","text":"Found it"}}`,
+	}
+
+	tests := []struct {
+		name        string
+		mode        ThinkingMode
+		wantShown   bool
+		wantLogged  string
+		wantVisible string
+	}{
+		{name: "discard", mode: ThinkingDiscard, wantVisible: "Found it"},
+		{name: "show", mode: ThinkingShow, wantShown: true, wantVisible: "Found it"},
+		{name: "log", mode: ThinkingLog, wantLogged: "Let me look.\n\n", wantVisible: "Found it"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf, log bytes.Buffer
+			sp := NewStreamProcessor(&buf)
+			sp.SetThinking(tt.mode, &log)
+
+			for _, line := range lines {
+				sp.ProcessLine(line)
+			}
+
+			got := buf.String()
+			if !strings.Contains(got, tt.wantVisible) {
+				t.Errorf("output = %q, want to contain %q", got, tt.wantVisible)
+			}
+			if shown := strings.Contains(got, "🧠 Let me look."); shown != tt.wantShown {
+				t.Errorf("thinking shown = %v, want %v (output %q)", shown, tt.wantShown, got)
+			}
+			if log.String() != tt.wantLogged {
+				t.Errorf("thinking log = %q, want %q", log.String(), tt.wantLogged)
+			}
+		})
+	}
+}
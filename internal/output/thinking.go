@@ -0,0 +1,112 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/flashingpumpkin/orbital/internal/redact"
+)
+
+// ThinkingMode controls what happens to the model's thinking (extended reasoning).
+type ThinkingMode string
+
+const (
+	// ThinkingDiscard drops thinking entirely. This is the default.
+	ThinkingDiscard ThinkingMode = "discard"
+
+	// ThinkingShow displays thinking in the TUI and formatted output.
+	ThinkingShow ThinkingMode = "show"
+
+	// ThinkingLog appends thinking to ThinkingLogPath without displaying it.
+	ThinkingLog ThinkingMode = "log"
+)
+
+// ParseThinkingMode validates a thinking mode from configuration.
+// An empty string selects ThinkingDiscard.
+func ParseThinkingMode(s string) (ThinkingMode, error) {
+	switch ThinkingMode(s) {
+	case "":
+		return ThinkingDiscard, nil
+	case ThinkingDiscard, ThinkingShow, ThinkingLog:
+		return ThinkingMode(s), nil
+	}
+	return "", fmt.Errorf("invalid thinking mode %q: must be show, log, or discard", s)
+}
+
+// ThinkingLogPath returns the file thinking is appended to in ThinkingLog mode.
+func ThinkingLogPath(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "thinking.log")
+}
+
+// ThinkingLogger appends thinking to a writer, separating blocks with a blank line.
+// A nil *ThinkingLogger discards everything.
+type ThinkingLogger struct {
+	w    io.Writer
+	open bool // a streamed thinking block has not been terminated yet
+
+	redactor *redact.Redactor // masks secrets split across deltas (may be nil)
+	held     string           // streamed thinking after the last complete line
+}
+
+// NewThinkingLogger creates a ThinkingLogger writing to w.
+func NewThinkingLogger(w io.Writer) *ThinkingLogger {
+	return &ThinkingLogger{w: w}
+}
+
+// SetRedactor masks secrets in streamed thinking. Each delta is redacted on
+// its own before it arrives, so a secret split across deltas is only masked
+// once they are joined: streamed thinking is then written a line at a time.
+func (l *ThinkingLogger) SetRedactor(r *redact.Redactor) {
+	if l != nil {
+		l.redactor = r
+	}
+}
+
+// Log records the thinking carried by event. Thinking deltas are written as they
+// arrive and terminated by the following content_block_stop; complete thinking
+// blocks from assistant messages are terminated immediately. Assistant
+// messages repeating thinking that was streamed are skipped.
+func (l *ThinkingLogger) Log(event *StreamEvent) {
+	if l == nil || l.w == nil || (event.Type == "assistant" && event.Streamed) {
+		return
+	}
+
+	switch {
+	case event.Thinking != "" && event.Type == "assistant":
+		l.flush()
+		_, _ = io.WriteString(l.w, event.Thinking+"\n\n")
+		l.open = false
+	case event.Thinking != "":
+		l.write(event.Thinking)
+		l.open = true
+	case l.open && event.Type == "content_block_stop":
+		l.flush()
+		_, _ = io.WriteString(l.w, "\n\n")
+		l.open = false
+	}
+}
+
+// write writes a thinking delta. With a redactor, the text after its last
+// complete line is held until the line ends, then masked.
+func (l *ThinkingLogger) write(delta string) {
+	if l.redactor == nil {
+		_, _ = io.WriteString(l.w, delta)
+		return
+	}
+	text := l.held + delta
+	i := strings.LastIndexByte(text, '\n')
+	l.held = text[i+1:]
+	if i >= 0 {
+		_, _ = io.WriteString(l.w, l.redactor.Mask(text[:i+1]))
+	}
+}
+
+// flush writes the held partial line.
+func (l *ThinkingLogger) flush() {
+	if l.held != "" {
+		_, _ = io.WriteString(l.w, l.redactor.Mask(l.held))
+		l.held = ""
+	}
+}
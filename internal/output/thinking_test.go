@@ -0,0 +1,73 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/redact"
+)
+
+func TestParseThinkingMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ThinkingMode
+		wantErr bool
+	}{
+		{input: "", want: ThinkingDiscard},
+		{input: "discard", want: ThinkingDiscard},
+		{input: "show", want: ThinkingShow},
+		{input: "log", want: ThinkingLog},
+		{input: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseThinkingMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseThinkingMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseThinkingMode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThinkingLogger_Log(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewThinkingLogger(&buf)
+
+	logger.Log(&StreamEvent{Type: "content_block_delta", Thinking: "Let me "})
+	logger.Log(&StreamEvent{Type: "content_block_delta", Thinking: "check."})
+	logger.Log(&StreamEvent{Type: "content_block_stop"})
+	logger.Log(&StreamEvent{Type: "content_block_stop"})
+	logger.Log(&StreamEvent{Type: "assistant", Thinking: "Let me check.", Streamed: true})
+	logger.Log(&StreamEvent{Type: "assistant", Thinking: "Done."})
+	logger.Log(&StreamEvent{Type: "assistant", Content: "Not thinking"})
+
+	want := "Let me check.\n\nDone.\n\n"
+	if buf.String() != want {
+		t.Errorf("log = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestThinkingLogger_RedactsSplitSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewThinkingLogger(&buf)
+	logger.SetRedactor(redact.New(redact.Builtin))
+
+	logger.Log(&StreamEvent{Type: "content_block_delta", Thinking: "The key is AKIAIOSF"})
+	logger.Log(&StreamEvent{Type: "content_block_delta", Thinking: "ODNN7EXAMPLE.\nNext "})
+	logger.Log(&StreamEvent{Type: "content_block_delta", Thinking: "line."})
+	logger.Log(&StreamEvent{Type: "content_block_stop"})
+
+	want := "The key is [REDACTED:aws-access-key].\nNext line.\n\n"
+	if buf.String() != want {
+		t.Errorf("log = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestThinkingLogger_NilIsNoop(t *testing.T) {
+	var logger *ThinkingLogger
+	logger.Log(&StreamEvent{Type: "assistant", Thinking: "ignored"})
+}
@@ -0,0 +1,155 @@
+// Package redact masks secrets in Claude's output before it reaches the
+// TUI, the terminal, logs and run records.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CustomName is the name under which matches of configured patterns are
+// counted and labelled.
+const CustomName = "custom"
+
+// Pattern is a named secret pattern. When the expression has a capture
+// group only the first group is masked, so a key name before the secret
+// stays readable; otherwise the whole match is.
+type Pattern struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+// Builtin are the patterns applied unless disabled: AWS access key IDs and
+// secret keys, JWTs, PEM private keys and GitHub tokens. A private key
+// whose END line was cut off is masked to the end of its base64 body.
+var Builtin = []Pattern{
+	{Name: "aws-access-key", Re: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA)[0-9A-Z]{16}\b`)},
+	{Name: "aws-secret-key", Re: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key[\\"'\s:=]+([A-Za-z0-9/+]{40})\b`)},
+	{Name: "jwt", Re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{Name: "private-key", Re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?:[A-Za-z0-9+/=\s]|\\[nr])*(?:-----END [A-Z ]*PRIVATE KEY-----)?`)},
+	{Name: "github-token", Re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`)},
+}
+
+// Compile turns configured regular expressions into patterns named
+// CustomName.
+func Compile(exprs []string) ([]Pattern, error) {
+	patterns := make([]Pattern, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, Pattern{Name: CustomName, Re: re})
+	}
+	return patterns, nil
+}
+
+// Redactor masks matches of its patterns with "[REDACTED:<name>]" and counts
+// them. It is safe for concurrent use. A nil Redactor leaves text unchanged.
+type Redactor struct {
+	patterns []Pattern
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// New creates a redactor for patterns.
+func New(patterns []Pattern) *Redactor {
+	return &Redactor{patterns: patterns, counts: make(map[string]int)}
+}
+
+// Redact returns s with every secret masked.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, p := range r.patterns {
+		s = r.apply(p, s, true)
+	}
+	return s
+}
+
+// Mask returns s with every secret masked, without counting them. It is for
+// text whose secrets are counted elsewhere, such as streamed deltas that the
+// assistant message after them repeats.
+func (r *Redactor) Mask(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, p := range r.patterns {
+		s = r.apply(p, s, false)
+	}
+	return s
+}
+
+// apply masks the matches of one pattern in s, counting them if count is
+// set.
+func (r *Redactor) apply(p Pattern, s string, count bool) string {
+	matches := p.Re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+	mask := "[REDACTED:" + p.Name + "]"
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(mask)
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	if count {
+		r.mu.Lock()
+		r.counts[p.Name] += len(matches)
+		r.mu.Unlock()
+	}
+	return b.String()
+}
+
+// Count returns the number of secrets redacted so far.
+func (r *Redactor) Count() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, n := range r.counts {
+		total += n
+	}
+	return total
+}
+
+// Summary describes the redactions by pattern, e.g. "3 (2 aws-access-key,
+// 1 jwt)", or "" when nothing was redacted.
+func (r *Redactor) Summary() string {
+	if r == nil {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.counts) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(r.counts))
+	total := 0
+	for name, n := range r.counts {
+		names = append(names, name)
+		total += n
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%d %s", r.counts[name], name)
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
@@ -0,0 +1,43 @@
+package runs
+
+// AncestorFunc reports whether ancestor is commit or one of its ancestors.
+type AncestorFunc func(ancestor, commit string) (bool, error)
+
+// Attribution names the run, and the iteration of it, that produced a
+// commit.
+type Attribution struct {
+	Record    *Record
+	Iteration int
+}
+
+// Blame returns the run that produced commit, or nil when no recorded run
+// did. A run produced a commit that was not in its start commit's history
+// but is in one of its recorded heads; the iteration is the first whose
+// head contains it. Records are searched newest first.
+func Blame(records []*Record, commit string, isAncestor AncestorFunc) (*Attribution, error) {
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if len(rec.Heads) == 0 {
+			continue
+		}
+		if rec.StartCommit != "" {
+			before, err := isAncestor(commit, rec.StartCommit)
+			if err != nil {
+				return nil, err
+			}
+			if before {
+				continue
+			}
+		}
+		for _, h := range rec.Heads {
+			in, err := isAncestor(commit, h.Commit)
+			if err != nil {
+				return nil, err
+			}
+			if in {
+				return &Attribution{Record: rec, Iteration: h.Iteration}, nil
+			}
+		}
+	}
+	return nil, nil
+}
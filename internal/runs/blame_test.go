@@ -0,0 +1,79 @@
+package runs
+
+import (
+	"errors"
+	"testing"
+)
+
+// linearHistory is an AncestorFunc for a history of commits c1, c2, ... in
+// order, each the parent of the next.
+func linearHistory(ancestor, commit string) (bool, error) {
+	return ancestor <= commit, nil
+}
+
+func TestBlame(t *testing.T) {
+	first := &Record{SessionID: "first", StartCommit: "c1", Heads: []HeadRecord{{Iteration: 1, Commit: "c2"}, {Iteration: 3, Commit: "c4"}}}
+	second := &Record{SessionID: "second", StartCommit: "c4", Heads: []HeadRecord{{Iteration: 2, Commit: "c6"}}}
+	noCommits := &Record{SessionID: "none", StartCommit: "c6"}
+	records := []*Record{first, second, noCommits}
+
+	tests := []struct {
+		commit        string
+		wantSession   string
+		wantIteration int
+	}{
+		{commit: "c1"},
+		{commit: "c2", wantSession: "first", wantIteration: 1},
+		{commit: "c3", wantSession: "first", wantIteration: 3},
+		{commit: "c4", wantSession: "first", wantIteration: 3},
+		{commit: "c5", wantSession: "second", wantIteration: 2},
+		{commit: "c7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.commit, func(t *testing.T) {
+			got, err := Blame(records, tt.commit, linearHistory)
+			if err != nil {
+				t.Fatalf("Blame() error = %v", err)
+			}
+			if tt.wantSession == "" {
+				if got != nil {
+					t.Errorf("Blame() = %s iteration %d, want no run", got.Record.SessionID, got.Iteration)
+				}
+				return
+			}
+			if got == nil || got.Record.SessionID != tt.wantSession || got.Iteration != tt.wantIteration {
+				t.Errorf("Blame() = %+v, want %s iteration %d", got, tt.wantSession, tt.wantIteration)
+			}
+		})
+	}
+}
+
+func TestBlame_AncestorError(t *testing.T) {
+	records := []*Record{{SessionID: "s", StartCommit: "c1", Heads: []HeadRecord{{Iteration: 1, Commit: "c2"}}}}
+	failing := func(string, string) (bool, error) { return false, errors.New("git failed") }
+	if _, err := Blame(records, "c2", failing); err == nil {
+		t.Error("Blame() expected error")
+	}
+}
+
+func TestRecord_AddHead(t *testing.T) {
+	rec := &Record{StartCommit: "c1"}
+	rec.AddHead(1, "c1")
+	rec.AddHead(2, "c2")
+	rec.AddHead(3, "c2")
+	rec.AddHead(4, "")
+	rec.AddHead(5, "c3")
+
+	want := []HeadRecord{{Iteration: 2, Commit: "c2"}, {Iteration: 5, Commit: "c3"}}
+	if len(rec.Heads) != len(want) {
+		t.Fatalf("Heads = %+v, want %+v", rec.Heads, want)
+	}
+	for i := range want {
+		if rec.Heads[i] != want[i] {
+			t.Errorf("Heads[%d] = %+v, want %+v", i, rec.Heads[i], want[i])
+		}
+	}
+
+	var nilRec *Record
+	nilRec.AddHead(1, "c2")
+}
@@ -0,0 +1,278 @@
+package runs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/environment"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// Format selects how a comparison is rendered.
+type Format string
+
+const (
+	// FormatTable renders an aligned plain-text table.
+	FormatTable Format = "table"
+
+	// FormatMarkdown renders a GitHub-flavoured markdown table.
+	FormatMarkdown Format = "markdown"
+)
+
+// ComparisonRow is a single metric compared across two runs.
+type ComparisonRow struct {
+	Metric string
+	A      string
+	B      string
+	Delta  string
+}
+
+// Comparison holds the result of comparing two run records.
+type Comparison struct {
+	A    *Record
+	B    *Record
+	Rows []ComparisonRow
+}
+
+// Compare builds a side-by-side comparison of two run records.
+func Compare(a, b *Record) *Comparison {
+	c := &Comparison{A: a, B: b}
+
+	c.Rows = append(c.Rows,
+		ComparisonRow{Metric: "Workflow", A: a.Workflow, B: b.Workflow},
+		ComparisonRow{Metric: "Model", A: a.Model, B: b.Model},
+		ComparisonRow{Metric: "Outcome", A: a.Outcome, B: b.Outcome},
+		ComparisonRow{
+			Metric: "Iterations",
+			A:      fmt.Sprintf("%d", a.Iterations),
+			B:      fmt.Sprintf("%d", b.Iterations),
+			Delta:  formatIntDelta(b.Iterations - a.Iterations),
+		},
+		ComparisonRow{
+			Metric: "Cost",
+			A:      util.FormatCost(a.TotalCost, 4),
+			B:      util.FormatCost(b.TotalCost, 4),
+			Delta:  formatCostDelta(b.TotalCost - a.TotalCost),
+		},
+		ComparisonRow{
+			Metric: "Tokens",
+			A:      fmt.Sprintf("%d", a.TokensIn+a.TokensOut),
+			B:      fmt.Sprintf("%d", b.TokensIn+b.TokensOut),
+			Delta:  formatIntDelta((b.TokensIn + b.TokensOut) - (a.TokensIn + a.TokensOut)),
+		},
+		ComparisonRow{
+			Metric: "Duration",
+			A:      a.Duration().Round(time.Second).String(),
+			B:      b.Duration().Round(time.Second).String(),
+			Delta:  formatDurationDelta(b.Duration() - a.Duration()),
+		},
+		ComparisonRow{
+			Metric: "Steps run",
+			A:      fmt.Sprintf("%d", len(a.Steps)),
+			B:      fmt.Sprintf("%d", len(b.Steps)),
+			Delta:  formatIntDelta(len(b.Steps) - len(a.Steps)),
+		},
+		ComparisonRow{
+			Metric: "Gate failures",
+			A:      fmt.Sprintf("%d", gateFailures(a)),
+			B:      fmt.Sprintf("%d", gateFailures(b)),
+			Delta:  formatIntDelta(gateFailures(b) - gateFailures(a)),
+		},
+		ComparisonRow{
+			Metric: "Verifications",
+			A:      verificationSummary(a),
+			B:      verificationSummary(b),
+		},
+	)
+
+	// What the runs ran with, to tell a regression from an upgrade
+	envA, envB := environmentOf(a), environmentOf(b)
+	c.Rows = append(c.Rows,
+		ComparisonRow{Metric: "Orbital", A: envA.Orbital, B: envB.Orbital},
+		ComparisonRow{Metric: "Claude CLI", A: envA.Claude, B: envB.Claude},
+		ComparisonRow{Metric: "Platform", A: envA.OS, B: envB.OS},
+		ComparisonRow{Metric: "Config", A: envA.Config, B: envB.Config},
+	)
+
+	// Per-step execution counts across both runs
+	for _, name := range stepNames(a, b) {
+		countA, countB := stepCount(a, name), stepCount(b, name)
+		c.Rows = append(c.Rows, ComparisonRow{
+			Metric: "Step: " + name,
+			A:      fmt.Sprintf("%d", countA),
+			B:      fmt.Sprintf("%d", countB),
+			Delta:  formatIntDelta(countB - countA),
+		})
+	}
+
+	return c
+}
+
+// Render writes the comparison to w in the given format.
+func (c *Comparison) Render(w io.Writer, format Format) error {
+	switch format {
+	case FormatMarkdown:
+		return c.renderMarkdown(w)
+	case FormatTable, "":
+		return c.renderTable(w)
+	default:
+		return fmt.Errorf("unknown format %q (valid: table, markdown)", format)
+	}
+}
+
+// renderTable writes an aligned plain-text table followed by the git diffs.
+func (c *Comparison) renderTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "METRIC\tA (%s)\tB (%s)\tDELTA\n", shortID(c.A.SessionID), shortID(c.B.SessionID))
+	for _, row := range c.Rows {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row.Metric, row.A, row.B, row.Delta)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, side := range []struct {
+		label string
+		rec   *Record
+	}{{"A", c.A}, {"B", c.B}} {
+		_, _ = fmt.Fprintf(w, "\nFinal diff %s (%s):\n", side.label, shortID(side.rec.SessionID))
+		_, _ = fmt.Fprintln(w, indent(diffOrPlaceholder(side.rec), "  "))
+	}
+
+	return nil
+}
+
+// renderMarkdown writes a markdown table followed by the git diffs in code blocks.
+func (c *Comparison) renderMarkdown(w io.Writer) error {
+	_, _ = fmt.Fprintf(w, "| Metric | A (`%s`) | B (`%s`) | Delta |\n", shortID(c.A.SessionID), shortID(c.B.SessionID))
+	_, _ = fmt.Fprintln(w, "|--------|-----|-----|-------|")
+	for _, row := range c.Rows {
+		_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s |\n", row.Metric, escapeCell(row.A), escapeCell(row.B), row.Delta)
+	}
+
+	for _, side := range []struct {
+		label string
+		rec   *Record
+	}{{"A", c.A}, {"B", c.B}} {
+		_, _ = fmt.Fprintf(w, "\n### Final diff %s (`%s`)\n\n```\n%s\n```\n", side.label, shortID(side.rec.SessionID), diffOrPlaceholder(side.rec))
+	}
+
+	return nil
+}
+
+// environmentOf returns the environment a run recorded, falling back to
+// its orbital version for records made before environments were captured.
+func environmentOf(r *Record) environment.Snapshot {
+	if r.Environment != nil {
+		return *r.Environment
+	}
+	return environment.Snapshot{Orbital: r.Version}
+}
+
+// gateFailures counts gate steps that failed in the run.
+func gateFailures(r *Record) int {
+	count := 0
+	for _, s := range r.Steps {
+		if s.GateResult == "FAIL" {
+			count++
+		}
+	}
+	return count
+}
+
+// verificationSummary formats verification outcomes as "passed/total".
+func verificationSummary(r *Record) string {
+	if len(r.Verifications) == 0 {
+		return "none"
+	}
+	passed := 0
+	for _, v := range r.Verifications {
+		if v.Verified {
+			passed++
+		}
+	}
+	return fmt.Sprintf("%d/%d passed", passed, len(r.Verifications))
+}
+
+// stepNames returns the union of step names in both runs, in first-seen order.
+func stepNames(a, b *Record) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, rec := range []*Record{a, b} {
+		for _, s := range rec.Steps {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				names = append(names, s.Name)
+			}
+		}
+	}
+	return names
+}
+
+// stepCount returns how many times the named step was executed.
+func stepCount(r *Record, name string) int {
+	count := 0
+	for _, s := range r.Steps {
+		if s.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+func formatIntDelta(d int) string {
+	if d == 0 {
+		return "="
+	}
+	return fmt.Sprintf("%+d", d)
+}
+
+func formatCostDelta(d float64) string {
+	if d > -0.00005 && d < 0.00005 {
+		return "="
+	}
+	if d < 0 {
+		return "-" + util.FormatCost(-d, 4)
+	}
+	return "+" + util.FormatCost(d, 4)
+}
+
+func formatDurationDelta(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d == 0 {
+		return "="
+	}
+	if d < 0 {
+		return "-" + (-d).String()
+	}
+	return "+" + d.String()
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func diffOrPlaceholder(r *Record) string {
+	if r.DiffStat == "" {
+		return "(no changes recorded)"
+	}
+	return r.DiffStat
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,140 @@
+package runs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/environment"
+)
+
+func sampleRecords() (*Record, *Record) {
+	start := time.Unix(1700000000, 0)
+
+	a := &Record{
+		SessionID:  "aaaaaaaaaaaa",
+		Workflow:   "spec-driven",
+		Model:      "opus",
+		Outcome:    "completed",
+		StartedAt:  start,
+		FinishedAt: start.Add(10 * time.Minute),
+		Iterations: 4,
+		TotalCost:  2.5,
+		TokensIn:   1000,
+		TokensOut:  200,
+		Steps: []StepRecord{
+			{Iteration: 1, Name: "implement", Status: "completed"},
+			{Iteration: 2, Name: "implement", Status: "completed"},
+		},
+		Verifications: []VerificationRecord{{Iteration: 2, Verified: false}, {Iteration: 4, Verified: true}},
+		DiffStat:      " a.go | 2 +-",
+		Version:       "0.1.0",
+	}
+	b := &Record{
+		SessionID:  "bbbbbbbbbbbb",
+		Workflow:   "reviewed",
+		Model:      "sonnet",
+		Outcome:    "completed",
+		StartedAt:  start,
+		FinishedAt: start.Add(6 * time.Minute),
+		Iterations: 2,
+		TotalCost:  1.0,
+		TokensIn:   800,
+		TokensOut:  100,
+		Steps: []StepRecord{
+			{Iteration: 1, Name: "implement", Status: "completed"},
+			{Iteration: 1, Name: "review", Status: "failed", GateResult: "FAIL"},
+			{Iteration: 2, Name: "review", Status: "passed", GateResult: "PASS"},
+		},
+		Verifications: []VerificationRecord{{Iteration: 2, Verified: true}},
+		Environment:   &environment.Snapshot{Orbital: "0.2.0", Claude: "2.0.14 (Claude Code)", OS: "linux/amd64", Config: "9b1e04c2aa71"},
+	}
+	return a, b
+}
+
+func findRow(c *Comparison, metric string) *ComparisonRow {
+	for i := range c.Rows {
+		if c.Rows[i].Metric == metric {
+			return &c.Rows[i]
+		}
+	}
+	return nil
+}
+
+func TestCompare_Rows(t *testing.T) {
+	a, b := sampleRecords()
+	c := Compare(a, b)
+
+	tests := []struct {
+		metric       string
+		wantA, wantB string
+		wantDelta    string
+	}{
+		{"Iterations", "4", "2", "-2"},
+		{"Cost", "$2.5000", "$1.0000", "-$1.5000"},
+		{"Tokens", "1200", "900", "-300"},
+		{"Duration", "10m0s", "6m0s", "-4m0s"},
+		{"Steps run", "2", "3", "+1"},
+		{"Gate failures", "0", "1", "+1"},
+		{"Verifications", "1/2 passed", "1/1 passed", ""},
+		{"Step: implement", "2", "1", "-1"},
+		{"Step: review", "0", "2", "+2"},
+		{"Outcome", "completed", "completed", ""},
+		{"Orbital", "0.1.0", "0.2.0", ""},
+		{"Claude CLI", "", "2.0.14 (Claude Code)", ""},
+		{"Platform", "", "linux/amd64", ""},
+		{"Config", "", "9b1e04c2aa71", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			row := findRow(c, tt.metric)
+			if row == nil {
+				t.Fatalf("row %q not found", tt.metric)
+			}
+			if row.A != tt.wantA || row.B != tt.wantB || row.Delta != tt.wantDelta {
+				t.Errorf("row = {%q %q %q}, want {%q %q %q}", row.A, row.B, row.Delta, tt.wantA, tt.wantB, tt.wantDelta)
+			}
+		})
+	}
+}
+
+func TestComparison_Render(t *testing.T) {
+	a, b := sampleRecords()
+	c := Compare(a, b)
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := c.Render(&buf, FormatTable); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{"METRIC", "A (aaaaaaaa)", "Iterations", "a.go | 2 +-", "(no changes recorded)"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("table output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := c.Render(&buf, FormatMarkdown); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		out := buf.String()
+		// Diffs are rendered in code blocks and are not escaped
+		for _, want := range []string{"| Metric |", "| Iterations | 4 | 2 | -2 |", "### Final diff A", "a.go | 2 +-"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("markdown output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := c.Render(&buf, Format("html")); err == nil {
+			t.Error("Render() error = nil, want error for unknown format")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package runs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnose classifies why a run did not complete from its record, most
+// telling first: gates that never passed again, iterations that left the
+// repository unchanged, and the last verification. Each finding is one line,
+// such as `gate "review" exhausted retries 3× in iteration 4; last judge
+// feedback: "..."`. Returns nil for a nil record or one with nothing to
+// report.
+func Diagnose(r *Record) []string {
+	if r == nil {
+		return nil
+	}
+	var findings []string
+	findings = append(findings, diagnoseGates(r)...)
+	if n := len(r.Unchanged); n > 0 {
+		noun := "iterations"
+		if n == 1 {
+			noun = "iteration"
+		}
+		findings = append(findings, fmt.Sprintf("%d %s made no repository changes (%s)", n, noun, formatIterations(r.Unchanged)))
+	}
+	if n := len(r.Verifications); n > 0 {
+		if v := r.Verifications[n-1]; !v.Verified {
+			findings = append(findings, fmt.Sprintf("verification of iteration %d found %d of %d items unchecked", v.Iteration, v.Unchecked, v.Checked+v.Unchecked))
+		}
+	} else if len(r.Steps) > 0 {
+		findings = append(findings, "no iteration finished the workflow, so the work was never verified")
+	}
+	return findings
+}
+
+// gateHistory is what a run record tells about one gate.
+type gateHistory struct {
+	name       string
+	failures   int
+	lastFailed int    // iteration of the last failure
+	passed     bool   // the gate's last result was a pass
+	exhausted  []int  // iterations in which the gate used up its retries
+	retries    int    // failures it took to use them up
+	feedback   string // the judge's feedback on the last failure
+}
+
+// diagnoseGates reports the gates whose last result was not a pass, in the
+// order they first ran.
+func diagnoseGates(r *Record) []string {
+	var order []*gateHistory
+	gates := make(map[string]*gateHistory)
+	for _, s := range r.Steps {
+		if s.GateResult == "" && s.Exhausted == 0 {
+			continue
+		}
+		g := gates[s.Name]
+		if g == nil {
+			g = &gateHistory{name: s.Name}
+			gates[s.Name] = g
+			order = append(order, g)
+		}
+		g.passed = s.GateResult == "PASS"
+		if g.passed {
+			continue
+		}
+		g.failures++
+		g.lastFailed = s.Iteration
+		if s.Feedback != "" {
+			g.feedback = s.Feedback
+		}
+		if s.Exhausted > 0 {
+			g.exhausted = append(g.exhausted, s.Iteration)
+			g.retries = s.Exhausted
+		}
+	}
+
+	var findings []string
+	for _, g := range order {
+		if g.passed {
+			continue
+		}
+		var finding string
+		if len(g.exhausted) > 0 {
+			finding = fmt.Sprintf("gate %q exhausted retries %d× in %s", g.name, g.retries, iterationsLabel(g.exhausted))
+		} else {
+			finding = fmt.Sprintf("gate %q failed %d %s, last in iteration %d", g.name, g.failures, timesLabel(g.failures), g.lastFailed)
+		}
+		if g.feedback != "" {
+			finding += fmt.Sprintf("; last judge feedback: %q", g.feedback)
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// iterationsLabel names the iterations in a finding: "iteration 4" or
+// "iterations 4, 6-8".
+func iterationsLabel(iterations []int) string {
+	if len(iterations) == 1 {
+		return fmt.Sprintf("iteration %d", iterations[0])
+	}
+	return "iterations " + formatIterations(iterations)
+}
+
+// timesLabel returns "time" or "times" for n.
+func timesLabel(n int) string {
+	if n == 1 {
+		return "time"
+	}
+	return "times"
+}
+
+// formatIterations lists ascending iteration numbers, joining consecutive
+// ones into ranges: "2, 5-8".
+func formatIterations(iterations []int) string {
+	var parts []string
+	for i := 0; i < len(iterations); {
+		j := i
+		for j+1 < len(iterations) && iterations[j+1] == iterations[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, fmt.Sprintf("%d", iterations[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", iterations[i], iterations[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,74 @@
+package runs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  *Record
+		want []string
+	}{
+		{
+			name: "nil record",
+		},
+		{
+			name: "gate exhausted its retries",
+			rec: &Record{
+				Steps: []StepRecord{
+					{Iteration: 4, Name: "implement", Status: "completed"},
+					{Iteration: 4, Name: "review", GateResult: "FAIL", Feedback: "the parser has no tests"},
+					{Iteration: 4, Name: "review", GateResult: "FAIL", Feedback: "parser tests still missing", Exhausted: 2},
+					{Iteration: 5, Name: "review", GateResult: "FAIL", Exhausted: 1},
+					{Iteration: 6, Name: "review", GateResult: "FAIL", Exhausted: 1},
+				},
+				Verifications: []VerificationRecord{{Iteration: 3, Unchecked: 2, Checked: 5}},
+			},
+			want: []string{
+				`gate "review" exhausted retries 1× in iterations 4-6; last judge feedback: "parser tests still missing"`,
+				"verification of iteration 3 found 2 of 7 items unchecked",
+			},
+		},
+		{
+			name: "gate that passed again is not reported",
+			rec: &Record{
+				Steps: []StepRecord{
+					{Iteration: 1, Name: "review", GateResult: "FAIL", Feedback: "broken"},
+					{Iteration: 1, Name: "review", GateResult: "PASS"},
+					{Iteration: 2, Name: "lint", GateResult: "FAIL"},
+				},
+				Verifications: []VerificationRecord{{Iteration: 1, Verified: true}},
+			},
+			want: []string{`gate "lint" failed 1 time, last in iteration 2`},
+		},
+		{
+			name: "unchanged iterations and no verification",
+			rec: &Record{
+				Steps:     []StepRecord{{Iteration: 1, Name: "implement"}},
+				Unchanged: []int{2, 5, 6, 7, 8},
+			},
+			want: []string{
+				"5 iterations made no repository changes (2, 5-8)",
+				"no iteration finished the workflow, so the work was never verified",
+			},
+		},
+		{
+			name: "single unchanged iteration",
+			rec: &Record{
+				Unchanged:     []int{3},
+				Verifications: []VerificationRecord{{Iteration: 3, Verified: true}},
+			},
+			want: []string{"1 iteration made no repository changes (3)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diagnose(tt.rec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diagnose() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
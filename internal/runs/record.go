@@ -0,0 +1,325 @@
+// Package runs provides persistent records of completed orbital runs.
+// Records outlive session state so runs can be inspected and compared later.
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/environment"
+)
+
+// Record captures the outcome of a single orbital run.
+type Record struct {
+	// SessionID is orbital's internal state ID for the run.
+	SessionID string `json:"session_id"`
+
+	// SpecFiles are the spec and context files the run worked on.
+	SpecFiles []string `json:"spec_files"`
+
+	// Workflow is the name of the workflow used.
+	Workflow string `json:"workflow"`
+
+	// Model is the execution model.
+	Model string `json:"model"`
+
+	// StartedAt is when the run began.
+	StartedAt time.Time `json:"started_at"`
+
+	// FinishedAt is when the run ended.
+	FinishedAt time.Time `json:"finished_at"`
+
+	// Iterations is the number of loop iterations executed.
+	Iterations int `json:"iterations"`
+
+	// TotalCost is the cumulative cost in USD.
+	TotalCost float64 `json:"total_cost"`
+
+	// TokensIn is the cumulative number of input tokens.
+	TokensIn int `json:"tokens_in"`
+
+	// TokensOut is the cumulative number of output tokens.
+	TokensOut int `json:"tokens_out"`
+
+	// Completed is true if the run finished with verification passing.
+	Completed bool `json:"completed"`
+
+	// Outcome is a short description of how the run ended.
+	Outcome string `json:"outcome"`
+
+	// Steps lists every workflow step executed, in order.
+	Steps []StepRecord `json:"steps"`
+
+	// Verifications lists every verification check, in order.
+	Verifications []VerificationRecord `json:"verifications,omitempty"`
+
+	// StartCommit is the git HEAD when the run began (empty outside git).
+	StartCommit string `json:"start_commit,omitempty"`
+
+	// DiffStat is `git diff --stat` from StartCommit to the final working tree.
+	DiffStat string `json:"diff_stat,omitempty"`
+
+	// Heads records the git HEAD after each iteration that moved it, so the
+	// commits a run made can be traced back to it (see Blame).
+	Heads []HeadRecord `json:"heads,omitempty"`
+
+	// Version is the version of orbital that ran.
+	Version string `json:"version,omitempty"`
+
+	// Environment is the environment the run started in: orbital and Claude
+	// CLI versions, platform and config hash.
+	Environment *environment.Snapshot `json:"environment,omitempty"`
+
+	// Redactions describes the secrets masked in Claude's output by pattern.
+	Redactions string `json:"redactions,omitempty"`
+
+	// Unconstrained is true if Claude ran with --dangerously-skip-permissions.
+	Unconstrained bool `json:"unconstrained,omitempty"`
+
+	// Unchanged lists the iterations that left the repository as the
+	// previous one did.
+	Unchanged []int `json:"unchanged,omitempty"`
+}
+
+// StepRecord captures a single executed workflow step.
+type StepRecord struct {
+	Iteration  int           `json:"iteration"`
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	GateResult string        `json:"gate_result,omitempty"`
+	Model      string        `json:"model,omitempty"`
+	Escalated  bool          `json:"escalated,omitempty"`
+	Cost       float64       `json:"cost"`
+	Tokens     int           `json:"tokens"`
+	Duration   time.Duration `json:"duration"`
+
+	// Scores are the gate judge's rubric scores, if the step has a rubric.
+	Scores map[string]float64 `json:"scores,omitempty"`
+
+	// Artifacts are the declared artifact files the step wrote.
+	Artifacts []string `json:"artifacts,omitempty"`
+
+	// Verdicts are the review panel's verdicts by agent, true for approve.
+	Verdicts map[string]bool `json:"verdicts,omitempty"`
+
+	// CPUTime is the CPU time the Claude process used for the step.
+	CPUTime time.Duration `json:"cpu_time,omitempty"`
+
+	// PeakRSS is the Claude process's peak resident memory in bytes.
+	PeakRSS int64 `json:"peak_rss,omitempty"`
+
+	// Dry is set when the step was not sent to Claude (dry = true or
+	// --skip-step).
+	Dry bool `json:"dry,omitempty"`
+
+	// Exhausted is set on the gate failure that used up the gate's retries
+	// in the iteration, to the number of times it failed.
+	Exhausted int `json:"exhausted,omitempty"`
+
+	// Feedback is the end of the judge's reply when the gate failed.
+	Feedback string `json:"feedback,omitempty"`
+}
+
+// HeadRecord is the git HEAD at the end of an iteration.
+type HeadRecord struct {
+	Iteration int    `json:"iteration"`
+	Commit    string `json:"commit"`
+}
+
+// VerificationRecord captures a single verification check.
+type VerificationRecord struct {
+	Iteration int  `json:"iteration"`
+	Verified  bool `json:"verified"`
+	Unchecked int  `json:"unchecked"`
+	Checked   int  `json:"checked"`
+	// Confirmed is set when a --double-check verification agreed.
+	Confirmed bool `json:"confirmed,omitempty"`
+}
+
+// Dir returns the directory where run records are stored for the working directory.
+func Dir(workingDir string) string {
+	workingDir = strings.TrimSuffix(workingDir, "/")
+	return filepath.Join(workingDir, ".orbital", "runs")
+}
+
+// EventLogPath returns the path of the TUI event log for a session.
+// The .jsonl extension keeps it out of List.
+func EventLogPath(workingDir, sessionID string) string {
+	return filepath.Join(Dir(workingDir), sessionID+".events.jsonl")
+}
+
+// AuditLogPath returns the path of the shell command audit trail for a
+// session. Like the event log, it is kept out of List by its extension.
+func AuditLogPath(workingDir, sessionID string) string {
+	return filepath.Join(Dir(workingDir), sessionID+".audit.jsonl")
+}
+
+// NewRecord creates a record for a run starting now.
+func NewRecord(sessionID string, specFiles []string, workflowName, model string) *Record {
+	return &Record{
+		SessionID: sessionID,
+		SpecFiles: specFiles,
+		Workflow:  workflowName,
+		Model:     model,
+		StartedAt: time.Now(),
+	}
+}
+
+// AddStep appends a step to the record. Safe to call on a nil record.
+func (r *Record) AddStep(step StepRecord) {
+	if r == nil {
+		return
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+// AddHead records HEAD at the end of an iteration, unless it has not moved
+// since the last recorded head or the start of the run. Safe to call on a
+// nil record.
+func (r *Record) AddHead(iteration int, commit string) {
+	if r == nil || commit == "" {
+		return
+	}
+	last := r.StartCommit
+	if n := len(r.Heads); n > 0 {
+		last = r.Heads[n-1].Commit
+	}
+	if commit == last {
+		return
+	}
+	r.Heads = append(r.Heads, HeadRecord{Iteration: iteration, Commit: commit})
+}
+
+// AddUnchanged records an iteration that made no repository changes. Safe
+// to call on a nil record.
+func (r *Record) AddUnchanged(iteration int) {
+	if r == nil {
+		return
+	}
+	r.Unchanged = append(r.Unchanged, iteration)
+}
+
+// AddVerification appends a verification check to the record. Safe to call on a nil record.
+func (r *Record) AddVerification(v VerificationRecord) {
+	if r == nil {
+		return
+	}
+	r.Verifications = append(r.Verifications, v)
+}
+
+// Save writes the record to <workingDir>/.orbital/runs/<session-id>.json.
+func (r *Record) Save(workingDir string) error {
+	dir := Dir(workingDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	path := filepath.Join(dir, r.SessionID+".json")
+	tempPath := path + ".tmp"
+	if err := encrypt.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to rename run record: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a run record by session ID from the working directory.
+// A unique prefix of the session ID is accepted, as is a path to a record file.
+func Load(workingDir, ref string) (*Record, error) {
+	if strings.HasSuffix(ref, ".json") {
+		if _, err := os.Stat(ref); err == nil {
+			return loadFile(ref)
+		}
+	}
+
+	records, err := List(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Record
+	for _, rec := range records {
+		if rec.SessionID == ref {
+			return rec, nil
+		}
+		if strings.HasPrefix(rec.SessionID, ref) {
+			matches = append(matches, rec)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no recorded run matches %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("run reference %q is ambiguous (%d matches)", ref, len(matches))
+	}
+}
+
+// List returns all run records in the working directory, oldest first.
+// Returns an empty slice if no runs have been recorded.
+func List(workingDir string) ([]*Record, error) {
+	entries, err := os.ReadDir(Dir(workingDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Record{}, nil
+		}
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	records := make([]*Record, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		rec, err := loadFile(filepath.Join(Dir(workingDir), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartedAt.Before(records[j].StartedAt)
+	})
+
+	return records, nil
+}
+
+// loadFile reads a single run record from disk.
+func loadFile(path string) (*Record, error) {
+	data, err := encrypt.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run record: %w", err)
+	}
+
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal run record %s: %w", filepath.Base(path), err)
+	}
+
+	return &rec, nil
+}
+
+// Duration returns the wall-clock duration of the run.
+func (r *Record) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
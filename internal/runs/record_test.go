@@ -0,0 +1,132 @@
+package runs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecord_SaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	rec := NewRecord("abc123def456", []string{"/spec.md"}, "spec-driven", "opus")
+	rec.Iterations = 3
+	rec.TotalCost = 1.25
+	rec.AddStep(StepRecord{Iteration: 1, Name: "implement", Status: "completed", Cost: 0.5})
+	rec.AddVerification(VerificationRecord{Iteration: 3, Verified: true, Checked: 4})
+
+	if err := rec.Save(tmpDir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(Dir(tmpDir), "abc123def456.json")); err != nil {
+		t.Fatalf("record file not created: %v", err)
+	}
+
+	loaded, err := Load(tmpDir, "abc123def456")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Iterations != 3 || loaded.TotalCost != 1.25 {
+		t.Errorf("loaded = %+v, want iterations 3 and cost 1.25", loaded)
+	}
+	if len(loaded.Steps) != 1 || loaded.Steps[0].Name != "implement" {
+		t.Errorf("Steps = %+v, want one implement step", loaded.Steps)
+	}
+	if len(loaded.Verifications) != 1 || !loaded.Verifications[0].Verified {
+		t.Errorf("Verifications = %+v, want one passing verification", loaded.Verifications)
+	}
+}
+
+func TestLoad_ByPrefixAndPath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, id := range []string{"aaaa1111", "aaaa2222", "bbbb3333"} {
+		if err := NewRecord(id, nil, "fast", "opus").Save(tmpDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		wantID  string
+		wantErr bool
+	}{
+		{"unique prefix", "bbbb", "bbbb3333", false},
+		{"exact id", "aaaa1111", "aaaa1111", false},
+		{"ambiguous prefix", "aaaa", "", true},
+		{"no match", "cccc", "", true},
+		{"file path", filepath.Join(Dir(tmpDir), "aaaa2222.json"), "aaaa2222", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := Load(tmpDir, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Load(%q) error = nil, want error", tt.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load(%q) error = %v", tt.ref, err)
+			}
+			if rec.SessionID != tt.wantID {
+				t.Errorf("Load(%q).SessionID = %q, want %q", tt.ref, rec.SessionID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestList_SortedByStart(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := time.Now()
+
+	newer := NewRecord("newer", nil, "fast", "opus")
+	newer.StartedAt = base.Add(time.Hour)
+	older := NewRecord("older", nil, "fast", "opus")
+	older.StartedAt = base
+
+	for _, rec := range []*Record{newer, older} {
+		if err := rec.Save(tmpDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := List(tmpDir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(records) != 2 || records[0].SessionID != "older" || records[1].SessionID != "newer" {
+		t.Errorf("List() order = %v, want [older newer]", []string{records[0].SessionID, records[1].SessionID})
+	}
+}
+
+func TestList_NoRunsDirectory(t *testing.T) {
+	records, err := List(t.TempDir())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("List() returned %d records, want 0", len(records))
+	}
+}
+
+func TestRecord_NilSafeAdders(t *testing.T) {
+	var rec *Record
+	rec.AddStep(StepRecord{Name: "implement"})
+	rec.AddVerification(VerificationRecord{Verified: true})
+}
+
+func TestRecord_Duration(t *testing.T) {
+	rec := &Record{StartedAt: time.Unix(0, 0)}
+	if rec.Duration() != 0 {
+		t.Errorf("Duration() = %v for unfinished run, want 0", rec.Duration())
+	}
+	rec.FinishedAt = rec.StartedAt.Add(90 * time.Second)
+	if rec.Duration() != 90*time.Second {
+		t.Errorf("Duration() = %v, want 1m30s", rec.Duration())
+	}
+}
@@ -0,0 +1,186 @@
+package sandbox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// ChangeKind is how a file changed in the sandbox.
+type ChangeKind string
+
+// Kinds of change.
+const (
+	Added    ChangeKind = "added"
+	Modified ChangeKind = "modified"
+	Deleted  ChangeKind = "deleted"
+)
+
+// Change is a file changed in the sandbox since it was created.
+type Change struct {
+	// Path is the file's path relative to the tree, slash separated.
+	Path string
+
+	// Kind is how the file changed.
+	Kind ChangeKind
+
+	// Conflict is set when the original tree's file also changed since the
+	// sandbox was created, so applying the change would lose that work.
+	Conflict bool
+}
+
+// Changes compares the sandbox with the files recorded when it was created
+// and returns what changed, by path.
+func (s *Sandbox) Changes() ([]Change, error) {
+	current, err := hashTree(s.Path())
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for path, hash := range current {
+		before, ok := s.Files[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Kind: Added})
+		case hash != before:
+			changes = append(changes, Change{Path: path, Kind: Modified})
+		}
+	}
+	for path := range s.Files {
+		if _, ok := current[path]; !ok {
+			changes = append(changes, Change{Path: path, Kind: Deleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	for i, c := range changes {
+		changes[i].Conflict = s.originalChanged(c.Path)
+	}
+	return changes, nil
+}
+
+// originalChanged reports whether the original tree's file at path differs
+// from when the sandbox was created.
+func (s *Sandbox) originalChanged(path string) bool {
+	before, existed := s.Files[path]
+	hash, err := hashFile(filepath.Join(s.Original, filepath.FromSlash(path)))
+	if err != nil {
+		return existed || !os.IsNotExist(err)
+	}
+	return !existed || hash != before
+}
+
+// Diff writes a unified diff of changes, from the original tree to the
+// sandbox. Binary files are named rather than shown.
+func (s *Sandbox) Diff(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		before, err := readText(filepath.Join(s.Original, filepath.FromSlash(c.Path)))
+		if err != nil {
+			return err
+		}
+		after, err := readText(filepath.Join(s.Path(), filepath.FromSlash(c.Path)))
+		if err != nil {
+			return err
+		}
+		from, to := "a/"+c.Path, "b/"+c.Path
+		switch c.Kind {
+		case Added:
+			from = "/dev/null"
+		case Deleted:
+			to = "/dev/null"
+		}
+		if isBinary(before) || isBinary(after) {
+			if _, err := fmt.Fprintf(w, "Binary files %s and %s differ\n", from, to); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := io.WriteString(w, udiff.Unified(from, to, before, after)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply copies changes from the sandbox into the original tree: added and
+// modified files are written and deleted files removed. Unless force is
+// set, nothing is applied when any change conflicts with the original tree.
+func (s *Sandbox) Apply(changes []Change, force bool) error {
+	if !force {
+		var conflicts []string
+		for _, c := range changes {
+			if c.Conflict {
+				conflicts = append(conflicts, c.Path)
+			}
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("%w: %s", ErrConflict, strings.Join(conflicts, ", "))
+		}
+	}
+
+	for _, c := range changes {
+		dst := filepath.Join(s.Original, filepath.FromSlash(c.Path))
+		if c.Kind == Deleted {
+			if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete %s: %w", c.Path, err)
+			}
+			continue
+		}
+		if err := applyFile(filepath.Join(s.Path(), filepath.FromSlash(c.Path)), dst); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", c.Path, err)
+		}
+	}
+	return nil
+}
+
+// applyFile replaces dst with the sandbox's file src.
+func applyFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	}
+	return copyFile(src, dst, info.Mode().Perm())
+}
+
+// readText returns a file's content, or "" when it does not exist.
+func readText(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		return link + "\n", err
+	}
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+// isBinary reports whether content looks binary: it has a NUL byte in its
+// first 8 KB, as git decides.
+func isBinary(content string) bool {
+	return bytes.IndexByte([]byte(content[:min(len(content), 8000)]), 0) >= 0
+}
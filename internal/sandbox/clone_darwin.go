@@ -0,0 +1,11 @@
+//go:build darwin
+
+package sandbox
+
+import "golang.org/x/sys/unix"
+
+// cloneFile creates dst as an APFS clone of src, sharing its blocks until
+// either is written. It fails on filesystems without clones, such as HFS+.
+func cloneFile(src, dst string) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package sandbox
+
+import "errors"
+
+// cloneFile always fails: copy-on-write clones are only supported on Linux
+// and macOS, elsewhere files are copied.
+func cloneFile(src, dst string) error {
+	return errors.New("file clones are not supported on this platform")
+}
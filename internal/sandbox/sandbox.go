@@ -0,0 +1,288 @@
+// Package sandbox runs a session in a private copy of the project, so the
+// original tree is only changed when the copy's changes are applied back.
+// Files are cloned copy-on-write (reflinks on Linux, clonefile on macOS)
+// where the filesystem supports it and copied otherwise.
+package sandbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ModeCopy is the --sandbox mode that runs the session in a copy of the
+// project.
+const ModeCopy = "copy"
+
+// ErrConflict is returned by Apply when files changed in the sandbox were
+// also changed in the original tree since the sandbox was created.
+var ErrConflict = errors.New("changed in both the sandbox and the original tree")
+
+// skipCopy lists the top-level directories that are not copied into a
+// sandbox: orbital's own state, which holds the sandboxes themselves.
+var skipCopy = map[string]bool{".orbital": true}
+
+// skipDiff lists the top-level directories whose changes are not compared
+// or applied back. Git metadata is copied so git works in the sandbox, but
+// commits made there are not carried over.
+var skipDiff = map[string]bool{".orbital": true, ".git": true}
+
+// Root returns the directory the sandboxes of the project in workingDir are
+// kept in.
+func Root(workingDir string) string {
+	return filepath.Join(workingDir, ".orbital", "sandbox")
+}
+
+// Sandbox is a copy of a project tree.
+type Sandbox struct {
+	// ID names the sandbox; its copy is in Root(Original)/<ID>.
+	ID string `json:"id"`
+
+	// Original is the project directory the sandbox was copied from.
+	Original string `json:"original"`
+
+	// CreatedAt is when the sandbox was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Files maps each file's path, relative to the tree and slash
+	// separated, to its content hash when the sandbox was created.
+	Files map[string]string `json:"files"`
+}
+
+// Path returns the directory of the sandbox's copy.
+func (s *Sandbox) Path() string {
+	return filepath.Join(Root(s.Original), s.ID)
+}
+
+// manifestPath returns the file the sandbox is described in, kept next to
+// the copy so the session cannot change it.
+func manifestPath(original, id string) string {
+	return filepath.Join(Root(original), id+".json")
+}
+
+// Create copies the project in original into a new sandbox named id and
+// records the state of its files.
+func Create(original, id string) (*Sandbox, error) {
+	s := &Sandbox{ID: id, Original: original, CreatedAt: time.Now(), Files: make(map[string]string)}
+	dst := s.Path()
+	if _, err := os.Stat(dst); err == nil {
+		return nil, fmt.Errorf("sandbox %s already exists", id)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	if err := copyTree(original, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return nil, fmt.Errorf("failed to copy project into sandbox: %w", err)
+	}
+	files, err := hashTree(original)
+	if err != nil {
+		_ = os.RemoveAll(dst)
+		return nil, err
+	}
+	s.Files = files
+	if err := s.save(); err != nil {
+		_ = os.RemoveAll(dst)
+		return nil, err
+	}
+	return s, nil
+}
+
+// Open loads the sandbox id of the project in original.
+func Open(original, id string) (*Sandbox, error) {
+	data, err := os.ReadFile(manifestPath(original, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no sandbox %s in %s", id, Root(original))
+		}
+		return nil, fmt.Errorf("failed to read sandbox %s: %w", id, err)
+	}
+	var s Sandbox
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse sandbox %s: %w", id, err)
+	}
+	// The project may have moved since
+	s.Original = original
+	return &s, nil
+}
+
+// List returns the sandboxes of the project in original, oldest first.
+func List(original string) ([]*Sandbox, error) {
+	entries, err := os.ReadDir(Root(original))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list sandboxes: %w", err)
+	}
+	var sandboxes []*Sandbox
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		s, err := Open(original, id)
+		if err != nil {
+			return nil, err
+		}
+		sandboxes = append(sandboxes, s)
+	}
+	sort.SliceStable(sandboxes, func(i, j int) bool {
+		return sandboxes[i].CreatedAt.Before(sandboxes[j].CreatedAt)
+	})
+	return sandboxes, nil
+}
+
+// Remove deletes the sandbox's copy and its record.
+func (s *Sandbox) Remove() error {
+	if err := os.RemoveAll(s.Path()); err != nil {
+		return fmt.Errorf("failed to remove sandbox %s: %w", s.ID, err)
+	}
+	if err := os.Remove(manifestPath(s.Original, s.ID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove sandbox %s: %w", s.ID, err)
+	}
+	return nil
+}
+
+// save writes the sandbox's record.
+func (s *Sandbox) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sandbox: %w", err)
+	}
+	if err := os.WriteFile(manifestPath(s.Original, s.ID), data, 0644); err != nil {
+		return fmt.Errorf("failed to save sandbox: %w", err)
+	}
+	return nil
+}
+
+// copyTree copies the tree at src into dst, cloning files where possible.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() && skipCopy[rel] {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			// Sockets, devices and pipes are left out
+			return nil
+		}
+	})
+}
+
+// copyFile clones src to dst, falling back to copying its content when the
+// filesystem cannot clone.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := cloneFile(src, dst); err == nil {
+		return os.Chmod(dst, perm)
+	}
+	_ = os.Remove(dst)
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// hashTree returns the content hash of each file in the tree at dir, by
+// relative slash-separated path. Directories in skipDiff are left out.
+func hashTree(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDiff[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink == 0 && !d.Type().IsRegular() {
+			return nil
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// hashFile returns the hash of a file's content, or of a symlink's target.
+func hashFile(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		return "symlink:" + link, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
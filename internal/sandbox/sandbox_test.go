@@ -0,0 +1,238 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files under dir from a map of relative path to content.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newProject returns a project directory with a few files and orbital state.
+func newProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"spec.md":              "- [ ] Build it\n",
+		"src/main.go":          "package main\n",
+		"src/old.go":           "package main\n\n// old\n",
+		".orbital/config.toml": "",
+		".git/HEAD":            "ref: refs/heads/main\n",
+	})
+	return dir
+}
+
+func TestCreate(t *testing.T) {
+	dir := newProject(t)
+	if err := os.Symlink("src/main.go", filepath.Join(dir, "link.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Create(dir, "abc")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if s.Path() != filepath.Join(dir, ".orbital", "sandbox", "abc") {
+		t.Errorf("Path() = %s", s.Path())
+	}
+	data, err := os.ReadFile(filepath.Join(s.Path(), "src", "main.go"))
+	if err != nil || string(data) != "package main\n" {
+		t.Errorf("copied file = %q, %v", data, err)
+	}
+	if link, err := os.Readlink(filepath.Join(s.Path(), "link.go")); err != nil || link != "src/main.go" {
+		t.Errorf("copied symlink = %q, %v", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Path(), ".git", "HEAD")); err != nil {
+		t.Errorf("git metadata not copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.Path(), ".orbital")); !os.IsNotExist(err) {
+		t.Errorf("orbital state copied into the sandbox: %v", err)
+	}
+
+	var files []string
+	for path := range s.Files {
+		files = append(files, path)
+	}
+	if len(files) != 4 || s.Files["spec.md"] == "" || s.Files["link.go"] != "symlink:src/main.go" {
+		t.Errorf("Files = %v, want spec.md, link.go and the two sources", s.Files)
+	}
+
+	if _, err := Create(dir, "abc"); err == nil {
+		t.Error("Create() over an existing sandbox: expected error")
+	}
+}
+
+func TestChangesAndApply(t *testing.T) {
+	dir := newProject(t)
+	s, err := Create(dir, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The session adds, modifies and deletes files, and commits
+	writeFiles(t, s.Path(), map[string]string{
+		"src/main.go":     "package main\n\nfunc main() {}\n",
+		"src/new.go":      "package main\n",
+		".git/HEAD":       "ref: refs/heads/other\n",
+		".orbital/x.json": "{}",
+	})
+	if err := os.Remove(filepath.Join(s.Path(), "src", "old.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := s.Changes()
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []Change{
+		{Path: "src/main.go", Kind: Modified},
+		{Path: "src/new.go", Kind: Added},
+		{Path: "src/old.go", Kind: Deleted},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Changes() = %+v, want %+v", changes, want)
+	}
+
+	if err := s.Apply(changes, false); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "src", "main.go")); string(data) != "package main\n\nfunc main() {}\n" {
+		t.Errorf("modified file = %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "new.go")); err != nil {
+		t.Errorf("added file not applied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "old.go")); !os.IsNotExist(err) {
+		t.Errorf("deleted file still in the project: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".git", "HEAD")); string(data) != "ref: refs/heads/main\n" {
+		t.Errorf("git metadata applied: %q", data)
+	}
+}
+
+func TestApply_Conflicts(t *testing.T) {
+	dir := newProject(t)
+	s, err := Create(dir, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, s.Path(), map[string]string{"spec.md": "- [x] Build it\n", "src/new.go": "package sandbox\n"})
+	// Meanwhile the same files change in the project
+	writeFiles(t, dir, map[string]string{"spec.md": "- [ ] Build it properly\n", "src/new.go": "package project\n"})
+
+	changes, err := s.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || !changes[0].Conflict || !changes[1].Conflict {
+		t.Fatalf("Changes() = %+v, want both conflicting", changes)
+	}
+
+	err = s.Apply(changes, false)
+	if !errors.Is(err, ErrConflict) || !strings.Contains(err.Error(), "spec.md, src/new.go") {
+		t.Fatalf("Apply() error = %v, want ErrConflict naming both files", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "spec.md")); string(data) != "- [ ] Build it properly\n" {
+		t.Errorf("conflicting change applied without force: %q", data)
+	}
+
+	if err := s.Apply(changes, true); err != nil {
+		t.Fatalf("Apply(force) error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "spec.md")); string(data) != "- [x] Build it\n" {
+		t.Errorf("forced change not applied: %q", data)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := newProject(t)
+	s, err := Create(dir, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, s.Path(), map[string]string{
+		"spec.md":     "- [x] Build it\n",
+		"src/new.go":  "package main\n",
+		"image.png":   "\x89PNG\x00\x01",
+		"src/main.go": "package main\n",
+	})
+	if err := os.Remove(filepath.Join(s.Path(), "src", "old.go")); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := s.Diff(&out, changes); err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	for _, want := range []string{
+		"Binary files /dev/null and b/image.png differ",
+		"--- a/spec.md\n+++ b/spec.md\n",
+		"-- [ ] Build it\n+- [x] Build it\n",
+		"--- /dev/null\n+++ b/src/new.go\n",
+		"--- a/src/old.go\n+++ /dev/null\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Diff() missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "main.go") {
+		t.Errorf("Diff() shows an unchanged file:\n%s", out.String())
+	}
+}
+
+func TestOpenListRemove(t *testing.T) {
+	dir := newProject(t)
+	if sandboxes, err := List(dir); err != nil || len(sandboxes) != 0 {
+		t.Fatalf("List() = %v, %v, want none", sandboxes, err)
+	}
+	if _, err := Open(dir, "missing"); err == nil {
+		t.Error("Open() of a missing sandbox: expected error")
+	}
+
+	first, err := Create(dir, "b-first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Create(dir, "a-second")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sandboxes, err := List(dir)
+	if err != nil || len(sandboxes) != 2 || sandboxes[0].ID != first.ID || sandboxes[1].ID != second.ID {
+		t.Fatalf("List() = %v, %v, want both, oldest first", sandboxes, err)
+	}
+	opened, err := Open(dir, first.ID)
+	if err != nil || !reflect.DeepEqual(opened.Files, first.Files) {
+		t.Errorf("Open() = %+v, %v", opened, err)
+	}
+
+	if err := first.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(first.Path()); !os.IsNotExist(err) {
+		t.Errorf("sandbox copy not removed: %v", err)
+	}
+	if sandboxes, _ := List(dir); len(sandboxes) != 1 || sandboxes[0].ID != second.ID {
+		t.Errorf("List() after Remove() = %v", sandboxes)
+	}
+}
@@ -1,11 +1,18 @@
 package session
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/flashingpumpkin/orbital/internal/git"
 	"github.com/flashingpumpkin/orbital/internal/state"
 )
 
+// worktreeTimeout bounds the git call that finds sibling working trees.
+const worktreeTimeout = 5 * time.Second
+
 // Collector gathers all available sessions from a working directory.
 type Collector struct {
 	workingDir string
@@ -34,6 +41,9 @@ func (c *Collector) Collect() ([]Session, error) {
 		}
 	}
 
+	// Sessions left in the repository's other working trees
+	sessions = append(sessions, c.collectWorktreeSessions()...)
+
 	return sessions, nil
 }
 
@@ -48,14 +58,52 @@ func (c *Collector) collectRegularSession() (*Session, error) {
 		return nil, err
 	}
 
+	s := newStateSession(c.workingDir, st)
+	s.Type = SessionTypeRegular
+	s.Name = "Main session"
+	return &s, nil
+}
+
+// collectWorktreeSessions gathers the sessions saved in the other git
+// working trees of the repository the working directory belongs to. Outside
+// a git repository there are none.
+func (c *Collector) collectWorktreeSessions() []Session {
+	ctx, cancel := context.WithTimeout(context.Background(), worktreeTimeout)
+	defer cancel()
+	trees, err := git.Worktrees(ctx, c.workingDir)
+	if err != nil {
+		return nil
+	}
+
+	var sessions []Session
+	for _, wt := range trees {
+		if samePath(wt.Path, c.workingDir) || !state.Exists(wt.Path) {
+			continue
+		}
+		st, err := state.Load(wt.Path)
+		if err != nil {
+			continue
+		}
+		s := newStateSession(wt.Path, st)
+		s.Type = SessionTypeWorktree
+		s.Name = "Worktree " + filepath.Base(wt.Path)
+		s.WorktreeBranch = wt.Branch
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// newStateSession builds a session from the state saved in dir.
+func newStateSession(dir string, st *state.State) Session {
 	s := Session{
 		ID:           st.SessionID,
-		Type:         SessionTypeRegular,
-		Name:         "Main session",
 		SpecFiles:    st.ActiveFiles,
 		CreatedAt:    st.StartedAt,
 		RegularState: st,
 	}
+	if info, err := os.Stat(filepath.Join(state.StateDir(dir), "state.json")); err == nil {
+		s.UpdatedAt = info.ModTime()
+	}
 
 	// Validate: a session is invalid if it's currently running (not stale)
 	if !st.IsStale() {
@@ -64,8 +112,18 @@ func (c *Collector) collectRegularSession() (*Session, error) {
 	} else {
 		s.Valid = true
 	}
+	return s
+}
 
-	return &s, nil
+// samePath reports whether a and b name the same directory.
+func samePath(a, b string) bool {
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
 }
 
 // collectQueuedSession creates a synthetic session for queued files if no regular session exists.
@@ -3,9 +3,12 @@ package session
 import (
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/state"
 )
 
 func TestNewCollector(t *testing.T) {
@@ -207,3 +210,56 @@ func TestCollector_hasRegularSession(t *testing.T) {
 		})
 	}
 }
+
+func TestCollector_Collect_WorktreeSessions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	mainDir := t.TempDir()
+	linked := filepath.Join(t.TempDir(), "feature")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"worktree", "add", "-q", "-b", "feature", linked},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mainDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	st := state.NewState("worktree-session", linked, []string{"spec.md"}, "", nil)
+	st.PID = 99999999 // not running, so the session can be resumed
+	st.Iteration = 7
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := NewCollector(mainDir).Collect()
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Collect() returned %d sessions, want 1", len(sessions))
+	}
+	s := sessions[0]
+	if s.Type != SessionTypeWorktree || s.ID != "worktree-session" || s.Branch() != "feature" {
+		t.Errorf("session = %+v, want the worktree session on branch feature", s)
+	}
+	if s.Name != "Worktree feature" || s.Path() != linked || s.Iteration() != 7 {
+		t.Errorf("session name, path, iteration = %q, %q, %d", s.Name, s.Path(), s.Iteration())
+	}
+	if s.UpdatedAt.IsZero() || s.Status() != "interrupted" {
+		t.Errorf("session UpdatedAt = %v, Status() = %q; want last save and interrupted", s.UpdatedAt, s.Status())
+	}
+
+	// The linked worktree sees its own session as the main one
+	sessions, err = NewCollector(linked).Collect()
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Type != SessionTypeRegular {
+		t.Errorf("Collect() from the worktree = %+v, want only its regular session", sessions)
+	}
+}
@@ -14,6 +14,10 @@ type SessionType int
 const (
 	// SessionTypeRegular represents a regular session.
 	SessionTypeRegular SessionType = iota
+
+	// SessionTypeWorktree represents a session in another git working tree
+	// of the same repository.
+	SessionTypeWorktree
 )
 
 // Session represents a resumable orbital session.
@@ -41,6 +45,12 @@ type Session struct {
 
 	// RegularState holds the underlying regular state.
 	RegularState *state.State
+
+	// UpdatedAt is when the session's state was last saved.
+	UpdatedAt time.Time
+
+	// WorktreeBranch is the branch checked out in a worktree session.
+	WorktreeBranch string
 }
 
 // DisplayName returns a human-readable name for the session.
@@ -53,12 +63,54 @@ func (s *Session) DisplayName() string {
 
 // TypeLabel returns the session type label.
 func (s *Session) TypeLabel() string {
+	if s.Type == SessionTypeWorktree {
+		return "worktree"
+	}
 	return "regular"
 }
 
-// Branch returns an empty string (no branch for regular sessions).
+// Branch returns the branch of a worktree session, or "" for regular sessions.
 func (s *Session) Branch() string {
-	return ""
+	return s.WorktreeBranch
+}
+
+// Status describes where the session stands: "queued" for queued files
+// without a session yet, "running" for a session whose process is alive,
+// and "interrupted" for one that can be resumed.
+func (s *Session) Status() string {
+	switch {
+	case s.RegularState == nil:
+		return "queued"
+	case !s.Valid:
+		return "running"
+	default:
+		return "interrupted"
+	}
+}
+
+// Iteration returns the last iteration the session recorded.
+func (s *Session) Iteration() int {
+	if s.RegularState != nil {
+		return s.RegularState.Iteration
+	}
+	return 0
+}
+
+// Cost returns the session's total cost so far in USD.
+func (s *Session) Cost() float64 {
+	if s.RegularState != nil {
+		return s.RegularState.TotalCost
+	}
+	return 0
+}
+
+// LastActive returns when the session last saved its state, falling back to
+// when it was created.
+func (s *Session) LastActive() time.Time {
+	if !s.UpdatedAt.IsZero() {
+		return s.UpdatedAt
+	}
+	return s.CreatedAt
 }
 
 // Path returns the working directory path for the session.
@@ -164,3 +164,41 @@ func TestSession_FullSession(t *testing.T) {
 		t.Errorf("Path() = %q, want %q", s.Path(), "/home/user/project")
 	}
 }
+
+func TestSession_Status(t *testing.T) {
+	st := &state.State{Iteration: 4, TotalCost: 2.5}
+	tests := []struct {
+		name     string
+		session  Session
+		expected string
+	}{
+		{"queued files", Session{Valid: true}, "queued"},
+		{"running session", Session{RegularState: st, Valid: false}, "running"},
+		{"interrupted session", Session{RegularState: st, Valid: true}, "interrupted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Status(); got != tt.expected {
+				t.Errorf("Status() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	s := Session{RegularState: st}
+	if s.Iteration() != 4 || s.Cost() != 2.5 {
+		t.Errorf("Iteration(), Cost() = %d, %v; want 4, 2.5", s.Iteration(), s.Cost())
+	}
+}
+
+func TestSession_LastActive(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	s := Session{CreatedAt: created}
+	if !s.LastActive().Equal(created) {
+		t.Errorf("LastActive() = %v, want creation time %v", s.LastActive(), created)
+	}
+	s.UpdatedAt = created.Add(30 * time.Minute)
+	if !s.LastActive().Equal(s.UpdatedAt) {
+		t.Errorf("LastActive() = %v, want last save %v", s.LastActive(), s.UpdatedAt)
+	}
+}
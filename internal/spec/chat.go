@@ -0,0 +1,53 @@
+package spec
+
+import "strings"
+
+// ChatPrompt is the prompt template for questions asked from the TUI chat pane.
+// The model answers questions about the running session without changing it.
+const ChatPrompt = `You are answering a question from the user about an orbital run that is in progress.
+Another agent is working through the following spec file(s):
+
+{{files}}
+{{notes}}
+Recent output from the run:
+
+` + "```" + `
+{{output}}
+` + "```" + `
+
+Read the files above if they help you answer. Do not modify any files and do not run
+commands that change the working tree; the run is still in progress.
+
+Answer the following question concisely:
+
+{{question}}`
+
+// BuildChatPrompt generates the prompt for a chat question about the current run.
+// recentOutput is the tail of the run's output, most recent line last.
+func BuildChatPrompt(files []string, notesFile string, recentOutput []string, question string) string {
+	var fileList strings.Builder
+	for _, path := range files {
+		fileList.WriteString("- ")
+		fileList.WriteString(path)
+		fileList.WriteString("\n")
+	}
+
+	notes := ""
+	if notesFile != "" {
+		notes = "\nThe agent records its progress and blockers in " + notesFile + ".\n"
+	}
+
+	output := strings.Join(recentOutput, "\n")
+	if strings.TrimSpace(output) == "" {
+		output = "(no output yet)"
+	}
+
+	// Single-pass replacement so placeholders inside the output or question are left alone
+	r := strings.NewReplacer(
+		"{{files}}", strings.TrimSuffix(fileList.String(), "\n"),
+		"{{notes}}", notes,
+		"{{output}}", output,
+		"{{question}}", strings.TrimSpace(question),
+	)
+	return r.Replace(ChatPrompt)
+}
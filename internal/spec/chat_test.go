@@ -0,0 +1,38 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildChatPrompt(t *testing.T) {
+	prompt := BuildChatPrompt(
+		[]string{"/path/spec.md"},
+		"/path/notes.md",
+		[]string{"running tests", "FAIL TestFoo"},
+		"  why did the gate fail?  ",
+	)
+
+	for _, want := range []string{
+		"- /path/spec.md",
+		"/path/notes.md",
+		"running tests\nFAIL TestFoo",
+		"why did the gate fail?",
+		"Do not modify any files",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestBuildChatPrompt_NoOutput(t *testing.T) {
+	prompt := BuildChatPrompt([]string{"/path/spec.md"}, "", nil, "status?")
+
+	if !strings.Contains(prompt, "(no output yet)") {
+		t.Error("expected placeholder for empty output")
+	}
+	if strings.Contains(prompt, "{{") {
+		t.Error("prompt contains unreplaced placeholders")
+	}
+}
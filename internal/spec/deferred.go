@@ -0,0 +1,83 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DeferredHeading titles the spec section that collects work deferred in
+// TODO(orbital) code comments.
+const DeferredHeading = "## Deferred in code"
+
+// checkboxPattern matches a markdown task list item, ticked or not.
+var checkboxPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[[ xX]\]\s+(.+?)\s*$`)
+
+// AppendDeferred adds an unticked item for each of items to the
+// DeferredHeading section of the spec at path, creating the section at the
+// end of the file if needed. Items already in the spec as a task list item,
+// ticked or not, are skipped so an item is only added once. It returns the
+// items that were added.
+func AppendDeferred(path string, items []string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+
+	present := make(map[string]bool)
+	for _, line := range lines {
+		if m := checkboxPattern.FindStringSubmatch(line); m != nil {
+			present[m[1]] = true
+		}
+	}
+	var added []string
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || present[item] {
+			continue
+		}
+		present[item] = true
+		added = append(added, item)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]string, len(added))
+	for i, item := range added {
+		entries[i] = "- [ ] " + item
+	}
+
+	// Insert after the last item of an existing section, or start one
+	at := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == DeferredHeading {
+			at = i + 1
+			for j := i + 1; j < len(lines) && !strings.HasPrefix(lines[j], "#"); j++ {
+				if strings.TrimSpace(lines[j]) != "" {
+					at = j + 1
+				}
+			}
+			break
+		}
+	}
+	if at < 0 {
+		lines = append(lines, "", DeferredHeading, "")
+		lines = append(lines, entries...)
+	} else {
+		if !checkboxPattern.MatchString(lines[at-1]) {
+			entries = append([]string{""}, entries...)
+		}
+		if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+			entries = append(entries, "")
+		}
+		lines = append(lines[:at], append(entries, lines[at:]...)...)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write spec file: %w", err)
+	}
+	return added, nil
+}
@@ -0,0 +1,81 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppendDeferred(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		items     []string
+		wantAdded []string
+		want      string
+	}{
+		{
+			name:      "creates section",
+			spec:      "# Spec\n\n- [ ] Build it\n",
+			items:     []string{"handle errors (main.go)"},
+			wantAdded: []string{"handle errors (main.go)"},
+			want:      "# Spec\n\n- [ ] Build it\n\n## Deferred in code\n\n- [ ] handle errors (main.go)\n",
+		},
+		{
+			name:      "skips items already listed",
+			spec:      "# Spec\n\n- [x] handle errors (main.go)\n",
+			items:     []string{"handle errors (main.go)", "add docs (README.md)", "add docs (README.md)"},
+			wantAdded: []string{"add docs (README.md)"},
+			want:      "# Spec\n\n- [x] handle errors (main.go)\n\n## Deferred in code\n\n- [ ] add docs (README.md)\n",
+		},
+		{
+			name:      "extends existing section",
+			spec:      "# Spec\n\n## Deferred in code\n\n- [ ] first (a.go)\n\n## Notes\n\nText\n",
+			items:     []string{"second (b.go)"},
+			wantAdded: []string{"second (b.go)"},
+			want:      "# Spec\n\n## Deferred in code\n\n- [ ] first (a.go)\n- [ ] second (b.go)\n\n## Notes\n\nText\n",
+		},
+		{
+			name:      "fills empty section",
+			spec:      "# Spec\n\n## Deferred in code\n## Notes\n",
+			items:     []string{"first (a.go)"},
+			wantAdded: []string{"first (a.go)"},
+			want:      "# Spec\n\n## Deferred in code\n\n- [ ] first (a.go)\n\n## Notes\n",
+		},
+		{
+			name:  "nothing new",
+			spec:  "# Spec\n\n- [ ] first (a.go)\n",
+			items: []string{"first (a.go)", " "},
+			want:  "# Spec\n\n- [ ] first (a.go)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec.md")
+			if err := os.WriteFile(path, []byte(tt.spec), 0644); err != nil {
+				t.Fatal(err)
+			}
+			added, err := AppendDeferred(path, tt.items)
+			if err != nil {
+				t.Fatalf("AppendDeferred() error = %v", err)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added = %q, want %q", added, tt.wantAdded)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("spec =\n%s\nwant\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendDeferred_MissingFile(t *testing.T) {
+	if _, err := AppendDeferred(filepath.Join(t.TempDir(), "missing.md"), []string{"x"}); err == nil {
+		t.Error("expected an error for a missing spec")
+	}
+}
@@ -0,0 +1,102 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// codeSpanPattern matches inline code spans such as `internal/foo.go`.
+	codeSpanPattern = regexp.MustCompile("`([^`\n]+)`")
+	// linkPattern matches markdown link targets such as [foo](docs/foo.md "title").
+	linkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	// lineSuffixPattern matches a trailing line reference such as ":42" or ":42:7".
+	lineSuffixPattern = regexp.MustCompile(`:\d+(:\d+)?$`)
+)
+
+// DiscoverReferences parses the spec for referenced paths (inline code spans
+// and markdown links) and returns the ones that name existing regular files.
+// References are resolved against the spec's directory first, then against
+// workingDir. The spec itself is never returned, and each file appears once
+// in the order it is first referenced.
+func DiscoverReferences(specPath, workingDir string) ([]string, error) {
+	content, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	specAbs, _ := filepath.Abs(specPath)
+	bases := []string{filepath.Dir(specPath)}
+	if workingDir != "" && workingDir != bases[0] {
+		bases = append(bases, workingDir)
+	}
+
+	seen := map[string]bool{specAbs: true}
+	var found []string
+	for _, ref := range extractReferences(string(content)) {
+		for _, base := range bases {
+			path := filepath.Join(base, ref)
+			if filepath.IsAbs(ref) {
+				path = ref
+			}
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			abs, err := filepath.Abs(path)
+			if err != nil || seen[abs] {
+				break
+			}
+			seen[abs] = true
+			found = append(found, path)
+			break
+		}
+	}
+	return found, nil
+}
+
+// extractReferences returns candidate paths from inline code spans and link
+// targets, skipping fenced code blocks, URLs, anchors and anything that looks
+// like a command rather than a path.
+func extractReferences(content string) []string {
+	var refs []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		for _, m := range linkPattern.FindAllStringSubmatch(line, -1) {
+			target, _, _ := strings.Cut(m[1], "#")
+			if ref := cleanReference(target); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+		for _, m := range codeSpanPattern.FindAllStringSubmatch(line, -1) {
+			if ref := cleanReference(m[1]); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// cleanReference normalises a candidate path, returning "" when it cannot be
+// a local file reference.
+func cleanReference(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.ContainsAny(ref, " \t*?<>|") {
+		return ""
+	}
+	if strings.Contains(ref, "://") || strings.HasPrefix(ref, "mailto:") || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	return lineSuffixPattern.ReplaceAllString(ref, "")
+}
@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "code spans and links",
+			content: "Update `internal/foo.go` per [the design](docs/design.md).",
+			want:    []string{"docs/design.md", "internal/foo.go"},
+		},
+		{
+			name:    "link anchor and title stripped",
+			content: `See [notes](docs/notes.md#setup "Setup").`,
+			want:    []string{"docs/notes.md"},
+		},
+		{
+			name:    "line suffix stripped",
+			content: "Bug at `cmd/root.go:42` and `cmd/run.go:10:3`",
+			want:    []string{"cmd/root.go", "cmd/run.go"},
+		},
+		{
+			name:    "urls anchors and commands skipped",
+			content: "[site](https://example.com) [top](#top) `go test ./...` `*.go` [mail](mailto:a@b.c)",
+			want:    nil,
+		},
+		{
+			name:    "fenced blocks skipped",
+			content: "```go\nx := `internal/fenced.go`\n```\n`after.go`",
+			want:    []string{"after.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractReferences(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverReferences(t *testing.T) {
+	workDir := t.TempDir()
+	specDir := filepath.Join(workDir, "docs", "plans")
+	for _, path := range []string{
+		filepath.Join(specDir, "sibling.md"),
+		filepath.Join(workDir, "internal", "foo.go"),
+		filepath.Join(workDir, "README.md"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	specPath := filepath.Join(specDir, "spec.md")
+	content := "# Spec\n\n" +
+		"- [ ] Follow [sibling](sibling.md)\n" +
+		"- [ ] Edit `internal/foo.go` and `internal/foo.go:12`\n" +
+		"- [ ] Mention `README.md`, `missing.go` and the `internal` dir\n" +
+		"- [ ] Ignore [self](spec.md)\n"
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := DiscoverReferences(specPath, workDir)
+	if err != nil {
+		t.Fatalf("DiscoverReferences() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(specDir, "sibling.md"),
+		filepath.Join(workDir, "internal", "foo.go"),
+		filepath.Join(workDir, "README.md"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverReferences() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverReferences_MissingSpec(t *testing.T) {
+	if _, err := DiscoverReferences(filepath.Join(t.TempDir(), "nope.md"), ""); err == nil {
+		t.Error("DiscoverReferences() error = nil, want error")
+	}
+}
@@ -0,0 +1,119 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// followUpPrefix names follow-up specs after the spec they continue.
+const followUpPrefix = "continuation-of-"
+
+// checkedPattern matches a ticked markdown task list item.
+var checkedPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[[xX]\]\s+(.+?)\s*$`)
+
+// datePrefixPattern matches the date NewSpecPath and FollowUpPath put in
+// front of spec names.
+var datePrefixPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+
+// FollowUp describes a run that stopped with work left, for a follow-up spec
+// containing only what remains.
+type FollowUp struct {
+	// Spec is the spec the run worked on.
+	Spec string
+	// Reason says why the run stopped, e.g. "max iterations reached".
+	Reason string
+	// Iterations is the number of iterations the run took.
+	Iterations int
+	// Cost is the run's cost, already formatted.
+	Cost string
+	// DiffStat summarises the changes the run made (may be empty).
+	DiffStat string
+	// NotesFile is the run's notes file (may be empty).
+	NotesFile string
+}
+
+// CheckedItems returns the text of every ticked "- [x]" item in the files,
+// in file order.
+func CheckedItems(paths []string) ([]string, error) {
+	var items []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spec file: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if m := checkedPattern.FindStringSubmatch(line); m != nil {
+				items = append(items, m[1])
+			}
+		}
+	}
+	return items, nil
+}
+
+// FollowUpPath returns a path in dir for the follow-up of spec:
+// <dir>/<date>-continuation-of-<name>.md, numbered when that already exists.
+// Following up a follow-up keeps the original name rather than nesting.
+func FollowUpPath(dir, spec string, now time.Time) string {
+	name := strings.TrimSuffix(filepath.Base(spec), filepath.Ext(spec))
+	name = datePrefixPattern.ReplaceAllString(name, "")
+	name = strings.TrimPrefix(slugify(name), followUpPrefix)
+	if name == "" {
+		name = "spec"
+	}
+
+	base := filepath.Join(dir, now.Format("2006-01-02")+"-"+followUpPrefix+name)
+	path := base + ".md"
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d.md", base, n)
+	}
+}
+
+// FollowUpContent renders a follow-up spec: the items done so far as a
+// summary and the remaining items as the only checkboxes.
+func FollowUpContent(f FollowUp, done, remaining []string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Continuation of %s\n\n", specTitle(f.Spec))
+	fmt.Fprintf(&b, "Created: %s\n", now.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Continues: %s\n\n", f.Spec)
+
+	b.WriteString("## Done So Far\n\n")
+	fmt.Fprintf(&b, "The previous run stopped after %d iteration(s) (%s) at a cost of %s.", f.Iterations, f.Reason, f.Cost)
+	if f.NotesFile != "" {
+		fmt.Fprintf(&b, " Its notes are in %s.", f.NotesFile)
+	}
+	b.WriteString("\n\n")
+	if len(done) == 0 {
+		b.WriteString("No items were completed.\n")
+	}
+	for _, item := range done {
+		fmt.Fprintf(&b, "- %s\n", item)
+	}
+	if f.DiffStat != "" {
+		fmt.Fprintf(&b, "\nChanges made:\n\n```\n%s\n```\n", strings.TrimRight(f.DiffStat, "\n"))
+	}
+
+	b.WriteString("\n## Remaining\n\n")
+	for _, item := range remaining {
+		fmt.Fprintf(&b, "- [ ] %s\n", item)
+	}
+	return b.String()
+}
+
+// specTitle returns the first heading of the spec at path, or its file name.
+func specTitle(path string) string {
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if title, ok := strings.CutPrefix(line, "# "); ok && strings.TrimSpace(title) != "" {
+				return strings.TrimPrefix(strings.TrimSpace(title), "Continuation of ")
+			}
+		}
+	}
+	return filepath.Base(path)
+}
@@ -0,0 +1,96 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	content := "# Spec\n\n- [x] First\n- [ ] Second\n  * [X] Nested third\n1. [x] Numbered\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := CheckedItems([]string{path})
+	if err != nil {
+		t.Fatalf("CheckedItems() error = %v", err)
+	}
+	if want := []string{"First", "Nested third", "Numbered"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("CheckedItems() = %q, want %q", got, want)
+	}
+}
+
+func TestFollowUpPath(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"docs/plans/2026-03-01-user-auth.md", "2026-03-02-continuation-of-user-auth.md"},
+		{"specs/Rate Limits.md", "2026-03-02-continuation-of-rate-limits.md"},
+		{"docs/plans/2026-03-01-continuation-of-user-auth.md", "2026-03-02-continuation-of-user-auth.md"},
+		{"docs/plans/!!!.md", "2026-03-02-continuation-of-spec.md"},
+	}
+	for _, tt := range tests {
+		if got := FollowUpPath(dir, tt.spec, now); got != filepath.Join(dir, tt.want) {
+			t.Errorf("FollowUpPath(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	taken := filepath.Join(dir, "2026-03-02-continuation-of-user-auth.md")
+	if err := os.WriteFile(taken, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := FollowUpPath(dir, "user-auth.md", now), filepath.Join(dir, "2026-03-02-continuation-of-user-auth-2.md"); got != want {
+		t.Errorf("FollowUpPath() with an existing follow-up = %q, want %q", got, want)
+	}
+}
+
+func TestFollowUpContent(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "auth.md")
+	if err := os.WriteFile(specPath, []byte("# User Auth\n\n- [x] Login\n- [ ] Logout\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := FollowUp{
+		Spec:       specPath,
+		Reason:     "max iterations reached",
+		Iterations: 50,
+		Cost:       "$12.50",
+		DiffStat:   " auth.go | 10 ++++\n 1 file changed\n",
+		NotesFile:  "docs/notes/auth.md",
+	}
+	got := FollowUpContent(f, []string{"Login"}, []string{"Logout", "Password reset"}, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+
+	for _, want := range []string{
+		"# Continuation of User Auth\n",
+		"Created: 2026-03-02\n",
+		"Continues: " + specPath + "\n",
+		"stopped after 50 iteration(s) (max iterations reached) at a cost of $12.50. Its notes are in docs/notes/auth.md.",
+		"- Login\n",
+		"```\n auth.go | 10 ++++\n 1 file changed\n```\n",
+		"## Remaining\n\n- [ ] Logout\n- [ ] Password reset\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FollowUpContent() lacks %q:\n%s", want, got)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "followup.md")
+	if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+		t.Fatal(err)
+	}
+	remaining, err := UncheckedItems([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("follow-up has %d unchecked items, want only the 2 remaining", len(remaining))
+	}
+	if title := specTitle(path); title != "User Auth" {
+		t.Errorf("title of a follow-up = %q, want the original title", title)
+	}
+}
@@ -0,0 +1,210 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FrontMatterKey is the front-matter key whose value holds orbital's
+// per-spec defaults. Other keys are left to other tools.
+const FrontMatterKey = "orbital"
+
+// Defaults are run parameters a spec sets for itself in its front matter.
+// Zero values are unset. Explicit command-line flags take precedence.
+type Defaults struct {
+	Workflow     string
+	Model        string
+	CheckerModel string
+	Budget       float64
+	Iterations   int
+	MaxTurns     int
+}
+
+// IsZero reports whether no default is set.
+func (d Defaults) IsZero() bool {
+	return d == Defaults{}
+}
+
+// LoadDefaults reads the defaults from the front matter of the spec at path.
+// A spec without front matter, or without an orbital key in it, has none.
+func LoadDefaults(path string) (Defaults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Defaults{}, fmt.Errorf("failed to read spec file: %w", err)
+	}
+	d, err := ParseDefaults(string(data))
+	if err != nil {
+		return Defaults{}, fmt.Errorf("invalid front matter in %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// ParseDefaults parses the orbital key of a YAML front-matter block at the
+// start of content. The key takes a flow mapping on one line:
+//
+//	---
+//	orbital: {workflow: tdd, budget: 20, model: sonnet}
+//	---
+//
+// or a block mapping indented below it. Only the flat scalar subset of YAML
+// that these settings need is understood.
+func ParseDefaults(content string) (Defaults, error) {
+	block, ok := frontMatter(content)
+	if !ok {
+		return Defaults{}, nil
+	}
+
+	var d Defaults
+	lines := strings.Split(block, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := stripComment(lines[i])
+		if strings.TrimSpace(line) == "" || isIndented(line) {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(key) != FrontMatterKey {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if value != "" {
+			if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+				return Defaults{}, fmt.Errorf("%s must be a mapping", FrontMatterKey)
+			}
+			for _, pair := range splitFlow(value[1 : len(value)-1]) {
+				if strings.TrimSpace(pair) == "" {
+					continue
+				}
+				if err := d.set(pair); err != nil {
+					return Defaults{}, err
+				}
+			}
+			continue
+		}
+
+		for i+1 < len(lines) && (isIndented(lines[i+1]) || strings.TrimSpace(lines[i+1]) == "") {
+			i++
+			pair := strings.TrimSpace(stripComment(lines[i]))
+			if pair == "" {
+				continue
+			}
+			if err := d.set(pair); err != nil {
+				return Defaults{}, err
+			}
+		}
+	}
+	return d, nil
+}
+
+// set applies one "key: value" pair.
+func (d *Defaults) set(pair string) error {
+	key, raw, found := strings.Cut(pair, ":")
+	if !found {
+		return fmt.Errorf("expected key: value, got %q", strings.TrimSpace(pair))
+	}
+	key = strings.TrimSpace(key)
+	value := unquote(strings.TrimSpace(raw))
+	if value == "" {
+		return fmt.Errorf("%s.%s has no value", FrontMatterKey, key)
+	}
+
+	var err error
+	switch key {
+	case "workflow":
+		d.Workflow = value
+	case "model":
+		d.Model = value
+	case "checker_model":
+		d.CheckerModel = value
+	case "budget":
+		if d.Budget, err = strconv.ParseFloat(value, 64); err == nil && d.Budget <= 0 {
+			err = fmt.Errorf("must be positive")
+		}
+	case "iterations":
+		if d.Iterations, err = strconv.Atoi(value); err == nil && d.Iterations < 1 {
+			err = fmt.Errorf("must be at least 1")
+		}
+	case "max_turns":
+		if d.MaxTurns, err = strconv.Atoi(value); err == nil && d.MaxTurns < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown key %s.%s (valid: workflow, model, checker_model, budget, iterations, max_turns)", FrontMatterKey, key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s.%s %q: %w", FrontMatterKey, key, value, err)
+	}
+	return nil
+}
+
+// frontMatter returns the block between a leading "---" line and the next
+// "---" or "..." line.
+func frontMatter(content string) (string, bool) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimRight(first, " \t") != "---" {
+		return "", false
+	}
+	lines := strings.Split(rest, "\n")
+	for i, line := range lines {
+		if end := strings.TrimRight(line, " \t"); end == "---" || end == "..." {
+			return strings.Join(lines[:i], "\n"), true
+		}
+	}
+	return "", false
+}
+
+// splitFlow splits the inside of a flow mapping on commas outside quotes.
+func splitFlow(s string) []string {
+	var parts []string
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// stripComment removes a "#" comment that is not inside quotes.
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote removes matching single or double quotes around s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// isIndented reports whether line starts with whitespace.
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
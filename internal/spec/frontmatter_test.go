@@ -0,0 +1,97 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Defaults
+		wantErr string
+	}{
+		{
+			name:    "flow mapping",
+			content: "---\norbital: {workflow: tdd, budget: 20, model: sonnet}\n---\n# Spec\n",
+			want:    Defaults{Workflow: "tdd", Budget: 20, Model: "sonnet"},
+		},
+		{
+			name: "block mapping with comments and other keys",
+			content: "---\ntitle: Login page\norbital:\n  workflow: reviewed  # needs review\n" +
+				"  checker_model: \"haiku\"\n\n  iterations: 8\n  max_turns: 30\ntags: [ui]\n---\n- [ ] build it\n",
+			want: Defaults{Workflow: "reviewed", CheckerModel: "haiku", Iterations: 8, MaxTurns: 30},
+		},
+		{
+			name:    "no front matter",
+			content: "# Spec\n\norbital: {model: opus}\n",
+		},
+		{
+			name:    "front matter without orbital key",
+			content: "---\ntitle: x\n---\n",
+		},
+		{
+			name:    "unterminated front matter",
+			content: "---\norbital: {model: opus}\n# Spec\n",
+		},
+		{
+			name:    "crlf and yaml end marker",
+			content: "---\r\norbital: {model: 'opus'}\r\n...\r\n",
+			want:    Defaults{Model: "opus"},
+		},
+		{
+			name:    "unknown key",
+			content: "---\norbital: {modle: opus}\n---\n",
+			wantErr: "unknown key orbital.modle",
+		},
+		{
+			name:    "bad budget",
+			content: "---\norbital: {budget: lots}\n---\n",
+			wantErr: `invalid orbital.budget "lots"`,
+		},
+		{
+			name:    "negative budget",
+			content: "---\norbital: {budget: -1}\n---\n",
+			wantErr: "must be positive",
+		},
+		{
+			name:    "scalar value",
+			content: "---\norbital: tdd\n---\n",
+			wantErr: "orbital must be a mapping",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDefaults(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseDefaults() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDefaults() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.md")
+	if err := os.WriteFile(path, []byte("---\norbital: {iterations: 0}\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDefaults(path); err == nil || !strings.Contains(err.Error(), "invalid front matter in "+path) {
+		t.Errorf("LoadDefaults() error = %v, want invalid front matter", err)
+	}
+
+	if _, err := LoadDefaults(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("LoadDefaults() on a missing file returned no error")
+	}
+}
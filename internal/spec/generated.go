@@ -0,0 +1,76 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StdinPath is the spec file argument that reads the spec from standard input.
+const StdinPath = "-"
+
+// maxSlugLength caps the length of generated spec file names.
+const maxSlugLength = 40
+
+// TaskContent builds spec content for an inline task: a heading and a single
+// checkbox that the agent ticks off when the task is done.
+func TaskContent(task string) string {
+	task = strings.TrimSpace(task)
+	return fmt.Sprintf("# Task\n\n- [ ] %s\n", task)
+}
+
+// ReadStdin reads spec content from r, returning an error if it is empty.
+func ReadStdin(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read spec from stdin: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("spec from stdin is empty")
+	}
+	return string(data), nil
+}
+
+// WriteGenerated writes generated spec content to a new file in dir and
+// returns its path. The file name is derived from name and the current time
+// so repeated runs do not collide.
+func WriteGenerated(dir, name, content string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create spec directory: %w", err)
+	}
+
+	slug := slugify(name)
+	if slug == "" {
+		slug = "task"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.md", time.Now().Format("20060102-150405"), slug))
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write generated spec: %w", err)
+	}
+
+	return path, nil
+}
+
+// slugify converts s to a lowercase, hyphen-separated file name slug.
+func slugify(s string) string {
+	var b strings.Builder
+	lastHyphen := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			lastHyphen = false
+		case !lastHyphen:
+			b.WriteRune('-')
+			lastHyphen = true
+		}
+		if b.Len() >= maxSlugLength {
+			break
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
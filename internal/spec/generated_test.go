@@ -0,0 +1,77 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTaskContent(t *testing.T) {
+	got := TaskContent("  fix the flaky TestFoo \n")
+	want := "# Task\n\n- [ ] fix the flaky TestFoo\n"
+	if got != want {
+		t.Errorf("TaskContent() = %q, want %q", got, want)
+	}
+}
+
+func TestReadStdin(t *testing.T) {
+	got, err := ReadStdin(strings.NewReader("# Spec\n- [ ] item\n"))
+	if err != nil {
+		t.Fatalf("ReadStdin() error = %v", err)
+	}
+	if got != "# Spec\n- [ ] item\n" {
+		t.Errorf("ReadStdin() = %q", got)
+	}
+
+	if _, err := ReadStdin(strings.NewReader("  \n")); err == nil {
+		t.Error("ReadStdin() error = nil for empty input, want error")
+	}
+}
+
+func TestWriteGenerated(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "specs")
+
+	path, err := WriteGenerated(dir, "Fix the flaky TestFoo!", "content")
+	if err != nil {
+		t.Fatalf("WriteGenerated() error = %v", err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("path %q not in %q", path, dir)
+	}
+	if !strings.HasSuffix(path, "-fix-the-flaky-testfoo.md") {
+		t.Errorf("path = %q, want slugged name", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read generated spec: %v", err)
+	}
+	if string(data) != "content" {
+		t.Errorf("content = %q, want %q", string(data), "content")
+	}
+
+	// The generated file must pass spec validation
+	if _, err := Validate([]string{path}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"stdin", "stdin"},
+		{"Fix the flaky TestFoo", "fix-the-flaky-testfoo"},
+		{"--weird__input--", "weird-input"},
+		{"!!!", ""},
+		{strings.Repeat("a", 60), strings.Repeat("a", maxSlugLength)},
+	}
+
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LeanFeedbackLimit is the maximum number of characters of feedback passed
+// into a lean prompt. Longer feedback keeps its end, where verdicts and
+// failing checks are usually reported.
+const LeanFeedbackLimit = 2000
+
+// LeanPrompt replaces the recent notes in step prompts after a failed
+// verification or gate when --lean-prompts is set. The verbs are replaced
+// with the unchecked items and the feedback from the failed check.
+const LeanPrompt = `
+
+---
+REMAINING WORK: The last check failed. Work only on what is left below; do
+not re-read the whole spec or the notes file for context.
+
+Unchecked items:
+%s
+
+Feedback from the failed check:
+%s
+---
+`
+
+// uncheckedPattern matches a markdown task list item that is not ticked.
+var uncheckedPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[ \]\s+(.+?)\s*$`)
+
+// UncheckedItems returns the text of every unticked "- [ ]" item in the
+// files, in file order.
+func UncheckedItems(paths []string) ([]string, error) {
+	var items []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spec file: %w", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if m := uncheckedPattern.FindStringSubmatch(line); m != nil {
+				items = append(items, m[1])
+			}
+		}
+	}
+	return items, nil
+}
+
+// BuildLeanPrompt returns the lean context for the next prompt: only the
+// unchecked items and the feedback from the check that failed.
+func BuildLeanPrompt(items []string, feedback string) string {
+	list := "(none found; see the feedback)"
+	if len(items) > 0 {
+		lines := make([]string, len(items))
+		for i, item := range items {
+			lines[i] = "- [ ] " + item
+		}
+		list = strings.Join(lines, "\n")
+	}
+
+	feedback = strings.TrimSpace(feedback)
+	if runes := []rune(feedback); len(runes) > LeanFeedbackLimit {
+		feedback = "…" + strings.TrimSpace(string(runes[len(runes)-LeanFeedbackLimit:]))
+	}
+	if feedback == "" {
+		feedback = "(none)"
+	}
+
+	return fmt.Sprintf(LeanPrompt, list, feedback)
+}
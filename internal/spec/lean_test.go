@@ -0,0 +1,70 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUncheckedItems(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "spec.md")
+	second := filepath.Join(dir, "more.md")
+	content := "# Spec\n\n- [x] Parse config\n- [ ] Wire the loop  \n  * [ ] Nested item\n1. [ ] Numbered item\n- [] not a checkbox\n"
+	if err := os.WriteFile(first, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("- [X] Done\n+ [ ] Write docs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := UncheckedItems([]string{first, second})
+	if err != nil {
+		t.Fatalf("UncheckedItems() error = %v", err)
+	}
+	want := []string{"Wire the loop", "Nested item", "Numbered item", "Write docs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UncheckedItems() = %q, want %q", got, want)
+	}
+
+	if _, err := UncheckedItems([]string{filepath.Join(dir, "missing.md")}); err == nil {
+		t.Error("UncheckedItems() with a missing file succeeded, want error")
+	}
+}
+
+func TestBuildLeanPrompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []string
+		feedback string
+		want     []string
+	}{
+		{
+			name:     "items and feedback",
+			items:    []string{"Wire the loop", "Write docs"},
+			feedback: "  2 unchecked item(s) remain\n",
+			want:     []string{"REMAINING WORK", "- [ ] Wire the loop\n- [ ] Write docs", "Feedback from the failed check:\n2 unchecked item(s) remain\n"},
+		},
+		{
+			name: "nothing known",
+			want: []string{"(none found; see the feedback)", "Feedback from the failed check:\n(none)"},
+		},
+		{
+			name:     "long feedback keeps the end",
+			feedback: strings.Repeat("x", LeanFeedbackLimit) + "VERDICT: FAIL",
+			want:     []string{"…", "VERDICT: FAIL"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildLeanPrompt(tt.items, tt.feedback)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("BuildLeanPrompt() missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -107,7 +107,9 @@ This technique uses autonomous loops to drive you toward completion through pers
 
 Maintain notes in: ` + "`{{notes_file}}`" + `
 
-This file captures observations, blockers, and decisions across iterations. Create it if it does not exist. Do not track task status here; that belongs in the spec/stories file.
+This file captures observations, blockers, and decisions across iterations. Do not track task status here; that belongs in the spec/stories file.
+
+Orbital adds a ` + "`## Iteration N — step — time`" + ` heading before each step and includes the most recent sections in your prompt. Append your notes under the last heading and do not edit or add these headings yourself.
 
 Use it for:
 - Blockers or issues you encountered
@@ -119,7 +121,7 @@ Use it for:
 Each iteration:
 
 1. Read the spec/stories file. Find the next pending item (first ` + "`[ ]`" + ` checkbox).
-2. Read ` + "`{{notes_file}}`" + ` for context from previous iterations.
+2. Review the recent notes in your prompt; read ` + "`{{notes_file}}`" + ` only if you need older context.
 3. Plan and implement that single item.
 4. Run verification: tests, lint, typecheck, build.
 5. If verification passes, mark the item ` + "`[x]`" + ` in the spec/stories file.
@@ -141,6 +143,16 @@ Types: ` + "`feat`" + `, ` + "`fix`" + `, ` + "`refactor`" + `, ` + "`test`" + `
 
 Stage your changes, the spec/stories file, and ` + "`{{notes_file}}`" + `. Do not stage unrelated files.
 
+## Checkpoints
+
+When you reach a milestone worth returning to (a schema migration applied, the test suite green again), declare it on its own line:
+
+` + "```" + `
+<checkpoint>tests green</checkpoint>
+` + "```" + `
+
+Orbital records the working tree at the end of the iteration under that name so the operator can resume or roll back to it.
+
 ## Stop Condition
 
 Do not output the completion promise until:
@@ -223,6 +235,12 @@ func (s *Spec) BuildPrompt() string {
 // BuildSystemPrompt generates the system prompt to append via --append-system-prompt.
 // Uses SystemPromptTemplate if set, otherwise uses default system prompt.
 func BuildSystemPrompt() string {
+	return BuildSystemPromptFor(CompletionPromise, NotesFile)
+}
+
+// BuildSystemPromptFor is BuildSystemPrompt with the promise and notes file
+// given rather than read from CompletionPromise and NotesFile.
+func BuildSystemPromptFor(promise, notesFile string) string {
 	template := SystemPromptTemplate
 	if template == "" {
 		template = DefaultSystemPrompt
@@ -230,11 +248,11 @@ func BuildSystemPrompt() string {
 
 	// Replace placeholders
 	result := template
-	if CompletionPromise != "" {
-		result = strings.ReplaceAll(result, "{{promise}}", CompletionPromise)
+	if promise != "" {
+		result = strings.ReplaceAll(result, "{{promise}}", promise)
 	}
-	if NotesFile != "" {
-		result = strings.ReplaceAll(result, "{{notes_file}}", NotesFile)
+	if notesFile != "" {
+		result = strings.ReplaceAll(result, "{{notes_file}}", notesFile)
 	}
 
 	return result
@@ -244,11 +262,17 @@ func BuildSystemPrompt() string {
 // Takes a list of spec file paths and returns a prompt instructing Claude
 // to count checkboxes and report completion status.
 func BuildVerificationPrompt(files []string) string {
+	return BuildCustomVerificationPrompt(VerificationPrompt, files)
+}
+
+// BuildCustomVerificationPrompt fills template's {{files}} placeholder with
+// the list of files to verify, one "- path" line each.
+func BuildCustomVerificationPrompt(template string, files []string) string {
 	var fileList strings.Builder
 	for _, path := range files {
 		fileList.WriteString("- ")
 		fileList.WriteString(path)
 		fileList.WriteString("\n")
 	}
-	return strings.ReplaceAll(VerificationPrompt, "{{files}}", strings.TrimSuffix(fileList.String(), "\n"))
+	return strings.ReplaceAll(template, "{{files}}", strings.TrimSuffix(fileList.String(), "\n"))
 }
@@ -188,6 +188,17 @@ func TestBuildSystemPrompt_SubstitutesNotesFile(t *testing.T) {
 	}
 }
 
+func TestBuildSystemPromptFor_IgnoresGlobals(t *testing.T) {
+	systemPrompt := BuildSystemPromptFor("<test>DONE</test>", "custom/notes.md")
+
+	if !strings.Contains(systemPrompt, "<test>DONE</test>") || !strings.Contains(systemPrompt, "custom/notes.md") {
+		t.Error("BuildSystemPromptFor() should contain the given promise and notes file")
+	}
+	if strings.Contains(systemPrompt, NotesFile) {
+		t.Error("BuildSystemPromptFor() should not use the NotesFile global")
+	}
+}
+
 func TestSpec_StructFields(t *testing.T) {
 	spec := Spec{
 		FilePaths: []string{"/path/to/file.md"},
@@ -247,6 +258,14 @@ func TestBuildVerificationPrompt_ContainsCheckboxInstructions(t *testing.T) {
 	}
 }
 
+func TestBuildCustomVerificationPrompt(t *testing.T) {
+	got := BuildCustomVerificationPrompt("Check these plans:\n{{files}}\nReply DONE or TODO.", []string{"a.md", "b.md"})
+	want := "Check these plans:\n- a.md\n- b.md\nReply DONE or TODO."
+	if got != want {
+		t.Errorf("BuildCustomVerificationPrompt() = %q, want %q", got, want)
+	}
+}
+
 func TestVerificationPrompt_ContainsRequiredElements(t *testing.T) {
 	// Test the constant directly
 	if !strings.Contains(VerificationPrompt, "{{files}}") {
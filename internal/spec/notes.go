@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultNotesWindow is the number of recent notes sections passed into
+// step prompts.
+const DefaultNotesWindow = 3
+
+// notesSectionPrefix starts every section heading orbital writes.
+const notesSectionPrefix = "## Iteration "
+
+// Notes manages the structure of the notes file. Orbital writes a section
+// heading before each step so the model only fills in the body, and recent
+// sections are passed into the prompt instead of relying on the model to
+// re-read the whole file.
+type Notes struct {
+	// Path is the notes file.
+	Path string
+	// Window is the number of recent sections returned by Recent.
+	Window int
+
+	// openHeading is the last heading written, used to drop it again if the
+	// step left it empty.
+	openHeading string
+}
+
+// NewNotes creates a notes manager for path with the default window.
+func NewNotes(path string) *Notes {
+	return &Notes{Path: path, Window: DefaultNotesWindow}
+}
+
+// FormatNotesHeading returns the section heading for a step.
+func FormatNotesHeading(iteration int, step string, at time.Time) string {
+	return fmt.Sprintf("%s%d — %s — %s", notesSectionPrefix, iteration, step, at.Format("2006-01-02 15:04"))
+}
+
+// BeginSection appends the heading for a new step section. If the previous
+// section orbital wrote is still empty, it is replaced rather than kept, so
+// steps that leave no notes (such as gates) do not clutter the file.
+func (n *Notes) BeginSection(iteration int, step string, at time.Time) error {
+	heading := FormatNotesHeading(iteration, step, at)
+	if err := n.appendSection(heading, ""); err != nil {
+		return err
+	}
+	n.openHeading = heading
+	return nil
+}
+
+// AddSection appends a section with a body orbital wrote itself, such as a
+// notice that the spec changed. It is titled like a step section, so Recent
+// passes it into the following prompts.
+func (n *Notes) AddSection(iteration int, title, body string, at time.Time) error {
+	if err := n.appendSection(FormatNotesHeading(iteration, title, at), body); err != nil {
+		return err
+	}
+	n.openHeading = ""
+	return nil
+}
+
+// appendSection appends heading and body, first dropping the previous
+// heading orbital wrote if its section is still empty.
+func (n *Notes) appendSection(heading, body string) error {
+	data, err := os.ReadFile(n.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read notes file: %w", err)
+	}
+	content := strings.TrimRight(string(data), "\n")
+	if n.openHeading != "" && strings.HasSuffix(content, n.openHeading) {
+		content = strings.TrimRight(strings.TrimSuffix(content, n.openHeading), "\n")
+	}
+
+	if content != "" {
+		content += "\n\n"
+	}
+	content += heading + "\n"
+	if body = strings.TrimSpace(body); body != "" {
+		content += body + "\n"
+	}
+
+	if err := os.WriteFile(n.Path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write notes file: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the last Window sections that have content, oldest first.
+// Returns an empty string if the file is missing or has no such sections.
+func (n *Notes) Recent() (string, error) {
+	data, err := os.ReadFile(n.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes file: %w", err)
+	}
+
+	var filled []string
+	for _, section := range splitNotesSections(string(data)) {
+		_, body, _ := strings.Cut(section, "\n")
+		if strings.TrimSpace(body) != "" {
+			filled = append(filled, section)
+		}
+	}
+	if n.Window > 0 && len(filled) > n.Window {
+		filled = filled[len(filled)-n.Window:]
+	}
+	return strings.Join(filled, "\n\n"), nil
+}
+
+// splitNotesSections splits notes content into the sections orbital wrote,
+// dropping any preamble before the first heading. Sections are trimmed.
+func splitNotesSections(content string) []string {
+	var sections []string
+	var current []string
+	inSection := false
+	flush := func() {
+		if inSection {
+			sections = append(sections, strings.TrimSpace(strings.Join(current, "\n")))
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, notesSectionPrefix) {
+			flush()
+			inSection = true
+		}
+		if inSection {
+			current = append(current, line)
+		}
+	}
+	flush()
+	return sections
+}
@@ -0,0 +1,155 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotes_BeginSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(path, []byte("# Notes\n\nSpec: spec.md\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	n := NewNotes(path)
+
+	if err := n.BeginSection(1, "implement", at); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+	appendNotes(t, path, "Chose the parser task.\n")
+	// The gate leaves its section empty, so it is replaced by the next one
+	if err := n.BeginSection(1, "review", at); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+	if err := n.BeginSection(2, "implement", at); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Notes\n\nSpec: spec.md\n\n" +
+		"## Iteration 1 — implement — 2026-01-02 10:30\nChose the parser task.\n\n" +
+		"## Iteration 2 — implement — 2026-01-02 10:30\n"
+	if string(data) != want {
+		t.Errorf("notes = %q, want %q", data, want)
+	}
+}
+
+func TestNotes_BeginSection_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	if err := NewNotes(path).BeginSection(1, "implement", time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "## Iteration 1 — implement — 2026-01-02 10:30\n" {
+		t.Errorf("notes = %q", data)
+	}
+}
+
+func TestNotes_AddSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.md")
+	at := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	n := NewNotes(path)
+
+	if err := n.BeginSection(1, "review", at); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+	// The empty gate section is dropped; the added section is kept even when
+	// the next step's section is opened after it
+	if err := n.AddSection(2, "spec changed", "spec.md was edited.\n", at); err != nil {
+		t.Fatalf("AddSection() error = %v", err)
+	}
+	if err := n.BeginSection(2, "implement", at); err != nil {
+		t.Fatalf("BeginSection() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Iteration 2 — spec changed — 2026-01-02 10:30\nspec.md was edited.\n\n" +
+		"## Iteration 2 — implement — 2026-01-02 10:30\n"
+	if string(data) != want {
+		t.Errorf("notes = %q, want %q", data, want)
+	}
+	if recent, _ := n.Recent(); !strings.Contains(recent, "spec.md was edited.") {
+		t.Errorf("Recent() = %q, want the added section", recent)
+	}
+}
+
+func TestNotes_Recent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		window  int
+		want    string
+	}{
+		{
+			name:    "only headings",
+			content: "# Notes\n\n## Iteration 1 — implement — t\n",
+			window:  3,
+			want:    "",
+		},
+		{
+			name: "window keeps latest filled sections",
+			content: "# Notes\n\n" +
+				"## Iteration 1 — implement — t\none\n\n" +
+				"## Iteration 2 — implement — t\ntwo\n\n" +
+				"## Iteration 3 — implement — t\nthree\n\n" +
+				"## Iteration 4 — implement — t\n",
+			window: 2,
+			want:   "## Iteration 2 — implement — t\ntwo\n\n## Iteration 3 — implement — t\nthree",
+		},
+		{
+			name:    "zero window keeps everything",
+			content: "## Iteration 1 — a — t\none\n## Iteration 2 — b — t\ntwo\n",
+			window:  0,
+			want:    "## Iteration 1 — a — t\none\n\n## Iteration 2 — b — t\ntwo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "notes.md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			n := NewNotes(path)
+			n.Window = tt.window
+			got, err := n.Recent()
+			if err != nil {
+				t.Fatalf("Recent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Recent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotes_Recent_MissingFile(t *testing.T) {
+	got, err := NewNotes(filepath.Join(t.TempDir(), "missing.md")).Recent()
+	if err != nil || got != "" {
+		t.Errorf("Recent() = %q, %v, want empty and no error", got, err)
+	}
+}
+
+func appendNotes(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
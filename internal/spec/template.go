@@ -0,0 +1,125 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultSpecDir is where new specs are created, relative to the working directory.
+const DefaultSpecDir = "docs/plans"
+
+// DefaultSpecTemplate is the template for new specs. Placeholders:
+// {{title}}, {{date}}, {{description}}, and {{criteria}} (checkbox lines).
+const DefaultSpecTemplate = `# {{title}}
+
+Created: {{date}}
+
+## Context
+
+{{description}}
+
+## Acceptance Criteria
+
+{{criteria}}
+
+## Out of Scope
+
+- Anything deliberately excluded from this work
+`
+
+// placeholderCriterion is used when no acceptance criteria are supplied.
+const placeholderCriterion = "Describe the first acceptance criterion"
+
+// CriteriaPrompt asks a model to draft acceptance criteria for a new spec.
+const CriteriaPrompt = `Draft acceptance criteria for the following piece of work:
+
+{{title}}: {{description}}
+
+Write between 3 and 8 criteria. Each must be specific and independently verifiable.
+Respond with ONLY the criteria, one per line, each starting with "- [ ] ".
+Do not read or modify any files.`
+
+// NewSpecContent renders a spec template. An empty template uses
+// DefaultSpecTemplate; with no criteria a single placeholder checkbox is added.
+func NewSpecContent(template, title, description string, criteria []string, now time.Time) string {
+	if template == "" {
+		template = DefaultSpecTemplate
+	}
+	if len(criteria) == 0 {
+		criteria = []string{placeholderCriterion}
+	}
+
+	var lines []string
+	for _, c := range criteria {
+		lines = append(lines, "- [ ] "+c)
+	}
+
+	r := strings.NewReplacer(
+		"{{title}}", title,
+		"{{date}}", now.Format("2006-01-02"),
+		"{{description}}", description,
+		"{{criteria}}", strings.Join(lines, "\n"),
+	)
+	return r.Replace(template)
+}
+
+// NewSpecPath returns the path for a new spec: <dir>/<date>-<slug>.md.
+func NewSpecPath(dir, title string, now time.Time) string {
+	slug := slugify(title)
+	if slug == "" {
+		slug = "spec"
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.md", now.Format("2006-01-02"), slug))
+}
+
+// WriteNewSpec writes content to path, creating parent directories.
+// It refuses to overwrite an existing file.
+func WriteNewSpec(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create spec directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("spec already exists: %s", path)
+		}
+		return fmt.Errorf("failed to create spec: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	return nil
+}
+
+// BuildCriteriaPrompt generates the prompt asking a model to draft acceptance criteria.
+func BuildCriteriaPrompt(title, description string) string {
+	r := strings.NewReplacer(
+		"{{title}}", title,
+		"{{description}}", description,
+	)
+	return r.Replace(CriteriaPrompt)
+}
+
+// ParseCriteria extracts acceptance criteria from a model response. Lines
+// written as checkboxes or bullets are accepted; anything else is ignored.
+func ParseCriteria(response string) []string {
+	var criteria []string
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"- [ ] ", "- [x] ", "- [X] ", "* [ ] ", "- ", "* "} {
+			if strings.HasPrefix(line, prefix) {
+				if c := strings.TrimSpace(strings.TrimPrefix(line, prefix)); c != "" {
+					criteria = append(criteria, c)
+				}
+				break
+			}
+		}
+	}
+	return criteria
+}
@@ -0,0 +1,88 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSpecContent_DefaultTemplate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	content := NewSpecContent("", "User auth", "Let users log in", []string{"Login form", "Logout button"}, now)
+
+	for _, want := range []string{
+		"# User auth",
+		"2026-03-01",
+		"## Context\n\nLet users log in",
+		"## Acceptance Criteria\n\n- [ ] Login form\n- [ ] Logout button",
+		"## Out of Scope",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestNewSpecContent_PlaceholderCriterion(t *testing.T) {
+	content := NewSpecContent("{{criteria}}", "x", "", nil, time.Now())
+	if content != "- [ ] "+placeholderCriterion {
+		t.Errorf("content = %q, want a single placeholder checkbox", content)
+	}
+}
+
+func TestNewSpecPath(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"user auth", filepath.Join("docs/plans", "2026-03-01-user-auth.md")},
+		{"!!!", filepath.Join("docs/plans", "2026-03-01-spec.md")},
+	}
+	for _, tt := range tests {
+		if got := NewSpecPath("docs/plans", tt.title, now); got != tt.want {
+			t.Errorf("NewSpecPath(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestWriteNewSpec_RefusesOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans", "spec.md")
+
+	if err := WriteNewSpec(path, "first"); err != nil {
+		t.Fatalf("WriteNewSpec() error = %v", err)
+	}
+	if err := WriteNewSpec(path, "second"); err == nil {
+		t.Error("WriteNewSpec() error = nil, want error for existing file")
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "first" {
+		t.Errorf("file = %q, want original content kept", data)
+	}
+}
+
+func TestParseCriteria(t *testing.T) {
+	response := `Here are the criteria:
+- [ ] Users can log in with email
+- [x] Sessions expire after 24h
+* Passwords are hashed
+-   
+Not a criterion`
+
+	got := ParseCriteria(response)
+	want := []string{"Users can log in with email", "Sessions expire after 24h", "Passwords are hashed"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("ParseCriteria() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildCriteriaPrompt(t *testing.T) {
+	prompt := BuildCriteriaPrompt("User auth", "Let users log in")
+	if !strings.Contains(prompt, "User auth: Let users log in") {
+		t.Errorf("prompt missing title and description:\n%s", prompt)
+	}
+}
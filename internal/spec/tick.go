@@ -0,0 +1,156 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// TickPrompt asks the checker model which unchecked spec items the working
+// tree already satisfies, so orbital can tick them itself.
+const TickPrompt = `The following items in the spec file(s) are not ticked yet:
+
+{{files}}
+
+Unchecked items:
+{{items}}
+
+Inspect the code and files in the working directory and decide, for each
+item, whether it is fully implemented. Do not change any files.
+
+Respond with EXACTLY one JSON object and nothing else, listing the done items
+with their text copied exactly as given above:
+{"done": ["<item text>", ...]}
+
+Use {"done": []} when none are done.`
+
+// tickBoxPattern matches an unticked task list item, capturing the marker
+// before the box and the item text.
+var tickBoxPattern = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+)\[ \]\s+(.+?)\s*$`)
+
+// BuildTickPrompt generates the prompt asking the checker model which of
+// items, the unchecked items of files, are done.
+func BuildTickPrompt(files, items []string) string {
+	fileLines := make([]string, len(files))
+	for i, path := range files {
+		fileLines[i] = "- " + path
+	}
+	itemLines := make([]string, len(items))
+	for i, item := range items {
+		itemLines[i] = "- [ ] " + item
+	}
+	r := strings.NewReplacer(
+		"{{files}}", strings.Join(fileLines, "\n"),
+		"{{items}}", strings.Join(itemLines, "\n"),
+	)
+	return r.Replace(TickPrompt)
+}
+
+// ParseTickResponse extracts the done items from the checker model's reply to
+// a TickPrompt. Text around the JSON object is ignored; a reply without a
+// valid object yields no items.
+func ParseTickResponse(response string) []string {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return nil
+	}
+	var reply struct {
+		Done []string `json:"done"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &reply); err != nil {
+		return nil
+	}
+	var done []string
+	for _, item := range reply.Done {
+		if item = strings.TrimSpace(item); item != "" {
+			done = append(done, item)
+		}
+	}
+	return done
+}
+
+// TickItems ticks the unchecked "- [ ]" items in the files whose text matches
+// one of items, ignoring case and differences in whitespace. Each item ticks
+// at most one checkbox, the first unticked match in file order. It returns
+// the texts of the items ticked, as written in the spec.
+func TickItems(paths, items []string) ([]string, error) {
+	pending := make(map[string]int)
+	for _, item := range items {
+		if key := itemKey(item); key != "" {
+			pending[key]++
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	var ticked []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ticked, fmt.Errorf("failed to read spec file: %w", err)
+		}
+		lines := strings.Split(string(data), "\n")
+		changed := false
+		for i, line := range lines {
+			m := tickBoxPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			key := itemKey(m[2])
+			if pending[key] == 0 {
+				continue
+			}
+			pending[key]--
+			lines[i] = strings.Replace(line, m[1]+"[ ]", m[1]+"[x]", 1)
+			ticked = append(ticked, m[2])
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := writeAtomic(path, []byte(strings.Join(lines, "\n"))); err != nil {
+			return ticked, err
+		}
+	}
+	return ticked, nil
+}
+
+// itemKey normalises an item's text for matching: lower case with runs of
+// whitespace collapsed.
+func itemKey(item string) string {
+	return strings.ToLower(strings.Join(strings.Fields(item), " "))
+}
+
+// writeAtomic replaces path with data via a temporary file in the same
+// directory, keeping the file's permissions, so an interrupted write never
+// leaves a truncated spec.
+func writeAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write spec file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write spec file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write spec file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to write spec file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write spec file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildTickPrompt(t *testing.T) {
+	prompt := BuildTickPrompt([]string{"a.md", "b.md"}, []string{"Add login", "Add logout"})
+
+	for _, want := range []string{"- a.md\n- b.md", "- [ ] Add login\n- [ ] Add logout", `{"done": [`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "{{") {
+		t.Errorf("prompt has unreplaced placeholders:\n%s", prompt)
+	}
+}
+
+func TestParseTickResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []string
+	}{
+		{name: "plain object", response: `{"done": ["Add login", "Add logout"]}`, want: []string{"Add login", "Add logout"}},
+		{name: "surrounding text", response: "Checked the code.\n```json\n{\"done\": [\"Add login\"]}\n```", want: []string{"Add login"}},
+		{name: "blank items dropped", response: `{"done": [" ", " Add login "]}`, want: []string{"Add login"}},
+		{name: "none done", response: `{"done": []}`, want: nil},
+		{name: "no object", response: "VERIFIED: 0 unchecked, 3 checked", want: nil},
+		{name: "invalid json", response: `{"done": [Add login]}`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTickResponse(tt.response); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTickResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTickItems(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		items      []string
+		wantTicked []string
+		want       string
+	}{
+		{
+			name:       "ticks matching items",
+			spec:       "# Spec\n\n- [ ] Add login\n- [ ] Add logout\n",
+			items:      []string{"Add login"},
+			wantTicked: []string{"Add login"},
+			want:       "# Spec\n\n- [x] Add login\n- [ ] Add logout\n",
+		},
+		{
+			name:       "ignores case and whitespace",
+			spec:       "  * [ ] Add   the Login page\n1. [ ] Second\n",
+			items:      []string{"add the login page", "second"},
+			wantTicked: []string{"Add   the Login page", "Second"},
+			want:       "  * [x] Add   the Login page\n1. [x] Second\n",
+		},
+		{
+			name:       "each item ticks one box",
+			spec:       "- [ ] Write tests\n- [ ] Write tests\n",
+			items:      []string{"Write tests"},
+			wantTicked: []string{"Write tests"},
+			want:       "- [x] Write tests\n- [ ] Write tests\n",
+		},
+		{
+			name:       "unknown items leave the spec alone",
+			spec:       "- [x] Done\n- [ ] Open\n",
+			items:      []string{"Done", "Something else"},
+			wantTicked: nil,
+			want:       "- [x] Done\n- [ ] Open\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "spec.md")
+			if err := os.WriteFile(path, []byte(tt.spec), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			ticked, err := TickItems([]string{path}, tt.items)
+			if err != nil {
+				t.Fatalf("TickItems() error = %v", err)
+			}
+			if !reflect.DeepEqual(ticked, tt.wantTicked) {
+				t.Errorf("ticked = %q, want %q", ticked, tt.wantTicked)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("spec = %q, want %q", data, tt.want)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestTickItems_AcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	if err := os.WriteFile(a, []byte("- [ ] First\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("- [ ] Second\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ticked, err := TickItems([]string{a, b}, []string{"Second", "First"})
+	if err != nil {
+		t.Fatalf("TickItems() error = %v", err)
+	}
+	if want := []string{"First", "Second"}; !reflect.DeepEqual(ticked, want) {
+		t.Errorf("ticked = %q, want %q", ticked, want)
+	}
+}
+
+func TestTickItems_MissingFile(t *testing.T) {
+	if _, err := TickItems([]string{filepath.Join(t.TempDir(), "missing.md")}, []string{"x"}); err == nil {
+		t.Error("TickItems() on a missing file returned no error")
+	}
+}
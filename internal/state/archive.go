@@ -0,0 +1,128 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+// ArchiveDir returns the directory completed sessions' state is archived to.
+func ArchiveDir(workingDir string) string {
+	workingDir = strings.TrimSuffix(workingDir, "/")
+	return filepath.Join(workingDir, ".orbital", "archive")
+}
+
+// Archive moves the state directory (state, queue and generated specs) to
+// <ArchiveDir>/<session-id>/ instead of deleting it, copies the files in
+// extra alongside it, and removes all but the keep most recent archives.
+// Files in extra that do not exist are skipped. It returns the archive path.
+func (s *State) Archive(keep int, extra ...string) (string, error) {
+	archiveDir := ArchiveDir(s.WorkingDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	dest := filepath.Join(archiveDir, s.SessionID)
+	if err := os.RemoveAll(dest); err != nil {
+		return "", fmt.Errorf("failed to replace archive: %w", err)
+	}
+	if err := os.Rename(StateDir(s.WorkingDir), dest); err != nil {
+		return "", fmt.Errorf("failed to archive state directory: %w", err)
+	}
+	// The directory keeps its last modification time through the rename;
+	// retention goes by when the session was archived
+	now := time.Now()
+	_ = os.Chtimes(dest, now, now)
+
+	for _, path := range extra {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return dest, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		// Copied as is: encrypted files stay encrypted
+		if err := os.WriteFile(filepath.Join(dest, filepath.Base(path)), data, 0644); err != nil {
+			return dest, fmt.Errorf("failed to archive %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	if err := PruneArchives(s.WorkingDir, keep); err != nil {
+		return dest, err
+	}
+	return dest, nil
+}
+
+// PruneArchives removes all but the keep most recently archived sessions.
+func PruneArchives(workingDir string, keep int) error {
+	archives, err := listArchives(workingDir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(archives); i++ {
+		if err := os.RemoveAll(archives[i]); err != nil {
+			return fmt.Errorf("failed to remove old archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// LatestArchive loads the state of the most recently archived session. It
+// returns nil and no error when nothing has been archived.
+func LatestArchive(workingDir string) (*State, error) {
+	archives, err := listArchives(workingDir)
+	if err != nil || len(archives) == 0 {
+		return nil, err
+	}
+	data, err := encrypt.ReadFile(filepath.Join(archives[0], "state.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archived state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal archived state: %w", err)
+	}
+	return &s, nil
+}
+
+// listArchives returns the archive directories, most recent first.
+func listArchives(workingDir string) ([]string, error) {
+	dir := ArchiveDir(workingDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive directory: %w", err)
+	}
+
+	type archive struct {
+		path    string
+		modTime time.Time
+	}
+	var archives []archive
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, archive{path: filepath.Join(dir, e.Name()), modTime: info.ModTime()})
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].modTime.After(archives[j].modTime) })
+
+	paths := make([]string, len(archives))
+	for i, a := range archives {
+		paths[i] = a.path
+	}
+	return paths, nil
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveDir_ReturnsCorrectPath(t *testing.T) {
+	if got, want := ArchiveDir("/some/project/"), "/some/project/.orbital/archive"; got != want {
+		t.Errorf("ArchiveDir() = %q; want %q", got, want)
+	}
+}
+
+func TestState_Archive_MovesStateDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	state := NewState("session-123", tempDir, []string{"/path/spec.md"}, "", nil)
+	state.UpdateIteration(4, 1.25)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(StateDir(tempDir), "queue.json"), []byte(`{"files":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	eventLog := filepath.Join(tempDir, "session-123.events.jsonl")
+	if err := os.WriteFile(eventLog, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := state.Archive(10, eventLog, filepath.Join(tempDir, "missing.log"))
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	if want := filepath.Join(ArchiveDir(tempDir), "session-123"); path != want {
+		t.Errorf("Archive() = %q; want %q", path, want)
+	}
+	if _, err := os.Stat(StateDir(tempDir)); !os.IsNotExist(err) {
+		t.Error("state directory still exists after Archive()")
+	}
+	for _, name := range []string{"state.json", "queue.json", "session-123.events.jsonl"} {
+		if _, err := os.Stat(filepath.Join(path, name)); err != nil {
+			t.Errorf("%s not archived: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(eventLog); err != nil {
+		t.Error("event log was moved instead of copied")
+	}
+
+	latest, err := LatestArchive(tempDir)
+	if err != nil {
+		t.Fatalf("LatestArchive() error = %v", err)
+	}
+	if latest == nil || latest.SessionID != "session-123" || latest.TotalCost != 1.25 {
+		t.Errorf("LatestArchive() = %+v; want session-123 costing 1.25", latest)
+	}
+}
+
+func TestState_Archive_KeepsMostRecent(t *testing.T) {
+	tempDir := t.TempDir()
+	for i, id := range []string{"oldest", "middle"} {
+		dir := filepath.Join(ArchiveDir(tempDir), id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		at := time.Now().Add(time.Duration(i-2) * time.Hour)
+		if err := os.Chtimes(dir, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := NewState("newest", tempDir, nil, "", nil)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := state.Archive(2); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(ArchiveDir(tempDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if len(got) != 2 || got[0] != "middle" || got[1] != "newest" {
+		t.Errorf("archives = %v; want [middle newest]", got)
+	}
+}
+
+func TestLatestArchive_NoneArchived(t *testing.T) {
+	latest, err := LatestArchive(t.TempDir())
+	if err != nil || latest != nil {
+		t.Errorf("LatestArchive() = %v, %v; want nil, nil", latest, err)
+	}
+}
@@ -69,16 +69,45 @@ func (q *Queue) save() error {
 		return fmt.Errorf("failed to marshal queue: %w", err)
 	}
 
-	// Write to temp file and rename for atomicity
-	tempPath := queuePath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := writeFileAtomic(queuePath, data); err != nil {
 		return fmt.Errorf("failed to write queue file: %w", err)
 	}
 
-	if err := os.Rename(tempPath, queuePath); err != nil {
-		return fmt.Errorf("failed to rename queue file: %w", err)
-	}
+	return nil
+}
 
+// writeFileAtomic writes data to a temporary file next to path, flushes it
+// to disk and renames it over path, so readers see either the old or the
+// new content and never a partial write. Each writer uses its own temporary
+// file, so concurrent writers cannot interleave.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
 	return nil
 }
 
@@ -133,20 +162,63 @@ func (q *Queue) withLock(fn func() error) error {
 // Add adds a file to the queue with file locking for concurrent access protection.
 // Duplicates are silently ignored (returns nil, no error).
 func (q *Queue) Add(path string) error {
-	return q.withLock(func() error {
-		// Check for duplicates
-		for _, f := range q.QueuedFiles {
-			if f == path {
-				// Silently ignore duplicates
-				return nil
+	_, err := q.addAll([]string{path})
+	return err
+}
+
+// addAll adds files to the queue in a single locked update and returns the
+// ones that were not already queued.
+func (q *Queue) addAll(paths []string) ([]string, error) {
+	var added []string
+	err := q.withLock(func() error {
+		now := time.Now()
+		for _, path := range paths {
+			if q.Contains(path) {
+				continue
 			}
+			q.QueuedFiles = append(q.QueuedFiles, path)
+			q.AddedAt[path] = now
+			added = append(added, path)
+		}
+		if len(added) == 0 {
+			return nil
 		}
-
-		q.QueuedFiles = append(q.QueuedFiles, path)
-		q.AddedAt[path] = time.Now()
-
 		return q.save()
 	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// EnqueueFiles queues spec files for the session in stateDir, creating the
+// directory if needed. It is the way for other tools to hand work to a
+// running session: the queue file is locked for the update, so a file added
+// while the loop pops the queue is either taken by that pop or left for the
+// next one, never lost. Relative paths are resolved against the current
+// directory and files already queued are skipped. Returns the files added.
+func EnqueueFiles(stateDir string, files []string) ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", f, err)
+		}
+		paths = append(paths, abs)
+	}
+
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	q, err := LoadQueue(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	added, err := q.addAll(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue files: %w", err)
+	}
+	return added, nil
 }
 
 // Remove removes a file from the queue with file locking.
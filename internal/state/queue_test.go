@@ -1,7 +1,10 @@
 package state
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -423,3 +426,111 @@ func TestQueue_Pop_ReturnsErrorWhenSaveFails(t *testing.T) {
 		t.Error("Pop() should return error when save fails")
 	}
 }
+
+func TestEnqueueFiles_CreatesStateDirAndSkipsQueued(t *testing.T) {
+	stateDir := filepath.Join(t.TempDir(), ".orbital", "state")
+	t.Chdir(t.TempDir())
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := EnqueueFiles(stateDir, []string{"a.md", "/specs/b.md"})
+	if err != nil {
+		t.Fatalf("EnqueueFiles() error = %v", err)
+	}
+	want := []string{filepath.Join(cwd, "a.md"), "/specs/b.md"}
+	if len(added) != 2 || added[0] != want[0] || added[1] != want[1] {
+		t.Errorf("EnqueueFiles() added %v; want %v", added, want)
+	}
+
+	added, err = EnqueueFiles(stateDir, []string{"/specs/b.md", "/specs/c.md"})
+	if err != nil {
+		t.Fatalf("EnqueueFiles() error = %v", err)
+	}
+	if len(added) != 1 || added[0] != "/specs/c.md" {
+		t.Errorf("EnqueueFiles() added %v; want only the file not yet queued", added)
+	}
+
+	q, err := LoadQueue(stateDir)
+	if err != nil {
+		t.Fatalf("LoadQueue() error = %v", err)
+	}
+	if len(q.QueuedFiles) != 3 {
+		t.Errorf("QueuedFiles = %v; want 3 files", q.QueuedFiles)
+	}
+}
+
+func TestEnqueueFiles_RacingPopLosesNothing(t *testing.T) {
+	_, stateDir := testhelpers.StateDir(t)
+
+	const numFiles = 50
+	var wg sync.WaitGroup
+	errCh := make(chan error, numFiles)
+	for i := 0; i < numFiles; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			if _, err := EnqueueFiles(stateDir, []string{fmt.Sprintf("/specs/%d.md", idx)}); err != nil {
+				errCh <- err
+			}
+		}(i)
+	}
+
+	// Pop while the files are being queued, as the loop does between iterations
+	popped := make(map[string]bool)
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	pop := func() {
+		q, err := LoadQueue(stateDir)
+		if err != nil {
+			t.Fatalf("LoadQueue() error = %v", err)
+		}
+		files, err := q.Pop()
+		if err != nil {
+			t.Fatalf("Pop() error = %v", err)
+		}
+		for _, f := range files {
+			if popped[f] {
+				t.Errorf("%s popped twice", f)
+			}
+			popped[f] = true
+		}
+	}
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		pop()
+	}
+	close(errCh)
+	for err := range errCh {
+		t.Errorf("EnqueueFiles() error = %v", err)
+	}
+
+	if len(popped) != numFiles {
+		t.Errorf("popped %d files; want all %d", len(popped), numFiles)
+	}
+}
+
+func TestQueue_Save_LeavesNoTempFiles(t *testing.T) {
+	_, stateDir := testhelpers.StateDir(t)
+
+	if _, err := EnqueueFiles(stateDir, []string{"/specs/a.md"}); err != nil {
+		t.Fatalf("EnqueueFiles() error = %v", err)
+	}
+	entries, err := os.ReadDir(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file %s left in the state directory", e.Name())
+		}
+	}
+}
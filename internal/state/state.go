@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/environment"
 	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
@@ -39,8 +41,196 @@ type State struct {
 	NotesFile    string    `json:"notes_file,omitempty"`
 	ContextFiles []string  `json:"context_files,omitempty"`
 
+	// CPUTime and PeakRSS record the machine resources used by Claude
+	// processes so far: total CPU time and the highest resident memory in bytes.
+	CPUTime time.Duration `json:"cpu_time,omitempty"`
+	PeakRSS int64         `json:"peak_rss,omitempty"`
+
+	// ModelHistory records which model served each iteration.
+	ModelHistory []IterationModel `json:"model_history,omitempty"`
+
+	// ModeSwitches records when the session changed mode, such as leaving
+	// the --burst phase for the main model.
+	ModeSwitches []ModeSwitch `json:"mode_switches,omitempty"`
+
+	// Snapshots records the working tree snapshot taken after each iteration.
+	// Iteration 0 is the tree before the first iteration ran.
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
+
+	// Checkpoints records named milestones declared by Claude or the user,
+	// each pointing at the snapshot of the iteration it was declared in.
+	Checkpoints []Checkpoint `json:"checkpoints,omitempty"`
+
 	// Workflow captures the workflow configuration and progress.
 	Workflow *WorkflowState `json:"workflow,omitempty"`
+
+	// Unconstrained is true if Claude ran with --dangerously-skip-permissions
+	// at any point in the session.
+	Unconstrained bool `json:"unconstrained,omitempty"`
+
+	// TrackingIssue is the GitHub issue the task list is mirrored to, once
+	// orbital has opened it.
+	TrackingIssue int `json:"tracking_issue,omitempty"`
+
+	// OfflineSince is when the session paused because the network went
+	// down. It is cleared once the session resumes.
+	OfflineSince *time.Time `json:"offline_since,omitempty"`
+
+	// Environment is the environment the session started in.
+	Environment *environment.Snapshot `json:"environment,omitempty"`
+}
+
+// IterationModel records the model that served an iteration.
+type IterationModel struct {
+	Iteration int    `json:"iteration"`
+	Model     string `json:"model"`
+}
+
+// Session modes recorded in ModeSwitches.
+const (
+	// ModeBurst runs quick exploratory iterations on a cheap model.
+	ModeBurst = "burst"
+	// ModeMain runs iterations on the session's model.
+	ModeMain = "main"
+)
+
+// ModeSwitch records the iteration from which the session ran in Mode on
+// Model.
+type ModeSwitch struct {
+	Iteration int       `json:"iteration"`
+	Mode      string    `json:"mode"`
+	Model     string    `json:"model"`
+	At        time.Time `json:"at"`
+}
+
+// Snapshot records a git snapshot of the working tree after an iteration.
+type Snapshot struct {
+	Iteration int       `json:"iteration"`
+	Commit    string    `json:"commit"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Checkpoint is a named milestone such as "tests green", recorded with the
+// snapshot of the iteration it was declared in so the session can be resumed
+// or rolled back to it by name. Commit is empty without version control.
+type Checkpoint struct {
+	Name      string    `json:"name"`
+	Iteration int       `json:"iteration"`
+	Commit    string    `json:"commit,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecordSnapshot records the snapshot taken after the given iteration,
+// replacing any earlier entry for the same iteration.
+func (s *State) RecordSnapshot(iteration int, commit string) {
+	snap := Snapshot{Iteration: iteration, Commit: commit, CreatedAt: time.Now()}
+	for i := range s.Snapshots {
+		if s.Snapshots[i].Iteration == iteration {
+			s.Snapshots[i] = snap
+			return
+		}
+	}
+	s.Snapshots = append(s.Snapshots, snap)
+}
+
+// FindSnapshot returns the snapshot taken after the given iteration.
+func (s *State) FindSnapshot(iteration int) (Snapshot, bool) {
+	for _, snap := range s.Snapshots {
+		if snap.Iteration == iteration {
+			return snap, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// RecordCheckpoint names the given iteration, pointing at its snapshot if one
+// was recorded. A checkpoint with the same name is replaced.
+func (s *State) RecordCheckpoint(name string, iteration int) Checkpoint {
+	cp := Checkpoint{Name: name, Iteration: iteration, CreatedAt: time.Now()}
+	if snap, ok := s.FindSnapshot(iteration); ok {
+		cp.Commit = snap.Commit
+	}
+	for i := range s.Checkpoints {
+		if s.Checkpoints[i].Name == name {
+			s.Checkpoints[i] = cp
+			return cp
+		}
+	}
+	s.Checkpoints = append(s.Checkpoints, cp)
+	return cp
+}
+
+// FindCheckpoint returns the checkpoint with the given name.
+func (s *State) FindCheckpoint(name string) (Checkpoint, bool) {
+	for _, cp := range s.Checkpoints {
+		if cp.Name == name {
+			return cp, true
+		}
+	}
+	return Checkpoint{}, false
+}
+
+// RollbackTo trims the state back to the end of the given iteration.
+// Snapshots, checkpoints, model history and mode switches for later iterations are dropped and the
+// workflow restarts from its first step. Total cost is kept because the
+// money was spent regardless of the rollback.
+func (s *State) RollbackTo(iteration int) {
+	s.Iteration = iteration
+
+	snapshots := s.Snapshots[:0]
+	for _, snap := range s.Snapshots {
+		if snap.Iteration <= iteration {
+			snapshots = append(snapshots, snap)
+		}
+	}
+	s.Snapshots = snapshots
+
+	checkpoints := s.Checkpoints[:0]
+	for _, cp := range s.Checkpoints {
+		if cp.Iteration <= iteration {
+			checkpoints = append(checkpoints, cp)
+		}
+	}
+	s.Checkpoints = checkpoints
+
+	history := s.ModelHistory[:0]
+	for _, m := range s.ModelHistory {
+		if m.Iteration <= iteration {
+			history = append(history, m)
+		}
+	}
+	s.ModelHistory = history
+
+	switches := s.ModeSwitches[:0]
+	for _, m := range s.ModeSwitches {
+		if m.Iteration <= iteration {
+			switches = append(switches, m)
+		}
+	}
+	s.ModeSwitches = switches
+
+	if s.Workflow != nil {
+		s.Workflow.CurrentStepIndex = 0
+		s.Workflow.GateRetries = make(map[string]int)
+	}
+}
+
+// RecordModel records the model that served the given iteration,
+// replacing any earlier entry for the same iteration.
+func (s *State) RecordModel(iteration int, model string) {
+	for i := range s.ModelHistory {
+		if s.ModelHistory[i].Iteration == iteration {
+			s.ModelHistory[i].Model = model
+			return
+		}
+	}
+	s.ModelHistory = append(s.ModelHistory, IterationModel{Iteration: iteration, Model: model})
+}
+
+// RecordModeSwitch records that the session runs in mode on model from the
+// given iteration on.
+func (s *State) RecordModeSwitch(iteration int, mode, model string) {
+	s.ModeSwitches = append(s.ModeSwitches, ModeSwitch{Iteration: iteration, Mode: mode, Model: model, At: time.Now()})
 }
 
 // StateDir returns the path to the state directory for the given working directory.
@@ -82,7 +272,7 @@ func (s *State) Save() error {
 
 	// Write to temp file and rename for atomicity
 	tempPath := statePath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := encrypt.WriteFile(tempPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
 
@@ -98,7 +288,7 @@ func Load(workingDir string) (*State, error) {
 	stateDir := StateDir(workingDir)
 	statePath := filepath.Join(stateDir, "state.json")
 
-	data, err := os.ReadFile(statePath)
+	data, err := encrypt.ReadFile(statePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
@@ -140,6 +330,12 @@ func (s *State) UpdateIteration(iteration int, cost float64) {
 	s.TotalCost = cost
 }
 
+// RecordResources stores the session's resource usage so far.
+func (s *State) RecordResources(cpu time.Duration, peakRSS int64) {
+	s.CPUTime = cpu
+	s.PeakRSS = peakRSS
+}
+
 // SetWorkflow initialises the workflow state from a workflow configuration.
 func (s *State) SetWorkflow(w *workflow.Workflow) {
 	s.Workflow = &WorkflowState{
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
 	"github.com/flashingpumpkin/orbital/internal/workflow"
 )
 
@@ -105,6 +106,37 @@ func TestState_SaveAndLoad_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestState_SaveAndLoad_Encrypted(t *testing.T) {
+	tempDir := t.TempDir()
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	state := NewState("session-123", tempDir, []string{"/path/to/spec.md"}, "", nil)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(StateDir(tempDir), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypt.IsEncrypted(raw) {
+		t.Errorf("state.json stored in plaintext: %q", raw)
+	}
+
+	loaded, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.SessionID != "session-123" {
+		t.Errorf("SessionID = %q; want %q", loaded.SessionID, "session-123")
+	}
+}
+
 func TestLoad_ReturnsErrorWhenNoStateFile(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -176,6 +208,141 @@ func TestState_UpdateIteration_UpdatesFields(t *testing.T) {
 	}
 }
 
+func TestState_RecordModel(t *testing.T) {
+	tempDir := t.TempDir()
+	state := NewState("session-123", tempDir, []string{}, "", nil)
+
+	state.RecordModel(1, "opus")
+	state.RecordModel(2, "opus")
+	state.RecordModel(2, "sonnet")
+
+	if len(state.ModelHistory) != 2 {
+		t.Fatalf("ModelHistory has %d entries; want 2", len(state.ModelHistory))
+	}
+	if state.ModelHistory[1].Model != "sonnet" {
+		t.Errorf("ModelHistory[1].Model = %q; want sonnet", state.ModelHistory[1].Model)
+	}
+
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.ModelHistory) != 2 || loaded.ModelHistory[0].Model != "opus" {
+		t.Errorf("loaded ModelHistory = %+v; want opus then sonnet", loaded.ModelHistory)
+	}
+}
+
+func TestState_RecordModeSwitch(t *testing.T) {
+	tempDir := t.TempDir()
+	state := NewState("session-123", tempDir, []string{}, "", nil)
+
+	state.RecordModeSwitch(1, ModeBurst, "haiku")
+	state.RecordModeSwitch(4, ModeMain, "opus")
+
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(tempDir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.ModeSwitches) != 2 {
+		t.Fatalf("loaded ModeSwitches = %+v; want 2 entries", loaded.ModeSwitches)
+	}
+	if got := loaded.ModeSwitches[1]; got.Iteration != 4 || got.Mode != ModeMain || got.Model != "opus" || got.At.IsZero() {
+		t.Errorf("ModeSwitches[1] = %+v; want main on opus from iteration 4", got)
+	}
+}
+
+func TestState_RecordSnapshot(t *testing.T) {
+	state := NewState("session-123", t.TempDir(), []string{}, "", nil)
+
+	state.RecordSnapshot(0, "aaa")
+	state.RecordSnapshot(1, "bbb")
+	state.RecordSnapshot(1, "ccc")
+
+	if len(state.Snapshots) != 2 {
+		t.Fatalf("Snapshots has %d entries; want 2", len(state.Snapshots))
+	}
+	snap, ok := state.FindSnapshot(1)
+	if !ok || snap.Commit != "ccc" {
+		t.Errorf("FindSnapshot(1) = %+v, %v; want commit ccc", snap, ok)
+	}
+	if _, ok := state.FindSnapshot(5); ok {
+		t.Error("FindSnapshot(5) found a snapshot that was never recorded")
+	}
+}
+
+func TestState_RecordCheckpoint(t *testing.T) {
+	state := NewState("session-123", t.TempDir(), []string{}, "", nil)
+	state.RecordSnapshot(2, "bbb")
+	state.RecordSnapshot(3, "ccc")
+
+	state.RecordCheckpoint("schema migrated", 2)
+	state.RecordCheckpoint("tests green", 3)
+	state.RecordCheckpoint("tests green", 5)
+
+	if len(state.Checkpoints) != 2 {
+		t.Fatalf("Checkpoints has %d entries; want 2", len(state.Checkpoints))
+	}
+	cp, ok := state.FindCheckpoint("schema migrated")
+	if !ok || cp.Iteration != 2 || cp.Commit != "bbb" {
+		t.Errorf("FindCheckpoint(schema migrated) = %+v, %v; want iteration 2, commit bbb", cp, ok)
+	}
+	cp, ok = state.FindCheckpoint("tests green")
+	if !ok || cp.Iteration != 5 || cp.Commit != "" {
+		t.Errorf("FindCheckpoint(tests green) = %+v, %v; want iteration 5 without a commit", cp, ok)
+	}
+	if _, ok := state.FindCheckpoint("deployed"); ok {
+		t.Error("FindCheckpoint(deployed) found a checkpoint that was never recorded")
+	}
+}
+
+func TestState_RollbackTo(t *testing.T) {
+	state := NewState("session-123", t.TempDir(), []string{}, "", nil)
+	state.SetWorkflow(&workflow.Workflow{Steps: []workflow.Step{{Name: "implement"}, {Name: "review"}}})
+	for i := 0; i <= 4; i++ {
+		state.RecordSnapshot(i, "commit")
+		if i > 0 {
+			state.RecordModel(i, "opus")
+		}
+	}
+	state.RecordCheckpoint("schema migrated", 1)
+	state.RecordCheckpoint("tests green", 3)
+	state.RecordModeSwitch(1, ModeBurst, "haiku")
+	state.RecordModeSwitch(3, ModeMain, "opus")
+	state.UpdateIteration(4, 12.5)
+	state.UpdateWorkflowStep(1)
+	state.IncrementGateRetry("review")
+
+	state.RollbackTo(2)
+
+	if state.Iteration != 2 {
+		t.Errorf("Iteration = %d; want 2", state.Iteration)
+	}
+	if state.TotalCost != 12.5 {
+		t.Errorf("TotalCost = %v; want spend preserved", state.TotalCost)
+	}
+	if len(state.Snapshots) != 3 {
+		t.Errorf("Snapshots has %d entries; want 3 (iterations 0-2)", len(state.Snapshots))
+	}
+	if len(state.Checkpoints) != 1 || state.Checkpoints[0].Name != "schema migrated" {
+		t.Errorf("Checkpoints = %+v; want only the checkpoint at iteration 1", state.Checkpoints)
+	}
+	if len(state.ModelHistory) != 2 {
+		t.Errorf("ModelHistory has %d entries; want 2", len(state.ModelHistory))
+	}
+	if len(state.ModeSwitches) != 1 || state.ModeSwitches[0].Mode != ModeBurst {
+		t.Errorf("ModeSwitches = %+v; want only the switch to burst at iteration 1", state.ModeSwitches)
+	}
+	if state.Workflow.CurrentStepIndex != 0 || state.GetGateRetryCount("review") != 0 {
+		t.Errorf("workflow progress not reset: %+v", state.Workflow)
+	}
+}
+
 func TestState_StartedAt_IsPreservedOnLoad(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -0,0 +1,108 @@
+package tasks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeTodoMarker starts a comment deferring work to a later iteration, e.g.
+// "// TODO(orbital): handle the empty case".
+const CodeTodoMarker = "TODO(orbital):"
+
+// maxScanSize is the largest file ScanCodeTodos reads. Bigger files are
+// almost always generated or data.
+const maxScanSize = 1 << 20
+
+// binarySniffLen is how much of a file is checked for NUL bytes to detect
+// binary content.
+const binarySniffLen = 8000
+
+// codeTodoPattern captures the text after the marker.
+var codeTodoPattern = regexp.MustCompile(regexp.QuoteMeta(CodeTodoMarker) + `\s*(.*)`)
+
+// commentClosers are stripped from the end of a TODO so block comments read
+// cleanly.
+var commentClosers = []string{"*/", "-->", "#}", "%>"}
+
+// CodeTodo is a TODO(orbital) comment found in a source file.
+type CodeTodo struct {
+	// File is the path relative to the scanned directory.
+	File string
+	// Line is the 1-based line number.
+	Line int
+	// Text is the comment text after the marker.
+	Text string
+}
+
+// String describes the TODO, e.g. "handle the empty case (parser.go:42)".
+func (t CodeTodo) String() string {
+	return fmt.Sprintf("%s (%s:%d)", t.Text, t.File, t.Line)
+}
+
+// ScanCodeTodos returns the TODO(orbital) comments in files, which are
+// relative to dir, in file and line order. Missing, binary and very large
+// files are skipped, as are markers with no text.
+func ScanCodeTodos(dir string, files []string) ([]CodeTodo, error) {
+	var todos []CodeTodo
+	for _, file := range files {
+		found, err := scanFile(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, found...)
+	}
+	return todos, nil
+}
+
+// scanFile returns the TODO(orbital) comments in one file.
+func scanFile(dir, file string) ([]CodeTodo, error) {
+	path := filepath.Join(dir, file)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", file, err)
+	}
+	if !info.Mode().IsRegular() || info.Size() > maxScanSize {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", file, err)
+	}
+	if bytes.IndexByte(data[:min(len(data), binarySniffLen)], 0) >= 0 {
+		return nil, nil
+	}
+	if !bytes.Contains(data, []byte(CodeTodoMarker)) {
+		return nil, nil
+	}
+
+	var todos []CodeTodo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScanSize)
+	for line := 1; scanner.Scan(); line++ {
+		m := codeTodoPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		if text := cleanTodoText(m[1]); text != "" {
+			todos = append(todos, CodeTodo{File: filepath.ToSlash(file), Line: line, Text: text})
+		}
+	}
+	return todos, nil
+}
+
+// cleanTodoText trims whitespace and trailing comment closers.
+func cleanTodoText(text string) string {
+	text = strings.TrimSpace(text)
+	for _, closer := range commentClosers {
+		text = strings.TrimSpace(strings.TrimSuffix(text, closer))
+	}
+	return text
+}
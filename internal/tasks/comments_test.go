@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanCodeTodos(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":       "package main\n\n// TODO(orbital): handle the empty case\nfunc main() {} // TODO(orbital):   trim input  \n// TODO: not ours\n",
+		"web/page.html": "<p>\n<!-- TODO(orbital): add a title -->\n</p>\n",
+		"lib.c":         "/* TODO(orbital): free the buffer */\n// TODO(orbital):\n",
+		"image.bin":     "\x00\x01TODO(orbital): binary\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	todos, err := ScanCodeTodos(dir, []string{"lib.c", "main.go", "image.bin", "deleted.go", "web/page.html"})
+	if err != nil {
+		t.Fatalf("ScanCodeTodos() error = %v", err)
+	}
+	want := []CodeTodo{
+		{File: "lib.c", Line: 1, Text: "free the buffer"},
+		{File: "main.go", Line: 3, Text: "handle the empty case"},
+		{File: "main.go", Line: 4, Text: "trim input"},
+		{File: "web/page.html", Line: 2, Text: "add a title"},
+	}
+	if !reflect.DeepEqual(todos, want) {
+		t.Errorf("ScanCodeTodos() = %+v, want %+v", todos, want)
+	}
+}
+
+func TestCodeTodo_String(t *testing.T) {
+	todo := CodeTodo{File: "parser.go", Line: 42, Text: "handle the empty case"}
+	if got, want := todo.String(), "handle the empty case (parser.go:42)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CorrectionsPrompt is placed before every step prompt of the iteration that
+// picks up the operator's task corrections. The verb is replaced with the
+// list of corrections.
+const CorrectionsPrompt = `---
+OPERATOR TASK CORRECTIONS: The operator changed the status of these tasks
+while the loop was running. Treat them as the current state and update your
+task list to match:
+
+%s
+---
+
+`
+
+// statusCycle is the order a task's status moves through when toggled.
+var statusCycle = map[string]string{
+	"pending":     "in_progress",
+	"in_progress": "completed",
+	"completed":   "pending",
+}
+
+// NextStatus returns the status after status when toggling a task: pending,
+// in progress, completed, then pending again.
+func NextStatus(status string) string {
+	if next, ok := statusCycle[status]; ok {
+		return next
+	}
+	return "in_progress"
+}
+
+// Correction is an operator's change to a task's status.
+type Correction struct {
+	Content string
+	From    string
+	To      string
+}
+
+// String describes the correction, e.g. `"Add tests": completed (was
+// in_progress)`.
+func (c Correction) String() string {
+	return fmt.Sprintf("%q: %s (was %s)", c.Content, c.To, c.From)
+}
+
+// SetStatus changes the status of the agent's task id on the operator's
+// behalf, records the change for TakeCorrections and returns the updated
+// task list. Tasks for code comments cannot be changed: they are closed by
+// removing the comment.
+func (t *Tracker) SetStatus(id, status string) ([]Task, error) {
+	if _, ok := statusCycle[status]; !ok {
+		return nil, fmt.Errorf("unknown task status %q", status)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	task, exists := t.tasks[id]
+	if !exists {
+		for _, code := range t.code {
+			if code.ID == id {
+				return nil, errors.New("TODO(orbital) comments are closed by removing them from the code")
+			}
+		}
+		return nil, fmt.Errorf("no task %s", id)
+	}
+	if task.Status == status {
+		return t.toSlice(), nil
+	}
+
+	// A task changed back and forth keeps its original status
+	from := task.Status
+	kept := t.corrections[:0]
+	for _, c := range t.corrections {
+		if c.Content == task.Content {
+			from = c.From
+			continue
+		}
+		kept = append(kept, c)
+	}
+	t.corrections = kept
+	if from != status {
+		t.corrections = append(t.corrections, Correction{Content: task.Content, From: from, To: status})
+	}
+
+	task.Status = status
+	return t.toSlice(), nil
+}
+
+// TakeCorrections returns the operator's corrections made since the last
+// call and forgets them.
+func (t *Tracker) TakeCorrections() []Correction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	corrections := t.corrections
+	t.corrections = nil
+	return corrections
+}
+
+// FormatCorrections returns the text placed before step prompts for the
+// given corrections, or "" when there are none.
+func FormatCorrections(corrections []Correction) string {
+	if len(corrections) == 0 {
+		return ""
+	}
+	lines := make([]string, len(corrections))
+	for i, c := range corrections {
+		lines[i] = "- " + c.String()
+	}
+	return fmt.Sprintf(CorrectionsPrompt, strings.Join(lines, "\n"))
+}
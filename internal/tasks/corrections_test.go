@@ -0,0 +1,89 @@
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNextStatus(t *testing.T) {
+	tests := map[string]string{
+		"pending":     "in_progress",
+		"in_progress": "completed",
+		"completed":   "pending",
+		"":            "in_progress",
+	}
+	for status, want := range tests {
+		if got := NextStatus(status); got != want {
+			t.Errorf("NextStatus(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestTrackerSetStatus(t *testing.T) {
+	tracker := NewTracker()
+	tracker.ProcessToolUse("TodoWrite", `{"todos": [
+		{"content": "Write parser", "status": "completed"},
+		{"content": "Add tests", "status": "in_progress"}
+	]}`)
+	tracker.SetCodeTodos([]CodeTodo{{File: "main.go", Line: 3, Text: "handle errors"}})
+
+	tasks, err := tracker.SetStatus("1", "pending")
+	if err != nil {
+		t.Fatalf("SetStatus() error = %v", err)
+	}
+	if tasks[0].Status != "pending" || len(tasks) != 3 {
+		t.Errorf("SetStatus() tasks = %+v, want the parser pending", tasks)
+	}
+
+	// Toggling back and forth records only the net change
+	if _, err := tracker.SetStatus("2", "completed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tracker.SetStatus("2", "pending"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tracker.SetStatus("2", "in_progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Correction{{Content: "Write parser", From: "completed", To: "pending"}}
+	if got := tracker.TakeCorrections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TakeCorrections() = %+v, want %+v", got, want)
+	}
+	if got := tracker.TakeCorrections(); len(got) != 0 {
+		t.Errorf("TakeCorrections() again = %+v, want none", got)
+	}
+}
+
+func TestTrackerSetStatus_Errors(t *testing.T) {
+	tracker := NewTracker()
+	tracker.ProcessToolUse("TaskCreate", `{"subject": "Write parser"}`)
+	tracker.SetCodeTodos([]CodeTodo{{File: "main.go", Line: 3, Text: "handle errors"}})
+
+	tests := []struct {
+		id, status, wantErr string
+	}{
+		{"1", "done", "unknown task status"},
+		{"9", "completed", "no task 9"},
+		{"code:main.go:3", "completed", "removing them from the code"},
+	}
+	for _, tt := range tests {
+		if _, err := tracker.SetStatus(tt.id, tt.status); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("SetStatus(%q, %q) error = %v, want %q", tt.id, tt.status, err, tt.wantErr)
+		}
+	}
+	if got := tracker.TakeCorrections(); len(got) != 0 {
+		t.Errorf("failed changes recorded: %+v", got)
+	}
+}
+
+func TestFormatCorrections(t *testing.T) {
+	if got := FormatCorrections(nil); got != "" {
+		t.Errorf("FormatCorrections(nil) = %q, want empty", got)
+	}
+	got := FormatCorrections([]Correction{{Content: "Add tests", From: "completed", To: "pending"}})
+	if !strings.Contains(got, "OPERATOR TASK CORRECTIONS") || !strings.Contains(got, `- "Add tests": pending (was completed)`) {
+		t.Errorf("FormatCorrections() = %q", got)
+	}
+}
@@ -22,6 +22,14 @@ type Tracker struct {
 	mu    sync.RWMutex
 	tasks map[string]*Task
 	order []string // Preserves insertion order
+
+	// code holds the TODO(orbital) comments found in changed files. They
+	// are listed after the agent's tasks and survive TodoWrite.
+	code []Task
+
+	// corrections are the operator's status changes not yet passed to the
+	// agent, in the order they were made.
+	corrections []Correction
 }
 
 // NewTracker creates a new Tracker.
@@ -188,16 +196,35 @@ func (t *Tracker) handleTodoWrite(input string) []Task {
 	return t.toSlice()
 }
 
-// toSlice returns the tasks as a slice in insertion order.
+// SetCodeTodos replaces the tasks for TODO(orbital) comments found in code
+// and returns the updated task list. The comments are listed as pending
+// after the agent's own tasks.
+func (t *Tracker) SetCodeTodos(todos []CodeTodo) []Task {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.code = make([]Task, 0, len(todos))
+	for _, todo := range todos {
+		t.code = append(t.code, Task{
+			ID:      "code:" + todo.File + ":" + util.IntToString(todo.Line),
+			Content: todo.String(),
+			Status:  "pending",
+		})
+	}
+	return t.toSlice()
+}
+
+// toSlice returns the tasks as a slice in insertion order, followed by the
+// tasks for code comments.
 // Must be called with lock held.
 func (t *Tracker) toSlice() []Task {
-	result := make([]Task, 0, len(t.order))
+	result := make([]Task, 0, len(t.order)+len(t.code))
 	for _, id := range t.order {
 		if task, exists := t.tasks[id]; exists {
 			result = append(result, *task)
 		}
 	}
-	return result
+	return append(result, t.code...)
 }
 
 // GetTasks returns the current task list.
@@ -216,7 +243,7 @@ func (t *Tracker) GetSummary() Summary {
 		Tasks: t.toSlice(),
 	}
 
-	for _, task := range t.tasks {
+	for _, task := range summary.Tasks {
 		summary.Total++
 		switch task.Status {
 		case "completed":
@@ -240,11 +267,13 @@ type Summary struct {
 	Tasks      []Task
 }
 
-// Clear removes all tasks.
+// Clear removes all tasks, including those for code comments.
 func (t *Tracker) Clear() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	t.tasks = make(map[string]*Task)
 	t.order = make([]string, 0)
+	t.code = nil
+	t.corrections = nil
 }
 
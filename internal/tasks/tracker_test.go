@@ -307,3 +307,29 @@ func TestTrackerTodoWriteDefaultStatus(t *testing.T) {
 		t.Errorf("expected status 'pending', got %q", tasks[0].Status)
 	}
 }
+
+func TestTrackerSetCodeTodos(t *testing.T) {
+	tracker := NewTracker()
+	tracker.ProcessToolUse("TaskCreate", `{"subject": "Agent task"}`)
+
+	tasks := tracker.SetCodeTodos([]CodeTodo{{File: "main.go", Line: 7, Text: "handle errors"}})
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(tasks))
+	}
+	if tasks[1].Content != "handle errors (main.go:7)" || tasks[1].Status != "pending" {
+		t.Errorf("unexpected code task %+v", tasks[1])
+	}
+
+	// TodoWrite replaces the agent's tasks but keeps the code comments
+	tasks = tracker.ProcessToolUse("TodoWrite", `{"todos": [{"content": "New plan", "status": "in_progress"}]}`)
+	if len(tasks) != 2 || tasks[0].Content != "New plan" || tasks[1].Content != "handle errors (main.go:7)" {
+		t.Errorf("unexpected tasks after TodoWrite: %+v", tasks)
+	}
+	if summary := tracker.GetSummary(); summary.Total != 2 || summary.Pending != 1 || summary.InProgress != 1 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	if tasks := tracker.SetCodeTodos(nil); len(tasks) != 1 {
+		t.Errorf("expected code tasks to be removed, got %+v", tasks)
+	}
+}
@@ -0,0 +1,160 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// week is the period used for the runs-per-week rate.
+const week = 7 * 24 * time.Hour
+
+// Summary is the aggregate view of recorded samples.
+type Summary struct {
+	Runs      int
+	Completed int
+
+	// RunsPerWeek is the average number of runs per week since the first sample.
+	RunsPerWeek float64
+
+	// AvgIterationsToCompletion averages iterations over completed runs only.
+	AvgIterationsToCompletion float64
+
+	// AvgBudgetUtilisation is the mean fraction of the budget spent per run.
+	AvgBudgetUtilisation float64
+
+	TotalCost float64
+
+	// Workflows breaks the summary down by workflow, sorted by name.
+	Workflows []WorkflowSummary
+}
+
+// WorkflowSummary aggregates the runs of a single workflow.
+type WorkflowSummary struct {
+	Name                      string
+	Runs                      int
+	Completed                 int
+	AvgIterationsToCompletion float64
+	AvgCost                   float64
+}
+
+// Summarise aggregates the samples. now marks the end of the runs-per-week window.
+func (s *Stats) Summarise(now time.Time) Summary {
+	var sum Summary
+	if len(s.Samples) == 0 {
+		return sum
+	}
+
+	first := s.Samples[0].FinishedAt
+	var completedIterations, utilisation float64
+	var budgeted int
+	byWorkflow := make(map[string]*workflowTotals)
+
+	for _, sample := range s.Samples {
+		sum.Runs++
+		sum.TotalCost += sample.Cost
+		if sample.FinishedAt.Before(first) {
+			first = sample.FinishedAt
+		}
+		if sample.Completed {
+			sum.Completed++
+			completedIterations += float64(sample.Iterations)
+		}
+		if sample.Budget > 0 {
+			budgeted++
+			utilisation += sample.Cost / sample.Budget
+		}
+
+		wt, ok := byWorkflow[sample.Workflow]
+		if !ok {
+			wt = &workflowTotals{}
+			byWorkflow[sample.Workflow] = wt
+		}
+		wt.add(sample)
+	}
+
+	// Count at least one week so a handful of runs today isn't extrapolated
+	weeks := now.Sub(first).Hours() / week.Hours()
+	if weeks < 1 {
+		weeks = 1
+	}
+	sum.RunsPerWeek = float64(sum.Runs) / weeks
+
+	if sum.Completed > 0 {
+		sum.AvgIterationsToCompletion = completedIterations / float64(sum.Completed)
+	}
+	if budgeted > 0 {
+		sum.AvgBudgetUtilisation = utilisation / float64(budgeted)
+	}
+
+	for name, wt := range byWorkflow {
+		sum.Workflows = append(sum.Workflows, wt.summary(name))
+	}
+	sort.Slice(sum.Workflows, func(i, j int) bool {
+		return sum.Workflows[i].Name < sum.Workflows[j].Name
+	})
+
+	return sum
+}
+
+// workflowTotals accumulates per-workflow sums while summarising.
+type workflowTotals struct {
+	runs, completed     int
+	completedIterations int
+	cost                float64
+}
+
+func (wt *workflowTotals) add(sample Sample) {
+	wt.runs++
+	wt.cost += sample.Cost
+	if sample.Completed {
+		wt.completed++
+		wt.completedIterations += sample.Iterations
+	}
+}
+
+func (wt *workflowTotals) summary(name string) WorkflowSummary {
+	ws := WorkflowSummary{
+		Name:      name,
+		Runs:      wt.runs,
+		Completed: wt.completed,
+		AvgCost:   wt.cost / float64(wt.runs),
+	}
+	if wt.completed > 0 {
+		ws.AvgIterationsToCompletion = float64(wt.completedIterations) / float64(wt.completed)
+	}
+	return ws
+}
+
+// Render writes the summary as an aligned plain-text report.
+func (sum Summary) Render(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Runs:\t%d (%d completed)\n", sum.Runs, sum.Completed)
+	_, _ = fmt.Fprintf(tw, "Runs per week:\t%.1f\n", sum.RunsPerWeek)
+	_, _ = fmt.Fprintf(tw, "Avg iterations to completion:\t%.1f\n", sum.AvgIterationsToCompletion)
+	_, _ = fmt.Fprintf(tw, "Avg budget utilisation:\t%.0f%%\n", sum.AvgBudgetUtilisation*100)
+	_, _ = fmt.Fprintf(tw, "Total cost:\t%s\n", util.FormatCost(sum.TotalCost, 2))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(sum.Workflows) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "WORKFLOW\tRUNS\tCOMPLETED\tAVG ITERATIONS\tAVG COST")
+	for _, ws := range sum.Workflows {
+		name := ws.Name
+		if name == "" {
+			name = "(default)"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\n", name, ws.Runs, ws.Completed, ws.AvgIterationsToCompletion, util.FormatCost(ws.AvgCost, 2))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarise(t *testing.T) {
+	now := time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC)
+	s := &Stats{Samples: []Sample{
+		{FinishedAt: now.AddDate(0, 0, -28), Workflow: "fast", Iterations: 4, Completed: true, Cost: 5, Budget: 10},
+		{FinishedAt: now.AddDate(0, 0, -14), Workflow: "fast", Iterations: 10, Completed: false, Cost: 10, Budget: 10},
+		{FinishedAt: now.AddDate(0, 0, -7), Workflow: "tdd", Iterations: 2, Completed: true, Cost: 2, Budget: 20},
+		{FinishedAt: now, Workflow: "tdd", Iterations: 6, Completed: true, Cost: 4, Budget: 0},
+	}}
+
+	sum := s.Summarise(now)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Runs", float64(sum.Runs), 4},
+		{"Completed", float64(sum.Completed), 3},
+		{"RunsPerWeek", sum.RunsPerWeek, 1},
+		{"AvgIterationsToCompletion", sum.AvgIterationsToCompletion, 4},
+		{"AvgBudgetUtilisation", sum.AvgBudgetUtilisation, (0.5 + 1 + 0.1) / 3},
+		{"TotalCost", sum.TotalCost, 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-9 {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+
+	if len(sum.Workflows) != 2 || sum.Workflows[0].Name != "fast" || sum.Workflows[1].Name != "tdd" {
+		t.Fatalf("Workflows = %+v, want fast then tdd", sum.Workflows)
+	}
+	if sum.Workflows[0].AvgIterationsToCompletion != 4 || sum.Workflows[0].Completed != 1 {
+		t.Errorf("fast workflow = %+v", sum.Workflows[0])
+	}
+}
+
+func TestSummarise_RecentRunsCountAsOneWeek(t *testing.T) {
+	now := time.Now()
+	s := &Stats{Samples: []Sample{{FinishedAt: now}, {FinishedAt: now}}}
+
+	if got := s.Summarise(now).RunsPerWeek; got != 2 {
+		t.Errorf("RunsPerWeek = %v, want 2", got)
+	}
+}
+
+func TestSummary_Render(t *testing.T) {
+	s := &Stats{Samples: []Sample{{FinishedAt: time.Now(), Workflow: "fast", Iterations: 3, Completed: true, Cost: 1.5, Budget: 10}}}
+
+	var buf bytes.Buffer
+	if err := s.Summarise(time.Now()).Render(&buf); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{"Runs:", "1 (1 completed)", "Avg budget utilisation:", "15%", "WORKFLOW", "fast"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
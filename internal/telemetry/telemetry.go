@@ -0,0 +1,105 @@
+// Package telemetry aggregates opt-in, local-only usage statistics for orbital
+// runs. Statistics are written to .orbital/stats.json and never leave the
+// machine; they exist to help users tune their own workflows.
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxSamples caps the number of runs kept in the stats file. Older runs are
+// dropped first.
+const maxSamples = 1000
+
+// Config controls whether statistics are collected.
+type Config struct {
+	// Enabled turns on statistics collection. Off unless set.
+	Enabled bool `toml:"enabled"`
+}
+
+// Sample is the anonymous summary of a single run. It deliberately holds no
+// file paths, prompts, or session identifiers.
+type Sample struct {
+	FinishedAt time.Time `json:"finished_at"`
+	Workflow   string    `json:"workflow"`
+	Model      string    `json:"model"`
+	Iterations int       `json:"iterations"`
+	Completed  bool      `json:"completed"`
+	Cost       float64   `json:"cost"`
+	Budget     float64   `json:"budget"`
+}
+
+// Stats holds the samples recorded in a working directory.
+type Stats struct {
+	Samples []Sample `json:"samples"`
+}
+
+// Path returns the location of the stats file for the working directory.
+func Path(workingDir string) string {
+	workingDir = strings.TrimSuffix(workingDir, "/")
+	return filepath.Join(workingDir, ".orbital", "stats.json")
+}
+
+// Load reads the stats file from the working directory.
+// Returns empty stats if nothing has been recorded.
+func Load(workingDir string) (*Stats, error) {
+	data, err := os.ReadFile(Path(workingDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Stats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	var s Stats
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+	}
+	return &s, nil
+}
+
+// Add appends a sample, dropping the oldest once maxSamples is exceeded.
+func (s *Stats) Add(sample Sample) {
+	s.Samples = append(s.Samples, sample)
+	if excess := len(s.Samples) - maxSamples; excess > 0 {
+		s.Samples = append(s.Samples[:0], s.Samples[excess:]...)
+	}
+}
+
+// Save writes the stats file to the working directory.
+func (s *Stats) Save(workingDir string) error {
+	path := Path(workingDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to rename stats file: %w", err)
+	}
+
+	return nil
+}
+
+// Record adds a sample to the stats file in the working directory.
+func Record(workingDir string, sample Sample) error {
+	s, err := Load(workingDir)
+	if err != nil {
+		return err
+	}
+	s.Add(sample)
+	return s.Save(workingDir)
+}
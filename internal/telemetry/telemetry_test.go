@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	s, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(s.Samples) != 0 {
+		t.Errorf("Load() returned %d samples, want 0", len(s.Samples))
+	}
+}
+
+func TestRecord_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	finished := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= 2; i++ {
+		err := Record(dir, Sample{FinishedAt: finished, Workflow: "fast", Iterations: i, Completed: true, Cost: 1, Budget: 10})
+		if err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	if _, err := os.Stat(Path(dir)); err != nil {
+		t.Fatalf("stats file not written: %v", err)
+	}
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(s.Samples) != 2 || s.Samples[1].Iterations != 2 {
+		t.Errorf("loaded samples = %+v", s.Samples)
+	}
+}
+
+func TestAdd_CapsSamples(t *testing.T) {
+	var s Stats
+	for i := 0; i < maxSamples+10; i++ {
+		s.Add(Sample{Iterations: i})
+	}
+
+	if len(s.Samples) != maxSamples {
+		t.Fatalf("len(Samples) = %d, want %d", len(s.Samples), maxSamples)
+	}
+	if s.Samples[0].Iterations != 10 {
+		t.Errorf("oldest sample = %d, want oldest samples dropped first", s.Samples[0].Iterations)
+	}
+}
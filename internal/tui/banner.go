@@ -0,0 +1,62 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// handleConfirmKey handles a key press while a question is awaiting an
+// answer. Other bindings are disabled until it is answered.
+func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		m.answerConfirm(true)
+	case "n", "N", "esc":
+		m.answerConfirm(false)
+	case "ctrl+c":
+		m.answerConfirm(false)
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// answerConfirm replies to the pending question, if any, and clears it.
+func (m *Model) answerConfirm(answer bool) {
+	if m.confirm == nil {
+		return
+	}
+	select {
+	case m.confirm.Reply <- answer:
+	default:
+	}
+	m.confirm = nil
+}
+
+// renderConfirm renders the pending question in place of the help bar.
+func (m Model) renderConfirm() string {
+	yes, no := m.confirm.Yes, m.confirm.No
+	if yes == "" {
+		yes = "continue"
+	}
+	if no == "" {
+		no = "stop"
+	}
+	keys := "  " + m.styles.HelpKey.Render("y") + m.styles.HelpBar.Render(" "+yes+"  ") +
+		m.styles.HelpKey.Render("n") + m.styles.HelpBar.Render(" "+no)
+	return "  " + m.styles.Warning.Render(m.fitHelpText("⚠ "+m.confirm.Question, keys)) + keys
+}
+
+// renderBanner renders the warning banner in place of the help bar.
+func (m Model) renderBanner() string {
+	keys := "  " + m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" dismiss")
+	return "  " + m.styles.Warning.Render(m.fitHelpText("⚠ "+m.banner, keys)) + keys
+}
+
+// fitHelpText truncates text so it fits on the help bar beside keys.
+func (m Model) fitHelpText(text, keys string) string {
+	width := m.layout.Width - 2 - ansi.StringWidth(keys)
+	if width < 1 {
+		return text
+	}
+	return ansi.Truncate(text, width, "…")
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// update applies msg to m and returns the updated model.
+func update(m Model, msg tea.Msg) Model {
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestBanner_ShownUntilDismissed(t *testing.T) {
+	m := update(chatModel(nil), BannerMsg("Cost anomaly: iteration 4 cost $20.00"))
+	view := m.View()
+	if !strings.Contains(view, "⚠ Cost anomaly: iteration 4 cost $20.00") || !strings.Contains(view, "esc dismiss") {
+		t.Fatalf("banner not shown in place of the help bar:\n%s", view)
+	}
+
+	// Other keys keep working while the banner is shown
+	m = typeKeys(m, "w")
+	if !m.outputTruncate || m.banner == "" {
+		t.Error("keys should still work and keep the banner")
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.banner != "" || strings.Contains(m.View(), "Cost anomaly") {
+		t.Error("esc did not dismiss the banner")
+	}
+}
+
+func TestBanner_TruncatedToWidth(t *testing.T) {
+	m := update(chatModel(nil), BannerMsg(strings.Repeat("expensive ", 40)))
+	help := m.renderHelpBar()
+	if !strings.Contains(help, "…") || !strings.Contains(help, "dismiss") {
+		t.Errorf("long banner not truncated before the keys: %q", help)
+	}
+}
+
+func TestConfirm_Answers(t *testing.T) {
+	tests := []struct {
+		key  tea.KeyMsg
+		want bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}, false},
+		{tea.KeyMsg{Type: tea.KeyEsc}, false},
+	}
+	for _, tt := range tests {
+		reply := make(chan bool, 1)
+		m := update(chatModel(nil), ConfirmMsg{Question: "Continue?", Reply: reply})
+		if view := m.View(); !strings.Contains(view, "⚠ Continue?") || !strings.Contains(view, "y continue") {
+			t.Fatalf("question not shown:\n%s", view)
+		}
+
+		// Unrelated keys are swallowed while the question is open
+		m = typeKeys(m, "w")
+		if m.outputTruncate {
+			t.Error("w toggled truncation while a question was open")
+		}
+
+		m = update(m, tt.key)
+		if m.confirm != nil {
+			t.Errorf("%s did not close the question", tt.key)
+		}
+		if got := <-reply; got != tt.want {
+			t.Errorf("%s answered %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestConfirm_ReplacedQuestionIsDeclined(t *testing.T) {
+	first := make(chan bool, 1)
+	m := update(chatModel(nil), ConfirmMsg{Question: "first", Reply: first})
+	m = update(m, ConfirmMsg{Question: "second", Reply: make(chan bool, 1)})
+	if got := <-first; got {
+		t.Error("replaced question was answered yes")
+	}
+	if m.confirm == nil || m.confirm.Question != "second" {
+		t.Errorf("pending question = %+v, want second", m.confirm)
+	}
+}
+
+func TestConfirm_LabelsAndWithdrawal(t *testing.T) {
+	reply := make(chan bool, 1)
+	m := update(chatModel(nil), ConfirmMsg{Question: "Approve deploy?", Yes: "approve", No: "reject", Reply: reply})
+	if view := m.View(); !strings.Contains(view, "y approve") || !strings.Contains(view, "n reject") {
+		t.Fatalf("key labels not shown:\n%s", view)
+	}
+
+	// Withdrawing another question leaves this one open
+	m = update(m, confirmWithdrawnMsg{Reply: make(chan bool, 1)})
+	if m.confirm == nil {
+		t.Fatal("unrelated withdrawal closed the question")
+	}
+	m = update(m, confirmWithdrawnMsg{Reply: reply})
+	if m.confirm != nil {
+		t.Error("withdrawn question still open")
+	}
+	select {
+	case got := <-reply:
+		t.Errorf("withdrawn question answered %v", got)
+	default:
+	}
+}
+
+func TestProgram_ConfirmReturnsWhenStopped(t *testing.T) {
+	p, _ := newTestProgram()
+	defer p.Close()
+	p.Quit()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Confirm(context.Background(), "Continue?")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != ErrProgramStopped {
+			t.Errorf("Confirm() error = %v, want ErrProgramStopped", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Confirm() blocked after the program stopped")
+	}
+}
@@ -2,14 +2,18 @@ package tui
 
 import (
 	"encoding/json"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+	"unicode"
 	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fatih/color"
 	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/redact"
 	"github.com/flashingpumpkin/orbital/internal/tasks"
 	"github.com/flashingpumpkin/orbital/internal/util"
 )
@@ -21,33 +25,65 @@ const defaultQueueSize = 100
 // Bridge connects the Claude CLI stream output to the bubbletea TUI.
 // It implements io.Writer and sends messages to the tea.Program.
 // Messages are sent through a buffered channel to avoid blocking stream
-// processing when TUI rendering is slow.
+// processing when TUI rendering is slow. Output lines and stats are
+// coalesced and flushed at most once per flush interval.
 type Bridge struct {
-	program *tea.Program
+	send    func(tea.Msg)
 	tracker *tasks.Tracker
 	parser  *output.Parser
 
 	mu        sync.Mutex
 	textShown bool // tracks if we're in a streaming text block
 
+	thinkingMode  output.ThinkingMode
+	thinkingLog   *output.ThinkingLogger
+	thinkingShown bool // tracks if we're in a streaming thinking block
+
+	sanitize output.SanitizeMode // control sequences in the model's output
+
+	redactor *redact.Redactor // masks secrets split across streamed deltas (may be nil)
+
+	level *LogLevel // debug detail is only queued while on (may be nil)
+
+	text     *TextMode        // raw or markdown assistant text (may be nil)
+	markdown markdownRenderer // renders assistant text while text is not plain
+
+	// Streamed text and thinking show as they arrive: complete lines as
+	// output lines, the line in progress as the partial line
+	lineHead    string       // prefix of the streamed block, owed to its first line
+	pending     string       // text after the last complete line
+	streamStyle *color.Color // style of the streamed block; nil renders markdown
+
 	// Message queue for non-blocking sends to TUI
-	msgQueue chan tea.Msg
-	closed   atomic.Bool
-	wg       sync.WaitGroup // tracks the message pump goroutine
+	msgQueue      chan tea.Msg
+	flushInterval time.Duration
+	closed        atomic.Bool
+	wg            sync.WaitGroup // tracks the message pump goroutine
 }
 
 // NewBridge creates a new Bridge with the given program and tracker.
 // It starts a background goroutine that pumps messages to the TUI program.
 func NewBridge(program *tea.Program, tracker *tasks.Tracker) *Bridge {
+	var send func(tea.Msg)
+	if program != nil {
+		send = program.Send
+	}
+	return newBridge(send, tracker, defaultFlushInterval)
+}
+
+// newBridge creates a Bridge that delivers messages via send.
+// The message pump is only started when send is non-nil.
+func newBridge(send func(tea.Msg), tracker *tasks.Tracker, flushInterval time.Duration) *Bridge {
 	b := &Bridge{
-		program:  program,
-		tracker:  tracker,
-		parser:   output.NewParser(),
-		msgQueue: make(chan tea.Msg, defaultQueueSize),
+		send:          send,
+		tracker:       tracker,
+		parser:        output.NewParser(),
+		msgQueue:      make(chan tea.Msg, defaultQueueSize),
+		flushInterval: flushInterval,
+		thinkingMode:  output.ThinkingDiscard,
 	}
 
-	// Start the message pump goroutine if program is provided
-	if program != nil {
+	if send != nil {
 		b.wg.Add(1)
 		go b.messagePump()
 	}
@@ -56,13 +92,44 @@ func NewBridge(program *tea.Program, tracker *tasks.Tracker) *Bridge {
 }
 
 // messagePump reads messages from the queue and sends them to the TUI program.
+// Output lines and stats are coalesced and flushed on each tick; any other
+// message flushes pending output first so ordering is preserved.
 // It runs until the bridge is closed.
 func (b *Bridge) messagePump() {
 	defer b.wg.Done()
 
-	for msg := range b.msgQueue {
-		// Send to the TUI program (this may block briefly but won't block writers)
-		b.program.Send(msg)
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	var batch outputBatch
+	flush := func() {
+		for _, msg := range batch.drain() {
+			// Send to the TUI program (this may block briefly but won't block writers)
+			b.send(msg)
+		}
+	}
+
+	for {
+		select {
+		case msg, ok := <-b.msgQueue:
+			if !ok {
+				flush()
+				return
+			}
+			switch m := msg.(type) {
+			case OutputLineMsg:
+				batch.addLine(string(m))
+			case StatsMsg:
+				batch.setStats(m)
+			case OutputPartialMsg:
+				batch.setPartial(m)
+			default:
+				flush()
+				b.send(msg)
+			}
+		case <-ticker.C:
+			flush()
+		}
 	}
 }
 
@@ -83,6 +150,42 @@ func (b *Bridge) sendMsg(msg tea.Msg) {
 	}
 }
 
+// SetThinking sets how thinking is handled. In ThinkingLog mode it is appended
+// to log; in ThinkingShow mode it is shown dimmed in the output pane.
+func (b *Bridge) SetThinking(mode output.ThinkingMode, log io.Writer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.thinkingMode = mode
+	b.thinkingLog = nil
+	if mode == output.ThinkingLog {
+		b.thinkingLog = output.NewThinkingLogger(log)
+		b.thinkingLog.SetRedactor(b.redactor)
+	}
+}
+
+// SetRedactor sets the redactor applied to streamed text and thinking. Each
+// stream event is redacted before it reaches the bridge, but a secret split
+// across deltas is only whole once they are joined, so the joined text is
+// masked again before it is shown or logged.
+func (b *Bridge) SetRedactor(r *redact.Redactor) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.redactor = r
+	b.markdown.redactor = r
+	b.thinkingLog.SetRedactor(r)
+}
+
+// SetSanitize sets how control sequences in the model's output are handled
+// before it is shown or logged. The default is output.SanitizeStrip.
+func (b *Bridge) SetSanitize(mode output.SanitizeMode) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sanitize = mode
+}
+
 // Close shuts down the bridge and stops the message pump goroutine.
 // It should be called when the bridge is no longer needed.
 func (b *Bridge) Close() {
@@ -118,16 +221,26 @@ func (b *Bridge) processLine(line string) {
 
 	event, err := b.parser.ParseLine([]byte(line))
 	if err != nil || event == nil {
+		b.sendDebug(formatUnparsed(output.Sanitize(line, b.sanitize)))
 		return
 	}
+	if !displayedEventTypes[event.Type] {
+		b.sendDebug(formatUnhandled(event.Type, output.Sanitize(line, b.sanitize)))
+	}
+	output.SanitizeEvent(event, b.sanitize)
 
-	// Check for task-related tool uses
-	if event.ToolName != "" && event.ToolInput != "" {
+	// Check for task-related tool uses. A streamed tool use is started
+	// before its input is known; the assistant message carries it.
+	if event.ToolName != "" && event.ToolInput != "" && !(event.Streamed && event.Type == "content_block_start") {
 		if tasks := b.tracker.ProcessToolUse(event.ToolName, event.ToolInput); tasks != nil {
 			b.sendMsg(TasksMsg(tasks))
 		}
 	}
 
+	if thinking := b.formatThinking(event); thinking != "" {
+		b.sendMsg(OutputLineMsg(thinking))
+	}
+
 	// Format and send output line based on event type
 	formatted := b.formatEvent(event)
 	if formatted != "" {
@@ -147,11 +260,131 @@ func (b *Bridge) processLine(line string) {
 				Cost:                 stats.CostUSD,
 				CurrentIterTokensIn:  stats.CurrentIterTokensIn,
 				CurrentIterTokensOut: stats.CurrentIterTokensOut,
+				CacheReadTokens:      stats.CacheReadTokens,
 			})
 		}
 	}
 }
 
+// formatThinking shows, logs, or discards the thinking carried by event.
+// It returns the display string when thinking is shown.
+func (b *Bridge) formatThinking(event *output.StreamEvent) string {
+	if b.thinkingMode == output.ThinkingLog {
+		b.thinkingLog.Log(event)
+		return ""
+	}
+	// Assistant messages repeat the thinking that was streamed
+	repeated := event.Type == "assistant" && event.Streamed
+	if event.Thinking == "" || b.thinkingMode != output.ThinkingShow || repeated {
+		if b.thinkingShown {
+			// End of streamed thinking: show what was held back of its last line
+			b.thinkingShown = false
+			return b.endStream()
+		}
+		return ""
+	}
+
+	b.textShown = false
+	dim := color.New(color.Faint)
+	// Thinking from an assistant message is a complete block
+	if event.Type == "assistant" {
+		if rest := b.endStream(); rest != "" {
+			b.sendMsg(OutputLineMsg(rest))
+		}
+		b.thinkingShown = false
+		return "\n  🧠 " + dim.Sprint(event.Thinking)
+	}
+	if !b.thinkingShown {
+		// Starting a new thinking block
+		b.startStream("\n  🧠 ", dim)
+		b.thinkingShown = true
+	}
+	b.streamChunk(event.Thinking)
+	return ""
+}
+
+// startStream begins a streamed block led by head, in style (nil renders
+// markdown). What was left of an unfinished block is output first.
+func (b *Bridge) startStream(head string, style *color.Color) {
+	if rest := b.endStream(); rest != "" {
+		b.sendMsg(OutputLineMsg(rest))
+	}
+	b.lineHead = head
+	b.streamStyle = style
+}
+
+// streamChunk shows a chunk of the streamed block. The lines it completes
+// are output and the line still in progress is sent as the partial line, so
+// text appears word by word as it is generated. The message pump sends at
+// most one partial line per flush.
+func (b *Bridge) streamChunk(chunk string) {
+	var lines, partial string
+	complete := false
+	if b.streamStyle == nil {
+		lines = b.markdown.Feed(chunk)
+		complete = lines != ""
+		partial = b.markdown.Partial()
+	} else {
+		text := b.pending + chunk
+		i := strings.LastIndexByte(text, '\n')
+		if i >= 0 {
+			lines, complete = b.streamStyle.Sprint(b.redactor.Mask(text[:i])), true
+		}
+		b.pending = text[i+1:]
+		if p := maskPartial(b.redactor, b.pending); p != "" {
+			partial = b.streamStyle.Sprint(p)
+		}
+	}
+
+	if complete {
+		b.sendMsg(OutputLineMsg(b.lineHead + lines))
+		b.lineHead = ""
+	}
+	if partial != "" {
+		partial = b.lineHead + partial
+	}
+	b.sendMsg(OutputPartialMsg(partial))
+}
+
+// maskPartial masks secrets in a streamed line still in progress. With a
+// redactor, the word still arriving is held back as well, so no prefix of a
+// secret is shown before the rest of it arrives to be matched.
+func maskPartial(r *redact.Redactor, line string) string {
+	if r == nil {
+		return line
+	}
+	return strings.TrimRightFunc(r.Mask(line), func(c rune) bool { return !unicode.IsSpace(c) })
+}
+
+// endStream ends the streamed block and returns what was held back of its
+// last line, led by the block's prefix if none of it was output yet.
+func (b *Bridge) endStream() string {
+	var rest string
+	if b.streamStyle == nil {
+		rest = b.markdown.Flush()
+	} else if b.pending != "" {
+		rest = b.streamStyle.Sprint(b.redactor.Mask(b.pending))
+	}
+	if rest != "" {
+		rest = b.lineHead + rest
+	}
+	b.lineHead, b.pending, b.streamStyle = "", "", nil
+	return rest
+}
+
+// displayedEventTypes are the event types formatEvent handles. Others are
+// only shown as debug detail.
+var displayedEventTypes = map[string]bool{
+	"system":              true,
+	"content_block_start": true,
+	"content_block_stop":  true,
+	"content_block_delta": true,
+	"assistant":           true,
+	"user":                true,
+	"error":               true,
+	"result":              true,
+}
+
 // formatEvent formats a stream event into a display string.
 func (b *Bridge) formatEvent(event *output.StreamEvent) string {
 	cyan := color.New(color.FgCyan)
@@ -168,7 +401,9 @@ func (b *Bridge) formatEvent(event *output.StreamEvent) string {
 		}
 
 	case "content_block_start":
-		if event.Content == "tool_use" && event.ToolName != "" {
+		// A streamed tool use is shown by the assistant message carrying
+		// its input
+		if event.Content == "tool_use" && event.ToolName != "" && !event.Streamed {
 			b.textShown = false
 			summary := formatToolSummary(event.ToolName, event.ToolInput)
 			return cyan.Sprint("  → ") + cyan.Sprint(event.ToolName) + dim.Sprint(summary)
@@ -177,7 +412,8 @@ func (b *Bridge) formatEvent(event *output.StreamEvent) string {
 	case "content_block_stop":
 		if b.textShown {
 			b.textShown = false
-			return "" // End of text block, no additional output needed
+			// End of text block: show what was held back of its last line
+			return b.endStream()
 		}
 
 	case "assistant":
@@ -186,7 +422,8 @@ func (b *Bridge) formatEvent(event *output.StreamEvent) string {
 			summary := formatToolSummary(event.ToolName, event.ToolInput)
 			return cyan.Sprint("  → ") + cyan.Sprint(event.ToolName) + dim.Sprint(summary)
 		}
-		if event.Content != "" {
+		// The text of a streamed message has been shown as it arrived
+		if event.Content != "" && !event.Streamed {
 			// Format as assistant thought with 💭 prefix
 			var result string
 			if !b.textShown {
@@ -194,19 +431,25 @@ func (b *Bridge) formatEvent(event *output.StreamEvent) string {
 				result = "\n  💭 "
 				b.textShown = true
 			}
+			if !b.text.Plain() {
+				return result + b.markdown.Render(event.Content)
+			}
 			return result + yellow.Sprint(event.Content)
 		}
 
 	case "content_block_delta":
 		// Streaming text content
 		if event.Content != "" {
-			var result string
 			if !b.textShown {
 				// Starting a new thought block - emoji visible, not dimmed
-				result = "\n  💭 "
+				var style *color.Color
+				if b.text.Plain() {
+					style = yellow
+				}
+				b.startStream("\n  💭 ", style)
 				b.textShown = true
 			}
-			return result + yellow.Sprint(event.Content)
+			b.streamChunk(event.Content)
 		}
 
 	case "user":
@@ -415,7 +658,7 @@ func cleanToolResult(content string) string {
 
 // formatResultLine formats the result statistics line.
 func formatResultLine(stats *output.OutputStats) string {
-	return "  --- tokens: " + formatInt(stats.TokensIn) + " in, " + formatInt(stats.TokensOut) + " out | cost: $" + formatFloat(stats.CostUSD) + " ---"
+	return "  --- tokens: " + formatInt(stats.TokensIn) + " in, " + formatInt(stats.TokensOut) + " out | cost: " + util.FormatCost(stats.CostUSD, 4) + " ---"
 }
 
 // formatInt formats an integer with thousands separator.
@@ -423,24 +666,6 @@ func formatInt(n int) string {
 	return util.FormatNumber(n)
 }
 
-// formatFloat formats a float with 4 decimal places.
-func formatFloat(f float64) string {
-	// Handle negative values specially to preserve sign for values between -1 and 0
-	if f < 0 {
-		// Format absolute value and prepend minus sign
-		return "-" + formatFloat(-f)
-	}
-	whole := int(f)
-	// Use rounding (+0.5) instead of truncation for better precision
-	frac := int((f-float64(whole))*10000 + 0.5)
-	// Handle rounding that carries over to whole number (e.g., 1.99999 -> 2.0000)
-	if frac >= 10000 {
-		whole++
-		frac = 0
-	}
-	return util.IntToString(whole) + "." + padLeft(util.IntToString(frac), 4, '0')
-}
-
 // GetParser returns the parser for external access to stats.
 func (b *Bridge) GetParser() *output.Parser {
 	return b.parser
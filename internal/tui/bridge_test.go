@@ -1,7 +1,12 @@
 package tui
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tasks"
 )
 
 func TestExtractJSONField(t *testing.T) {
@@ -221,21 +226,21 @@ func TestFormatInt(t *testing.T) {
 	}
 }
 
-func TestFormatFloat(t *testing.T) {
+func TestFormatResultLine(t *testing.T) {
 	tests := []struct {
-		f        float64
+		cost     float64
 		expected string
 	}{
-		{0.0, "0.0000"},
-		{1.5, "1.5000"},
-		{0.1234, "0.1234"},
-		{10.05, "10.0500"},
+		{0.0, "cost: $0.0000 ---"},
+		{1.5, "cost: $1.5000 ---"},
+		{0.1234, "cost: $0.1234 ---"},
+		{10.05, "cost: $10.0500 ---"},
 	}
 
 	for _, tt := range tests {
-		got := formatFloat(tt.f)
-		if got != tt.expected {
-			t.Errorf("formatFloat(%f) = %q, want %q", tt.f, got, tt.expected)
+		got := formatResultLine(&output.OutputStats{TokensIn: 1200, TokensOut: 300, CostUSD: tt.cost})
+		if !strings.HasSuffix(got, tt.expected) || !strings.Contains(got, "1,200 in, 300 out") {
+			t.Errorf("formatResultLine(cost %f) = %q, want suffix %q", tt.cost, got, tt.expected)
 		}
 	}
 }
@@ -623,3 +628,74 @@ func TestFormatToolSummaryTodoWrite(t *testing.T) {
 		t.Error("TodoWrite summary missing 6-space indentation")
 	}
 }
+
+func TestBridgeThinking(t *testing.T) {
+	const line = `{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"Checking the gate"}]}}`
+
+	tests := []struct {
+		name       string
+		mode       output.ThinkingMode
+		wantOutput bool
+		wantLogged string
+	}{
+		{name: "discard", mode: output.ThinkingDiscard},
+		{name: "show", mode: output.ThinkingShow, wantOutput: true},
+		{name: "log", mode: output.ThinkingLog, wantLogged: "Checking the gate\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBridge(nil, tasks.NewTracker(), defaultFlushInterval)
+			var log bytes.Buffer
+			b.SetThinking(tt.mode, &log)
+
+			_, _ = b.Write([]byte(line + "\n"))
+
+			var shown bool
+			for len(b.msgQueue) > 0 {
+				if msg, ok := (<-b.msgQueue).(OutputLineMsg); ok && strings.Contains(string(msg), "Checking the gate") {
+					shown = true
+				}
+			}
+			if shown != tt.wantOutput {
+				t.Errorf("thinking shown = %v, want %v", shown, tt.wantOutput)
+			}
+			if log.String() != tt.wantLogged {
+				t.Errorf("thinking log = %q, want %q", log.String(), tt.wantLogged)
+			}
+		})
+	}
+}
+
+func TestBridgeSanitize(t *testing.T) {
+	// A tool result that clears the screen and retitles the terminal
+	const line = `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"done\u001b[2J\u001b]0;pwned\u0007"}]}}`
+
+	tests := []struct {
+		name string
+		mode output.SanitizeMode
+		want string
+	}{
+		{name: "default", want: "done"},
+		{name: "escape", mode: output.SanitizeEscape, want: "done␛[2J␛]0;pwned␇"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBridge(nil, tasks.NewTracker(), defaultFlushInterval)
+			if tt.mode != "" {
+				b.SetSanitize(tt.mode)
+			}
+			_, _ = b.Write([]byte(line + "\n"))
+
+			var got string
+			for len(b.msgQueue) > 0 {
+				if msg, ok := (<-b.msgQueue).(OutputLineMsg); ok {
+					got += string(msg)
+				}
+			}
+			if !strings.Contains(got, tt.want) || strings.Contains(got, "\x1b[2J") || strings.Contains(got, "\x1b]") {
+				t.Errorf("output = %q, want %q without raw control sequences", got, tt.want)
+			}
+		})
+	}
+}
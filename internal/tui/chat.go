@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ChatFunc answers a question about the running session.
+// recentOutput is the tail of the output pane with styling stripped,
+// most recent line last.
+type ChatFunc func(ctx context.Context, question string, recentOutput []string) (string, error)
+
+const (
+	// chatTimeout bounds how long a single chat question may take.
+	chatTimeout = 2 * time.Minute
+
+	// chatContextLines is the number of recent output lines passed to the chat model.
+	chatContextLines = 200
+)
+
+// chatEntry is a single question and its answer in the chat pane.
+type chatEntry struct {
+	Question string
+	Answer   string
+	Err      error
+	Pending  bool
+}
+
+// chatResponseMsg carries the answer to a chat question.
+type chatResponseMsg struct {
+	Answer string
+	Err    error
+}
+
+// askCmd creates a command that sends the question to the chat function.
+func askCmd(fn ChatFunc, question string, recentOutput []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), chatTimeout)
+		defer cancel()
+		answer, err := fn(ctx, question, recentOutput)
+		return chatResponseMsg{Answer: answer, Err: err}
+	}
+}
+
+// chatPending reports whether a chat question is awaiting an answer.
+func (m Model) chatPending() bool {
+	n := len(m.chatHistory)
+	return n > 0 && m.chatHistory[n-1].Pending
+}
+
+// recentOutput returns the last chatContextLines output lines without ANSI styling.
+func (m Model) recentOutput() []string {
+	start := m.outputLines.Len() - chatContextLines
+	if start < 0 {
+		start = 0
+	}
+	lines := make([]string, 0, m.outputLines.Len()-start)
+	m.outputLines.Iterate(func(i int, line string) bool {
+		if i >= start {
+			lines = append(lines, ansi.Strip(line))
+		}
+		return true
+	})
+	return lines
+}
+
+// handleChatKey handles a key press while the chat pane is open.
+// All printable keys go to the input line so they don't trigger other bindings.
+func (m Model) handleChatKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.chatOpen = false
+		return m, nil
+	case tea.KeyEnter:
+		question := strings.TrimSpace(m.chatInput)
+		if question == "" || m.chatPending() {
+			return m, nil
+		}
+		m.chatInput = ""
+		m.chatHistory = append(m.chatHistory, chatEntry{Question: question, Pending: true})
+		return m, askCmd(m.chat, question, m.recentOutput())
+	case tea.KeyBackspace:
+		if r := []rune(m.chatInput); len(r) > 0 {
+			m.chatInput = string(r[:len(r)-1])
+		}
+		return m, nil
+	case tea.KeySpace:
+		m.chatInput += " "
+		return m, nil
+	case tea.KeyRunes:
+		m.chatInput += string(msg.Runes)
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderChatPane renders the chat history and input line in the main content area.
+func (m Model) renderChatPane() string {
+	height := m.layout.ScrollAreaHeight
+	contentWidth := m.layout.ContentWidth()
+
+	// Guard against invalid dimensions
+	if height <= 0 {
+		return ""
+	}
+	if contentWidth < 0 {
+		contentWidth = 0
+	}
+
+	border := m.styles.Border.Render(BoxVertical)
+	wrapWidth := contentWidth - outputPaddingLeft
+	if wrapWidth < 1 {
+		wrapWidth = 1
+	}
+	wrap := lipgloss.NewStyle().Width(wrapWidth).PaddingLeft(outputPaddingLeft)
+
+	var body []string
+	if len(m.chatHistory) == 0 {
+		body = append(body, wrap.Render(m.styles.Label.Render("Ask a question about this run, e.g. \"why did the last gate fail?\"")))
+	}
+	for _, entry := range m.chatHistory {
+		body = append(body, wrap.Render(m.styles.Header.Render("> ")+m.styles.Value.Render(entry.Question)))
+		switch {
+		case entry.Pending:
+			body = append(body, wrap.Render(m.styles.Label.Render("Thinking...")))
+		case entry.Err != nil:
+			body = append(body, wrap.Render(m.styles.Error.Render(IconError+" "+entry.Err.Error())))
+		default:
+			body = append(body, wrap.Render(entry.Answer))
+		}
+		body = append(body, "")
+	}
+	bodyLines := strings.Split(strings.Join(body, "\n"), "\n")
+
+	// Keep the most recent history visible above the input line
+	visible := height - 1
+	if len(bodyLines) > visible {
+		bodyLines = bodyLines[len(bodyLines)-visible:]
+	}
+
+	var lines []string
+	for _, line := range bodyLines {
+		lines = append(lines, padChatLine(border, line, contentWidth))
+	}
+	for len(lines) < visible {
+		lines = append(lines, border+strings.Repeat(" ", contentWidth)+border)
+	}
+
+	input := m.styles.Header.Render(" chat> ") + m.styles.Value.Render(m.chatInput+"█")
+	lines = append(lines, padChatLine(border, input, contentWidth))
+
+	return strings.Join(lines, "\n")
+}
+
+// padChatLine pads or truncates a line to the content width and adds borders.
+func padChatLine(border, line string, contentWidth int) string {
+	padding := contentWidth - ansi.StringWidth(line)
+	if padding < 0 {
+		line = ansi.Truncate(line, contentWidth, "")
+		padding = 0
+	}
+	return border + line + strings.Repeat(" ", padding) + border
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func chatModel(fn ChatFunc) Model {
+	m := NewModel()
+	m.chat = fn
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated.(Model)
+}
+
+func typeKeys(m Model, s string) Model {
+	for _, r := range s {
+		var msg tea.KeyMsg
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace}
+		} else {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestChat_ToggleRequiresChatFunc(t *testing.T) {
+	m := chatModel(nil)
+	m = typeKeys(m, "c")
+	if m.chatOpen {
+		t.Error("chat pane opened without a chat function")
+	}
+
+	m = chatModel(func(context.Context, string, []string) (string, error) { return "", nil })
+	m = typeKeys(m, "c")
+	if !m.chatOpen {
+		t.Fatal("expected chat pane to open on 'c'")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).chatOpen {
+		t.Error("expected esc to close the chat pane")
+	}
+}
+
+func TestChat_TypingDoesNotTriggerBindings(t *testing.T) {
+	m := chatModel(func(context.Context, string, []string) (string, error) { return "", nil })
+	m = typeKeys(m, "c")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("typing 'q' in the chat pane should not quit")
+	}
+	if m.chatInput != "q" {
+		t.Errorf("chatInput = %q, want %q", m.chatInput, "q")
+	}
+}
+
+func TestChat_AskAndAnswer(t *testing.T) {
+	var gotQuestion string
+	var gotOutput []string
+	m := chatModel(func(_ context.Context, question string, recentOutput []string) (string, error) {
+		gotQuestion = question
+		gotOutput = recentOutput
+		return "  Because the tests failed.\n", nil
+	})
+	m.outputLines.Push("\x1b[31mFAIL\x1b[0m TestFoo")
+	m = typeKeys(m, "c")
+	m = typeKeys(m, "why did it fail?")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected enter to return a chat command")
+	}
+	if !m.chatPending() {
+		t.Error("expected question to be pending")
+	}
+	if m.chatInput != "" {
+		t.Errorf("chatInput = %q, want empty after submit", m.chatInput)
+	}
+
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if gotQuestion != "why did it fail?" {
+		t.Errorf("question = %q", gotQuestion)
+	}
+	if len(gotOutput) != 1 || gotOutput[0] != "FAIL TestFoo" {
+		t.Errorf("recentOutput = %q, want styling stripped", gotOutput)
+	}
+	if m.chatPending() {
+		t.Error("expected question to be answered")
+	}
+	if got := m.chatHistory[0].Answer; got != "Because the tests failed." {
+		t.Errorf("answer = %q", got)
+	}
+	if !strings.Contains(m.View(), "Because the tests failed.") {
+		t.Error("expected answer to be rendered in the chat pane")
+	}
+}
+
+func TestChat_RendersError(t *testing.T) {
+	m := chatModel(func(context.Context, string, []string) (string, error) { return "", nil })
+	m.chatOpen = true
+	m.chatHistory = []chatEntry{{Question: "status?", Err: errors.New("model unavailable")}}
+
+	if !strings.Contains(m.View(), "model unavailable") {
+		t.Error("expected chat error to be rendered")
+	}
+}
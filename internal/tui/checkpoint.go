@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// markCheckpointCmd hands a checkpoint name to fn and confirms it in the
+// output. The checkpoint itself is recorded when the iteration ends.
+func markCheckpointCmd(fn func(string), name string) tea.Cmd {
+	return func() tea.Msg {
+		fn(name)
+		return OutputLineMsg(fmt.Sprintf("📍 Checkpoint %q will be recorded at the end of this iteration", name))
+	}
+}
+
+// handleCheckpointKey handles a key press while a checkpoint name is being
+// typed. All printable keys go to the input so they don't trigger other bindings.
+func (m Model) handleCheckpointKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.checkpointOpen = false
+		return m, nil
+	case tea.KeyEnter:
+		name := strings.Join(strings.Fields(m.checkpointInput), " ")
+		if name == "" {
+			return m, nil
+		}
+		m.checkpointOpen = false
+		m.checkpointInput = ""
+		return m, markCheckpointCmd(m.checkpoint, name)
+	case tea.KeyBackspace:
+		if r := []rune(m.checkpointInput); len(r) > 0 {
+			m.checkpointInput = string(r[:len(r)-1])
+		}
+		return m, nil
+	case tea.KeySpace:
+		m.checkpointInput += " "
+		return m, nil
+	case tea.KeyRunes:
+		m.checkpointInput += string(msg.Runes)
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderCheckpointInput renders the checkpoint name prompt in place of the help bar.
+func (m Model) renderCheckpointInput() string {
+	return "  " + m.styles.Header.Render("checkpoint> ") + m.styles.Value.Render(m.checkpointInput+"█") + "  " +
+		m.styles.HelpKey.Render("enter") + m.styles.HelpBar.Render(" mark  ") +
+		m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" cancel")
+}
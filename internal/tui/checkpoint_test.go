@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCheckpoint_MarkRequiresFunc(t *testing.T) {
+	m := chatModel(nil)
+	m = typeKeys(m, "m")
+	if m.checkpointOpen {
+		t.Error("checkpoint prompt opened without a checkpoint function")
+	}
+}
+
+func TestCheckpoint_TypeAndSubmit(t *testing.T) {
+	var got string
+	m := chatModel(nil)
+	m.checkpoint = func(name string) { got = name }
+
+	m = typeKeys(m, "m")
+	if !m.checkpointOpen {
+		t.Fatal("expected checkpoint prompt to open on 'm'")
+	}
+	m = typeKeys(m, "tests  greenq")
+	if m.checkpointInput != "tests  greenq" {
+		t.Errorf("checkpointInput = %q, typing should not trigger bindings", m.checkpointInput)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "checkpoint> tests  green") {
+		t.Error("view does not show the checkpoint prompt")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.checkpointOpen {
+		t.Error("expected enter to close the checkpoint prompt")
+	}
+	if cmd == nil {
+		t.Fatal("expected a command to record the checkpoint")
+	}
+	msg := cmd()
+	if got != "tests green" {
+		t.Errorf("checkpoint name = %q, want %q", got, "tests green")
+	}
+	if line, ok := msg.(OutputLineMsg); !ok || !strings.Contains(string(line), `"tests green"`) {
+		t.Errorf("confirmation = %#v", msg)
+	}
+}
+
+func TestCheckpoint_EscCancels(t *testing.T) {
+	called := false
+	m := chatModel(nil)
+	m.checkpoint = func(string) { called = true }
+
+	m = typeKeys(m, "mabc")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.checkpointOpen || cmd != nil || called {
+		t.Error("expected esc to cancel without recording a checkpoint")
+	}
+}
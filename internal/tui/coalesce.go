@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fatih/color"
+)
+
+const (
+	// defaultFlushInterval is how often the bridge flushes coalesced output to
+	// the TUI. Output arriving within one interval is rendered in a single update.
+	defaultFlushInterval = 50 * time.Millisecond
+
+	// maxBatchLines caps the output lines held between flushes. Under sustained
+	// overload older lines are dropped and replaced by a single summary line.
+	maxBatchLines = 200
+)
+
+// outputBatch accumulates messages between flushes so that bursts of output
+// cost one render rather than one per line.
+type outputBatch struct {
+	lines   []string
+	dropped int
+	stats   *StatsMsg
+	partial *OutputPartialMsg
+}
+
+// addLine appends an output line, dropping the oldest lines once the batch
+// exceeds maxBatchLines. The line completes any partial line before it.
+func (ob *outputBatch) addLine(line string) {
+	ob.partial = nil
+	ob.lines = append(ob.lines, line)
+	if excess := len(ob.lines) - maxBatchLines; excess > 0 {
+		ob.dropped += excess
+		ob.lines = append(ob.lines[:0], ob.lines[excess:]...)
+	}
+}
+
+// setStats records the latest stats. Only the most recent stats are sent.
+func (ob *outputBatch) setStats(stats StatsMsg) {
+	ob.stats = &stats
+}
+
+// setPartial records the line being streamed in. Only the most recent one is
+// sent, so text streamed word by word costs one render per flush.
+func (ob *outputBatch) setPartial(partial OutputPartialMsg) {
+	ob.partial = &partial
+}
+
+// drain returns the messages to send for the batch and resets it.
+// Output lines are merged into a single OutputBatchMsg, prefixed with a
+// summary line when lines were dropped, and followed by the partial line.
+func (ob *outputBatch) drain() []tea.Msg {
+	var msgs []tea.Msg
+
+	if len(ob.lines) > 0 || ob.dropped > 0 {
+		lines := make([]string, 0, len(ob.lines)+1)
+		if ob.dropped > 0 {
+			summary := fmt.Sprintf("  … %d lines skipped (output too fast to display)", ob.dropped)
+			lines = append(lines, color.New(color.Faint).Sprint(summary))
+		}
+		lines = append(lines, ob.lines...)
+		msgs = append(msgs, OutputBatchMsg(lines))
+	}
+
+	if ob.partial != nil {
+		msgs = append(msgs, *ob.partial)
+	}
+
+	if ob.stats != nil {
+		msgs = append(msgs, *ob.stats)
+	}
+
+	ob.lines = ob.lines[:0]
+	ob.dropped = 0
+	ob.stats = nil
+	ob.partial = nil
+
+	return msgs
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOutputBatch_DrainMergesLines(t *testing.T) {
+	var batch outputBatch
+	batch.addLine("one")
+	batch.addLine("two")
+	batch.setStats(StatsMsg{TokensIn: 10})
+	batch.setStats(StatsMsg{TokensIn: 20})
+
+	msgs := batch.drain()
+	if len(msgs) != 2 {
+		t.Fatalf("drain() returned %d messages, want 2", len(msgs))
+	}
+
+	lines, ok := msgs[0].(OutputBatchMsg)
+	if !ok || len(lines) != 2 || lines[0] != "one" || lines[1] != "two" {
+		t.Errorf("msgs[0] = %#v, want OutputBatchMsg{one two}", msgs[0])
+	}
+	stats, ok := msgs[1].(StatsMsg)
+	if !ok || stats.TokensIn != 20 {
+		t.Errorf("msgs[1] = %#v, want latest StatsMsg", msgs[1])
+	}
+
+	if again := batch.drain(); len(again) != 0 {
+		t.Errorf("drain() after reset returned %d messages, want 0", len(again))
+	}
+}
+
+func TestOutputBatch_DropsToSummaryUnderOverload(t *testing.T) {
+	var batch outputBatch
+	total := maxBatchLines + 50
+	for i := 0; i < total; i++ {
+		batch.addLine(fmt.Sprintf("line %d", i))
+	}
+
+	msgs := batch.drain()
+	lines := msgs[0].(OutputBatchMsg)
+
+	if len(lines) != maxBatchLines+1 {
+		t.Fatalf("batch has %d lines, want %d", len(lines), maxBatchLines+1)
+	}
+	if !strings.Contains(lines[0], "50 lines skipped") {
+		t.Errorf("summary line = %q, want skipped count", lines[0])
+	}
+	if lines[len(lines)-1] != fmt.Sprintf("line %d", total-1) {
+		t.Errorf("last line = %q, want most recent line kept", lines[len(lines)-1])
+	}
+}
+
+// recordingSender collects messages delivered by the bridge pump.
+type recordingSender struct {
+	mu   sync.Mutex
+	msgs []tea.Msg
+}
+
+func (r *recordingSender) send(msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func (r *recordingSender) messages() []tea.Msg {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]tea.Msg(nil), r.msgs...)
+}
+
+func TestBridge_CoalescesOutputLines(t *testing.T) {
+	rec := &recordingSender{}
+	// Long interval so all lines land in the same flush on Close
+	bridge := newBridge(rec.send, NewTaskTracker(), time.Hour)
+
+	for i := 0; i < 5; i++ {
+		bridge.sendMsg(OutputLineMsg(fmt.Sprintf("line %d", i)))
+	}
+	bridge.Close()
+
+	msgs := rec.messages()
+	if len(msgs) != 1 {
+		t.Fatalf("got %d messages, want 1 coalesced batch", len(msgs))
+	}
+	if batch, ok := msgs[0].(OutputBatchMsg); !ok || len(batch) != 5 {
+		t.Errorf("msgs[0] = %#v, want OutputBatchMsg with 5 lines", msgs[0])
+	}
+}
+
+func TestBridge_FlushesBeforeOtherMessages(t *testing.T) {
+	rec := &recordingSender{}
+	bridge := newBridge(rec.send, NewTaskTracker(), time.Hour)
+
+	bridge.sendMsg(OutputLineMsg("before"))
+	bridge.sendMsg(TasksMsg{})
+	bridge.sendMsg(OutputLineMsg("after"))
+	bridge.Close()
+
+	msgs := rec.messages()
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want 3", len(msgs))
+	}
+	if _, ok := msgs[0].(OutputBatchMsg); !ok {
+		t.Errorf("msgs[0] = %T, want OutputBatchMsg", msgs[0])
+	}
+	if _, ok := msgs[1].(TasksMsg); !ok {
+		t.Errorf("msgs[1] = %T, want TasksMsg", msgs[1])
+	}
+	if _, ok := msgs[2].(OutputBatchMsg); !ok {
+		t.Errorf("msgs[2] = %T, want OutputBatchMsg", msgs[2])
+	}
+}
+
+func TestBridge_FlushesOnInterval(t *testing.T) {
+	rec := &recordingSender{}
+	bridge := newBridge(rec.send, NewTaskTracker(), 10*time.Millisecond)
+	defer bridge.Close()
+
+	bridge.sendMsg(OutputLineMsg("tick"))
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if len(rec.messages()) > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("output was not flushed within the flush interval")
+}
+
+func TestOutputBatch_SendsLatestPartialLine(t *testing.T) {
+	var batch outputBatch
+	batch.setPartial("Hel")
+	batch.setPartial("Hello wor")
+	msgs := batch.drain()
+	if len(msgs) != 1 || msgs[0] != OutputPartialMsg("Hello wor") {
+		t.Errorf("drain() = %#v, want only the latest partial line", msgs)
+	}
+
+	// A line completes the partial line before it
+	batch.setPartial("Hello wor")
+	batch.addLine("Hello world")
+	batch.setPartial("Sec")
+	msgs = batch.drain()
+	if len(msgs) != 2 || msgs[1] != OutputPartialMsg("Sec") {
+		t.Fatalf("drain() = %#v, want the line then the new partial line", msgs)
+	}
+	if lines, ok := msgs[0].(OutputBatchMsg); !ok || len(lines) != 1 || lines[0] != "Hello world" {
+		t.Errorf("msgs[0] = %#v, want the completed line", msgs[0])
+	}
+
+	batch.setPartial("Sec")
+	batch.addLine("Second")
+	msgs = batch.drain()
+	if len(msgs) != 1 {
+		t.Errorf("drain() = %#v, want the line without the partial line it completed", msgs)
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// maxSparkline is how many of the latest iteration durations the sparkline shows.
+const maxSparkline = 12
+
+// slowIterationFactor is how many times the average of the previous
+// iterations the current one may take before its elapsed time is highlighted.
+const slowIterationFactor = 1.5
+
+// Sparkline renders the latest durations as bars scaled to the longest, e.g.
+// "▂▃█▅". Empty without durations.
+func Sparkline(durations []time.Duration) string {
+	if len(durations) > maxSparkline {
+		durations = durations[len(durations)-maxSparkline:]
+	}
+	var longest time.Duration
+	for _, d := range durations {
+		longest = max(longest, d)
+	}
+	var b strings.Builder
+	for _, d := range durations {
+		level := 0
+		if longest > 0 {
+			level = int(float64(d) / float64(longest) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// averageDuration returns the mean of durations, or 0 without any.
+func averageDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// formatElapsed formats a duration as "Xm Ys", like the countdown timer.
+func formatElapsed(d time.Duration) string {
+	d = max(d, 0)
+	return util.IntToString(int(d.Minutes())) + "m " + util.IntToString(int(d.Seconds())%60) + "s"
+}
+
+// formatIterationHistory formats the current iteration's elapsed time and
+// the durations of the previous iterations, e.g. "Elapsed: 4m 12s │ History:
+// ▂▃█▅ avg 2m 40s". The elapsed time is highlighted once the iteration has
+// taken well over the average. Returns empty string before the first
+// iteration starts.
+func (m Model) formatIterationHistory() string {
+	p := m.progress
+	var parts []string
+	avg := averageDuration(p.IterationDurations)
+	if !p.IterationBegan.IsZero() {
+		elapsed := m.now().Sub(p.IterationBegan)
+		value := m.styles.Value.Render(formatElapsed(elapsed))
+		if avg > 0 && float64(elapsed) > slowIterationFactor*float64(avg) {
+			value = m.styles.Warning.Render(formatElapsed(elapsed))
+		}
+		parts = append(parts, m.styles.Label.Render("Elapsed: ")+value)
+	}
+	if len(p.IterationDurations) > 0 {
+		parts = append(parts, m.styles.Label.Render("History: ")+m.styles.Value.Render(Sparkline(p.IterationDurations))+
+			m.styles.Label.Render(" avg ")+m.styles.Value.Render(formatElapsed(avg)))
+	}
+	return strings.Join(parts, " "+InnerVertical+" ")
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      string
+	}{
+		{name: "empty", durations: nil, want: ""},
+		{name: "scaled to longest", durations: []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}, want: "▂▄█"},
+		{name: "all zero", durations: []time.Duration{0, 0}, want: "▁▁"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.durations); got != tt.want {
+				t.Errorf("Sparkline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparkline_ShowsLatest(t *testing.T) {
+	durations := make([]time.Duration, maxSparkline+5)
+	for i := range durations {
+		durations[i] = time.Second
+	}
+	if got := len([]rune(Sparkline(durations))); got != maxSparkline {
+		t.Errorf("Sparkline() has %d bars, want %d", got, maxSparkline)
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "0m 0s"},
+		{d: 4*time.Minute + 12*time.Second, want: "4m 12s"},
+		{d: -time.Second, want: "0m 0s"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatIterationHistory(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := NewModel()
+	m.SetClock(func() time.Time { return now })
+
+	if got := m.formatIterationHistory(); got != "" {
+		t.Errorf("formatIterationHistory() before any iteration = %q, want empty", got)
+	}
+
+	m.progress.IterationBegan = now.Add(-90 * time.Second)
+	m.progress.IterationDurations = []time.Duration{time.Minute, 3 * time.Minute}
+	got := ansi.Strip(m.formatIterationHistory())
+	want := "Elapsed: 1m 30s " + InnerVertical + " History: ▃█ avg 2m 0s"
+	if got != want {
+		t.Errorf("formatIterationHistory() = %q, want %q", got, want)
+	}
+
+	// The first iteration has no history yet
+	m.progress.IterationDurations = nil
+	if got := ansi.Strip(m.formatIterationHistory()); got != "Elapsed: 1m 30s" {
+		t.Errorf("formatIterationHistory() = %q, want elapsed time only", got)
+	}
+}
+
+func TestRenderProgressPanel_ShowsIterationHistory(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := NewModel()
+	m.SetClock(func() time.Time { return now })
+	m.layout = CalculateLayout(160, 40, 0)
+	m.progress = ProgressInfo{
+		Iteration:          3,
+		MaxIteration:       10,
+		ContextWindow:      200000,
+		IterationBegan:     now.Add(-30 * time.Second),
+		IterationDurations: []time.Duration{time.Minute, 2 * time.Minute},
+	}
+	panel := ansi.Strip(m.renderProgressPanel())
+	if !strings.Contains(panel, "Elapsed: 0m 30s") || !strings.Contains(panel, "History: ▄█") {
+		t.Errorf("progress panel missing iteration history:\n%s", panel)
+	}
+}
@@ -1,12 +1,21 @@
 // Package tui provides the terminal user interface for orbit using bubbletea.
 package tui
 
+import (
+	"fmt"
+	"slices"
+)
+
 // MinTerminalWidth is the minimum supported terminal width.
 const MinTerminalWidth = 80
 
 // MinTerminalHeight is the minimum supported terminal height.
 const MinTerminalHeight = 24
 
+// SplitMinWidth is the minimum terminal width for the split layout, which
+// shows the output and a spec/notes file side by side.
+const SplitMinWidth = 160
+
 // Panel heights (number of lines)
 const (
 	// HeaderPanelHeight is the height of the header panel (brand + metrics).
@@ -33,6 +42,87 @@ const (
 	BorderHeight = 6
 )
 
+// DefaultMinOutputRows is the fewest rows the output area keeps before the
+// task panel is collapsed to make room.
+const DefaultMinOutputRows = 4
+
+// Panels that LayoutConfig.Hide can hide.
+const (
+	// PanelTasks is the task list panel.
+	PanelTasks = "tasks"
+
+	// PanelSession is the session info panel (spec, notes, state paths).
+	PanelSession = "session"
+)
+
+// LayoutConfig holds the layout knobs from the [tui] config section. The
+// zero value is the default layout.
+type LayoutConfig struct {
+	// TaskRows is the most tasks the task panel shows before scrolling
+	// (default: TaskPanelMaxHeight).
+	TaskRows int
+
+	// MinOutputRows is the fewest rows the output area keeps; the task
+	// panel is collapsed below it (default: DefaultMinOutputRows).
+	MinOutputRows int
+
+	// BarWidth is the width of the progress bars (default: BarWidth).
+	BarWidth int
+
+	// Hide lists panels that are never shown: PanelTasks, PanelSession.
+	Hide []string
+}
+
+// Validate checks the knobs are in range and the hidden panels exist.
+func (c LayoutConfig) Validate() error {
+	if c.TaskRows < 0 {
+		return fmt.Errorf("invalid task rows %d: cannot be negative", c.TaskRows)
+	}
+	if c.MinOutputRows < 0 {
+		return fmt.Errorf("invalid minimum output rows %d: cannot be negative", c.MinOutputRows)
+	}
+	if c.BarWidth < 0 || c.BarWidth > MaxBarWidth {
+		return fmt.Errorf("invalid bar width %d: must be between 1 and %d", c.BarWidth, MaxBarWidth)
+	}
+	for _, panel := range c.Hide {
+		switch panel {
+		case PanelTasks, PanelSession:
+		default:
+			return fmt.Errorf("unknown panel %q to hide: must be %s or %s", panel, PanelTasks, PanelSession)
+		}
+	}
+	return nil
+}
+
+// hides reports whether panel is hidden.
+func (c LayoutConfig) hides(panel string) bool {
+	return slices.Contains(c.Hide, panel)
+}
+
+// taskRows returns the configured task rows or the default.
+func (c LayoutConfig) taskRows() int {
+	if c.TaskRows > 0 {
+		return c.TaskRows
+	}
+	return TaskPanelMaxHeight
+}
+
+// minOutputRows returns the configured minimum output rows or the default.
+func (c LayoutConfig) minOutputRows() int {
+	if c.MinOutputRows > 0 {
+		return c.MinOutputRows
+	}
+	return DefaultMinOutputRows
+}
+
+// barWidth returns the configured progress bar width or the default.
+func (c LayoutConfig) barWidth() int {
+	if c.BarWidth > 0 {
+		return c.BarWidth
+	}
+	return BarWidth
+}
+
 // Layout represents the calculated dimensions for each UI region.
 type Layout struct {
 	// Total terminal dimensions
@@ -48,18 +138,29 @@ type Layout struct {
 	// ScrollArea is the output region at the top
 	ScrollAreaHeight int
 
-	// TaskPanel is the task list region (variable height, max 6)
+	// TaskPanel is the task list region (variable height, max 6 by default)
 	TaskPanelHeight int
 
 	// ProgressPanel is the metrics region
 	ProgressPanelHeight int
 
-	// SessionPanel is the file paths region
+	// SessionPanel is the file paths region (zero when hidden)
 	SessionPanelHeight int
 
+	// BarWidth is the width of the progress bars
+	BarWidth int
+
 	// HelpBar is the help text region at the bottom (outside main frame)
 	HelpBarHeight int
 
+	// SplitAvailable indicates the terminal is wide enough for the split layout
+	SplitAvailable bool
+
+	// SplitLeftWidth and SplitRightWidth are the content widths of the output
+	// and file panes in the split layout (zero when unavailable)
+	SplitLeftWidth  int
+	SplitRightWidth int
+
 	// TooSmall indicates the terminal is below minimum size
 	TooSmall bool
 
@@ -67,8 +168,14 @@ type Layout struct {
 	TooSmallMessage string
 }
 
-// CalculateLayout computes the layout based on terminal dimensions and task count.
+// CalculateLayout computes the default layout based on terminal dimensions
+// and task count.
 func CalculateLayout(width, height, taskCount int) Layout {
+	return LayoutConfig{}.Calculate(width, height, taskCount)
+}
+
+// Calculate computes the layout based on terminal dimensions and task count.
+func (c LayoutConfig) Calculate(width, height, taskCount int) Layout {
 	layout := Layout{
 		Width:               width,
 		Height:              height,
@@ -77,6 +184,7 @@ func CalculateLayout(width, height, taskCount int) Layout {
 		ProgressPanelHeight: ProgressPanelHeight,
 		SessionPanelHeight:  SessionPanelHeight,
 		HelpBarHeight:       HelpBarHeight,
+		BarWidth:            c.barWidth(),
 	}
 
 	// Check minimum width
@@ -94,12 +202,12 @@ func CalculateLayout(width, height, taskCount int) Layout {
 	}
 
 	// Calculate task panel height (variable, 0 to max)
-	if taskCount == 0 {
+	if taskCount == 0 || c.hides(PanelTasks) {
 		layout.TaskPanelHeight = 0
-	} else if taskCount <= TaskPanelMaxHeight {
+	} else if taskCount <= c.taskRows() {
 		layout.TaskPanelHeight = taskCount + 1 // +1 for header
 	} else {
-		layout.TaskPanelHeight = TaskPanelMaxHeight + 1 // +1 for header with scroll indicator
+		layout.TaskPanelHeight = c.taskRows() + 1 // +1 for header with scroll indicator
 	}
 
 	// Calculate fixed panel total
@@ -108,17 +216,20 @@ func CalculateLayout(width, height, taskCount int) Layout {
 	if layout.TaskPanelHeight > 0 {
 		borderCount++
 	}
+	// A hidden session panel takes its border with it
+	if c.hides(PanelSession) {
+		layout.SessionPanelHeight = 0
+		borderCount--
+	}
 	fixedHeight := layout.HeaderPanelHeight + layout.TabBarHeight + layout.TaskPanelHeight + layout.ProgressPanelHeight + layout.SessionPanelHeight + layout.HelpBarHeight + borderCount
 
 	// Remaining space goes to scroll area
 	layout.ScrollAreaHeight = height - fixedHeight
 
 	// If scroll area would be too small, collapse task panel
-	if layout.ScrollAreaHeight < 4 && layout.TaskPanelHeight > 0 {
+	if layout.ScrollAreaHeight < c.minOutputRows() && layout.TaskPanelHeight > 0 {
+		layout.ScrollAreaHeight += layout.TaskPanelHeight + 1 // and its border
 		layout.TaskPanelHeight = 0
-		borderCount-- // Remove task panel border
-		fixedHeight = layout.HeaderPanelHeight + layout.TabBarHeight + layout.ProgressPanelHeight + layout.SessionPanelHeight + layout.HelpBarHeight + borderCount
-		layout.ScrollAreaHeight = height - fixedHeight
 	}
 
 	// Final check: if still too cramped, mark as too small
@@ -128,6 +239,14 @@ func CalculateLayout(width, height, taskCount int) Layout {
 		return layout
 	}
 
+	// Split layout: left border + output + divider + file + right border
+	if width >= SplitMinWidth {
+		paneWidth := width - 3
+		layout.SplitAvailable = true
+		layout.SplitLeftWidth = paneWidth / 2
+		layout.SplitRightWidth = paneWidth - layout.SplitLeftWidth
+	}
+
 	return layout
 }
 
@@ -112,6 +112,36 @@ func TestCalculateLayout(t *testing.T) {
 	}
 }
 
+func TestCalculateLayoutSplit(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     int
+		wantSplit bool
+		wantLeft  int
+		wantRight int
+	}{
+		{name: "below split width", width: 159, wantSplit: false},
+		{name: "at split width", width: 160, wantSplit: true, wantLeft: 78, wantRight: 79},
+		{name: "wide terminal", width: 203, wantSplit: true, wantLeft: 100, wantRight: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := CalculateLayout(tt.width, 40, 0)
+
+			if layout.SplitAvailable != tt.wantSplit {
+				t.Fatalf("SplitAvailable = %v, want %v", layout.SplitAvailable, tt.wantSplit)
+			}
+			if layout.SplitLeftWidth != tt.wantLeft || layout.SplitRightWidth != tt.wantRight {
+				t.Errorf("split widths = %d/%d, want %d/%d", layout.SplitLeftWidth, layout.SplitRightWidth, tt.wantLeft, tt.wantRight)
+			}
+			if tt.wantSplit && layout.SplitLeftWidth+layout.SplitRightWidth+3 != tt.width {
+				t.Errorf("split widths plus borders = %d, want %d", layout.SplitLeftWidth+layout.SplitRightWidth+3, tt.width)
+			}
+		})
+	}
+}
+
 func TestLayoutContentWidth(t *testing.T) {
 	layout := CalculateLayout(100, 40, 0)
 	if layout.ContentWidth() != 98 {
@@ -164,3 +194,117 @@ func TestLayoutHasTaskOverflow(t *testing.T) {
 		})
 	}
 }
+
+func TestLayoutConfigCalculate(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           LayoutConfig
+		height           int
+		taskCount        int
+		wantScrollHeight int
+		wantTaskHeight   int
+		wantSession      int
+		wantBarWidth     int
+	}{
+		{
+			name:             "zero value is the default layout",
+			height:           40,
+			taskCount:        3,
+			wantScrollHeight: 21,
+			wantTaskHeight:   4,
+			wantSession:      SessionPanelHeight,
+			wantBarWidth:     BarWidth,
+		},
+		{
+			name:             "fewer task rows",
+			config:           LayoutConfig{TaskRows: 2},
+			height:           40,
+			taskCount:        10,
+			wantScrollHeight: 22, // 40 - (1 + 1 + 3 + 3 + 2 + 1 + 6 + 1)
+			wantTaskHeight:   3,
+			wantSession:      SessionPanelHeight,
+			wantBarWidth:     BarWidth,
+		},
+		{
+			name:             "hidden tasks",
+			config:           LayoutConfig{Hide: []string{PanelTasks}},
+			height:           40,
+			taskCount:        3,
+			wantScrollHeight: 26,
+			wantTaskHeight:   0,
+			wantSession:      SessionPanelHeight,
+			wantBarWidth:     BarWidth,
+		},
+		{
+			name:             "hidden session gives its rows and border to the output",
+			config:           LayoutConfig{Hide: []string{PanelSession}},
+			height:           40,
+			taskCount:        0,
+			wantScrollHeight: 29,
+			wantTaskHeight:   0,
+			wantSession:      0,
+			wantBarWidth:     BarWidth,
+		},
+		{
+			name:             "minimum output rows collapse the task panel",
+			config:           LayoutConfig{MinOutputRows: 22},
+			height:           40,
+			taskCount:        3,
+			wantScrollHeight: 26,
+			wantTaskHeight:   0,
+			wantSession:      SessionPanelHeight,
+			wantBarWidth:     BarWidth,
+		},
+		{
+			name:             "bar width",
+			config:           LayoutConfig{BarWidth: 10},
+			height:           40,
+			wantScrollHeight: 26,
+			wantSession:      SessionPanelHeight,
+			wantBarWidth:     10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := tt.config.Calculate(120, tt.height, tt.taskCount)
+			if layout.TooSmall {
+				t.Fatalf("TooSmall = true: %s", layout.TooSmallMessage)
+			}
+			if layout.ScrollAreaHeight != tt.wantScrollHeight {
+				t.Errorf("ScrollAreaHeight = %d, want %d", layout.ScrollAreaHeight, tt.wantScrollHeight)
+			}
+			if layout.TaskPanelHeight != tt.wantTaskHeight {
+				t.Errorf("TaskPanelHeight = %d, want %d", layout.TaskPanelHeight, tt.wantTaskHeight)
+			}
+			if layout.SessionPanelHeight != tt.wantSession {
+				t.Errorf("SessionPanelHeight = %d, want %d", layout.SessionPanelHeight, tt.wantSession)
+			}
+			if layout.BarWidth != tt.wantBarWidth {
+				t.Errorf("BarWidth = %d, want %d", layout.BarWidth, tt.wantBarWidth)
+			}
+		})
+	}
+}
+
+func TestLayoutConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LayoutConfig
+		wantErr bool
+	}{
+		{name: "zero value", config: LayoutConfig{}, wantErr: false},
+		{name: "all knobs", config: LayoutConfig{TaskRows: 3, MinOutputRows: 8, BarWidth: 30, Hide: []string{PanelTasks, PanelSession}}, wantErr: false},
+		{name: "negative task rows", config: LayoutConfig{TaskRows: -1}, wantErr: true},
+		{name: "negative output rows", config: LayoutConfig{MinOutputRows: -1}, wantErr: true},
+		{name: "bar too wide", config: LayoutConfig{BarWidth: MaxBarWidth + 1}, wantErr: true},
+		{name: "unknown panel", config: LayoutConfig{Hide: []string{"progress"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
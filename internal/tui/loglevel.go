@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fatih/color"
+)
+
+// maxDebugLineRunes bounds a debug line, since raw stream-json lines can be
+// megabytes long.
+const maxDebugLineRunes = 1000
+
+// LogLevel is how much detail the output pane shows. The model toggles it
+// with `d`; the bridge reads it to drop debug lines before they are queued,
+// so a noisy stream cannot crowd ordinary output out of the message queue.
+// A nil LogLevel never shows debug detail.
+type LogLevel struct {
+	debug atomic.Bool
+}
+
+// Debug reports whether debug detail is shown.
+func (l *LogLevel) Debug() bool {
+	return l != nil && l.debug.Load()
+}
+
+// SetDebug turns debug detail on or off.
+func (l *LogLevel) SetDebug(on bool) {
+	if l != nil {
+		l.debug.Store(on)
+	}
+}
+
+// toggleDebug turns debug detail on or off and notes the change in the
+// output. Only lines received afterwards are affected.
+func (m Model) toggleDebug() (tea.Model, tea.Cmd) {
+	on := !m.logLevel.Debug()
+	m.logLevel.SetDebug(on)
+	note := "⚙ debug detail off"
+	if on {
+		note = "⚙ debug detail on: unparsed lines, unhandled events and claude's stderr"
+	}
+	m.outputLines.Push(color.New(color.Faint).Sprint(note))
+	m.syncViewportContent()
+	return m, nil
+}
+
+// sendDebug queues line for the output pane when debug detail is on.
+func (b *Bridge) sendDebug(line string) {
+	if b.level.Debug() {
+		b.sendMsg(OutputLineMsg(line))
+	}
+}
+
+// formatUnparsed formats a stream line that is not a JSON event.
+func formatUnparsed(line string) string {
+	return color.New(color.FgYellow).Sprint("  ⚠ unparsed: ") + color.New(color.Faint).Sprint(truncateDebug(line))
+}
+
+// formatUnhandled formats the raw JSON of an event type the bridge does not
+// display.
+func formatUnhandled(eventType, line string) string {
+	return color.New(color.FgMagenta).Sprintf("  ? [%s] ", eventType) + color.New(color.Faint).Sprint(truncateDebug(line))
+}
+
+// truncateDebug cuts s to maxDebugLineRunes.
+func truncateDebug(s string) string {
+	if len(s) <= maxDebugLineRunes {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= maxDebugLineRunes {
+		return s
+	}
+	return string(r[:maxDebugLineRunes]) + "…"
+}
+
+// Stderr returns a writer for claude's stderr. Each complete line is shown
+// as debug detail.
+func (b *Bridge) Stderr() *StderrWriter {
+	return &StderrWriter{bridge: b}
+}
+
+// StderrWriter shows the lines written to it as debug detail in the TUI.
+// A line split across writes is held until its newline arrives.
+type StderrWriter struct {
+	bridge *Bridge
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+// Write implements io.Writer.
+func (w *StderrWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.send(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	// Never hold more than a debug line's worth of a line without a newline
+	if len(w.partial) > 4*maxDebugLineRunes {
+		w.send(string(w.partial))
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+// send shows one stderr line.
+func (w *StderrWriter) send(line string) {
+	line = strings.TrimRight(line, "\r ")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.bridge.sendDebug(color.New(color.FgRed).Sprint("  stderr: ") + color.New(color.Faint).Sprint(truncateDebug(line)))
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// debugLines writes stream lines and stderr to a bridge at the given level
+// and returns the output lines it queued.
+func debugLines(t *testing.T, level *LogLevel, stream, stderr string) []string {
+	t.Helper()
+	rec := &recordingSender{}
+	bridge := newBridge(rec.send, NewTaskTracker(), time.Hour)
+	bridge.level = level
+
+	_, _ = bridge.Write([]byte(stream))
+	w := bridge.Stderr()
+	_, _ = w.Write([]byte(stderr[:len(stderr)/2]))
+	_, _ = w.Write([]byte(stderr[len(stderr)/2:]))
+	bridge.Close()
+
+	var lines []string
+	for _, msg := range rec.messages() {
+		if batch, ok := msg.(OutputBatchMsg); ok {
+			lines = append(lines, batch...)
+		}
+	}
+	return lines
+}
+
+func TestBridge_DebugDetail(t *testing.T) {
+	stream := "not json\n" +
+		`{"type":"rate_limit_event","retry_after":3}` + "\n" +
+		`{"type":"system","subtype":"init","content":"ready"}` + "\n"
+	stderr := "warning: slow network\n"
+
+	off := strings.Join(debugLines(t, &LogLevel{}, stream, stderr), "\n")
+	for _, detail := range []string{"unparsed", "rate_limit_event", "stderr"} {
+		if strings.Contains(off, detail) {
+			t.Errorf("debug off: output contains %q:\n%s", detail, off)
+		}
+	}
+
+	level := &LogLevel{}
+	level.SetDebug(true)
+	on := strings.Join(debugLines(t, level, stream, stderr), "\n")
+	for _, detail := range []string{"unparsed: not json", `? [rate_limit_event] {"type":"rate_limit_event"`, "stderr: warning: slow network"} {
+		if !strings.Contains(on, detail) {
+			t.Errorf("debug on: output lacks %q:\n%s", detail, on)
+		}
+	}
+	if strings.Contains(on, "[system]") {
+		t.Errorf("debug on: displayed event reported as unhandled:\n%s", on)
+	}
+}
+
+func TestLogLevel_Nil(t *testing.T) {
+	var l *LogLevel
+	l.SetDebug(true)
+	if l.Debug() {
+		t.Error("nil LogLevel reports debug on")
+	}
+}
+
+func TestTruncateDebug(t *testing.T) {
+	long := strings.Repeat("é", maxDebugLineRunes+5)
+	got := truncateDebug(long)
+	if !strings.HasSuffix(got, "…") || len([]rune(got)) != maxDebugLineRunes+1 {
+		t.Errorf("truncateDebug() kept %d runes, want %d plus an ellipsis", len([]rune(got)), maxDebugLineRunes)
+	}
+	if truncateDebug("short") != "short" {
+		t.Error("truncateDebug() changed a short line")
+	}
+}
+
+func TestModel_ToggleDebug(t *testing.T) {
+	m := chatModel(nil)
+	if !strings.Contains(m.View(), "d debug") {
+		t.Error("help bar does not offer debug detail")
+	}
+
+	m = typeKeys(m, "d")
+	if !m.logLevel.Debug() {
+		t.Fatal("expected d to turn debug detail on")
+	}
+	view := m.View()
+	if !strings.Contains(view, "debug detail on") || !strings.Contains(view, "d hide debug") {
+		t.Errorf("view does not show debug detail is on:\n%s", view)
+	}
+
+	m = typeKeys(m, "d")
+	if m.logLevel.Debug() || !strings.Contains(m.View(), "debug detail off") {
+		t.Error("expected d to turn debug detail off again")
+	}
+}
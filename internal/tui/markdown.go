@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fatih/color"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+)
+
+// TextMode is how assistant text is shown in the output pane: rendered as
+// markdown (the default) or as the raw text Claude wrote. The model toggles
+// it with `p`; the bridge reads it as text arrives. A nil TextMode renders
+// markdown.
+type TextMode struct {
+	plain atomic.Bool
+}
+
+// Plain reports whether assistant text is shown raw.
+func (t *TextMode) Plain() bool {
+	return t != nil && t.plain.Load()
+}
+
+// SetPlain switches between raw and rendered assistant text.
+func (t *TextMode) SetPlain(on bool) {
+	if t != nil {
+		t.plain.Store(on)
+	}
+}
+
+// togglePlain switches assistant text between markdown and raw and notes the
+// change in the output. Only text received afterwards is affected.
+func (m Model) togglePlain() (tea.Model, tea.Cmd) {
+	plain := !m.textMode.Plain()
+	m.textMode.SetPlain(plain)
+	note := "⚙ assistant text rendered as markdown"
+	if plain {
+		note = "⚙ assistant text shown as plain text"
+	}
+	m.outputLines.Push(color.New(color.Faint).Sprint(note))
+	m.syncViewportContent()
+	return m, nil
+}
+
+var (
+	// headingPattern matches an ATX heading: "## Title".
+	headingPattern = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	// listPattern matches a list item: its indent, marker and text.
+	listPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	// rulePattern matches a thematic break: "---", "***" or "___".
+	rulePattern = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+)
+
+// markdownRenderer renders the markdown of assistant text line by line:
+// headings and **bold** in bold, `code` and fenced code blocks on a
+// background, and list bullets as •. It keeps whether a code fence is open
+// between calls, so text streamed in chunks renders like a whole block.
+// It is not safe for concurrent use; the bridge calls it under its lock.
+type markdownRenderer struct {
+	inFence bool
+	pending string // streamed text after the last complete line
+
+	redactor *redact.Redactor // masks secrets split across streamed chunks (may be nil)
+}
+
+// Render renders a complete block of text.
+func (r *markdownRenderer) Render(block string) string {
+	r.pending = ""
+	out := r.renderLines(block)
+	r.inFence = false
+	return out
+}
+
+// Feed renders the complete lines of a streamed chunk, holding a trailing
+// partial line until the rest of it arrives. It returns "" when no line is
+// complete yet.
+func (r *markdownRenderer) Feed(chunk string) string {
+	text := r.pending + chunk
+	i := strings.LastIndexByte(text, '\n')
+	if i < 0 {
+		r.pending = text
+		return ""
+	}
+	r.pending = text[i+1:]
+	return r.renderLines(r.redactor.Mask(text[:i]))
+}
+
+// Partial renders the partial line Feed is holding as it stands, without
+// ending it, so the line can be shown while the rest of it streams in.
+func (r *markdownRenderer) Partial() string {
+	line := maskPartial(r.redactor, r.pending)
+	if line == "" {
+		return ""
+	}
+	if r.inFence {
+		return mdCode.Sprint(" " + strings.ReplaceAll(line, "\t", "    ") + " ")
+	}
+	return renderInline(line)
+}
+
+// Flush renders any held partial line and ends the block.
+func (r *markdownRenderer) Flush() string {
+	var out string
+	if r.pending != "" {
+		out = r.renderLines(r.redactor.Mask(r.pending))
+	}
+	r.pending = ""
+	r.inFence = false
+	return out
+}
+
+// renderLines renders each line of text.
+func (r *markdownRenderer) renderLines(text string) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if rendered, ok := r.renderLine(line); ok {
+			out = append(out, rendered)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderLine renders one line. It reports false for lines that are not
+// shown, such as the closing fence of a code block.
+func (r *markdownRenderer) renderLine(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		r.inFence = !r.inFence
+		lang := strings.TrimSpace(strings.Trim(trimmed, "`~"))
+		if r.inFence && lang != "" {
+			return color.New(color.Faint).Sprint(lang), true
+		}
+		return "", false
+	}
+	if r.inFence {
+		return mdCode.Sprint(" " + strings.ReplaceAll(line, "\t", "    ") + " "), true
+	}
+
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		return mdBold.Sprint(m[1]), true
+	}
+	if rulePattern.MatchString(line) {
+		return color.New(color.Faint).Sprint(strings.Repeat("─", 20)), true
+	}
+	if m := listPattern.FindStringSubmatch(line); m != nil {
+		marker := m[2]
+		if marker == "-" || marker == "*" || marker == "+" {
+			marker = "•"
+		}
+		return m[1] + mdText.Sprint(marker+" ") + renderInline(m[3]), true
+	}
+	if quote, ok := strings.CutPrefix(trimmed, ">"); ok {
+		return color.New(color.Faint).Sprint("│ ") + renderInline(strings.TrimSpace(quote)), true
+	}
+	return renderInline(line), true
+}
+
+var (
+	mdText = color.New(color.FgYellow)
+	mdBold = color.New(color.FgYellow, color.Bold)
+	mdCode = color.New(color.FgHiWhite, color.BgHiBlack)
+)
+
+// renderInline renders **bold** and `code` spans. Unclosed markers are
+// shown as written.
+func renderInline(s string) string {
+	var b strings.Builder
+	for s != "" {
+		code := strings.Index(s, "`")
+		bold := strings.Index(s, "**")
+		if code < 0 && bold < 0 {
+			writeText(&b, s)
+			break
+		}
+
+		if code >= 0 && (bold < 0 || code < bold) {
+			end := strings.Index(s[code+1:], "`")
+			if end < 0 {
+				writeText(&b, s)
+				break
+			}
+			writeText(&b, s[:code])
+			b.WriteString(mdCode.Sprint(s[code+1 : code+1+end]))
+			s = s[code+1+end+1:]
+			continue
+		}
+
+		end := strings.Index(s[bold+2:], "**")
+		if end <= 0 {
+			writeText(&b, s)
+			break
+		}
+		writeText(&b, s[:bold])
+		b.WriteString(mdBold.Sprint(s[bold+2 : bold+2+end]))
+		s = s[bold+2+end+2:]
+	}
+	return b.String()
+}
+
+// writeText writes plain assistant text, skipping empty runs so no stray
+// colour codes are left between spans.
+func writeText(b *strings.Builder, s string) {
+	if s != "" {
+		b.WriteString(mdText.Sprint(s))
+	}
+}
@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/flashingpumpkin/orbital/internal/redact"
+)
+
+func TestMarkdownRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "Just some text", "Just some text"},
+		{"heading", "## Plan", "Plan"},
+		{"bullets", "- one\n  * two", "• one\n  • two"},
+		{"numbered list", "1. first\n2) second", "1. first\n2) second"},
+		{"bold and code", "Run **all** the `go test` checks", "Run all the go test checks"},
+		{"unclosed markers", "a ** b ` c", "a ** b ` c"},
+		{"code fence", "Before\n```go\nfunc main() {}\n```\nAfter", "Before\ngo\n func main() {} \nAfter"},
+		{"markdown inside a fence", "```\n- not a bullet\n```", " - not a bullet "},
+		{"blockquote", "> quoted", "│ quoted"},
+		{"rule", "---", strings.Repeat("─", 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r markdownRenderer
+			if got := r.Render(tt.input); got != tt.expected {
+				t.Errorf("Render(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownRenderer_Styles(t *testing.T) {
+	old := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = old }()
+
+	var r markdownRenderer
+	got := r.Render("Run **all** of `go test`")
+	if !strings.Contains(got, mdBold.Sprint("all")) {
+		t.Errorf("bold span not styled: %q", got)
+	}
+	if !strings.Contains(got, mdCode.Sprint("go test")) {
+		t.Errorf("code span not styled: %q", got)
+	}
+	if strings.Contains(got, mdText.Sprint("")) {
+		t.Errorf("empty text run left colour codes: %q", got)
+	}
+}
+
+func TestMarkdownRenderer_Feed(t *testing.T) {
+	var r markdownRenderer
+	var lines []string
+	for _, chunk := range []string{"- fir", "st\n```", "\nco", "de\n", "```\ntail"} {
+		if out := r.Feed(chunk); out != "" {
+			lines = append(lines, out)
+		}
+	}
+	lines = append(lines, r.Flush())
+
+	want := []string{"• first", " code ", "tail"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("streamed lines = %q, want %q", lines, want)
+	}
+	if r.inFence || r.pending != "" {
+		t.Error("Flush() did not reset the renderer")
+	}
+}
+
+// assistantLines writes assistant text events to a bridge in the given text
+// mode and returns the output lines it queued.
+func assistantLines(t *testing.T, mode *TextMode, events ...string) []string {
+	t.Helper()
+	rec := &recordingSender{}
+	bridge := newBridge(rec.send, NewTaskTracker(), time.Hour)
+	bridge.text = mode
+	for _, e := range events {
+		_, _ = bridge.Write([]byte(e + "\n"))
+	}
+	bridge.Close()
+
+	var lines []string
+	for _, msg := range rec.messages() {
+		if batch, ok := msg.(OutputBatchMsg); ok {
+			lines = append(lines, batch...)
+		}
+	}
+	return lines
+}
+
+func textEvent(eventType, text string) string {
+	quoted, _ := json.Marshal(text)
+	if eventType == "content_block_delta" {
+		return `{"type":"content_block_delta","delta":{"text":` + string(quoted) + `}}`
+	}
+	return `{"type":"assistant","message":{"content":[{"type":"text","text":` + string(quoted) + `}]}}`
+}
+
+func TestBridge_MarkdownText(t *testing.T) {
+	block := textEvent("assistant", "## Done\n- **fixed** the `parser`")
+
+	rendered := strings.Join(assistantLines(t, &TextMode{}, block), "\n")
+	if !strings.Contains(rendered, "💭 Done\n• fixed the parser") {
+		t.Errorf("markdown not rendered:\n%s", rendered)
+	}
+
+	plain := &TextMode{}
+	plain.SetPlain(true)
+	raw := strings.Join(assistantLines(t, plain, block), "\n")
+	if !strings.Contains(raw, "💭 ## Done\n- **fixed** the `parser`") {
+		t.Errorf("plain mode changed the text:\n%s", raw)
+	}
+
+	streamed := assistantLines(t, nil,
+		textEvent("content_block_delta", "Step **one"),
+		textEvent("content_block_delta", "** done\n- next"),
+		`{"type":"content_block_stop"}`,
+	)
+	if got := strings.Join(streamed, "|"); got != "\n  💭 Step one done|• next" {
+		t.Errorf("streamed markdown = %q", got)
+	}
+}
+
+func TestModel_TogglePlain(t *testing.T) {
+	m := chatModel(nil)
+	if !strings.Contains(m.View(), "p plain") {
+		t.Error("help bar does not offer plain text")
+	}
+
+	m = typeKeys(m, "p")
+	if !m.textMode.Plain() {
+		t.Fatal("expected p to switch to plain text")
+	}
+	view := m.View()
+	if !strings.Contains(view, "shown as plain text") || !strings.Contains(view, "p markdown") {
+		t.Errorf("view does not show plain text is on:\n%s", view)
+	}
+
+	m = typeKeys(m, "p")
+	if m.textMode.Plain() || !strings.Contains(m.View(), "rendered as markdown") {
+		t.Error("expected p to switch back to markdown")
+	}
+}
+
+func TestBridge_StreamsPartialMessages(t *testing.T) {
+	plain := &TextMode{}
+	plain.SetPlain(true)
+	bridge := newBridge(nil, NewTaskTracker(), time.Hour)
+	bridge.text = plain
+
+	partial := func(event string) string {
+		return `{"type":"stream_event","event":` + event + `}`
+	}
+	delta := func(text string) string {
+		quoted, _ := json.Marshal(text)
+		return partial(`{"type":"content_block_delta","delta":{"type":"text_delta","text":` + string(quoted) + `}}`)
+	}
+	events := []string{
+		partial(`{"type":"content_block_start","content_block":{"type":"text","text":""}}`),
+		delta("Hello wor"),
+		delta("ld\nSecond"),
+		partial(`{"type":"content_block_stop"}`),
+		// The assistant messages repeat what was streamed
+		textEvent("assistant", "Hello world\nSecond"),
+		partial(`{"type":"content_block_start","content_block":{"type":"tool_use","id":"t1","name":"Read","input":{}}}`),
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"main.go"}}]}}`,
+	}
+	for _, e := range events {
+		_, _ = bridge.Write([]byte(e + "\n"))
+	}
+
+	var got []string
+	for len(bridge.msgQueue) > 0 {
+		switch msg := (<-bridge.msgQueue).(type) {
+		case OutputLineMsg:
+			got = append(got, "line:"+string(msg))
+		case OutputPartialMsg:
+			got = append(got, "partial:"+string(msg))
+		}
+	}
+	want := []string{
+		"partial:\n  💭 Hello wor",
+		"line:\n  💭 Hello world",
+		"partial:Second",
+		"line:Second",
+		"line:  → Read main.go",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestBridge_RedactsSecretsSplitAcrossDeltas(t *testing.T) {
+	for _, plain := range []bool{true, false} {
+		mode := &TextMode{}
+		mode.SetPlain(plain)
+		bridge := newBridge(nil, NewTaskTracker(), time.Hour)
+		bridge.text = mode
+		bridge.SetRedactor(redact.New(redact.Builtin))
+
+		delta := func(text string) string {
+			quoted, _ := json.Marshal(text)
+			return `{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":` + string(quoted) + `}}}`
+		}
+		for _, e := range []string{delta("The key is AKIAIOSF"), delta("ODNN7EXAMPLE and more"), delta(" text\nnext")} {
+			_, _ = bridge.Write([]byte(e + "\n"))
+		}
+
+		var shown []string
+		for len(bridge.msgQueue) > 0 {
+			switch msg := (<-bridge.msgQueue).(type) {
+			case OutputLineMsg:
+				shown = append(shown, string(msg))
+			case OutputPartialMsg:
+				shown = append(shown, string(msg))
+			}
+		}
+		got := strings.Join(shown, "|")
+		if strings.Contains(got, "AKIAIOSF") {
+			t.Errorf("plain=%v: secret split across deltas was shown: %q", plain, got)
+		}
+		if !strings.Contains(got, "The key is [REDACTED:aws-access-key] and more text") {
+			t.Errorf("plain=%v: messages = %q, want the joined line masked", plain, got)
+		}
+	}
+}
+
+func TestModel_PartialLine(t *testing.T) {
+	m := chatModel(nil)
+	updated, _ := m.Update(OutputPartialMsg("Hello wor"))
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "Hello wor") {
+		t.Fatal("partial line not shown")
+	}
+
+	updated, _ = m.Update(OutputBatchMsg{"Hello world"})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "Hello world") || strings.Count(view, "Hello wor") != 1 {
+		t.Errorf("completed line did not replace the partial line:\n%s", view)
+	}
+}
@@ -1,8 +1,18 @@
 package tui
 
+import "time"
+
 // OutputLineMsg represents a new formatted output line to display.
 type OutputLineMsg string
 
+// OutputBatchMsg represents several output lines coalesced into one update.
+type OutputBatchMsg []string
+
+// OutputPartialMsg is the output line being streamed in, shown below the
+// output until the complete line arrives as an output line. An empty message
+// clears it.
+type OutputPartialMsg string
+
 // TasksMsg represents an updated task list.
 type TasksMsg []Task
 
@@ -11,3 +21,75 @@ type ProgressMsg ProgressInfo
 
 // SessionMsg represents session information (typically set once at startup).
 type SessionMsg SessionInfo
+
+// GateScoresMsg carries the rubric scores a gate judge reported for a step.
+type GateScoresMsg struct {
+	Step   string
+	Scores map[string]float64
+}
+
+// CheckpointMsg records a named checkpoint taken at the end of an iteration.
+type CheckpointMsg struct {
+	Name      string
+	Iteration int
+}
+
+// InterjectionMsg records an operator interjection picked up at the start of
+// an iteration.
+type InterjectionMsg struct {
+	Iteration int
+	Text      string
+}
+
+// ParseErrorMsg records a malformed stream line quarantined by the parser.
+// Count is the number quarantined so far in the session.
+type ParseErrorMsg struct {
+	Count int
+	Line  string
+}
+
+// EscalationMsg records a failing gate escalating the workflow to a stronger
+// model.
+type EscalationMsg struct {
+	Step     string
+	Failures int
+	Model    string
+}
+
+// GateResultMsg records a gate's verdict (PASS, FAIL or TIMEOUT) and how
+// long it took.
+type GateResultMsg struct {
+	Step     string
+	Result   string
+	Duration time.Duration
+}
+
+// DryStepMsg records a dry workflow step and the prompt it would have sent.
+type DryStepMsg struct {
+	Step   string
+	Prompt string
+}
+
+// BannerMsg shows a warning in place of the help bar until it is dismissed
+// with esc or replaced by another banner.
+type BannerMsg string
+
+// ConfirmMsg asks a yes/no question in place of the help bar. The answer is
+// sent on Reply, which must be buffered. Yes and No label the y and n keys
+// (default: "continue" and "stop").
+type ConfirmMsg struct {
+	Question string
+	Yes      string
+	No       string
+	Reply    chan<- bool
+}
+
+// confirmWithdrawnMsg closes the question answered on Reply, if it is still
+// open, when its asker stops waiting.
+type confirmWithdrawnMsg struct {
+	Reply chan<- bool
+}
+
+// ArtifactsMsg carries artifact files written by workflow steps. Each new
+// path gets a tab.
+type ArtifactsMsg []string
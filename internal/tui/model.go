@@ -1,9 +1,9 @@
 package tui
 
 import (
-	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/flashingpumpkin/orbital/internal/output"
 	"github.com/flashingpumpkin/orbital/internal/tasks"
 	"github.com/flashingpumpkin/orbital/internal/util"
 )
@@ -41,6 +42,8 @@ type SessionInfo struct {
 	NotesFile   string
 	StateFile   string
 	ContextFile string
+	Artifacts   []string // Files written by workflow steps
+	WorkingDir  string   // Project directory; tabs name files outside it by absolute path
 }
 
 // ProgressInfo contains iteration and cost metrics.
@@ -65,6 +68,12 @@ type ProgressInfo struct {
 	// These are used for context window display (per-invocation usage).
 	CurrentIterTokensIn  int
 	CurrentIterTokensOut int
+	// CacheReadTokens is the part of TokensIn read from the prompt cache.
+	CacheReadTokens int
+	// IterationBegan is when the current iteration started, across its steps.
+	IterationBegan time.Time
+	// IterationDurations are the durations of the completed iterations, in order.
+	IterationDurations []time.Duration
 }
 
 // StatsMsg is a message containing updated token and cost statistics.
@@ -76,15 +85,19 @@ type StatsMsg struct {
 	// These are used for context window display (per-invocation usage).
 	CurrentIterTokensIn  int
 	CurrentIterTokensOut int
+	// CacheReadTokens is the part of TokensIn read from the prompt cache.
+	CacheReadTokens int
 }
 
 // Model is the main bubbletea model for the orbit TUI.
 type Model struct {
 	// Layout
-	layout Layout
+	layout       Layout
+	layoutConfig LayoutConfig
 
 	// Content
 	outputLines *RingBuffer     // Ring buffer for bounded memory usage
+	partialLine string          // Line being streamed in, shown after outputLines
 	viewport    viewport.Model  // Viewport for output scrolling
 	tasks       []Task
 	progress    ProgressInfo
@@ -98,11 +111,58 @@ type Model struct {
 	fileModTimes  map[string]time.Time       // Last known modification times per file
 
 	// Output scrolling
-	outputTailing bool // Whether the output window is locked to the bottom (auto-scrolling)
+	outputTailing  bool // Whether the output window is locked to the bottom (auto-scrolling)
+	outputTruncate bool // Whether long output lines are truncated instead of wrapped
+	scrollLock     bool // Whether left/right pan truncated output instead of switching tabs
+	outputHScroll  int  // Columns truncated output is panned to the right
+
+	// Debug detail
+	logLevel *LogLevel // Whether debug detail is shown (toggled with d), shared with the bridge
+
+	// Assistant text
+	textMode *TextMode // Whether assistant text is shown raw instead of as markdown (toggled with p), shared with the bridge
+
+	// Split layout
+	splitView bool // Whether output and a spec/notes file are shown side by side (wide terminals only)
+
+	// Chat pane
+	chat        ChatFunc    // Answers questions about the run (nil disables the pane)
+	chatOpen    bool        // Whether the chat pane replaces the main content area
+	chatInput   string      // Question being typed
+	chatHistory []chatEntry // Questions asked and their answers
+
+	// Task editing
+	taskEditing  bool         // Whether task editing was requested (see WithTaskEditing)
+	taskEdit     TaskEditFunc // Changes a task's status (nil disables editing)
+	tasksFocused bool         // Whether the task panel has the keyboard focus
+	taskCursor   int          // Selected task while the panel has the focus
+
+	// Checkpoint marking
+	checkpoint      func(name string) // Receives checkpoint names (nil disables marking)
+	checkpointOpen  bool              // Whether the checkpoint name is being typed
+	checkpointInput string            // Checkpoint name being typed
+
+	// Warnings shown in place of the help bar
+	banner  string      // Warning shown until dismissed with esc
+	confirm *ConfirmMsg // Question awaiting a y/n answer (nil when none)
+
+	// Timeline scrubber
+	timeline        *EventLog // Persisted output events (nil disables the scrubber)
+	timelineOpen    bool      // Whether the scrubber replaces the main content area
+	timelinePeriods []Period  // Periods captured when the scrubber was opened
+	timelineCursor  int       // Selected period in the list
+	timelineViewing bool      // Whether a period's output is shown instead of the list
+	timelinePeriod  Period    // Period being viewed
+	timelineLines   []string  // Output of the period being viewed
+	timelineErr     error     // Error loading the period being viewed
+	timelineScroll  int       // Scroll offset into the viewed output
 
 	// Styles
 	styles Styles
 
+	// Clock for the iteration timer; replaced in tests so View is deterministic
+	now func() time.Time
+
 	// State
 	ready bool
 }
@@ -125,7 +185,10 @@ func NewModelWithTheme(theme Theme) Model {
 		fileViewports: make(map[string]viewport.Model),
 		fileModTimes:  make(map[string]time.Time),
 		outputTailing: true,
+		logLevel:      &LogLevel{},
+		textMode:      &TextMode{},
 		styles:        GetStyles(theme),
+		now:           time.Now,
 		progress: ProgressInfo{
 			Iteration:    1,
 			MaxIteration: 50,
@@ -213,11 +276,11 @@ func formatFileSize(size int64) string {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.layout = CalculateLayout(msg.Width, msg.Height, len(m.tasks))
+		m.layout = m.layoutConfig.Calculate(msg.Width, msg.Height, len(m.tasks))
 		m.ready = true
 
 		// Update output viewport dimensions
-		m.viewport.Width = m.layout.ContentWidth()
+		m.viewport.Width = m.outputWidth()
 		m.viewport.Height = m.layout.ScrollAreaHeight
 
 		// Rebuild viewport content from ring buffer
@@ -246,20 +309,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progress.Cost = msg.Cost
 		m.progress.CurrentIterTokensIn = msg.CurrentIterTokensIn
 		m.progress.CurrentIterTokensOut = msg.CurrentIterTokensOut
+		m.progress.CacheReadTokens = msg.CacheReadTokens
 		return m, nil
 
 	case OutputLineMsg:
 		m.outputLines.Push(string(msg))
+		m.partialLine = ""
 		m.syncViewportContent()
 		return m, nil
 
-	case TasksMsg:
-		m.tasks = msg
-		if m.ready {
-			m.layout = CalculateLayout(m.layout.Width, m.layout.Height, len(m.tasks))
+	case OutputPartialMsg:
+		m.partialLine = string(msg)
+		m.syncViewportContent()
+		return m, nil
+
+	case BannerMsg:
+		m.banner = string(msg)
+		return m, nil
+
+	case ConfirmMsg:
+		m.answerConfirm(false)
+		m.confirm = &msg
+		return m, nil
+
+	case confirmWithdrawnMsg:
+		if m.confirm != nil && m.confirm.Reply == msg.Reply {
+			m.confirm = nil
+		}
+		return m, nil
+
+	case OutputBatchMsg:
+		for _, line := range msg {
+			m.outputLines.Push(line)
 		}
+		m.partialLine = ""
+		m.syncViewportContent()
+		return m, nil
+
+	case TasksMsg:
+		m.SetTasks(msg)
 		return m, nil
 
+	case taskEditedMsg:
+		m.SetTasks(msg.tasks)
+		line := OutputLineMsg(msg.line)
+		return m, func() tea.Msg { return line }
+
 	case ProgressMsg:
 		m.progress = ProgressInfo(msg)
 		return m, nil
@@ -273,6 +368,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case ArtifactsMsg:
+		added := false
+		for _, path := range msg {
+			if !slices.Contains(m.session.Artifacts, path) {
+				m.session.Artifacts = append(m.session.Artifacts, path)
+				added = true
+			}
+		}
+		if added {
+			m.tabs = m.buildTabs()
+		}
+		return m, nil
+
 	case FileContentMsg:
 		if msg.Error != nil {
 			m.fileContents[msg.Path] = "Error loading file: " + msg.Error.Error()
@@ -291,17 +399,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Schedule next tick
 		cmd := fileRefreshTick()
 
-		// Only check file changes when on a file tab (not Output tab)
-		if m.activeTab > 0 && m.activeTab < len(m.tabs) {
-			tab := m.tabs[m.activeTab]
-			if tab.Type == TabFile && tab.FilePath != "" {
-				// Check if file has been modified
-				if info, err := os.Stat(tab.FilePath); err == nil {
-					lastMod, exists := m.fileModTimes[tab.FilePath]
-					if !exists || info.ModTime().After(lastMod) {
-						// File changed, reload it
-						return m, tea.Batch(cmd, loadFileCmd(tab.FilePath))
-					}
+		// Only check file changes when a file is on screen
+		if path := m.visibleFilePath(); path != "" {
+			// Check if file has been modified
+			if info, err := os.Stat(path); err == nil {
+				lastMod, exists := m.fileModTimes[path]
+				if !exists || info.ModTime().After(lastMod) {
+					// File changed, reload it
+					return m, tea.Batch(cmd, loadFileCmd(path))
 				}
 			}
 		}
@@ -311,10 +416,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Just schedule next tick - the timer display updates on each render
 		return m, timerTick()
 
+	case timelineLoadedMsg:
+		return m.handleTimelineLoaded(msg), nil
+
+	case chatResponseMsg:
+		if n := len(m.chatHistory); n > 0 {
+			m.chatHistory[n-1].Answer = strings.TrimSpace(msg.Answer)
+			m.chatHistory[n-1].Err = msg.Err
+			m.chatHistory[n-1].Pending = false
+		}
+		return m, nil
+
+	case tea.ResumeMsg:
+		return m, resumeCmd()
+
+	case tea.FocusMsg:
+		// Regaining focus includes reattaching under tmux: redraw every line
+		// in case the terminal shows stale content
+		return m, tea.WindowSize()
+
 	case tea.KeyMsg:
+		// In raw mode ctrl+z is a key press, not a signal
+		if msg.Type == tea.KeyCtrlZ {
+			return m, tea.Suspend
+		}
+		if m.confirm != nil {
+			return m.handleConfirmKey(msg)
+		}
+		if m.checkpointOpen {
+			return m.handleCheckpointKey(msg)
+		}
+		if m.tasksFocused {
+			return m.handleTaskKey(msg)
+		}
+		if m.chatOpen {
+			return m.handleChatKey(msg)
+		}
+		if m.timelineOpen {
+			return m.handleTimelineKey(msg)
+		}
+		if m.scrollLock {
+			if updated, cmd, handled := m.handleScrollLockKey(msg); handled {
+				return updated, cmd
+			}
+		}
+		if m.banner != "" && msg.Type == tea.KeyEsc {
+			m.banner = ""
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "c":
+			if m.chat != nil {
+				m.chatOpen = true
+			}
+			return m, nil
+		case "t":
+			if m.timeline != nil {
+				return m.openTimeline()
+			}
+			return m, nil
+		case "T":
+			return m.focusTasks()
+		case "m":
+			if m.checkpoint != nil {
+				m.checkpointOpen = true
+				m.checkpointInput = ""
+			}
+			return m, nil
 		case "left", "h":
 			return m.prevTab()
 		case "right", "l":
@@ -342,6 +512,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleScrollEnd()
 		case "r":
 			return m.reloadCurrentFile()
+		case "s":
+			return m.toggleSplit()
+		case "w":
+			return m.toggleWrap()
+		case "L":
+			return m.toggleScrollLock()
+		case "d":
+			return m.toggleDebug()
+		case "p":
+			return m.togglePlain()
 		}
 
 	case tea.MouseMsg:
@@ -367,7 +547,7 @@ func (m Model) buildTabs() []Tab {
 	// Add spec files
 	for _, path := range m.session.SpecFiles {
 		tabs = append(tabs, Tab{
-			Name:     "Spec: " + filepath.Base(path),
+			Name:     m.fileTabName("Spec: ", path),
 			Type:     TabFile,
 			FilePath: path,
 		})
@@ -389,7 +569,7 @@ func (m Model) buildTabs() []Tab {
 			path = strings.TrimSpace(path)
 			if path != "" {
 				tabs = append(tabs, Tab{
-					Name:     "Ctx: " + filepath.Base(path),
+					Name:     m.fileTabName("Ctx: ", path),
 					Type:     TabFile,
 					FilePath: path,
 				})
@@ -397,9 +577,109 @@ func (m Model) buildTabs() []Tab {
 		}
 	}
 
+	// Add artifacts written by workflow steps
+	for _, path := range m.session.Artifacts {
+		tabs = append(tabs, Tab{
+			Name:     m.fileTabName("Art: ", path),
+			Type:     TabFile,
+			FilePath: path,
+		})
+	}
+
 	return tabs
 }
 
+// maxExternalTabPath is the widest a file tab shows the absolute path of a
+// file outside the working directory; longer paths keep their end.
+const maxExternalTabPath = 40
+
+// fileTabName names the tab of a file: the file name for files in the working
+// directory, and the absolute path for files outside it, so a file from
+// another project is not mistaken for one of the project's own.
+func (m Model) fileTabName(prefix, path string) string {
+	if m.session.WorkingDir == "" {
+		return prefix + filepath.Base(path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return prefix + filepath.Base(path)
+	}
+	dir, err := filepath.Abs(m.session.WorkingDir)
+	if err != nil {
+		return prefix + filepath.Base(path)
+	}
+	rel, err := filepath.Rel(dir, abs)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return prefix + filepath.Base(path)
+	}
+	return prefix + truncateFromStart(abs, maxExternalTabPath)
+}
+
+// splitActive reports whether the split layout is on and the terminal is
+// wide enough to show it.
+func (m Model) splitActive() bool {
+	return m.splitView && m.layout.SplitAvailable
+}
+
+// outputWidth returns the content width of the output pane.
+func (m Model) outputWidth() int {
+	if m.splitActive() {
+		return m.layout.SplitLeftWidth
+	}
+	return m.layout.ContentWidth()
+}
+
+// fileWidth returns the content width of the file pane.
+func (m Model) fileWidth() int {
+	if m.splitActive() {
+		return m.layout.SplitRightWidth
+	}
+	return m.layout.ContentWidth()
+}
+
+// visibleFilePath returns the file currently on screen: the active file tab,
+// or in the split layout the first spec file when the Output tab is active.
+// Returns "" when no file is shown.
+func (m Model) visibleFilePath() string {
+	if m.activeTab > 0 && m.activeTab < len(m.tabs) {
+		if tab := m.tabs[m.activeTab]; tab.Type == TabFile {
+			return tab.FilePath
+		}
+	}
+	if !m.splitActive() {
+		return ""
+	}
+	for _, tab := range m.tabs {
+		if tab.Type == TabFile && tab.FilePath != "" {
+			return tab.FilePath
+		}
+	}
+	return ""
+}
+
+// toggleSplit switches the split layout on or off. It does nothing on
+// terminals narrower than SplitMinWidth.
+func (m Model) toggleSplit() (tea.Model, tea.Cmd) {
+	if !m.layout.SplitAvailable {
+		return m, nil
+	}
+	m.splitView = !m.splitView
+
+	// Pane widths changed, so re-wrap the output and file content
+	m.viewport.Width = m.outputWidth()
+	m.syncViewportContent()
+	for path := range m.fileContents {
+		m.syncFileViewport(path)
+	}
+
+	if path := m.visibleFilePath(); path != "" {
+		if _, ok := m.fileContents[path]; !ok {
+			return m, loadFileCmd(path)
+		}
+	}
+	return m, nil
+}
+
 // prevTab switches to the previous tab.
 func (m Model) prevTab() (tea.Model, tea.Cmd) {
 	if m.activeTab > 0 {
@@ -690,10 +970,12 @@ func (m Model) renderFull() string {
 
 	// Progress panel
 	sections = append(sections, m.renderProgressPanel())
-	sections = append(sections, RenderDoubleBorder(m.layout.Width, m.styles.Border))
 
-	// Session info panel
-	sections = append(sections, m.renderSessionPanel())
+	// Session info panel (unless hidden)
+	if m.layout.SessionPanelHeight > 0 {
+		sections = append(sections, RenderDoubleBorder(m.layout.Width, m.styles.Border))
+		sections = append(sections, m.renderSessionPanel())
+	}
 
 	// Bottom border
 	sections = append(sections, RenderBottomBorder(m.layout.Width, m.styles.Border))
@@ -763,11 +1045,73 @@ func (m Model) renderHeader() string {
 
 // renderHelpBar renders the help text below the main frame.
 func (m Model) renderHelpBar() string {
+	if m.confirm != nil {
+		return m.renderConfirm()
+	}
+	if m.checkpointOpen {
+		return m.renderCheckpointInput()
+	}
+	if m.tasksFocused {
+		return m.renderTaskHelp()
+	}
+	if m.chatOpen {
+		return "  " + m.styles.HelpKey.Render("enter") + m.styles.HelpBar.Render(" ask  ") +
+			m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" close chat")
+	}
+	if m.timelineOpen {
+		if m.timelineViewing {
+			return "  " + m.styles.HelpKey.Render("↑/↓") + m.styles.HelpBar.Render(" scroll  ") +
+				m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" back to timeline  ") +
+				m.styles.HelpKey.Render("t") + m.styles.HelpBar.Render(" live output")
+		}
+		return "  " + m.styles.HelpKey.Render("↑/↓") + m.styles.HelpBar.Render(" select  ") +
+			m.styles.HelpKey.Render("enter") + m.styles.HelpBar.Render(" view  ") +
+			m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" live output")
+	}
+
+	if m.banner != "" {
+		return m.renderBanner()
+	}
+	if m.scrollLock {
+		return m.renderScrollLockHelp()
+	}
+
 	help := "  " + m.styles.HelpKey.Render("↑/↓") + m.styles.HelpBar.Render(" scroll  ") +
 		m.styles.HelpKey.Render("←/→") + m.styles.HelpBar.Render(" tab  ") +
 		m.styles.HelpKey.Render("1-9") + m.styles.HelpBar.Render(" jump  ") +
-		m.styles.HelpKey.Render("r") + m.styles.HelpBar.Render(" reload  ") +
-		m.styles.HelpKey.Render("q") + m.styles.HelpBar.Render(" quit")
+		m.styles.HelpKey.Render("r") + m.styles.HelpBar.Render(" reload  ")
+	if m.chat != nil {
+		help += m.styles.HelpKey.Render("c") + m.styles.HelpBar.Render(" chat  ")
+	}
+	if m.timeline != nil {
+		help += m.styles.HelpKey.Render("t") + m.styles.HelpBar.Render(" timeline  ")
+	}
+	if m.checkpoint != nil {
+		help += m.styles.HelpKey.Render("m") + m.styles.HelpBar.Render(" checkpoint  ")
+	}
+	if m.taskEdit != nil && len(m.tasks) > 0 {
+		help += m.styles.HelpKey.Render("T") + m.styles.HelpBar.Render(" tasks  ")
+	}
+	if m.layout.SplitAvailable {
+		help += m.styles.HelpKey.Render("s") + m.styles.HelpBar.Render(" split  ")
+	}
+	if m.outputTruncate {
+		help += m.styles.HelpKey.Render("w") + m.styles.HelpBar.Render(" wrap  ") +
+			m.styles.HelpKey.Render("L") + m.styles.HelpBar.Render(" pan  ")
+	} else {
+		help += m.styles.HelpKey.Render("w") + m.styles.HelpBar.Render(" truncate  ")
+	}
+	if m.logLevel.Debug() {
+		help += m.styles.HelpKey.Render("d") + m.styles.HelpBar.Render(" hide debug  ")
+	} else {
+		help += m.styles.HelpKey.Render("d") + m.styles.HelpBar.Render(" debug  ")
+	}
+	if m.textMode.Plain() {
+		help += m.styles.HelpKey.Render("p") + m.styles.HelpBar.Render(" markdown  ")
+	} else {
+		help += m.styles.HelpKey.Render("p") + m.styles.HelpBar.Render(" plain  ")
+	}
+	help += m.styles.HelpKey.Render("q") + m.styles.HelpBar.Render(" quit")
 	return help
 }
 
@@ -830,6 +1174,19 @@ func (m Model) renderTabBar() string {
 
 // renderMainContent renders either the output stream or file content based on active tab.
 func (m Model) renderMainContent() string {
+	if m.chatOpen {
+		return m.renderChatPane()
+	}
+	if m.timelineOpen {
+		return m.renderTimelinePane()
+	}
+
+	if m.splitActive() {
+		if path := m.visibleFilePath(); path != "" {
+			return m.renderSplit(path)
+		}
+	}
+
 	if m.activeTab == 0 || m.activeTab >= len(m.tabs) {
 		return m.renderScrollArea()
 	}
@@ -842,20 +1199,58 @@ func (m Model) renderMainContent() string {
 	return m.renderScrollArea()
 }
 
+// renderSplit renders the output on the left and the file at path on the
+// right, separated by a vertical divider.
+func (m Model) renderSplit(path string) string {
+	if m.layout.ScrollAreaHeight <= 0 {
+		return ""
+	}
+
+	border := m.styles.Border.Render(BoxVertical)
+	left := m.scrollAreaLines(m.layout.SplitLeftWidth)
+	right := m.fileContentLines(path, m.layout.SplitRightWidth)
+
+	lines := make([]string, len(left))
+	for i := range left {
+		lines[i] = border + left[i] + border + right[i] + border
+	}
+	return strings.Join(lines, "\n")
+}
+
 // renderFileContent renders the content of a file using viewport for scrolling.
 func (m Model) renderFileContent(path string) string {
+	return m.frameLines(m.fileContentLines(path, m.layout.ContentWidth()))
+}
+
+// renderScrollArea renders the scrolling output region using the viewport.
+func (m Model) renderScrollArea() string {
+	return m.frameLines(m.scrollAreaLines(m.layout.ContentWidth()))
+}
+
+// frameLines wraps each line in the left and right borders.
+func (m Model) frameLines(lines []string) string {
+	border := m.styles.Border.Render(BoxVertical)
+	framed := make([]string, len(lines))
+	for i, line := range lines {
+		framed[i] = border + line + border
+	}
+	return strings.Join(framed, "\n")
+}
+
+// fileContentLines returns the visible lines of a file, each padded to
+// contentWidth, without borders.
+func (m Model) fileContentLines(path string, contentWidth int) []string {
 	height := m.layout.ScrollAreaHeight
-	contentWidth := m.layout.ContentWidth()
 
 	// Guard against invalid dimensions
 	if height <= 0 {
-		return ""
+		return nil
 	}
 	if contentWidth < 0 {
 		contentWidth = 0
 	}
 
-	border := m.styles.Border.Render(BoxVertical)
+	emptyLine := strings.Repeat(" ", contentWidth)
 
 	content, ok := m.fileContents[path]
 	if !ok {
@@ -867,12 +1262,11 @@ func (m Model) renderFileContent(path string) string {
 		if padding < 0 {
 			padding = 0
 		}
-		lines = append(lines, border+loadingLine+strings.Repeat(" ", padding)+border)
-		emptyLine := border + strings.Repeat(" ", contentWidth) + border
+		lines = append(lines, loadingLine+strings.Repeat(" ", padding))
 		for len(lines) < height {
 			lines = append(lines, emptyLine)
 		}
-		return strings.Join(lines, "\n")
+		return lines
 	}
 
 	// Get viewport for scroll position
@@ -901,7 +1295,6 @@ func (m Model) renderFileContent(path string) string {
 	for i := 0; i < height; i++ {
 		lineIdx := offset + i
 		if lineIdx >= len(fileLines) {
-			emptyLine := border + strings.Repeat(" ", contentWidth) + border
 			lines = append(lines, emptyLine)
 			continue
 		}
@@ -937,30 +1330,29 @@ func (m Model) renderFileContent(path string) string {
 			padding = 0
 		}
 
-		lines = append(lines, border+lineContent+strings.Repeat(" ", padding)+border)
+		lines = append(lines, lineContent+strings.Repeat(" ", padding))
 	}
 
-	return strings.Join(lines, "\n")
+	return lines
 }
 
-// renderScrollArea renders the scrolling output region using the viewport.
-func (m Model) renderScrollArea() string {
+// scrollAreaLines returns the visible output lines, each padded to
+// contentWidth, without borders.
+func (m Model) scrollAreaLines(contentWidth int) []string {
 	height := m.layout.ScrollAreaHeight
-	contentWidth := m.layout.ContentWidth()
 
 	// Guard against invalid dimensions
 	if height <= 0 {
-		return ""
+		return nil
 	}
 	if contentWidth < 0 {
 		contentWidth = 0
 	}
 
-	border := m.styles.Border.Render(BoxVertical)
-	emptyLine := border + strings.Repeat(" ", contentWidth) + border
+	emptyLine := strings.Repeat(" ", contentWidth)
 
 	// Empty state: show waiting message
-	if m.outputLines.Len() == 0 {
+	if m.outputLines.Len() == 0 && m.partialLine == "" {
 		var lines []string
 		midHeight := height / 2
 		for i := 0; i < midHeight-1; i++ {
@@ -978,18 +1370,18 @@ func (m Model) renderScrollArea() string {
 		if rightPad < 0 {
 			rightPad = 0
 		}
-		lines = append(lines, border+strings.Repeat(" ", leftPad)+waitMsg+strings.Repeat(" ", rightPad)+border)
+		lines = append(lines, strings.Repeat(" ", leftPad)+waitMsg+strings.Repeat(" ", rightPad))
 		for len(lines) < height {
 			lines = append(lines, emptyLine)
 		}
-		return strings.Join(lines, "\n")
+		return lines
 	}
 
 	// Get viewport content
 	viewContent := m.viewport.View()
 	viewLines := strings.Split(viewContent, "\n")
 
-	// Build output with borders
+	// Build output lines
 	var lines []string
 	for i := 0; i < height; i++ {
 		var line string
@@ -1004,10 +1396,10 @@ func (m Model) renderScrollArea() string {
 			line = ansi.Truncate(line, contentWidth, "")
 			padding = 0
 		}
-		lines = append(lines, border+line+strings.Repeat(" ", padding)+border)
+		lines = append(lines, line+strings.Repeat(" ", padding))
 	}
 
-	return strings.Join(lines, "\n")
+	return lines
 }
 
 // renderTaskPanel renders the task list panel.
@@ -1036,16 +1428,16 @@ func (m Model) renderTaskPanel() string {
 
 	// Tasks
 	visible := m.layout.TasksVisible()
-	for i := 0; i < visible && i < len(m.tasks); i++ {
+	for i := m.taskWindow(); i < m.taskWindow()+visible && i < len(m.tasks); i++ {
 		task := m.tasks[i]
-		lines = append(lines, m.renderTask(task))
+		lines = append(lines, m.renderTask(task, m.tasksFocused && i == m.taskCursor))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-// renderTask renders a single task line.
-func (m Model) renderTask(task Task) string {
+// renderTask renders a single task line, marked when selected.
+func (m Model) renderTask(task Task, selected bool) string {
 	var icon string
 	var style lipgloss.Style
 
@@ -1074,8 +1466,12 @@ func (m Model) renderTask(task Task) string {
 		content = ansi.Truncate(content, maxLen-3, "...")
 	}
 
-	taskContent := style.Render("  " + icon + " " + content)
-	taskWidth := ansi.StringWidth("  " + icon + " " + content)
+	marker := "  "
+	if selected {
+		marker = "▸ "
+	}
+	taskContent := style.Render(marker + icon + " " + content)
+	taskWidth := ansi.StringWidth(marker + icon + " " + content)
 	padding := contentWidth - taskWidth
 	if padding < 0 {
 		// Content exceeds available width - truncate to fit
@@ -1098,7 +1494,7 @@ func (m Model) renderProgressPanel() string {
 	if p.MaxIteration > 0 {
 		iterRatio = float64(p.Iteration) / float64(p.MaxIteration)
 	}
-	iterBar := RenderProgressBar(iterRatio, BarWidth, m.styles.Value, m.styles.Warning)
+	iterBar := RenderProgressBar(iterRatio, m.layout.BarWidth, m.styles.Value, m.styles.Warning)
 	iterLabel := m.styles.Label.Render("Iteration ")
 	iterValue := m.styles.Value.Render(formatFraction(p.Iteration, p.MaxIteration))
 	if p.MaxIteration > 0 && iterRatio > 0.8 {
@@ -1136,8 +1532,8 @@ func (m Model) renderProgressPanel() string {
 	if p.Budget > 0 {
 		costRatio = p.Cost / p.Budget
 	}
-	budgetBar := RenderProgressBar(costRatio, BarWidth, m.styles.Value, m.styles.Warning)
-	tokensStr := m.formatTokens(p.TokensIn, p.TokensOut)
+	budgetBar := RenderProgressBar(costRatio, m.layout.BarWidth, m.styles.Value, m.styles.Warning)
+	tokensStr := m.formatTokens(p.TokensIn, p.TokensOut, p.CacheReadTokens)
 	costStr := m.formatCost(p.Cost, p.Budget)
 	line2Content := " " + budgetBar + " " + tokensStr + " " + InnerVertical + " " + costStr
 	line2Width := ansi.StringWidth(line2Content)
@@ -1155,9 +1551,12 @@ func (m Model) renderProgressPanel() string {
 	if p.ContextWindow > 0 {
 		contextRatio = float64(currentIterTokens) / float64(p.ContextWindow)
 	}
-	contextBar := RenderProgressBar(contextRatio, BarWidth, m.styles.Value, m.styles.Warning)
+	contextBar := RenderProgressBar(contextRatio, m.layout.BarWidth, m.styles.Value, m.styles.Warning)
 	contextStr := m.formatContext(currentIterTokens, p.ContextWindow, contextRatio)
 	line3Content := " " + contextBar + " " + contextStr
+	if history := m.formatIterationHistory(); history != "" {
+		line3Content += " " + InnerVertical + " " + history
+	}
 	line3Width := ansi.StringWidth(line3Content)
 	line3Padding := contentWidth - line3Width
 	if line3Padding < 0 {
@@ -1193,11 +1592,16 @@ func (m Model) formatGateRetries(retries, max int) string {
 }
 
 // formatTokens formats token counts with thousands separator.
-func (m Model) formatTokens(in, out int) string {
+func (m Model) formatTokens(in, out, cacheRead int) string {
 	label := m.styles.Label.Render("Tokens: ")
 	inStr := m.styles.Value.Render(util.FormatNumber(in))
 	outStr := m.styles.Value.Render(util.FormatNumber(out))
-	return label + inStr + m.styles.Label.Render(" in / ") + outStr + m.styles.Label.Render(" out")
+	tokens := label + inStr + m.styles.Label.Render(" in / ") + outStr + m.styles.Label.Render(" out")
+	if cacheRead > 0 {
+		hit := util.IntToString(int(output.CacheHitRatio(cacheRead, in)*100)) + "%"
+		tokens += m.styles.Label.Render(" (") + m.styles.Value.Render(hit) + m.styles.Label.Render(" cached)")
+	}
+	return tokens
 }
 
 // formatCost formats cost with optional warning colour.
@@ -1255,7 +1659,7 @@ func (m Model) formatIterationTimer() string {
 		return ""
 	}
 
-	elapsed := time.Since(p.IterationStart)
+	elapsed := m.now().Sub(p.IterationStart)
 	remaining := p.IterationTimeout - elapsed
 
 	// Clamp to zero if negative
@@ -1371,24 +1775,10 @@ func formatFraction(a, b int) string {
 }
 
 
+// formatCurrency formats a USD amount in the display currency with two
+// decimal places.
 func formatCurrency(amount float64) string {
-	// Handle negative amounts by formatting absolute value and prepending minus
-	if amount < 0 {
-		return "-" + formatCurrency(-amount)
-	}
-	// Format as $X.XX with proper rounding using math.Round for precision
-	totalCents := int(math.Round(amount * 100))
-	whole := totalCents / 100
-	cents := totalCents % 100
-	return "$" + util.FormatNumber(whole) + "." + padLeft(util.IntToString(cents), 2, '0')
-}
-
-
-func padLeft(s string, length int, pad rune) string {
-	for len(s) < length {
-		s = string(pad) + s
-	}
-	return s
+	return util.FormatCost(amount, 2)
 }
 
 // truncateFromStart truncates a string from the beginning to fit within targetWidth,
@@ -1426,6 +1816,12 @@ func (m *Model) SetProgress(p ProgressInfo) {
 	m.progress = p
 }
 
+// SetClock replaces the clock used by the iteration timer. Snapshot tests use
+// a fixed clock so rendering does not depend on when they run.
+func (m *Model) SetClock(now func() time.Time) {
+	m.now = now
+}
+
 // SetSession updates the session information.
 func (m *Model) SetSession(s SessionInfo) {
 	m.session = s
@@ -1434,9 +1830,14 @@ func (m *Model) SetSession(s SessionInfo) {
 // SetTasks updates the task list.
 func (m *Model) SetTasks(tasks []Task) {
 	m.tasks = tasks
+	// Keep the selection on the list, or give the focus back without one
+	m.taskCursor = max(0, min(m.taskCursor, len(tasks)-1))
+	if len(tasks) == 0 {
+		m.tasksFocused = false
+	}
 	// Recalculate layout with new task count
 	if m.ready {
-		m.layout = CalculateLayout(m.layout.Width, m.layout.Height, len(tasks))
+		m.layout = m.layoutConfig.Calculate(m.layout.Width, m.layout.Height, len(tasks))
 	}
 }
 
@@ -1463,7 +1864,7 @@ func (m *Model) syncFileViewport(path string) {
 	}
 
 	// Update dimensions - file content area excludes line numbers (6 chars: "NNNNN│")
-	vp.Width = m.layout.ContentWidth() - 6
+	vp.Width = m.fileWidth() - 6
 	if vp.Width < 1 {
 		vp.Width = 1
 	}
@@ -1507,6 +1908,9 @@ func (m *Model) syncViewportContent() {
 		lines = append(lines, line)
 		return true
 	})
+	if m.partialLine != "" {
+		lines = append(lines, m.partialLine)
+	}
 
 	// Use lipgloss to wrap and pad content
 	// Account for padding in the wrap width
@@ -1514,6 +1918,13 @@ func (m *Model) syncViewportContent() {
 	if wrapWidth < 1 {
 		wrapWidth = 1
 	}
+	if m.outputTruncate {
+		m.viewport.SetContent(truncateLines(lines, m.outputHScroll, wrapWidth))
+		if m.outputTailing {
+			m.viewport.GotoBottom()
+		}
+		return
+	}
 	contentStyle := lipgloss.NewStyle().Width(wrapWidth).PaddingLeft(outputPaddingLeft)
 	wrapped := contentStyle.Render(strings.Join(lines, "\n"))
 	m.viewport.SetContent(wrapped)
@@ -1528,6 +1939,7 @@ func (m *Model) syncViewportContent() {
 // It also resets tailing mode so new content will auto-scroll.
 func (m *Model) ClearOutput() {
 	m.outputLines.Clear()
+	m.partialLine = ""
 	m.viewport.SetContent("")
 	m.outputTailing = true
 }
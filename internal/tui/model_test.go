@@ -61,6 +61,26 @@ func TestModelUpdateWindowSize(t *testing.T) {
 	}
 }
 
+func TestModelViewHiddenPanels(t *testing.T) {
+	m := NewModel()
+	WithLayout(LayoutConfig{Hide: []string{PanelTasks, PanelSession}})(&m)
+	m.SetSession(SessionInfo{SpecFiles: []string{"docs/plans/spec.md"}})
+	m.SetTasks([]Task{{ID: "1", Content: "Write the parser", Status: "pending"}})
+
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view := updatedModel.(Model).View()
+
+	if lines := strings.Count(view, "\n") + 1; lines != 40 {
+		t.Errorf("view has %d lines, want the terminal height 40", lines)
+	}
+	if strings.Contains(view, "docs/plans/spec.md") {
+		t.Error("hidden session panel is rendered")
+	}
+	if strings.Contains(view, "Write the parser") {
+		t.Error("hidden task panel is rendered")
+	}
+}
+
 func TestModelUpdateQuit(t *testing.T) {
 	m := NewModel()
 
@@ -308,7 +328,7 @@ func TestModelRenderTaskWithIcons(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		rendered := model.renderTask(tt.task)
+		rendered := model.renderTask(tt.task, false)
 		if !strings.Contains(rendered, tt.wantIcon) {
 			t.Errorf("renderTask(%s) should contain %q, got %q", tt.task.Status, tt.wantIcon, rendered)
 		}
@@ -1962,6 +1982,23 @@ func TestRenderProgressPanelContextBar(t *testing.T) {
 	}
 }
 
+func TestRenderProgressPanelCacheHits(t *testing.T) {
+	m := NewModel()
+	updatedModel, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model := updatedModel.(Model)
+
+	model.SetProgress(ProgressInfo{TokensIn: 40000, TokensOut: 2000, Budget: 10})
+	if strings.Contains(model.View(), "cached") {
+		t.Error("expected no cache hit ratio before the cache is used")
+	}
+
+	updatedModel, _ = model.Update(StatsMsg{TokensIn: 40000, TokensOut: 2000, CacheReadTokens: 30000})
+	model = updatedModel.(Model)
+	if !strings.Contains(model.View(), "(75% cached)") {
+		t.Error("expected the cache hit ratio '(75% cached)' next to the tokens")
+	}
+}
+
 func TestProgressPanelHasThreeLines(t *testing.T) {
 	m := NewModel()
 
@@ -2447,3 +2484,125 @@ func TestProgressInfoWorkflowNameField(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitViewToggle(t *testing.T) {
+	tests := []struct {
+		name      string
+		width     int
+		wantSplit bool
+	}{
+		{name: "wide terminal enables split", width: 180, wantSplit: true},
+		{name: "narrow terminal ignores toggle", width: 120, wantSplit: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel()
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: tt.width, Height: 40})
+			updated, _ = updated.Update(SessionMsg{SpecFiles: []string{"/test/spec.md"}})
+
+			updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+			model := updated.(Model)
+
+			if model.splitActive() != tt.wantSplit {
+				t.Fatalf("splitActive() = %v, want %v", model.splitActive(), tt.wantSplit)
+			}
+			if !tt.wantSplit {
+				if model.viewport.Width != model.layout.ContentWidth() {
+					t.Errorf("viewport width = %d, want %d", model.viewport.Width, model.layout.ContentWidth())
+				}
+				return
+			}
+			if cmd == nil {
+				t.Error("expected command to load the spec file")
+			}
+			if model.viewport.Width != model.layout.SplitLeftWidth {
+				t.Errorf("viewport width = %d, want %d", model.viewport.Width, model.layout.SplitLeftWidth)
+			}
+
+			// Toggling again restores the full-width output
+			updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+			model = updated.(Model)
+			if model.splitActive() {
+				t.Error("expected split to be off after second toggle")
+			}
+			if model.viewport.Width != model.layout.ContentWidth() {
+				t.Errorf("viewport width = %d, want %d", model.viewport.Width, model.layout.ContentWidth())
+			}
+		})
+	}
+}
+
+func TestSplitViewRendering(t *testing.T) {
+	terminalWidth := 180
+	m := NewModel()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: terminalWidth, Height: 40})
+	updated, _ = updated.Update(SessionMsg{
+		SpecFiles: []string{"/test/spec.md"},
+		NotesFile: "/test/notes.md",
+	})
+	updated, _ = updated.Update(FileContentMsg{Path: "/test/spec.md", Content: "- [ ] acceptance criterion"})
+	updated, _ = updated.Update(FileContentMsg{Path: "/test/notes.md", Content: "notes content"})
+	updated, _ = updated.Update(OutputLineMsg("agent output line"))
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	model := updated.(Model)
+
+	content := model.renderMainContent()
+	for _, want := range []string{"agent output line", "acceptance criterion"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("split content missing %q", want)
+		}
+	}
+
+	// Switching to the notes tab shows notes beside the output
+	updated, _ = model.switchToTab(2)
+	model = updated.(Model)
+	content = model.renderMainContent()
+	for _, want := range []string{"agent output line", "notes content"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("split content on notes tab missing %q", want)
+		}
+	}
+
+	lines := strings.Split(model.View(), "\n")
+	for i, line := range lines[:len(lines)-1] {
+		if w := ansi.StringWidth(line); w != terminalWidth {
+			t.Errorf("line %d has width %d, expected %d: %q", i, w, terminalWidth, line)
+		}
+	}
+}
+
+func TestArtifactsMsg_AddsTabs(t *testing.T) {
+	m := NewModel()
+	updated, _ := m.Update(SessionMsg(SessionInfo{SpecFiles: []string{"/path/to/spec.md"}}))
+	updated, _ = updated.Update(ArtifactsMsg{"/path/to/plan.md"})
+	updated, _ = updated.Update(ArtifactsMsg{"/path/to/plan.md", "/path/to/design.md"})
+	model := updated.(Model)
+
+	var names []string
+	for _, tab := range model.tabs {
+		names = append(names, tab.Name)
+	}
+	want := "Output|Spec: spec.md|Art: plan.md|Art: design.md"
+	if got := strings.Join(names, "|"); got != want {
+		t.Errorf("tabs = %q, want %q", got, want)
+	}
+}
+
+func TestBuildTabs_ExternalFilesShowAbsolutePath(t *testing.T) {
+	m := NewModel()
+	m.SetSession(SessionInfo{
+		SpecFiles:   []string{"/work/app/spec.md"},
+		ContextFile: "/work/app/docs/api.md, /work/shared-lib/README.md, /work/shared-lib/docs/very/long/path/to/notes.md",
+		WorkingDir:  "/work/app",
+	})
+
+	var names []string
+	for _, tab := range m.buildTabs() {
+		names = append(names, tab.Name)
+	}
+	want := "Output|Spec: spec.md|Ctx: api.md|Ctx: /work/shared-lib/README.md|Ctx: ...ared-lib/docs/very/long/path/to/notes.md"
+	if got := strings.Join(names, "|"); got != want {
+		t.Errorf("tabs = %q, want %q", got, want)
+	}
+}
@@ -1,25 +1,129 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/flashingpumpkin/orbital/internal/output"
+	"github.com/flashingpumpkin/orbital/internal/tasks"
 	"github.com/muesli/termenv"
 )
 
+// maxPendingMessages bounds the messages buffered before Run starts the event loop.
+// When full, the oldest message is dropped so the latest state is kept.
+const maxPendingMessages = 1000
+
+// ErrProgramRunning is returned when Run is called on a program that is already running.
+var ErrProgramRunning = errors.New("tui: program already running")
+
+// ErrProgramStopped is returned by Confirm when the program exits before the
+// question is answered.
+var ErrProgramStopped = errors.New("tui: program stopped")
+
+// PanicError is returned by Run when the TUI panicked. The terminal has been
+// restored by the time Run returns.
+type PanicError struct {
+	// Value is the recovered panic value.
+	Value any
+
+	// Stack is the stack trace of the panic, or nil when the panic happened
+	// outside the model (for example in a command).
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("tui panicked: %v", e.Value)
+}
+
+// Unwrap returns tea.ErrProgramPanic.
+func (e *PanicError) Unwrap() error {
+	return tea.ErrProgramPanic
+}
+
+// programState tracks the lifecycle of the wrapped tea.Program.
+type programState int
+
+const (
+	// programPending means Run has not been called; messages are buffered.
+	programPending programState = iota
+	// programStarting means Run has been called and buffered messages are being flushed.
+	// New messages are still buffered so they arrive after the earlier ones.
+	programStarting
+	// programRunning means messages are sent straight to the event loop.
+	programRunning
+	// programStopped means the program quit, was killed, or exited; messages are dropped.
+	programStopped
+)
+
 // Program wraps the tea.Program and Bridge for lifecycle management.
+// All methods are safe to call from any goroutine at any time: messages sent
+// before Run are buffered and delivered once the event loop starts, and
+// messages sent after the program stops are dropped instead of blocking.
 type Program struct {
 	program *tea.Program
 	bridge  *Bridge
 	tracker *TaskTracker
+	events  *EventLog // records every message for the timeline scrubber (may be nil)
+
+	onOutput func(line string) // sees every output line (may be nil)
+
+	mu       sync.Mutex
+	state    programState
+	pending  []tea.Msg
+	done     chan struct{}
+	doneOnce sync.Once
+	panicked *PanicError
+
+	// suspending is set while Bubble Tea suspends the program (see filter).
+	suspending atomic.Bool
+}
+
+// Option configures the TUI model created by New.
+type Option func(*Model)
+
+// WithChat enables the chat pane (toggled with `c`), answering questions with fn.
+func WithChat(fn ChatFunc) Option {
+	return func(m *Model) {
+		m.chat = fn
+	}
+}
+
+// WithCheckpoint enables marking named checkpoints (with `m`). fn receives
+// the name typed by the user and must not block.
+func WithCheckpoint(fn func(name string)) Option {
+	return func(m *Model) {
+		m.checkpoint = fn
+	}
+}
+
+// WithLayout adjusts the panel layout (see LayoutConfig).
+func WithLayout(c LayoutConfig) Option {
+	return func(m *Model) {
+		m.layoutConfig = c
+	}
+}
+
+// WithEventLog records output to log and enables the timeline scrubber (toggled with `t`).
+func WithEventLog(log *EventLog) Option {
+	return func(m *Model) {
+		m.timeline = log
+	}
 }
 
 // New creates a new TUI program with the given initial session and progress.
 // Returns the Program wrapper which provides access to both the tea.Program and Bridge.
 // The theme parameter specifies the colour theme: "auto", "dark", or "light".
 // If theme is "auto", it will be resolved using DetectTheme().
-func New(session SessionInfo, progress ProgressInfo, theme string) *Program {
+func New(session SessionInfo, progress ProgressInfo, theme string, opts ...Option) *Program {
 	// Handle NO_COLOR environment variable
 	if os.Getenv("NO_COLOR") != "" {
 		lipgloss.SetColorProfile(termenv.Ascii)
@@ -33,33 +137,188 @@ func New(session SessionInfo, progress ProgressInfo, theme string) *Program {
 	model.session = session
 	model.tabs = model.buildTabs()
 	model.progress = progress
+	for _, opt := range opts {
+		opt(&model)
+	}
 
-	// Create task tracker
-	tracker := NewTaskTracker()
-
-	// Create the tea program
-	program := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	// Task edits go to the program's tracker, created with the program
+	var p *Program
+	if model.taskEditing {
+		model.taskEdit = func(id, status string) ([]Task, error) {
+			return p.tracker.SetStatus(id, status)
+		}
+	}
 
-	// Create the bridge
-	bridge := NewBridge(program, tracker)
+	p = newProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
+	p.bridge.level = model.logLevel
+	p.bridge.text = model.textMode
+	p.events = model.timeline
+	p.events.Observe(ProgressMsg(progress))
+	return p
+}
 
-	return &Program{
-		program: program,
-		bridge:  bridge,
-		tracker: tracker,
+// newProgram wraps a tea.Program for model and creates its Bridge.
+func newProgram(model tea.Model, opts ...tea.ProgramOption) *Program {
+	p := &Program{
+		tracker: NewTaskTracker(),
+		done:    make(chan struct{}),
 	}
+	opts = append(opts, tea.WithFilter(p.filter))
+	p.program = tea.NewProgram(panicGuard{Model: model, p: p}, opts...)
+
+	// The bridge sends through the lifecycle-aware send so output written
+	// before Run or after Quit never blocks the stream
+	p.bridge = newBridge(p.send, p.tracker, defaultFlushInterval)
+
+	return p
 }
 
 // Run starts the TUI program. This blocks until the program exits.
+// Messages buffered before Run are delivered in order once the event loop starts.
+// If the program was quit or killed before Run, it returns nil immediately.
 func (p *Program) Run() error {
+	p.mu.Lock()
+	switch p.state {
+	case programStopped:
+		p.mu.Unlock()
+		return nil
+	case programStarting, programRunning:
+		p.mu.Unlock()
+		return ErrProgramRunning
+	}
+	p.state = programStarting
+	p.mu.Unlock()
+
+	go p.flushPending()
+	stopWatching := p.watchJobControl()
+
 	_, err := p.program.Run()
+	stopWatching()
+	p.stop()
+	if errors.Is(err, tea.ErrProgramPanic) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.panicked != nil {
+			return p.panicked
+		}
+		return &PanicError{Value: err}
+	}
 	return err
 }
 
+// panicGuard wraps the model to capture the stack trace of a panic in Init,
+// Update or View before Bubble Tea recovers it and restores the terminal.
+type panicGuard struct {
+	tea.Model
+	p *Program
+}
+
+func (g panicGuard) Init() tea.Cmd {
+	defer g.p.capturePanic()
+	return g.Model.Init()
+}
+
+func (g panicGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer g.p.capturePanic()
+	model, cmd := g.Model.Update(msg)
+	return panicGuard{Model: model, p: g.p}, cmd
+}
+
+func (g panicGuard) View() string {
+	defer g.p.capturePanic()
+	return g.Model.View()
+}
+
+// capturePanic records a panic and its stack trace, then re-panics so Bubble
+// Tea shuts down as usual. It must be called directly by defer.
+func (p *Program) capturePanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	p.mu.Lock()
+	if p.panicked == nil {
+		p.panicked = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+	p.mu.Unlock()
+	panic(r)
+}
+
+// SetOutputObserver calls fn with every output line sent to the program, so
+// recent output can be kept outside the TUI. It must be called before any
+// output is sent.
+func (p *Program) SetOutputObserver(fn func(line string)) {
+	p.onOutput = fn
+}
+
+// flushPending delivers buffered messages to the event loop, then switches to
+// direct sends. tea.Program.Send blocks until the event loop reads the message
+// and returns immediately once the program has exited, so this never leaks.
+func (p *Program) flushPending() {
+	for {
+		p.mu.Lock()
+		if p.state != programStarting {
+			p.mu.Unlock()
+			return
+		}
+		msgs := p.pending
+		p.pending = nil
+		if len(msgs) == 0 {
+			p.state = programRunning
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+
+		for _, msg := range msgs {
+			p.program.Send(msg)
+		}
+	}
+}
+
+// send delivers msg according to the lifecycle state: buffered before the
+// event loop is running, sent while running, and dropped once stopped.
+func (p *Program) send(msg tea.Msg) {
+	p.events.Observe(msg)
+	if p.onOutput != nil {
+		switch msg := msg.(type) {
+		case OutputLineMsg:
+			p.onOutput(string(msg))
+		case OutputBatchMsg:
+			for _, line := range msg {
+				p.onOutput(line)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	switch p.state {
+	case programPending, programStarting:
+		if len(p.pending) >= maxPendingMessages {
+			p.pending = p.pending[1:]
+		}
+		p.pending = append(p.pending, msg)
+		p.mu.Unlock()
+		return
+	case programStopped:
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	p.program.Send(msg)
+}
+
+// stop marks the program stopped, discards buffered messages, and releases Wait.
+func (p *Program) stop() {
+	p.mu.Lock()
+	p.state = programStopped
+	p.pending = nil
+	p.mu.Unlock()
+
+	p.doneOnce.Do(func() { close(p.done) })
+}
+
 // Bridge returns the Bridge which implements io.Writer for streaming output.
 func (p *Program) Bridge() *Bridge {
 	return p.bridge
@@ -67,37 +326,169 @@ func (p *Program) Bridge() *Bridge {
 
 // Send sends a message to the program.
 func (p *Program) Send(msg tea.Msg) {
-	p.program.Send(msg)
+	p.send(msg)
 }
 
-// Quit sends a quit message to the program.
+// Quit sends a quit message to the program. The quit is queued behind any
+// buffered messages; if Run has not been called, the program is stopped
+// and a later Run returns immediately.
 func (p *Program) Quit() {
-	p.program.Quit()
+	p.mu.Lock()
+	pending := p.state == programPending
+	p.mu.Unlock()
+
+	if pending {
+		p.stop()
+		return
+	}
+	p.send(tea.Quit())
 }
 
 // SendProgress sends a progress update to the program.
 func (p *Program) SendProgress(progress ProgressInfo) {
-	p.program.Send(ProgressMsg(progress))
+	p.send(ProgressMsg(progress))
 }
 
 // SendSession sends session info to the program.
 func (p *Program) SendSession(session SessionInfo) {
-	p.program.Send(SessionMsg(session))
+	p.send(SessionMsg(session))
+}
+
+// SendArtifacts adds tabs for artifact files written by a workflow step.
+func (p *Program) SendArtifacts(paths []string) {
+	p.send(ArtifactsMsg(paths))
 }
 
 // SendOutput sends a formatted output line to the program.
 func (p *Program) SendOutput(line string) {
-	p.program.Send(OutputLineMsg(line))
+	p.send(OutputLineMsg(line))
+}
+
+// SendBanner shows a warning in place of the help bar until the user
+// dismisses it.
+func (p *Program) SendBanner(text string) {
+	p.send(BannerMsg(text))
+}
+
+// Confirm asks a yes/no question in place of the help bar and blocks until
+// it is answered, the program stops, or ctx is done.
+func (p *Program) Confirm(ctx context.Context, question string) (bool, error) {
+	return p.Ask(ctx, ConfirmMsg{Question: question})
+}
+
+// Ask is Confirm with the question and key labels in q; its Reply is set
+// here. When ctx is done first the question is closed unanswered.
+func (p *Program) Ask(ctx context.Context, q ConfirmMsg) (bool, error) {
+	reply := make(chan bool, 1)
+	q.Reply = reply
+	p.send(q)
+	select {
+	case answer := <-reply:
+		return answer, nil
+	case <-p.done:
+		return false, ErrProgramStopped
+	case <-ctx.Done():
+		p.send(confirmWithdrawnMsg{Reply: reply})
+		return false, ctx.Err()
+	}
+}
+
+// SendGateScores records a gate judge's rubric scores in the event log and
+// shows them in the output.
+func (p *Program) SendGateScores(step string, scores map[string]float64) {
+	p.send(GateScoresMsg{Step: step, Scores: scores})
+	p.send(OutputLineMsg("🎯 Gate scores: " + output.FormatScores(scores)))
+}
+
+// SendGateResult records a gate's verdict and how long it took in the event
+// log and shows them in the output.
+func (p *Program) SendGateResult(step, result string, duration time.Duration) {
+	p.send(GateResultMsg{Step: step, Result: result, Duration: duration})
+	p.send(OutputLineMsg(fmt.Sprintf("⚖ Gate %s: %s in %s", step, result, duration.Round(time.Second))))
+}
+
+// SendGateTimeout records a gate that timed out after timeout in the event
+// log and shows detail, what happens next, in the output.
+func (p *Program) SendGateTimeout(step string, timeout time.Duration, detail string) {
+	p.send(GateResultMsg{Step: step, Result: "TIMEOUT", Duration: timeout})
+	p.send(OutputLineMsg("⏱ " + detail))
+}
+
+// SendCheckpoint records a named checkpoint in the event log and shows it in
+// the output.
+func (p *Program) SendCheckpoint(name string, iteration int) {
+	p.send(CheckpointMsg{Name: name, Iteration: iteration})
+	p.send(OutputLineMsg(fmt.Sprintf("📍 Checkpoint %q at iteration %d", name, iteration)))
+}
+
+// SendEscalation records a failing gate escalating to a stronger model in the
+// event log and shows it in the output.
+func (p *Program) SendEscalation(step string, failures int, model string) {
+	p.send(EscalationMsg{Step: step, Failures: failures, Model: model})
+	p.send(OutputLineMsg(fmt.Sprintf("⬆ Gate %s failed %d times, escalating to %s", step, failures, model)))
+}
+
+// SendInterjection records an operator interjection in the event log and
+// shows it in the output.
+func (p *Program) SendInterjection(iteration int, text string) {
+	p.send(InterjectionMsg{Iteration: iteration, Text: text})
+	lines := []string{fmt.Sprintf("🛑 Operator interjection for iteration %d:", iteration)}
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, "  │ "+line)
+	}
+	p.send(OutputBatchMsg(lines))
+}
+
+// SendParseError records a quarantined stream line in the event log. Only the
+// first is shown in the output, so a garbled stream does not flood it.
+func (p *Program) SendParseError(count int, line, quarantinePath string) {
+	p.send(ParseErrorMsg{Count: count, Line: line})
+	if count == 1 {
+		p.send(OutputLineMsg(fmt.Sprintf("⚠ Skipped a malformed output line; quarantined to %s", quarantinePath)))
+	}
+}
+
+// SendDryStep records a dry step in the event log and shows the prompt it
+// would have sent in the output.
+func (p *Program) SendDryStep(step, prompt string) {
+	p.send(DryStepMsg{Step: step, Prompt: prompt})
+	lines := []string{fmt.Sprintf("🧪 Dry step %s: prompt not sent", step)}
+	for _, line := range strings.Split(strings.TrimRight(prompt, "\n"), "\n") {
+		lines = append(lines, "  │ "+line)
+	}
+	p.send(OutputBatchMsg(lines))
 }
 
-// Kill forcefully terminates the program.
+// SendCodeTodos replaces the TODO(orbital) comments listed in the task
+// panel after the agent's own tasks.
+func (p *Program) SendCodeTodos(todos []CodeTodo) {
+	p.send(TasksMsg(p.tracker.SetCodeTodos(todos)))
+}
+
+// TakeTaskCorrections returns the task status changes the operator made in
+// the task panel since the last call (see WithTaskEditing).
+func (p *Program) TakeTaskCorrections() []tasks.Correction {
+	return p.tracker.TakeCorrections()
+}
+
+// Kill forcefully terminates the program, discarding buffered messages.
 func (p *Program) Kill() {
+	p.mu.Lock()
+	pending := p.state == programPending
+	p.state = programStopped
+	p.pending = nil
+	p.mu.Unlock()
+
 	p.program.Kill()
+	if pending {
+		p.stop()
+	}
 }
 
-// Wait waits for the program to finish.
+// Wait waits for the program to finish. It returns immediately if the
+// program was stopped before Run.
 func (p *Program) Wait() {
-	p.program.Wait()
+	<-p.done
 }
 
 // Close cleans up resources including the Bridge's message pump goroutine.
@@ -109,17 +500,16 @@ func (p *Program) Close() {
 }
 
 // SendInitialPrompt formats and sends the initial prompt to the TUI viewport.
-// This should be called after the TUI is ready (after the startup delay).
 func (p *Program) SendInitialPrompt(prompt string) {
 	// Send header with blank line after
-	p.program.Send(OutputLineMsg("📋 Initial Prompt"))
-	p.program.Send(OutputLineMsg(""))
+	p.send(OutputLineMsg("📋 Initial Prompt"))
+	p.send(OutputLineMsg(""))
 
 	// Send the prompt content (padding applied globally in syncViewportContent)
-	p.program.Send(OutputLineMsg(prompt))
+	p.send(OutputLineMsg(prompt))
 
 	// Blank line after prompt
-	p.program.Send(OutputLineMsg(""))
+	p.send(OutputLineMsg(""))
 }
 
 // ResetIterationTokens resets the per-iteration token counters.
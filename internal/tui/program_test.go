@@ -1,8 +1,16 @@
 package tui
 
 import (
+	"errors"
+	"io"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestNewProgram(t *testing.T) {
@@ -54,3 +62,241 @@ func TestProgramBridge(t *testing.T) {
 	// Bridge should implement io.Writer
 	var _ interface{ Write([]byte) (int, error) } = bridge
 }
+
+// recordMsg is a test message recorded by recorderModel.
+type recordMsg string
+
+// recorderModel records recordMsg values and quits on "quit".
+type recorderModel struct {
+	mu   *sync.Mutex
+	seen *[]string
+}
+
+func (m recorderModel) Init() tea.Cmd { return nil }
+
+func (m recorderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if r, ok := msg.(recordMsg); ok {
+		m.mu.Lock()
+		*m.seen = append(*m.seen, string(r))
+		m.mu.Unlock()
+		if r == "quit" {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m recorderModel) View() string { return "" }
+
+func newTestProgram() (*Program, func() []string) {
+	var mu sync.Mutex
+	var seen []string
+	p := newProgram(recorderModel{mu: &mu, seen: &seen},
+		tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer())
+	return p, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+// runAsync starts p.Run in a goroutine and returns a channel with its result.
+func runAsync(p *Program) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- p.Run() }()
+	return done
+}
+
+func waitRun(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return")
+	}
+}
+
+func TestProgram_BuffersMessagesBeforeRun(t *testing.T) {
+	p, seen := newTestProgram()
+	defer p.Close()
+
+	p.Send(recordMsg("first"))
+	p.Send(recordMsg("second"))
+	p.Send(recordMsg("quit"))
+
+	waitRun(t, runAsync(p))
+
+	got := seen()
+	want := []string{"first", "second", "quit"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestProgram_ConcurrentSendsDuringStartup(t *testing.T) {
+	p, seen := newTestProgram()
+	defer p.Close()
+
+	done := runAsync(p)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				p.Send(recordMsg("msg"))
+				p.SendProgress(ProgressInfo{Iteration: j})
+				_, _ = p.Bridge().Write([]byte(`{"type":"system","message":"hi"}` + "\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	p.Send(recordMsg("quit"))
+
+	waitRun(t, done)
+
+	if got := len(seen()); got != 201 {
+		t.Errorf("received %d messages, want 201", got)
+	}
+}
+
+func TestProgram_QuitBeforeRun(t *testing.T) {
+	p, seen := newTestProgram()
+	defer p.Close()
+
+	p.Send(recordMsg("dropped"))
+	p.Quit()
+
+	waitRun(t, runAsync(p))
+	p.Wait()
+
+	if got := seen(); len(got) != 0 {
+		t.Errorf("messages = %v, want none after Quit before Run", got)
+	}
+}
+
+func TestProgram_SendAfterStopDoesNotBlock(t *testing.T) {
+	p, _ := newTestProgram()
+	defer p.Close()
+
+	p.Send(recordMsg("quit"))
+	waitRun(t, runAsync(p))
+
+	sent := make(chan struct{})
+	go func() {
+		p.Send(recordMsg("late"))
+		p.SendOutput("late")
+		p.SendInitialPrompt("late")
+		p.Quit()
+		p.Kill()
+		p.Wait()
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sending after the program stopped blocked")
+	}
+}
+
+func TestProgram_RunTwice(t *testing.T) {
+	p, _ := newTestProgram()
+	defer p.Close()
+
+	done := runAsync(p)
+	// Wait until the first Run has claimed the program
+	for {
+		p.mu.Lock()
+		state := p.state
+		p.mu.Unlock()
+		if state != programPending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := p.Run(); err != ErrProgramRunning {
+		t.Errorf("second Run() error = %v, want ErrProgramRunning", err)
+	}
+
+	p.Quit()
+	waitRun(t, done)
+}
+
+func TestProgram_RecordsEvents(t *testing.T) {
+	p, _ := newTestProgram()
+	defer p.Close()
+	p.events = openTestEventLog(t)
+
+	p.SendProgress(ProgressInfo{Iteration: 2, StepName: "review"})
+	p.SendOutput("buffered before Run")
+
+	periods := p.events.Periods()
+	if len(periods) != 1 || periods[0].Iteration != 2 || periods[0].Lines != 1 {
+		t.Errorf("Periods() = %+v, want one review period with one line", periods)
+	}
+}
+
+// panicModel panics when it receives a recordMsg.
+type panicModel struct{}
+
+func (m panicModel) Init() tea.Cmd { return nil }
+
+func (m panicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(recordMsg); ok {
+		panic("model exploded")
+	}
+	return m, nil
+}
+
+func (m panicModel) View() string { return "" }
+
+func TestProgram_RunReturnsPanicError(t *testing.T) {
+	p := newProgram(panicModel{}, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer())
+	defer p.Close()
+	p.Send(recordMsg("boom"))
+
+	var err error
+	select {
+	case err = <-runAsync(p):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return")
+	}
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Run() error = %v, want *PanicError", err)
+	}
+	if pe.Value != "model exploded" || !strings.Contains(string(pe.Stack), "panicModel.Update") {
+		t.Errorf("PanicError = %v with stack:\n%s", pe.Value, pe.Stack)
+	}
+	if !errors.Is(err, tea.ErrProgramPanic) {
+		t.Error("PanicError does not unwrap to tea.ErrProgramPanic")
+	}
+}
+
+func TestProgram_SetOutputObserver(t *testing.T) {
+	p, _ := newTestProgram()
+	defer p.Close()
+
+	var lines []string
+	p.SetOutputObserver(func(line string) { lines = append(lines, line) })
+	p.SendOutput("one")
+	p.Send(OutputBatchMsg{"two", "three"})
+	p.SendProgress(ProgressInfo{Iteration: 1})
+
+	if want := []string{"one", "two", "three"}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("observed %q, want %q", lines, want)
+	}
+}
@@ -317,12 +317,28 @@ func (m Model) renderSession(idx int, s session.Session) string {
 		b.WriteString("\n")
 	}
 
+	// Line 4: Worktree location
+	if s.Type == session.SessionTypeWorktree {
+		where := s.Path()
+		if s.Branch() != "" {
+			where += " (" + s.Branch() + ")"
+		}
+		pathLine := "       " + labelStyle.Render("Path: ") + valueStyle.Render(where)
+		b.WriteString(m.renderBorderedLine(pathLine, width))
+		b.WriteString("\n")
+	}
+
 	// Line 5: Created time
 	createdLine := "       " + labelStyle.Render("Created: ") + valueStyle.Render(formatTimeAgo(s.CreatedAt))
 	b.WriteString(m.renderBorderedLine(createdLine, width))
 	b.WriteString("\n")
 
-	// Line 6: Invalid reason if applicable
+	// Line 6: Progress
+	progressLine := "       " + labelStyle.Render("Progress: ") + valueStyle.Render(formatProgress(s))
+	b.WriteString(m.renderBorderedLine(progressLine, width))
+	b.WriteString("\n")
+
+	// Line 7: Invalid reason if applicable
 	if !s.Valid {
 		reasonLine := "       " + m.styles.Warning.Render("! "+s.InvalidReason)
 		b.WriteString(m.renderBorderedLine(reasonLine, width))
@@ -447,6 +463,16 @@ func formatSpecs(specs []string) string {
 	return util.IntToString(len(specs)) + " files"
 }
 
+// formatProgress summarises how far a session got and when it was last active.
+func formatProgress(s session.Session) string {
+	if s.RegularState == nil {
+		return s.Status()
+	}
+	return s.Status() + ", iteration " + util.IntToString(s.Iteration()) +
+		", " + util.FormatCost(s.Cost(), 2) + " " + util.CurrencyCode() +
+		", last active " + formatTimeAgo(s.LastActive())
+}
+
 // formatTimeAgo formats a time as relative duration.
 func formatTimeAgo(t time.Time) string {
 	if t.IsZero() {
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/flashingpumpkin/orbital/internal/session"
+	"github.com/flashingpumpkin/orbital/internal/state"
 	"github.com/flashingpumpkin/orbital/internal/util"
 )
 
@@ -429,6 +430,31 @@ func TestViewSessionList(t *testing.T) {
 	}
 }
 
+func TestViewSessionDetails(t *testing.T) {
+	sessions := []session.Session{
+		{
+			ID:             "1",
+			Type:           session.SessionTypeWorktree,
+			Name:           "Worktree feature",
+			SpecFiles:      []string{"docs/plans/auth.md"},
+			CreatedAt:      time.Now().Add(-3 * time.Hour),
+			UpdatedAt:      time.Now().Add(-2 * time.Hour),
+			Valid:          true,
+			RegularState:   &state.State{WorkingDir: "/repo-feature", Iteration: 6, TotalCost: 1.5},
+			WorktreeBranch: "feature",
+		},
+	}
+	m := New(sessions)
+	m = sendWindowSize(m, 120, 24)
+
+	view := m.View()
+	for _, want := range []string{"docs/plans/auth.md", "/repo-feature (feature)", "3 hours ago", "interrupted, iteration 6, $1.50", "last active 2 hours ago"} {
+		if !containsString(view, want) {
+			t.Errorf("expected view to contain %q:\n%s", want, view)
+		}
+	}
+}
+
 func TestViewCleanupDialog(t *testing.T) {
 	sessions := []session.Session{
 		{ID: "1", Name: "stale-session", Valid: false, InvalidReason: "Session not found"},
@@ -0,0 +1,134 @@
+// Package snapshot renders TUI views into golden files so layout regressions
+// show up as diffs rather than slipping past string-contains assertions.
+//
+// Views are rendered at fixed terminal sizes, stripped of ANSI sequences and
+// trailing whitespace, and compared with testdata/snapshots/<test>_<w>x<h>.golden.
+// Run the tests with -update to regenerate the golden files:
+//
+//	go test ./internal/tui/... -update
+package snapshot
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Dir is the directory golden files are stored in, relative to the package
+// under test.
+var Dir = filepath.Join("testdata", "snapshots")
+
+// update reports whether golden files should be rewritten. The flag is shared
+// with github.com/charmbracelet/x/exp/golden, which registers -update as well,
+// so both harnesses regenerate their files from the same flag.
+var update = func() func() bool {
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "update golden files")
+	}
+	return func() bool {
+		f := flag.Lookup("update")
+		return f != nil && f.Value.String() == "true"
+	}
+}()
+
+// Size is a terminal size to render at.
+type Size struct {
+	Width  int
+	Height int
+}
+
+// String returns the size as "WxH".
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// DefaultSizes are the sizes views are rendered at when none are given: the
+// minimum supported terminal and a typical wide one.
+var DefaultSizes = []Size{{Width: 80, Height: 24}, {Width: 120, Height: 40}}
+
+// StripANSI removes ANSI escape sequences from s.
+func StripANSI(s string) string {
+	return ansi.Strip(s)
+}
+
+// Normalize prepares rendered output for comparison: ANSI sequences and
+// trailing whitespace on each line are removed and the output ends with a
+// single newline.
+func Normalize(s string) string {
+	lines := strings.Split(StripANSI(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// Match compares got, normalised, with the golden file at path. With -update
+// the golden file is written instead.
+func Match(t testing.TB, path, got string) {
+	t.Helper()
+	got = Normalize(got)
+
+	if update() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create snapshot directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot %s (run with -update to create it): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("snapshot %s does not match (run with -update to accept):\n%s", path, diff(string(want), got))
+	}
+}
+
+// AssertSizes renders the view at each size and compares it with the test's
+// golden file for that size. DefaultSizes are used when sizes is empty.
+func AssertSizes(t *testing.T, render func(Size) string, sizes ...Size) {
+	t.Helper()
+	if len(sizes) == 0 {
+		sizes = DefaultSizes
+	}
+	for _, size := range sizes {
+		name := strings.ReplaceAll(t.Name(), "/", "_") + "_" + size.String() + ".golden"
+		Match(t, filepath.Join(Dir, name), render(size))
+	}
+}
+
+// diff returns a line-by-line comparison of the first lines that differ,
+// marking expected lines with "-" and actual lines with "+".
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&b, "line %d:\n- %q\n+ %q\n", i+1, w, g)
+		if shown++; shown == 10 {
+			b.WriteString("...\n")
+			break
+		}
+	}
+	return b.String()
+}
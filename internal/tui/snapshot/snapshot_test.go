@@ -0,0 +1,67 @@
+package snapshot
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "strips ansi", in: "\x1b[1;32mok\x1b[0m", want: "ok\n"},
+		{name: "trims trailing spaces", in: "a  \nb\t\n", want: "a\nb\n"},
+		{name: "single final newline", in: "a\n\n\n", want: "a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// setUpdate sets the -update flag for the duration of a test.
+func setUpdate(t *testing.T, v string) {
+	t.Helper()
+	if err := flag.Set("update", v); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = flag.Set("update", "false") })
+}
+
+func TestMatch_UpdateThenCompare(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots", "view.golden")
+
+	setUpdate(t, "true")
+	Match(t, path, "\x1b[31mheader\x1b[0m   \nbody")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+	if string(data) != "header\nbody\n" {
+		t.Errorf("snapshot = %q, want normalised output", data)
+	}
+
+	setUpdate(t, "false")
+	Match(t, path, "header\nbody")
+}
+
+func TestDiff(t *testing.T) {
+	got := diff("a\nb\nc\n", "a\nx\nc\n")
+	if !strings.Contains(got, "line 2") || !strings.Contains(got, `- "b"`) || !strings.Contains(got, `+ "x"`) {
+		t.Errorf("diff() = %q", got)
+	}
+}
+
+func TestSize_String(t *testing.T) {
+	if got := (Size{Width: 80, Height: 24}).String(); got != "80x24" {
+		t.Errorf("String() = %q, want 80x24", got)
+	}
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/flashingpumpkin/orbital/internal/tui/snapshot"
+)
+
+// snapshotClock is the fixed time views are rendered at.
+var snapshotClock = time.Date(2026, 1, 26, 12, 0, 0, 0, time.UTC)
+
+// renderSnapshot returns a render function for snapshot.AssertSizes that
+// builds a fresh model at each size, applies setup and renders it with a
+// fixed clock.
+func renderSnapshot(t *testing.T, setup func(m *Model)) func(snapshot.Size) string {
+	t.Helper()
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("TERM", "dumb")
+
+	return func(size snapshot.Size) string {
+		m := NewModel()
+		m.SetClock(func() time.Time { return snapshotClock })
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+		m = updated.(Model)
+		if setup != nil {
+			setup(&m)
+		}
+		return m.View()
+	}
+}
+
+func TestSnapshot_IterationTimer(t *testing.T) {
+	snapshot.AssertSizes(t, renderSnapshot(t, func(m *Model) {
+		m.SetProgress(ProgressInfo{
+			Iteration:        4,
+			MaxIteration:     20,
+			StepName:         "implement",
+			StepPosition:     1,
+			StepTotal:        2,
+			TokensIn:         42000,
+			TokensOut:        9000,
+			Cost:             2.25,
+			Budget:           10.00,
+			IterationTimeout: 30 * time.Minute,
+			IterationStart:   snapshotClock.Add(-12*time.Minute - 30*time.Second),
+		})
+		m.SetTasks([]Task{
+			{ID: "1", Content: "Parse config", Status: "completed"},
+			{ID: "2", Content: "Wire the loop", Status: "in_progress"},
+		})
+		for _, line := range []string{"Reading internal/config/file.go", "\x1b[32mtests passed\x1b[0m"} {
+			m.AppendOutput(line)
+		}
+	}))
+}
+
+func TestSnapshot_Empty(t *testing.T) {
+	snapshot.AssertSizes(t, renderSnapshot(t, nil))
+}
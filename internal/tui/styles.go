@@ -59,6 +59,9 @@ const (
 	BarFilled = "█"
 	BarEmpty  = "░"
 	BarWidth  = 20
+
+	// MaxBarWidth is the widest progress bar the layout config allows.
+	MaxBarWidth = 60
 )
 
 // Status indicator icons
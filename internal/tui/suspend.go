@@ -0,0 +1,24 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// filter watches messages before Bubble Tea handles them. A tea.SuspendMsg
+// (from ctrl+z or an external SIGTSTP) marks the program as suspending, so the
+// SIGTSTP Bubble Tea then raises to stop the process is let through instead of
+// being taken for another suspend request.
+func (p *Program) filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	if _, ok := msg.(tea.SuspendMsg); ok {
+		p.suspending.Store(true)
+	}
+	return msg
+}
+
+// resumeCmd repaints the whole screen after the process was stopped: the
+// shell wrote over it in the meantime and the terminal may have been resized
+// or reattached at a different size. Asking for the window size re-runs the
+// layout and makes the renderer redraw every line.
+func resumeCmd() tea.Cmd {
+	return tea.Batch(tea.ClearScreen, tea.WindowSize())
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package tui
+
+// watchJobControl does nothing where there is no job control.
+func (p *Program) watchJobControl() func() {
+	return func() {}
+}
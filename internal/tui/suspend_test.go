@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModelUpdateCtrlZSuspends(t *testing.T) {
+	m := NewModel()
+	m.chatOpen = true // ctrl+z works from any pane
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if cmd == nil {
+		t.Fatal("expected a suspend command from ctrl+z")
+	}
+	if _, ok := cmd().(tea.SuspendMsg); !ok {
+		t.Error("ctrl+z did not return tea.Suspend")
+	}
+}
+
+func TestModelUpdateRepaints(t *testing.T) {
+	for _, msg := range []tea.Msg{tea.ResumeMsg{}, tea.FocusMsg{}} {
+		m := NewModel()
+		if _, cmd := m.Update(msg); cmd == nil {
+			t.Errorf("%T: expected a repaint command", msg)
+		}
+	}
+}
+
+func TestProgram_FilterMarksSuspend(t *testing.T) {
+	p := newProgram(NewModel())
+
+	if got := p.filter(nil, tea.KeyMsg{Type: tea.KeyCtrlZ}); got == nil {
+		t.Error("filter dropped a message")
+	}
+	if p.suspending.Load() {
+		t.Error("suspending set by a key press")
+	}
+	p.filter(nil, tea.SuspendMsg{})
+	if !p.suspending.Load() {
+		t.Error("suspending not set by tea.SuspendMsg")
+	}
+}
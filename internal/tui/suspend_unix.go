@@ -0,0 +1,56 @@
+//go:build unix
+
+package tui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchJobControl makes job control signals leave the terminal usable. A
+// SIGTSTP from outside the TUI (kill -TSTP, or a shell that sends it) is
+// turned into a Bubble Tea suspend, which restores the terminal before the
+// process stops. A SIGCONT after a stop the TUI did not see (SIGSTOP) triggers
+// a full repaint. It returns a function that stops watching.
+func (p *Program) watchJobControl() func() {
+	tstp := make(chan os.Signal, 1)
+	cont := make(chan os.Signal, 1)
+	signal.Notify(tstp, syscall.SIGTSTP)
+	signal.Notify(cont, syscall.SIGCONT)
+	done := make(chan struct{})
+
+	go func() {
+		defer signal.Stop(cont)
+		defer signal.Stop(tstp)
+		for {
+			select {
+			case <-done:
+				return
+			case <-tstp:
+				if !p.suspending.Load() {
+					// Bubble Tea restores the terminal, then raises SIGTSTP again
+					go p.program.Send(tea.SuspendMsg{})
+					continue
+				}
+				// Stop for real: the default action of SIGTSTP
+				signal.Reset(syscall.SIGTSTP)
+				_ = syscall.Kill(0, syscall.SIGTSTP)
+				select {
+				case <-cont:
+				case <-done:
+					return
+				}
+				// Bubble Tea sends tea.ResumeMsg itself
+				p.suspending.Store(false)
+				signal.Notify(tstp, syscall.SIGTSTP)
+			case <-cont:
+				go p.program.Send(tea.ResumeMsg{})
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
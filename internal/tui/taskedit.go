@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/flashingpumpkin/orbital/internal/tasks"
+)
+
+// TaskEditFunc sets the status of the task with the given ID and returns the
+// updated task list.
+type TaskEditFunc func(id, status string) ([]Task, error)
+
+// taskEditedMsg carries the task list after the operator changed a task's
+// status, and the line confirming it.
+type taskEditedMsg struct {
+	tasks []Task
+	line  string
+}
+
+// WithTaskEditing lets the operator focus the task panel (with `T`) and
+// change task statuses. Changes go to the program's task tracker, which
+// records them as corrections for the agent (see tasks.Tracker.SetStatus).
+func WithTaskEditing() Option {
+	return func(m *Model) {
+		m.taskEditing = true
+	}
+}
+
+// editTaskCmd sets task's status with fn and confirms the change, or reports
+// why it was refused.
+func editTaskCmd(fn TaskEditFunc, task Task, status string) tea.Cmd {
+	return func() tea.Msg {
+		updated, err := fn(task.ID, status)
+		if err != nil {
+			return OutputLineMsg("⚠ " + err.Error())
+		}
+		return taskEditedMsg{
+			tasks: updated,
+			line:  fmt.Sprintf("✎ Task %q marked %s; the agent is told at the next iteration", task.Content, statusLabel(status)),
+		}
+	}
+}
+
+// statusLabel returns a task status for display, e.g. "in progress".
+func statusLabel(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+// focusTasks moves the keyboard focus to the task panel, when it shows any
+// tasks and editing is enabled.
+func (m Model) focusTasks() (tea.Model, tea.Cmd) {
+	if m.taskEdit == nil || len(m.tasks) == 0 || m.layout.TasksVisible() == 0 {
+		return m, nil
+	}
+	m.tasksFocused = true
+	m.taskCursor = min(m.taskCursor, len(m.tasks)-1)
+	return m, nil
+}
+
+// handleTaskKey handles a key press while the task panel has the focus.
+// Other bindings are disabled until the focus returns to the output.
+func (m Model) handleTaskKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "T":
+		m.tasksFocused = false
+	case "up", "k":
+		if m.taskCursor > 0 {
+			m.taskCursor--
+		}
+	case "down", "j":
+		if m.taskCursor < len(m.tasks)-1 {
+			m.taskCursor++
+		}
+	case " ":
+		if m.taskCursor < len(m.tasks) {
+			task := m.tasks[m.taskCursor]
+			return m, editTaskCmd(m.taskEdit, task, tasks.NextStatus(task.Status))
+		}
+	}
+	return m, nil
+}
+
+// taskWindow returns the index of the first task shown, keeping the cursor
+// visible while the panel has the focus.
+func (m Model) taskWindow() int {
+	visible := m.layout.TasksVisible()
+	if !m.tasksFocused || m.taskCursor < visible {
+		return 0
+	}
+	return m.taskCursor - visible + 1
+}
+
+// renderTaskHelp renders the task panel keys in place of the help bar.
+func (m Model) renderTaskHelp() string {
+	return "  " + m.styles.HelpKey.Render("↑/↓") + m.styles.HelpBar.Render(" select  ") +
+		m.styles.HelpKey.Render("space") + m.styles.HelpBar.Render(" change status  ") +
+		m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" back to output")
+}
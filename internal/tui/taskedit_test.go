@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// taskModel returns a model showing the tasks of tracker, with editing
+// going to it.
+func taskModel(tracker *TaskTracker) Model {
+	m := chatModel(nil)
+	m.taskEdit = tracker.SetStatus
+	return update(m, TasksMsg(tracker.GetTasks()))
+}
+
+func TestTaskEdit_RequiresFunc(t *testing.T) {
+	m := update(chatModel(nil), TasksMsg{{ID: "1", Content: "Write parser", Status: "pending"}})
+	m = typeKeys(m, "T")
+	if m.tasksFocused {
+		t.Error("task panel focused without an edit function")
+	}
+	if strings.Contains(m.renderHelpBar(), " tasks") {
+		t.Error("help bar offers task editing without an edit function")
+	}
+}
+
+func TestTaskEdit_SelectAndToggle(t *testing.T) {
+	tracker := NewTaskTracker()
+	tracker.ProcessToolUse("TodoWrite", `{"todos": [
+		{"content": "Write parser", "status": "completed"},
+		{"content": "Add tests", "status": "pending"}
+	]}`)
+	m := taskModel(tracker)
+
+	m = typeKeys(m, "T")
+	if !m.tasksFocused {
+		t.Fatal("expected T to focus the task panel")
+	}
+	if !strings.Contains(m.View(), "▸ ") || !strings.Contains(m.renderHelpBar(), "change status") {
+		t.Error("selection or task keys not shown")
+	}
+
+	// Other bindings are disabled while the panel has the focus
+	m = typeKeys(m, "jw")
+	if m.taskCursor != 1 || m.outputTruncate {
+		t.Fatalf("taskCursor = %d, truncate = %v; want the second task selected and w ignored", m.taskCursor, m.outputTruncate)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a command to change the task")
+	}
+	m = update(m, cmd())
+	if m.tasks[1].Status != "in_progress" {
+		t.Errorf("task status = %q, want in_progress", m.tasks[1].Status)
+	}
+	if got := tracker.GetTasks()[1].Status; got != "in_progress" {
+		t.Errorf("tracker status = %q, want in_progress", got)
+	}
+	if corrections := tracker.TakeCorrections(); len(corrections) != 1 || corrections[0].Content != "Add tests" {
+		t.Errorf("corrections = %+v, want the change to Add tests", corrections)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.tasksFocused {
+		t.Error("expected esc to return the focus to the output")
+	}
+}
+
+func TestTaskEdit_RefusedChangeIsReported(t *testing.T) {
+	tracker := NewTaskTracker()
+	tracker.SetCodeTodos([]CodeTodo{{File: "main.go", Line: 3, Text: "handle errors"}})
+	m := typeKeys(taskModel(tracker), "T")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	if cmd == nil {
+		t.Fatal("expected a command to change the task")
+	}
+	if line, ok := cmd().(OutputLineMsg); !ok || !strings.Contains(string(line), "⚠") {
+		t.Errorf("refused change reported as %#v", line)
+	}
+}
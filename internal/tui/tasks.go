@@ -7,6 +7,10 @@ import (
 // TaskTracker is an alias to the shared tasks.Tracker type for TUI use.
 type TaskTracker = tasks.Tracker
 
+// CodeTodo is an alias to tasks.CodeTodo, a TODO(orbital) comment found in
+// a changed file.
+type CodeTodo = tasks.CodeTodo
+
 // NewTaskTracker creates a new TaskTracker using the shared tasks package.
 func NewTaskTracker() *TaskTracker {
 	return tasks.NewTracker()
@@ -0,0 +1,494 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// Event kinds recorded in the event log.
+const (
+	// EventPeriod marks the start of a new iteration/step period.
+	EventPeriod = "period"
+	// EventOutput is a single line of formatted output.
+	EventOutput = "output"
+	// EventScores records a gate judge's rubric scores.
+	EventScores = "scores"
+	// EventCheckpoint records a named checkpoint.
+	EventCheckpoint = "checkpoint"
+	// EventEscalation records a failing gate escalating to a stronger model.
+	EventEscalation = "escalation"
+	// EventGate records a gate's verdict (PASS, FAIL or TIMEOUT) in Line and
+	// how long it took in Duration.
+	EventGate = "gate"
+	// EventDryStep records a dry step and, in Line, the prompt it would have sent.
+	EventDryStep = "dry_step"
+	// EventInterjection records, in Line, an operator interjection prepended
+	// to an iteration's prompts.
+	EventInterjection = "interjection"
+	// EventParseError records, in Line, a malformed stream line that was
+	// quarantined and, in Malformed, how many have been so far.
+	EventParseError = "parse_error"
+)
+
+// Event is a single typed entry in the event log.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	Period    int       `json:"period"`
+	Iteration int       `json:"iteration"`
+	Step      string    `json:"step,omitempty"`
+	Line      string    `json:"line,omitempty"`
+
+	Scores     map[string]float64 `json:"scores,omitempty"`
+	Checkpoint string             `json:"checkpoint,omitempty"`
+	Model      string             `json:"model,omitempty"`
+	Failures   int                `json:"failures,omitempty"`
+	Malformed  int                `json:"malformed,omitempty"`
+	Duration   time.Duration      `json:"duration,omitempty"`
+}
+
+// Period is a contiguous stretch of output belonging to one iteration and step.
+// The same step can appear more than once in an iteration (e.g. a gate retry),
+// so periods are identified by their index rather than by iteration and step.
+type Period struct {
+	Index     int
+	Iteration int
+	Step      string
+	Start     time.Time
+	Lines     int
+}
+
+// Label returns a human-readable name such as "iteration 7, step review".
+func (p Period) Label() string {
+	label := "iteration " + util.IntToString(p.Iteration)
+	if p.Step != "" {
+		label += ", step " + p.Step
+	}
+	return label
+}
+
+// EventLog persists output events with timestamps to a JSONL file so earlier
+// periods of a run can be viewed after they have scrolled out of the output
+// buffer. It is safe for concurrent use.
+type EventLog struct {
+	mu      sync.Mutex
+	path    string
+	file    io.WriteCloser
+	w       *bufio.Writer
+	periods []Period
+	now     func() time.Time
+}
+
+// OpenEventLog creates (or appends to) the event log at path. The log is
+// encrypted when encryption at rest is enabled.
+func OpenEventLog(path string) (*EventLog, error) {
+	f, err := encrypt.OpenAppend(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	return &EventLog{
+		path: path,
+		file: f,
+		w:    bufio.NewWriter(f),
+		now:  time.Now,
+	}, nil
+}
+
+// Observe records a message sent to the TUI. Progress updates that change the
+// iteration or step start a new period; output lines are appended to the
+// current period, as are gate scores and results, checkpoints, escalations,
+// dry steps and interjections. Other messages are ignored.
+func (l *EventLog) Observe(msg tea.Msg) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch msg := msg.(type) {
+	case ProgressMsg:
+		l.startPeriod(msg.Iteration, msg.StepName)
+	case OutputLineMsg:
+		l.appendLine(string(msg))
+	case OutputBatchMsg:
+		for _, line := range msg {
+			l.appendLine(line)
+		}
+	case GateScoresMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(0, msg.Step)
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventScores, Period: p.Index, Iteration: p.Iteration, Step: msg.Step, Scores: msg.Scores})
+	case CheckpointMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(msg.Iteration, "")
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventCheckpoint, Period: p.Index, Iteration: msg.Iteration, Step: p.Step, Checkpoint: msg.Name})
+	case EscalationMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(0, msg.Step)
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventEscalation, Period: p.Index, Iteration: p.Iteration, Step: msg.Step, Model: msg.Model, Failures: msg.Failures})
+	case GateResultMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(0, msg.Step)
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventGate, Period: p.Index, Iteration: p.Iteration, Step: msg.Step, Line: msg.Result, Duration: msg.Duration})
+	case DryStepMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(0, msg.Step)
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventDryStep, Period: p.Index, Iteration: p.Iteration, Step: msg.Step, Line: msg.Prompt})
+	case InterjectionMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(msg.Iteration, "")
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventInterjection, Period: p.Index, Iteration: msg.Iteration, Step: p.Step, Line: msg.Text})
+	case ParseErrorMsg:
+		if len(l.periods) == 0 {
+			l.startPeriod(0, "")
+		}
+		p := l.periods[len(l.periods)-1]
+		l.write(Event{Time: l.now(), Kind: EventParseError, Period: p.Index, Iteration: p.Iteration, Step: p.Step, Line: msg.Line, Malformed: msg.Count})
+	}
+}
+
+// startPeriod begins a new period unless the iteration and step are unchanged.
+// Must be called with the mutex held.
+func (l *EventLog) startPeriod(iteration int, step string) {
+	if n := len(l.periods); n > 0 && l.periods[n-1].Iteration == iteration && l.periods[n-1].Step == step {
+		return
+	}
+
+	p := Period{
+		Index:     len(l.periods),
+		Iteration: iteration,
+		Step:      step,
+		Start:     l.now(),
+	}
+	l.periods = append(l.periods, p)
+	l.write(Event{Time: p.Start, Kind: EventPeriod, Period: p.Index, Iteration: iteration, Step: step})
+}
+
+// appendLine records an output line in the current period.
+// Must be called with the mutex held.
+func (l *EventLog) appendLine(line string) {
+	if len(l.periods) == 0 {
+		l.startPeriod(0, "")
+	}
+	p := &l.periods[len(l.periods)-1]
+	p.Lines++
+	l.write(Event{Time: l.now(), Kind: EventOutput, Period: p.Index, Iteration: p.Iteration, Step: p.Step, Line: line})
+}
+
+// write encodes an event. Write errors are ignored: the log is a convenience
+// and must never interrupt the run. Must be called with the mutex held.
+func (l *EventLog) write(e Event) {
+	if l.w == nil {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = l.w.Write(append(data, '\n'))
+}
+
+// Periods returns a copy of the periods recorded so far, oldest first.
+func (l *EventLog) Periods() []Period {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Period(nil), l.periods...)
+}
+
+// Lines returns the output lines recorded for the period with the given index.
+func (l *EventLog) Lines(period int) ([]string, error) {
+	l.mu.Lock()
+	if l.w != nil {
+		if err := l.w.Flush(); err != nil {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("failed to flush event log: %w", err)
+		}
+	}
+	l.mu.Unlock()
+
+	f, err := encrypt.OpenFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// Output lines can be long (e.g. tool results), so allow up to 1MB per event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Kind == EventOutput && e.Period == period {
+			lines = append(lines, e.Line)
+		}
+	}
+	// The log is still being written, so an encrypted one has no final frame yet
+	if err := scanner.Err(); err != nil && !errors.Is(err, encrypt.ErrTruncated) {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return lines, nil
+}
+
+// ReadEventLog reads every event from the event log at path, decrypting it if
+// it is encrypted. Malformed entries are skipped. An encrypted log that does
+// not end with its final frame returns the events read along with an error
+// wrapping encrypt.ErrTruncated.
+func ReadEventLog(path string) ([]Event, error) {
+	f, err := encrypt.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var events []Event
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); errors.Is(err, encrypt.ErrTruncated) {
+		return events, fmt.Errorf("event log %s: %w", path, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return events, nil
+}
+
+// Close flushes and closes the log file.
+func (l *EventLog) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	flushErr := l.w.Flush()
+	closeErr := l.file.Close()
+	l.file, l.w = nil, nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return closeErr
+}
+
+// timelineLoadedMsg carries the output lines of a period selected in the timeline.
+type timelineLoadedMsg struct {
+	Period Period
+	Lines  []string
+	Err    error
+}
+
+// loadPeriodCmd creates a command that reads a period's output from the event log.
+func loadPeriodCmd(log *EventLog, period Period) tea.Cmd {
+	return func() tea.Msg {
+		lines, err := log.Lines(period.Index)
+		return timelineLoadedMsg{Period: period, Lines: lines, Err: err}
+	}
+}
+
+// openTimeline enters scrubber mode with the latest period selected.
+func (m Model) openTimeline() (tea.Model, tea.Cmd) {
+	m.timelineOpen = true
+	m.timelinePeriods = m.timeline.Periods()
+	m.timelineCursor = len(m.timelinePeriods) - 1
+	if m.timelineCursor < 0 {
+		m.timelineCursor = 0
+	}
+	m.timelineViewing = false
+	m.timelineLines = nil
+	m.timelineErr = nil
+	return m, nil
+}
+
+// handleTimelineKey handles a key press while the timeline scrubber is open.
+// While picking, up/down select a period and enter shows its output; while
+// viewing, the scroll keys move through the output and esc returns to the list.
+func (m Model) handleTimelineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "t":
+		m.timelineOpen = false
+		return m, nil
+	case "esc":
+		if m.timelineViewing {
+			m.timelineViewing = false
+			return m, nil
+		}
+		m.timelineOpen = false
+		return m, nil
+	}
+
+	if m.timelineViewing {
+		maxScroll := len(m.timelineWrapped()) - m.timelineBodyHeight()
+		if maxScroll < 0 {
+			maxScroll = 0
+		}
+		switch msg.String() {
+		case "up", "k":
+			m.timelineScroll--
+		case "down", "j":
+			m.timelineScroll++
+		case "pgup":
+			m.timelineScroll -= m.timelineBodyHeight() / 2
+		case "pgdown":
+			m.timelineScroll += m.timelineBodyHeight() / 2
+		case "home":
+			m.timelineScroll = 0
+		case "end":
+			m.timelineScroll = maxScroll
+		}
+		m.timelineScroll = max(0, min(m.timelineScroll, maxScroll))
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.timelineCursor > 0 {
+			m.timelineCursor--
+		}
+	case "down", "j":
+		if m.timelineCursor < len(m.timelinePeriods)-1 {
+			m.timelineCursor++
+		}
+	case "home":
+		m.timelineCursor = 0
+	case "end":
+		m.timelineCursor = max(0, len(m.timelinePeriods)-1)
+	case "enter":
+		if m.timelineCursor < len(m.timelinePeriods) {
+			return m, loadPeriodCmd(m.timeline, m.timelinePeriods[m.timelineCursor])
+		}
+	}
+	return m, nil
+}
+
+// handleTimelineLoaded shows the output of the loaded period.
+func (m Model) handleTimelineLoaded(msg timelineLoadedMsg) Model {
+	if !m.timelineOpen {
+		return m
+	}
+	m.timelineViewing = true
+	m.timelinePeriod = msg.Period
+	m.timelineLines = msg.Lines
+	m.timelineErr = msg.Err
+	m.timelineScroll = 0
+	return m
+}
+
+// timelineBodyHeight is the number of content rows below the pane title.
+func (m Model) timelineBodyHeight() int {
+	return max(0, m.layout.ScrollAreaHeight-1)
+}
+
+// timelineWrapped returns the viewed period's output wrapped to the content width.
+func (m Model) timelineWrapped() []string {
+	if m.timelineErr != nil {
+		return []string{m.styles.Error.Render(IconError + " " + m.timelineErr.Error())}
+	}
+	if len(m.timelineLines) == 0 {
+		return []string{m.styles.Label.Render("No output recorded for this period")}
+	}
+	wrapWidth := max(1, m.layout.ContentWidth()-outputPaddingLeft)
+	wrap := lipgloss.NewStyle().Width(wrapWidth).PaddingLeft(outputPaddingLeft)
+	return strings.Split(wrap.Render(strings.Join(m.timelineLines, "\n")), "\n")
+}
+
+// renderTimelinePane renders the period list or the selected period's output
+// in the main content area.
+func (m Model) renderTimelinePane() string {
+	height := m.layout.ScrollAreaHeight
+	contentWidth := max(0, m.layout.ContentWidth())
+	if height <= 0 {
+		return ""
+	}
+
+	border := m.styles.Border.Render(BoxVertical)
+
+	var title string
+	var body []string
+	if m.timelineViewing {
+		title = m.styles.Header.Render(" ⏪ "+m.timelinePeriod.Label()) +
+			m.styles.Label.Render("  "+m.timelinePeriod.Start.Format("15:04:05"))
+		wrapped := m.timelineWrapped()
+		end := min(len(wrapped), m.timelineScroll+m.timelineBodyHeight())
+		body = wrapped[min(m.timelineScroll, end):end]
+	} else {
+		title = m.styles.Header.Render(" ⏪ Timeline") + m.styles.Label.Render("  select a period to view its output")
+		body = m.renderTimelineList()
+	}
+
+	lines := []string{padChatLine(border, title, contentWidth)}
+	for _, line := range body {
+		lines = append(lines, padChatLine(border, line, contentWidth))
+	}
+	for len(lines) < height {
+		lines = append(lines, border+strings.Repeat(" ", contentWidth)+border)
+	}
+	return strings.Join(lines[:height], "\n")
+}
+
+// renderTimelineList renders the recorded periods, keeping the cursor visible.
+func (m Model) renderTimelineList() []string {
+	if len(m.timelinePeriods) == 0 {
+		return []string{"  " + m.styles.Label.Render("No output recorded yet")}
+	}
+
+	visible := m.timelineBodyHeight()
+	start := 0
+	if m.timelineCursor >= visible {
+		start = m.timelineCursor - visible + 1
+	}
+
+	var lines []string
+	for i := start; i < len(m.timelinePeriods) && len(lines) < visible; i++ {
+		p := m.timelinePeriods[i]
+		text := p.Start.Format("15:04:05") + "  " + p.Label() + "  (" + util.IntToString(p.Lines) + " lines)"
+		if i == m.timelineCursor {
+			lines = append(lines, m.styles.Header.Render(" ▸ "+text))
+		} else {
+			lines = append(lines, "   "+m.styles.Value.Render(text))
+		}
+	}
+	return lines
+}
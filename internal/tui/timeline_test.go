@@ -0,0 +1,370 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/flashingpumpkin/orbital/internal/encrypt"
+)
+
+func openTestEventLog(t *testing.T) *EventLog {
+	t.Helper()
+	log, err := OpenEventLog(filepath.Join(t.TempDir(), "runs", "session.events.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	return log
+}
+
+func TestEventLog_RecordsPeriods(t *testing.T) {
+	log := openTestEventLog(t)
+
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "implement"})
+	log.Observe(OutputLineMsg("writing code"))
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "implement", Cost: 0.5}) // same period
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "review"})
+	log.Observe(OutputBatchMsg{"reviewing", "<gate>FAIL</gate>"})
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "implement"}) // retry is a new period
+	log.Observe(OutputLineMsg("fixing"))
+	log.Observe(StatsMsg{Cost: 1}) // ignored
+
+	periods := log.Periods()
+	if len(periods) != 3 {
+		t.Fatalf("len(Periods()) = %d, want 3: %+v", len(periods), periods)
+	}
+	wantLabels := []string{"iteration 1, step implement", "iteration 1, step review", "iteration 1, step implement"}
+	wantLines := []int{1, 2, 1}
+	for i, p := range periods {
+		if p.Index != i || p.Label() != wantLabels[i] || p.Lines != wantLines[i] {
+			t.Errorf("period %d = %+v (%q), want label %q with %d lines", i, p, p.Label(), wantLabels[i], wantLines[i])
+		}
+	}
+
+	lines, err := log.Lines(1)
+	if err != nil {
+		t.Fatalf("Lines() error = %v", err)
+	}
+	if strings.Join(lines, "|") != "reviewing|<gate>FAIL</gate>" {
+		t.Errorf("Lines(1) = %q", lines)
+	}
+}
+
+func TestEventLog_OutputBeforeProgress(t *testing.T) {
+	log := openTestEventLog(t)
+	log.Observe(OutputLineMsg("early"))
+
+	periods := log.Periods()
+	if len(periods) != 1 || periods[0].Label() != "iteration 0" || periods[0].Lines != 1 {
+		t.Errorf("Periods() = %+v, want one unnamed period with the early line", periods)
+	}
+}
+
+func TestEventLog_RecordsGateScores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 2, StepName: "review"})
+	log.Observe(GateScoresMsg{Step: "review", Scores: map[string]float64{"overall": 4}})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var e Event
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &e); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if e.Kind != EventScores || e.Iteration != 2 || e.Step != "review" || e.Scores["overall"] != 4 {
+		t.Errorf("scores event = %+v", e)
+	}
+}
+
+func TestEventLog_RecordsCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 3, StepName: "implement"})
+	log.Observe(CheckpointMsg{Name: "tests green", Iteration: 3})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != EventCheckpoint || e.Iteration != 3 || e.Checkpoint != "tests green" {
+		t.Errorf("checkpoint event = %+v", e)
+	}
+}
+
+func TestEventLog_RecordsEscalations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 2, StepName: "review"})
+	log.Observe(EscalationMsg{Step: "review", Failures: 2, Model: "opus"})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != EventEscalation || e.Iteration != 2 || e.Step != "review" || e.Model != "opus" || e.Failures != 2 {
+		t.Errorf("escalation event = %+v", e)
+	}
+}
+
+func TestEventLog_RecordsGateResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 4, StepName: "review"})
+	log.Observe(GateResultMsg{Step: "review", Result: "TIMEOUT", Duration: 3 * time.Minute})
+	log.Observe(GateResultMsg{Step: "review", Result: "PASS", Duration: 72 * time.Second})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	gates := events[len(events)-2:]
+	for i, want := range []Event{
+		{Kind: EventGate, Iteration: 4, Step: "review", Line: "TIMEOUT", Duration: 3 * time.Minute},
+		{Kind: EventGate, Iteration: 4, Step: "review", Line: "PASS", Duration: 72 * time.Second},
+	} {
+		e := gates[i]
+		if e.Kind != want.Kind || e.Iteration != want.Iteration || e.Step != want.Step || e.Line != want.Line || e.Duration != want.Duration {
+			t.Errorf("gate event %d = %+v, want %+v", i, e, want)
+		}
+	}
+}
+
+func TestEventLog_RecordsDrySteps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "review"})
+	log.Observe(DryStepMsg{Step: "review", Prompt: "Review the code"})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != EventDryStep || e.Iteration != 1 || e.Step != "review" || e.Line != "Review the code" {
+		t.Errorf("dry step event = %+v", e)
+	}
+}
+
+func TestEventLog_RecordsInterjections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(InterjectionMsg{Iteration: 3, Text: "Stop refactoring; fix the build"})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != EventInterjection || e.Iteration != 3 || e.Line != "Stop refactoring; fix the build" {
+		t.Errorf("interjection event = %+v", e)
+	}
+}
+
+func TestEventLog_RecordsParseErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+
+	log.Observe(ProgressMsg{Iteration: 2, StepName: "implement"})
+	log.Observe(ParseErrorMsg{Count: 4, Line: `{"type":"assis`})
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	e := events[len(events)-1]
+	if e.Kind != EventParseError || e.Iteration != 2 || e.Step != "implement" || e.Malformed != 4 || e.Line != `{"type":"assis` {
+		t.Errorf("parse error event = %+v", e)
+	}
+}
+
+func TestEventLog_NilIsNoop(t *testing.T) {
+	var log *EventLog
+	log.Observe(OutputLineMsg("ignored"))
+	if log.Periods() != nil {
+		t.Error("nil log returned periods")
+	}
+	if err := log.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestEventLog_Encrypted(t *testing.T) {
+	key, err := encrypt.ParseKey("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt.SetKey(&key)
+	t.Cleanup(func() { encrypt.SetKey(nil) })
+
+	path := filepath.Join(t.TempDir(), "runs", "session.events.jsonl")
+	log, err := OpenEventLog(path)
+	if err != nil {
+		t.Fatalf("OpenEventLog() error = %v", err)
+	}
+	log.Observe(ProgressMsg{Iteration: 1, StepName: "implement"})
+	log.Observe(OutputLineMsg("confidential output"))
+
+	lines, err := log.Lines(0)
+	if err != nil {
+		t.Fatalf("Lines() error = %v", err)
+	}
+	if len(lines) != 1 || lines[0] != "confidential output" {
+		t.Errorf("Lines(0) = %q", lines)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encrypt.IsEncrypted(raw) || strings.Contains(string(raw), "confidential") {
+		t.Errorf("event log stored in plaintext: %q", raw)
+	}
+
+	events, err := ReadEventLog(path)
+	if err != nil {
+		t.Fatalf("ReadEventLog() error = %v", err)
+	}
+	if len(events) != 2 || events[1].Line != "confidential output" {
+		t.Errorf("ReadEventLog() = %+v", events)
+	}
+}
+
+func timelineModel(t *testing.T, log *EventLog) Model {
+	t.Helper()
+	m := NewModel()
+	m.timeline = log
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated.(Model)
+}
+
+func pressKey(m Model, key tea.KeyMsg) (Model, tea.Cmd) {
+	updated, cmd := m.Update(key)
+	return updated.(Model), cmd
+}
+
+func TestTimeline_RequiresEventLog(t *testing.T) {
+	m := timelineModel(t, nil)
+	m = typeKeys(m, "t")
+	if m.timelineOpen {
+		t.Error("timeline opened without an event log")
+	}
+	if strings.Contains(m.renderHelpBar(), "timeline") {
+		t.Error("help bar advertises the timeline without an event log")
+	}
+}
+
+func TestTimeline_ScrubToPeriod(t *testing.T) {
+	log := openTestEventLog(t)
+	log.Observe(ProgressMsg{Iteration: 6, StepName: "implement"})
+	log.Observe(OutputLineMsg("old implement output"))
+	log.Observe(ProgressMsg{Iteration: 7, StepName: "review"})
+	log.Observe(OutputLineMsg("review said FAIL"))
+	log.Observe(ProgressMsg{Iteration: 8, StepName: "implement"})
+
+	m := timelineModel(t, log)
+	m = typeKeys(m, "t")
+	if !m.timelineOpen {
+		t.Fatal("expected timeline to open on 't'")
+	}
+	if m.timelineCursor != 2 {
+		t.Errorf("cursor = %d, want latest period selected", m.timelineCursor)
+	}
+	if view := m.View(); !strings.Contains(view, "iteration 7, step review") {
+		t.Errorf("timeline list missing period:\n%s", view)
+	}
+
+	m, _ = pressKey(m, tea.KeyMsg{Type: tea.KeyUp})
+	m, cmd := pressKey(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to load the selected period")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	if !m.timelineViewing || m.timelinePeriod.Iteration != 7 {
+		t.Fatalf("viewing = %v, period = %+v, want iteration 7", m.timelineViewing, m.timelinePeriod)
+	}
+	view := m.View()
+	if !strings.Contains(view, "review said FAIL") || strings.Contains(view, "old implement output") {
+		t.Errorf("view does not show only the selected period:\n%s", view)
+	}
+
+	m, _ = pressKey(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.timelineViewing || !m.timelineOpen {
+		t.Error("esc while viewing should return to the period list")
+	}
+	m, _ = pressKey(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.timelineOpen {
+		t.Error("esc on the period list should return to live output")
+	}
+}
+
+func TestTimeline_LoadedAfterCloseIsIgnored(t *testing.T) {
+	m := timelineModel(t, openTestEventLog(t))
+	updated, _ := m.Update(timelineLoadedMsg{Period: Period{Iteration: 1, Start: time.Now()}})
+	if updated.(Model).timelineViewing {
+		t.Error("late load reopened a closed timeline")
+	}
+}
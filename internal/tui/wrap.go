@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/flashingpumpkin/orbital/internal/util"
+)
+
+// hScrollStep is the number of columns truncated output pans per key press.
+const hScrollStep = 8
+
+// truncateLines renders output lines without wrapping: each line is cut to
+// the columns [offset, offset+width) and indented by the output padding.
+func truncateLines(lines []string, offset, width int) string {
+	pad := strings.Repeat(" ", outputPaddingLeft)
+	cut := make([]string, len(lines))
+	for i, line := range lines {
+		cut[i] = pad + ansi.Cut(line, offset, offset+width)
+	}
+	return strings.Join(cut, "\n")
+}
+
+// toggleWrap switches output lines between wrapped and truncated rendering.
+// Switching back to wrapped lines leaves scroll lock and resets panning.
+func (m Model) toggleWrap() (tea.Model, tea.Cmd) {
+	m.outputTruncate = !m.outputTruncate
+	m.scrollLock = false
+	m.outputHScroll = 0
+	m.syncViewportContent()
+	return m, nil
+}
+
+// toggleScrollLock enters or leaves scroll lock, in which left/right pan the
+// output horizontally. Entering it truncates long lines if they were wrapped;
+// leaving it pans back to the first column.
+func (m Model) toggleScrollLock() (tea.Model, tea.Cmd) {
+	m.scrollLock = !m.scrollLock
+	if m.scrollLock {
+		m.outputTruncate = true
+	} else {
+		m.outputHScroll = 0
+	}
+	m.syncViewportContent()
+	return m, nil
+}
+
+// handleScrollLockKey handles the keys that behave differently in scroll
+// lock. It reports false for keys that keep their usual binding.
+func (m Model) handleScrollLockKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "left", "h":
+		m.panOutput(-hScrollStep)
+	case "right", "l":
+		m.panOutput(hScrollStep)
+	case "0":
+		m.panOutput(-m.outputHScroll)
+	case "esc", "L":
+		updated, cmd := m.toggleScrollLock()
+		return updated, cmd, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// panOutput moves truncated output delta columns to the right, stopping
+// where the longest line ends.
+func (m *Model) panOutput(delta int) {
+	width := m.viewport.Width - outputPaddingLeft
+	longest := 0
+	m.outputLines.Iterate(func(_ int, line string) bool {
+		if w := ansi.StringWidth(line); w > longest {
+			longest = w
+		}
+		return true
+	})
+
+	maxOffset := longest - width
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	m.outputHScroll += delta
+	if m.outputHScroll > maxOffset {
+		m.outputHScroll = maxOffset
+	}
+	if m.outputHScroll < 0 {
+		m.outputHScroll = 0
+	}
+	m.syncViewportContent()
+}
+
+// renderScrollLockHelp renders the help bar while scroll lock is on.
+func (m Model) renderScrollLockHelp() string {
+	return "  " + m.styles.Header.Render("scroll lock") + m.styles.HelpBar.Render(" col "+util.IntToString(m.outputHScroll+1)+"  ") +
+		m.styles.HelpKey.Render("←/→") + m.styles.HelpBar.Render(" pan  ") +
+		m.styles.HelpKey.Render("0") + m.styles.HelpBar.Render(" start  ") +
+		m.styles.HelpKey.Render("↑/↓") + m.styles.HelpBar.Render(" scroll  ") +
+		m.styles.HelpKey.Render("esc") + m.styles.HelpBar.Render(" unlock")
+}
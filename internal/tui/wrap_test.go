@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wrapModel returns a model with one line longer than the output width.
+func wrapModel(t *testing.T) (Model, string) {
+	t.Helper()
+	m := chatModel(nil)
+	long := "trace: " + strings.Repeat("abcdefghij", 20) + " END"
+	m.AppendOutput(long)
+	return m, long
+}
+
+func TestTruncateLines(t *testing.T) {
+	got := truncateLines([]string{"0123456789", "\x1b[31mred text\x1b[0m"}, 2, 4)
+	want := "  2345\n  \x1b[31md te\x1b[0m"
+	if got != want {
+		t.Errorf("truncateLines() = %q, want %q", got, want)
+	}
+}
+
+func TestWrap_ToggleTruncatesLongLines(t *testing.T) {
+	m, _ := wrapModel(t)
+	if !strings.Contains(m.View(), "END") {
+		t.Fatal("wrapped output should show the end of the long line")
+	}
+
+	m = typeKeys(m, "w")
+	if !m.outputTruncate {
+		t.Fatal("expected w to switch to truncated lines")
+	}
+	view := m.View()
+	if strings.Contains(view, "END") {
+		t.Error("truncated output shows the end of the long line")
+	}
+	if !strings.Contains(view, "w wrap") {
+		t.Error("help bar does not offer to wrap again")
+	}
+
+	m = typeKeys(m, "w")
+	if m.outputTruncate || !strings.Contains(m.View(), "END") {
+		t.Error("expected w to wrap lines again")
+	}
+}
+
+func TestWrap_ScrollLockPans(t *testing.T) {
+	m, _ := wrapModel(t)
+	m = typeKeys(m, "L")
+	if !m.scrollLock || !m.outputTruncate {
+		t.Fatal("expected L to enter scroll lock with truncated lines")
+	}
+	if !strings.Contains(m.View(), "scroll lock col 1") {
+		t.Error("help bar does not show scroll lock")
+	}
+
+	tab := m.activeTab
+	for i := 0; i < 100; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+		m = updated.(Model)
+	}
+	if m.activeTab != tab {
+		t.Error("right switched tabs in scroll lock")
+	}
+	if !strings.Contains(m.View(), "END") {
+		t.Error("panning right did not reach the end of the long line")
+	}
+	if longest := len("trace: ") + 200 + len(" END"); m.outputHScroll != longest-(m.viewport.Width-outputPaddingLeft) {
+		t.Errorf("outputHScroll = %d, want it stopped at the end of the longest line", m.outputHScroll)
+	}
+
+	m = typeKeys(m, "0")
+	if m.outputHScroll != 0 {
+		t.Errorf("0 left the output at column %d", m.outputHScroll+1)
+	}
+
+	m = typeKeys(m, "l")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.scrollLock || m.outputHScroll != 0 {
+		t.Errorf("esc left scrollLock=%v at column %d, want unlocked at the start", m.scrollLock, m.outputHScroll+1)
+	}
+	if !m.outputTruncate {
+		t.Error("leaving scroll lock should keep lines truncated")
+	}
+}
@@ -0,0 +1,301 @@
+// Package update finds, downloads and installs orbital release binaries
+// published on GitHub, for the self-update command.
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Release channels selectable with --channel.
+const (
+	// ChannelStable is the latest tagged release.
+	ChannelStable = "stable"
+	// ChannelEdge is the rolling prerelease built from every push to main.
+	ChannelEdge = "edge"
+)
+
+// DefaultRepo is the GitHub repository releases are published to.
+const DefaultRepo = "flashingpumpkin/orbital-cli"
+
+// DefaultAPIURL is the GitHub REST API base URL.
+const DefaultAPIURL = "https://api.github.com"
+
+// ChecksumsAsset is the release asset listing the SHA-256 of every binary in
+// sha256sum format.
+const ChecksumsAsset = "checksums.txt"
+
+// edgeTag is the tag the edge prerelease is published under.
+const edgeTag = "edge"
+
+// maxBinarySize bounds a downloaded binary.
+const maxBinarySize = 200 << 20
+
+// maxMetadataSize bounds release metadata and checksum downloads.
+const maxMetadataSize = 1 << 20
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Update is the binary a release offers for one platform.
+type Update struct {
+	// Release is the release the binary belongs to.
+	Release *Release
+	// Asset is the binary.
+	Asset Asset
+	// SHA256 is the binary's checksum from the release's checksums file.
+	SHA256 string
+}
+
+// Client talks to the GitHub releases API.
+type Client struct {
+	// HTTP is the client requests are made with.
+	HTTP *http.Client
+	// APIURL is the API base URL, DefaultAPIURL unless testing.
+	APIURL string
+	// Repo is the "owner/name" repository releases are read from.
+	Repo string
+}
+
+// NewClient creates a client for DefaultRepo.
+func NewClient() *Client {
+	return &Client{
+		HTTP:   &http.Client{Timeout: 5 * time.Minute},
+		APIURL: DefaultAPIURL,
+		Repo:   DefaultRepo,
+	}
+}
+
+// ValidateChannel returns an error for an unknown release channel.
+func ValidateChannel(channel string) error {
+	switch channel {
+	case ChannelStable, ChannelEdge:
+		return nil
+	default:
+		return fmt.Errorf("invalid channel %q (valid: %s, %s)", channel, ChannelStable, ChannelEdge)
+	}
+}
+
+// AssetName returns the name of the release binary for goos and goarch,
+// e.g. "orbital-linux-amd64".
+func AssetName(goos, goarch string) string {
+	name := "orbital-" + goos + "-" + goarch
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Latest returns the newest release on channel.
+func (c *Client) Latest(ctx context.Context, channel string) (*Release, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return nil, err
+	}
+	path := "/releases/latest"
+	if channel == ChannelEdge {
+		path = "/releases/tags/" + edgeTag
+	}
+	data, err := c.get(ctx, strings.TrimRight(c.APIURL, "/")+"/repos/"+c.Repo+path, maxMetadataSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the latest %s release: %w", channel, err)
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &r, nil
+}
+
+// Find returns the binary for goos and goarch in the newest release on
+// channel, with the checksum it is verified against.
+func (c *Client) Find(ctx context.Context, channel, goos, goarch string) (*Update, error) {
+	r, err := c.Latest(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	name := AssetName(goos, goarch)
+	binary, ok := r.asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no binary for %s/%s", r.TagName, goos, goarch)
+	}
+	sums, ok := r.asset(ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", r.TagName, ChecksumsAsset)
+	}
+	data, err := c.get(ctx, sums.URL, maxMetadataSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+	checksums, err := ParseChecksums(data)
+	if err != nil {
+		return nil, err
+	}
+	sum, ok := checksums[name]
+	if !ok {
+		return nil, fmt.Errorf("%s in release %s has no entry for %s", ChecksumsAsset, r.TagName, name)
+	}
+	return &Update{Release: r, Asset: binary, SHA256: sum}, nil
+}
+
+// Download fetches the update's binary and verifies its checksum.
+func (c *Client) Download(ctx context.Context, u *Update) ([]byte, error) {
+	data, err := c.get(ctx, u.Asset.URL, maxBinarySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", u.Asset.Name, err)
+	}
+	if got := sha256Hex(data); got != u.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", u.Asset.Name, got, u.SHA256)
+	}
+	return data, nil
+}
+
+// get fetches url, failing on a non-2xx status or a body over limit bytes.
+func (c *Client) get(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", url, limit)
+	}
+	return data, nil
+}
+
+// asset returns the release asset called name.
+func (r *Release) asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ParseChecksums parses sha256sum output ("<hex>  <name>" per line) into a
+// map from file name to lower-case hex checksum.
+func ParseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksum line %q", line)
+		}
+		sum := strings.ToLower(fields[0])
+		if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 checksum %q", fields[0])
+		}
+		// sha256sum marks binary mode with a leading "*"
+		sums[strings.TrimPrefix(fields[1], "*")] = sum
+	}
+	return sums, scanner.Err()
+}
+
+// FileSHA256 returns the hex SHA-256 of the file at path.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sha256Hex returns the hex SHA-256 of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Install replaces the executable at path with data. The new binary is
+// written next to it and renamed into place, so path always holds either the
+// old or the new binary. Windows cannot replace a running executable, so
+// there the old one is moved aside to path+".old" first.
+func Install(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0111); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move old binary aside: %w", err)
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			_ = os.Rename(old, path)
+			return fmt.Errorf("failed to install new binary: %w", err)
+		}
+		return nil
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGitHub serves a stable and an edge release, each with one linux/amd64
+// binary and a checksums file.
+func fakeGitHub(t *testing.T, binary, checksums string) *Client {
+	t.Helper()
+	var srv *httptest.Server
+	release := func(tag string, prerelease bool) Release {
+		return Release{TagName: tag, Prerelease: prerelease, Assets: []Asset{
+			{Name: "orbital-linux-amd64", URL: srv.URL + "/download/" + tag + "/orbital-linux-amd64"},
+			{Name: ChecksumsAsset, URL: srv.URL + "/download/" + tag + "/" + ChecksumsAsset},
+		}}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(release("v1.2.0", false))
+	})
+	mux.HandleFunc("/repos/o/r/releases/tags/edge", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(release("edge", true))
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ChecksumsAsset) {
+			_, _ = fmt.Fprint(w, checksums)
+			return
+		}
+		_, _ = fmt.Fprint(w, binary)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &Client{HTTP: srv.Client(), APIURL: srv.URL, Repo: "o/r"}
+}
+
+func TestClient_FindAndDownload(t *testing.T) {
+	binary := "new binary"
+	checksums := sha256Hex([]byte(binary)) + "  orbital-linux-amd64\n" + sha256Hex([]byte("other")) + " *orbital-darwin-arm64\n"
+	c := fakeGitHub(t, binary, checksums)
+
+	for _, channel := range []string{ChannelStable, ChannelEdge} {
+		t.Run(channel, func(t *testing.T) {
+			u, err := c.Find(context.Background(), channel, "linux", "amd64")
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if channel == ChannelEdge && u.Release.TagName != "edge" {
+				t.Errorf("TagName = %q, want edge", u.Release.TagName)
+			}
+			if channel == ChannelStable && u.Release.TagName != "v1.2.0" {
+				t.Errorf("TagName = %q, want v1.2.0", u.Release.TagName)
+			}
+			data, err := c.Download(context.Background(), u)
+			if err != nil {
+				t.Fatalf("Download() error = %v", err)
+			}
+			if string(data) != binary {
+				t.Errorf("Download() = %q, want %q", data, binary)
+			}
+		})
+	}
+}
+
+func TestClient_Find_MissingPlatform(t *testing.T) {
+	c := fakeGitHub(t, "bin", "")
+	_, err := c.Find(context.Background(), ChannelStable, "plan9", "386")
+	if err == nil || !strings.Contains(err.Error(), "no binary for plan9/386") {
+		t.Errorf("Find() error = %v, want missing binary error", err)
+	}
+}
+
+func TestClient_Find_MissingChecksum(t *testing.T) {
+	c := fakeGitHub(t, "bin", sha256Hex([]byte("bin"))+"  orbital-darwin-arm64\n")
+	_, err := c.Find(context.Background(), ChannelStable, "linux", "amd64")
+	if err == nil || !strings.Contains(err.Error(), "no entry for orbital-linux-amd64") {
+		t.Errorf("Find() error = %v, want missing entry error", err)
+	}
+}
+
+func TestClient_Download_ChecksumMismatch(t *testing.T) {
+	c := fakeGitHub(t, "tampered", sha256Hex([]byte("original"))+"  orbital-linux-amd64\n")
+	u, err := c.Find(context.Background(), ChannelStable, "linux", "amd64")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if _, err := c.Download(context.Background(), u); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Download() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestClient_Latest_InvalidChannel(t *testing.T) {
+	if _, err := NewClient().Latest(context.Background(), "nightly"); err == nil {
+		t.Error("Latest() accepted an unknown channel")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	valid := strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "text mode", data: valid + "  orbital-linux-amd64\n", want: map[string]string{"orbital-linux-amd64": valid}},
+		{name: "binary mode and blank lines", data: "\n" + strings.ToUpper(valid) + " *orbital.exe\n\n", want: map[string]string{"orbital.exe": valid}},
+		{name: "short checksum", data: "abcd  orbital\n", wantErr: true},
+		{name: "missing name", data: valid + "\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChecksums([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseChecksums() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseChecksums() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("ParseChecksums()[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	if got := AssetName("darwin", "arm64"); got != "orbital-darwin-arm64" {
+		t.Errorf("AssetName(darwin, arm64) = %q", got)
+	}
+	if got := AssetName("windows", "amd64"); got != "orbital-windows-amd64.exe" {
+		t.Errorf("AssetName(windows, amd64) = %q", got)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orbital")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Install(path, []byte("new")); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Errorf("binary = %q, want new", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("mode = %v, want executable", info.Mode())
+	}
+	sum, err := FileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != sha256Hex([]byte("new")) {
+		t.Errorf("FileSHA256() = %s, want checksum of new binary", sum)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the binary", len(entries))
+	}
+}
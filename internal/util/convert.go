@@ -36,30 +36,18 @@ func IntToString(n int) string {
 	return string(runes)
 }
 
-// FormatNumber formats an integer with thousands separators (commas).
+// FormatNumber formats an integer with the display locale's thousands
+// separators (commas by default; see SetDisplay).
 // For example, 1234567 becomes "1,234,567" and -1234567 becomes "-1,234,567".
 func FormatNumber(n int) string {
+	display.RLock()
+	sep := display.locale.thousands
+	display.RUnlock()
+
 	// Handle negative numbers by formatting the absolute value and prepending minus
 	if n < 0 {
-		// Use IntToString which handles math.MinInt correctly
-		if n == math.MinInt {
-			// math.MinInt formatted with commas
-			return "-9,223,372,036,854,775,808"
-		}
-		return "-" + FormatNumber(-n)
-	}
-
-	s := IntToString(n)
-	if len(s) <= 3 {
-		return s
-	}
-
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && (len(s)-i)%3 == 0 {
-			result.WriteRune(',')
-		}
-		result.WriteRune(r)
+		// IntToString handles math.MinInt, which cannot be negated
+		return "-" + groupDigits(strings.TrimPrefix(IntToString(n), "-"), sep)
 	}
-	return result.String()
+	return groupDigits(IntToString(n), sep)
 }
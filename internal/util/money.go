@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultCurrency and DefaultLocale are used when no display settings are
+// configured: costs in US dollars with US separators.
+const (
+	DefaultCurrency = "USD"
+	DefaultLocale   = "en-US"
+)
+
+// currencyFormat describes how amounts in a currency are written.
+type currencyFormat struct {
+	symbol   string
+	decimals int // maximum decimal places (0 for currencies without minor units)
+}
+
+// currencies are the currency codes accepted by SetDisplay.
+var currencies = map[string]currencyFormat{
+	"USD": {symbol: "$", decimals: 4},
+	"EUR": {symbol: "€", decimals: 4},
+	"GBP": {symbol: "£", decimals: 4},
+	"CHF": {symbol: "CHF", decimals: 4},
+	"CAD": {symbol: "CA$", decimals: 4},
+	"AUD": {symbol: "A$", decimals: 4},
+	"INR": {symbol: "₹", decimals: 4},
+	"BRL": {symbol: "R$", decimals: 4},
+	"JPY": {symbol: "¥", decimals: 0},
+}
+
+// localeFormat describes a locale's number separators and where the currency
+// symbol goes.
+type localeFormat struct {
+	thousands   string
+	decimal     string
+	symbolAfter bool // "1.234,56 €" rather than "€1,234.56"
+}
+
+// locales are the locales accepted by SetDisplay.
+var locales = map[string]localeFormat{
+	"en-US": {thousands: ",", decimal: "."},
+	"en-GB": {thousands: ",", decimal: "."},
+	"en-AU": {thousands: ",", decimal: "."},
+	"en-CA": {thousands: ",", decimal: "."},
+	"en-IN": {thousands: ",", decimal: "."},
+	"ja-JP": {thousands: ",", decimal: "."},
+	"de-DE": {thousands: ".", decimal: ",", symbolAfter: true},
+	"es-ES": {thousands: ".", decimal: ",", symbolAfter: true},
+	"it-IT": {thousands: ".", decimal: ",", symbolAfter: true},
+	"nl-NL": {thousands: ".", decimal: ",", symbolAfter: true},
+	"pt-BR": {thousands: ".", decimal: ","},
+	"fr-FR": {thousands: "\u202f", decimal: ",", symbolAfter: true},
+	"de-CH": {thousands: "’", decimal: "."},
+}
+
+// display is the active display configuration.
+var display = struct {
+	sync.RWMutex
+	code     string
+	currency currencyFormat
+	locale   localeFormat
+	rate     float64
+}{
+	code:     DefaultCurrency,
+	currency: currencies[DefaultCurrency],
+	locale:   locales[DefaultLocale],
+	rate:     1,
+}
+
+// SetDisplay sets the currency and locale that costs and numbers are shown
+// in. Costs are tracked in US dollars; rate converts them to the display
+// currency (display = USD × rate) and affects display only, never budgets.
+// Empty values select the defaults and a zero rate means 1. Codes are
+// case-insensitive.
+func SetDisplay(currency, locale string, rate float64) error {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	code := strings.ToUpper(currency)
+	cf, ok := currencies[code]
+	if !ok {
+		return fmt.Errorf("unknown currency %q: must be one of %s", currency, strings.Join(sortedKeys(currencies), ", "))
+	}
+	lf, ok := lookupLocale(locale)
+	if !ok {
+		return fmt.Errorf("unknown locale %q: must be one of %s", locale, strings.Join(sortedKeys(locales), ", "))
+	}
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("currency rate must be a positive number, got %v", rate)
+	}
+	if rate == 0 {
+		rate = 1
+	}
+
+	display.Lock()
+	defer display.Unlock()
+	display.code = code
+	display.currency = cf
+	display.locale = lf
+	display.rate = rate
+	return nil
+}
+
+// lookupLocale finds a locale by name, ignoring case and accepting "_" for "-".
+func lookupLocale(name string) (localeFormat, bool) {
+	name = strings.ReplaceAll(name, "_", "-")
+	for key, lf := range locales {
+		if strings.EqualFold(key, name) {
+			return lf, true
+		}
+	}
+	return localeFormat{}, false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CurrencyCode returns the ISO code of the display currency, e.g. "USD".
+func CurrencyCode() string {
+	display.RLock()
+	defer display.RUnlock()
+	return display.code
+}
+
+// FormatCost converts a USD amount to the display currency and formats it
+// with the currency symbol, the locale's separators and the given number of
+// decimal places (fewer for currencies without minor units), e.g. "$1,234.56"
+// or "1.234,56 €".
+func FormatCost(usd float64, decimals int) string {
+	display.RLock()
+	cf, lf, rate := display.currency, display.locale, display.rate
+	display.RUnlock()
+
+	amount := usd * rate
+	if amount < 0 {
+		return "-" + formatCost(-amount, decimals, cf, lf)
+	}
+	return formatCost(amount, decimals, cf, lf)
+}
+
+func formatCost(amount float64, decimals int, cf currencyFormat, lf localeFormat) string {
+	if decimals > cf.decimals {
+		decimals = cf.decimals
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	scale := math.Pow10(decimals)
+	total := int(math.Round(amount * scale))
+	whole := total / int(scale)
+
+	number := groupDigits(IntToString(whole), lf.thousands)
+	if decimals > 0 {
+		frac := IntToString(total % int(scale))
+		number += lf.decimal + strings.Repeat("0", decimals-len(frac)) + frac
+	}
+
+	if lf.symbolAfter {
+		return number + " " + cf.symbol
+	}
+	if len(cf.symbol) > 1 && cf.symbol[len(cf.symbol)-1] >= 'A' && cf.symbol[len(cf.symbol)-1] <= 'Z' {
+		return cf.symbol + " " + number // "CHF 12.50"
+	}
+	return cf.symbol + number
+}
+
+// groupDigits inserts sep between groups of three digits of a non-negative
+// decimal string.
+func groupDigits(s, sep string) string {
+	if len(s) <= 3 {
+		return s
+	}
+	var result strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			result.WriteString(sep)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
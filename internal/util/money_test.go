@@ -0,0 +1,75 @@
+package util
+
+import "testing"
+
+// setDisplay sets the display settings for the duration of a test.
+func setDisplay(t *testing.T, currency, locale string, rate float64) {
+	t.Helper()
+	if err := SetDisplay(currency, locale, rate); err != nil {
+		t.Fatalf("SetDisplay() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetDisplay("", "", 0) })
+}
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		locale   string
+		rate     float64
+		usd      float64
+		decimals int
+		want     string
+	}{
+		{name: "default", usd: 1234.567, decimals: 2, want: "$1,234.57"},
+		{name: "four decimals", usd: 0.01234, decimals: 4, want: "$0.0123"},
+		{name: "negative", usd: -5.5, decimals: 2, want: "-$5.50"},
+		{name: "euro in germany", currency: "EUR", locale: "de-DE", usd: 1234.5, decimals: 2, want: "1.234,50 €"},
+		{name: "euro in france", currency: "eur", locale: "fr_FR", usd: 1234.5, decimals: 2, want: "1\u202f234,50 €"},
+		{name: "pound with rate", currency: "GBP", locale: "en-GB", rate: 0.5, usd: 10, decimals: 2, want: "£5.00"},
+		{name: "yen has no minor units", currency: "JPY", locale: "ja-JP", rate: 150, usd: 12.345, decimals: 4, want: "¥1,852"},
+		{name: "code symbol is spaced", currency: "CHF", locale: "de-CH", usd: 12.5, decimals: 2, want: "CHF 12.50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDisplay(t, tt.currency, tt.locale, tt.rate)
+			if got := FormatCost(tt.usd, tt.decimals); got != tt.want {
+				t.Errorf("FormatCost(%v, %d) = %q, want %q", tt.usd, tt.decimals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNumber_Locale(t *testing.T) {
+	setDisplay(t, "EUR", "de-DE", 0)
+	if got := FormatNumber(-1234567); got != "-1.234.567" {
+		t.Errorf("FormatNumber(-1234567) = %q, want %q", got, "-1.234.567")
+	}
+	if got := CurrencyCode(); got != "EUR" {
+		t.Errorf("CurrencyCode() = %q, want EUR", got)
+	}
+}
+
+func TestSetDisplay_Invalid(t *testing.T) {
+	t.Cleanup(func() { _ = SetDisplay("", "", 0) })
+	tests := []struct {
+		name     string
+		currency string
+		locale   string
+		rate     float64
+	}{
+		{name: "unknown currency", currency: "XYZ"},
+		{name: "unknown locale", locale: "xx-XX"},
+		{name: "negative rate", rate: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetDisplay(tt.currency, tt.locale, tt.rate); err == nil {
+				t.Error("SetDisplay() error = nil, want error")
+			}
+		})
+	}
+	if got := FormatCost(1, 2); got != "$1.00" {
+		t.Errorf("failed SetDisplay changed the display: FormatCost(1, 2) = %q", got)
+	}
+}
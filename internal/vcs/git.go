@@ -0,0 +1,61 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+)
+
+// gitBackend implements Backend with the git CLI.
+type gitBackend struct {
+	dir string
+}
+
+// Git returns a backend for the git work tree containing dir.
+func Git(dir string) Backend {
+	return gitBackend{dir: dir}
+}
+
+// Name implements Backend.
+func (g gitBackend) Name() string {
+	return BackendGit
+}
+
+// Head implements Backend with the HEAD commit.
+func (g gitBackend) Head(ctx context.Context) (string, error) {
+	return git.HeadCommit(ctx, g.dir)
+}
+
+// DiffStat implements Backend with `git diff --stat`.
+func (g gitBackend) DiffStat(ctx context.Context, from string) (string, error) {
+	return git.DiffStat(ctx, g.dir, from)
+}
+
+// ChangedFiles implements Backend.
+func (g gitBackend) ChangedFiles(ctx context.Context, from string) ([]string, error) {
+	return git.ChangedFiles(ctx, g.dir, from)
+}
+
+// TreeState implements Backend.
+func (g gitBackend) TreeState(ctx context.Context) (string, error) {
+	return git.TreeState(ctx, g.dir)
+}
+
+// Snapshot implements Backend with a snapshot commit kept under a ref in
+// git.SnapshotRefPrefix.
+func (g gitBackend) Snapshot(ctx context.Context, sessionID string, iteration int) (string, error) {
+	ref := git.SnapshotRef(sessionID, iteration)
+	message := fmt.Sprintf("orbital %s: iteration %d", sessionID, iteration)
+	return git.CreateSnapshot(ctx, g.dir, ref, message)
+}
+
+// Restore implements Backend.
+func (g gitBackend) Restore(ctx context.Context, id string) error {
+	return git.RestoreSnapshot(ctx, g.dir, id)
+}
+
+// DeleteSnapshots implements Backend.
+func (g gitBackend) DeleteSnapshots(ctx context.Context, sessionID string, keep int) error {
+	return git.DeleteSnapshots(ctx, g.dir, sessionID, keep)
+}
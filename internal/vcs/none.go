@@ -0,0 +1,52 @@
+package vcs
+
+import "context"
+
+// noneBackend is used outside version control. Every feature reports
+// ErrUnsupported so callers can disable it.
+type noneBackend struct{}
+
+// None returns a backend for directories without version control.
+func None() Backend {
+	return noneBackend{}
+}
+
+// Name implements Backend.
+func (noneBackend) Name() string {
+	return BackendNone
+}
+
+// Head implements Backend.
+func (noneBackend) Head(context.Context) (string, error) {
+	return "", ErrUnsupported
+}
+
+// DiffStat implements Backend.
+func (noneBackend) DiffStat(context.Context, string) (string, error) {
+	return "", ErrUnsupported
+}
+
+// ChangedFiles implements Backend.
+func (noneBackend) ChangedFiles(context.Context, string) ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+// TreeState implements Backend.
+func (noneBackend) TreeState(context.Context) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Snapshot implements Backend.
+func (noneBackend) Snapshot(context.Context, string, int) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Restore implements Backend.
+func (noneBackend) Restore(context.Context, string) error {
+	return ErrUnsupported
+}
+
+// DeleteSnapshots implements Backend.
+func (noneBackend) DeleteSnapshots(context.Context, string, int) error {
+	return ErrUnsupported
+}
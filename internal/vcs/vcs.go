@@ -0,0 +1,84 @@
+// Package vcs abstracts the version control features orbital relies on
+// (snapshots and rollback, diff summaries, and progress detection) so they
+// degrade cleanly in directories that are not under version control.
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+)
+
+// Backend names accepted by New.
+const (
+	// BackendAuto selects git inside a git work tree and none elsewhere.
+	BackendAuto = "auto"
+	// BackendGit uses the git CLI.
+	BackendGit = "git"
+	// BackendNone disables version control features.
+	BackendNone = "none"
+)
+
+// ErrUnsupported is returned by backends that cannot provide a feature, such
+// as snapshots outside version control.
+var ErrUnsupported = errors.New("not supported without version control")
+
+// Backend is a version control system for a working directory.
+type Backend interface {
+	// Name returns the backend name, e.g. "git" or "none".
+	Name() string
+
+	// Head returns an identifier for the current revision.
+	Head(ctx context.Context) (string, error)
+
+	// DiffStat summarises the changes from revision from to the working tree.
+	DiffStat(ctx context.Context, from string) (string, error)
+
+	// ChangedFiles lists the files changed since revision from, including
+	// uncommitted and untracked files, relative to the working directory.
+	ChangedFiles(ctx context.Context, from string) ([]string, error)
+
+	// TreeState describes the working tree. Equal results mean nothing was
+	// committed or changed in between.
+	TreeState(ctx context.Context) (string, error)
+
+	// Snapshot records the working tree as the given iteration of a session
+	// and returns an identifier for Restore.
+	Snapshot(ctx context.Context, sessionID string, iteration int) (string, error)
+
+	// Restore makes the working tree match a snapshot.
+	Restore(ctx context.Context, id string) error
+
+	// DeleteSnapshots removes a session's snapshots. Iterations at or below
+	// keep are retained; pass a negative keep to delete them all.
+	DeleteSnapshots(ctx context.Context, sessionID string, keep int) error
+}
+
+// New returns the named backend for dir. An empty name is BackendAuto.
+// BackendGit fails if dir is not inside a git work tree.
+func New(ctx context.Context, name, dir string) (Backend, error) {
+	switch name {
+	case "", BackendAuto:
+		return Detect(ctx, dir), nil
+	case BackendGit:
+		if !git.IsRepo(ctx, dir) {
+			return nil, fmt.Errorf("vcs is %q but %s is not a git repository", BackendGit, dir)
+		}
+		return Git(dir), nil
+	case BackendNone:
+		return None(), nil
+	default:
+		return nil, fmt.Errorf("unknown vcs %q: must be %s, %s, or %s", name, BackendAuto, BackendGit, BackendNone)
+	}
+}
+
+// Detect returns the git backend if dir is inside a git work tree, and the
+// none backend otherwise.
+func Detect(ctx context.Context, dir string) Backend {
+	if git.IsRepo(ctx, dir) {
+		return Git(dir)
+	}
+	return None()
+}
@@ -0,0 +1,184 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flashingpumpkin/orbital/internal/git"
+)
+
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if _, err := git.Run(ctx, dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	return dir
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	repo := initRepo(t)
+	plain := t.TempDir()
+
+	tests := []struct {
+		name     string
+		backend  string
+		dir      string
+		want     string
+		errMatch string
+	}{
+		{name: "auto in repo", backend: "", dir: repo, want: BackendGit},
+		{name: "auto outside repo", backend: BackendAuto, dir: plain, want: BackendNone},
+		{name: "git in repo", backend: BackendGit, dir: repo, want: BackendGit},
+		{name: "git outside repo", backend: BackendGit, dir: plain, errMatch: "not a git repository"},
+		{name: "none in repo", backend: BackendNone, dir: repo, want: BackendNone},
+		{name: "unknown", backend: "hg", dir: plain, errMatch: "unknown vcs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New(ctx, tt.backend, tt.dir)
+			if tt.errMatch != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMatch) {
+					t.Errorf("New() error = %v, want %q", err, tt.errMatch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if b.Name() != tt.want {
+				t.Errorf("New() backend = %q, want %q", b.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNone_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	b := None()
+
+	if _, err := b.Head(ctx); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Head() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := b.DiffStat(ctx, "x"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DiffStat() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := b.ChangedFiles(ctx, "x"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ChangedFiles() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := b.TreeState(ctx); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("TreeState() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := b.Snapshot(ctx, "sess", 1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Snapshot() error = %v, want ErrUnsupported", err)
+	}
+	if err := b.Restore(ctx, "x"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Restore() error = %v, want ErrUnsupported", err)
+	}
+	if err := b.DeleteSnapshots(ctx, "sess", -1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DeleteSnapshots() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestGit_SnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+	b := Git(dir)
+
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.Run(ctx, dir, "add", "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.Run(ctx, dir, "commit", "-q", "-m", "add a"); err != nil {
+		t.Fatal(err)
+	}
+	id, err := b.Snapshot(ctx, "sess", 1)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	before, err := b.TreeState(ctx)
+	if err != nil {
+		t.Fatalf("TreeState() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := b.TreeState(ctx); after == before {
+		t.Error("TreeState() unchanged after editing a file")
+	}
+
+	if err := b.Restore(ctx, id); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "one\n" {
+		t.Errorf("a.txt = %q after Restore(), want snapshot contents", data)
+	}
+	if err := b.DeleteSnapshots(ctx, "sess", -1); err != nil {
+		t.Errorf("DeleteSnapshots() error = %v", err)
+	}
+}
+
+func TestGit_ChangedFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := initRepo(t)
+	b := Git(dir)
+
+	for name, content := range map[string]string{"kept.txt": "one\n", "edited.txt": "one\n", "deleted.txt": "one\n", ".gitignore": "ignored.txt\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := git.Run(ctx, dir, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git.Run(ctx, dir, "commit", "-q", "-m", "add files"); err != nil {
+		t.Fatal(err)
+	}
+	head, err := b.Head(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "edited.txt"), []byte("two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "deleted.txt")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"new.txt", "ignored.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("new\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := b.ChangedFiles(ctx, head)
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if got, want := strings.Join(files, ","), "edited.txt,new.txt"; got != want {
+		t.Errorf("ChangedFiles() = %s, want %s", got, want)
+	}
+}
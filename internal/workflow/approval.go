@@ -0,0 +1,153 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Gate types for Step.GateType.
+const (
+	GateTypeModel         = "model"
+	GateTypeHuman         = "human"
+	GateTypeDiffEmptyFail = "diff-empty-fail"
+)
+
+// What a human gate does when nobody decides in time (Step.OnApprovalTimeout).
+const (
+	ApprovalTimeoutAbort = "abort"
+	ApprovalTimeoutFail  = "fail"
+	ApprovalTimeoutPass  = "pass"
+)
+
+// DefaultApprovalTimeout is how long a human gate waits for a decision by
+// default.
+const DefaultApprovalTimeout = time.Hour
+
+// ErrApprovalTimedOut is returned when nobody decided on a human gate in
+// time and the gate aborts the run.
+var ErrApprovalTimedOut = errors.New("approval timed out")
+
+// Approval is a person's decision on a human gate.
+type Approval struct {
+	// Approved passes the gate; otherwise it fails.
+	Approved bool
+
+	// By names who decided, if known.
+	By string
+
+	// Comment is the reason given, if any.
+	Comment string
+}
+
+// ApproveFunc asks a person to decide on a human gate and blocks until they
+// do. It returns ctx's error when ctx ends first, including when the gate's
+// approval timeout passes.
+type ApproveFunc func(ctx context.Context, step Step) (Approval, error)
+
+// SetApprover sets the function human gates wait on. A workflow with a human
+// gate fails at that gate while no approver is set.
+func (r *Runner) SetApprover(approve ApproveFunc) {
+	r.approve = approve
+}
+
+// IsHumanGate reports whether the step is a gate judged by a person.
+func (s *Step) IsHumanGate() bool {
+	return s.Gate && s.GateType == GateTypeHuman
+}
+
+// EffectiveApprovalTimeout returns the step's approval timeout or the default
+// if not set.
+func (s *Step) EffectiveApprovalTimeout() time.Duration {
+	if s.ApprovalTimeout > 0 {
+		return time.Duration(s.ApprovalTimeout)
+	}
+	return DefaultApprovalTimeout
+}
+
+// EffectiveOnApprovalTimeout returns the step's approval timeout policy or the
+// default if not set.
+func (s *Step) EffectiveOnApprovalTimeout() string {
+	if s.OnApprovalTimeout != "" {
+		return s.OnApprovalTimeout
+	}
+	return ApprovalTimeoutAbort
+}
+
+// validateGateType checks the step's gate type and the settings that depend
+// on it.
+func (s *Step) validateGateType() error {
+	switch s.GateType {
+	case "", GateTypeModel:
+	case GateTypeHuman, GateTypeDiffEmptyFail:
+		if !s.Gate {
+			return errors.New("gate_type requires gate = true")
+		}
+		kind := "human"
+		if s.GateType == GateTypeDiffEmptyFail {
+			kind = "diff"
+		}
+		if s.Panel != nil || s.Rubric != "" {
+			return fmt.Errorf("a %s gate cannot have a panel or rubric", kind)
+		}
+		if len(s.Artifacts) > 0 || s.Cacheable {
+			return fmt.Errorf("a %s gate cannot have artifacts or be cacheable", kind)
+		}
+	default:
+		return fmt.Errorf("unknown gate_type %q: must be %s, %s or %s", s.GateType, GateTypeModel, GateTypeHuman, GateTypeDiffEmptyFail)
+	}
+
+	if len(s.AllowUnchanged) > 0 && !s.IsDiffGate() {
+		return fmt.Errorf("allow_unchanged requires gate_type = %q", GateTypeDiffEmptyFail)
+	}
+
+	if !s.IsHumanGate() && (s.ApprovalTimeout != 0 || s.OnApprovalTimeout != "") {
+		return fmt.Errorf("approval_timeout and on_approval_timeout require gate_type = %q", GateTypeHuman)
+	}
+	if s.ApprovalTimeout < 0 {
+		return errors.New("approval_timeout cannot be negative")
+	}
+	switch s.OnApprovalTimeout {
+	case "", ApprovalTimeoutAbort, ApprovalTimeoutFail, ApprovalTimeoutPass:
+		return nil
+	default:
+		return fmt.Errorf("unknown on_approval_timeout %q: must be %s, %s or %s",
+			s.OnApprovalTimeout, ApprovalTimeoutAbort, ApprovalTimeoutFail, ApprovalTimeoutPass)
+	}
+}
+
+// awaitApproval waits for a person to decide on a human gate and returns the
+// gate's result. The decision's comment is the step's output. When nobody
+// decides in time the step's timeout policy applies.
+func (r *Runner) awaitApproval(ctx context.Context, step Step) (*ExecutionResult, GateResult, error) {
+	if r.approve == nil {
+		return nil, GateNotFound, errors.New("human gate but no approver is set")
+	}
+	timeout := step.EffectiveApprovalTimeout()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	approval, err := r.approve(waitCtx, step)
+	if err != nil {
+		// Only the approval timeout falls back to the policy
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			return nil, GateNotFound, err
+		}
+		output := fmt.Sprintf("No decision within %s", formatDuration(timeout))
+		switch step.EffectiveOnApprovalTimeout() {
+		case ApprovalTimeoutPass:
+			return &ExecutionResult{StepName: step.Name, Output: output}, GatePassed, nil
+		case ApprovalTimeoutFail:
+			return &ExecutionResult{StepName: step.Name, Output: output}, GateFailed, nil
+		default:
+			return nil, GateNotFound, fmt.Errorf("%w after %s", ErrApprovalTimedOut, formatDuration(timeout))
+		}
+	}
+
+	gateResult := GateFailed
+	if approval.Approved {
+		gateResult = GatePassed
+	}
+	return &ExecutionResult{StepName: step.Name, Output: approval.Comment}, gateResult, nil
+}
@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deployWorkflow has a human gate between a build and a deploy step, sending
+// rejections back to the build.
+func deployWorkflow(gate Step) *Workflow {
+	gate.Name = "sign-off"
+	gate.Gate = true
+	gate.GateType = GateTypeHuman
+	gate.OnFail = "build"
+	return &Workflow{Steps: []Step{
+		{Name: "build", Prompt: "Build"},
+		gate,
+		{Name: "deploy", Prompt: "Deploy"},
+	}}
+}
+
+func TestRunner_HumanGate(t *testing.T) {
+	exec := newMockExecutor()
+	var asked []string
+	decisions := []Approval{{Approved: false, Comment: "Tests are missing"}, {Approved: true, By: "sam"}}
+	runner := NewRunner(deployWorkflow(Step{Prompt: "Check the staging build"}), exec)
+	runner.SetApprover(func(ctx context.Context, step Step) (Approval, error) {
+		asked = append(asked, step.Name+": "+step.Prompt)
+		d := decisions[0]
+		decisions = decisions[1:]
+		return d, nil
+	})
+
+	var outputs []string
+	runner.SetCallback(func(info StepInfo, result *ExecutionResult, gateResult GateResult) error {
+		if info.Name == "sign-off" {
+			outputs = append(outputs, result.Output)
+		}
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps {
+		t.Error("CompletedAllSteps = false, want true")
+	}
+	// The gate is never sent to Claude; the rejection reruns the build
+	if want := []string{"build", "build", "deploy"}; !reflect.DeepEqual(exec.calls, want) {
+		t.Errorf("calls = %v, want %v", exec.calls, want)
+	}
+	if len(asked) != 2 || asked[0] != "sign-off: Check the staging build" {
+		t.Errorf("approver asked %v, want the gate twice", asked)
+	}
+	if want := []string{"Tests are missing", ""}; !reflect.DeepEqual(outputs, want) {
+		t.Errorf("gate outputs = %q, want %q", outputs, want)
+	}
+}
+
+func TestRunner_HumanGateTimeout(t *testing.T) {
+	waitForTimeout := func(ctx context.Context, step Step) (Approval, error) {
+		<-ctx.Done()
+		return Approval{}, ctx.Err()
+	}
+
+	tests := []struct {
+		policy    string
+		wantCalls []string
+		wantErr   error
+	}{
+		{policy: "", wantCalls: []string{"build"}, wantErr: ErrApprovalTimedOut},
+		{policy: ApprovalTimeoutPass, wantCalls: []string{"build", "deploy"}},
+		{policy: ApprovalTimeoutFail, wantCalls: []string{"build", "build", "build"}, wantErr: ErrMaxGateRetriesExceeded},
+	}
+	for _, tt := range tests {
+		t.Run("policy "+tt.policy, func(t *testing.T) {
+			w := deployWorkflow(Step{ApprovalTimeout: Duration(10 * time.Millisecond), OnApprovalTimeout: tt.policy})
+			exec := newMockExecutor()
+			runner := NewRunner(w, exec)
+			runner.SetApprover(waitForTimeout)
+
+			_, err := runner.Run(context.Background())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(exec.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", exec.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunner_HumanGateErrors(t *testing.T) {
+	t.Run("no approver", func(t *testing.T) {
+		_, err := NewRunner(deployWorkflow(Step{}), newMockExecutor()).Run(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "no approver") {
+			t.Errorf("Run() error = %v, want no approver", err)
+		}
+	})
+
+	t.Run("cancelled run is not a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		runner := NewRunner(deployWorkflow(Step{OnApprovalTimeout: ApprovalTimeoutPass}), newMockExecutor())
+		runner.SetApprover(func(ctx context.Context, step Step) (Approval, error) {
+			cancel()
+			return Approval{}, ctx.Err()
+		})
+		_, err := runner.Run(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run() error = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestStep_ValidateGateType(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    Step
+		wantErr string
+	}{
+		{name: "model gate", step: Step{Gate: true, GateType: GateTypeModel}},
+		{name: "human gate", step: Step{Gate: true, GateType: GateTypeHuman, ApprovalTimeout: Duration(time.Hour), OnApprovalTimeout: "pass"}},
+		{name: "unknown type", step: Step{Gate: true, GateType: "robot"}, wantErr: `unknown gate_type "robot"`},
+		{name: "not a gate", step: Step{GateType: GateTypeHuman}, wantErr: "gate_type requires gate = true"},
+		{name: "human panel", step: Step{Gate: true, GateType: GateTypeHuman, Panel: &Panel{Agents: []string{"a"}}}, wantErr: "panel or rubric"},
+		{name: "human artifacts", step: Step{Gate: true, GateType: GateTypeHuman, Artifacts: []string{"plan.md"}}, wantErr: "artifacts"},
+		{name: "timeout on model gate", step: Step{Gate: true, ApprovalTimeout: Duration(time.Hour)}, wantErr: "require gate_type"},
+		{name: "unknown policy", step: Step{Gate: true, GateType: GateTypeHuman, OnApprovalTimeout: "retry"}, wantErr: `unknown on_approval_timeout "retry"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.step.validateGateType()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateGateType() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateGateType() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflow_Validate_HumanGateNeedsNoPrompt(t *testing.T) {
+	w := deployWorkflow(Step{})
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	w.Steps[1].GateType = GateTypeModel
+	if err := w.Validate(); err == nil || !strings.Contains(err.Error(), "prompt is required") {
+		t.Errorf("Validate() error = %v, want prompt is required", err)
+	}
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrArtifactMissing is returned when a step still has not written its
+// declared artifacts after being retried.
+var ErrArtifactMissing = errors.New("step artifact missing")
+
+// ArtifactsRequiredPrompt is appended to the prompt of a step that declares
+// artifacts. The verb is replaced with the list of artifact paths.
+const ArtifactsRequiredPrompt = `
+
+---
+ARTIFACTS: This step must create or update the following file(s):
+%s
+---
+`
+
+// ArtifactsPrompt is appended to a step prompt that does not place
+// {{artifacts}} itself, once earlier steps have produced artifacts. The verb
+// is replaced with the list of artifact paths.
+const ArtifactsPrompt = `
+
+---
+ARTIFACTS from earlier steps (read them before you start):
+%s
+---
+`
+
+// ArtifactMissingPrompt is appended when a step is retried because it did
+// not write its artifacts. The verb is replaced with the missing paths.
+const ArtifactMissingPrompt = `
+
+---
+IMPORTANT: Your previous attempt did not create or update the following
+required file(s). Write them now:
+%s
+---
+`
+
+// artifactStat is what is known about an artifact before its step runs.
+type artifactStat struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// artifactPaths returns the step's artifact paths with {{spec_name}} expanded
+// and relative paths resolved against the working directory.
+func (r *Runner) artifactPaths(step Step) []string {
+	if len(step.Artifacts) == 0 {
+		return nil
+	}
+	specName := strings.TrimSuffix(filepath.Base(r.specFile), filepath.Ext(r.specFile))
+	paths := make([]string, 0, len(step.Artifacts))
+	for _, p := range step.Artifacts {
+		p = strings.ReplaceAll(p, "{{spec_name}}", specName)
+		if !filepath.IsAbs(p) && r.workingDir != "" {
+			p = filepath.Join(r.workingDir, p)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// statArtifacts records the current state of each artifact path.
+func statArtifacts(paths []string) map[string]artifactStat {
+	stats := make(map[string]artifactStat, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			stats[p] = artifactStat{exists: true, modTime: info.ModTime(), size: info.Size()}
+		} else {
+			stats[p] = artifactStat{}
+		}
+	}
+	return stats
+}
+
+// checkArtifacts splits paths into artifacts the step wrote and those it did
+// not: missing files, and files unchanged since before the step ran.
+func checkArtifacts(paths []string, before map[string]artifactStat) (written, missing []string) {
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			missing = append(missing, p)
+			continue
+		}
+		prev := before[p]
+		if prev.exists && info.ModTime().Equal(prev.modTime) && info.Size() == prev.size {
+			missing = append(missing, p)
+			continue
+		}
+		written = append(written, p)
+	}
+	return written, missing
+}
+
+// recordArtifacts adds paths to the artifacts passed into later prompts,
+// keeping the order they were first produced in.
+func (r *Runner) recordArtifacts(paths []string) {
+	for _, p := range paths {
+		known := false
+		for _, a := range r.artifacts {
+			if a == p {
+				known = true
+				break
+			}
+		}
+		if !known {
+			r.artifacts = append(r.artifacts, p)
+		}
+	}
+}
+
+// Artifacts returns the artifact paths produced so far, oldest first.
+func (r *Runner) Artifacts() []string {
+	return append([]string(nil), r.artifacts...)
+}
+
+// formatPathList formats paths as a markdown list.
+func formatPathList(paths []string) string {
+	var b strings.Builder
+	for i, p := range paths {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// artifactsPromptText returns the text appended for required and missing
+// artifacts of a step about to run.
+func artifactsPromptText(required, missing []string) string {
+	var text string
+	if len(required) > 0 {
+		text += fmt.Sprintf(ArtifactsRequiredPrompt, formatPathList(required))
+	}
+	if len(missing) > 0 {
+		text += fmt.Sprintf(ArtifactMissingPrompt, formatPathList(missing))
+	}
+	return text
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunner_Run_Artifacts(t *testing.T) {
+	dir := t.TempDir()
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "plan", Prompt: "Plan", Artifacts: []string{"docs/plans/{{spec_name}}-plan.md"}},
+			{Name: "implement", Prompt: "Implement using {{artifacts}}"},
+		},
+	}
+	planPath := filepath.Join(dir, "docs", "plans", "auth-plan.md")
+
+	prompts := make(map[string]string)
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		prompts[stepName] = prompt
+		if stepName == "plan" {
+			if err := os.MkdirAll(filepath.Dir(planPath), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(planPath, []byte("# Plan\n"), 0644); err != nil {
+				return nil, err
+			}
+		}
+		return &ExecutionResult{StepName: stepName, Output: "done"}, nil
+	}
+
+	var callbackArtifacts []string
+	runner := NewRunner(w, exec)
+	runner.SetWorkingDir(dir)
+	runner.SetSpecFile(filepath.Join(dir, "specs", "auth.md"))
+	runner.SetCallback(func(info StepInfo, result *ExecutionResult, gateResult GateResult) error {
+		if info.Name == "plan" {
+			callbackArtifacts = info.Artifacts
+		}
+		return nil
+	})
+
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(prompts["plan"], "ARTIFACTS: This step must create or update") || !strings.Contains(prompts["plan"], planPath) {
+		t.Errorf("plan prompt does not name its artifact: %q", prompts["plan"])
+	}
+	if prompts["implement"] != "Implement using - "+planPath {
+		t.Errorf("implement prompt = %q, want the artifact path substituted", prompts["implement"])
+	}
+	if len(callbackArtifacts) != 1 || callbackArtifacts[0] != planPath {
+		t.Errorf("StepInfo.Artifacts = %v, want [%s]", callbackArtifacts, planPath)
+	}
+	if got := runner.Artifacts(); len(got) != 1 || got[0] != planPath {
+		t.Errorf("Artifacts() = %v", got)
+	}
+}
+
+func TestRunner_Run_ArtifactMissingRetriesOnce(t *testing.T) {
+	dir := t.TempDir()
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "plan", Prompt: "Plan", Artifacts: []string{"plan.md"}},
+		},
+	}
+
+	var prompts []string
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		prompts = append(prompts, prompt)
+		if len(prompts) == 2 {
+			if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("plan"), 0644); err != nil {
+				return nil, err
+			}
+		}
+		return &ExecutionResult{StepName: stepName, Output: "done"}, nil
+	}
+
+	runner := NewRunner(w, exec)
+	runner.SetWorkingDir(dir)
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("step ran %d times, want 2", len(prompts))
+	}
+	if strings.Contains(prompts[0], "did not create or update") || !strings.Contains(prompts[1], "did not create or update") {
+		t.Errorf("retry prompt should name the missing artifact only on the second attempt: %q", prompts)
+	}
+}
+
+func TestRunner_Run_ArtifactMissingFails(t *testing.T) {
+	dir := t.TempDir()
+	// An existing artifact the step never touches counts as not written
+	if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "plan", Prompt: "Plan", Artifacts: []string{"plan.md"}},
+		},
+	}
+
+	exec := newMockExecutor()
+	runner := NewRunner(w, exec)
+	runner.SetWorkingDir(dir)
+
+	_, err := runner.Run(context.Background())
+	if !errors.Is(err, ErrArtifactMissing) {
+		t.Fatalf("Run() error = %v, want ErrArtifactMissing", err)
+	}
+	if len(exec.calls) != 2 {
+		t.Errorf("step ran %d times, want 2", len(exec.calls))
+	}
+}
+
+func TestRunner_buildPrompt_ArtifactsAppended(t *testing.T) {
+	runner := NewRunner(&Workflow{}, newMockExecutor())
+	if got := runner.buildPrompt("Do it", DefaultStepTimeout); got != "Do it" {
+		t.Errorf("buildPrompt() without artifacts = %q", got)
+	}
+	if got := runner.buildPrompt("See {{artifacts}}", DefaultStepTimeout); got != "See (none yet)" {
+		t.Errorf("buildPrompt() placeholder without artifacts = %q", got)
+	}
+
+	runner.recordArtifacts([]string{"/w/plan.md", "/w/plan.md"})
+	got := runner.buildPrompt("Do it", DefaultStepTimeout)
+	if !strings.Contains(got, "ARTIFACTS from earlier steps") || strings.Count(got, "/w/plan.md") != 1 {
+		t.Errorf("buildPrompt() = %q, want the artifact appended once", got)
+	}
+}
+
+func TestRunner_buildPrompt_ArtifactsBeforeNotes(t *testing.T) {
+	runner := NewRunner(&Workflow{}, newMockExecutor())
+	runner.recordArtifacts([]string{"/w/plan.md"})
+	runner.SetRecentNotes("recent")
+
+	// The notes change every iteration, so they come last to keep the
+	// prompt's prefix stable for the prompt cache
+	want := "Do it" + fmt.Sprintf(ArtifactsPrompt, formatPathList([]string{"/w/plan.md"})) + fmt.Sprintf(RecentNotesPrompt, "recent")
+	if got := runner.buildPrompt("Do it", DefaultStepTimeout); got != want {
+		t.Errorf("buildPrompt() = %q, want %q", got, want)
+	}
+}
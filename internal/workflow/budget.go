@@ -0,0 +1,315 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Prompt components the context budget measures. The spec file is measured
+// but never trimmed.
+const (
+	// ComponentSpec is the spec file Claude reads, measured by its size.
+	ComponentSpec = "spec"
+	// ComponentContext is the context files listed in the prompt, measured
+	// by their size. Trimming drops files from the end of the list.
+	ComponentContext = "context"
+	// ComponentNotes is the recent notes, or the lean context replacing
+	// them. Trimming drops the oldest lines.
+	ComponentNotes = "notes"
+	// ComponentHistory is the text appended to every prompt of the
+	// iteration: injected instructions, notices and the branch summary.
+	// Trimming drops the end.
+	ComponentHistory = "history"
+)
+
+// DefaultTrimOrder is the order components are trimmed in when a prompt is
+// over budget: least important first.
+var DefaultTrimOrder = []string{ComponentContext, ComponentNotes, ComponentHistory}
+
+// charsPerToken approximates the characters in a token for English text and
+// code.
+const charsPerToken = 4
+
+// trimmedNotesMarker and trimmedHistoryMarker mark where a component was
+// cut to fit the budget.
+const (
+	trimmedNotesMarker   = "[earlier notes trimmed to fit the context budget]\n"
+	trimmedHistoryMarker = "\n[trimmed to fit the context budget]\n"
+)
+
+// EstimateTokens approximates the number of tokens in text.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// ContextBudget caps the estimated tokens a step prompt brings into Claude's
+// context: the prompt itself plus the spec and context files it points
+// Claude at. Over the limit, components are trimmed in TrimOrder.
+type ContextBudget struct {
+	// Limit is the most tokens a prompt may bring in.
+	Limit int
+
+	// TrimOrder is the order components are trimmed in (default:
+	// DefaultTrimOrder). Components left out are never trimmed.
+	TrimOrder []string
+}
+
+// ValidateTrimOrder checks that order names only trimmable components, each
+// once.
+func ValidateTrimOrder(order []string) error {
+	seen := make(map[string]bool)
+	for _, c := range order {
+		switch c {
+		case ComponentContext, ComponentNotes, ComponentHistory:
+		default:
+			return fmt.Errorf("unknown prompt component %q: must be %s, %s or %s", c, ComponentContext, ComponentNotes, ComponentHistory)
+		}
+		if seen[c] {
+			return fmt.Errorf("prompt component %q listed twice", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Trim describes what the context budget removed from one component.
+type Trim struct {
+	Component string
+	// Tokens is the estimated number of tokens removed.
+	Tokens int
+	// Detail names what was removed, e.g. a dropped context file.
+	Detail string
+}
+
+// String describes the trim, e.g. "notes: dropped ~1200 tokens".
+func (t Trim) String() string {
+	if t.Detail != "" {
+		return fmt.Sprintf("%s: %s (~%d tokens)", t.Component, t.Detail, t.Tokens)
+	}
+	return fmt.Sprintf("%s: dropped ~%d tokens", t.Component, t.Tokens)
+}
+
+// BudgetReport describes a step prompt trimmed to the context budget.
+type BudgetReport struct {
+	Step string
+	// Limit is the budget, and Before and After the estimated tokens of the
+	// prompt before and after trimming. After is above Limit when trimming
+	// everything allowed was not enough.
+	Limit  int
+	Before int
+	After  int
+	// Sizes are the estimated tokens of each component before trimming.
+	Sizes map[string]int
+	Trims []Trim
+}
+
+// String summarises the report on one line.
+func (b BudgetReport) String() string {
+	trims := make([]string, len(b.Trims))
+	for i, t := range b.Trims {
+		trims[i] = t.String()
+	}
+	msg := fmt.Sprintf("Step %s: prompt trimmed from ~%d to ~%d tokens (budget %d): %s", b.Step, b.Before, b.After, b.Limit, strings.Join(trims, "; "))
+	if b.After > b.Limit {
+		msg += "; still over budget"
+	}
+	return msg
+}
+
+// BudgetCallback is called when a step prompt is trimmed to the budget.
+type BudgetCallback func(report BudgetReport)
+
+// SetContextBudget sets the budget step prompts are trimmed to, and the
+// callback reporting what was trimmed. A nil budget turns trimming off.
+func (r *Runner) SetContextBudget(b *ContextBudget, cb BudgetCallback) {
+	r.budget = b
+	r.budgetCallback = cb
+}
+
+// contextFile is a context file and its estimated tokens.
+type contextFile struct {
+	path   string
+	tokens int
+}
+
+// budgetParts are the components of a step prompt the budget allocates.
+type budgetParts struct {
+	// fixed is the prompt text that is never trimmed, and spec the spec
+	// file's estimated tokens.
+	fixed   int
+	spec    int
+	context []contextFile
+	notes   string
+	history string
+}
+
+// total returns the estimated tokens of all components.
+func (p budgetParts) total() int {
+	n := p.fixed + p.spec + EstimateTokens(p.notes) + EstimateTokens(p.history)
+	for _, f := range p.context {
+		n += f.tokens
+	}
+	return n
+}
+
+// allocate trims p to limit, components in order, and returns what it
+// removed.
+func (p *budgetParts) allocate(limit int, order []string) []Trim {
+	var trims []Trim
+	for _, component := range order {
+		over := p.total() - limit
+		if over <= 0 {
+			break
+		}
+		switch component {
+		case ComponentContext:
+			for i := len(p.context) - 1; i >= 0 && p.total() > limit; i-- {
+				f := p.context[i]
+				if f.tokens == 0 {
+					continue
+				}
+				p.context = append(p.context[:i:i], p.context[i+1:]...)
+				trims = append(trims, Trim{Component: ComponentContext, Tokens: f.tokens, Detail: "dropped " + f.path})
+			}
+		case ComponentNotes:
+			before := EstimateTokens(p.notes)
+			p.notes = keepTail(p.notes, before-over)
+			if removed := before - EstimateTokens(p.notes); removed > 0 {
+				trims = append(trims, Trim{Component: ComponentNotes, Tokens: removed})
+			}
+		case ComponentHistory:
+			before := EstimateTokens(p.history)
+			p.history = keepHead(p.history, before-over)
+			if removed := before - EstimateTokens(p.history); removed > 0 {
+				trims = append(trims, Trim{Component: ComponentHistory, Tokens: removed})
+			}
+		}
+	}
+	return trims
+}
+
+// keepTail returns the last whole lines of text that fit in tokens with the
+// trimmed marker, or "" when none fit.
+func keepTail(text string, tokens int) string {
+	if EstimateTokens(text) <= tokens {
+		return text
+	}
+	room := (tokens - EstimateTokens(trimmedNotesMarker)) * charsPerToken
+	if room <= 0 {
+		return ""
+	}
+	tail := text[len(text)-room:]
+	if i := strings.Index(tail, "\n"); i >= 0 {
+		tail = tail[i+1:]
+	} else {
+		return ""
+	}
+	if strings.TrimSpace(tail) == "" {
+		return ""
+	}
+	return trimmedNotesMarker + tail
+}
+
+// keepHead returns the first whole lines of text that fit in tokens with
+// the trimmed marker, or "" when none fit.
+func keepHead(text string, tokens int) string {
+	if EstimateTokens(text) <= tokens {
+		return text
+	}
+	room := (tokens - EstimateTokens(trimmedHistoryMarker)) * charsPerToken
+	if room <= 0 {
+		return ""
+	}
+	head := text[:room]
+	i := strings.LastIndex(head, "\n")
+	if i < 0 || strings.TrimSpace(head[:i]) == "" {
+		return ""
+	}
+	return head[:i] + trimmedHistoryMarker
+}
+
+// fileTokens estimates the tokens of a file from its size, or 0 when it
+// cannot be read.
+func fileTokens(path string) int {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return 0
+	}
+	return int((info.Size() + charsPerToken - 1) / charsPerToken)
+}
+
+// stepPrompt builds a step's prompt between the prompt prefix and suffix,
+// trimmed to the context budget when one is set. Trimming is reported to the
+// budget callback.
+func (r *Runner) stepPrompt(step *Step) string {
+	return r.promptPrefix + r.budgetedStepPrompt(step)
+}
+
+// budgetedStepPrompt builds a step's prompt followed by the prompt suffix,
+// trimmed so that with the prompt prefix it fits the context budget when one
+// is set.
+func (r *Runner) budgetedStepPrompt(step *Step) string {
+	if r.budget == nil || r.budget.Limit <= 0 {
+		return r.buildStepPrompt(step) + r.promptSuffix
+	}
+
+	contextFiles := r.stepContextFiles(step)
+	parts := budgetParts{
+		spec:    fileTokens(r.specFile),
+		notes:   r.recentNotes,
+		history: r.promptSuffix,
+	}
+	if r.leanContext != "" {
+		parts.notes = r.leanContext
+	}
+	for _, f := range contextFiles {
+		parts.context = append(parts.context, contextFile{path: f, tokens: fileTokens(f)})
+	}
+	bare := *r
+	bare.recentNotes, bare.leanContext = "", ""
+	parts.fixed = EstimateTokens(r.promptPrefix + bare.buildStepPromptWith(step, contextFiles))
+
+	before := parts.total()
+	sizes := map[string]int{
+		ComponentSpec:    parts.spec,
+		ComponentNotes:   EstimateTokens(parts.notes),
+		ComponentHistory: EstimateTokens(parts.history),
+	}
+	for _, f := range parts.context {
+		sizes[ComponentContext] += f.tokens
+	}
+	order := r.budget.TrimOrder
+	if order == nil {
+		order = DefaultTrimOrder
+	}
+	trims := parts.allocate(r.budget.Limit, order)
+	if len(trims) == 0 {
+		return r.buildStepPrompt(step) + r.promptSuffix
+	}
+
+	trimmed := *r
+	if r.leanContext != "" {
+		trimmed.leanContext = parts.notes
+		trimmed.recentNotes = ""
+	} else {
+		trimmed.recentNotes = parts.notes
+	}
+	kept := make([]string, len(parts.context))
+	for i, f := range parts.context {
+		kept[i] = f.path
+	}
+	prompt := trimmed.buildStepPromptWith(step, kept) + parts.history
+
+	if r.budgetCallback != nil {
+		r.budgetCallback(BudgetReport{
+			Step:   step.Name,
+			Limit:  r.budget.Limit,
+			Before: before,
+			After:  parts.total(),
+			Sizes:  sizes,
+			Trims:  trims,
+		})
+	}
+	return prompt
+}
@@ -0,0 +1,231 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "abc", want: 1},
+		{text: "abcd", want: 1},
+		{text: "abcde", want: 2},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTrimOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   []string
+		wantErr bool
+	}{
+		{name: "default", order: DefaultTrimOrder},
+		{name: "subset", order: []string{ComponentNotes}},
+		{name: "empty", order: []string{}},
+		{name: "spec is never trimmed", order: []string{ComponentSpec}, wantErr: true},
+		{name: "unknown", order: []string{"history", "files"}, wantErr: true},
+		{name: "duplicate", order: []string{"notes", "notes"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTrimOrder(tt.order); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTrimOrder(%v) error = %v, wantErr %v", tt.order, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// lines returns n numbered lines of 19 characters plus a newline, 5 tokens
+// each.
+func lines(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%-16.16s%c..\n", prefix, 'a'+i%26)
+	}
+	return b.String()
+}
+
+func TestBudgetParts_Allocate(t *testing.T) {
+	newParts := func() budgetParts {
+		return budgetParts{
+			fixed:   100,
+			spec:    500,
+			context: []contextFile{{path: "a.md", tokens: 200}, {path: "b.md", tokens: 300}},
+			notes:   lines("note", 40),    // 200 tokens
+			history: lines("history", 40), // 200 tokens
+		}
+	}
+
+	tests := []struct {
+		name        string
+		limit       int
+		order       []string
+		wantContext []string
+		wantTrimmed []string
+		checkParts  func(t *testing.T, p budgetParts)
+	}{
+		{
+			name:        "under budget",
+			limit:       2000,
+			order:       DefaultTrimOrder,
+			wantContext: []string{"a.md", "b.md"},
+		},
+		{
+			name:        "drops the last context file first",
+			limit:       1300,
+			order:       DefaultTrimOrder,
+			wantContext: []string{"a.md"},
+			wantTrimmed: []string{ComponentContext},
+		},
+		{
+			name:        "then trims the oldest notes",
+			limit:       950,
+			order:       DefaultTrimOrder,
+			wantContext: nil,
+			wantTrimmed: []string{ComponentContext, ComponentContext, ComponentNotes},
+			checkParts: func(t *testing.T, p budgetParts) {
+				if !strings.HasPrefix(p.notes, trimmedNotesMarker) || !strings.HasSuffix(p.notes, lines("note", 40)[len(lines("note", 40))-20:]) {
+					t.Errorf("notes = %q, want the marker and the latest lines", p.notes)
+				}
+				if p.history != lines("history", 40) {
+					t.Error("history trimmed before notes were used up")
+				}
+			},
+		},
+		{
+			name:        "follows the configured order",
+			limit:       1400,
+			order:       []string{ComponentHistory, ComponentContext},
+			wantContext: []string{"a.md", "b.md"},
+			wantTrimmed: []string{ComponentHistory},
+			checkParts: func(t *testing.T, p budgetParts) {
+				if !strings.HasPrefix(p.history, lines("history", 1)) || !strings.HasSuffix(p.history, trimmedHistoryMarker) {
+					t.Errorf("history = %q, want its first lines and the marker", p.history)
+				}
+			},
+		},
+		{
+			name:        "stays over when nothing more may be trimmed",
+			limit:       100,
+			order:       []string{ComponentNotes},
+			wantContext: []string{"a.md", "b.md"},
+			wantTrimmed: []string{ComponentNotes},
+			checkParts: func(t *testing.T, p budgetParts) {
+				if p.notes != "" {
+					t.Errorf("notes = %q, want all trimmed", p.notes)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newParts()
+			trims := p.allocate(tt.limit, tt.order)
+
+			var context []string
+			for _, f := range p.context {
+				context = append(context, f.path)
+			}
+			if strings.Join(context, ",") != strings.Join(tt.wantContext, ",") {
+				t.Errorf("context = %v, want %v", context, tt.wantContext)
+			}
+			var trimmed []string
+			for _, tr := range trims {
+				trimmed = append(trimmed, tr.Component)
+				if tr.Tokens <= 0 {
+					t.Errorf("trim %+v removed no tokens", tr)
+				}
+			}
+			if strings.Join(trimmed, ",") != strings.Join(tt.wantTrimmed, ",") {
+				t.Errorf("trims = %v, want %v", trimmed, tt.wantTrimmed)
+			}
+			if len(tt.wantTrimmed) > 0 && tt.limit >= p.fixed+p.spec && p.total() > tt.limit {
+				t.Errorf("total = %d, want at most %d", p.total(), tt.limit)
+			}
+			if tt.checkParts != nil {
+				tt.checkParts(t, p)
+			}
+		})
+	}
+}
+
+func TestRunner_ContextBudget(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	specFile := write("spec.md", 400)
+	small := write("small.md", 400)
+	big := write("big.md", 40000)
+
+	w := &Workflow{Steps: []Step{{Name: "implement", Prompt: "Implement {{spec_file}}\n\nContext:\n{{context_files}}"}}}
+	var prompts []string
+	mock := newMockExecutor()
+	mock.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		prompts = append(prompts, prompt)
+		return &ExecutionResult{Output: "done"}, nil
+	}
+	runner := NewRunner(w, mock)
+	runner.SetSpecFile(specFile)
+	runner.SetContextFiles([]string{small, big})
+	runner.SetFilePaths([]string{specFile, small, big})
+	runner.SetRecentNotes(lines("note", 20))
+
+	var reports []BudgetReport
+	runner.SetContextBudget(&ContextBudget{Limit: 1000}, func(r BudgetReport) {
+		reports = append(reports, r)
+	})
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(prompts) != 1 || strings.Contains(prompts[0], big) || !strings.Contains(prompts[0], small) {
+		t.Fatalf("prompt = %q, want the small context file but not the big one", prompts)
+	}
+	if !strings.Contains(prompts[0], "RECENT NOTES") {
+		t.Error("notes dropped although dropping the big file was enough")
+	}
+	if len(reports) != 1 {
+		t.Fatalf("reports = %+v, want one", reports)
+	}
+	r := reports[0]
+	if r.Step != "implement" || r.Before <= r.Limit || r.After > r.Limit || r.Sizes[ComponentContext] != 10100 {
+		t.Errorf("report = %+v", r)
+	}
+	if len(r.Trims) != 1 || r.Trims[0].Detail != "dropped "+big {
+		t.Errorf("trims = %+v, want the big file dropped", r.Trims)
+	}
+	if !strings.Contains(r.String(), "dropped "+big) {
+		t.Errorf("String() = %q", r.String())
+	}
+
+	// Under budget the prompt is unchanged and nothing is reported
+	prompts, reports = nil, nil
+	runner.SetContextBudget(&ContextBudget{Limit: 100000}, func(r BudgetReport) {
+		reports = append(reports, r)
+	})
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(reports) != 0 || !strings.Contains(prompts[0], big) {
+		t.Errorf("under budget: reports %+v, prompt %q", reports, prompts[0])
+	}
+}
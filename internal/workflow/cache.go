@@ -0,0 +1,45 @@
+package workflow
+
+// StepCache stores the results of cacheable steps (see Step.Cacheable), so a
+// step re-run with an identical prompt, such as after a crash, reuses its
+// earlier result instead of running Claude again.
+type StepCache interface {
+	// Get returns the cached result of prompt on model, where an empty model
+	// is the executor's own. A cached result costs nothing.
+	Get(stepName, model, prompt string) (*ExecutionResult, bool)
+
+	// Put caches the result of a step that succeeded: a step that is not a
+	// gate, or a gate that passed.
+	Put(stepName, model, prompt string, result *ExecutionResult)
+}
+
+// SetCache sets the cache consulted for cacheable steps. Nil disables it.
+func (r *Runner) SetCache(c StepCache) {
+	r.cache = c
+}
+
+// cachedResult returns the cached result of a cacheable step, or nil. A
+// step's cached result is only reused when the artifacts it wrote still
+// exist.
+func (r *Runner) cachedResult(step Step, model, prompt string, artifacts map[string]artifactStat) *ExecutionResult {
+	if !step.Cacheable || r.cache == nil {
+		return nil
+	}
+	for _, a := range artifacts {
+		if !a.exists {
+			return nil
+		}
+	}
+	result, ok := r.cache.Get(step.Name, model, prompt)
+	if !ok {
+		return nil
+	}
+	result.StepName = step.Name
+	result.CostUSD = 0
+	result.TokensIn = 0
+	result.TokensOut = 0
+	result.CacheReadTokens = 0
+	result.CacheCreationTokens = 0
+	result.Cached = true
+	return result
+}
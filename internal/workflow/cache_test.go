@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// memoryStepCache is an in-memory StepCache for tests.
+type memoryStepCache struct {
+	entries map[string]ExecutionResult
+	puts    []string
+}
+
+func newMemoryStepCache() *memoryStepCache {
+	return &memoryStepCache{entries: make(map[string]ExecutionResult)}
+}
+
+func (c *memoryStepCache) Get(stepName, model, prompt string) (*ExecutionResult, bool) {
+	result, ok := c.entries[model+"\x00"+prompt]
+	if !ok {
+		return nil, false
+	}
+	return &result, true
+}
+
+func (c *memoryStepCache) Put(stepName, model, prompt string, result *ExecutionResult) {
+	c.entries[model+"\x00"+prompt] = *result
+	c.puts = append(c.puts, stepName)
+}
+
+func TestRunner_CacheableStep(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "plan", Prompt: "Plan {{spec_file}}", Cacheable: true},
+			{Name: "implement", Prompt: "Implement {{spec_file}}"},
+		},
+	}
+	cache := newMemoryStepCache()
+
+	run := func() (*mockStepExecutor, *RunResult) {
+		t.Helper()
+		mock := newMockExecutor()
+		mock.setResponse("plan", "the plan", 0.40, 1000)
+		mock.setResponse("implement", "done", 0.10, 100)
+		runner := NewRunner(w, mock)
+		runner.SetSpecFile("spec.md")
+		runner.SetCache(cache)
+		result, err := runner.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		return mock, result
+	}
+
+	mock, result := run()
+	if strings.Join(mock.calls, ",") != "plan,implement" {
+		t.Errorf("first run executed %v, want plan,implement", mock.calls)
+	}
+	if strings.Join(cache.puts, ",") != "plan" {
+		t.Errorf("cached %v, want only plan", cache.puts)
+	}
+	if result.Steps[0].Cached {
+		t.Error("first run's plan step reported as cached")
+	}
+
+	mock, result = run()
+	if strings.Join(mock.calls, ",") != "implement" {
+		t.Errorf("second run executed %v, want only implement", mock.calls)
+	}
+	plan := result.Steps[0]
+	if !plan.Cached || plan.Output != "the plan" || plan.CostUSD != 0 || plan.TokensIn != 0 {
+		t.Errorf("second run's plan step = %+v, want the cached output at no cost", plan)
+	}
+	if result.TotalCost != 0.10 {
+		t.Errorf("TotalCost = %v, want 0.10", result.TotalCost)
+	}
+	if len(cache.puts) != 1 {
+		t.Errorf("cache hit was stored again: %v", cache.puts)
+	}
+}
+
+func TestRunner_CacheSkipsFailedGate(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+			{Name: "review", Prompt: "Review", Gate: true, OnFail: "implement", Cacheable: true},
+		},
+	}
+	mock := newMockExecutor()
+	reviews := 0
+	mock.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		if stepName == "review" {
+			reviews++
+			if reviews == 1 {
+				return &ExecutionResult{Output: "<gate>FAIL</gate>"}, nil
+			}
+			return &ExecutionResult{Output: "<gate>PASS</gate>"}, nil
+		}
+		return &ExecutionResult{Output: "done"}, nil
+	}
+	cache := newMemoryStepCache()
+	runner := NewRunner(w, mock)
+	runner.SetCache(cache)
+
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if reviews != 2 {
+		t.Errorf("review ran %d times, want 2", reviews)
+	}
+	if strings.Join(cache.puts, ",") != "review" {
+		t.Errorf("cached %v, want only the passing review", cache.puts)
+	}
+	for _, r := range cache.entries {
+		if r.Output != "<gate>PASS</gate>" {
+			t.Errorf("cached output %q, want the passing verdict", r.Output)
+		}
+	}
+}
+
+func TestRunner_CacheNeedsArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "plan", Prompt: "Plan", Artifacts: []string{"plan.md"}, Cacheable: true},
+		},
+	}
+	cache := newMemoryStepCache()
+
+	run := func() *mockStepExecutor {
+		t.Helper()
+		mock := newMockExecutor()
+		mock.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+			if err := os.WriteFile(filepath.Join(dir, "plan.md"), []byte("# Plan"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			return &ExecutionResult{Output: "planned"}, nil
+		}
+		runner := NewRunner(w, mock)
+		runner.SetWorkingDir(dir)
+		runner.SetCache(cache)
+		if _, err := runner.Run(context.Background()); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		return mock
+	}
+
+	if calls := run().calls; len(calls) != 1 {
+		t.Fatalf("first run executed %v, want plan", calls)
+	}
+	if calls := run().calls; len(calls) != 0 {
+		t.Errorf("second run executed %v, want the cached plan", calls)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "plan.md")); err != nil {
+		t.Fatal(err)
+	}
+	if calls := run().calls; len(calls) != 1 {
+		t.Errorf("run without the artifact executed %v, want plan", calls)
+	}
+}
+
+func TestWorkflow_HasCacheableSteps(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "plan", Prompt: "Plan"}}}
+	if w.HasCacheableSteps() {
+		t.Error("HasCacheableSteps() = true without a cacheable step")
+	}
+	w.Steps = append(w.Steps, Step{Name: "design", Prompt: "Design", Cacheable: true})
+	if !w.HasCacheableSteps() {
+		t.Error("HasCacheableSteps() = false with a cacheable step")
+	}
+}
@@ -0,0 +1,47 @@
+package workflow
+
+import "strings"
+
+// CheckpointOpenTag and CheckpointCloseTag delimit a named checkpoint declared
+// by Claude, e.g. <checkpoint>tests green</checkpoint>.
+const (
+	CheckpointOpenTag  = "<checkpoint>"
+	CheckpointCloseTag = "</checkpoint>"
+)
+
+// maxCheckpointName bounds checkpoint names so a runaway tag cannot swallow
+// the rest of the output.
+const maxCheckpointName = 80
+
+// ParseCheckpoints returns the checkpoint names declared in output, in order
+// and without duplicates. Empty and over-long names are ignored.
+func ParseCheckpoints(output string) []string {
+	var names []string
+	for {
+		start := strings.Index(output, CheckpointOpenTag)
+		if start < 0 {
+			return names
+		}
+		output = output[start+len(CheckpointOpenTag):]
+		end := strings.Index(output, CheckpointCloseTag)
+		if end < 0 {
+			return names
+		}
+		name := strings.Join(strings.Fields(output[:end]), " ")
+		output = output[end+len(CheckpointCloseTag):]
+
+		if name == "" || len(name) > maxCheckpointName {
+			continue
+		}
+		seen := false
+		for _, n := range names {
+			if n == name {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			names = append(names, name)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package workflow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCheckpoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "none", output: "all done", want: nil},
+		{name: "single", output: "migrated <checkpoint>schema migrated</checkpoint>", want: []string{"schema migrated"}},
+		{
+			name:   "several in order",
+			output: "<checkpoint>schema migrated</checkpoint>\n...\n<checkpoint>tests green</checkpoint>",
+			want:   []string{"schema migrated", "tests green"},
+		},
+		{name: "whitespace collapsed", output: "<checkpoint>\n  tests\n  green </checkpoint>", want: []string{"tests green"}},
+		{name: "duplicates dropped", output: "<checkpoint>a</checkpoint><checkpoint>a</checkpoint>", want: []string{"a"}},
+		{name: "empty ignored", output: "<checkpoint> </checkpoint>", want: nil},
+		{name: "unterminated ignored", output: "<checkpoint>tests green", want: nil},
+		{name: "too long ignored", output: "<checkpoint>" + strings.Repeat("x", 81) + "</checkpoint>", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCheckpoints(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCheckpoints() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
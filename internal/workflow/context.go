@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MaxStepContextFiles bounds the number of files a step's context patterns
+// add to its prompt. Further matches are summarised rather than listed.
+const MaxStepContextFiles = 100
+
+// contextSkipDirs are directories never searched for context patterns.
+var contextSkipDirs = map[string]bool{".git": true, ".orbital": true, "node_modules": true}
+
+// stepContextFiles returns the context files for a step: the runner's
+// context files when the step sets no context patterns, otherwise the files
+// its patterns match under the working directory.
+func (r *Runner) stepContextFiles(step *Step) []string {
+	if step.Context == nil {
+		return r.contextFiles
+	}
+	return expandContext(step.Context, r.workingDir)
+}
+
+// expandContext returns the files matching patterns, relative to dir unless
+// a pattern is absolute. Patterns use filepath.Match syntax per path segment,
+// and "**" matches any number of directories. Literal paths are kept only if
+// they exist. At most MaxStepContextFiles paths are returned, followed by a
+// note of how many more matched.
+func expandContext(patterns []string, dir string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	more := 0
+	add := func(p string) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		if len(files) >= MaxStepContextFiles {
+			more++
+			return
+		}
+		files = append(files, p)
+	}
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) && dir != "" {
+			pattern = filepath.Join(dir, pattern)
+		}
+		pattern = filepath.Clean(pattern)
+		if !hasGlobMeta(pattern) {
+			if info, err := os.Stat(pattern); err == nil && !info.IsDir() {
+				add(pattern)
+			}
+			continue
+		}
+		for _, match := range globFiles(pattern) {
+			add(match)
+		}
+	}
+
+	if more > 0 {
+		files = append(files, fmt.Sprintf("(and %d more matching files)", more))
+	}
+	return files
+}
+
+// hasGlobMeta reports whether pattern contains glob metacharacters.
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// globFiles walks the directory before the pattern's first glob segment and
+// returns the files matching it, in lexical order.
+func globFiles(pattern string) []string {
+	slashed := filepath.ToSlash(pattern)
+	segments := strings.Split(slashed, "/")
+	root := 0
+	for root < len(segments) && !hasGlobMeta(segments[root]) {
+		root++
+	}
+	base := strings.Join(segments[:root], "/")
+	if base == "" {
+		base = "."
+		if strings.HasPrefix(slashed, "/") {
+			base = "/"
+		}
+	}
+
+	var matches []string
+	_ = filepath.WalkDir(filepath.FromSlash(base), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if contextSkipDirs[d.Name()] && p != filepath.FromSlash(base) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matchSegments(segments, strings.Split(filepath.ToSlash(p), "/")) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	return matches
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ApplyStepContext sets the context patterns of the named steps, so a
+// preset's steps can be given their own context files without redefining
+// them. It returns an error naming any step the workflow does not have.
+func (w *Workflow) ApplyStepContext(patterns map[string][]string) error {
+	for name, p := range patterns {
+		i := w.GetStepIndex(name)
+		if i < 0 {
+			return fmt.Errorf("context set for unknown step %q", name)
+		}
+		w.Steps[i].Context = append([]string{}, p...)
+	}
+	return nil
+}
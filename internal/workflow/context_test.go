@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTree creates empty files at the given paths under dir.
+func writeTree(t *testing.T, dir string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"src/**", "src/a.go", true},
+		{"src/**", "src/pkg/deep/a.go", true},
+		{"src/**/*.go", "src/a.go", true},
+		{"src/**/*.go", "src/pkg/a_test.md", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/sub/a.md", false},
+		{"**/README.md", "README.md", true},
+		{"**/README.md", "a/b/README.md", true},
+	}
+	for _, tt := range tests {
+		got := matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/"))
+		if got != tt.want {
+			t.Errorf("matchSegments(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandContext(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "docs/architecture.md", "docs/api.md", "src/main.go", "src/pkg/util.go", ".git/HEAD", "src/.orbital/state.json")
+
+	got := expandContext([]string{"docs/architecture.md", "missing.md", "src/**", "docs/*.md"}, dir)
+	want := []string{
+		filepath.Join(dir, "docs/architecture.md"),
+		filepath.Join(dir, "src/main.go"),
+		filepath.Join(dir, "src/pkg/util.go"),
+		filepath.Join(dir, "docs/api.md"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandContext() = %v, want %v", got, want)
+	}
+}
+
+func TestExpandContext_CapsMatches(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < MaxStepContextFiles+5; i++ {
+		writeTree(t, dir, filepath.Join("src", strings.Repeat("a", i+1)+".go"))
+	}
+
+	got := expandContext([]string{"src/*.go"}, dir)
+	if len(got) != MaxStepContextFiles+1 || got[len(got)-1] != "(and 5 more matching files)" {
+		t.Errorf("expandContext() returned %d entries ending %q", len(got), got[len(got)-1])
+	}
+}
+
+func TestRunner_buildStepPrompt_Context(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, "docs/architecture.md")
+
+	w := &Workflow{Steps: []Step{
+		{Name: "plan", Prompt: "Files:\n{{files}}\nContext:\n{{context_files}}", Context: []string{"docs/*.md"}},
+		{Name: "implement", Prompt: "Context:\n{{context_files}}"},
+		{Name: "review", Prompt: "Context:\n{{context_files}}", Context: []string{}},
+	}}
+	runner := NewRunner(w, newMockExecutor())
+	runner.SetWorkingDir(dir)
+	runner.SetSpecFile("/spec.md")
+	runner.SetContextFiles([]string{"/all.md"})
+	runner.SetFilePaths([]string{"/spec.md", "/all.md"})
+
+	arch := filepath.Join(dir, "docs/architecture.md")
+	tests := []struct {
+		step string
+		want string
+	}{
+		{"plan", "Files:\n- /spec.md\n- " + arch + "\nContext:\n- " + arch},
+		{"implement", "Context:\n- /all.md"},
+		{"review", "Context:\n(none provided)"},
+	}
+	for _, tt := range tests {
+		if got := runner.GetStepPrompt(tt.step); got != tt.want {
+			t.Errorf("GetStepPrompt(%q) = %q, want %q", tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestWorkflow_Validate_Context(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "plan", Prompt: "p", Context: []string{"docs/[.md"}}}}
+	if err := w.Validate(); err == nil || !strings.Contains(err.Error(), "invalid context pattern") {
+		t.Errorf("Validate() error = %v, want invalid context pattern", err)
+	}
+	w.Steps[0].Context = []string{" "}
+	if err := w.Validate(); err == nil || !strings.Contains(err.Error(), "cannot be empty") {
+		t.Errorf("Validate() error = %v, want empty pattern error", err)
+	}
+}
+
+func TestWorkflow_ApplyStepContext(t *testing.T) {
+	w := tddPreset()
+	if err := w.ApplyStepContext(map[string][]string{"red": {"src/**"}}); err != nil {
+		t.Fatalf("ApplyStepContext() error = %v", err)
+	}
+	if got := w.Steps[w.GetStepIndex("red")].Context; !reflect.DeepEqual(got, []string{"src/**"}) {
+		t.Errorf("red context = %v", got)
+	}
+	if err := w.ApplyStepContext(map[string][]string{"nope": nil}); err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("ApplyStepContext() error = %v, want unknown step", err)
+	}
+}
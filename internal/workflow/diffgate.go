@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TreeStateFunc describes the working tree (see vcs.Backend.TreeState).
+// Equal results mean nothing changed in between; "" means the state is
+// unavailable.
+type TreeStateFunc func(ctx context.Context) string
+
+// SetTreeState sets the function diff-empty-fail gates compare the working
+// tree with. While none is set, those gates pass without checking.
+func (r *Runner) SetTreeState(treeState TreeStateFunc) {
+	r.treeState = treeState
+}
+
+// IsDiffGate reports whether the step is a gate that fails when the steps
+// before it changed nothing in the repository.
+func (s *Step) IsDiffGate() bool {
+	return s.Gate && s.GateType == GateTypeDiffEmptyFail
+}
+
+// IsBuiltinGate reports whether the step is a gate orbital judges without
+// sending it to Claude.
+func (s *Step) IsBuiltinGate() bool {
+	return s.IsHumanGate() || s.IsDiffGate()
+}
+
+// hasDiffGate reports whether any step of the workflow is a diff-empty-fail
+// gate.
+func (w *Workflow) hasDiffGate() bool {
+	for _, step := range w.Steps {
+		if step.IsDiffGate() {
+			return true
+		}
+	}
+	return false
+}
+
+// treeBefore returns the working tree state before step runs, or "" when no
+// diff-empty-fail gate will look at it. Gates are exempt: judging changes
+// nothing.
+func (r *Runner) treeBefore(ctx context.Context, step Step) string {
+	if r.treeState == nil || step.Gate || !r.workflow.hasDiffGate() {
+		return ""
+	}
+	return r.treeState(ctx)
+}
+
+// noteTreeChange records whether step changed the working tree since before
+// for the next diff-empty-fail gate. A step that changed nothing is listed
+// until it runs again and does. Cached results are exempt: their changes were
+// made when they were cached.
+func (r *Runner) noteTreeChange(ctx context.Context, step Step, before string, result *ExecutionResult) {
+	if before == "" || result.Cached {
+		return
+	}
+	after := r.treeState(ctx)
+	if after == "" {
+		return
+	}
+	kept := r.unchanged[:0]
+	for _, name := range r.unchanged {
+		if name != step.Name {
+			kept = append(kept, name)
+		}
+	}
+	r.unchanged = kept
+	if after == before {
+		r.unchanged = append(r.unchanged, step.Name)
+	}
+}
+
+// checkDiffGate fails when a step since the previous diff-empty-fail gate
+// changed nothing in the repository, unless the gate allows it. The steps
+// are checked once: the list starts over after every check.
+func (r *Runner) checkDiffGate(step Step) (*ExecutionResult, GateResult) {
+	if r.treeState == nil {
+		return &ExecutionResult{StepName: step.Name, Output: "Working tree state unavailable; not checked"}, GatePassed
+	}
+
+	allowed := make(map[string]bool, len(step.AllowUnchanged))
+	for _, name := range step.AllowUnchanged {
+		allowed[name] = true
+	}
+	var idle []string
+	for _, name := range r.unchanged {
+		if !allowed[name] {
+			idle = append(idle, name)
+		}
+	}
+	r.unchanged = nil
+
+	if len(idle) > 0 {
+		output := fmt.Sprintf("No repository changes from step(s): %s", strings.Join(idle, ", "))
+		return &ExecutionResult{StepName: step.Name, Output: output}, GateFailed
+	}
+	return &ExecutionResult{StepName: step.Name, Output: "Repository changed"}, GatePassed
+}
@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// diffWorkflow has a plan and an implement step followed by a diff gate that
+// sends failures back to the implement step.
+func diffWorkflow(gate Step) *Workflow {
+	gate.Name = "changed"
+	gate.Gate = true
+	gate.GateType = GateTypeDiffEmptyFail
+	gate.OnFail = "implement"
+	return &Workflow{Steps: []Step{
+		{Name: "plan", Prompt: "Plan"},
+		{Name: "implement", Prompt: "Implement"},
+		gate,
+	}}
+}
+
+// fakeTree is a working tree that changes whenever a step in writes runs,
+// popping one entry per run.
+type fakeTree struct {
+	version int
+	writes  map[string][]bool
+}
+
+func (f *fakeTree) state(context.Context) string {
+	return strings.Repeat("x", f.version+1)
+}
+
+func (f *fakeTree) executor() *mockStepExecutor {
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		if w := f.writes[stepName]; len(w) > 0 {
+			if w[0] {
+				f.version++
+			}
+			f.writes[stepName] = w[1:]
+		}
+		return &ExecutionResult{StepName: stepName, Output: "done"}, nil
+	}
+	return exec
+}
+
+func TestRunner_DiffGate(t *testing.T) {
+	tests := []struct {
+		name        string
+		allow       []string
+		writes      map[string][]bool
+		wantCalls   []string
+		wantOutputs []string
+		wantErr     error
+	}{
+		{
+			name:        "every step changed the tree",
+			writes:      map[string][]bool{"plan": {true}, "implement": {true}},
+			wantCalls:   []string{"plan", "implement"},
+			wantOutputs: []string{"Repository changed"},
+		},
+		{
+			name:        "allowed step changed nothing",
+			allow:       []string{"plan"},
+			writes:      map[string][]bool{"implement": {true}},
+			wantCalls:   []string{"plan", "implement"},
+			wantOutputs: []string{"Repository changed"},
+		},
+		{
+			name:        "step changed nothing, then did on retry",
+			allow:       []string{"plan"},
+			writes:      map[string][]bool{"implement": {false, true}},
+			wantCalls:   []string{"plan", "implement", "implement"},
+			wantOutputs: []string{"No repository changes from step(s): implement", "Repository changed"},
+		},
+		{
+			name:        "nothing changed",
+			writes:      map[string][]bool{},
+			wantCalls:   []string{"plan", "implement", "implement", "implement"},
+			wantOutputs: []string{"No repository changes from step(s): plan, implement", "No repository changes from step(s): implement", "No repository changes from step(s): implement"},
+			wantErr:     ErrMaxGateRetriesExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := &fakeTree{writes: tt.writes}
+			exec := tree.executor()
+			runner := NewRunner(diffWorkflow(Step{AllowUnchanged: tt.allow}), exec)
+			runner.SetTreeState(tree.state)
+
+			var outputs []string
+			runner.SetCallback(func(info StepInfo, result *ExecutionResult, gateResult GateResult) error {
+				if info.Name == "changed" {
+					outputs = append(outputs, result.Output)
+				}
+				return nil
+			})
+
+			_, err := runner.Run(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			// The gate itself is never sent to Claude
+			if !reflect.DeepEqual(exec.calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", exec.calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(outputs, tt.wantOutputs) {
+				t.Errorf("gate outputs = %q, want %q", outputs, tt.wantOutputs)
+			}
+		})
+	}
+}
+
+func TestRunner_DiffGateWithoutTreeState(t *testing.T) {
+	for _, treeState := range []TreeStateFunc{nil, func(context.Context) string { return "" }} {
+		exec := newMockExecutor()
+		runner := NewRunner(diffWorkflow(Step{}), exec)
+		runner.SetTreeState(treeState)
+		result, err := runner.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !result.CompletedAllSteps {
+			t.Error("CompletedAllSteps = false, want the gate to pass when the tree state is unavailable")
+		}
+	}
+}
+
+func TestWorkflow_Validate_DiffGate(t *testing.T) {
+	if err := diffWorkflow(Step{AllowUnchanged: []string{"plan"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(w *Workflow)
+		wantErr string
+	}{
+		{name: "unknown allowed step", mutate: func(w *Workflow) { w.Steps[2].AllowUnchanged = []string{"review"} }, wantErr: `allow_unchanged references unknown step "review"`},
+		{name: "allow on model gate", mutate: func(w *Workflow) {
+			w.Steps[2].GateType = GateTypeModel
+			w.Steps[2].Prompt = "Review"
+			w.Steps[2].AllowUnchanged = []string{"plan"}
+		}, wantErr: "allow_unchanged requires gate_type"},
+		{name: "rubric", mutate: func(w *Workflow) { w.Steps[2].Rubric = "rubric.md" }, wantErr: "a diff gate cannot have a panel or rubric"},
+		{name: "cacheable", mutate: func(w *Workflow) { w.Steps[2].Cacheable = true }, wantErr: "a diff gate cannot have artifacts or be cacheable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := diffWorkflow(Step{})
+			tt.mutate(w)
+			if err := w.Validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
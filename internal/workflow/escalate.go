@@ -0,0 +1,114 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultEscalateAfter is the number of times a gate must fail before its
+// retry is escalated to the workflow's escalation model.
+const DefaultEscalateAfter = 2
+
+// ModelStepExecutor is a StepExecutor that can run a step on a model other
+// than its own. Gate escalation needs it; with other executors a workflow's
+// escalate_model has no effect.
+type ModelStepExecutor interface {
+	StepExecutor
+
+	// ExecuteStepOnModel executes a single step with the given prompt on model.
+	ExecuteStepOnModel(ctx context.Context, stepName, prompt, model string) (*ExecutionResult, error)
+}
+
+// Escalation describes a switch to the escalation model after repeated gate
+// failures.
+type Escalation struct {
+	// Gate is the gate step that kept failing.
+	Gate string
+
+	// Failures is the number of times the gate has failed.
+	Failures int
+
+	// Model is the model the following steps run on until the gate passes.
+	Model string
+}
+
+// String describes the escalation, e.g. "gate review failed 2 times,
+// escalating to opus".
+func (e Escalation) String() string {
+	return fmt.Sprintf("gate %s failed %d times, escalating to %s", e.Gate, e.Failures, e.Model)
+}
+
+// EscalationCallback is called when a gate's failures escalate the workflow
+// to the escalation model.
+type EscalationCallback func(e Escalation)
+
+// SetEscalationCallback sets the callback called when a failing gate is
+// escalated to the workflow's escalation model.
+func (r *Runner) SetEscalationCallback(cb EscalationCallback) {
+	r.escalationCallback = cb
+}
+
+// EffectiveEscalateAfter returns the configured number of gate failures
+// before escalating, or the default. The default is lowered when the gate
+// retry limit would stop the workflow before it is reached.
+func (w *Workflow) EffectiveEscalateAfter() int {
+	if w.EscalateAfter > 0 {
+		return w.EscalateAfter
+	}
+	return max(1, min(DefaultEscalateAfter, w.EffectiveMaxGateRetries()-1))
+}
+
+// validateEscalation checks the escalation settings against the gate retry
+// limit.
+func (w *Workflow) validateEscalation() error {
+	if w.EscalateAfter < 0 {
+		return fmt.Errorf("escalate_after cannot be negative")
+	}
+	if w.EscalateAfter > 0 && w.EscalateModel == "" {
+		return fmt.Errorf("escalate_after requires escalate_model")
+	}
+	if w.EscalateAfter > 0 && w.EscalateAfter >= w.EffectiveMaxGateRetries() {
+		return fmt.Errorf("escalate_after (%d) must be below max_gate_retries (%d)", w.EscalateAfter, w.EffectiveMaxGateRetries())
+	}
+	return nil
+}
+
+// escalation returns the escalation a gate's failures call for, or nil when
+// the workflow has no escalation model, the gate has not failed often enough
+// or the executor cannot switch models.
+func (r *Runner) escalation(gate string, failures int) *Escalation {
+	if r.workflow.EscalateModel == "" || failures < r.workflow.EffectiveEscalateAfter() {
+		return nil
+	}
+	if _, ok := r.executor.(ModelStepExecutor); !ok {
+		return nil
+	}
+	return &Escalation{Gate: gate, Failures: failures, Model: r.workflow.EscalateModel}
+}
+
+// escalate returns the gate the steps are escalated by after gate has failed
+// failures times: escalatedBy while an escalation is in effect, gate when its
+// failures start one, or empty. A new escalation is reported to the
+// escalation callback.
+func (r *Runner) escalate(escalatedBy, gate string, failures int) string {
+	if escalatedBy != "" {
+		return escalatedBy
+	}
+	e := r.escalation(gate, failures)
+	if e == nil {
+		return ""
+	}
+	if r.escalationCallback != nil {
+		r.escalationCallback(*e)
+	}
+	return gate
+}
+
+// executeStep runs a step on model, or on the executor's own model when
+// model is empty.
+func (r *Runner) executeStep(ctx context.Context, stepName, prompt, model string) (*ExecutionResult, error) {
+	if exec, ok := r.executor.(ModelStepExecutor); ok && model != "" {
+		return exec.ExecuteStepOnModel(ctx, stepName, prompt, model)
+	}
+	return r.executor.ExecuteStep(ctx, stepName, prompt)
+}
@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// modelStepExecutor records the model each step ran on.
+type modelStepExecutor struct {
+	*mockStepExecutor
+	models []string
+}
+
+func (m *modelStepExecutor) ExecuteStep(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+	m.models = append(m.models, "")
+	return m.mockStepExecutor.ExecuteStep(ctx, stepName, prompt)
+}
+
+func (m *modelStepExecutor) ExecuteStepOnModel(ctx context.Context, stepName, prompt, model string) (*ExecutionResult, error) {
+	m.models = append(m.models, model)
+	return m.mockStepExecutor.ExecuteStep(ctx, stepName, prompt)
+}
+
+// failingReview fails the review gate the first n times.
+func failingReview(n int) func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+	reviews := 0
+	return func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+		output := "done"
+		if stepName == "review" {
+			reviews++
+			output = GatePassTag
+			if reviews <= n {
+				output = GateFailTag
+			}
+		}
+		return &ExecutionResult{StepName: stepName, Output: output, CostUSD: 0.01}, nil
+	}
+}
+
+func escalationWorkflow() *Workflow {
+	return &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+			{Name: "review", Prompt: "Review", Gate: true, OnFail: "implement"},
+		},
+		MaxGateRetries: 4,
+		EscalateModel:  "opus",
+	}
+}
+
+func TestRunner_Run_EscalatesAfterRepeatedGateFailures(t *testing.T) {
+	exec := &modelStepExecutor{mockStepExecutor: newMockExecutor()}
+	exec.customHandler = failingReview(2)
+
+	runner := NewRunner(escalationWorkflow(), exec)
+	var escalations []Escalation
+	runner.SetEscalationCallback(func(e Escalation) {
+		escalations = append(escalations, e)
+	})
+	var completed []string
+	runner.SetCallback(func(info StepInfo, result *ExecutionResult, gate GateResult) error {
+		completed = append(completed, info.Name+"@"+info.Model)
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps {
+		t.Error("CompletedAllSteps = false, want true")
+	}
+
+	wantModels := []string{"", "", "", "", "opus", "opus"}
+	if strings.Join(exec.models, ",") != strings.Join(wantModels, ",") {
+		t.Errorf("models = %q, want %q", exec.models, wantModels)
+	}
+	wantCompleted := "implement@,review@,implement@,review@,implement@opus,review@opus"
+	if got := strings.Join(completed, ","); got != wantCompleted {
+		t.Errorf("completed = %s, want %s", got, wantCompleted)
+	}
+	if len(escalations) != 1 {
+		t.Fatalf("escalations = %v, want one", escalations)
+	}
+	if want := (Escalation{Gate: "review", Failures: 2, Model: "opus"}); escalations[0] != want {
+		t.Errorf("escalation = %+v, want %+v", escalations[0], want)
+	}
+}
+
+func TestRunner_Run_EscalationEndsWhenGatePasses(t *testing.T) {
+	w := escalationWorkflow()
+	w.Steps = append(w.Steps, Step{Name: "document", Prompt: "Document"})
+
+	exec := &modelStepExecutor{mockStepExecutor: newMockExecutor()}
+	exec.customHandler = failingReview(2)
+
+	if _, err := NewRunner(w, exec).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if last := exec.models[len(exec.models)-1]; last != "" {
+		t.Errorf("step after the passing gate ran on %q, want the run's model", last)
+	}
+}
+
+func TestRunner_Run_NoEscalationWithoutModelExecutor(t *testing.T) {
+	exec := newMockExecutor()
+	exec.customHandler = failingReview(2)
+
+	runner := NewRunner(escalationWorkflow(), exec)
+	runner.SetEscalationCallback(func(e Escalation) {
+		t.Errorf("unexpected escalation %v", e)
+	})
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestRunner_Run_NoEscalationWithoutModel(t *testing.T) {
+	w := escalationWorkflow()
+	w.EscalateModel = ""
+
+	exec := &modelStepExecutor{mockStepExecutor: newMockExecutor()}
+	exec.customHandler = failingReview(3)
+
+	if _, err := NewRunner(w, exec).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for i, model := range exec.models {
+		if model != "" {
+			t.Errorf("models[%d] = %q, want the run's model", i, model)
+		}
+	}
+}
+
+func TestWorkflow_EffectiveEscalateAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		after      int
+		maxRetries int
+		want       int
+	}{
+		{name: "default", want: DefaultEscalateAfter},
+		{name: "configured", after: 1, maxRetries: 5, want: 1},
+		{name: "default below retry limit", maxRetries: 2, want: 1},
+		{name: "single retry", maxRetries: 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Workflow{EscalateAfter: tt.after, MaxGateRetries: tt.maxRetries}
+			if got := w.EffectiveEscalateAfter(); got != tt.want {
+				t.Errorf("EffectiveEscalateAfter() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflow_Validate_Escalation(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   string
+		after   int
+		wantErr string
+	}{
+		{name: "model only", model: "opus"},
+		{name: "model and after", model: "opus", after: 2},
+		{name: "negative after", model: "opus", after: -1, wantErr: "cannot be negative"},
+		{name: "after without model", after: 2, wantErr: "requires escalate_model"},
+		{name: "after at retry limit", model: "opus", after: 3, wantErr: "must be below max_gate_retries"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Workflow{
+				Steps:         []Step{{Name: "review", Prompt: "Review", Gate: true}},
+				EscalateModel: tt.model,
+				EscalateAfter: tt.after,
+			}
+			err := w.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -24,6 +26,17 @@ Continue from where you left off. Do not start over. Complete the remaining work
 
 `
 
+// RecentNotesPrompt is appended to a step prompt that does not place
+// {{recent_notes}} itself. The verb is replaced with the recent notes.
+const RecentNotesPrompt = `
+
+---
+RECENT NOTES from previous iterations (the full history is in the notes file):
+
+%s
+---
+`
+
 // ExecutionResult contains the result of executing a single step.
 type ExecutionResult struct {
 	// StepName is the name of the step that was executed.
@@ -40,6 +53,26 @@ type ExecutionResult struct {
 
 	// TokensOut is the number of output tokens used by this step.
 	TokensOut int
+
+	// CacheReadTokens and CacheCreationTokens are the parts of TokensIn
+	// read from and written to the prompt cache.
+	CacheReadTokens     int
+	CacheCreationTokens int
+
+	// CPUTime is the CPU time the Claude process used for this step.
+	CPUTime time.Duration
+
+	// PeakRSS is the Claude process's peak resident memory in bytes.
+	PeakRSS int64
+
+	// Cached is set when the result came from the step cache instead of
+	// Claude. It costs nothing.
+	Cached bool
+
+	// Offline is set when the step failed because Claude could not reach
+	// the API. The runner waits for the network and runs it again (see
+	// SetOfflineWait).
+	Offline bool
 }
 
 // StepExecutor is the interface for executing a single workflow step.
@@ -74,6 +107,29 @@ type StepInfo struct {
 
 	// IsTimeoutRetry indicates this is a retry after timeout.
 	IsTimeoutRetry bool
+
+	// Scores are the judge's rubric scores, set in the completion callback
+	// for gates with a rubric that reported valid scores.
+	Scores map[string]float64
+
+	// Artifacts are the declared artifacts the step wrote, set in the
+	// completion callback.
+	Artifacts []string
+
+	// Panel holds the reviewers' verdicts, set in the completion callback
+	// for panel gates.
+	Panel *PanelResult
+
+	// Model is the escalation model the step runs on after repeated gate
+	// failures, or empty when it runs on the executor's own model.
+	Model string
+
+	// Dry is set for a dry step, which is not sent to Claude.
+	Dry bool
+
+	// Prompt is the prompt a dry step would have sent, set in the completion
+	// callback.
+	Prompt string
 }
 
 // RunnerCallback is called after each step completes.
@@ -84,10 +140,13 @@ type StepStartCallback func(info StepInfo)
 
 // Runner executes a workflow by running its steps in sequence.
 type Runner struct {
-	workflow      *Workflow
-	executor      StepExecutor
-	callback      RunnerCallback
-	startCallback StepStartCallback
+	workflow           *Workflow
+	executor           StepExecutor
+	callback           RunnerCallback
+	startCallback      StepStartCallback
+	escalationCallback EscalationCallback
+	// gateTimeoutCallback is called when a gate bounded by [workflow.gate] times out.
+	gateTimeoutCallback GateTimeoutCallback
 
 	// filePaths is used for template substitution in prompts (all files).
 	filePaths []string
@@ -100,6 +159,46 @@ type Runner struct {
 
 	// notesFile is the path to the notes file for cross-iteration context.
 	notesFile string
+
+	// recentNotes is the recent window of the notes file passed into prompts.
+	recentNotes string
+
+	// leanContext replaces recentNotes after a failed check when lean
+	// prompts are enabled.
+	leanContext string
+
+	// promptSuffix is appended to every step prompt (e.g. an escalation notice).
+	promptSuffix string
+
+	// promptPrefix is placed before every step prompt (an operator
+	// interjection). It is never trimmed to the context budget.
+	promptPrefix string
+
+	// workingDir resolves relative rubric and artifact paths.
+	workingDir string
+
+	// artifacts are the artifact paths produced by steps so far, passed into
+	// later step prompts.
+	artifacts []string
+
+	// cache holds the results of cacheable steps.
+	cache StepCache
+
+	// budget caps the tokens a step prompt brings in, and budgetCallback
+	// reports what was trimmed to fit it.
+	budget         *ContextBudget
+	budgetCallback BudgetCallback
+
+	// offlineWait pauses the run while the network is down.
+	offlineWait OfflineWaitFunc
+
+	// approve asks a person to decide on human gates.
+	approve ApproveFunc
+
+	// treeState describes the working tree for diff-empty-fail gates, and
+	// unchanged lists the steps that changed nothing since the last one.
+	treeState TreeStateFunc
+	unchanged []string
 }
 
 // NewRunner creates a new workflow runner.
@@ -140,6 +239,36 @@ func (r *Runner) SetNotesFile(path string) {
 	r.notesFile = path
 }
 
+// SetRecentNotes sets the recent notes passed into step prompts, either at
+// {{recent_notes}} or appended to the prompt. An empty string omits them.
+func (r *Runner) SetRecentNotes(notes string) {
+	r.recentNotes = notes
+}
+
+// SetLeanContext sets text passed into step prompts in place of the recent
+// notes, such as the work left after a failed check. An empty string restores
+// the recent notes.
+func (r *Runner) SetLeanContext(context string) {
+	r.leanContext = context
+}
+
+// SetWorkingDir sets the directory relative rubric paths are resolved against.
+func (r *Runner) SetWorkingDir(dir string) {
+	r.workingDir = dir
+}
+
+// SetPromptSuffix sets text appended to every step prompt. An empty string
+// removes it.
+func (r *Runner) SetPromptSuffix(suffix string) {
+	r.promptSuffix = suffix
+}
+
+// SetPromptPrefix sets text placed before every step prompt. An empty string
+// removes it.
+func (r *Runner) SetPromptPrefix(prefix string) {
+	r.promptPrefix = prefix
+}
+
 // RunResult contains the result of running the entire workflow.
 type RunResult struct {
 	// Steps contains results for each step executed.
@@ -158,6 +287,28 @@ type RunResult struct {
 	CompletedAllSteps bool
 }
 
+// Output returns the outputs of all steps run, concatenated in order.
+func (r *RunResult) Output() string {
+	var b strings.Builder
+	for _, step := range r.Steps {
+		if step != nil {
+			b.WriteString(step.Output)
+		}
+	}
+	return b.String()
+}
+
+// PromiseStep returns the first step whose output contains promise, or nil
+// if none does.
+func (r *RunResult) PromiseStep(promise string) *StepResult {
+	for _, step := range r.Steps {
+		if step != nil && strings.Contains(step.Output, promise) {
+			return step
+		}
+	}
+	return nil
+}
+
 // StepResult contains the result of a single step execution.
 type StepResult struct {
 	StepName   string
@@ -167,6 +318,12 @@ type StepResult struct {
 	TokensOut  int
 	GateResult GateResult
 	RetryCount int
+	Scores     map[string]float64
+	Panel      *PanelResult
+	// Dry is set when the step was not sent to Claude (see Step.Dry).
+	Dry bool
+	// Cached is set when the step's result came from the step cache.
+	Cached bool
 }
 
 // Run executes all workflow steps in sequence.
@@ -179,8 +336,16 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 	stepIndex := 0
 	gateRetries := make(map[string]int)
 	timeoutRetries := make(map[string]bool)
+	gateTimeouts := make(map[string]int)
+	artifactRetries := make(map[string][]string)
 	arrivedViaOnFail := false
 
+	// escalatedBy is the gate whose failures switched the steps to the
+	// escalation model, until it passes
+	escalatedBy := ""
+
+	r.unchanged = nil
+
 	for stepIndex < len(r.workflow.Steps) {
 		step := r.workflow.Steps[stepIndex]
 
@@ -196,6 +361,11 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 		// Check if this is a timeout retry
 		isTimeoutRetry := timeoutRetries[step.Name]
 
+		model := ""
+		if escalatedBy != "" {
+			model = r.workflow.EscalateModel
+		}
+
 		// Call start callback if set
 		if r.startCallback != nil {
 			info := StepInfo{
@@ -205,28 +375,71 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 				GateRetries:    gateRetries[step.Name],
 				MaxRetries:     r.workflow.EffectiveMaxGateRetries(),
 				IsGate:         step.Gate,
-				Timeout:        step.EffectiveTimeout(),
+				Timeout:        r.workflow.StepTimeout(&step),
 				IsTimeoutRetry: isTimeoutRetry,
+				Model:          model,
+				Dry:            step.Dry,
 			}
 			r.startCallback(info)
 		}
 
-		// Build the prompt with template substitution
-		prompt := r.buildPrompt(step.Prompt, step.EffectiveTimeout())
+		// Build the prompt with template substitution, within the context budget
+		prompt := r.stepPrompt(&step)
+
+		// Inject the gate's rubric. It is re-read every time so edits apply mid-run.
+		if step.Rubric != "" {
+			rubric, err := r.readRubric(step.Rubric)
+			if err != nil {
+				return result, fmt.Errorf("step %q: %w", step.Name, err)
+			}
+			prompt += fmt.Sprintf(RubricPrompt, rubric)
+		}
+
+		// Ask a review panel for one verdict per agent
+		if step.Gate && step.Panel != nil {
+			prompt += panelPromptText(step.Panel)
+		}
 
 		// If this is a timeout retry, append the continuation prompt
 		if isTimeoutRetry {
-			prompt += fmt.Sprintf(TimeoutContinuationPrompt, formatDuration(step.EffectiveTimeout()))
+			prompt += fmt.Sprintf(TimeoutContinuationPrompt, formatDuration(r.workflow.StepTimeout(&step)))
 		}
 
-		// Create timeout context for this step
-		stepCtx, stepCancel := context.WithTimeout(ctx, step.EffectiveTimeout())
+		// Name the files the step must write, and any it failed to write last time
+		artifactPaths := r.artifactPaths(step)
+		prompt += artifactsPromptText(artifactPaths, artifactRetries[step.Name])
+		artifactsBefore := statArtifacts(artifactPaths)
 
-		// Execute the step
-		execResult, err := r.executor.ExecuteStep(stepCtx, step.Name, prompt)
+		// A dry step reports its prompt instead of sending it; a dry gate passes
+		if step.Dry {
+			if escalatedBy == step.Name {
+				escalatedBy = ""
+			}
+			if err := r.runDryStep(result, step, stepIndex, prompt, gateRetries[step.Name], model); err != nil {
+				return result, err
+			}
+			stepIndex++
+			continue
+		}
 
-		// Cancel the step context to release resources
-		stepCancel()
+		// A human gate waits for a person's decision instead of Claude, and
+		// a diff gate checks the steps before it changed the repository.
+		// Otherwise reuse a cacheable step's earlier result, or execute the
+		// step with its own timeout, waiting out any network outage.
+		var execResult *ExecutionResult
+		var decided GateResult
+		var err error
+		treeBefore := r.treeBefore(ctx, step)
+		if step.IsHumanGate() {
+			execResult, decided, err = r.awaitApproval(ctx, step)
+			if err != nil {
+				return result, fmt.Errorf("step %q: %w", step.Name, err)
+			}
+		} else if step.IsDiffGate() {
+			execResult, decided = r.checkDiffGate(step)
+		} else if execResult = r.cachedResult(step, model, prompt, artifactsBefore); execResult == nil {
+			execResult, err = r.runStep(ctx, step, prompt, model, result)
+		}
 
 		// Handle timeout - retry once
 		if err != nil && errors.Is(err, context.DeadlineExceeded) {
@@ -237,6 +450,16 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 				result.TotalTokensOut += execResult.TokensOut
 			}
 
+			// A model-judged gate bounded by [workflow.gate] starts over
+			// after a backoff, as often as its retries allow
+			if r.workflow.isJudgedGate(&step) {
+				gateTimeouts[step.Name]++
+				if err := r.gateTimedOut(ctx, &step, gateTimeouts[step.Name]); err != nil {
+					return result, err
+				}
+				continue
+			}
+
 			// Check if we already retried this step for timeout
 			if timeoutRetries[step.Name] {
 				return result, fmt.Errorf("%w: step %q timed out twice", ErrStepTimedOut, step.Name)
@@ -247,8 +470,9 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 			continue
 		}
 
-		// Clear timeout retry flag on successful execution
+		// Clear timeout retry flags on successful execution
 		delete(timeoutRetries, step.Name)
+		delete(gateTimeouts, step.Name)
 
 		if err != nil {
 			return result, fmt.Errorf("step %q failed: %w", step.Name, err)
@@ -258,11 +482,37 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 		result.TotalCost += execResult.CostUSD
 		result.TotalTokensIn += execResult.TokensIn
 		result.TotalTokensOut += execResult.TokensOut
+		r.noteTreeChange(ctx, step, treeBefore, execResult)
 
 		// Check gate if this is a gate step
 		var gateResult GateResult
+		var scores map[string]float64
+		var panel *PanelResult
 		if step.Gate {
-			gateResult = CheckGate(execResult.Output)
+			switch {
+			case step.IsBuiltinGate():
+				gateResult = decided
+			case step.Panel != nil:
+				gateResult, panel = CheckPanel(execResult.Output, step.Panel)
+			default:
+				gateResult = CheckGate(execResult.Output)
+			}
+			if step.Rubric != "" {
+				scores = ParseScores(execResult.Output)
+			}
+		}
+
+		// Check the step wrote its artifacts. A cached result's artifacts
+		// were written when it was cached.
+		writtenArtifacts, missingArtifacts := checkArtifacts(artifactPaths, artifactsBefore)
+		if execResult.Cached {
+			writtenArtifacts, missingArtifacts = artifactPaths, nil
+		}
+		r.recordArtifacts(writtenArtifacts)
+
+		// Cache the result of a cacheable step that succeeded
+		if step.Cacheable && r.cache != nil && !execResult.Cached && len(missingArtifacts) == 0 && (!step.Gate || gateResult == GatePassed) {
+			r.cache.Put(step.Name, model, prompt, execResult)
 		}
 
 		// Record step result
@@ -274,6 +524,9 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 			TokensOut:  execResult.TokensOut,
 			GateResult: gateResult,
 			RetryCount: gateRetries[step.Name],
+			Scores:     scores,
+			Panel:      panel,
+			Cached:     execResult.Cached,
 		}
 		result.Steps = append(result.Steps, stepResult)
 
@@ -286,18 +539,36 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 				GateRetries:    gateRetries[step.Name],
 				MaxRetries:     r.workflow.EffectiveMaxGateRetries(),
 				IsGate:         step.Gate,
-				Timeout:        step.EffectiveTimeout(),
+				Timeout:        r.workflow.StepTimeout(&step),
 				IsTimeoutRetry: isTimeoutRetry,
+				Scores:         scores,
+				Artifacts:      writtenArtifacts,
+				Panel:          panel,
+				Model:          model,
 			}
 			if err := r.callback(info, execResult, gateResult); err != nil {
 				return result, err
 			}
 		}
 
+		// Retry once if artifacts were not written
+		if len(missingArtifacts) > 0 {
+			if _, retried := artifactRetries[step.Name]; retried {
+				return result, fmt.Errorf("%w: step %q did not write %s", ErrArtifactMissing, step.Name, strings.Join(missingArtifacts, ", "))
+			}
+			artifactRetries[step.Name] = missingArtifacts
+			continue
+		}
+		delete(artifactRetries, step.Name)
+
 		// Handle gate result
 		if step.Gate {
 			switch gateResult {
 			case GatePassed:
+				// Return to the run's model once the escalated gate passes
+				if escalatedBy == step.Name {
+					escalatedBy = ""
+				}
 				// Continue to next step
 				stepIndex++
 
@@ -309,6 +580,7 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 				if gateRetries[step.Name] >= r.workflow.EffectiveMaxGateRetries() {
 					return result, fmt.Errorf("%w: step %q failed %d times", ErrMaxGateRetriesExceeded, step.Name, gateRetries[step.Name])
 				}
+				escalatedBy = r.escalate(escalatedBy, step.Name, gateRetries[step.Name])
 
 				// Loop back to on_fail step
 				if step.OnFail != "" {
@@ -328,6 +600,7 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 				if gateRetries[step.Name] >= r.workflow.EffectiveMaxGateRetries() {
 					return result, fmt.Errorf("%w: step %q did not output gate signal after %d attempts", ErrMaxGateRetriesExceeded, step.Name, gateRetries[step.Name])
 				}
+				escalatedBy = r.escalate(escalatedBy, step.Name, gateRetries[step.Name])
 				// Retry the step
 			}
 		} else {
@@ -340,6 +613,37 @@ func (r *Runner) Run(ctx context.Context) (*RunResult, error) {
 	return result, nil
 }
 
+// runDryStep records a dry step as run without executing it and passes the
+// prompt it would have sent to the completion callback.
+func (r *Runner) runDryStep(result *RunResult, step Step, stepIndex int, prompt string, retries int, model string) error {
+	var gateResult GateResult
+	if step.Gate {
+		gateResult = GatePassed
+	}
+	result.Steps = append(result.Steps, &StepResult{
+		StepName:   step.Name,
+		GateResult: gateResult,
+		RetryCount: retries,
+		Dry:        true,
+	})
+	if r.callback == nil {
+		return nil
+	}
+	info := StepInfo{
+		Name:        step.Name,
+		Position:    stepIndex + 1, // 1-indexed
+		Total:       len(r.workflow.Steps),
+		GateRetries: retries,
+		MaxRetries:  r.workflow.EffectiveMaxGateRetries(),
+		IsGate:      step.Gate,
+		Timeout:     r.workflow.StepTimeout(&step),
+		Model:       model,
+		Dry:         true,
+		Prompt:      prompt,
+	}
+	return r.callback(info, &ExecutionResult{StepName: step.Name}, gateResult)
+}
+
 // GetFirstStepPrompt returns the first step's prompt with template substitutions applied.
 // This is useful for displaying the initial prompt in the TUI.
 func (r *Runner) GetFirstStepPrompt() string {
@@ -350,12 +654,11 @@ func (r *Runner) GetFirstStepPrompt() string {
 	for i := range r.workflow.Steps {
 		step := &r.workflow.Steps[i]
 		if !step.Deferred {
-			return r.buildPrompt(step.Prompt, step.EffectiveTimeout())
+			return r.buildStepPrompt(step)
 		}
 	}
 	// All steps are deferred, return first step's prompt anyway
-	step := &r.workflow.Steps[0]
-	return r.buildPrompt(step.Prompt, step.EffectiveTimeout())
+	return r.buildStepPrompt(&r.workflow.Steps[0])
 }
 
 // GetStepPrompt returns a step's prompt by name with template substitutions applied.
@@ -364,21 +667,58 @@ func (r *Runner) GetStepPrompt(name string) string {
 	for i := range r.workflow.Steps {
 		step := &r.workflow.Steps[i]
 		if step.Name == name {
-			return r.buildPrompt(step.Prompt, step.EffectiveTimeout())
+			return r.buildStepPrompt(step)
 		}
 	}
 	return ""
 }
 
+// readRubric returns the contents of a rubric file.
+func (r *Runner) readRubric(path string) (string, error) {
+	if !filepath.IsAbs(path) && r.workingDir != "" {
+		path = filepath.Join(r.workingDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read rubric: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// buildStepPrompt substitutes template placeholders in a step's prompt,
+// listing the step's own context files when it selects them.
+func (r *Runner) buildStepPrompt(step *Step) string {
+	return r.buildStepPromptWith(step, r.stepContextFiles(step))
+}
+
+// buildStepPromptWith is buildStepPrompt listing contextFiles, the step's
+// context files or some of them, as its context files.
+func (r *Runner) buildStepPromptWith(step *Step, contextFiles []string) string {
+	if step.Context == nil && len(contextFiles) == len(r.contextFiles) {
+		return r.buildPrompt(step.Prompt, r.workflow.StepTimeout(step))
+	}
+	filePaths := r.filePaths
+	if r.specFile != "" {
+		filePaths = append([]string{r.specFile}, contextFiles...)
+	}
+	return r.buildPromptWith(step.Prompt, r.workflow.StepTimeout(step), filePaths, contextFiles)
+}
+
 // buildPrompt substitutes template placeholders in the prompt.
 // The timeout parameter is the step's effective timeout for the {{timeout}} placeholder.
 func (r *Runner) buildPrompt(template string, timeout time.Duration) string {
+	return r.buildPromptWith(template, timeout, r.filePaths, r.contextFiles)
+}
+
+// buildPromptWith substitutes template placeholders in the prompt, listing
+// filePaths for {{files}} and contextFiles for {{context_files}}.
+func (r *Runner) buildPromptWith(template string, timeout time.Duration, filePaths, contextFiles []string) string {
 	result := template
 
 	// Handle {{files}} placeholder (backwards compatible - all files)
-	if len(r.filePaths) > 0 {
+	if len(filePaths) > 0 {
 		var fileList strings.Builder
-		for _, path := range r.filePaths {
+		for _, path := range filePaths {
 			fileList.WriteString("- ")
 			fileList.WriteString(path)
 			fileList.WriteString("\n")
@@ -392,9 +732,9 @@ func (r *Runner) buildPrompt(template string, timeout time.Duration) string {
 	}
 
 	// Handle {{context_files}} placeholder (additional reference files)
-	if len(r.contextFiles) > 0 {
+	if len(contextFiles) > 0 {
 		var contextList strings.Builder
-		for _, path := range r.contextFiles {
+		for _, path := range contextFiles {
 			contextList.WriteString("- ")
 			contextList.WriteString(path)
 			contextList.WriteString("\n")
@@ -411,6 +751,38 @@ func (r *Runner) buildPrompt(template string, timeout time.Duration) string {
 		result = strings.ReplaceAll(result, "{{notes_file}}", "(no notes file)")
 	}
 
+	// Handle {{artifacts}} placeholder, appending the paths if it is absent.
+	// Appended sections go from least to most volatile, artifacts before
+	// notes, so consecutive prompts share the longest prefix the prompt
+	// cache can reuse.
+	if strings.Contains(result, "{{artifacts}}") {
+		artifacts := "(none yet)"
+		if len(r.artifacts) > 0 {
+			artifacts = formatPathList(r.artifacts)
+		}
+		result = strings.ReplaceAll(result, "{{artifacts}}", artifacts)
+	} else if len(r.artifacts) > 0 {
+		result += fmt.Sprintf(ArtifactsPrompt, formatPathList(r.artifacts))
+	}
+
+	// Handle {{recent_notes}} placeholder, appending the notes if it is absent.
+	// Lean context takes the notes' place.
+	if r.leanContext != "" {
+		if strings.Contains(result, "{{recent_notes}}") {
+			result = strings.ReplaceAll(result, "{{recent_notes}}", strings.TrimSpace(r.leanContext))
+		} else {
+			result += r.leanContext
+		}
+	} else if strings.Contains(result, "{{recent_notes}}") {
+		notes := r.recentNotes
+		if notes == "" {
+			notes = "(no notes yet)"
+		}
+		result = strings.ReplaceAll(result, "{{recent_notes}}", notes)
+	} else if r.recentNotes != "" {
+		result += fmt.Sprintf(RecentNotesPrompt, r.recentNotes)
+	}
+
 	// Handle {{timeout}} placeholder (human-readable step timeout)
 	result = strings.ReplaceAll(result, "{{timeout}}", formatDuration(timeout))
 
@@ -3,6 +3,10 @@ package workflow
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -98,6 +102,24 @@ func TestRunner_Run_SingleStep(t *testing.T) {
 	}
 }
 
+func TestRunResult_OutputAndPromiseStep(t *testing.T) {
+	result := &RunResult{Steps: []*StepResult{
+		{StepName: "implement", Output: "working "},
+		nil,
+		{StepName: "review", Output: "<promise>COMPLETE</promise>"},
+	}}
+
+	if got, want := result.Output(), "working <promise>COMPLETE</promise>"; got != want {
+		t.Errorf("Output() = %q, want %q", got, want)
+	}
+	if step := result.PromiseStep("<promise>COMPLETE</promise>"); step == nil || step.StepName != "review" {
+		t.Errorf("PromiseStep() = %+v, want the review step", step)
+	}
+	if step := result.PromiseStep("<promise>OTHER</promise>"); step != nil {
+		t.Errorf("PromiseStep() = %+v, want nil", step)
+	}
+}
+
 func TestRunner_Run_MultipleSteps(t *testing.T) {
 	w := &Workflow{
 		Steps: []Step{
@@ -304,6 +326,193 @@ func TestRunner_Run_TemplateSubstitution(t *testing.T) {
 	}
 }
 
+func TestRunner_Run_PromptSuffix(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+		},
+	}
+
+	var capturedPrompts []string
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+		capturedPrompts = append(capturedPrompts, prompt)
+		return &ExecutionResult{StepName: stepName, Output: "Done"}, nil
+	}
+
+	runner := NewRunner(w, exec)
+	runner.SetPromptSuffix("\nChange approach.")
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	runner.SetPromptSuffix("")
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"Implement\nChange approach.", "Implement"}
+	if len(capturedPrompts) != 2 || capturedPrompts[0] != want[0] || capturedPrompts[1] != want[1] {
+		t.Errorf("prompts = %q, want %q", capturedPrompts, want)
+	}
+}
+
+func TestRunner_Run_PromptPrefix(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+		},
+	}
+
+	var capturedPrompts []string
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+		capturedPrompts = append(capturedPrompts, prompt)
+		return &ExecutionResult{StepName: stepName, Output: "Done"}, nil
+	}
+
+	runner := NewRunner(w, exec)
+	runner.SetPromptPrefix("Stop first.\n")
+	runner.SetPromptSuffix("\nChange approach.")
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	runner.SetPromptPrefix("")
+	if _, err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{"Stop first.\nImplement\nChange approach.", "Implement\nChange approach."}
+	if len(capturedPrompts) != 2 || capturedPrompts[0] != want[0] || capturedPrompts[1] != want[1] {
+		t.Errorf("prompts = %q, want %q", capturedPrompts, want)
+	}
+}
+
+func TestRunner_buildPrompt_RecentNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		notes    string
+		lean     string
+		want     string
+	}{
+		{
+			name:     "placeholder replaced",
+			template: "Notes:\n{{recent_notes}}",
+			notes:    "## Iteration 1 — implement — 2026-01-02 10:00\nDid a thing",
+			want:     "Notes:\n## Iteration 1 — implement — 2026-01-02 10:00\nDid a thing",
+		},
+		{
+			name:     "placeholder without notes",
+			template: "Notes:\n{{recent_notes}}",
+			want:     "Notes:\n(no notes yet)",
+		},
+		{
+			name:     "appended when placeholder absent",
+			template: "Implement",
+			notes:    "recent",
+			want:     "Implement" + fmt.Sprintf(RecentNotesPrompt, "recent"),
+		},
+		{
+			name:     "nothing appended without notes",
+			template: "Implement",
+			want:     "Implement",
+		},
+		{
+			name:     "lean context replaces appended notes",
+			template: "Implement",
+			notes:    "recent",
+			lean:     "\n\nREMAINING WORK\n",
+			want:     "Implement\n\nREMAINING WORK\n",
+		},
+		{
+			name:     "lean context fills placeholder",
+			template: "Notes:\n{{recent_notes}}",
+			notes:    "recent",
+			lean:     "\n\nREMAINING WORK\n",
+			want:     "Notes:\nREMAINING WORK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(&Workflow{}, newMockExecutor())
+			runner.SetRecentNotes(tt.notes)
+			runner.SetLeanContext(tt.lean)
+			if got := runner.buildPrompt(tt.template, time.Minute); got != tt.want {
+				t.Errorf("buildPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_Run_GateRubric(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "rubrics"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "rubrics", "review.md"), []byte("- correctness\n- tests\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "review", Prompt: "Review", Gate: true, Rubric: "rubrics/review.md"},
+		},
+	}
+
+	var capturedPrompt string
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		capturedPrompt = prompt
+		return &ExecutionResult{
+			StepName: stepName,
+			Output:   `<scores>{"correctness": 4, "overall": 4}</scores><gate>PASS</gate>`,
+		}, nil
+	}
+
+	var callbackScores map[string]float64
+	runner := NewRunner(w, exec)
+	runner.SetWorkingDir(dir)
+	runner.SetCallback(func(info StepInfo, result *ExecutionResult, gateResult GateResult) error {
+		callbackScores = info.Scores
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(capturedPrompt, "<rubric>\n- correctness\n- tests\n</rubric>") {
+		t.Errorf("prompt missing rubric: %q", capturedPrompt)
+	}
+	if got := result.Steps[0].Scores["overall"]; got != 4 {
+		t.Errorf("StepResult.Scores[overall] = %v, want 4", got)
+	}
+	if got := callbackScores["correctness"]; got != 4 {
+		t.Errorf("StepInfo.Scores[correctness] = %v, want 4", got)
+	}
+}
+
+func TestRunner_Run_GateRubricMissing(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "review", Prompt: "Review", Gate: true, Rubric: "missing.md"},
+		},
+	}
+
+	exec := newMockExecutor()
+	runner := NewRunner(w, exec)
+	runner.SetWorkingDir(t.TempDir())
+
+	_, err := runner.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to read rubric") {
+		t.Errorf("Run() error = %v, want rubric read error", err)
+	}
+	if len(exec.calls) != 0 {
+		t.Errorf("executor called %d times, want 0", len(exec.calls))
+	}
+}
+
 func TestRunner_Run_Callback(t *testing.T) {
 	w := &Workflow{
 		Steps: []Step{
@@ -844,3 +1053,59 @@ func TestRunner_Run_TimeoutSubstitutionDefault(t *testing.T) {
 		t.Errorf("prompt = %q, want %q", capturedPrompt, expected)
 	}
 }
+
+func TestRunner_DryStep(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement {{spec_file}}"},
+			{Name: "review", Prompt: "Review {{spec_file}}", Gate: true, OnFail: "implement", Dry: true},
+			{Name: "document", Prompt: "Document", Artifacts: []string{"missing.md"}, Dry: true},
+		},
+	}
+	mock := newMockExecutor()
+	mock.setResponse("implement", "done", 0.01, 100)
+
+	runner := NewRunner(w, mock)
+	runner.SetSpecFile("spec.md")
+	runner.SetWorkingDir(t.TempDir())
+
+	var started []bool
+	runner.SetStartCallback(func(info StepInfo) {
+		started = append(started, info.Dry)
+	})
+	var dryPrompts []string
+	var gates []GateResult
+	runner.SetCallback(func(info StepInfo, result *ExecutionResult, gateResult GateResult) error {
+		if info.Dry {
+			dryPrompts = append(dryPrompts, info.Prompt)
+			if result.Output != "" || result.CostUSD != 0 {
+				t.Errorf("dry step %s result = %+v, want empty", info.Name, result)
+			}
+		}
+		gates = append(gates, gateResult)
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps {
+		t.Error("dry steps should not stop the workflow")
+	}
+	if strings.Join(mock.calls, ",") != "implement" {
+		t.Errorf("executed %v, want only implement", mock.calls)
+	}
+	if fmt.Sprint(started) != "[false true true]" {
+		t.Errorf("start callback Dry = %v", started)
+	}
+	if len(dryPrompts) != 2 || !strings.HasPrefix(dryPrompts[0], "Review spec.md") || !strings.Contains(dryPrompts[1], "missing.md") {
+		t.Errorf("dry prompts = %q", dryPrompts)
+	}
+	if gates[1] != GatePassed || gates[2] != GateNotFound {
+		t.Errorf("gate results = %v, want the dry gate passed", gates)
+	}
+	if len(result.Steps) != 3 || !result.Steps[1].Dry || result.Steps[1].GateResult != GatePassed || result.Steps[0].Dry {
+		t.Errorf("steps = %+v", result.Steps)
+	}
+}
@@ -1,6 +1,9 @@
 package workflow
 
-import "strings"
+import (
+	"encoding/json"
+	"strings"
+)
 
 // GateResult represents the result of a gate check.
 type GateResult int
@@ -59,3 +62,57 @@ func (r GateResult) String() string {
 		return "NOT_FOUND"
 	}
 }
+
+// ScoresOpenTag and ScoresCloseTag delimit a judge's JSON scores.
+const (
+	ScoresOpenTag  = "<scores>"
+	ScoresCloseTag = "</scores>"
+)
+
+// RubricPrompt is appended to a gate prompt when the gate has a rubric.
+// The placeholder is replaced with the rubric file's contents.
+const RubricPrompt = `
+
+---
+RUBRIC: Judge the work against the following rubric.
+
+<rubric>
+%s
+</rubric>
+
+Before the gate signal, output a score for each rubric criterion and an
+"overall" score as a JSON object inside <scores></scores> tags, e.g.
+<scores>{"correctness": 4, "tests": 3, "overall": 4}</scores>
+---
+`
+
+// ParseScores extracts the judge's scores from the last <scores> block in
+// output. Non-numeric values are ignored. Returns nil if there is no block or
+// it does not hold a JSON object.
+func ParseScores(output string) map[string]float64 {
+	start := strings.LastIndex(output, ScoresOpenTag)
+	if start < 0 {
+		return nil
+	}
+	body := output[start+len(ScoresOpenTag):]
+	end := strings.Index(body, ScoresCloseTag)
+	if end < 0 {
+		return nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(body[:end])), &raw); err != nil {
+		return nil
+	}
+
+	scores := make(map[string]float64, len(raw))
+	for name, value := range raw {
+		if n, ok := value.(float64); ok {
+			scores[name] = n
+		}
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	return scores
+}
@@ -1,6 +1,9 @@
 package workflow
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestCheckGate(t *testing.T) {
 	tests := []struct {
@@ -79,3 +82,38 @@ func TestGateResult_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   map[string]float64
+	}{
+		{
+			name:   "scores block",
+			output: `Looks good. <scores>{"correctness": 4, "tests": 3.5, "overall": 4}</scores> <gate>PASS</gate>`,
+			want:   map[string]float64{"correctness": 4, "tests": 3.5, "overall": 4},
+		},
+		{
+			name:   "last block wins",
+			output: "<scores>{\"overall\": 1}</scores>\nrevised:\n<scores>\n{\"overall\": 5}\n</scores>",
+			want:   map[string]float64{"overall": 5},
+		},
+		{
+			name:   "non-numeric values ignored",
+			output: `<scores>{"overall": 3, "notes": "fine"}</scores>`,
+			want:   map[string]float64{"overall": 3},
+		},
+		{name: "no block", output: "<gate>PASS</gate>", want: nil},
+		{name: "unterminated block", output: `<scores>{"overall": 3}`, want: nil},
+		{name: "invalid json", output: "<scores>overall: 3</scores>", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseScores(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseScores() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
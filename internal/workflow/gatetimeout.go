@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultGateTimeoutRetries is the number of times a gate that timed out is
+// run again when [workflow.gate] sets no retries.
+const DefaultGateTimeoutRetries = 1
+
+// DefaultGateBackoff is the wait before running a gate that timed out again
+// when [workflow.gate] sets no backoff. It doubles with each further retry.
+const DefaultGateBackoff = 10 * time.Second
+
+// GateConfig bounds how long model-judged gates may take, independently of
+// the steps' own timeouts. Human and diff gates are not affected.
+type GateConfig struct {
+	// Timeout is how long a gate step may run. Zero keeps the step's own
+	// timeout.
+	Timeout Duration `toml:"timeout" json:"timeout,omitempty"`
+
+	// Retries is the number of times a gate that timed out is run again
+	// before the iteration stops (default: 1).
+	Retries *int `toml:"retries" json:"retries,omitempty"`
+
+	// Backoff is the wait before the first retry, doubled for each one after
+	// it (default: 10s).
+	Backoff Duration `toml:"backoff" json:"backoff,omitempty"`
+}
+
+// EffectiveRetries returns the configured number of retries or the default.
+func (g *GateConfig) EffectiveRetries() int {
+	if g.Retries != nil {
+		return *g.Retries
+	}
+	return DefaultGateTimeoutRetries
+}
+
+// backoff returns the wait before retry n (1-indexed) of a gate that timed
+// out.
+func (g *GateConfig) backoff(n int) time.Duration {
+	d := time.Duration(g.Backoff)
+	if d <= 0 {
+		d = DefaultGateBackoff
+	}
+	return d << (n - 1)
+}
+
+// validate checks the gate settings.
+func (g *GateConfig) validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Timeout < 0 {
+		return fmt.Errorf("gate timeout cannot be negative")
+	}
+	if g.Retries != nil && *g.Retries < 0 {
+		return fmt.Errorf("gate retries cannot be negative")
+	}
+	if g.Backoff < 0 {
+		return fmt.Errorf("gate backoff cannot be negative")
+	}
+	return nil
+}
+
+// GateTimeout describes a gate that ran out of time.
+type GateTimeout struct {
+	// Gate is the gate step that timed out.
+	Gate string
+
+	// Attempt is the number of times the gate has now timed out in a row.
+	Attempt int
+
+	// Attempts is the number of attempts the gate is allowed.
+	Attempts int
+
+	// Timeout is how long each attempt may run.
+	Timeout time.Duration
+
+	// Backoff is the wait before the gate runs again, or zero when it has
+	// no attempts left.
+	Backoff time.Duration
+}
+
+// String describes the timeout, e.g. "gate review timed out after 3 minutes
+// (attempt 1 of 2), retrying in 10 seconds".
+func (t GateTimeout) String() string {
+	s := fmt.Sprintf("gate %s timed out after %s (attempt %d of %d)", t.Gate, formatDuration(t.Timeout), t.Attempt, t.Attempts)
+	if t.Backoff > 0 {
+		return s + ", retrying in " + formatDuration(t.Backoff)
+	}
+	return s + ", giving up"
+}
+
+// GateTimeoutCallback is called when a gate step times out, before it is
+// run again or the workflow stops.
+type GateTimeoutCallback func(t GateTimeout)
+
+// SetGateTimeoutCallback sets the callback called when a gate bounded by
+// [workflow.gate] times out.
+func (r *Runner) SetGateTimeoutCallback(cb GateTimeoutCallback) {
+	r.gateTimeoutCallback = cb
+}
+
+// isJudgedGate reports whether step is a gate judged by a model, the kind
+// [workflow.gate] bounds.
+func (w *Workflow) isJudgedGate(step *Step) bool {
+	return w.Gate != nil && step.Gate && !step.IsBuiltinGate()
+}
+
+// StepTimeout returns how long step may run: the [workflow.gate] timeout for
+// a model-judged gate when set, otherwise the step's own timeout.
+func (w *Workflow) StepTimeout(step *Step) time.Duration {
+	if w.isJudgedGate(step) && w.Gate.Timeout > 0 {
+		return time.Duration(w.Gate.Timeout)
+	}
+	return step.EffectiveTimeout()
+}
+
+// gateTimedOut reports a gate's attempts-th timeout in a row and waits out
+// the backoff before its next attempt. It returns ErrStepTimedOut once the
+// gate has no attempts left, or the context's error if the wait is
+// interrupted.
+func (r *Runner) gateTimedOut(ctx context.Context, step *Step, attempts int) error {
+	gate := r.workflow.Gate
+	t := GateTimeout{
+		Gate:     step.Name,
+		Attempt:  attempts,
+		Attempts: gate.EffectiveRetries() + 1,
+		Timeout:  r.workflow.StepTimeout(step),
+	}
+	if attempts < t.Attempts {
+		t.Backoff = gate.backoff(attempts)
+	}
+	if r.gateTimeoutCallback != nil {
+		r.gateTimeoutCallback(t)
+	}
+	if t.Backoff == 0 {
+		return fmt.Errorf("%w: gate %q timed out %d times", ErrStepTimedOut, step.Name, attempts)
+	}
+
+	timer := time.NewTimer(t.Backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
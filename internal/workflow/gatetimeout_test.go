@@ -0,0 +1,189 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowReview makes the review gate run until its deadline the first n times.
+func slowReview(n int, deadlines *[]time.Duration) func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+	reviews := 0
+	return func(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+		if stepName != "review" {
+			return &ExecutionResult{StepName: stepName, Output: "done"}, nil
+		}
+		reviews++
+		if deadline, ok := ctx.Deadline(); ok {
+			*deadlines = append(*deadlines, time.Until(deadline))
+		}
+		if reviews <= n {
+			<-ctx.Done()
+			return &ExecutionResult{StepName: stepName, CostUSD: 0.01}, ctx.Err()
+		}
+		if strings.Contains(prompt, "timed out") {
+			return nil, errors.New("gate retry was sent the timeout continuation prompt")
+		}
+		return &ExecutionResult{StepName: stepName, Output: GatePassTag, CostUSD: 0.01}, nil
+	}
+}
+
+func gateTimeoutWorkflow(retries int) *Workflow {
+	return &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement", Timeout: Duration(time.Hour)},
+			{Name: "review", Prompt: "Review", Gate: true, OnFail: "implement", Timeout: Duration(time.Hour)},
+		},
+		Gate: &GateConfig{
+			Timeout: Duration(20 * time.Millisecond),
+			Retries: &retries,
+			Backoff: Duration(time.Millisecond),
+		},
+	}
+}
+
+func TestRunner_Run_RetriesTimedOutGate(t *testing.T) {
+	var deadlines []time.Duration
+	exec := newMockExecutor()
+	exec.customHandler = slowReview(2, &deadlines)
+
+	runner := NewRunner(gateTimeoutWorkflow(2), exec)
+	var timeouts []GateTimeout
+	runner.SetGateTimeoutCallback(func(t GateTimeout) {
+		timeouts = append(timeouts, t)
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps {
+		t.Error("CompletedAllSteps = false, want true")
+	}
+	if got := strings.Join(exec.calls, ","); got != "implement,review,review,review" {
+		t.Errorf("calls = %s, want the gate run three times after one implement", got)
+	}
+	for _, d := range deadlines {
+		if d > time.Second {
+			t.Errorf("gate deadline = %s, want the 20ms gate timeout rather than the step's", d)
+		}
+	}
+	if result.TotalCost < 0.029 {
+		t.Errorf("TotalCost = %.2f, want the timed out attempts counted", result.TotalCost)
+	}
+
+	want := []GateTimeout{
+		{Gate: "review", Attempt: 1, Attempts: 3, Timeout: 20 * time.Millisecond, Backoff: time.Millisecond},
+		{Gate: "review", Attempt: 2, Attempts: 3, Timeout: 20 * time.Millisecond, Backoff: 2 * time.Millisecond},
+	}
+	if len(timeouts) != len(want) {
+		t.Fatalf("timeouts = %+v, want %+v", timeouts, want)
+	}
+	for i := range want {
+		if timeouts[i] != want[i] {
+			t.Errorf("timeouts[%d] = %+v, want %+v", i, timeouts[i], want[i])
+		}
+	}
+}
+
+func TestRunner_Run_GivesUpOnTimedOutGate(t *testing.T) {
+	var deadlines []time.Duration
+	exec := newMockExecutor()
+	exec.customHandler = slowReview(5, &deadlines)
+
+	runner := NewRunner(gateTimeoutWorkflow(1), exec)
+	var last GateTimeout
+	runner.SetGateTimeoutCallback(func(t GateTimeout) { last = t })
+
+	_, err := runner.Run(context.Background())
+	if !errors.Is(err, ErrStepTimedOut) {
+		t.Fatalf("Run() error = %v, want ErrStepTimedOut", err)
+	}
+	if !strings.Contains(err.Error(), `gate "review" timed out 2 times`) {
+		t.Errorf("Run() error = %v, want the gate's timeout count", err)
+	}
+	if last.Attempt != 2 || last.Backoff != 0 {
+		t.Errorf("last timeout = %+v, want attempt 2 without a backoff", last)
+	}
+	if got := last.String(); !strings.HasSuffix(got, "(attempt 2 of 2), giving up") {
+		t.Errorf("String() = %q, want it to say the gate gave up", got)
+	}
+}
+
+func TestRunner_Run_GateBackoffInterrupted(t *testing.T) {
+	var deadlines []time.Duration
+	exec := newMockExecutor()
+	exec.customHandler = slowReview(5, &deadlines)
+
+	w := gateTimeoutWorkflow(1)
+	w.Gate.Backoff = Duration(time.Hour)
+	runner := NewRunner(w, exec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner.SetGateTimeoutCallback(func(GateTimeout) { cancel() })
+
+	if _, err := runner.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWorkflow_StepTimeout(t *testing.T) {
+	implement := Step{Name: "implement", Prompt: "Implement", Timeout: Duration(time.Hour)}
+	review := Step{Name: "review", Prompt: "Review", Gate: true}
+	approve := Step{Name: "approve", Gate: true, GateType: GateTypeHuman}
+
+	plain := &Workflow{}
+	gated := &Workflow{Gate: &GateConfig{Timeout: Duration(2 * time.Minute)}}
+	retriesOnly := &Workflow{Gate: &GateConfig{}}
+
+	tests := []struct {
+		name string
+		w    *Workflow
+		step Step
+		want time.Duration
+	}{
+		{"step without gate config", plain, review, DefaultStepTimeout},
+		{"gate with gate timeout", gated, review, 2 * time.Minute},
+		{"non-gate step keeps its timeout", gated, implement, time.Hour},
+		{"human gate keeps its timeout", gated, approve, DefaultStepTimeout},
+		{"gate config without timeout", retriesOnly, review, DefaultStepTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.w.StepTimeout(&tt.step); got != tt.want {
+				t.Errorf("StepTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflow_Validate_Gate(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name    string
+		gate    *GateConfig
+		wantErr string
+	}{
+		{"no gate config", nil, ""},
+		{"valid", &GateConfig{Timeout: Duration(time.Minute), Backoff: Duration(time.Second)}, ""},
+		{"negative retries", &GateConfig{Retries: &negative}, "gate retries cannot be negative"},
+		{"negative timeout", &GateConfig{Timeout: Duration(-time.Minute)}, "gate timeout cannot be negative"},
+		{"negative backoff", &GateConfig{Backoff: Duration(-time.Second)}, "gate backoff cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Workflow{Steps: []Step{{Name: "review", Prompt: "Review", Gate: true}}, Gate: tt.gate}
+			err := w.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package workflow
+
+import "context"
+
+// OfflineWaitFunc blocks until the network is back after a step failed
+// because it was unreachable (see ExecutionResult.Offline). It returns an
+// error when the run should stop instead, such as after waiting too long.
+type OfflineWaitFunc func(ctx context.Context) error
+
+// SetOfflineWait sets the function that pauses the run while offline. A step
+// that fails for want of a network then runs again once wait returns, without
+// counting as a timeout or a gate retry. Nil disables pausing.
+func (r *Runner) SetOfflineWait(wait OfflineWaitFunc) {
+	r.offlineWait = wait
+}
+
+// runStep executes a step with its own timeout. While offline it waits for
+// the network and runs the step again, counting what the failed attempts
+// spent in result's totals.
+func (r *Runner) runStep(ctx context.Context, step Step, prompt, model string, result *RunResult) (*ExecutionResult, error) {
+	for {
+		stepCtx, stepCancel := context.WithTimeout(ctx, r.workflow.StepTimeout(&step))
+		execResult, err := r.executeStep(stepCtx, step.Name, prompt, model)
+
+		// Cancel the step context to release resources
+		stepCancel()
+
+		if err != nil || r.offlineWait == nil || execResult == nil || !execResult.Offline {
+			return execResult, err
+		}
+		result.TotalCost += execResult.CostUSD
+		result.TotalTokensIn += execResult.TokensIn
+		result.TotalTokensOut += execResult.TokensOut
+		if err := r.offlineWait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
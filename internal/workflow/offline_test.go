@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunner_OfflineStepRunsAgain(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+			{Name: "review", Prompt: "Review", Gate: true},
+		},
+	}
+
+	exec := newMockExecutor()
+	reviews := 0
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		if stepName == "review" {
+			reviews++
+			if reviews == 1 {
+				return &ExecutionResult{StepName: stepName, Output: "API Error: Connection error.", CostUSD: 0.01, Offline: true}, nil
+			}
+			return &ExecutionResult{StepName: stepName, Output: "<gate>PASS</gate>", CostUSD: 0.02}, nil
+		}
+		return &ExecutionResult{StepName: stepName, Output: "Done", CostUSD: 0.02}, nil
+	}
+
+	waits := 0
+	runner := NewRunner(w, exec)
+	runner.SetOfflineWait(func(context.Context) error {
+		waits++
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps {
+		t.Error("CompletedAllSteps = false, want true")
+	}
+	want := []string{"implement", "review", "review"}
+	if len(exec.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", exec.calls, want)
+	}
+	if waits != 1 {
+		t.Errorf("waits = %d, want 1", waits)
+	}
+	// The offline attempt is not a gate failure, but its cost counts
+	if len(result.Steps) != 2 {
+		t.Errorf("got %d step results, want 2", len(result.Steps))
+	}
+	if result.TotalCost < 0.0499 || result.TotalCost > 0.0501 {
+		t.Errorf("TotalCost = %f, want 0.05", result.TotalCost)
+	}
+}
+
+func TestRunner_OfflineWaitErrorStopsRun(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "implement", Prompt: "Implement"}}}
+
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		return &ExecutionResult{StepName: stepName, Offline: true}, nil
+	}
+
+	errGaveUp := errors.New("network unreachable for 1m")
+	runner := NewRunner(w, exec)
+	runner.SetOfflineWait(func(context.Context) error { return errGaveUp })
+
+	_, err := runner.Run(context.Background())
+	if !errors.Is(err, errGaveUp) {
+		t.Errorf("Run() error = %v, want %v", err, errGaveUp)
+	}
+	if len(exec.calls) != 1 {
+		t.Errorf("calls = %v, want a single attempt", exec.calls)
+	}
+}
+
+func TestRunner_OfflineWithoutWaitIsAnOrdinaryResult(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "implement", Prompt: "Implement"}}}
+
+	exec := newMockExecutor()
+	exec.customHandler = func(ctx context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		return &ExecutionResult{StepName: stepName, Offline: true}, nil
+	}
+
+	result, err := NewRunner(w, exec).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps || len(exec.calls) != 1 {
+		t.Errorf("CompletedAllSteps = %v, calls = %v; want the step to complete once", result.CompletedAllSteps, exec.calls)
+	}
+}
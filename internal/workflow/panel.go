@@ -0,0 +1,223 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Panel fans a gate out to several reviewer agents (defined with --agents or
+// [agents]) and passes it only when a quorum of them approves. Orbital counts
+// the verdicts itself; a <gate> tag in the output is ignored.
+type Panel struct {
+	// Agents are the reviewer agent names, e.g. "security-reviewer".
+	Agents []string `toml:"agents" json:"agents"`
+
+	// Quorum is the number of approvals needed to pass (default: a majority).
+	Quorum int `toml:"quorum" json:"quorum,omitempty"`
+}
+
+// EffectiveQuorum returns the configured quorum or a simple majority.
+func (p *Panel) EffectiveQuorum() int {
+	if p.Quorum > 0 {
+		return p.Quorum
+	}
+	return len(p.Agents)/2 + 1
+}
+
+// Validate checks the panel has agents and an achievable quorum.
+func (p *Panel) Validate() error {
+	if len(p.Agents) == 0 {
+		return errors.New("panel needs at least one agent")
+	}
+	seen := make(map[string]bool, len(p.Agents))
+	for _, agent := range p.Agents {
+		if strings.TrimSpace(agent) == "" {
+			return errors.New("panel agent name cannot be empty")
+		}
+		if seen[agent] {
+			return fmt.Errorf("panel agent %q is listed twice", agent)
+		}
+		seen[agent] = true
+	}
+	if p.Quorum < 0 || p.Quorum > len(p.Agents) {
+		return fmt.Errorf("panel quorum must be between 1 and %d, got %d", len(p.Agents), p.Quorum)
+	}
+	return nil
+}
+
+// Verdict values reviewers report.
+const (
+	VerdictApprove = "APPROVE"
+	VerdictReject  = "REJECT"
+)
+
+// PanelPrompt is appended to a panel gate's prompt. The verbs are replaced
+// with the list of agents and the quorum.
+const PanelPrompt = `
+
+---
+REVIEW PANEL: Spawn the following reviewer agents in parallel using the Task
+tool. Each reviews the SAME changes independently:
+%s
+
+Wait for ALL agents to complete. Record each agent's findings in the notes
+file, then output one verdict per agent on its own line, APPROVE if the agent
+found no blocking issues and REJECT otherwise:
+
+<verdict agent="NAME">APPROVE</verdict>
+<verdict agent="NAME">REJECT</verdict>
+
+Report every agent's verdict faithfully. Orbital passes the gate when at
+least %d agent(s) approve; do not output a <gate> tag yourself.
+---
+`
+
+// verdictPattern matches <verdict agent="name">APPROVE|REJECT</verdict>.
+var verdictPattern = regexp.MustCompile(`<verdict\s+agent="([^"]+)"\s*>\s*(\w+)\s*</verdict>`)
+
+// ParseVerdicts returns each agent's verdict in output, true for approval.
+// When an agent reports more than once the last verdict wins; unrecognised
+// verdict values are ignored.
+func ParseVerdicts(output string) map[string]bool {
+	verdicts := make(map[string]bool)
+	for _, m := range verdictPattern.FindAllStringSubmatch(output, -1) {
+		switch strings.ToUpper(m[2]) {
+		case VerdictApprove:
+			verdicts[m[1]] = true
+		case VerdictReject:
+			verdicts[m[1]] = false
+		}
+	}
+	return verdicts
+}
+
+// PanelResult is the outcome of a panel gate.
+type PanelResult struct {
+	// Agents are the panel's agents in configured order.
+	Agents []string
+
+	// Verdicts holds the verdict of each agent that reported, true for approval.
+	Verdicts map[string]bool
+
+	// Quorum is the number of approvals needed to pass.
+	Quorum int
+}
+
+// Approvals returns the number of panel agents that approved.
+func (r *PanelResult) Approvals() int {
+	n := 0
+	for _, agent := range r.Agents {
+		if r.Verdicts[agent] {
+			n++
+		}
+	}
+	return n
+}
+
+// String summarises the verdicts, e.g.
+// "2/3 approved, quorum 2 (design-reviewer approve, logic-reviewer approve, security-reviewer reject)".
+func (r *PanelResult) String() string {
+	agents := append([]string(nil), r.Agents...)
+	sort.Strings(agents)
+	parts := make([]string, len(agents))
+	for i, agent := range agents {
+		verdict, ok := r.Verdicts[agent]
+		switch {
+		case !ok:
+			parts[i] = agent + " no verdict"
+		case verdict:
+			parts[i] = agent + " approve"
+		default:
+			parts[i] = agent + " reject"
+		}
+	}
+	return fmt.Sprintf("%d/%d approved, quorum %d (%s)", r.Approvals(), len(r.Agents), r.Quorum, strings.Join(parts, ", "))
+}
+
+// CheckPanel counts the panel's verdicts in output. The gate passes when the
+// quorum approves, fails when any agent reported but the quorum was not met,
+// and is not found when no panel agent reported at all. Verdicts from agents
+// outside the panel are ignored.
+func CheckPanel(output string, p *Panel) (GateResult, *PanelResult) {
+	all := ParseVerdicts(output)
+	result := &PanelResult{
+		Agents:   p.Agents,
+		Verdicts: make(map[string]bool),
+		Quorum:   p.EffectiveQuorum(),
+	}
+	for _, agent := range p.Agents {
+		if verdict, ok := all[agent]; ok {
+			result.Verdicts[agent] = verdict
+		}
+	}
+
+	switch {
+	case result.Approvals() >= result.Quorum:
+		return GatePassed, result
+	case len(result.Verdicts) > 0:
+		return GateFailed, result
+	default:
+		return GateNotFound, result
+	}
+}
+
+// panelPromptText returns the panel instructions appended to a gate prompt.
+func panelPromptText(p *Panel) string {
+	return fmt.Sprintf(PanelPrompt, formatAgentList(p.Agents), p.EffectiveQuorum())
+}
+
+// formatAgentList formats agent names as a numbered list.
+func formatAgentList(agents []string) string {
+	var b strings.Builder
+	for i, agent := range agents {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d. %s", i+1, agent)
+	}
+	return b.String()
+}
+
+// ApplyPanel overrides the agents and quorum of every panel gate in the
+// workflow with the non-zero fields of p, so a preset's panel can be
+// reconfigured without redefining its steps.
+func (w *Workflow) ApplyPanel(p *Panel) {
+	if p == nil {
+		return
+	}
+	for i := range w.Steps {
+		step := &w.Steps[i]
+		if step.Panel == nil {
+			continue
+		}
+		panel := *step.Panel
+		if len(p.Agents) > 0 {
+			panel.Agents = append([]string(nil), p.Agents...)
+		}
+		if p.Quorum > 0 {
+			panel.Quorum = p.Quorum
+		}
+		step.Panel = &panel
+	}
+}
+
+// PanelAgents returns the agent names used by the workflow's panel gates.
+func (w *Workflow) PanelAgents() []string {
+	var agents []string
+	seen := make(map[string]bool)
+	for _, step := range w.Steps {
+		if step.Panel == nil {
+			continue
+		}
+		for _, agent := range step.Panel.Agents {
+			if !seen[agent] {
+				seen[agent] = true
+				agents = append(agents, agent)
+			}
+		}
+	}
+	return agents
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVerdicts(t *testing.T) {
+	output := `<verdict agent="security-reviewer">APPROVE</verdict>
+<verdict agent="design-reviewer">reject</verdict>
+<verdict agent="logic-reviewer">MAYBE</verdict>
+<verdict agent="security-reviewer">REJECT</verdict>`
+
+	want := map[string]bool{"security-reviewer": false, "design-reviewer": false}
+	if got := ParseVerdicts(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseVerdicts() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckPanel(t *testing.T) {
+	panel := &Panel{Agents: []string{"a", "b", "c"}, Quorum: 2}
+	verdict := func(agent, v string) string {
+		return `<verdict agent="` + agent + `">` + v + "</verdict>\n"
+	}
+
+	tests := []struct {
+		name      string
+		output    string
+		want      GateResult
+		approvals int
+	}{
+		{name: "quorum reached", output: verdict("a", "APPROVE") + verdict("b", "APPROVE") + verdict("c", "REJECT"), want: GatePassed, approvals: 2},
+		{name: "quorum missed", output: verdict("a", "APPROVE") + verdict("b", "REJECT") + verdict("c", "REJECT"), want: GateFailed, approvals: 1},
+		{name: "missing verdicts count against", output: verdict("a", "APPROVE"), want: GateFailed, approvals: 1},
+		{name: "no verdicts", output: "<gate>PASS</gate>", want: GateNotFound},
+		{name: "outsiders ignored", output: verdict("x", "APPROVE") + verdict("y", "APPROVE"), want: GateNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, result := CheckPanel(tt.output, panel)
+			if got != tt.want {
+				t.Errorf("CheckPanel() = %v, want %v", got, tt.want)
+			}
+			if result.Approvals() != tt.approvals {
+				t.Errorf("Approvals() = %d, want %d", result.Approvals(), tt.approvals)
+			}
+		})
+	}
+}
+
+func TestPanel_EffectiveQuorum(t *testing.T) {
+	tests := []struct {
+		panel Panel
+		want  int
+	}{
+		{Panel{Agents: []string{"a", "b", "c"}}, 2},
+		{Panel{Agents: []string{"a", "b"}}, 2},
+		{Panel{Agents: []string{"a", "b", "c"}, Quorum: 3}, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.panel.EffectiveQuorum(); got != tt.want {
+			t.Errorf("EffectiveQuorum(%+v) = %d, want %d", tt.panel, got, tt.want)
+		}
+	}
+}
+
+func TestPanel_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		panel   Panel
+		wantErr string
+	}{
+		{name: "valid", panel: Panel{Agents: []string{"a", "b"}, Quorum: 1}},
+		{name: "no agents", panel: Panel{}, wantErr: "at least one agent"},
+		{name: "empty name", panel: Panel{Agents: []string{"a", " "}}, wantErr: "cannot be empty"},
+		{name: "duplicate", panel: Panel{Agents: []string{"a", "a"}}, wantErr: "listed twice"},
+		{name: "quorum too high", panel: Panel{Agents: []string{"a", "b"}, Quorum: 3}, wantErr: "between 1 and 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.panel.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflow_Validate_PanelRequiresGate(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "review", Prompt: "p", Panel: &Panel{Agents: []string{"a"}}}}}
+	if err := w.Validate(); err == nil || !strings.Contains(err.Error(), "panel requires gate") {
+		t.Errorf("Validate() error = %v, want panel requires gate", err)
+	}
+}
+
+func TestWorkflow_ApplyPanel(t *testing.T) {
+	w := panelPreset()
+	w.ApplyPanel(&Panel{Quorum: 3})
+
+	panel := w.Steps[1].Panel
+	if panel.Quorum != 3 || len(panel.Agents) != 3 {
+		t.Errorf("panel = %+v, want preset agents with quorum 3", panel)
+	}
+	if fresh := panelPreset(); fresh.Steps[1].Panel.Quorum != 2 {
+		t.Error("ApplyPanel changed the preset definition")
+	}
+
+	w.ApplyPanel(&Panel{Agents: []string{"a", "b"}})
+	if got := w.PanelAgents(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("PanelAgents() = %v, want [a b]", got)
+	}
+}
+
+func TestPanelResult_String(t *testing.T) {
+	r := &PanelResult{Agents: []string{"b", "a", "c"}, Verdicts: map[string]bool{"a": true, "b": false}, Quorum: 2}
+	want := "1/3 approved, quorum 2 (a approve, b reject, c no verdict)"
+	if got := r.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRunner_Run_PanelGate(t *testing.T) {
+	w := &Workflow{
+		Steps: []Step{
+			{Name: "implement", Prompt: "Implement"},
+			{Name: "review", Prompt: "Review", Gate: true, OnFail: "implement", Panel: &Panel{Agents: []string{"a", "b", "c"}}},
+		},
+	}
+
+	reviews := 0
+	var reviewPrompt string
+	exec := newMockExecutor()
+	exec.customHandler = func(_ context.Context, stepName, prompt string) (*ExecutionResult, error) {
+		if stepName != "review" {
+			return &ExecutionResult{StepName: stepName, Output: "done"}, nil
+		}
+		reviews++
+		reviewPrompt = prompt
+		// A <gate>PASS</gate> alone must not pass a panel
+		output := `<verdict agent="a">APPROVE</verdict><verdict agent="b">REJECT</verdict><gate>PASS</gate>`
+		if reviews > 1 {
+			output = `<verdict agent="a">APPROVE</verdict><verdict agent="b">APPROVE</verdict>`
+		}
+		return &ExecutionResult{StepName: stepName, Output: output}, nil
+	}
+
+	var panels []*PanelResult
+	runner := NewRunner(w, exec)
+	runner.SetCallback(func(info StepInfo, _ *ExecutionResult, _ GateResult) error {
+		if info.Panel != nil {
+			panels = append(panels, info.Panel)
+		}
+		return nil
+	})
+
+	result, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.CompletedAllSteps || reviews != 2 {
+		t.Errorf("completed = %v after %d reviews, want completion after 2", result.CompletedAllSteps, reviews)
+	}
+	if !strings.Contains(reviewPrompt, "REVIEW PANEL") || !strings.Contains(reviewPrompt, "3. c") || !strings.Contains(reviewPrompt, "least 2 agent(s) approve") {
+		t.Errorf("review prompt missing panel instructions:\n%s", reviewPrompt)
+	}
+	if len(panels) != 2 || panels[0].Approvals() != 1 || panels[1].Approvals() != 2 {
+		t.Errorf("panel results = %v", panels)
+	}
+}
@@ -21,6 +21,9 @@ const (
 	// PresetAutonomous takes a self-directed approach: pick highest-leverage task,
 	// complete it, document, commit, and exit for the next iteration.
 	PresetAutonomous PresetName = "autonomous"
+
+	// PresetPanel adds a review gate decided by a quorum of reviewer agents.
+	PresetPanel PresetName = "panel"
 )
 
 // DefaultPreset is the preset used when none is specified.
@@ -28,7 +31,7 @@ const DefaultPreset = PresetSpecDriven
 
 // ValidPresets returns all valid preset names.
 func ValidPresets() []PresetName {
-	return []PresetName{PresetFast, PresetSpecDriven, PresetReviewed, PresetTDD, PresetAutonomous}
+	return []PresetName{PresetFast, PresetSpecDriven, PresetReviewed, PresetTDD, PresetAutonomous, PresetPanel}
 }
 
 // IsValidPreset returns true if the given name is a valid preset.
@@ -55,6 +58,8 @@ func GetPreset(name PresetName) (*Workflow, error) {
 		return tddPreset(), nil
 	case PresetAutonomous:
 		return autonomousPreset(), nil
+	case PresetPanel:
+		return panelPreset(), nil
 	default:
 		return nil, fmt.Errorf("unknown preset: %s", name)
 	}
@@ -282,6 +287,55 @@ EXECUTION:
 	}
 }
 
+// panelReviewPrompt is the review prompt of the panel preset. The reviewer
+// agents and the verdict format are appended by the runner (see PanelPrompt).
+const panelReviewPrompt = `You are chairing a code review panel. Your job is to collect independent
+verdicts, not to review the code yourself.
+
+First, identify all files changed in this iteration using git diff, and pass
+the list to every reviewer.
+
+An agent approves only if its output ends with _CLEAR; any _ISSUES_FOUND is a
+rejection.
+
+Aggregate the findings in the notes file with this structure:
+## Panel Review - Iteration N
+
+### <agent name>
+[findings or "No issues"]
+
+### Verdicts
+[one line per agent: APPROVE or REJECT with a one-sentence reason]`
+
+// panelPreset returns the panel workflow: implement, then a review gate that
+// fans out to three reviewer agents and passes when two of them approve.
+func panelPreset() *Workflow {
+	return &Workflow{
+		Name:   string(PresetPanel),
+		Preset: string(PresetPanel),
+		Steps: []Step{
+			{
+				Name: "implement",
+				Prompt: `Read the notes file for any review panel feedback to address.
+If there is feedback, address it first before continuing.
+Then continue implementing the requirements in {{files}}.
+Focus on the next incomplete item.
+Do not output completion promise yet.`,
+			},
+			{
+				Name:   "review",
+				Prompt: panelReviewPrompt,
+				Gate:   true,
+				OnFail: "implement",
+				Panel: &Panel{
+					Agents: []string{"security-reviewer", "design-reviewer", "logic-reviewer"},
+					Quorum: 2,
+				},
+			},
+		},
+	}
+}
+
 // PresetDescriptions returns brief descriptions for each preset.
 func PresetDescriptions() map[PresetName]string {
 	return map[PresetName]string{
@@ -290,5 +344,6 @@ func PresetDescriptions() map[PresetName]string {
 		PresetReviewed:   "Implement with review gate before completion",
 		PresetTDD:        "Red-green-refactor cycle with review gate",
 		PresetAutonomous: "Self-directed task selection with review gate",
+		PresetPanel:      "Implement with a review panel of agents that must reach a quorum",
 	}
 }
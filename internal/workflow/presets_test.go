@@ -41,6 +41,7 @@ func TestGetPreset(t *testing.T) {
 		{PresetReviewed, 2, false},
 		{PresetTDD, 4, false},
 		{PresetAutonomous, 3, false},
+		{PresetPanel, 2, false},
 		{"invalid", 0, true},
 	}
 
@@ -181,8 +182,8 @@ func TestTDDPreset(t *testing.T) {
 func TestValidPresets(t *testing.T) {
 	presets := ValidPresets()
 
-	if len(presets) != 5 {
-		t.Errorf("ValidPresets() returned %d presets, want 5", len(presets))
+	if len(presets) != 6 {
+		t.Errorf("ValidPresets() returned %d presets, want 6", len(presets))
 	}
 
 	expected := map[PresetName]bool{
@@ -191,6 +192,7 @@ func TestValidPresets(t *testing.T) {
 		PresetReviewed:   true,
 		PresetTDD:        true,
 		PresetAutonomous: true,
+		PresetPanel:      true,
 	}
 
 	for _, p := range presets {
@@ -294,3 +296,15 @@ func TestAutonomousPreset(t *testing.T) {
 		t.Errorf("autonomous preset validation failed: %v", err)
 	}
 }
+
+func TestPanelPreset(t *testing.T) {
+	w := panelPreset()
+
+	review := w.Steps[len(w.Steps)-1]
+	if !review.Gate || review.OnFail != "implement" {
+		t.Errorf("review step = %+v, want a gate looping back to implement", review)
+	}
+	if review.Panel == nil || len(review.Panel.Agents) != 3 || review.Panel.EffectiveQuorum() != 2 {
+		t.Errorf("review panel = %+v, want three agents with a quorum of two", review.Panel)
+	}
+}
@@ -4,6 +4,8 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -44,12 +46,64 @@ type Step struct {
 	// Gate marks this step as a quality gate that must pass before continuing.
 	Gate bool `toml:"gate" json:"gate,omitempty"`
 
+	// GateType selects who judges the gate: "model" (default), where Claude
+	// outputs a <gate> tag, "human", where the run pauses until a person
+	// approves or rejects it (see Runner.SetApprover), or "diff-empty-fail",
+	// which fails when a step before it changed nothing in the repository
+	// (see Runner.SetTreeState). Human and diff gates are not sent to
+	// Claude; a human gate's prompt, if any, tells the approver what to check.
+	GateType string `toml:"gate_type" json:"gate_type,omitempty"`
+
+	// ApprovalTimeout is how long a human gate waits for a decision
+	// (default: 1 hour).
+	ApprovalTimeout Duration `toml:"approval_timeout" json:"approval_timeout,omitempty"`
+
+	// OnApprovalTimeout is what a human gate does when nobody decides in
+	// time: "abort" the run (default), "fail" the gate or "pass" it.
+	OnApprovalTimeout string `toml:"on_approval_timeout" json:"on_approval_timeout,omitempty"`
+
+	// AllowUnchanged names the steps a diff-empty-fail gate lets change
+	// nothing in the repository, such as a plan step.
+	AllowUnchanged []string `toml:"allow_unchanged" json:"allow_unchanged,omitempty"`
+
 	// OnFail specifies the step name to return to if this gate fails.
 	OnFail string `toml:"on_fail" json:"on_fail,omitempty"`
 
 	// Deferred marks this step to be skipped during normal execution.
 	// Deferred steps only run when reached via a gate's OnFail jump.
 	Deferred bool `toml:"deferred" json:"deferred,omitempty"`
+
+	// Rubric is a markdown file whose criteria are injected into this gate's
+	// prompt. The judge is asked to report its scores as JSON (see ParseScores).
+	// Relative paths are resolved against the runner's working directory.
+	Rubric string `toml:"rubric" json:"rubric,omitempty"`
+
+	// Artifacts are files this step must create or update, e.g.
+	// "docs/plans/{{spec_name}}-plan.md". The runner checks them after the
+	// step, retries the step once if one was not written, and passes their
+	// paths into later step prompts. Relative paths are resolved against the
+	// runner's working directory.
+	Artifacts []string `toml:"artifacts" json:"artifacts,omitempty"`
+
+	// Context selects the context files listed in this step's prompt, as
+	// paths or glob patterns ("docs/architecture.md", "src/**") relative to
+	// the runner's working directory. When unset the step gets all of the
+	// run's context files; an empty list gives it none.
+	Context []string `toml:"context" json:"context,omitempty"`
+
+	// Panel turns this gate into a review panel: the prompt asks for one
+	// verdict per agent and the gate passes when the panel's quorum approves.
+	Panel *Panel `toml:"panel" json:"panel,omitempty"`
+
+	// Dry skips sending this step to Claude: the runner reports the prompt it
+	// would have sent and, for a gate, passes it. Useful while iterating on
+	// a workflow definition.
+	Dry bool `toml:"dry" json:"dry,omitempty"`
+
+	// Cacheable lets the runner reuse this step's result when it is re-run
+	// with an identical prompt on the same model, e.g. a plan step after a
+	// crash (see StepCache). Only results of steps that succeeded are cached.
+	Cacheable bool `toml:"cacheable" json:"cacheable,omitempty"`
 }
 
 // EffectiveTimeout returns the step's timeout or the default if not set.
@@ -73,6 +127,19 @@ type Workflow struct {
 
 	// MaxGateRetries is the maximum number of times a gate can fail before aborting (default: 3).
 	MaxGateRetries int `toml:"max_gate_retries" json:"max_gate_retries,omitempty"`
+
+	// EscalateModel is a stronger model the steps retrying a failing gate
+	// switch to once the gate has failed EscalateAfter times. They return to
+	// the run's model when the gate passes. Empty disables escalation.
+	EscalateModel string `toml:"escalate_model" json:"escalate_model,omitempty"`
+
+	// EscalateAfter is the number of gate failures before escalating
+	// (default: 2, lowered to stay below MaxGateRetries).
+	EscalateAfter int `toml:"escalate_after" json:"escalate_after,omitempty"`
+
+	// Gate bounds model-judged gates with their own timeout and retries,
+	// independently of the step timeouts. Nil leaves gates to their steps'.
+	Gate *GateConfig `toml:"gate" json:"gate,omitempty"`
 }
 
 // Validate checks that the workflow configuration is valid.
@@ -86,7 +153,7 @@ func (w *Workflow) Validate() error {
 		if step.Name == "" {
 			return fmt.Errorf("step %d: name is required", i+1)
 		}
-		if step.Prompt == "" {
+		if step.Prompt == "" && !step.IsBuiltinGate() {
 			return fmt.Errorf("step %d (%s): prompt is required", i+1, step.Name)
 		}
 		if stepNames[step.Name] {
@@ -97,15 +164,54 @@ func (w *Workflow) Validate() error {
 		if step.OnFail != "" && !step.Gate {
 			return fmt.Errorf("step %d (%s): on_fail requires gate = true", i+1, step.Name)
 		}
+		if step.Rubric != "" && !step.Gate {
+			return fmt.Errorf("step %d (%s): rubric requires gate = true", i+1, step.Name)
+		}
+		if step.Panel != nil {
+			if !step.Gate {
+				return fmt.Errorf("step %d (%s): panel requires gate = true", i+1, step.Name)
+			}
+			if err := step.Panel.Validate(); err != nil {
+				return fmt.Errorf("step %d (%s): %w", i+1, step.Name, err)
+			}
+		}
+		if err := step.validateGateType(); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.Name, err)
+		}
+		for _, pattern := range step.Context {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("step %d (%s): context pattern cannot be empty", i+1, step.Name)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("step %d (%s): invalid context pattern %q: %w", i+1, step.Name, pattern, err)
+			}
+		}
+		for _, artifact := range step.Artifacts {
+			if strings.TrimSpace(artifact) == "" {
+				return fmt.Errorf("step %d (%s): artifact path cannot be empty", i+1, step.Name)
+			}
+		}
 	}
 
-	// Validate on_fail references existing steps
+	if err := w.validateEscalation(); err != nil {
+		return err
+	}
+	if err := w.Gate.validate(); err != nil {
+		return err
+	}
+
+	// Validate on_fail and allow_unchanged reference existing steps
 	for i, step := range w.Steps {
 		if step.OnFail != "" {
 			if !stepNames[step.OnFail] {
 				return fmt.Errorf("step %d (%s): on_fail references unknown step %q", i+1, step.Name, step.OnFail)
 			}
 		}
+		for _, name := range step.AllowUnchanged {
+			if !stepNames[name] {
+				return fmt.Errorf("step %d (%s): allow_unchanged references unknown step %q", i+1, step.Name, name)
+			}
+		}
 	}
 
 	// Validate deferred steps are reachable via OnFail
@@ -155,6 +261,29 @@ func (w *Workflow) HasGates() bool {
 	return false
 }
 
+// HasCacheableSteps returns true if any step in the workflow is cacheable.
+func (w *Workflow) HasCacheableSteps() bool {
+	for _, step := range w.Steps {
+		if step.Cacheable {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDrySteps marks the named steps as dry (see Step.Dry). It returns an
+// error naming the first step not in the workflow.
+func (w *Workflow) SetDrySteps(names []string) error {
+	for _, name := range names {
+		i := w.GetStepIndex(name)
+		if i < 0 {
+			return fmt.Errorf("unknown step %q", name)
+		}
+		w.Steps[i].Dry = true
+	}
+	return nil
+}
+
 // SetAllStepTimeouts sets the timeout for all steps in the workflow.
 // This is used when the --timeout CLI flag is provided to override step timeouts.
 func (w *Workflow) SetAllStepTimeouts(timeout time.Duration) {
@@ -79,6 +79,24 @@ func TestWorkflow_Validate(t *testing.T) {
 			},
 			wantErr: "step 1 (implement): on_fail requires gate = true",
 		},
+		{
+			name: "rubric without gate",
+			workflow: Workflow{
+				Steps: []Step{
+					{Name: "review", Prompt: "Review", Rubric: "docs/rubrics/review.md"},
+				},
+			},
+			wantErr: "step 1 (review): rubric requires gate = true",
+		},
+		{
+			name: "empty artifact path",
+			workflow: Workflow{
+				Steps: []Step{
+					{Name: "plan", Prompt: "Plan", Artifacts: []string{" "}},
+				},
+			},
+			wantErr: "step 1 (plan): artifact path cannot be empty",
+		},
 		{
 			name: "on_fail references unknown step",
 			workflow: Workflow{
@@ -289,3 +307,16 @@ func TestWorkflow_SetAllStepTimeouts(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkflow_SetDrySteps(t *testing.T) {
+	w := &Workflow{Steps: []Step{{Name: "implement"}, {Name: "review"}}}
+	if err := w.SetDrySteps([]string{"review"}); err != nil {
+		t.Fatalf("SetDrySteps() error = %v", err)
+	}
+	if w.Steps[0].Dry || !w.Steps[1].Dry {
+		t.Errorf("steps = %+v, want only review dry", w.Steps)
+	}
+	if err := w.SetDrySteps([]string{"deploy"}); err == nil || err.Error() != `unknown step "deploy"` {
+		t.Errorf("SetDrySteps(deploy) error = %v", err)
+	}
+}
@@ -0,0 +1,439 @@
+// Package orbital embeds the orbital autonomous loop in other Go programs.
+//
+// Run drives a workflow over one or more spec files until the work is
+// verified complete, the iteration limit is reached, or the budget runs out:
+//
+//	result, err := orbital.Run(ctx, orbital.Options{
+//		SpecFiles:  []string{"docs/plans/auth.md"},
+//		WorkingDir: ".",
+//		MaxBudget:  10,
+//	})
+//
+// By default each step runs the Claude Code CLI and completion is verified by
+// the checker model. Set Options.Executor and Options.Verifier to replace
+// either. Only the identifiers in this package are covered by compatibility
+// guarantees; the internal packages they alias may change.
+package orbital
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/flashingpumpkin/orbital/internal/config"
+	"github.com/flashingpumpkin/orbital/internal/executor"
+	"github.com/flashingpumpkin/orbital/internal/loop"
+	"github.com/flashingpumpkin/orbital/internal/spec"
+	"github.com/flashingpumpkin/orbital/internal/vcs"
+	"github.com/flashingpumpkin/orbital/internal/workflow"
+)
+
+// Defaults applied to zero-valued Options fields. They match the CLI defaults.
+const (
+	DefaultMaxIterations     = 50
+	DefaultModel             = "opus"
+	DefaultCheckerModel      = "haiku"
+	DefaultMaxBudget         = 100.00
+	DefaultIterationTimeout  = 5 * time.Minute
+	DefaultCompletionPromise = "<promise>COMPLETE</promise>"
+)
+
+// Errors returned by Run. Match them with errors.Is.
+var (
+	// ErrBudgetExceeded is returned when spend reaches Options.MaxBudget.
+	ErrBudgetExceeded = loop.ErrBudgetExceeded
+	// ErrMaxIterationsReached is returned when the work is not verified
+	// within Options.MaxIterations.
+	ErrMaxIterationsReached = loop.ErrMaxIterationsReached
+	// ErrNoProgress is returned when iterations keep repeating the same output
+	// without changing the working tree (see Options.NoProgressLimit).
+	ErrNoProgress = loop.ErrNoProgress
+)
+
+type (
+	// Workflow is an ordered list of steps, optionally with gates.
+	Workflow = workflow.Workflow
+	// Step is a single workflow step.
+	Step = workflow.Step
+	// StepExecutor runs a single step's prompt.
+	StepExecutor = workflow.StepExecutor
+	// ModelStepExecutor is a StepExecutor that can run a step on another
+	// model, which a workflow's EscalateModel needs.
+	ModelStepExecutor = workflow.ModelStepExecutor
+	// ExecutionResult is the outcome of one step execution.
+	ExecutionResult = workflow.ExecutionResult
+	// Verifier decides whether the spec is complete.
+	Verifier = loop.Verifier
+	// VerificationResult is the outcome of a verification.
+	VerificationResult = loop.VerificationResult
+)
+
+// Preset returns a copy of a built-in workflow: fast, spec-driven, reviewed,
+// tdd, or autonomous.
+func Preset(name string) (*Workflow, error) {
+	return workflow.GetPreset(workflow.PresetName(name))
+}
+
+// Options configures Run. Only SpecFiles is required.
+type Options struct {
+	// SpecFiles lists the spec file first, followed by any context files.
+	SpecFiles []string
+
+	// NotesFile is an optional file for cross-iteration notes.
+	NotesFile string
+
+	// WorkingDir is the directory the model works in (default: ".").
+	WorkingDir string
+
+	// Workflow is the workflow to run (default: the spec-driven preset).
+	Workflow *Workflow
+
+	// Model and CheckerModel select the execution and verification models.
+	Model        string
+	CheckerModel string
+
+	// MaxIterations bounds the number of workflow iterations.
+	MaxIterations int
+
+	// MaxBudget is the maximum spend in USD.
+	MaxBudget float64
+
+	// IterationTimeout bounds each step that does not set its own timeout.
+	IterationTimeout time.Duration
+
+	// MaxTurns limits agentic turns per step (0 means unlimited).
+	MaxTurns int
+
+	// CompletionPromise is the string the model prints when it believes the
+	// work is done.
+	CompletionPromise string
+
+	// SystemPrompt replaces the default system prompt.
+	SystemPrompt string
+
+	// DangerouslySkipPermissions lets the model run commands without
+	// permission prompts. Only enable it in trusted environments.
+	DangerouslySkipPermissions bool
+
+	// NoProgressLimit is the number of consecutive iterations with the same
+	// output and working tree tolerated before NoProgressAction applies
+	// (default: 3).
+	NoProgressLimit int
+
+	// NoProgressAction is "escalate" (default), "abort", or "off".
+	NoProgressAction string
+
+	// InjectFile is watched for instructions that are appended once to the
+	// next iteration's prompts, then removed. Empty disables injection.
+	InjectFile string
+
+	// Stream receives the raw stream-json output of the default executor.
+	Stream io.Writer
+
+	// Executor replaces the default Claude Code CLI step executor.
+	Executor StepExecutor
+
+	// Verifier replaces the default checker-model verification.
+	Verifier Verifier
+
+	// OnStep is called after every step completes.
+	OnStep func(StepEvent)
+}
+
+// StepEvent describes a completed step.
+type StepEvent struct {
+	Iteration int
+	Step      string
+	Position  int
+	Total     int
+	// Gate is "PASS" or "FAIL" for gate steps and empty otherwise.
+	Gate      string
+	Output    string
+	Cost      float64
+	TokensIn  int
+	TokensOut int
+	// Scores are the judge's rubric scores for gates with a rubric.
+	Scores map[string]float64
+}
+
+// Result summarises a run. It is returned alongside any error.
+type Result struct {
+	Iterations int
+	Completed  bool
+	TotalCost  float64
+	TokensIn   int
+	TokensOut  int
+	LastOutput string
+}
+
+// Run executes the workflow over opts.SpecFiles until verification passes or
+// a limit is reached. Runs share no state, so Run may be called concurrently.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	opts = withDefaults(opts)
+
+	sp, err := spec.Validate(opts.SpecFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate files: %w", err)
+	}
+
+	wf := opts.Workflow
+	if wf == nil {
+		if wf, err = workflow.GetPreset(workflow.PresetSpecDriven); err != nil {
+			return nil, err
+		}
+	}
+	if err := wf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid workflow: %w", err)
+	}
+	wf = withStepTimeouts(wf, opts.IterationTimeout)
+
+	cfg := &config.Config{
+		MaxIterations:              opts.MaxIterations,
+		CompletionPromise:          opts.CompletionPromise,
+		Model:                      opts.Model,
+		CheckerModel:               opts.CheckerModel,
+		MaxBudget:                  opts.MaxBudget,
+		WorkingDir:                 opts.WorkingDir,
+		IterationTimeout:           opts.IterationTimeout,
+		MaxTurns:                   opts.MaxTurns,
+		SystemPrompt:               opts.SystemPrompt,
+		DangerouslySkipPermissions: opts.DangerouslySkipPermissions,
+		MaxOutputSize:              config.DefaultMaxOutputSize,
+		InjectFile:                 opts.InjectFile,
+	}
+	if cfg.SystemPrompt == "" {
+		cfg.SystemPrompt = spec.BuildSystemPromptFor(opts.CompletionPromise, opts.NotesFile)
+	}
+
+	stepExec := opts.Executor
+	if stepExec == nil {
+		exec := executor.New(cfg)
+		if opts.Stream != nil {
+			exec.SetStreamWriter(opts.Stream)
+		}
+		stepExec = &cliStepExecutor{exec: exec}
+	}
+
+	verifier := opts.Verifier
+	if verifier == nil {
+		verifier = loop.NewModelVerifier(cfg.CheckerModel, cfg.MaxBudget)
+	}
+
+	guard, err := loop.NewProgressGuard(opts.NoProgressLimit, opts.NoProgressAction)
+	if err != nil {
+		return nil, err
+	}
+
+	return runLoop(ctx, cfg, wf, stepExec, verifier, guard, sp.FilePaths, opts)
+}
+
+// withDefaults fills zero-valued options.
+func withDefaults(opts Options) Options {
+	if opts.WorkingDir == "" {
+		opts.WorkingDir = "."
+	}
+	if opts.Model == "" {
+		opts.Model = DefaultModel
+	}
+	if opts.CheckerModel == "" {
+		opts.CheckerModel = DefaultCheckerModel
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = DefaultMaxIterations
+	}
+	if opts.MaxBudget <= 0 {
+		opts.MaxBudget = DefaultMaxBudget
+	}
+	if opts.IterationTimeout <= 0 {
+		opts.IterationTimeout = DefaultIterationTimeout
+	}
+	if opts.CompletionPromise == "" {
+		opts.CompletionPromise = DefaultCompletionPromise
+	}
+	return opts
+}
+
+// withStepTimeouts returns a copy of wf in which steps without their own
+// timeout use timeout. The caller's workflow is not modified.
+func withStepTimeouts(wf *Workflow, timeout time.Duration) *Workflow {
+	clone := *wf
+	clone.Steps = append([]Step(nil), wf.Steps...)
+	for i := range clone.Steps {
+		if clone.Steps[i].Timeout == 0 {
+			clone.Steps[i].Timeout = workflow.Duration(timeout)
+		}
+	}
+	return &clone
+}
+
+// runLoop is the outer iteration loop: run the workflow, and when every step
+// completes or the promise appears, verify; retry until verified or a limit
+// is hit. Step timeouts and exhausted gate retries move on to the next
+// iteration rather than failing the run.
+func runLoop(ctx context.Context, cfg *config.Config, wf *Workflow, stepExec StepExecutor, verifier Verifier, guard *loop.ProgressGuard, specFiles []string, opts Options) (*Result, error) {
+	state := &loop.LoopState{StartTime: time.Now()}
+	stagnation := loop.ProgressOK
+	repo := vcs.Detect(ctx, cfg.WorkingDir)
+	var injector *loop.Injector
+	if cfg.InjectFile != "" {
+		injector = loop.NewInjector(cfg.InjectFile)
+	}
+
+	runner := workflow.NewRunner(wf, stepExec)
+	runner.SetFilePaths(specFiles)
+	runner.SetSpecFile(specFiles[0])
+	if len(specFiles) > 1 {
+		runner.SetContextFiles(specFiles[1:])
+	}
+	runner.SetNotesFile(opts.NotesFile)
+	runner.SetWorkingDir(opts.WorkingDir)
+	if opts.NotesFile != "" {
+		// Notes scaffolding is best effort; a failure leaves the prompt without
+		// recent notes but does not stop the run.
+		notes := spec.NewNotes(opts.NotesFile)
+		runner.SetStartCallback(func(info workflow.StepInfo) {
+			_ = notes.BeginSection(state.Iteration, info.Name, time.Now())
+			recent, _ := notes.Recent()
+			runner.SetRecentNotes(recent)
+		})
+	}
+	runner.SetCallback(func(info workflow.StepInfo, res *workflow.ExecutionResult, gate workflow.GateResult) error {
+		state.TotalCost += res.CostUSD
+		state.TotalTokensIn += res.TokensIn
+		state.TotalTokensOut += res.TokensOut
+		state.TotalTokens += res.TokensIn + res.TokensOut
+		state.LastOutput = res.Output
+
+		if opts.OnStep != nil {
+			event := StepEvent{
+				Iteration: state.Iteration,
+				Step:      info.Name,
+				Position:  info.Position,
+				Total:     info.Total,
+				Output:    res.Output,
+				Cost:      res.CostUSD,
+				TokensIn:  res.TokensIn,
+				TokensOut: res.TokensOut,
+				Scores:    info.Scores,
+			}
+			switch gate {
+			case workflow.GatePassed:
+				event.Gate = "PASS"
+			case workflow.GateFailed:
+				event.Gate = "FAIL"
+			}
+			opts.OnStep(event)
+		}
+		return nil
+	})
+
+	for iteration := 1; iteration <= cfg.MaxIterations; iteration++ {
+		// Stop starting iterations once only the verification reserve is left
+		if state.TotalCost >= cfg.LoopBudget() {
+			return finishLoop(state, ErrBudgetExceeded)
+		}
+
+		state.Iteration = iteration
+		if ctx.Err() != nil {
+			return finishLoop(state, ctx.Err())
+		}
+
+		suffix := ""
+		switch stagnation {
+		case loop.ProgressAbort:
+			return finishLoop(state, fmt.Errorf("%w: %d consecutive iterations without changes", ErrNoProgress, guard.Stagnant()))
+		case loop.ProgressEscalate:
+			suffix = guard.Escalation()
+		}
+		if injector != nil {
+			// A failed claim leaves the file for the next iteration
+			if instructions, err := injector.Take(); err == nil {
+				suffix += loop.FormatInjection(instructions)
+			}
+		}
+		runner.SetPromptSuffix(suffix)
+
+		runResult, err := runner.Run(ctx)
+		if err != nil {
+			if errors.Is(err, workflow.ErrStepTimedOut) || errors.Is(err, workflow.ErrMaxGateRetriesExceeded) {
+				continue
+			}
+			return finishLoop(state, err)
+		}
+
+		tree, _ := repo.TreeState(ctx)
+		stagnation = guard.Observe(runResult.Output(), tree)
+
+		// An iteration that ends in the reserve may still be verified; the
+		// next one is not started.
+		if state.TotalCost >= cfg.MaxBudget {
+			return finishLoop(state, ErrBudgetExceeded)
+		}
+
+		if !runResult.CompletedAllSteps && runResult.PromiseStep(cfg.CompletionPromise) == nil {
+			continue
+		}
+
+		verified, err := verifier.Verify(ctx, specFiles)
+		if verified != nil {
+			state.TotalCost += verified.Cost
+			state.TotalTokens += verified.Tokens
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return finishLoop(state, ctx.Err())
+			}
+			continue
+		}
+		if verified.Verified {
+			state.Completed = true
+			return finishLoop(state, nil)
+		}
+	}
+
+	return finishLoop(state, ErrMaxIterationsReached)
+}
+
+// finishLoop records err as the reason the loop ended and summarises state.
+func finishLoop(state *loop.LoopState, err error) (*Result, error) {
+	state.Error = err
+	return &Result{
+		Iterations: state.Iteration,
+		Completed:  state.Completed,
+		TotalCost:  state.TotalCost,
+		TokensIn:   state.TotalTokensIn,
+		TokensOut:  state.TotalTokensOut,
+		LastOutput: state.LastOutput,
+	}, err
+}
+
+// cliStepExecutor runs steps through the Claude Code CLI.
+type cliStepExecutor struct {
+	exec *executor.Executor
+}
+
+// ExecuteStep implements StepExecutor.
+func (e *cliStepExecutor) ExecuteStep(ctx context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+	res, err := e.exec.Execute(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("step %q execution failed: %w", stepName, err)
+	}
+	return &ExecutionResult{
+		StepName:  stepName,
+		Output:    res.Output,
+		CostUSD:   res.CostUSD,
+		TokensIn:  res.TokensIn,
+		TokensOut: res.TokensOut,
+	}, nil
+}
+
+// ExecuteStepOnModel implements workflow.ModelStepExecutor, running a step on
+// a workflow's escalation model.
+func (e *cliStepExecutor) ExecuteStepOnModel(ctx context.Context, stepName, prompt, model string) (*ExecutionResult, error) {
+	previous := e.exec.Model()
+	e.exec.SetModel(model)
+	defer e.exec.SetModel(previous)
+	return e.ExecuteStep(ctx, stepName, prompt)
+}
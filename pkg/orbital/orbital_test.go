@@ -0,0 +1,196 @@
+package orbital
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedExecutor returns canned outputs in order, repeating the last one.
+type scriptedExecutor struct {
+	outputs []string
+	cost    float64
+	calls   []string
+}
+
+func (e *scriptedExecutor) ExecuteStep(_ context.Context, stepName string, prompt string) (*ExecutionResult, error) {
+	e.calls = append(e.calls, stepName)
+	out := e.outputs[min(len(e.calls), len(e.outputs))-1]
+	return &ExecutionResult{StepName: stepName, Output: out, CostUSD: e.cost, TokensIn: 10, TokensOut: 5}, nil
+}
+
+// stubVerifier verifies once it has been called `after` times.
+type stubVerifier struct {
+	after int
+	calls int
+}
+
+func (v *stubVerifier) Verify(context.Context, []string) (*VerificationResult, error) {
+	v.calls++
+	return &VerificationResult{Verified: v.calls >= v.after, Cost: 0.01}, nil
+}
+
+func writeSpec(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.md")
+	if err := os.WriteFile(path, []byte("# Spec\n- [ ] Do the thing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func singleStep(t *testing.T) *Workflow {
+	t.Helper()
+	return &Workflow{Name: "single", Steps: []Step{{Name: "implement", Prompt: "Implement {{files}}"}}}
+}
+
+func TestRun_CompletesWhenVerified(t *testing.T) {
+	exec := &scriptedExecutor{outputs: []string{"working", DefaultCompletionPromise}, cost: 0.5}
+	verifier := &stubVerifier{after: 2}
+	var events []StepEvent
+
+	result, err := Run(context.Background(), Options{
+		SpecFiles: []string{writeSpec(t)},
+		Workflow:  singleStep(t),
+		Executor:  exec,
+		Verifier:  verifier,
+		OnStep:    func(e StepEvent) { events = append(events, e) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Completed {
+		t.Error("Completed = false, want true")
+	}
+	if result.Iterations != 2 {
+		t.Errorf("Iterations = %d, want 2", result.Iterations)
+	}
+	if want := 1.02; result.TotalCost < want-1e-9 || result.TotalCost > want+1e-9 {
+		t.Errorf("TotalCost = %v, want %v", result.TotalCost, want)
+	}
+	if result.TokensIn != 20 || result.TokensOut != 10 {
+		t.Errorf("tokens = %d/%d, want 20/10", result.TokensIn, result.TokensOut)
+	}
+	if len(events) != 2 || events[1].Iteration != 2 || events[1].Output != DefaultCompletionPromise {
+		t.Errorf("events = %+v, want one per iteration", events)
+	}
+}
+
+func TestRun_MaxIterations(t *testing.T) {
+	result, err := Run(context.Background(), Options{
+		SpecFiles:     []string{writeSpec(t)},
+		Workflow:      singleStep(t),
+		MaxIterations: 3,
+		Executor:      &scriptedExecutor{outputs: []string{DefaultCompletionPromise}},
+		Verifier:      &stubVerifier{after: 10},
+	})
+	if !errors.Is(err, ErrMaxIterationsReached) {
+		t.Fatalf("Run() error = %v, want ErrMaxIterationsReached", err)
+	}
+	if result.Iterations != 3 || result.Completed {
+		t.Errorf("result = %+v, want 3 incomplete iterations", result)
+	}
+}
+
+func TestRun_BudgetExceeded(t *testing.T) {
+	_, err := Run(context.Background(), Options{
+		SpecFiles: []string{writeSpec(t)},
+		Workflow:  singleStep(t),
+		MaxBudget: 1,
+		Executor:  &scriptedExecutor{outputs: []string{"working"}, cost: 0.6},
+		Verifier:  &stubVerifier{after: 10},
+	})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Run() error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestRun_BudgetExceededWhileGatesFail(t *testing.T) {
+	wf := &Workflow{
+		Name:           "gated",
+		Steps:          []Step{{Name: "review", Prompt: "Review {{files}}", Gate: true}},
+		MaxGateRetries: 1,
+	}
+	exec := &scriptedExecutor{outputs: []string{"<gate>FAIL</gate>"}, cost: 0.6}
+
+	result, err := Run(context.Background(), Options{
+		SpecFiles:     []string{writeSpec(t)},
+		Workflow:      wf,
+		MaxIterations: 10,
+		MaxBudget:     1,
+		Executor:      exec,
+		Verifier:      &stubVerifier{after: 10},
+	})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Run() error = %v, want ErrBudgetExceeded", err)
+	}
+	if len(exec.calls) != 2 || result.Iterations != 2 {
+		t.Errorf("calls, iterations = %d, %d, want the run to stop once spend reached the budget", len(exec.calls), result.Iterations)
+	}
+}
+
+func TestRun_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Run(ctx, Options{
+		SpecFiles: []string{writeSpec(t)},
+		Executor:  &scriptedExecutor{outputs: []string{"working"}},
+		Verifier:  &stubVerifier{},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRun_MissingSpec(t *testing.T) {
+	_, err := Run(context.Background(), Options{SpecFiles: []string{filepath.Join(t.TempDir(), "missing.md")}})
+	if err == nil || !strings.Contains(err.Error(), "failed to validate files") {
+		t.Errorf("Run() error = %v, want validation error", err)
+	}
+}
+
+func TestPreset(t *testing.T) {
+	wf, err := Preset("tdd")
+	if err != nil || wf.Name != "tdd" {
+		t.Errorf("Preset(tdd) = %v, %v", wf, err)
+	}
+	if _, err := Preset("nope"); err == nil {
+		t.Error("Preset(nope) error = nil, want error")
+	}
+}
+
+func TestWithStepTimeouts_DoesNotModifyWorkflow(t *testing.T) {
+	wf := singleStep(t)
+	got := withStepTimeouts(wf, time.Minute)
+
+	if wf.Steps[0].Timeout != 0 {
+		t.Errorf("original timeout = %v, want unchanged", wf.Steps[0].Timeout)
+	}
+	if got.Steps[0].Timeout.Duration() != time.Minute {
+		t.Errorf("timeout = %v, want 1m", got.Steps[0].Timeout.Duration())
+	}
+}
+
+func TestRun_NoProgress(t *testing.T) {
+	exec := &scriptedExecutor{outputs: []string{"stuck"}}
+	_, err := Run(context.Background(), Options{
+		SpecFiles:        []string{writeSpec(t)},
+		WorkingDir:       t.TempDir(),
+		Workflow:         singleStep(t),
+		NoProgressLimit:  2,
+		NoProgressAction: "abort",
+		Executor:         exec,
+		Verifier:         &stubVerifier{after: 100},
+	})
+	if !errors.Is(err, ErrNoProgress) {
+		t.Fatalf("Run() error = %v, want ErrNoProgress", err)
+	}
+	if len(exec.calls) != 3 {
+		t.Errorf("calls = %d, want 3", len(exec.calls))
+	}
+}